@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"golang.org/x/term"
+)
+
+// replHelp lista os comandos do modo interativo
+const replHelp = `Comandos:
+  /sources  mostra as fontes da última resposta
+  /reset    inicia uma nova sessão, descartando o contexto da conversa
+  /help     mostra esta ajuda
+  /exit     encerra (também com Ctrl+D ou Ctrl+C)`
+
+// lineReader lê as perguntas digitadas pelo usuário
+type lineReader interface {
+	ReadLine() (string, error)
+}
+
+// scanReader lê linhas quando a entrada não é um terminal (por exemplo, um
+// arquivo redirecionado), sem edição de linha nem histórico
+type scanReader struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+// ReadLine implementa lineReader
+func (r *scanReader) ReadLine() (string, error) {
+	fmt.Fprint(r.out, "> ")
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+// openTerminal prepara a leitura das perguntas. Em um terminal, usa edição
+// de linha com histórico (setas para cima e para baixo); restore devolve o
+// terminal ao estado original.
+func openTerminal() (lines lineReader, out io.Writer, restore func(), err error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return &scanReader{scanner: bufio.NewScanner(os.Stdin), out: os.Stdout}, os.Stdout, func() {}, nil
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("erro ao configurar o terminal: %w", err)
+	}
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "> ")
+	return terminal, terminal, func() { term.Restore(fd, state) }, nil
+}
+
+// repl mantém uma conversa com o agente, reaproveitando a mesma sessão para
+// que as perguntas seguintes usem o contexto das anteriores
+type repl struct {
+	service   domain.RAGService
+	out       io.Writer
+	sessionID string
+	groups    []string
+	language  string              // Idioma das respostas; vazio detecta pela pergunta
+	last      *domain.RAGResponse // Última resposta, usada por /sources
+}
+
+// runInteractive executa o modo interativo até o usuário encerrá-lo
+func runInteractive(ctx context.Context, service domain.RAGService, sessionID string, groups []string, language string) error {
+	lines, out, restore, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	// Com o contexto cancelado por um sinal, a leitura pendente é
+	// interrompida para encerrar o modo interativo
+	defer context.AfterFunc(ctx, func() { os.Stdin.Close() })()
+
+	r := &repl{service: service, out: out, sessionID: sessionID, groups: groups, language: language}
+	fmt.Fprintln(out, "Modo interativo. Digite /help para ver os comandos.")
+
+	for {
+		line, err := lines.ReadLine()
+		if errors.Is(err, io.EOF) || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao ler entrada: %w", err)
+		}
+
+		line = strings.TrimSpace(line)
+		switch line {
+		case "":
+			continue
+		case "/exit", "/quit":
+			return nil
+		case "/help":
+			fmt.Fprintln(out, replHelp)
+		case "/reset":
+			r.sessionID = ""
+			r.last = nil
+			fmt.Fprintln(out, "Nova sessão iniciada.")
+		case "/sources":
+			r.printSources()
+		default:
+			if strings.HasPrefix(line, "/") {
+				fmt.Fprintf(out, "Comando desconhecido: %s (digite /help)\n", line)
+				continue
+			}
+			r.ask(ctx, line)
+		}
+	}
+}
+
+// ask envia a pergunta ao agente, exibindo a resposta à medida que é gerada
+func (r *repl) ask(ctx context.Context, query string) {
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	resp, err := r.service.ProcessQueryStream(queryCtx, domain.RAGRequest{
+		Query:     query,
+		SessionID: r.sessionID,
+		Groups:    r.groups,
+		Language:  r.language,
+	}, func(token string) {
+		fmt.Fprint(r.out, token)
+	})
+	fmt.Fprintln(r.out)
+	if err != nil {
+		var timeout *domain.TimeoutResult
+		if errors.As(err, &timeout) {
+			printTimeoutResult(r.out, timeout)
+			return
+		}
+		fmt.Fprintf(r.out, "Erro ao processar pergunta: %v\n", err)
+		return
+	}
+
+	printCitations(r.out, resp.Citations)
+	r.last = resp
+	if resp.SessionID != "" {
+		r.sessionID = resp.SessionID
+	}
+}
+
+// printSources exibe as fontes consultadas na última resposta
+func (r *repl) printSources() {
+	switch {
+	case r.last == nil:
+		fmt.Fprintln(r.out, "Nenhuma pergunta respondida nesta sessão.")
+	case !r.last.UsedSearch || len(r.last.Sources) == 0:
+		fmt.Fprintln(r.out, "(resposta gerada sem busca na base)")
+	default:
+		printSources(r.out, r.last.Sources)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/alextavella/agentic-rag/internal/cache"
+	"github.com/alextavella/agentic-rag/internal/chunking"
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/infrastructure/llm"
+	"github.com/alextavella/agentic-rag/internal/ingest"
+	"github.com/alextavella/agentic-rag/internal/resilience"
+	"github.com/alextavella/agentic-rag/internal/stream"
+	"github.com/alextavella/agentic-rag/internal/webhook"
+)
+
+func main() {
+	tenant := flag.String("tenant", "", "tenant dos documentos sem tenant_id na mensagem")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Uso: consumer [opções]")
+		fmt.Fprintln(os.Stderr, "A fonte é configurada por STREAM_DRIVER (kafka ou nats) e pelas variáveis KAFKA_* ou NATS_*.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := domain.ValidateTenantID(*tenant); err != nil {
+		log.Fatalf("Tenant inválido: %v", err)
+	}
+
+	// Encerra ao receber SIGINT ou SIGTERM; o lote em andamento não é
+	// confirmado e volta a ser entregue na próxima execução
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = domain.WithTenant(ctx, *tenant)
+
+	streamConfig := stream.ConfigFromEnv()
+	source, err := stream.New(ctx, streamConfig)
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao stream: %v", err)
+	}
+	if source == nil {
+		log.Fatal("STREAM_DRIVER não definida")
+	}
+	defer source.Close()
+
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco de dados: %v", err)
+	}
+	defer db.Close(context.Background())
+
+	if err := db.SetupIndexes(ctx); err != nil {
+		log.Printf("Aviso ao configurar índices: %v", err)
+	}
+
+	// Documentos com link substituem os já indexados com o mesmo link, e as
+	// falhas de gravação são retornadas para que o lote seja repetido
+	splitter, err := chunking.NewSplitter(chunking.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro na configuração de chunking: %v", err)
+	}
+	opts := []ingest.Option{
+		ingest.WithSplitter(splitter),
+		ingest.WithReplaceByLink(db),
+		ingest.WithStrictBatches(),
+	}
+	if embedder := llm.NewEmbeddingClient(llm.ConfigFromEnv()); embedder != nil {
+		opts = append(opts, ingest.WithEmbeddingClient(resilience.NewRetryEmbeddingClient(embedder, resilience.RetryPolicyFromEnv())))
+	}
+	responseCache, err := cache.New(ctx, cache.ConfigFromEnv())
+	if err != nil {
+		log.Printf("Aviso ao conectar ao cache: %v", err)
+	} else if responseCache != nil {
+		defer responseCache.Close()
+		opts = append(opts, ingest.WithResponseCache(responseCache))
+	}
+	// Notifica os documentos gravados aos endpoints de WEBHOOK_URLS
+	dispatcher, err := webhook.New(webhook.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro na configuração dos webhooks: %v", err)
+	}
+	if dispatcher != nil {
+		defer dispatcher.Close()
+		opts = append(opts, ingest.WithEventPublisher(dispatcher))
+	}
+	// Registra os documentos gravados no log de auditoria (AUDIT_LOG=true)
+	audit, err := database.AuditFromEnv(db)
+	if err != nil {
+		log.Fatalf("Erro ao configurar auditoria: %v", err)
+	}
+	if audit != nil {
+		opts = append(opts, ingest.WithAuditLog(audit))
+	}
+
+	log.Printf("Consumindo documentos do %s", streamConfig.Driver)
+	consumer := stream.NewConsumer(source, ingest.New(db, opts...), streamConfig)
+	if err := consumer.Run(ctx); err != nil {
+		log.Fatalf("Erro no consumidor: %v", err)
+	}
+	log.Println("Consumidor encerrado")
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/app"
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/discord"
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+func main() {
+	addr := flag.String("addr", cmp.Or(os.Getenv("DISCORD_ADDR"), ":8091"), "endereço do endpoint de interações")
+	tenant := flag.String("tenant", "", "tenant cujos documentos e conversas são usados")
+	register := flag.Bool("register", false, "registra o comando /ask na aplicação antes de iniciar")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Uso: discord [opções]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := domain.ValidateTenantID(*tenant); err != nil {
+		log.Fatalf("Tenant inválido: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = domain.WithTenant(ctx, *tenant)
+
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco de dados: %v", err)
+	}
+	defer db.Close(context.Background())
+
+	rag, err := app.New(ctx, db)
+	if err != nil {
+		log.Fatalf("Erro ao configurar o serviço: %v", err)
+	}
+	// Espera as tarefas em segundo plano do serviço antes de fechar o banco
+	defer rag.Close(context.Background())
+
+	bot, err := discord.New(rag.Service, discord.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro na configuração do Discord: %v", err)
+	}
+	if *register {
+		if err := bot.RegisterCommands(ctx); err != nil {
+			log.Fatalf("Erro ao registrar comandos: %v", err)
+		}
+		log.Println("Comando /ask registrado")
+	}
+
+	// As requisições herdam o tenant do contexto base
+	mux := http.NewServeMux()
+	mux.Handle("POST /interactions", bot)
+	httpServer := &http.Server{
+		Addr:        *addr,
+		Handler:     mux,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	// ListenAndServe retorna assim que Shutdown começa; as requisições em
+	// andamento são esperadas antes de fechar o banco
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Aviso: requisições interrompidas no encerramento: %v", err)
+		}
+	}()
+	log.Printf("Endpoint de interações do Discord em %s/interactions", *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Erro no servidor: %v", err)
+	}
+	<-shutdownDone
+	// Conclui as respostas em andamento antes de fechar o banco
+	bot.Wait()
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Resultado de uma pergunta na comparação, do ponto de vista da candidata
+const (
+	outcomeWin  = "win"
+	outcomeLoss = "loss"
+	outcomeTie  = "tie"
+)
+
+// comparison é o relatório da comparação entre duas configurações
+type comparison struct {
+	Baseline  summary `json:"baseline"`
+	Candidate summary `json:"candidate"`
+	// Wins, Losses e Ties contam as perguntas em que a candidata foi melhor,
+	// pior ou equivalente à configuração de referência
+	Wins      int            `json:"wins"`
+	Losses    int            `json:"losses"`
+	Ties      int            `json:"ties"`
+	Questions []questionDiff `json:"questions"`
+}
+
+// questionDiff compara as avaliações de uma pergunta nas duas configurações
+type questionDiff struct {
+	ID       string `json:"id"`
+	Question string `json:"question"`
+	// Notas de 0 a 1 (média das métricas do caso); nil quando o caso não
+	// tem métricas, e a pergunta conta como empate
+	BaselineScore  *float64 `json:"baseline_score,omitempty"`
+	CandidateScore *float64 `json:"candidate_score,omitempty"`
+	Delta          float64  `json:"delta"`
+	Outcome        string   `json:"outcome"`
+	Baseline       result   `json:"baseline_result"`
+	Candidate      result   `json:"candidate_result"`
+}
+
+// compareConfigs responde o dataset com a configuração de referência (o
+// ambiente, com as variáveis de baselineEnv, se informado) e com a candidata
+// (o ambiente com as variáveis de candidateEnv) e compara as perguntas
+// respondidas nas duas
+func compareConfigs(ctx context.Context, cases []evalCase, baselineEnv, candidateEnv string, margin float64, useJudge bool, timeout time.Duration) comparison {
+	var baseline, candidate []result
+	var c comparison
+
+	log.Printf("Avaliando a configuração de referência")
+	err := withEnvFile(baselineEnv, func() {
+		baseline, c.Baseline = runDataset(ctx, cases, useJudge, timeout)
+	})
+	if err != nil {
+		log.Fatalf("Erro ao ler a configuração de referência: %v", err)
+	}
+	log.Printf("Avaliando a configuração de %s", candidateEnv)
+	err = withEnvFile(candidateEnv, func() {
+		candidate, c.Candidate = runDataset(ctx, cases, useJudge, timeout)
+	})
+	if err != nil {
+		log.Fatalf("Erro ao ler a configuração comparada: %v", err)
+	}
+
+	c.Questions = make([]questionDiff, 0, len(cases))
+	for i := range min(len(baseline), len(candidate)) {
+		d := compareResults(baseline[i], candidate[i], margin)
+		switch d.Outcome {
+		case outcomeWin:
+			c.Wins++
+		case outcomeLoss:
+			c.Losses++
+		default:
+			c.Ties++
+		}
+		c.Questions = append(c.Questions, d)
+	}
+	log.Printf("Candidata: %d vitórias, %d derrotas, %d empates", c.Wins, c.Losses, c.Ties)
+	return c
+}
+
+// compareResults compara as notas de uma pergunta nas duas configurações.
+// Diferenças menores que margin são empates.
+func compareResults(baseline, candidate result, margin float64) questionDiff {
+	d := questionDiff{
+		ID:             baseline.ID,
+		Question:       baseline.Question,
+		BaselineScore:  score(baseline),
+		CandidateScore: score(candidate),
+		Outcome:        outcomeTie,
+		Baseline:       baseline,
+		Candidate:      candidate,
+	}
+	if d.BaselineScore == nil || d.CandidateScore == nil {
+		return d
+	}
+	d.Delta = *d.CandidateScore - *d.BaselineScore
+	switch {
+	case d.Delta >= margin:
+		d.Outcome = outcomeWin
+	case d.Delta <= -margin:
+		d.Outcome = outcomeLoss
+	}
+	return d
+}
+
+// score é a média das métricas avaliadas na pergunta: recall das fontes,
+// similaridade com a resposta esperada e, com --judge, as notas do juiz.
+// Perguntas que falharam recebem nota zero; sem métricas, retorna nil.
+func score(r result) *float64 {
+	if r.Error != "" {
+		zero := 0.0
+		return &zero
+	}
+	var values []float64
+	for _, v := range []*float64{r.RetrievalRecall, r.AnswerSimilarity} {
+		if v != nil {
+			values = append(values, *v)
+		}
+	}
+	if r.Quality != nil {
+		for _, v := range []*float64{r.Quality.Faithfulness, r.Quality.ContextRelevance} {
+			if v != nil {
+				values = append(values, *v)
+			}
+		}
+		values = append(values, r.Quality.AnswerRelevance)
+	}
+	return mean(values)
+}
+
+// withEnvFile executa fn com as variáveis do arquivo definidas no ambiente,
+// restaurando os valores anteriores em seguida. Sem arquivo, executa fn com
+// o ambiente atual.
+func withEnvFile(path string, fn func()) error {
+	if path == "" {
+		fn()
+		return nil
+	}
+	vars, err := readEnvFile(path)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range vars {
+		previous, ok := os.LookupEnv(key)
+		os.Setenv(key, value)
+		if ok {
+			defer os.Setenv(key, previous)
+		} else {
+			defer os.Unsetenv(key)
+		}
+	}
+	fn()
+	return nil
+}
+
+// readEnvFile lê um arquivo no formato do .env: uma variável CHAVE=valor por
+// linha, com linhas em branco e comentários (#) ignorados, o prefixo export
+// opcional e aspas ao redor do valor removidas
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	vars := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(text, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("linha %d: esperado CHAVE=valor", line)
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		vars[key] = value
+	}
+	return vars, scanner.Err()
+}
+
+// comparisonHeader são as colunas da comparação em CSV
+var comparisonHeader = []string{
+	"id", "question", "baseline_score", "candidate_score", "delta", "outcome",
+	"baseline_latency_ms", "candidate_latency_ms", "baseline_cost_usd", "candidate_cost_usd",
+	"baseline_error", "candidate_error",
+}
+
+// writeComparisonCSV grava uma linha por pergunta; o resumo fica apenas no log
+func writeComparisonCSV(w io.Writer, questions []questionDiff) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(comparisonHeader); err != nil {
+		return err
+	}
+	for _, d := range questions {
+		record := []string{
+			d.ID,
+			d.Question,
+			formatFloat(d.BaselineScore),
+			formatFloat(d.CandidateScore),
+			strconv.FormatFloat(d.Delta, 'f', 4, 64),
+			d.Outcome,
+			strconv.FormatInt(d.Baseline.LatencyMS, 10),
+			strconv.FormatInt(d.Candidate.LatencyMS, 10),
+			strconv.FormatFloat(d.Baseline.CostUSD, 'f', 6, 64),
+			strconv.FormatFloat(d.Candidate.CostUSD, 'f', 6, 64),
+			d.Baseline.Error,
+			d.Candidate.Error,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
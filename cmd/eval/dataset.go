@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// evalCase é uma pergunta do dataset de referência
+type evalCase struct {
+	ID       string `json:"id,omitempty"`
+	Question string `json:"question"`
+	// ExpectedSources identifica os documentos que a busca deveria encontrar,
+	// pelo ID, ParentID, link ou título
+	ExpectedSources []string `json:"expected_sources,omitempty"`
+	// ExpectedAnswer é a resposta de referência, comparada à resposta gerada
+	ExpectedAnswer string `json:"expected_answer,omitempty"`
+
+	// Filtros das buscas, como em domain.RAGRequest
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// readDataset lê o dataset em JSONL, um caso por linha. Linhas em branco são
+// ignoradas; casos sem ID recebem o número da linha.
+func readDataset(path string) ([]evalCase, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cases []evalCase
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var c evalCase
+		if err := json.Unmarshal([]byte(text), &c); err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line, err)
+		}
+		if strings.TrimSpace(c.Question) == "" {
+			return nil, fmt.Errorf("linha %d: campo 'question' obrigatório", line)
+		}
+		if c.ID == "" {
+			c.ID = fmt.Sprint(line)
+		}
+		cases = append(cases, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+	"unicode"
+
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/evaluation"
+	"github.com/alextavella/agentic-rag/internal/infrastructure/llm"
+	"github.com/alextavella/agentic-rag/internal/pricing"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+	"github.com/alextavella/agentic-rag/internal/rerank"
+	"github.com/alextavella/agentic-rag/internal/routing"
+	"github.com/alextavella/agentic-rag/internal/service"
+	"github.com/alextavella/agentic-rag/internal/tokenizer"
+	"github.com/alextavella/agentic-rag/internal/vector"
+)
+
+// Formatos do relatório
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+)
+
+func main() {
+	out := flag.String("out", "-", "arquivo do relatório (.json ou .csv); - para a saída padrão")
+	format := flag.String("format", "", "formato do relatório: json ou csv (padrão: pela extensão, ou json)")
+	tenant := flag.String("tenant", "", "tenant cujos documentos são consultados")
+	timeout := flag.Duration("timeout", 2*time.Minute, "tempo máximo de cada pergunta")
+	useJudge := flag.Bool("judge", false, "avalia também faithfulness, context relevance e answer relevance com o LLM")
+	baselineEnv := flag.String("baseline", "", "arquivo de variáveis de ambiente da configuração de referência, com --compare")
+	candidateEnv := flag.String("compare", "", "arquivo de variáveis de ambiente da configuração comparada à de referência")
+	margin := flag.Float64("margin", 0.05, "diferença de nota abaixo da qual a comparação de uma pergunta é empate")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Uso: eval [opções] <dataset.jsonl>")
+		fmt.Fprintln(os.Stderr, "     eval --compare candidata.env [--baseline referencia.env] [opções] <dataset.jsonl>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := domain.ValidateTenantID(*tenant); err != nil {
+		log.Fatalf("Tenant inválido: %v", err)
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*out), ".")
+		if *format != formatCSV {
+			*format = formatJSON
+		}
+	}
+	if *format != formatJSON && *format != formatCSV {
+		log.Fatalf("Formato desconhecido: %q", *format)
+	}
+	if *baselineEnv != "" && *candidateEnv == "" {
+		log.Fatalf("--baseline exige --compare")
+	}
+
+	cases, err := readDataset(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("Erro ao ler o dataset: %v", err)
+	}
+
+	// SIGINT e SIGTERM interrompem a avaliação; o relatório é gravado com as
+	// perguntas já respondidas
+	ctx, stop := signal.NotifyContext(domain.WithTenant(context.Background(), *tenant), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *candidateEnv != "" {
+		c := compareConfigs(ctx, cases, *baselineEnv, *candidateEnv, *margin, *useJudge, *timeout)
+		err = writeTo(*out, func(w io.Writer) error {
+			if *format == formatCSV {
+				return writeComparisonCSV(w, c.Questions)
+			}
+			return writeJSON(w, c)
+		})
+		if err != nil {
+			log.Fatalf("Erro ao gravar o relatório: %v", err)
+		}
+		return
+	}
+
+	results, s := runDataset(ctx, cases, *useJudge, *timeout)
+	err = writeTo(*out, func(w io.Writer) error {
+		if *format == formatCSV {
+			return writeCSV(w, results)
+		}
+		return writeJSON(w, report{Summary: s, Results: results})
+	})
+	if err != nil {
+		log.Fatalf("Erro ao gravar o relatório: %v", err)
+	}
+}
+
+// runDataset monta o serviço com a configuração do ambiente, responde as
+// perguntas do dataset uma de cada vez e registra o resumo no log
+func runDataset(ctx context.Context, cases []evalCase, useJudge bool, timeout time.Duration) ([]result, summary) {
+	ragService, judge, embedder, closeDB := newService(ctx)
+	defer closeDB()
+
+	method := "lexical"
+	if embedder != nil {
+		method = "embedding"
+	}
+	e := &evaluator{service: ragService, embedder: embedder, timeout: timeout}
+	if useJudge {
+		e.judge = judge
+	}
+	results := make([]result, 0, len(cases))
+	for i, c := range cases {
+		if ctx.Err() != nil {
+			log.Printf("Avaliação interrompida: %d de %d perguntas respondidas", len(results), len(cases))
+			break
+		}
+		r := e.evaluate(ctx, c)
+		if ctx.Err() != nil {
+			// A pergunta interrompida fica fora do relatório
+			continue
+		}
+		log.Printf("[%d/%d] %s: %d ms, US$ %.4f %s", i+1, len(cases), c.ID, r.LatencyMS, r.CostUSD, r.Error)
+		results = append(results, r)
+	}
+
+	s := summarize(results, method)
+	log.Printf("%d perguntas, %d erros, hit rate %.2f, similaridade média %.2f (%s), latência média %d ms (p95 %d ms), custo US$ %.4f por pergunta",
+		s.Questions, s.Errors, s.HitRate, s.MeanSimilarity, s.SimilarityMethod, s.MeanLatencyMS, s.P95LatencyMS, s.CostPerQuestion)
+	return results, s
+}
+
+// newService monta o serviço com a configuração do ambiente, como o comando
+// api, mas sem caches nem histórico, para que cada pergunta seja respondida
+// pelo agente. Retorna também o juiz de qualidade, com o mesmo LLM e prompts,
+// e o cliente de embeddings, usado na similaridade.
+func newService(ctx context.Context) (domain.RAGService, *evaluation.Judge, domain.EmbeddingClient, func()) {
+	llmConfig := llm.ConfigFromEnv()
+	client, err := llm.New(llmConfig)
+	if err != nil {
+		log.Fatalf("Erro ao criar cliente de LLM: %v", err)
+	}
+	// Mede o consumo de tokens de todas as chamadas (agente e reranker)
+	client = pricing.NewLLMClient(client)
+
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco de dados: %v", err)
+	}
+
+	prices, err := pricing.TableFromEnv()
+	if err != nil {
+		log.Fatalf("Erro ao carregar tabela de preços: %v", err)
+	}
+	windows, err := tokenizer.ContextWindowsFromEnv()
+	if err != nil {
+		log.Fatalf("Erro ao carregar janelas de contexto: %v", err)
+	}
+	promptSet, err := prompts.New(prompts.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro ao carregar prompts: %v", err)
+	}
+
+	opts := []service.Option{
+		service.WithConfig(service.ConfigFromEnv()),
+		service.WithPriceTable(prices),
+		service.WithPrompts(promptSet),
+		service.WithTokenizer(tokenizer.New(llmConfig.Model())),
+	}
+	if window, ok := windows.Lookup(llmConfig.Model()); ok {
+		opts = append(opts, service.WithContextWindow(window))
+	}
+	embedder := llm.NewEmbeddingClient(llmConfig)
+	if embedder != nil {
+		opts = append(opts, service.WithEmbeddingClient(embedder))
+	}
+	reranker, err := rerank.New(rerank.ConfigFromEnv(), client)
+	if err != nil {
+		log.Fatalf("Erro ao criar reranker: %v", err)
+	}
+	if reranker != nil {
+		opts = append(opts, service.WithReranker(reranker))
+	}
+	// Escolhe o modelo de cada pergunta pela complexidade, quando configurado em ROUTER_SMALL_MODEL e ROUTER_LARGE_MODEL
+	router, err := routing.New(routing.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro ao criar roteador de modelos: %v", err)
+	}
+	if router != nil {
+		opts = append(opts, service.WithModelRouter(router))
+	}
+
+	closeDB := func() { db.Close(context.Background()) }
+	return service.NewRAGService(client, db, opts...), evaluation.NewJudge(client, promptSet), embedder, closeDB
+}
+
+// evaluator responde as perguntas do dataset e calcula as métricas de cada uma
+type evaluator struct {
+	service  domain.RAGService
+	embedder domain.EmbeddingClient // Opcional: similaridade pelos embeddings
+	judge    *evaluation.Judge      // Opcional: notas de qualidade dadas pelo LLM
+	timeout  time.Duration
+}
+
+// evaluate responde a pergunta e a compara ao caso de referência
+func (e *evaluator) evaluate(ctx context.Context, c evalCase) result {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := e.service.ProcessQuery(ctx, domain.RAGRequest{
+		Query:    c.Question,
+		Category: c.Category,
+		Tags:     c.Tags,
+	})
+	r := result{ID: c.ID, Question: c.Question, LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		r.Error = err.Error()
+		resp = &domain.RAGResponse{}
+	}
+	r.Answer = resp.Answer
+	r.Tokens = resp.Usage.Total()
+	r.CostUSD = resp.CostUSD
+
+	if len(c.ExpectedSources) > 0 {
+		recall := sourceRecall(c.ExpectedSources, resp.Sources)
+		hit := recall > 0
+		r.RetrievalHit, r.RetrievalRecall = &hit, &recall
+	}
+	if c.ExpectedAnswer != "" {
+		similarity := 0.0
+		if err == nil {
+			similarity = e.similarity(ctx, c.ExpectedAnswer, resp.Answer)
+		}
+		r.AnswerSimilarity = &similarity
+	}
+	if e.judge != nil && err == nil {
+		scores, err := e.judge.Score(ctx, c.Question, resp.Answer, resp.Sources)
+		if err != nil {
+			log.Printf("Aviso ao avaliar %s: %v", c.ID, err)
+		} else {
+			r.Quality = scores
+		}
+	}
+	return r
+}
+
+// similarity compara a resposta à de referência pelo cosseno dos embeddings
+// ou, sem embeddings, pelo F1 das palavras
+func (e *evaluator) similarity(ctx context.Context, expected, answer string) float64 {
+	if e.embedder == nil {
+		return lexicalSimilarity(expected, answer)
+	}
+	vectors, err := e.embedder.Embed(ctx, []string{expected, answer})
+	if err != nil || len(vectors) != 2 {
+		log.Printf("Aviso ao gerar embeddings, usando similaridade lexical: %v", err)
+		return lexicalSimilarity(expected, answer)
+	}
+	return max(vector.Cosine(vectors[0], vectors[1]), 0)
+}
+
+// sourceRecall retorna a fração das fontes esperadas entre as recuperadas.
+// Uma fonte esperada corresponde a um documento pelo ID, ParentID, link ou
+// título, sem diferença de maiúsculas.
+func sourceRecall(expected []string, sources []domain.Document) float64 {
+	found := 0
+	for _, want := range expected {
+		want = strings.TrimSpace(want)
+		for _, doc := range sources {
+			if matchesSource(want, doc) {
+				found++
+				break
+			}
+		}
+	}
+	return float64(found) / float64(len(expected))
+}
+
+// matchesSource indica se o documento é a fonte identificada por ref
+func matchesSource(ref string, doc domain.Document) bool {
+	for _, id := range []string{doc.ID, doc.ParentID, doc.Link, doc.Title} {
+		if id != "" && strings.EqualFold(ref, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// lexicalSimilarity calcula o F1 entre as palavras das duas respostas
+func lexicalSimilarity(expected, answer string) float64 {
+	want := words(expected)
+	got := words(answer)
+	if len(want) == 0 || len(got) == 0 {
+		return 0
+	}
+
+	counts := map[string]int{}
+	for _, w := range want {
+		counts[w]++
+	}
+	common := 0
+	for _, w := range got {
+		if counts[w] > 0 {
+			counts[w]--
+			common++
+		}
+	}
+	if common == 0 {
+		return 0
+	}
+	precision := float64(common) / float64(len(got))
+	recall := float64(common) / float64(len(want))
+	return 2 * precision * recall / (precision + recall)
+}
+
+// words divide o texto em palavras minúsculas, sem pontuação
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// writeTo abre o arquivo do relatório, ou a saída padrão com "-", e grava nele
+func writeTo(path string, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// result é a avaliação de uma pergunta do dataset
+type result struct {
+	ID       string `json:"id"`
+	Question string `json:"question"`
+	Answer   string `json:"answer,omitempty"`
+	// RetrievalHit indica se ao menos uma fonte esperada foi recuperada; nil
+	// quando o caso não informa fontes
+	RetrievalHit *bool `json:"retrieval_hit,omitempty"`
+	// RetrievalRecall é a fração das fontes esperadas que foram recuperadas
+	RetrievalRecall *float64 `json:"retrieval_recall,omitempty"`
+	// AnswerSimilarity compara a resposta à de referência (0 a 1); nil
+	// quando o caso não informa a resposta
+	AnswerSimilarity *float64 `json:"answer_similarity,omitempty"`
+	// Quality são as notas do LLM juiz, com --judge
+	Quality   *domain.QualityScores `json:"quality,omitempty"`
+	LatencyMS int64                 `json:"latency_ms"`
+	Tokens    int                   `json:"tokens"`
+	CostUSD   float64               `json:"cost_usd"` // Custo da resposta, sem o do juiz
+	Error     string                `json:"error,omitempty"`
+}
+
+// summary agrega os resultados do dataset
+type summary struct {
+	Questions int `json:"questions"`
+	Errors    int `json:"errors"`
+	// HitRate é a fração dos casos com fontes esperadas em que alguma foi recuperada
+	HitRate float64 `json:"hit_rate"`
+	// MeanRecall é a média de RetrievalRecall nos casos com fontes esperadas
+	MeanRecall float64 `json:"mean_recall"`
+	// MeanSimilarity é a média de AnswerSimilarity nos casos com resposta de referência
+	MeanSimilarity float64 `json:"mean_similarity"`
+	// SimilarityMethod é "embedding" (cosseno) ou "lexical" (F1 das palavras)
+	SimilarityMethod string `json:"similarity_method"`
+	// Médias das notas do LLM juiz, com --judge
+	MeanFaithfulness     *float64 `json:"mean_faithfulness,omitempty"`
+	MeanContextRelevance *float64 `json:"mean_context_relevance,omitempty"`
+	MeanAnswerRelevance  *float64 `json:"mean_answer_relevance,omitempty"`
+	MeanLatencyMS        int64    `json:"mean_latency_ms"`
+	P95LatencyMS         int64    `json:"p95_latency_ms"`
+	TotalCostUSD         float64  `json:"total_cost_usd"`
+	CostPerQuestion      float64  `json:"cost_per_question_usd"`
+}
+
+// report é o relatório completo da avaliação
+type report struct {
+	Summary summary  `json:"summary"`
+	Results []result `json:"results"`
+}
+
+// summarize calcula as métricas agregadas. Perguntas que falharam entram
+// nas médias sem fontes recuperadas e com similaridade zero.
+func summarize(results []result, method string) summary {
+	s := summary{Questions: len(results), SimilarityMethod: method}
+
+	var hits, withSources, withAnswer int
+	var latencies []time.Duration
+	var faithfulness, contextRelevance, answerRelevance []float64
+	for _, r := range results {
+		if r.Quality != nil {
+			if r.Quality.Faithfulness != nil {
+				faithfulness = append(faithfulness, *r.Quality.Faithfulness)
+				contextRelevance = append(contextRelevance, *r.Quality.ContextRelevance)
+			}
+			answerRelevance = append(answerRelevance, r.Quality.AnswerRelevance)
+		}
+		if r.Error != "" {
+			s.Errors++
+		}
+		if r.RetrievalHit != nil {
+			withSources++
+			if *r.RetrievalHit {
+				hits++
+			}
+			s.MeanRecall += *r.RetrievalRecall
+		}
+		if r.AnswerSimilarity != nil {
+			withAnswer++
+			s.MeanSimilarity += *r.AnswerSimilarity
+		}
+		latencies = append(latencies, time.Duration(r.LatencyMS)*time.Millisecond)
+		s.TotalCostUSD += r.CostUSD
+	}
+
+	if withSources > 0 {
+		s.HitRate = float64(hits) / float64(withSources)
+		s.MeanRecall /= float64(withSources)
+	}
+	if withAnswer > 0 {
+		s.MeanSimilarity /= float64(withAnswer)
+	}
+	s.MeanFaithfulness = mean(faithfulness)
+	s.MeanContextRelevance = mean(contextRelevance)
+	s.MeanAnswerRelevance = mean(answerRelevance)
+	if len(results) > 0 {
+		var total time.Duration
+		for _, l := range latencies {
+			total += l
+		}
+		s.MeanLatencyMS = (total / time.Duration(len(latencies))).Milliseconds()
+		slices.Sort(latencies)
+		s.P95LatencyMS = latencies[(len(latencies)*95+99)/100-1].Milliseconds()
+		s.CostPerQuestion = s.TotalCostUSD / float64(len(results))
+	}
+	return s
+}
+
+// mean retorna a média dos valores, ou nil sem valores
+func mean(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	avg := total / float64(len(values))
+	return &avg
+}
+
+// writeJSON grava o relatório completo em JSON
+func writeJSON(w io.Writer, r any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// csvHeader são as colunas do relatório em CSV
+var csvHeader = []string{
+	"id", "question", "retrieval_hit", "retrieval_recall", "answer_similarity",
+	"faithfulness", "context_relevance", "answer_relevance", "latency_ms", "tokens", "cost_usd", "error",
+}
+
+// writeCSV grava uma linha por pergunta; o resumo fica apenas no log.
+// Métricas que não se aplicam ao caso ficam vazias.
+func writeCSV(w io.Writer, results []result) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range results {
+		var quality domain.QualityScores
+		if r.Quality != nil {
+			quality = *r.Quality
+		}
+		record := []string{
+			r.ID,
+			r.Question,
+			formatBool(r.RetrievalHit),
+			formatFloat(r.RetrievalRecall),
+			formatFloat(r.AnswerSimilarity),
+			formatFloat(quality.Faithfulness),
+			formatFloat(quality.ContextRelevance),
+			formatFloat(optional(r.Quality != nil, quality.AnswerRelevance)),
+			strconv.FormatInt(r.LatencyMS, 10),
+			strconv.Itoa(r.Tokens),
+			strconv.FormatFloat(r.CostUSD, 'f', 6, 64),
+			r.Error,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatBool formata um valor opcional, vazio quando ausente
+func formatBool(v *bool) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatBool(*v)
+}
+
+// optional retorna o endereço de v quando ok, ou nil
+func optional(ok bool, v float64) *float64 {
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+// formatFloat formata um valor opcional, vazio quando ausente
+func formatFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 4, 64)
+}
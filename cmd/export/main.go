@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/backup"
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// dateLayout é o formato aceito nos filtros de data, além do RFC 3339
+const dateLayout = "2006-01-02"
+
+func main() {
+	out := flag.String("out", "-", "arquivo de documentos (.jsonl ou .bson); - para a saída padrão")
+	conversations := flag.String("conversations", "", "arquivo para exportar também as conversas")
+	format := flag.String("format", "", "formato dos arquivos: jsonl ou bson (padrão: pela extensão)")
+	category := flag.String("category", "", "exporta apenas documentos desta categoria")
+	since := flag.String("since", "", "exporta registros criados a partir desta data (AAAA-MM-DD ou RFC 3339)")
+	until := flag.String("until", "", "exporta registros criados antes desta data (AAAA-MM-DD ou RFC 3339)")
+	timeout := flag.Duration("timeout", time.Hour, "tempo máximo da exportação")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Uso: export [opções]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	filter := database.ExportFilter{Category: *category}
+	var err error
+	if filter.Since, err = parseDate(*since); err != nil {
+		log.Fatalf("Data inválida em --since: %v", err)
+	}
+	if filter.Until, err = parseDate(*until); err != nil {
+		log.Fatalf("Data inválida em --until: %v", err)
+	}
+
+	// SIGINT e SIGTERM interrompem a exportação e fecham a conexão
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	if err := export(ctx, filter, *out, *conversations, *format); err != nil {
+		log.Fatalf("Exportação interrompida: %v", err)
+	}
+}
+
+// export grava os documentos em out e, se informado, as conversas em
+// conversations
+func export(ctx context.Context, filter database.ExportFilter, out, conversations, format string) error {
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		return fmt.Errorf("erro ao conectar ao banco de dados: %w", err)
+	}
+	defer db.Close(context.Background())
+
+	docs := 0
+	err = exportTo(out, format, func(w *backup.Writer) error {
+		return db.ExportDocuments(ctx, filter, func(doc domain.Document) error {
+			docs++
+			return w.WriteDocument(doc)
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao exportar documentos: %w", err)
+	}
+	log.Printf("%d documentos exportados", docs)
+
+	if conversations == "" {
+		return nil
+	}
+	if db.Conversations() == nil {
+		return errors.New("o banco configurado não guarda conversas")
+	}
+	convs := 0
+	err = exportTo(conversations, format, func(w *backup.Writer) error {
+		return db.ExportConversations(ctx, filter, func(conv domain.Conversation) error {
+			convs++
+			return w.WriteConversation(conv)
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao exportar conversas: %w", err)
+	}
+	log.Printf("%d conversas exportadas", convs)
+	return nil
+}
+
+// exportTo cria o arquivo (ou usa a saída padrão) e grava nele os registros
+// produzidos por export
+func exportTo(path, format string, export func(*backup.Writer) error) error {
+	if format == "" {
+		format = backup.FormatFromPath(path)
+	}
+
+	var dest io.Writer = os.Stdout
+	var file *os.File
+	if path != "-" {
+		var err error
+		if file, err = os.Create(path); err != nil {
+			return fmt.Errorf("erro ao criar arquivo: %w", err)
+		}
+		defer file.Close()
+		dest = file
+	}
+
+	writer, err := backup.NewWriter(dest, format)
+	if err != nil {
+		return err
+	}
+	if err := export(writer); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("erro ao gravar arquivo: %w", err)
+	}
+	if file != nil {
+		return file.Close()
+	}
+	return nil
+}
+
+// parseDate interpreta uma data em AAAA-MM-DD ou RFC 3339; vazia não filtra
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/cache"
+	"github.com/alextavella/agentic-rag/internal/chunking"
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/infrastructure/llm"
+	"github.com/alextavella/agentic-rag/internal/ingest"
+	"github.com/alextavella/agentic-rag/internal/loader"
+	"github.com/alextavella/agentic-rag/internal/webhook"
+)
+
+// importStats resume o resultado da importação
+type importStats struct {
+	rows     int // Registros lidos
+	imported int // Registros inseridos
+	failed   int // Registros inválidos ou que falharam na inserção
+}
+
+func main() {
+	format := flag.String("format", "", "formato do arquivo: csv ou jsonl, ou jsonl ou bson com --restore (padrão: pela extensão)")
+	batchSize := flag.Int("batch-size", 100, "registros inseridos por lote")
+	concurrency := flag.Int("concurrency", ingest.DefaultWorkers, "lotes inseridos ao mesmo tempo")
+	timeout := flag.Duration("timeout", time.Hour, "tempo máximo da importação")
+	restore := flag.Bool("restore", false, "restaura um arquivo gerado pelo comando export, mantendo IDs e embeddings")
+	conversations := flag.String("conversations", "", "com --restore, arquivo de conversas gerado pelo export")
+	tenant := flag.String("tenant", "", "tenant dos documentos importados (com --restore, vale o tenant gravado no arquivo)")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Uso: import [opções] <arquivo.csv|arquivo.jsonl>")
+		fmt.Fprintln(os.Stderr, "     import --restore [--conversations conversas.jsonl] <documentos.jsonl|documentos.bson>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || *batchSize <= 0 || *concurrency <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := domain.ValidateTenantID(*tenant); err != nil {
+		log.Fatalf("Tenant inválido: %v", err)
+	}
+
+	// SIGINT e SIGTERM interrompem a importação, que ainda espera os lotes
+	// em andamento e fecha as conexões
+	path := flag.Arg(0)
+	ctx, stop := signal.NotifyContext(domain.WithTenant(context.Background(), *tenant), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	if *restore {
+		if err := restoreBackup(ctx, path, *conversations, *format, *batchSize); err != nil {
+			log.Fatalf("Restauração interrompida: %v", err)
+		}
+		return
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	if err := importFile(ctx, path, *format, *batchSize, *concurrency); err != nil {
+		log.Fatalf("Importação interrompida: %v", err)
+	}
+}
+
+// importFile importa os registros do arquivo, fechando o arquivo e as
+// conexões antes de retornar
+func importFile(ctx context.Context, path, format string, batchSize, concurrency int) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir arquivo: %w", err)
+	}
+	defer file.Close()
+
+	records, err := loader.NewRecordReader(file, format)
+	if err != nil {
+		return fmt.Errorf("erro ao ler arquivo: %w", err)
+	}
+
+	ingester, closeIngester, err := newIngester(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao preparar importação: %w", err)
+	}
+	defer closeIngester()
+
+	stats, err := importRecords(ctx, ingest.NewPool(ingester, concurrency), records, batchSize)
+	log.Printf("Importação concluída: %d registros lidos, %d importados, %d com falha", stats.rows, stats.imported, stats.failed)
+	return err
+}
+
+// importRecords lê os registros e os insere em lotes, gravados em paralelo
+// pelo pool enquanto a leitura continua. Registros inválidos e lotes com
+// falha são reportados sem interromper a importação; apenas erros de
+// leitura do arquivo ou o fim do prazo a encerram, depois de esperar os
+// lotes já enviados.
+func importRecords(ctx context.Context, pool *ingest.Pool, records loader.RecordReader, batchSize int) (importStats, error) {
+	var stats importStats
+	invalid, failed := 0, 0 // Atualizados pela leitura e pelos lotes, respectivamente
+	batch := make([]domain.Document, 0, batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		docs := batch
+		pool.Submit(ctx, docs, func(_ int, err error) {
+			if err != nil {
+				log.Printf("Erro ao inserir lote de %d registros: %v", len(docs), err)
+				failed += len(docs)
+			} else {
+				stats.imported += len(docs)
+			}
+		})
+		batch = make([]domain.Document, 0, batchSize)
+	}
+	finish := func(err error) (importStats, error) {
+		pool.Wait()
+		stats.failed = invalid + failed
+		return stats, err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return finish(err)
+		}
+
+		doc, err := records.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var recordErr *loader.RecordError
+		if errors.As(err, &recordErr) {
+			stats.rows++
+			invalid++
+			log.Printf("Registro ignorado: %v", recordErr)
+			continue
+		}
+		if err != nil {
+			flush()
+			return finish(err)
+		}
+
+		stats.rows++
+		batch = append(batch, doc)
+		if len(batch) == batchSize {
+			flush()
+		}
+	}
+	flush()
+	return finish(nil)
+}
+
+// newIngester conecta ao banco configurado em DB_DRIVER e monta o Ingester
+// com chunking, embeddings e cache configurados no ambiente
+func newIngester(ctx context.Context) (*ingest.Ingester, func(), error) {
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao conectar ao banco de dados: %w", err)
+	}
+	closers := []func(){func() { db.Close(context.Background()) }}
+	closeAll := func() {
+		for _, c := range closers {
+			c()
+		}
+	}
+
+	if err := db.SetupIndexes(ctx); err != nil {
+		log.Printf("Aviso ao configurar índices: %v", err)
+	}
+
+	splitter, err := chunking.NewSplitter(chunking.ConfigFromEnv())
+	if err != nil {
+		closeAll()
+		return nil, nil, fmt.Errorf("erro na configuração de chunking: %w", err)
+	}
+	opts := []ingest.Option{ingest.WithSplitter(splitter)}
+	if embedder := llm.NewEmbeddingClient(llm.ConfigFromEnv()); embedder != nil {
+		opts = append(opts, ingest.WithEmbeddingClient(embedder))
+	}
+	responseCache, err := cache.New(ctx, cache.ConfigFromEnv())
+	if err != nil {
+		log.Printf("Aviso ao conectar ao cache: %v", err)
+	} else if responseCache != nil {
+		closers = append(closers, func() { responseCache.Close() })
+		opts = append(opts, ingest.WithResponseCache(responseCache))
+	}
+	dispatcher, err := webhook.New(webhook.ConfigFromEnv())
+	if err != nil {
+		closeAll()
+		return nil, nil, fmt.Errorf("erro na configuração dos webhooks: %w", err)
+	}
+	if dispatcher != nil {
+		// Fecha antes do banco, esperando as entregas pendentes
+		closers = append([]func(){func() { dispatcher.Close() }}, closers...)
+		opts = append(opts, ingest.WithEventPublisher(dispatcher))
+	}
+	audit, err := database.AuditFromEnv(db)
+	if err != nil {
+		closeAll()
+		return nil, nil, fmt.Errorf("erro ao configurar auditoria: %w", err)
+	}
+	if audit != nil {
+		opts = append(opts, ingest.WithAuditLog(audit))
+	}
+
+	return ingest.New(db, opts...), closeAll, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/alextavella/agentic-rag/internal/backup"
+	"github.com/alextavella/agentic-rag/internal/cache"
+	"github.com/alextavella/agentic-rag/internal/database"
+)
+
+// restoreBackup grava os documentos (e, opcionalmente, as conversas) de um
+// backup gerado pelo comando export. Os IDs, embeddings e datas são mantidos
+// e registros já existentes são substituídos, então restaurar o mesmo
+// arquivo duas vezes não duplica a base.
+func restoreBackup(ctx context.Context, docsPath, convsPath, format string, batchSize int) error {
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		return fmt.Errorf("erro ao conectar ao banco de dados: %w", err)
+	}
+	defer db.Close(context.Background())
+
+	if err := db.SetupIndexes(ctx); err != nil {
+		log.Printf("Aviso ao configurar índices: %v", err)
+	}
+
+	reader, closeFile, err := openBackup(docsPath, format)
+	if err != nil {
+		return err
+	}
+	defer closeFile()
+	docs, err := restoreBatches(ctx, reader.ReadDocument, db.RestoreDocuments, batchSize)
+	log.Printf("%d documentos restaurados", docs)
+	if err != nil {
+		return err
+	}
+
+	if convsPath != "" {
+		reader, closeFile, err := openBackup(convsPath, format)
+		if err != nil {
+			return err
+		}
+		defer closeFile()
+		convs, err := restoreBatches(ctx, reader.ReadConversation, db.RestoreConversations, batchSize)
+		log.Printf("%d conversas restauradas", convs)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Respostas guardadas podem não refletir os documentos restaurados
+	responseCache, err := cache.New(ctx, cache.ConfigFromEnv())
+	if err != nil {
+		log.Printf("Aviso ao conectar ao cache: %v", err)
+	} else if responseCache != nil {
+		defer responseCache.Close()
+		if err := responseCache.Invalidate(ctx); err != nil {
+			log.Printf("Aviso ao invalidar o cache: %v", err)
+		}
+	}
+	return nil
+}
+
+// openBackup abre um arquivo de backup no formato informado ou deduzido
+// pela extensão
+func openBackup(path, format string) (*backup.Reader, func(), error) {
+	if format == "" {
+		format = backup.FormatFromPath(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao abrir arquivo: %w", err)
+	}
+	reader, err := backup.NewReader(file, format)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return reader, func() { file.Close() }, nil
+}
+
+// restoreBatches lê os registros com next e os grava em lotes com write,
+// retornando quantos foram gravados
+func restoreBatches[T any](ctx context.Context, next func() (T, error), write func(context.Context, []T) error, batchSize int) (int, error) {
+	restored := 0
+	batch := make([]T, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := write(ctx, batch); err != nil {
+			return err
+		}
+		restored += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		record, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return restored, err
+		}
+		batch = append(batch, record)
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return restored, err
+			}
+		}
+	}
+	return restored, flush()
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/cache"
+	"github.com/alextavella/agentic-rag/internal/chunking"
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/infrastructure/llm"
+	"github.com/alextavella/agentic-rag/internal/ingest"
+	"github.com/alextavella/agentic-rag/internal/loader"
+	"github.com/alextavella/agentic-rag/internal/webhook"
+)
+
+// usage descreve os comandos disponíveis
+const usage = `Uso:
+  ingest url [--category <categoria>] <endereço>
+      indexa uma página web
+  ingest sitemap [--category <categoria>] [--concurrency 4] [--workers 4] [--max-pages 0] [--timeout 1h] <sitemap.xml>
+      indexa todas as páginas listadas no sitemap, respeitando o robots.txt
+
+Páginas já indexadas são substituídas, usando o link canônico como chave.`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// SIGINT e SIGTERM interrompem a ingestão, que ainda espera as páginas
+	// em andamento e fecha as conexões
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "url":
+		err = runURL(ctx, os.Args[2:])
+	case "sitemap":
+		err = runSitemap(ctx, os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("Erro na ingestão: %v", err)
+	}
+}
+
+// runURL baixa uma página, extrai o conteúdo principal e o insere na base
+func runURL(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("url", flag.ExitOnError)
+	category := flags.String("category", "", "categoria dos documentos")
+	tenant := flags.String("tenant", "", "tenant dos documentos")
+	timeout := flags.Duration("timeout", 5*time.Minute, "tempo máximo da ingestão")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("informe o endereço da página\n%s", usage)
+	}
+
+	if err := domain.ValidateTenantID(*tenant); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(domain.WithTenant(ctx, *tenant), *timeout)
+	defer cancel()
+
+	doc, err := loader.NewHTMLLoader(nil).LoadURL(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	doc.Category = *category
+
+	ingester, closeIngester, err := newIngester(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeIngester()
+
+	return ingestPage(ctx, ingester, doc)
+}
+
+// runSitemap percorre o sitemap e insere cada página encontrada na base
+func runSitemap(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("sitemap", flag.ExitOnError)
+	category := flags.String("category", "", "categoria dos documentos")
+	tenant := flags.String("tenant", "", "tenant dos documentos")
+	concurrency := flags.Int("concurrency", 4, "páginas baixadas em paralelo")
+	workers := flags.Int("workers", ingest.DefaultWorkers, "páginas indexadas em paralelo")
+	maxPages := flags.Int("max-pages", 0, "limite de páginas processadas (0 processa todas)")
+	timeout := flags.Duration("timeout", time.Hour, "tempo máximo da ingestão")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("informe o endereço do sitemap\n%s", usage)
+	}
+
+	if err := domain.ValidateTenantID(*tenant); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(domain.WithTenant(ctx, *tenant), *timeout)
+	defer cancel()
+
+	ingester, closeIngester, err := newIngester(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeIngester()
+
+	// As páginas baixadas são indexadas em paralelo; a primeira falha
+	// interrompe o sitemap
+	pool := ingest.NewPool(ingester, *workers)
+	var mu sync.Mutex
+	var ingestErr error
+	pages := 0
+	crawler := loader.NewCrawler(nil, loader.CrawlerConfig{Concurrency: *concurrency, MaxPages: *maxPages})
+	err = crawler.Crawl(ctx, flags.Arg(0), func(doc *domain.Document) error {
+		mu.Lock()
+		err := ingestErr
+		mu.Unlock()
+		if err != nil {
+			return err
+		}
+
+		doc.Category = *category
+		pool.Submit(ctx, []domain.Document{*doc}, func(inserted int, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if ingestErr == nil {
+					ingestErr = err
+				}
+				return
+			}
+			log.Printf("Página indexada: %s (%s), %d chunks", doc.Title, doc.Link, inserted)
+			pages++
+		})
+		return nil
+	})
+	pool.Wait()
+	log.Printf("%d páginas indexadas", pages)
+	if ingestErr != nil {
+		return ingestErr
+	}
+	return err
+}
+
+// ingestPage insere a página, substituindo a versão já indexada
+func ingestPage(ctx context.Context, ingester *ingest.Ingester, doc *domain.Document) error {
+	inserted, err := ingester.Ingest(ctx, []domain.Document{*doc})
+	if err != nil {
+		return err
+	}
+	log.Printf("Página indexada: %s (%s), %d chunks", doc.Title, doc.Link, inserted)
+	return nil
+}
+
+// newIngester conecta ao banco configurado em DB_DRIVER e monta o Ingester
+// com chunking, embeddings e cache configurados no ambiente
+func newIngester(ctx context.Context) (*ingest.Ingester, func(), error) {
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao conectar ao banco de dados: %w", err)
+	}
+	closers := []func(){func() { db.Close(context.Background()) }}
+	closeAll := func() {
+		for _, c := range closers {
+			c()
+		}
+	}
+
+	if err := db.SetupIndexes(ctx); err != nil {
+		log.Printf("Aviso ao configurar índices: %v", err)
+	}
+
+	splitter, err := chunking.NewSplitter(chunking.ConfigFromEnv())
+	if err != nil {
+		closeAll()
+		return nil, nil, fmt.Errorf("erro na configuração de chunking: %w", err)
+	}
+	opts := []ingest.Option{
+		ingest.WithSplitter(splitter),
+		ingest.WithReplaceByLink(db),
+	}
+	if embedder := llm.NewEmbeddingClient(llm.ConfigFromEnv()); embedder != nil {
+		opts = append(opts, ingest.WithEmbeddingClient(embedder))
+	}
+	responseCache, err := cache.New(ctx, cache.ConfigFromEnv())
+	if err != nil {
+		log.Printf("Aviso ao conectar ao cache: %v", err)
+	} else if responseCache != nil {
+		closers = append(closers, func() { responseCache.Close() })
+		opts = append(opts, ingest.WithResponseCache(responseCache))
+	}
+	dispatcher, err := webhook.New(webhook.ConfigFromEnv())
+	if err != nil {
+		closeAll()
+		return nil, nil, fmt.Errorf("erro na configuração dos webhooks: %w", err)
+	}
+	if dispatcher != nil {
+		// Fecha antes do banco, esperando as entregas pendentes
+		closers = append([]func(){func() { dispatcher.Close() }}, closers...)
+		opts = append(opts, ingest.WithEventPublisher(dispatcher))
+	}
+	audit, err := database.AuditFromEnv(db)
+	if err != nil {
+		closeAll()
+		return nil, nil, fmt.Errorf("erro ao configurar auditoria: %w", err)
+	}
+	if audit != nil {
+		opts = append(opts, ingest.WithAuditLog(audit))
+	}
+
+	return ingest.New(db, opts...), closeAll, nil
+}
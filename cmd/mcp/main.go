@@ -0,0 +1,105 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/app"
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/mcp"
+)
+
+// Transportes do protocolo
+const (
+	transportStdio = "stdio"
+	transportHTTP  = "http"
+)
+
+// serverVersion é a versão informada aos clientes no initialize
+const serverVersion = "1.0.0"
+
+func main() {
+	transport := flag.String("transport", transportStdio, "transporte do protocolo: stdio ou http")
+	addr := flag.String("addr", cmp.Or(os.Getenv("MCP_ADDR"), "127.0.0.1:8090"), "endereço do servidor HTTP, com --transport http")
+	tenant := flag.String("tenant", "", "tenant cujos documentos e conversas são usados")
+	timeout := flag.Duration("timeout", 2*time.Minute, "tempo máximo de cada pergunta feita com answer_question")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Uso: mcp [--transport stdio|http] [opções]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 || (*transport != transportStdio && *transport != transportHTTP) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := domain.ValidateTenantID(*tenant); err != nil {
+		log.Fatalf("Tenant inválido: %v", err)
+	}
+
+	// Encerra ao receber SIGINT ou SIGTERM; no stdio, também no fim da entrada
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = domain.WithTenant(ctx, *tenant)
+
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco de dados: %v", err)
+	}
+	defer db.Close(context.Background())
+
+	rag, err := app.New(ctx, db)
+	if err != nil {
+		log.Fatalf("Erro ao configurar o serviço: %v", err)
+	}
+	// Espera as tarefas em segundo plano do serviço antes de fechar o banco
+	defer rag.Close(context.Background())
+
+	registry, err := mcp.RAGTools(rag.Service, *timeout)
+	if err != nil {
+		log.Fatalf("Erro ao registrar ferramentas: %v", err)
+	}
+	var opts []mcp.ServerOption
+	if token := os.Getenv("MCP_AUTH_TOKEN"); token != "" {
+		opts = append(opts, mcp.WithAuthToken(token))
+	}
+	server := mcp.NewServer(mcp.Implementation{Name: "agentic-rag", Version: serverVersion}, registry, opts...)
+
+	if *transport == transportStdio {
+		// A saída padrão é do protocolo; o log continua na saída de erros
+		if err := server.ServeStdio(ctx, os.Stdin, os.Stdout); err != nil {
+			log.Fatalf("Erro no transporte stdio: %v", err)
+		}
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", server)
+	httpServer := &http.Server{Addr: *addr, Handler: mux}
+	// ListenAndServe retorna assim que Shutdown começa; as requisições em
+	// andamento são esperadas antes de fechar o banco
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Aviso: requisições interrompidas no encerramento: %v", err)
+		}
+	}()
+	log.Printf("Servidor MCP em http://%s/mcp", *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Erro no servidor MCP: %v", err)
+	}
+	<-shutdownDone
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/cache"
+	"github.com/alextavella/agentic-rag/internal/chunking"
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/infrastructure/llm"
+	"github.com/alextavella/agentic-rag/internal/ingest"
+	"github.com/alextavella/agentic-rag/internal/refresh"
+	"github.com/alextavella/agentic-rag/internal/resilience"
+	"github.com/alextavella/agentic-rag/internal/webhook"
+)
+
+func main() {
+	sourcesFile := flag.String("sources", os.Getenv("REFRESH_SOURCES_FILE"), "arquivo JSON com as fontes e os seus schedules")
+	once := flag.Bool("once", false, "atualiza todas as fontes uma vez e termina, sem agendar")
+	concurrency := flag.Int("concurrency", 4, "páginas de um sitemap baixadas em paralelo")
+	timeout := flag.Duration("timeout", time.Hour, "tempo máximo de cada atualização de fonte")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Uso: refresh --sources fontes.json [opções]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 || *sourcesFile == "" || *concurrency <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	sources, err := refresh.LoadSources(*sourcesFile)
+	if err != nil {
+		log.Fatalf("Erro nas fontes: %v", err)
+	}
+
+	// Encerra ao receber SIGINT ou SIGTERM, esperando as atualizações em andamento
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco de dados: %v", err)
+	}
+	defer db.Close(context.Background())
+
+	if err := db.SetupIndexes(ctx); err != nil {
+		log.Printf("Aviso ao configurar índices: %v", err)
+	}
+
+	splitter, err := chunking.NewSplitter(chunking.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro na configuração de chunking: %v", err)
+	}
+	// As páginas alteradas substituem as já indexadas com o mesmo link
+	opts := []ingest.Option{
+		ingest.WithSplitter(splitter),
+		ingest.WithReplaceByLink(db),
+	}
+	if embedder := llm.NewEmbeddingClient(llm.ConfigFromEnv()); embedder != nil {
+		opts = append(opts, ingest.WithEmbeddingClient(resilience.NewRetryEmbeddingClient(embedder, resilience.RetryPolicyFromEnv())))
+	}
+	responseCache, err := cache.New(ctx, cache.ConfigFromEnv())
+	if err != nil {
+		log.Printf("Aviso ao conectar ao cache: %v", err)
+	} else if responseCache != nil {
+		defer responseCache.Close()
+		opts = append(opts, ingest.WithResponseCache(responseCache))
+	}
+	// Notifica as páginas indexadas aos endpoints de WEBHOOK_URLS
+	dispatcher, err := webhook.New(webhook.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro na configuração dos webhooks: %v", err)
+	}
+	if dispatcher != nil {
+		defer dispatcher.Close()
+		opts = append(opts, ingest.WithEventPublisher(dispatcher))
+	}
+	// Registra os documentos gravados no log de auditoria (AUDIT_LOG=true)
+	audit, err := database.AuditFromEnv(db)
+	if err != nil {
+		log.Fatalf("Erro ao configurar auditoria: %v", err)
+	}
+	if audit != nil {
+		opts = append(opts, ingest.WithAuditLog(audit))
+	}
+
+	refresher := refresh.New(ingest.New(db, opts...), *concurrency)
+	if *once {
+		if err := refresher.RefreshAll(ctx, sources, *timeout); err != nil {
+			log.Fatalf("Atualização concluída com erros: %v", err)
+		}
+		return
+	}
+
+	log.Printf("%d fontes carregadas de %s", len(sources), *sourcesFile)
+	if err := refresher.Run(ctx, sources, *timeout); err != nil {
+		log.Fatalf("Erro ao agendar as fontes: %v", err)
+	}
+	log.Println("Agendador encerrado")
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/cache"
+	"github.com/alextavella/agentic-rag/internal/chunking"
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/infrastructure/llm"
+	"github.com/alextavella/agentic-rag/internal/resilience"
+)
+
+func main() {
+	batchSize := flag.Int("batch-size", 100, "documentos com embeddings gerados e gravados por lote")
+	concurrency := flag.Int("concurrency", 4, "lotes processados ao mesmo tempo")
+	rechunk := flag.Bool("rechunk", true, "divide os documentos novamente com a configuração de chunking do ambiente")
+	timeout := flag.Duration("timeout", 6*time.Hour, "tempo máximo da reindexação")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Uso: reindex [opções]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 || *batchSize <= 0 || *concurrency <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// SIGINT e SIGTERM interrompem a reindexação, que é descartada mantendo
+	// a base atual
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	r := &reindexer{batchSize: *batchSize, concurrency: *concurrency}
+	if *rechunk {
+		var err error
+		if r.splitter, err = chunking.NewSplitter(chunking.ConfigFromEnv()); err != nil {
+			log.Fatalf("Erro na configuração de chunking: %v", err)
+		}
+	}
+	if embedder := llm.NewEmbeddingClient(llm.ConfigFromEnv()); embedder != nil {
+		r.embedder = resilience.NewRetryEmbeddingClient(embedder, resilience.RetryPolicyFromEnv())
+	} else {
+		log.Println("Nenhum cliente de embeddings configurado, documentos serão gravados sem embeddings")
+	}
+
+	if err := r.reindex(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// reindex grava os documentos em uma base nova e a troca pela atual,
+// descartando a nova em caso de falha
+func (r *reindexer) reindex(ctx context.Context) error {
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		return fmt.Errorf("erro ao conectar ao banco de dados: %w", err)
+	}
+	defer db.Close(context.Background())
+
+	if r.target, err = db.BeginReindex(ctx); err != nil {
+		return fmt.Errorf("erro ao iniciar a reindexação: %w", err)
+	}
+	log.Println("Reindexação iniciada; documentos gravados na base atual até a troca não serão mantidos")
+
+	read, written, err := r.run(ctx, db)
+	if err != nil {
+		if abortErr := r.target.Abort(context.Background()); abortErr != nil {
+			log.Printf("Aviso ao descartar a reindexação: %v", abortErr)
+		}
+		return fmt.Errorf("reindexação interrompida, a base atual foi mantida: %w", err)
+	}
+	if err := r.target.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao trocar a base: %w", err)
+	}
+	log.Printf("Reindexação concluída: %d documentos lidos, %d gravados", read, written)
+
+	// Respostas guardadas foram geradas com a base anterior
+	responseCache, err := cache.New(ctx, cache.ConfigFromEnv())
+	if err != nil {
+		log.Printf("Aviso ao conectar ao cache: %v", err)
+	} else if responseCache != nil {
+		defer responseCache.Close()
+		if err := responseCache.Invalidate(ctx); err != nil {
+			log.Printf("Aviso ao invalidar o cache: %v", err)
+		}
+	}
+	return nil
+}
+
+// chunkGroup identifica os chunks de um documento lógico
+type chunkGroup struct {
+	tenantID string
+	parentID string
+}
+
+// contentKey identifica um conteúdo no tenant
+type contentKey struct {
+	tenantID string
+	hash     string
+}
+
+// reindexer regrava todos os documentos da base com a configuração atual
+type reindexer struct {
+	splitter    chunking.Splitter      // Opcional: divide os documentos novamente
+	embedder    domain.EmbeddingClient // Opcional: gera os novos embeddings
+	target      database.Reindex
+	batchSize   int
+	concurrency int
+}
+
+// run percorre os documentos da base, de todos os tenants e inclusive os
+// excluídos, e os grava na reindexação em lotes processados em paralelo.
+// Com o splitter, os chunks de cada documento lógico são guardados até o fim
+// da leitura, reunidos e divididos novamente. Documentos sem hash de
+// conteúdo o recebem. Retorna quantos documentos
+// foram lidos e quantos foram gravados; um erro em qualquer lote interrompe
+// a reindexação.
+func (r *reindexer) run(ctx context.Context, source database.Store) (int, int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var written atomic.Int64
+	var firstErr error
+	var once sync.Once
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	batches := make(chan []domain.Document)
+	var wg sync.WaitGroup
+	for range r.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if err := r.write(ctx, batch); err != nil {
+					fail(err)
+					continue // Esvazia o canal para encerrar a leitura
+				}
+				total := written.Add(int64(len(batch)))
+				log.Printf("%d documentos gravados", total)
+			}
+		}()
+	}
+
+	pending := make([]domain.Document, 0, r.batchSize)
+	emit := func(docs ...domain.Document) error {
+		for _, doc := range docs {
+			pending = append(pending, doc)
+			if len(pending) < r.batchSize {
+				continue
+			}
+			select {
+			case batches <- pending:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			pending = make([]domain.Document, 0, r.batchSize)
+		}
+		return nil
+	}
+
+	read := 0
+	seen := map[contentKey]string{}
+	groups := map[chunkGroup][]domain.Document{}
+	err := source.ExportDocuments(ctx, database.ExportFilter{}, func(doc domain.Document) error {
+		read++
+		doc.Embedding = nil
+		if doc.ParentID == "" {
+			fillContentHash(&doc, doc.ID, seen)
+		}
+		if r.splitter == nil {
+			return emit(doc)
+		}
+		if doc.ParentID != "" {
+			key := chunkGroup{tenantID: doc.TenantID, parentID: doc.ParentID}
+			groups[key] = append(groups[key], doc)
+			return nil
+		}
+		return emit(r.split(doc, doc.ID, nil)...)
+	})
+	for key, chunks := range groups {
+		if err != nil {
+			break
+		}
+		doc := chunking.Reassemble(chunks)
+		fillContentHash(&doc, key.parentID, seen)
+		err = emit(r.split(doc, key.parentID, chunks)...)
+	}
+	if err == nil && len(pending) > 0 {
+		select {
+		case batches <- pending:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	close(batches)
+	wg.Wait()
+
+	if firstErr != nil {
+		return read, written.Load(), firstErr
+	}
+	return read, written.Load(), err
+}
+
+// fillContentHash calcula o hash de conteúdo dos documentos gravados antes
+// da deduplicação. Cópias de um conteúdo já visto no tenant ficam sem o hash,
+// para não violar o índice único, e são listadas no log para remoção manual.
+func fillContentHash(doc *domain.Document, id string, seen map[contentKey]string) {
+	if doc.ContentHash == "" {
+		doc.ContentHash = domain.ContentHash(doc.Content)
+	}
+	key := contentKey{tenantID: doc.TenantID, hash: doc.ContentHash}
+	if original, ok := seen[key]; ok {
+		log.Printf("Aviso: documento %s tem o mesmo conteúdo de %s", id, original)
+		doc.ContentHash = ""
+		return
+	}
+	seen[key] = id
+}
+
+// split divide o documento lógico com o splitter atual, usando parentID como
+// ParentID dos chunks, e reaproveita os IDs dos chunks anteriores, na ordem.
+// Um documento que era dividido e agora cabe em um único chunk continua
+// sendo um chunk de parentID, para que siga acessível pelo mesmo ID.
+func (r *reindexer) split(doc domain.Document, parentID string, previous []domain.Document) []domain.Document {
+	chunks := chunking.ChunkDocument(doc, r.splitter, parentID)
+	if len(previous) == 0 {
+		return chunks
+	}
+
+	if len(chunks) == 1 {
+		chunks[0].ParentID = parentID
+		chunks[0].ChunkIndex = 0
+		chunks[0].ID = ""
+	}
+	previous = slices.Clone(previous)
+	slices.SortFunc(previous, func(a, b domain.Document) int { return a.ChunkIndex - b.ChunkIndex })
+	for i := range min(len(chunks), len(previous)) {
+		chunks[i].ID = previous[i].ID
+	}
+	return chunks
+}
+
+// write gera os embeddings do lote e o grava na reindexação
+func (r *reindexer) write(ctx context.Context, batch []domain.Document) error {
+	if r.embedder != nil {
+		texts := make([]string, len(batch))
+		for i, doc := range batch {
+			texts[i] = doc.EmbeddingText()
+		}
+		vectors, err := r.embedder.Embed(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("erro ao gerar embeddings: %w", err)
+		}
+		for i := range batch {
+			batch[i].Embedding = vectors[i]
+		}
+	}
+	return r.target.Write(ctx, batch)
+}
@@ -2,72 +2,129 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/alextavella/agentic-rag/internal/cache"
+	"github.com/alextavella/agentic-rag/internal/chunking"
 	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/infrastructure/llm"
+	"github.com/alextavella/agentic-rag/internal/ingest"
+	"github.com/alextavella/agentic-rag/internal/loader"
+	"github.com/alextavella/agentic-rag/internal/webhook"
 )
 
 func main() {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	dir := flag.String("dir", "data", "diretório com os arquivos (Markdown, PDF ou HTML) a inserir; a pasta de cada arquivo é usada como categoria")
+	tenant := flag.String("tenant", "", "tenant dos documentos inseridos")
+	batchSize := flag.Int("batch-size", 50, "documentos por lote de embeddings e inserção")
+	concurrency := flag.Int("concurrency", ingest.DefaultWorkers, "lotes processados ao mesmo tempo")
+	timeout := flag.Duration("timeout", 30*time.Minute, "tempo máximo do seed, incluindo a geração dos embeddings")
+	flag.Parse()
+	if *batchSize <= 0 || *concurrency <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := domain.ValidateTenantID(*tenant); err != nil {
+		log.Fatalf("Tenant inválido: %v", err)
+	}
+
+	// Carrega os documentos a partir dos arquivos (Markdown, PDF e HTML) do diretório
+	documents, err := loader.LoadDir(*dir)
+	if err != nil {
+		log.Fatalf("Erro ao carregar documentos de %s: %v", *dir, err)
+	}
+	if len(documents) == 0 {
+		log.Fatalf("Nenhum arquivo suportado (.md, .pdf, .html) encontrado em %s", *dir)
+	}
+	log.Printf("%d documentos carregados de %s", len(documents), *dir)
+
+	// SIGINT e SIGTERM interrompem o seed, que ainda espera os lotes em
+	// andamento e fecha as conexões
+	ctx, stop := signal.NotifyContext(domain.WithTenant(context.Background(), *tenant), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
 	defer cancel()
 
-	// Conecta ao MongoDB
-	mongoURI := os.Getenv("MONGO_URI")
-	db, err := database.NewMongoDB(ctx, mongoURI)
+	if err := seed(ctx, documents, *batchSize, *concurrency); err != nil {
+		log.Fatalf("Seed interrompido: %v", err)
+	}
+	log.Println("Seed concluído com sucesso!")
+}
+
+// seed limpa a base e insere os documentos em lotes paralelos
+func seed(ctx context.Context, documents []domain.Document, batchSize, concurrency int) error {
+	// Conecta ao banco configurado em DB_DRIVER
+	db, err := database.Open(ctx, database.ConfigFromEnv())
 	if err != nil {
-		log.Fatalf("Erro ao conectar ao MongoDB: %v", err)
+		return fmt.Errorf("erro ao conectar ao banco de dados: %w", err)
 	}
-	defer db.Close(ctx)
+	defer db.Close(context.Background())
 
-	// Documentos de exemplo sobre performance em Go
-	documents := []database.Document{
-		{
-			Title:    "Optimizing Go Routines",
-			Content:  "Goroutines são leves e eficientes, mas é importante gerenciá-las corretamente. Este guia aborda as melhores práticas para otimização de goroutines, incluindo o uso adequado de channels, wait groups e context.",
-			Link:     "/docs/go-optimizing",
-			Category: "performance",
-		},
-		{
-			Title:    "Memory Management in Go",
-			Content:  "O garbage collector do Go é sofisticado, mas entender como ele funciona é crucial para otimização. Aprenda sobre alocação de memória, escape analysis e dicas para reduzir a pressão no GC.",
-			Link:     "/docs/go-memory",
-			Category: "performance",
-		},
-		{
-			Title:    "Profiling Go Applications",
-			Content:  "Ferramentas de profiling são essenciais para identificar gargalos. Este documento explora o uso de pprof, trace e outras ferramentas built-in do Go para análise de performance.",
-			Link:     "/docs/go-profiling",
-			Category: "performance",
-		},
-		{
-			Title:    "Database Performance in Go",
-			Content:  "Otimize suas consultas de banco de dados em Go. Aprenda sobre connection pooling, prepared statements e como estruturar suas queries para máxima eficiência.",
-			Link:     "/docs/go-db-performance",
-			Category: "performance",
-		},
-		{
-			Title:    "Network Performance Tuning",
-			Content:  "Maximize a performance de rede em aplicações Go. Inclui dicas sobre TCP tuning, HTTP/2, e como implementar client-side caching efetivamente.",
-			Link:     "/docs/go-network",
-			Category: "performance",
-		},
+	// Garante que o esquema e os índices existam
+	if err := db.SetupIndexes(ctx); err != nil {
+		log.Printf("Aviso ao configurar índices: %v", err)
 	}
 
-	// Limpa a coleção antes de inserir os novos documentos
-	if err := db.ClearCollection(ctx); err != nil {
-		log.Fatalf("Erro ao limpar a coleção: %v", err)
+	// Divide os documentos longos em chunks e gera os embeddings para a
+	// busca vetorial, se houver cliente disponível
+	splitter, err := chunking.NewSplitter(chunking.ConfigFromEnv())
+	if err != nil {
+		return fmt.Errorf("erro na configuração de chunking: %w", err)
+	}
+	opts := []ingest.Option{ingest.WithSplitter(splitter)}
+	if embedder := llm.NewEmbeddingClient(llm.ConfigFromEnv()); embedder != nil {
+		opts = append(opts, ingest.WithEmbeddingClient(embedder))
+	} else {
+		log.Println("Nenhum cliente de embeddings configurado, documentos serão inseridos sem embeddings")
+	}
+	// Descarta as respostas em cache, que podem não refletir os novos documentos
+	responseCache, err := cache.New(ctx, cache.ConfigFromEnv())
+	if err != nil {
+		log.Printf("Aviso ao conectar ao cache: %v", err)
+	} else if responseCache != nil {
+		defer responseCache.Close()
+		opts = append(opts, ingest.WithResponseCache(responseCache))
+	}
+	// Notifica os documentos inseridos aos endpoints de WEBHOOK_URLS
+	dispatcher, err := webhook.New(webhook.ConfigFromEnv())
+	if err != nil {
+		return fmt.Errorf("erro na configuração dos webhooks: %w", err)
+	}
+	if dispatcher != nil {
+		defer dispatcher.Close()
+		opts = append(opts, ingest.WithEventPublisher(dispatcher))
 	}
 
-	// Insere os documentos
-	for _, doc := range documents {
-		if err := db.InsertDocument(ctx, doc); err != nil {
-			log.Printf("Erro ao inserir documento '%s': %v", doc.Title, err)
-			continue
-		}
-		log.Printf("Documento inserido com sucesso: %s", doc.Title)
+	// Limpa a base antes de inserir os novos documentos
+	if err := db.Clear(ctx); err != nil {
+		return fmt.Errorf("erro ao limpar os documentos: %w", err)
 	}
 
-	log.Println("Seed concluído com sucesso!")
+	// Os lotes são divididos em chunks, enviados aos embeddings e gravados
+	// em paralelo
+	pool := ingest.NewPool(ingest.New(db, opts...), concurrency)
+	inserted, failed := 0, 0
+	for start := 0; start < len(documents); start += batchSize {
+		batch := documents[start:min(start+batchSize, len(documents))]
+		pool.Submit(ctx, batch, func(chunks int, err error) {
+			inserted += chunks
+			if err != nil {
+				log.Printf("Erro ao inserir lote de %d documentos: %v", len(batch), err)
+				failed += len(batch)
+			}
+		})
+	}
+	pool.Wait()
+	log.Printf("%d chunks inseridos", inserted)
+	if failed > 0 {
+		return fmt.Errorf("%d de %d documentos não foram inseridos", failed, len(documents))
+	}
+	return nil
 }
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/api"
+	"github.com/alextavella/agentic-rag/internal/app"
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/health"
+	"github.com/alextavella/agentic-rag/internal/infrastructure/llm"
+	"github.com/alextavella/agentic-rag/internal/ingest"
+	"github.com/alextavella/agentic-rag/internal/jobs"
+	"github.com/alextavella/agentic-rag/internal/metrics"
+	"github.com/alextavella/agentic-rag/internal/tracing"
+)
+
+// defaultShutdownTimeout é a espera padrão pelas requisições em andamento
+// ao encerrar o servidor
+const defaultShutdownTimeout = 30 * time.Second
+
+func main() {
+	// Ao receber SIGINT ou SIGTERM, o servidor para de aceitar conexões,
+	// espera as requisições em andamento por até SHUTDOWN_TIMEOUT e fecha as
+	// conexões com o banco, o cache e os demais serviços
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// Registrado antes das demais limpezas, para sair com erro depois delas
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+	shutdownTimeout, _ := time.ParseDuration(os.Getenv("SHUTDOWN_TIMEOUT"))
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
+	// Exporta os traces para o coletor configurado em OTEL_EXPORTER_OTLP_ENDPOINT;
+	// os spans pendentes são enviados ao encerrar
+	shutdownTracing, err := tracing.Setup(ctx, tracing.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro ao configurar tracing: %v", err)
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.Printf("Aviso ao enviar os traces pendentes: %v", err)
+		}
+	}()
+
+	// Inicializa a conexão com o banco configurado em DB_DRIVER
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco de dados: %v", err)
+	}
+	defer db.Close(context.Background())
+
+	// Configura os índices de busca (necessário apenas uma vez)
+	if err := db.SetupIndexes(ctx); err != nil {
+		log.Printf("Aviso ao configurar índice: %v", err)
+	}
+
+	// Monta o serviço com o provedor de LLM configurado em LLM_PROVIDER,
+	// seguido dos provedores de LLM_FALLBACK. Cada provedor repete as chamadas
+	// que falham por motivos transitórios (como o 429 da OpenAI) e recusa as
+	// chamadas enquanto está fora do ar, passando a vez ao próximo; sem
+	// provedores disponíveis, as perguntas falham rápido e as rotas de
+	// documentos continuam atendendo
+	rag, err := app.New(ctx, db)
+	if err != nil {
+		log.Fatalf("Erro ao configurar o serviço: %v", err)
+	}
+	// Registrado depois do banco, para fechar os caches e as demais conexões
+	// antes dele e depois dos jobs
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := rag.Close(closeCtx); err != nil {
+			log.Printf("Aviso: %v", err)
+		}
+	}()
+
+	var handlerOpts []api.Option
+	if rag.Quality != nil {
+		handlerOpts = append(handlerOpts, api.WithQuality(rag.Quality))
+	}
+	if rag.Audit != nil {
+		handlerOpts = append(handlerOpts, api.WithAudit(rag.Audit))
+	}
+	if conversations := db.Conversations(); conversations != nil {
+		handlerOpts = append(handlerOpts, api.WithConversations(conversations))
+	}
+	if rag.Feedback != nil {
+		handlerOpts = append(handlerOpts, api.WithFeedback(rag.Feedback))
+	}
+	if rag.Analytics != nil {
+		handlerOpts = append(handlerOpts, api.WithAnalytics(rag.Analytics))
+	}
+	// Ingestão em segundo plano por POST /v1/ingest, com JOB_WORKERS workers
+	// consumindo a fila do banco (apenas MongoDB)
+	if jobRepo := db.Jobs(); jobRepo != nil {
+		ingestOpts := append(rag.IngestOptions(), ingest.WithStrictBatches())
+		runner := jobs.New(jobRepo, ingest.New(rag.Repository, ingestOpts...), jobs.ConfigFromEnv())
+		jobsDone := make(chan struct{})
+		go func() {
+			defer close(jobsDone)
+			runner.Run(ctx)
+		}()
+		// Os jobs interrompidos são retomados do último documento processado
+		defer func() { <-jobsDone }()
+		handlerOpts = append(handlerOpts, api.WithJobs(jobRepo))
+	}
+	// Frontends de chat em outros domínios, separados por vírgula
+	if origins := os.Getenv("WS_ALLOWED_ORIGINS"); origins != "" {
+		handlerOpts = append(handlerOpts, api.WithAllowedOrigins(strings.Split(origins, ",")...))
+	}
+	// Com API_ADMIN_KEY definida, as rotas exigem chaves de API, criadas em /v1/admin/api-keys
+	if adminKey := os.Getenv("API_ADMIN_KEY"); adminKey != "" {
+		apiKeys := db.APIKeys()
+		if apiKeys == nil {
+			log.Fatalf("O banco %q não guarda chaves de API", database.ConfigFromEnv().Driver)
+		}
+		handlerOpts = append(handlerOpts, api.WithAPIKeys(apiKeys, adminKey))
+	}
+	// /readyz verifica a base, os índices de busca e a chave do provedor de
+	// LLM principal; /livez apenas responde enquanto o processo está no ar
+	readiness := health.New(health.ConfigFromEnv())
+	readiness.Add("database", db.HealthCheck)
+	readiness.Add("indexes", db.CheckIndexes)
+	llmChecker, err := llm.NewHealthChecker(rag.LLMConfig)
+	if err != nil {
+		log.Fatalf("Erro ao criar verificação do LLM: %v", err)
+	}
+	if llmChecker != nil {
+		readiness.AddCached("llm", llmChecker.HealthCheck)
+	}
+	handlerOpts = append(handlerOpts, api.WithReadiness(readiness))
+	handler := api.NewHandler(rag.Service, handlerOpts...)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", handler.Routes())
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	addr := os.Getenv("SERVER_ADDR")
+	if addr == "" {
+		addr = ":8080"
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           tracing.Handler(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Servidor HTTP ouvindo em %s", addr)
+		serverErr <- server.ListenAndServe()
+	}()
+	select {
+	case err := <-serverErr:
+		log.Printf("Erro no servidor HTTP: %v", err)
+		exitCode = 1
+		stop()
+	case <-ctx.Done():
+		log.Println("Encerrando o servidor HTTP...")
+	}
+
+	// As conexões do /ws/chat não são esperadas: o cliente reconecta e
+	// retoma a sessão
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Aviso: requisições interrompidas após %s: %v", shutdownTimeout, err)
+	}
+	log.Println("Servidor encerrado")
+}
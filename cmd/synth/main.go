@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/evaluation"
+	"github.com/alextavella/agentic-rag/internal/infrastructure/llm"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+	"github.com/alextavella/agentic-rag/internal/resilience"
+)
+
+// pageSize é o número de documentos lidos por página da listagem
+const pageSize = 500
+
+// minContentChars é o tamanho mínimo do conteúdo de um documento sorteado;
+// trechos menores raramente respondem uma pergunta
+const minContentChars = 200
+
+// datasetCase é uma linha do dataset, no formato lido pelo comando eval
+type datasetCase struct {
+	ID              string   `json:"id"`
+	Question        string   `json:"question"`
+	ExpectedSources []string `json:"expected_sources"`
+	ExpectedAnswer  string   `json:"expected_answer,omitempty"`
+}
+
+func main() {
+	out := flag.String("out", "-", "arquivo do dataset (.jsonl); - para a saída padrão")
+	samples := flag.Int("samples", 50, "número de documentos sorteados")
+	perDoc := flag.Int("per-doc", 2, "perguntas geradas por documento")
+	category := flag.String("category", "", "sorteia apenas documentos desta categoria")
+	tenant := flag.String("tenant", "", "tenant dos documentos")
+	seed := flag.Uint64("seed", 0, "semente do sorteio, para repetir a mesma amostra (0 sorteia uma)")
+	timeout := flag.Duration("timeout", time.Hour, "tempo máximo da geração")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Uso: synth [opções]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *samples <= 0 || *perDoc <= 0 {
+		log.Fatalf("--samples e --per-doc devem ser positivos")
+	}
+	if err := domain.ValidateTenantID(*tenant); err != nil {
+		log.Fatalf("Tenant inválido: %v", err)
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+
+	// SIGINT e SIGTERM interrompem a geração; as perguntas já gravadas no
+	// dataset são mantidas
+	ctx, stop := signal.NotifyContext(domain.WithTenant(context.Background(), *tenant), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	client, err := llm.New(llm.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro ao criar cliente de LLM: %v", err)
+	}
+	client = resilience.NewRetryLLMClient(client, resilience.RetryPolicyFromEnv())
+	promptSet, err := prompts.New(prompts.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro ao carregar prompts: %v", err)
+	}
+
+	docs, err := sample(ctx, domain.DocumentFilter{Category: *category}, *samples, *seed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(docs) == 0 {
+		log.Fatalf("Nenhum documento com ao menos %d caracteres encontrado", minContentChars)
+	}
+	log.Printf("%d documentos sorteados (semente %d)", len(docs), *seed)
+
+	judge := evaluation.NewJudge(client, promptSet)
+	questions := 0
+	err = writeTo(*out, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		for i, doc := range docs {
+			generated, err := judge.GenerateQuestions(ctx, doc, *perDoc)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				log.Printf("[%d/%d] Aviso em %q: %v", i+1, len(docs), doc.Title, err)
+				continue
+			}
+			for _, q := range generated {
+				questions++
+				err := encoder.Encode(datasetCase{
+					ID:              fmt.Sprintf("synth-%d", questions),
+					Question:        q.Question,
+					ExpectedSources: []string{sourceLabel(doc)},
+					ExpectedAnswer:  q.Answer,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			log.Printf("[%d/%d] %q: %d perguntas", i+1, len(docs), doc.Title, len(generated))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Erro ao gerar o dataset: %v", err)
+	}
+	log.Printf("%d perguntas geradas", questions)
+}
+
+// sample conecta ao banco de dados apenas para sortear os documentos; a
+// geração das perguntas não depende dele
+func sample(ctx context.Context, filter domain.DocumentFilter, n int, seed uint64) ([]domain.Document, error) {
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao banco de dados: %w", err)
+	}
+	defer db.Close(context.Background())
+
+	docs, err := sampleDocuments(ctx, db, filter, n, rand.New(rand.NewPCG(seed, 0)))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar documentos: %w", err)
+	}
+	return docs, nil
+}
+
+// sampleDocuments percorre a listagem e sorteia até n documentos (ou chunks)
+// com conteúdo suficiente, com a mesma chance para cada um, sem guardar a
+// base inteira em memória
+func sampleDocuments(ctx context.Context, repo domain.DocumentRepository, filter domain.DocumentFilter, n int, rng *rand.Rand) ([]domain.Document, error) {
+	sample := make([]domain.Document, 0, n)
+	seen := 0
+	cursor := ""
+	for {
+		page, next, err := repo.List(ctx, filter, cursor, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range page {
+			if utf8.RuneCountInString(strings.TrimSpace(doc.Content)) < minContentChars {
+				continue
+			}
+			// Amostragem por reservatório: o k-ésimo documento substitui um
+			// dos sorteados com probabilidade n/k
+			seen++
+			if len(sample) < n {
+				sample = append(sample, doc)
+			} else if i := rng.IntN(seen); i < n {
+				sample[i] = doc
+			}
+		}
+		if next == "" {
+			return sample, nil
+		}
+		cursor = next
+	}
+}
+
+// sourceLabel identifica o documento em expected_sources: pelo link, que
+// sobrevive a uma nova ingestão, ou pelo ID do documento lógico
+func sourceLabel(doc domain.Document) string {
+	switch {
+	case doc.Link != "":
+		return doc.Link
+	case doc.ParentID != "":
+		return doc.ParentID
+	default:
+		return doc.ID
+	}
+}
+
+// writeTo abre o arquivo do dataset, ou a saída padrão com "-", e grava nele
+func writeTo(path string, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := write(w); err != nil {
+		w.Flush()
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
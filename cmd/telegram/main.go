@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/alextavella/agentic-rag/internal/app"
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/ingest"
+	"github.com/alextavella/agentic-rag/internal/telegram"
+)
+
+func main() {
+	tenant := flag.String("tenant", "", "tenant cujos documentos e conversas são usados")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Uso: telegram [opções]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := domain.ValidateTenantID(*tenant); err != nil {
+		log.Fatalf("Tenant inválido: %v", err)
+	}
+
+	cfg, err := telegram.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Erro na configuração do Telegram: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = domain.WithTenant(ctx, *tenant)
+
+	db, err := database.Open(ctx, database.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco de dados: %v", err)
+	}
+	defer db.Close(context.Background())
+
+	rag, err := app.New(ctx, db)
+	if err != nil {
+		log.Fatalf("Erro ao configurar o serviço: %v", err)
+	}
+	// Espera as tarefas em segundo plano do serviço antes de fechar o banco
+	defer rag.Close(context.Background())
+
+	// O /ingest substitui as páginas já indexadas com o mesmo link
+	ingestOpts := append(rag.IngestOptions(), ingest.WithReplaceByLink(db))
+	bot, err := telegram.New(rag.Service, ingest.New(rag.Repository, ingestOpts...), cfg)
+	if err != nil {
+		log.Fatalf("Erro na configuração do Telegram: %v", err)
+	}
+	if len(cfg.AdminIDs) == 0 {
+		log.Println("Nenhum administrador configurado, /ingest desabilitado")
+	}
+
+	if err := bot.Run(ctx); err != nil {
+		log.Fatalf("Erro no bot: %v", err)
+	}
+	log.Println("Bot do Telegram encerrado")
+}
@@ -0,0 +1,237 @@
+// Package analytics registra cada consulta recebida pelo serviço RAG, com a
+// quantidade de resultados e a latência, para as estatísticas de uso
+// (consultas mais frequentes, consultas sem resultados, latência e
+// categorias) calculadas por domain.AnalyticsRepository
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Valores da gravação dos registros
+const (
+	recordTimeout = 5 * time.Second  // Tempo máximo da gravação de cada registro
+	queueSize     = 1000             // Registros pendentes; acima disso, são descartados
+	drainTimeout  = 10 * time.Second // Espera pelos registros pendentes em Close
+)
+
+// pending é o registro de uma consulta à espera de gravação, com o contexto
+// da consulta, que define o tenant
+type pending struct {
+	ctx    context.Context
+	record domain.QueryRecord
+}
+
+// Service decora um domain.RAGService registrando as perguntas e as buscas
+// no repositório de estatísticas. Os registros são enfileirados e gravados
+// em segundo plano, fora do caminho da resposta; falhas ao registrar não
+// afetam a consulta e são apenas registradas no log.
+type Service struct {
+	next    domain.RAGService
+	queries domain.AnalyticsRepository
+
+	queue chan pending
+	wg    sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewService registra as consultas feitas ao serviço informado e inicia a
+// gravação dos registros, encerrada por Close
+func NewService(next domain.RAGService, queries domain.AnalyticsRepository) *Service {
+	s := &Service{next: next, queries: queries, queue: make(chan pending, queueSize)}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for item := range s.queue {
+			s.write(item)
+		}
+	}()
+	return s
+}
+
+// Close para de aceitar registros e espera os pendentes serem gravados, por
+// até 10 segundos; depois disso, os que restam são descartados
+func (s *Service) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(drainTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("registros de consultas pendentes descartados após %s", drainTimeout)
+	}
+}
+
+// ProcessQuery implementa domain.RAGService
+func (s *Service) ProcessQuery(ctx context.Context, req domain.RAGRequest) (*domain.RAGResponse, error) {
+	start := time.Now()
+	resp, err := s.next.ProcessQuery(ctx, req)
+	s.recordQuery(ctx, req, resp, err, start)
+	return resp, err
+}
+
+// ProcessQueryStream implementa domain.RAGService
+func (s *Service) ProcessQueryStream(ctx context.Context, req domain.RAGRequest, onToken func(token string)) (*domain.RAGResponse, error) {
+	start := time.Now()
+	resp, err := s.next.ProcessQueryStream(ctx, req, onToken)
+	s.recordQuery(ctx, req, resp, err, start)
+	return resp, err
+}
+
+// SearchDocuments implementa domain.RAGService
+func (s *Service) SearchDocuments(ctx context.Context, query string, filter domain.SearchFilter) ([]domain.Document, error) {
+	start := time.Now()
+	docs, err := s.next.SearchDocuments(ctx, query, filter)
+	s.recordSearch(ctx, query, filter, len(docs), err, start)
+	return docs, err
+}
+
+// SearchWithFacets implementa domain.RAGService
+func (s *Service) SearchWithFacets(ctx context.Context, query string, filter domain.SearchFilter) (*domain.FacetedSearch, error) {
+	start := time.Now()
+	search, err := s.next.SearchWithFacets(ctx, query, filter)
+	results := 0
+	if search != nil {
+		results = search.Total
+	}
+	s.recordSearch(ctx, query, filter, results, err, start)
+	return search, err
+}
+
+// AddDocument implementa domain.RAGService
+func (s *Service) AddDocument(ctx context.Context, doc *domain.Document) error {
+	return s.next.AddDocument(ctx, doc)
+}
+
+// GetDocument implementa domain.RAGService
+func (s *Service) GetDocument(ctx context.Context, id string) (*domain.Document, error) {
+	return s.next.GetDocument(ctx, id)
+}
+
+// ListDocuments implementa domain.RAGService
+func (s *Service) ListDocuments(ctx context.Context, filter domain.DocumentFilter, cursor string, limit int) ([]domain.Document, string, error) {
+	return s.next.ListDocuments(ctx, filter, cursor, limit)
+}
+
+// UpdateDocument implementa domain.RAGService
+func (s *Service) UpdateDocument(ctx context.Context, id string, doc *domain.Document) error {
+	return s.next.UpdateDocument(ctx, id, doc)
+}
+
+// GetVersionHistory implementa domain.RAGService
+func (s *Service) GetVersionHistory(ctx context.Context, id string) ([]domain.DocumentVersion, error) {
+	return s.next.GetVersionHistory(ctx, id)
+}
+
+// Rollback implementa domain.RAGService
+func (s *Service) Rollback(ctx context.Context, id string, version int) (*domain.Document, error) {
+	return s.next.Rollback(ctx, id, version)
+}
+
+// DeleteDocument implementa domain.RAGService
+func (s *Service) DeleteDocument(ctx context.Context, id string) error {
+	return s.next.DeleteDocument(ctx, id)
+}
+
+// RestoreDocument implementa domain.RAGService
+func (s *Service) RestoreDocument(ctx context.Context, id string) error {
+	return s.next.RestoreDocument(ctx, id)
+}
+
+// ReembedDocument implementa domain.RAGService
+func (s *Service) ReembedDocument(ctx context.Context, id string) (int, error) {
+	return s.next.ReembedDocument(ctx, id)
+}
+
+// HealthCheck implementa domain.RAGService
+func (s *Service) HealthCheck(ctx context.Context) error {
+	return s.next.HealthCheck(ctx)
+}
+
+// recordQuery registra uma pergunta ao agente. Os resultados são as fontes
+// da resposta, e a pergunta fica sem resultados apenas quando o agente
+// consultou a base e não encontrou nada. Como no serviço, o tenant da
+// pergunta prevalece sobre o do contexto, para que as perguntas feitas fora
+// da API HTTP sejam registradas no tenant em que foram respondidas.
+func (s *Service) recordQuery(ctx context.Context, req domain.RAGRequest, resp *domain.RAGResponse, err error, start time.Time) {
+	if req.TenantID != "" && domain.ValidateTenantID(req.TenantID) == nil {
+		ctx = domain.WithTenant(ctx, req.TenantID)
+	}
+	record := domain.QueryRecord{Kind: domain.QueryKindAgent, Query: req.Query, Category: req.Category, Failed: err != nil}
+	if resp != nil {
+		record.Results = len(resp.Sources)
+		record.ZeroHit = err == nil && resp.UsedSearch && len(resp.Sources) == 0
+	}
+	s.record(ctx, record, start)
+}
+
+// recordSearch registra uma busca direta na base
+func (s *Service) recordSearch(ctx context.Context, query string, filter domain.SearchFilter, results int, err error, start time.Time) {
+	s.record(ctx, domain.QueryRecord{
+		Kind:     domain.QueryKindSearch,
+		Query:    query,
+		Category: filter.Category,
+		Results:  results,
+		ZeroHit:  err == nil && results == 0,
+		Failed:   err != nil,
+	}, start)
+}
+
+// record normaliza a consulta e a enfileira para gravação. A gravação não é
+// cancelada junto com a consulta, para registrar também as que excederam o
+// tempo. Com a fila cheia ou o serviço fechado, o registro é descartado.
+func (s *Service) record(ctx context.Context, record domain.QueryRecord, start time.Time) {
+	record.Query = normalizeQuery(record.Query)
+	if record.Query == "" {
+		return
+	}
+	record.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	record.CreatedAt = start
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.queue <- pending{ctx: context.WithoutCancel(ctx), record: record}:
+	default:
+		log.Printf("Aviso: fila de estatísticas cheia, registro da consulta descartado")
+	}
+}
+
+// write grava o registro enfileirado
+func (s *Service) write(item pending) {
+	ctx, cancel := context.WithTimeout(item.ctx, recordTimeout)
+	defer cancel()
+	if err := s.queries.Record(ctx, item.record); err != nil {
+		log.Printf("Aviso ao registrar estatísticas da consulta: %v", err)
+	}
+}
+
+// normalizeQuery deixa a consulta em minúsculas e com um espaço entre as
+// palavras, para que as repetições sejam agrupadas
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
@@ -0,0 +1,110 @@
+package analytics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// fakeService responde às perguntas sem consultar a base
+type fakeService struct {
+	domain.RAGService
+}
+
+func (fakeService) ProcessQuery(ctx context.Context, req domain.RAGRequest) (*domain.RAGResponse, error) {
+	return &domain.RAGResponse{Answer: "ok", UsedSearch: true}, nil
+}
+
+// recordingRepository guarda os registros e o tenant do contexto de cada um,
+// esperando release, quando informado, antes de gravar
+type recordingRepository struct {
+	domain.AnalyticsRepository
+	release chan struct{}
+
+	mu      sync.Mutex
+	records []domain.QueryRecord
+	tenants []string
+}
+
+func (r *recordingRepository) Record(ctx context.Context, record domain.QueryRecord) error {
+	if r.release != nil {
+		<-r.release
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+	r.tenants = append(r.tenants, domain.TenantFromContext(ctx))
+	return nil
+}
+
+func TestServiceRecordsRequestTenant(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctxTenant string
+		reqTenant string
+		want      string
+	}{
+		{name: "tenant do contexto", ctxTenant: "acme", want: "acme"},
+		{name: "tenant da pergunta sem tenant no contexto", reqTenant: "globex", want: "globex"},
+		{name: "tenant da pergunta prevalece", ctxTenant: "acme", reqTenant: "globex", want: "globex"},
+		{name: "tenant inválido na pergunta", ctxTenant: "acme", reqTenant: "a b", want: "acme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &recordingRepository{}
+			svc := NewService(fakeService{}, repo)
+			ctx := domain.WithTenant(context.Background(), tt.ctxTenant)
+			if _, err := svc.ProcessQuery(ctx, domain.RAGRequest{Query: "  Como  usar Goroutines? ", TenantID: tt.reqTenant}); err != nil {
+				t.Fatalf("ProcessQuery: %v", err)
+			}
+			if err := svc.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			if len(repo.records) != 1 {
+				t.Fatalf("registros = %d, esperado 1", len(repo.records))
+			}
+			if got := repo.tenants[0]; got != tt.want {
+				t.Errorf("tenant = %q, esperado %q", got, tt.want)
+			}
+			if got, want := repo.records[0].Query, "como usar goroutines?"; got != want {
+				t.Errorf("consulta = %q, esperado %q", got, want)
+			}
+		})
+	}
+}
+
+func TestServiceRecordsInBackground(t *testing.T) {
+	repo := &recordingRepository{release: make(chan struct{})}
+	svc := NewService(fakeService{}, repo)
+
+	// A resposta não espera a gravação, que está bloqueada
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		svc.ProcessQuery(context.Background(), domain.RAGRequest{Query: "goroutines"})
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ProcessQuery esperou a gravação do registro")
+	}
+
+	// Close espera os registros pendentes
+	close(repo.release)
+	if err := svc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if len(repo.records) != 1 {
+		t.Errorf("registros = %d, esperado 1", len(repo.records))
+	}
+
+	// Depois de fechado, os registros são descartados
+	svc.ProcessQuery(context.Background(), domain.RAGRequest{Query: "canais"})
+	if len(repo.records) != 1 {
+		t.Errorf("registros após Close = %d, esperado 1", len(repo.records))
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Limites de GET /v1/admin/analytics
+const (
+	defaultAnalyticsDays  = 30
+	maxAnalyticsDays      = 365
+	defaultAnalyticsLimit = 10
+	maxAnalyticsLimit     = 100
+)
+
+// handleAnalytics retorna as estatísticas das consultas do tenant da
+// requisição nos últimos days dias, com até limit itens em cada lista
+func (h *Handler) handleAnalytics(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	days := defaultAnalyticsDays
+	if value := query.Get("days"); value != "" {
+		var err error
+		if days, err = strconv.Atoi(value); err != nil || days <= 0 || days > maxAnalyticsDays {
+			writeServiceError(w, &domain.ValidationError{Field: "days", Message: "deve ser um número de 1 a 365"})
+			return
+		}
+	}
+	limit := defaultAnalyticsLimit
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			writeServiceError(w, &domain.ValidationError{Field: "limit", Message: "deve ser um número positivo"})
+			return
+		}
+	}
+	limit = min(limit, maxAnalyticsLimit)
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+	stats, err := h.analytics.Stats(r.Context(), since, limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAnalyticsResponse(since, stats))
+}
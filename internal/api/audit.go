@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Limites de GET /v1/admin/audit
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// handleListAudit retorna uma página do log de auditoria do tenant da
+// requisição, da entrada mais recente para a mais antiga, com os filtros
+// action, actor, request_id, resource e o período since/until (RFC 3339)
+func (h *Handler) handleListAudit(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := defaultAuditLimit
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			writeServiceError(w, &domain.ValidationError{Field: "limit", Message: "deve ser um número positivo"})
+			return
+		}
+	}
+	limit = min(limit, maxAuditLimit)
+
+	filter := domain.AuditFilter{
+		Action:    query.Get("action"),
+		Actor:     query.Get("actor"),
+		RequestID: query.Get("request_id"),
+		Resource:  query.Get("resource"),
+	}
+	for name, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeServiceError(w, &domain.ValidationError{Field: name, Message: "deve ser uma data no formato RFC 3339"})
+			return
+		}
+		*dst = t
+	}
+
+	entries, next, err := h.audit.List(r.Context(), filter, query.Get("cursor"), limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newAuditListResponse(entries, next))
+}
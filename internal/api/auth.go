@@ -0,0 +1,240 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// adminPrefix é o início das rotas de administração, autenticadas pela chave
+// de administração em vez das chaves de API
+const adminPrefix = "/v1/admin/"
+
+// publicPaths são as rotas acessíveis sem chave de API
+var publicPaths = map[string]bool{
+	"/livez":        true,
+	"/readyz":       true,
+	"/healthz":      true,
+	"/openapi.json": true,
+}
+
+// requestIDHeader é o cabeçalho com o ID da requisição, gerado quando o
+// cliente não o envia e registrado no log de auditoria
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limita o tamanho do ID de requisição enviado pelo cliente
+const maxRequestIDLength = 128
+
+// adminActor é o ator registrado no log de auditoria nas rotas de administração
+const adminActor = "admin"
+
+// apiKeyContextKey identifica a chave de API da requisição no contexto
+type apiKeyContextKey struct{}
+
+// authenticate define o tenant e o ID da requisição e, com as chaves de API
+// habilitadas, exige uma chave válida e define o ator do log de auditoria. O tenant vem de X-Tenant-ID ou, para
+// clientes WebSocket que não enviam cabeçalhos, de ?tenant_id=; sem nenhum
+// dos dois, a requisição usa o tenant padrão. Com uma chave de API, o tenant
+// é o da chave e o informado na requisição, se houver, precisa ser o mesmo.
+func (h *Handler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID := r.Header.Get(tenantHeader)
+		if tenantID == "" {
+			tenantID = r.URL.Query().Get("tenant_id")
+		}
+		if err := domain.ValidateTenantID(tenantID); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		requestID := r.Header.Get(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = rand.Text()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+
+		ctx := domain.WithRequestID(r.Context(), requestID)
+		switch {
+		case h.apiKeys == nil || publicPaths[r.URL.Path]:
+		case strings.HasPrefix(r.URL.Path, adminPrefix):
+			if !h.isAdmin(r) {
+				writeError(w, http.StatusUnauthorized, CodeUnauthorized, "chave de administração inválida")
+				return
+			}
+			ctx = domain.WithActor(ctx, adminActor)
+		default:
+			key, err := h.findAPIKey(ctx, r)
+			if err != nil {
+				if errors.Is(err, domain.ErrAPIKeyNotFound) {
+					w.Header().Set("WWW-Authenticate", "Bearer")
+					writeError(w, http.StatusUnauthorized, CodeUnauthorized, "chave de API ausente ou inválida")
+					return
+				}
+				log.Printf("Erro ao verificar chave de API: %v", err)
+				writeError(w, http.StatusInternalServerError, CodeInternal, "erro interno")
+				return
+			}
+			if tenantID != "" && tenantID != key.TenantID {
+				log.Printf("Chave de API %s (usuário %s) recusada no tenant %q", key.Prefix, key.UserID, tenantID)
+				writeError(w, http.StatusForbidden, CodeForbidden, "a chave de API não tem acesso a este tenant")
+				return
+			}
+			tenantID = key.TenantID
+			ctx = context.WithValue(ctx, apiKeyContextKey{}, key)
+			ctx = domain.WithActor(ctx, keyActor(key))
+		}
+
+		next.ServeHTTP(w, r.WithContext(domain.WithTenant(ctx, tenantID)))
+	})
+}
+
+// validRequestID aceita IDs de requisição não vazios, de até
+// maxRequestIDLength caracteres ASCII visíveis, para que o valor enviado pelo
+// cliente possa ser guardado e devolvido no cabeçalho com segurança
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// keyActor identifica no log de auditoria quem usa a chave: o usuário dono
+// da chave ou, em chaves sem usuário, o prefixo da chave
+func keyActor(key *domain.APIKey) string {
+	if key.UserID != "" {
+		return key.UserID
+	}
+	return "key:" + key.Prefix
+}
+
+// findAPIKey busca a chave enviada em "Authorization: Bearer" ou, para
+// clientes WebSocket, em ?api_key=. Chaves ausentes ou revogadas resultam em
+// domain.ErrAPIKeyNotFound.
+func (h *Handler) findAPIKey(ctx context.Context, r *http.Request) (*domain.APIKey, error) {
+	secret := bearerToken(r)
+	if secret == "" {
+		secret = r.URL.Query().Get("api_key")
+	}
+	if secret == "" {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	key, err := h.apiKeys.FindByHash(ctx, domain.HashAPIKey(secret))
+	if err != nil {
+		return nil, err
+	}
+	if key.Revoked() {
+		log.Printf("Chave de API revogada usada: %s (usuário %s)", key.Prefix, key.UserID)
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+// isAdmin verifica se a requisição traz a chave de administração
+func (h *Handler) isAdmin(r *http.Request) bool {
+	token := bearerToken(r)
+	return h.adminKey != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.adminKey)) == 1
+}
+
+// bearerToken retorna o token do cabeçalho Authorization, ou vazio
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// requestUserID retorna o usuário ao qual a pergunta é atribuída: o dono da
+// chave de API, quando a requisição foi autenticada, ou o informado pelo
+// cliente
+func requestUserID(ctx context.Context, userID string) string {
+	if key, ok := ctx.Value(apiKeyContextKey{}).(*domain.APIKey); ok {
+		return key.UserID
+	}
+	return userID
+}
+
+// requestGroups retorna os grupos usados na ACL dos documentos: os da chave
+// de API, quando a requisição foi autenticada, ou os informados pelo cliente.
+// Sem chaves de API, o controle de acesso depende de quem chama a API
+// repassar os grupos do usuário.
+func requestGroups(ctx context.Context, groups []string) []string {
+	if key, ok := ctx.Value(apiKeyContextKey{}).(*domain.APIKey); ok {
+		return key.Groups
+	}
+	return groups
+}
+
+// readerContext restringe as leituras de documentos à ACL de quem chama: os
+// grupos da chave de API ou, sem chaves de API, os informados em ?groups=
+// (repetido para cada grupo). As rotas de administração leem todos os
+// documentos.
+func readerContext(r *http.Request) context.Context {
+	ctx := r.Context()
+	if strings.HasPrefix(r.URL.Path, adminPrefix) {
+		return ctx
+	}
+	return domain.WithReaderGroups(ctx, requestGroups(ctx, r.URL.Query()["groups"]))
+}
+
+// handleCreateAPIKey cria uma chave de API. O segredo só é devolvido nesta
+// resposta.
+func (h *Handler) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req APIKeyRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "corpo da requisição inválido")
+		return
+	}
+
+	key, secret, err := domain.NewAPIKey(req.Name, req.UserID, req.TenantID, req.Groups)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if err := h.apiKeys.Create(r.Context(), key); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	log.Printf("Chave de API criada: %s (usuário %s)", key.Prefix, key.UserID)
+	writeJSON(w, http.StatusCreated, CreatedAPIKeyResponse{APIKeyResponse: newAPIKeyResponse(*key), Key: secret})
+}
+
+// handleListAPIKeys lista as chaves de API, sem os segredos
+func (h *Handler) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.apiKeys.List(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp := APIKeyListResponse{Keys: make([]APIKeyResponse, 0, len(keys))}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, newAPIKeyResponse(key))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleRevokeAPIKey revoga uma chave de API
+func (h *Handler) handleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.apiKeys.Revoke(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	log.Printf("Chave de API revogada: %s", id)
+	w.WriteHeader(http.StatusNoContent)
+}
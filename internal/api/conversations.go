@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// handleGetConversation retorna o histórico de uma sessão do tenant da
+// requisição, com o resumo dos turnos mais antigos. Conversas com dono só
+// são encontradas pelo usuário da chave de API ou, sem chaves de API, pelo
+// informado em ?user_id=.
+func (h *Handler) handleGetConversation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	conv, err := h.conversations.FindBySessionID(ctx, r.PathValue("session_id"))
+	if err == nil && !conv.AccessibleTo(requestUserID(ctx, r.URL.Query().Get("user_id"))) {
+		err = domain.ErrConversationNotFound
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newConversationResponse(*conv))
+}
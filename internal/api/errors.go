@@ -0,0 +1,124 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Códigos das respostas de erro, estáveis para que os clientes possam tratar
+// cada caso sem depender das mensagens
+const (
+	CodeInvalidRequest       = "invalid_request"           // Corpo ou parâmetro malformado
+	CodeValidation           = "validation_failed"         // Campos inválidos, listados em fields
+	CodeInvalidCursor        = "invalid_cursor"            // Cursor de paginação inválido
+	CodeUnauthorized         = "unauthorized"              // Chave de API ou de administração ausente ou inválida
+	CodeForbidden            = "forbidden"                 // Chave sem acesso ao tenant
+	CodeDocumentNotFound     = "document_not_found"        // Documento inexistente ou excluído
+	CodeVersionNotFound      = "version_not_found"         // Versão inexistente no histórico
+	CodeAPIKeyNotFound       = "api_key_not_found"         // Chave de API inexistente
+	CodeJobNotFound          = "job_not_found"             // Job de ingestão inexistente
+	CodeConversationNotFound = "conversation_not_found"    // Sessão sem histórico no tenant
+	CodeVersionConflict      = "version_conflict"          // Documento alterado por outra operação
+	CodeDuplicateDocument    = "duplicate_document"        // Conteúdo já indexado em outro documento
+	CodeRateLimited          = "rate_limited"              // Limite de perguntas excedido; veja Retry-After
+	CodePromptTooLarge       = "prompt_too_large"          // Pergunta maior que o orçamento de contexto
+	CodeContentBlocked       = "content_blocked"           // Pergunta ou resposta bloqueada pela moderação
+	CodeStructuredAnswer     = "invalid_structured_answer" // Resposta fora do response_schema
+	CodeEmbeddingsDisabled   = "embeddings_disabled"       // Operação exige um cliente de embeddings
+	CodeFacetsUnsupported    = "facets_unsupported"        // Banco sem busca com facets
+	CodeLLMUnavailable       = "llm_unavailable"           // Provedor de LLM indisponível ou sem cota
+	CodeTimeout              = "timeout"                   // Pergunta interrompida pelo tempo limite
+	CodeStreamingUnsupported = "streaming_unsupported"     // Conexão não permite respostas em streaming
+	CodeInternal             = "internal_error"            // Falha inesperada, registrada no log do servidor
+)
+
+// apiError é um erro do serviço convertido para a resposta da API
+type apiError struct {
+	status     int
+	code       string
+	message    string
+	fields     domain.ValidationErrors // Campos inválidos, nos erros de validação
+	retryAfter time.Duration           // Espera sugerida, no limite de perguntas
+}
+
+// classifyError converte os erros do domínio no status HTTP, no código e na
+// mensagem da resposta. Erros desconhecidos resultam em CodeInternal, sem
+// expor a mensagem original.
+func classifyError(err error) apiError {
+	var validationErrs domain.ValidationErrors
+	var validationErr *domain.ValidationError
+	var rateLimitErr *domain.RateLimitError
+	var timeout *domain.TimeoutResult
+	switch {
+	case errors.As(err, &validationErrs):
+		return apiError{status: http.StatusBadRequest, code: CodeValidation, message: validationErrs.Error(), fields: validationErrs}
+	case errors.As(err, &validationErr):
+		return apiError{status: http.StatusBadRequest, code: CodeValidation, message: validationErr.Error(), fields: domain.ValidationErrors{validationErr}}
+	case errors.As(err, &rateLimitErr):
+		return apiError{status: http.StatusTooManyRequests, code: CodeRateLimited, message: rateLimitErr.Error(), retryAfter: rateLimitErr.RetryAfter}
+	case errors.As(err, &timeout):
+		return apiError{status: http.StatusGatewayTimeout, code: CodeTimeout, message: timeout.Error()}
+	case errors.Is(err, domain.ErrDocumentNotFound):
+		return apiError{status: http.StatusNotFound, code: CodeDocumentNotFound, message: err.Error()}
+	case errors.Is(err, domain.ErrVersionNotFound):
+		return apiError{status: http.StatusNotFound, code: CodeVersionNotFound, message: err.Error()}
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		return apiError{status: http.StatusNotFound, code: CodeAPIKeyNotFound, message: err.Error()}
+	case errors.Is(err, domain.ErrJobNotFound):
+		return apiError{status: http.StatusNotFound, code: CodeJobNotFound, message: err.Error()}
+	case errors.Is(err, domain.ErrConversationNotFound):
+		return apiError{status: http.StatusNotFound, code: CodeConversationNotFound, message: err.Error()}
+	case errors.Is(err, domain.ErrVersionConflict):
+		return apiError{status: http.StatusConflict, code: CodeVersionConflict, message: err.Error()}
+	case errors.Is(err, domain.ErrDuplicateDocument):
+		return apiError{status: http.StatusConflict, code: CodeDuplicateDocument, message: err.Error()}
+	case errors.Is(err, domain.ErrInvalidCursor):
+		return apiError{status: http.StatusBadRequest, code: CodeInvalidCursor, message: err.Error()}
+	case errors.Is(err, domain.ErrPromptTooLarge):
+		return apiError{status: http.StatusRequestEntityTooLarge, code: CodePromptTooLarge, message: err.Error()}
+	case errors.Is(err, domain.ErrContentBlocked):
+		return apiError{status: http.StatusUnprocessableEntity, code: CodeContentBlocked, message: err.Error()}
+	case errors.Is(err, domain.ErrStructuredAnswer):
+		return apiError{status: http.StatusBadGateway, code: CodeStructuredAnswer, message: err.Error()}
+	case errors.Is(err, domain.ErrEmbeddingsDisabled):
+		return apiError{status: http.StatusNotImplemented, code: CodeEmbeddingsDisabled, message: err.Error()}
+	case errors.Is(err, domain.ErrFacetsUnsupported):
+		return apiError{status: http.StatusNotImplemented, code: CodeFacetsUnsupported, message: err.Error()}
+	case errors.Is(err, domain.ErrLLMUnavailable), errors.Is(err, domain.ErrLLMQuotaExceeded):
+		// A falta de cota é um problema de configuração, não do cliente
+		return apiError{status: http.StatusServiceUnavailable, code: CodeLLMUnavailable, message: domain.ErrLLMUnavailable.Error()}
+	default:
+		return apiError{status: http.StatusInternalServerError, code: CodeInternal, message: "erro interno"}
+	}
+}
+
+// response monta o corpo da resposta de erro
+func (e apiError) response() ErrorResponse {
+	resp := newErrorResponse(e.status, e.code, e.message)
+	if len(e.fields) > 0 {
+		resp.Field = e.fields[0].Field
+		resp.Fields = make([]FieldError, len(e.fields))
+		for i, err := range e.fields {
+			resp.Fields[i] = FieldError{Field: err.Field, Message: err.Message}
+		}
+	}
+	return resp
+}
+
+// newErrorResponse monta o corpo de um erro sem campos inválidos
+func newErrorResponse(status int, code, message string) ErrorResponse {
+	return ErrorResponse{Code: code, Message: message, Error: message, Retryable: retryable(status)}
+}
+
+// retryable informa se a mesma requisição pode ser repetida mais tarde: o
+// limite de perguntas, as falhas do LLM e o tempo limite são temporários
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
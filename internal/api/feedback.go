@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Limites de GET /v1/admin/feedback
+const (
+	defaultFeedbackLimit = 100
+	maxFeedbackLimit     = 1000
+)
+
+// maxFeedbackComment é o tamanho máximo do comentário, em caracteres
+const maxFeedbackComment = 4000
+
+// maxFeedbackSources limita as fontes de uma avaliação, cada uma conferida
+// na base
+const maxFeedbackSources = 50
+
+// handleCreateFeedback guarda a avaliação de uma resposta, com a pergunta, a
+// resposta e as fontes enviadas pelo cliente que existem na base
+func (h *Handler) handleCreateFeedback(w http.ResponseWriter, r *http.Request) {
+	var req FeedbackRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "corpo da requisição inválido")
+		return
+	}
+	if err := req.validate(); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	feedback := req.toDomain(requestUserID(r.Context(), req.UserID))
+	sources, err := h.knownSources(r.Context(), feedback.Sources)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	feedback.Sources = sources
+	if err := h.feedback.Record(r.Context(), feedback); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newFeedbackResponse(*feedback))
+}
+
+// knownSources mantém apenas as fontes que existem na base, uma vez cada. As
+// avaliações movem os documentos citados na ordenação das buscas, então IDs
+// inventados ou repetidos pelo cliente são descartados. Com uma chave de
+// API, valem apenas os documentos que os grupos da chave podem ler.
+func (h *Handler) knownSources(ctx context.Context, sources []domain.FeedbackSource) ([]domain.FeedbackSource, error) {
+	if key, ok := ctx.Value(apiKeyContextKey{}).(*domain.APIKey); ok {
+		ctx = domain.WithReaderGroups(ctx, key.Groups)
+	}
+	var known []domain.FeedbackSource
+	seen := map[string]bool{}
+	for _, source := range sources {
+		if seen[source.ID] {
+			continue
+		}
+		seen[source.ID] = true
+		if _, err := h.service.GetDocument(ctx, source.ID); err != nil {
+			if errors.Is(err, domain.ErrDocumentNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		known = append(known, source)
+	}
+	return known, nil
+}
+
+// handleListFeedback retorna uma página das avaliações do tenant da
+// requisição, da mais recente para a mais antiga, com os filtros rating,
+// user_id, session_id e o período since/until (RFC 3339), para a triagem
+// dos problemas de qualidade
+func (h *Handler) handleListFeedback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := defaultFeedbackLimit
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			writeServiceError(w, &domain.ValidationError{Field: "limit", Message: "deve ser um número positivo"})
+			return
+		}
+	}
+	limit = min(limit, maxFeedbackLimit)
+
+	filter := domain.FeedbackFilter{
+		Rating:    query.Get("rating"),
+		UserID:    query.Get("user_id"),
+		SessionID: query.Get("session_id"),
+	}
+	if filter.Rating != "" && filter.Rating != domain.FeedbackUp && filter.Rating != domain.FeedbackDown {
+		writeServiceError(w, &domain.ValidationError{Field: "rating", Message: "deve ser up ou down"})
+		return
+	}
+	for name, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeServiceError(w, &domain.ValidationError{Field: name, Message: "deve ser uma data no formato RFC 3339"})
+			return
+		}
+		*dst = t
+	}
+
+	feedback, next, err := h.feedback.List(r.Context(), filter, query.Get("cursor"), limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp := FeedbackListResponse{Feedback: make([]FeedbackResponse, 0, len(feedback)), NextCursor: next}
+	for _, f := range feedback {
+		resp.Feedback = append(resp.Feedback, newFeedbackResponse(f))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// validate confere a avaliação, a pergunta, a resposta e as fontes
+func (r FeedbackRequest) validate() error {
+	switch {
+	case r.Rating != domain.FeedbackUp && r.Rating != domain.FeedbackDown:
+		return &domain.ValidationError{Field: "rating", Message: "deve ser up ou down"}
+	case strings.TrimSpace(r.Query) == "":
+		return &domain.ValidationError{Field: "query", Message: "obrigatório"}
+	case strings.TrimSpace(r.Answer) == "":
+		return &domain.ValidationError{Field: "answer", Message: "obrigatório"}
+	case len([]rune(r.Comment)) > maxFeedbackComment:
+		return &domain.ValidationError{Field: "comment", Message: "deve ter no máximo " + strconv.Itoa(maxFeedbackComment) + " caracteres"}
+	case len(r.Sources) > maxFeedbackSources:
+		return &domain.ValidationError{Field: "sources", Message: "deve ter no máximo " + strconv.Itoa(maxFeedbackSources) + " itens"}
+	}
+	for i, source := range r.Sources {
+		if source.ID == "" {
+			return &domain.ValidationError{Field: "sources[" + strconv.Itoa(i) + "].id", Message: "obrigatório"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,514 @@
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// queryTimeout limita o tempo de processamento de uma pergunta
+const queryTimeout = 2 * time.Minute
+
+// openAPISpec é a especificação OpenAPI 3 da API, servida em /openapi.json
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// maxBodySize limita o tamanho do corpo das requisições
+const maxBodySize = 1 << 20 // 1 MB
+
+// tenantHeader é o cabeçalho que identifica o tenant da requisição
+const tenantHeader = "X-Tenant-ID"
+
+// Handler expõe o serviço RAG via HTTP
+type Handler struct {
+	service        domain.RAGService
+	allowedOrigins []string // Origens, além da própria, aceitas no chat via WebSocket
+
+	apiKeys  domain.APIKeyRepository // Chaves de API exigidas nas requisições, se definido
+	adminKey string                  // Chave das rotas de administração das chaves de API
+
+	quality   domain.QualityRepository // Respostas avaliadas por amostragem, se definido
+	jobs      domain.JobRepository     // Fila de ingestão em segundo plano, se definido
+	audit     domain.AuditRepository   // Log de auditoria consultado pelos administradores, se definido
+	readiness domain.ReadinessChecker  // Verificações de /readyz; sem ele, apenas a base
+
+	conversations domain.ConversationRepository // Histórico das sessões, se definido
+	feedback      domain.FeedbackRepository     // Avaliações das respostas pelos usuários, se definido
+	analytics     domain.AnalyticsRepository    // Estatísticas das consultas, se definido
+}
+
+// Option configura o handler HTTP
+type Option func(*Handler)
+
+// WithAllowedOrigins permite conexões WebSocket de outras origens (por
+// exemplo, "app.example.com" ou "localhost:*"), usadas por frontends de chat
+// hospedados em outro domínio
+func WithAllowedOrigins(patterns ...string) Option {
+	return func(h *Handler) {
+		h.allowedOrigins = patterns
+	}
+}
+
+// WithAPIKeys exige uma chave de API válida em todas as rotas, exceto as
+// sondas (/livez, /readyz e /healthz) e /openapi.json, e atribui as
+// perguntas ao usuário dono da chave. As rotas /v1/admin/api-keys, que
+// criam e revogam chaves, /v1/admin/documents, de manutenção dos
+// documentos de qualquer tenant, /v1/admin/audit, do log de auditoria,
+// /v1/admin/feedback, das avaliações dos usuários, e /v1/admin/analytics,
+// das estatísticas das consultas, são autenticadas pela chave de
+// administração e só existem quando ela é informada.
+func WithAPIKeys(keys domain.APIKeyRepository, adminKey string) Option {
+	return func(h *Handler) {
+		h.apiKeys = keys
+		h.adminKey = adminKey
+	}
+}
+
+// WithQuality expõe em GET /v1/quality as médias diárias das notas das
+// respostas avaliadas por amostragem
+func WithQuality(repo domain.QualityRepository) Option {
+	return func(h *Handler) {
+		h.quality = repo
+	}
+}
+
+// WithJobs habilita a ingestão em segundo plano: POST /v1/ingest enfileira
+// os documentos em um job, processado pelos workers do pacote jobs, e
+// GET /v1/jobs/{id} informa o progresso
+func WithJobs(repo domain.JobRepository) Option {
+	return func(h *Handler) {
+		h.jobs = repo
+	}
+}
+
+// WithAudit expõe em GET /v1/admin/audit o log de auditoria do tenant da
+// requisição. Como as demais rotas de administração, a rota só é registrada
+// com a chave de administração de WithAPIKeys, que ela exige.
+func WithAudit(repo domain.AuditRepository) Option {
+	return func(h *Handler) {
+		h.audit = repo
+	}
+}
+
+// WithFeedback recebe em POST /v1/feedback as avaliações das respostas
+// pelos usuários e as lista em GET /v1/admin/feedback, para a triagem dos
+// problemas de qualidade. A listagem só é registrada com a chave de
+// administração de WithAPIKeys, que ela exige.
+func WithFeedback(repo domain.FeedbackRepository) Option {
+	return func(h *Handler) {
+		h.feedback = repo
+	}
+}
+
+// WithAnalytics expõe em GET /v1/admin/analytics as estatísticas das
+// consultas do tenant da requisição. A rota só é registrada com a chave de
+// administração de WithAPIKeys, que ela exige.
+func WithAnalytics(repo domain.AnalyticsRepository) Option {
+	return func(h *Handler) {
+		h.analytics = repo
+	}
+}
+
+// WithConversations expõe em GET /v1/conversations/{session_id} o histórico
+// de uma sessão, com o resumo dos turnos mais antigos
+func WithConversations(repo domain.ConversationRepository) Option {
+	return func(h *Handler) {
+		h.conversations = repo
+	}
+}
+
+// WithReadiness define as dependências verificadas em /readyz. Sem esta
+// opção, apenas o acesso à base é verificado.
+func WithReadiness(checker domain.ReadinessChecker) Option {
+	return func(h *Handler) {
+		h.readiness = checker
+	}
+}
+
+// NewHandler cria um novo handler HTTP para o serviço RAG
+func NewHandler(service domain.RAGService, opts ...Option) *Handler {
+	h := &Handler{service: service}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Routes registra as rotas da API
+func (h *Handler) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/query", h.handleQuery)
+	mux.HandleFunc("POST /v1/query/stream", h.handleQueryStream)
+	mux.HandleFunc("POST /v1/search", h.handleSearch)
+	mux.HandleFunc("POST /v1/documents", h.handleCreateDocument)
+	mux.HandleFunc("GET /v1/documents", h.handleListDocuments)
+	mux.HandleFunc("GET /v1/documents/{id}", h.handleGetDocument)
+	mux.HandleFunc("PUT /v1/documents/{id}", h.handleUpdateDocument)
+	mux.HandleFunc("DELETE /v1/documents/{id}", h.handleDeleteDocument)
+	mux.HandleFunc("GET /v1/documents/{id}/versions", h.handleVersionHistory)
+	mux.HandleFunc("POST /v1/documents/{id}/versions/{version}/rollback", h.handleRollback)
+	mux.HandleFunc("POST /v1/documents/{id}/restore", h.handleRestoreDocument)
+	mux.HandleFunc("GET /ws/chat", h.handleChat)
+	mux.HandleFunc("GET /livez", h.handleLive)
+	mux.HandleFunc("GET /readyz", h.handleReady)
+	mux.HandleFunc("GET /healthz", h.handleReady) // Mantida por compatibilidade
+	mux.HandleFunc("GET /openapi.json", h.handleOpenAPI)
+	if h.quality != nil {
+		mux.HandleFunc("GET /v1/quality", h.handleQualityTrend)
+	}
+	if h.jobs != nil {
+		mux.HandleFunc("POST /v1/ingest", h.handleCreateJob)
+		mux.HandleFunc("GET /v1/jobs/{id}", h.handleGetJob)
+	}
+	if h.feedback != nil {
+		mux.HandleFunc("POST /v1/feedback", h.handleCreateFeedback)
+	}
+	if h.conversations != nil {
+		mux.HandleFunc("GET /v1/conversations/{session_id}", h.handleGetConversation)
+	}
+	// As rotas de administração só existem com a chave de administração, que
+	// as autentica
+	if h.apiKeys != nil && h.adminKey != "" {
+		mux.HandleFunc("POST /v1/admin/api-keys", h.handleCreateAPIKey)
+		mux.HandleFunc("GET /v1/admin/api-keys", h.handleListAPIKeys)
+		mux.HandleFunc("DELETE /v1/admin/api-keys/{id}", h.handleRevokeAPIKey)
+		// Manutenção da base com a chave de administração, em qualquer tenant
+		mux.HandleFunc("POST /v1/admin/documents", h.handleCreateDocument)
+		mux.HandleFunc("GET /v1/admin/documents/{id}", h.handleGetDocument)
+		mux.HandleFunc("PUT /v1/admin/documents/{id}", h.handleUpdateDocument)
+		mux.HandleFunc("DELETE /v1/admin/documents/{id}", h.handleDeleteDocument)
+		mux.HandleFunc("POST /v1/admin/documents/{id}/reembed", h.handleReembedDocument)
+		if h.audit != nil {
+			mux.HandleFunc("GET /v1/admin/audit", h.handleListAudit)
+		}
+		if h.feedback != nil {
+			mux.HandleFunc("GET /v1/admin/feedback", h.handleListFeedback)
+		}
+		if h.analytics != nil {
+			mux.HandleFunc("GET /v1/admin/analytics", h.handleAnalytics)
+		}
+	}
+	return h.authenticate(mux)
+}
+
+// handleQuery processa uma pergunta ao agente
+func (h *Handler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	var req QueryRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "corpo da requisição inválido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), queryTimeout)
+	defer cancel()
+
+	resp, err := h.service.ProcessQuery(ctx, req.toDomain(requestUserID(ctx, req.UserID), requestGroups(ctx, req.Groups)))
+	if err != nil {
+		// Em caso de timeout, devolve o que foi obtido até o momento
+		var timeout *domain.TimeoutResult
+		if errors.As(err, &timeout) {
+			writeJSON(w, http.StatusGatewayTimeout, newTimeoutResponse(timeout))
+			return
+		}
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newQueryResponse(resp))
+}
+
+// handleSearch busca documentos diretamente na base, sem o agente, e conta
+// os encontrados por categoria, tag e mês de criação
+func (h *Handler) handleSearch(w http.ResponseWriter, r *http.Request) {
+	var req SearchRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "corpo da requisição inválido")
+		return
+	}
+
+	ctx := r.Context()
+	search, err := h.service.SearchWithFacets(ctx, req.Query, req.filter(requestGroups(ctx, req.Groups)))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newSearchResponse(search))
+}
+
+// handleQueryStream processa uma pergunta ao agente enviando a resposta via
+// Server-Sent Events: um evento "token" para cada trecho gerado e, ao final,
+// um evento "done" com a resposta completa ou "error" em caso de falha
+func (h *Handler) handleQueryStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, CodeStreamingUnsupported, "streaming não suportado")
+		return
+	}
+
+	var req QueryRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "corpo da requisição inválido")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), queryTimeout)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	resp, err := h.service.ProcessQueryStream(ctx, req.toDomain(requestUserID(ctx, req.UserID), requestGroups(ctx, req.Groups)), func(token string) {
+		writeEvent(w, "token", TokenEvent{Content: token})
+		flusher.Flush()
+	})
+	if err != nil {
+		var timeout *domain.TimeoutResult
+		if errors.As(err, &timeout) {
+			writeEvent(w, "error", newTimeoutResponse(timeout))
+		} else {
+			e := classifyError(err)
+			if e.code == CodeInternal {
+				log.Printf("Erro no streaming: %v", err)
+			}
+			writeEvent(w, "error", e.response())
+		}
+		flusher.Flush()
+		return
+	}
+
+	writeEvent(w, "done", newQueryResponse(resp))
+	flusher.Flush()
+}
+
+// handleCreateDocument insere um novo documento na base
+func (h *Handler) handleCreateDocument(w http.ResponseWriter, r *http.Request) {
+	var req DocumentRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "corpo da requisição inválido")
+		return
+	}
+
+	doc := req.toDomain()
+	if err := h.service.AddDocument(r.Context(), doc); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newDocumentResponse(*doc))
+}
+
+// handleGetDocument busca um documento pelo ID
+func (h *Handler) handleGetDocument(w http.ResponseWriter, r *http.Request) {
+	doc, err := h.service.GetDocument(readerContext(r), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newDocumentResponse(*doc))
+}
+
+// handleUpdateDocument substitui o conteúdo de um documento, guardando a
+// versão anterior
+func (h *Handler) handleUpdateDocument(w http.ResponseWriter, r *http.Request) {
+	var req DocumentRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "corpo da requisição inválido")
+		return
+	}
+
+	doc := req.toDomain()
+	if err := h.service.UpdateDocument(readerContext(r), r.PathValue("id"), doc); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newDocumentResponse(*doc))
+}
+
+// handleVersionHistory lista as versões de um documento
+func (h *Handler) handleVersionHistory(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	versions, err := h.service.GetVersionHistory(readerContext(r), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newDocumentVersionsResponse(id, versions))
+}
+
+// handleRollback volta um documento a uma versão anterior
+func (h *Handler) handleRollback(w http.ResponseWriter, r *http.Request) {
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil || version <= 0 {
+		writeServiceError(w, &domain.ValidationError{Field: "version", Message: "deve ser um número positivo"})
+		return
+	}
+
+	doc, err := h.service.Rollback(readerContext(r), r.PathValue("id"), version)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newDocumentResponse(*doc))
+}
+
+// handleDeleteDocument exclui logicamente um documento
+func (h *Handler) handleDeleteDocument(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteDocument(readerContext(r), r.PathValue("id")); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRestoreDocument restaura um documento excluído logicamente
+func (h *Handler) handleRestoreDocument(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RestoreDocument(readerContext(r), r.PathValue("id")); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleReembedDocument gera novamente o embedding de um documento (ou dos
+// seus chunks) com o cliente de embeddings atual
+func (h *Handler) handleReembedDocument(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	embedded, err := h.service.ReembedDocument(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ReembedResponse{ID: id, Embedded: embedded})
+}
+
+// handleListDocuments lista os documentos da base em páginas. O parâmetro
+// cursor recebe o next_cursor da página anterior e include_deleted=true
+// inclui os documentos excluídos logicamente.
+func (h *Handler) handleListDocuments(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			writeServiceError(w, &domain.ValidationError{Field: "limit", Message: "deve ser um número positivo"})
+			return
+		}
+	}
+
+	filter := domain.DocumentFilter{
+		Category:       query.Get("category"),
+		IncludeDeleted: query.Get("include_deleted") == "true",
+	}
+	docs, next, err := h.service.ListDocuments(readerContext(r), filter, query.Get("cursor"), limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, DocumentListResponse{
+		Documents:  newDocumentResponses(docs),
+		NextCursor: next,
+	})
+}
+
+// handleLive responde enquanto o processo está em execução, sem verificar as
+// dependências: uma falha na base não deve reiniciar o servidor
+func (h *Handler) handleLive(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, HealthResponse{Status: domain.HealthOK})
+}
+
+// handleReady verifica se as dependências estão prontas para atender as
+// requisições, com a situação de cada uma
+func (h *Handler) handleReady(w http.ResponseWriter, r *http.Request) {
+	report := h.checkReadiness(r.Context())
+	status := http.StatusOK
+	if report.Status != domain.HealthOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, newReadinessResponse(report))
+}
+
+// checkReadiness executa as verificações configuradas ou, sem elas, apenas
+// a do acesso à base
+func (h *Handler) checkReadiness(ctx context.Context) domain.HealthReport {
+	if h.readiness != nil {
+		return h.readiness.CheckReadiness(ctx)
+	}
+
+	start := time.Now()
+	err := h.service.HealthCheck(ctx)
+	dep := domain.DependencyHealth{Name: "database", Status: domain.HealthOK, Latency: time.Since(start), Err: err}
+	if err != nil {
+		log.Printf("Health check falhou: %v", err)
+		dep.Status = domain.HealthUnavailable
+	}
+	return domain.HealthReport{Status: dep.Status, Dependencies: []domain.DependencyHealth{dep}}
+}
+
+// handleOpenAPI serve a especificação OpenAPI da API
+func (h *Handler) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+// decodeJSON decodifica o corpo da requisição, limitando o seu tamanho
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// writeServiceError converte erros do domínio em respostas HTTP
+func writeServiceError(w http.ResponseWriter, err error) {
+	e := classifyError(err)
+	if e.code == CodeInternal {
+		log.Printf("Erro interno: %v", err)
+	}
+	if e.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(e.retryAfter.Seconds()))))
+	}
+	writeJSON(w, e.status, e.response())
+}
+
+// writeError escreve uma resposta de erro em JSON com o código informado
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, newErrorResponse(status, code, message))
+}
+
+// writeEvent escreve um evento Server-Sent Events com o payload em JSON
+func writeEvent(w http.ResponseWriter, event string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Erro ao serializar evento: %v", err)
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
+
+// writeJSON escreve a resposta em JSON com o status informado
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Erro ao escrever resposta: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Limites de POST /v1/ingest, abaixo do tamanho máximo de um documento do MongoDB
+const (
+	maxIngestBodySize = 10 << 20 // 10 MB
+	maxJobDocuments   = 1000
+)
+
+// handleCreateJob enfileira os documentos para ingestão em segundo plano e
+// responde com o job, cujo progresso é consultado em GET /v1/jobs/{id}
+func (h *Handler) handleCreateJob(w http.ResponseWriter, r *http.Request) {
+	var req IngestRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxIngestBodySize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "corpo da requisição inválido")
+		return
+	}
+	if len(req.Documents) == 0 || len(req.Documents) > maxJobDocuments {
+		writeServiceError(w, &domain.ValidationError{Field: "documents", Message: fmt.Sprintf("deve ter de 1 a %d documentos", maxJobDocuments)})
+		return
+	}
+
+	job := &domain.Job{Documents: make([]domain.Document, 0, len(req.Documents))}
+	for i, docReq := range req.Documents {
+		doc := docReq.toDomain()
+		if err := validateJobDocument(i, doc); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		job.Documents = append(job.Documents, *doc)
+	}
+	if err := h.jobs.Create(r.Context(), job); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v1/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, newJobResponse(*job))
+}
+
+// handleGetJob retorna o progresso de um job do tenant da requisição
+func (h *Handler) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.jobs.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newJobResponse(*job))
+}
+
+// validateJobDocument confere os campos obrigatórios antes de enfileirar; os
+// demais erros são registrados no job, por documento
+func validateJobDocument(index int, doc *domain.Document) error {
+	field := fmt.Sprintf("documents[%d].", index)
+	switch {
+	case strings.TrimSpace(doc.Title) == "":
+		return &domain.ValidationError{Field: field + "title", Message: "obrigatório"}
+	case strings.TrimSpace(doc.Content) == "":
+		return &domain.ValidationError{Field: field + "content", Message: "obrigatório"}
+	case doc.Expired(time.Now()):
+		return &domain.ValidationError{Field: field + "expires_at", Message: "deve ser uma data futura"}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Período padrão e máximo das médias de qualidade, em dias
+const (
+	defaultQualityDays = 30
+	maxQualityDays     = 365
+)
+
+// handleQualityTrend retorna as médias diárias das notas das respostas
+// avaliadas por amostragem no tenant da requisição
+func (h *Handler) handleQualityTrend(w http.ResponseWriter, r *http.Request) {
+	days := defaultQualityDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		var err error
+		if days, err = strconv.Atoi(value); err != nil || days <= 0 || days > maxQualityDays {
+			writeServiceError(w, &domain.ValidationError{Field: "days", Message: "deve ser um número de 1 a 365"})
+			return
+		}
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	trend, err := h.quality.Trend(r.Context(), today.AddDate(0, 0, 1-days))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	resp := QualityTrendResponse{Days: make([]QualityDay, 0, len(trend))}
+	for _, t := range trend {
+		resp.Days = append(resp.Days, QualityDay{
+			Day:              t.Day.Format(time.DateOnly),
+			Samples:          t.Samples,
+			Faithfulness:     t.Faithfulness,
+			ContextRelevance: t.ContextRelevance,
+			AnswerRelevance:  t.AnswerRelevance,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,816 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Os tipos abaixo definem o contrato da API HTTP, descrito em openapi.json.
+// Eles espelham as entidades do domínio, para que mudanças internas não
+// alterem o formato das respostas sem que o contrato seja atualizado.
+
+// QueryRequest é o corpo de POST /v1/query e POST /v1/query/stream
+type QueryRequest struct {
+	Query     string `json:"query"`
+	SessionID string `json:"session_id,omitempty"` // Sessão usada para manter o histórico
+	UserID    string `json:"user_id,omitempty"`    // Usuário usado no controle de consumo, ignorado com chave de API
+	// Groups são os grupos do usuário, usados na ACL dos documentos;
+	// ignorados com chave de API, que usa os grupos da chave
+	Groups []string `json:"groups,omitempty"`
+
+	// Filtros das buscas do agente
+	Category string            `json:"category,omitempty"` // Apenas documentos da categoria
+	Metadata map[string]string `json:"metadata,omitempty"` // Apenas documentos com todos esses metadados
+	Tags     []string          `json:"tags,omitempty"`     // Apenas documentos com essas tags
+	TagMode  string            `json:"tag_mode,omitempty"` // "any" (padrão) ou "all"
+	// Fuzzy tolera erros de digitação na busca textual; ausente usa RAG_FUZZY_SEARCH
+	Fuzzy *bool `json:"fuzzy,omitempty"`
+
+	// ResponseSchema pede também a resposta como um objeto JSON que siga este JSON Schema
+	ResponseSchema map[string]any `json:"response_schema,omitempty"`
+	// Language é o idioma da resposta, como "pt" ou "en-US"; vazio ou "auto" detecta pela pergunta
+	Language string `json:"language,omitempty"`
+	// Style é o formato da resposta: "concise", "detailed" ou "bullet"
+	Style string `json:"style,omitempty"`
+	// MaxAnswerTokens limita o tamanho da resposta, em tokens
+	MaxAnswerTokens int `json:"max_answer_tokens,omitempty"`
+	// Parâmetros de geração; ausentes usam os padrões do servidor
+	Temperature *float64 `json:"temperature,omitempty"` // De 0 a 2
+	TopP        *float64 `json:"top_p,omitempty"`       // Maior que 0 e no máximo 1
+	Seed        *int     `json:"seed,omitempty"`        // Semente, para repetir respostas
+	// Model escolhe um dos modelos permitidos em RAG_ALLOWED_MODELS
+	Model string `json:"model,omitempty"`
+}
+
+// toDomain converte a requisição para a entidade do domínio, atribuída ao
+// usuário e aos grupos informados
+func (r QueryRequest) toDomain(userID string, groups []string) domain.RAGRequest {
+	return domain.RAGRequest{
+		Query:     r.Query,
+		SessionID: r.SessionID,
+		UserID:    userID,
+		Groups:    groups,
+		Category:  r.Category,
+		Metadata:  r.Metadata,
+		Tags:      r.Tags,
+		TagMode:   domain.TagMode(r.TagMode),
+		Fuzzy:     r.Fuzzy,
+		Language:  r.Language,
+		Style:     domain.AnswerStyle(r.Style),
+
+		ResponseSchema:  r.ResponseSchema,
+		MaxAnswerTokens: r.MaxAnswerTokens,
+		Temperature:     r.Temperature,
+		TopP:            r.TopP,
+		Seed:            r.Seed,
+		Model:           r.Model,
+	}
+}
+
+// QueryResponse é a resposta do agente a uma pergunta
+type QueryResponse struct {
+	Answer     string             `json:"answer"`
+	Sources    []DocumentResponse `json:"sources"`
+	UsedSearch bool               `json:"used_search"`
+	SessionID  string             `json:"session_id,omitempty"`
+	Steps      []AgentStep        `json:"steps,omitempty"`
+	Cached     bool               `json:"cached,omitempty"`
+	Model      string             `json:"model,omitempty"`    // Modelo que gerou a resposta
+	Language   string             `json:"language,omitempty"` // Idioma pedido ao modelo, informado ou detectado
+	// PromptVersion identifica as versões dos prompts usados na resposta
+	PromptVersion string `json:"prompt_version,omitempty"`
+	// Citations liga os marcadores da resposta, como [1], às fontes
+	Citations []Citation `json:"citations,omitempty"`
+	// Groundedness é a verificação da resposta contra as fontes, quando habilitada
+	Groundedness *Groundedness `json:"groundedness,omitempty"`
+	// Moderation lista a pergunta e a resposta sinalizadas pela moderação
+	Moderation []ModerationFlag `json:"moderation,omitempty"`
+	// SourcesSummarized indica que o agente recebeu resumos das fontes, que
+	// não cabiam no contexto; sources traz o conteúdo original
+	SourcesSummarized bool `json:"sources_summarized,omitempty"`
+	// StructuredAnswer é a resposta no formato de response_schema, quando pedido
+	StructuredAnswer json.RawMessage `json:"structured_answer,omitempty"`
+	Usage            Usage           `json:"usage"`
+	CostUSD          float64         `json:"cost_usd"`
+}
+
+// newQueryResponse converte a resposta do domínio
+func newQueryResponse(resp *domain.RAGResponse) *QueryResponse {
+	steps := make([]AgentStep, 0, len(resp.Steps))
+	for _, step := range resp.Steps {
+		steps = append(steps, newAgentStep(step))
+	}
+	return &QueryResponse{
+		Answer:        resp.Answer,
+		Sources:       newDocumentResponses(resp.Sources),
+		UsedSearch:    resp.UsedSearch,
+		SessionID:     resp.SessionID,
+		Steps:         steps,
+		Cached:        resp.Cached,
+		Model:         resp.Model,
+		Language:      resp.Language,
+		PromptVersion: resp.PromptVersion,
+		Citations:     newCitations(resp.Citations),
+		Groundedness:  newGroundedness(resp.Groundedness),
+		Moderation:    newModerationFlags(resp.Moderation),
+
+		SourcesSummarized: resp.SourcesSummarized,
+		StructuredAnswer:  resp.StructuredAnswer,
+		Usage: Usage{
+			Model:            resp.Usage.Model,
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+		},
+		CostUSD: resp.CostUSD,
+	}
+}
+
+// Citation liga um marcador de citação da resposta a uma das fontes
+type Citation struct {
+	Marker   int    `json:"marker"` // Número citado na resposta, como em [1]
+	Source   int    `json:"source"` // Índice da fonte em sources
+	Title    string `json:"title"`
+	Link     string `json:"link"`
+	External bool   `json:"external,omitempty"` // A fonte veio da busca na web, fora da base
+}
+
+// newCitations converte as citações do domínio
+func newCitations(citations []domain.Citation) []Citation {
+	if len(citations) == 0 {
+		return nil
+	}
+	result := make([]Citation, 0, len(citations))
+	for _, c := range citations {
+		result = append(result, Citation{Marker: c.Marker, Source: c.Source, Title: c.Title, Link: c.Link, External: c.External})
+	}
+	return result
+}
+
+// Groundedness é o resultado da verificação da resposta contra as fontes
+type Groundedness struct {
+	Score       float64  `json:"score"`                 // Fração das afirmações sustentadas (0 a 1)
+	Unsupported []string `json:"unsupported,omitempty"` // Afirmações não sustentadas
+	Regenerated bool     `json:"regenerated,omitempty"` // A resposta foi gerada novamente por ter ficado abaixo do limite
+}
+
+// newGroundedness converte a verificação do domínio
+func newGroundedness(g *domain.Groundedness) *Groundedness {
+	if g == nil {
+		return nil
+	}
+	return &Groundedness{Score: g.Score, Unsupported: g.Unsupported, Regenerated: g.Regenerated}
+}
+
+// ModerationFlag é uma etapa sinalizada pela moderação
+type ModerationFlag struct {
+	Stage      string   `json:"stage"`      // "query" ou "answer"
+	Categories []string `json:"categories"` // Categorias sinalizadas
+}
+
+// newModerationFlags converte as sinalizações do domínio
+func newModerationFlags(results []domain.ModerationResult) []ModerationFlag {
+	if len(results) == 0 {
+		return nil
+	}
+	flags := make([]ModerationFlag, 0, len(results))
+	for _, r := range results {
+		flags = append(flags, ModerationFlag{Stage: r.Stage, Categories: r.Categories})
+	}
+	return flags
+}
+
+// AgentStep é uma iteração do agente, devolvida para diagnóstico
+type AgentStep struct {
+	Iteration int        `json:"iteration"`
+	Content   string     `json:"content,omitempty"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	Tokens    int        `json:"tokens"`
+}
+
+// newAgentStep converte o passo do domínio
+func newAgentStep(step domain.AgentStep) AgentStep {
+	var calls []ToolCall
+	for _, call := range step.ToolCalls {
+		calls = append(calls, ToolCall{ID: call.ID, Name: call.Name, Arguments: call.Arguments})
+	}
+	return AgentStep{
+		Iteration: step.Iteration,
+		Content:   step.Content,
+		ToolCalls: calls,
+		Tokens:    step.Tokens,
+	}
+}
+
+// ToolCall é uma chamada de ferramenta solicitada pelo agente
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // Argumentos em JSON
+}
+
+// Usage é o consumo de tokens das chamadas ao LLM
+type Usage struct {
+	Model            string `json:"model,omitempty"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+}
+
+// DocumentRequest é o corpo de POST /v1/documents e PUT /v1/documents/{id}
+type DocumentRequest struct {
+	Title    string            `json:"title"`
+	Content  string            `json:"content"`
+	Link     string            `json:"link"`
+	Category string            `json:"category"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	// ACL restringe as buscas pelo documento aos usuários desses grupos
+	ACL []string `json:"acl,omitempty"`
+	// ExpiresAt tira o documento das buscas a partir do momento informado
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// toDomain converte a requisição para a entidade do domínio. O ID é sempre
+// gerado pela base.
+func (r DocumentRequest) toDomain() *domain.Document {
+	return &domain.Document{
+		Title:     r.Title,
+		Content:   r.Content,
+		Link:      r.Link,
+		Category:  r.Category,
+		Metadata:  r.Metadata,
+		Tags:      r.Tags,
+		ACL:       r.ACL,
+		ExpiresAt: r.ExpiresAt,
+	}
+}
+
+// DocumentResponse é um documento da base
+type DocumentResponse struct {
+	ID         string            `json:"id,omitempty"`
+	Title      string            `json:"title"`
+	Content    string            `json:"content"`
+	Link       string            `json:"link"`
+	Category   string            `json:"category"`
+	ParentID   string            `json:"parent_id,omitempty"`
+	ChunkIndex int               `json:"chunk_index,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	ACL        []string          `json:"acl,omitempty"` // Grupos que podem ver o documento nas buscas
+	Version    int               `json:"version"`
+	CreatedAt  time.Time         `json:"created_at,omitzero"`
+	UpdatedAt  time.Time         `json:"updated_at,omitzero"`
+	DeletedAt  time.Time         `json:"deleted_at,omitzero"` // Presente apenas em documentos excluídos
+	ExpiresAt  time.Time         `json:"expires_at,omitzero"` // Presente apenas em documentos com validade
+	External   bool              `json:"external,omitempty"`  // Fonte vinda da busca na web, fora da base
+}
+
+// newDocumentResponse converte o documento do domínio
+func newDocumentResponse(doc domain.Document) DocumentResponse {
+	return DocumentResponse{
+		ID:         doc.ID,
+		Title:      doc.Title,
+		Content:    doc.Content,
+		Link:       doc.Link,
+		Category:   doc.Category,
+		ParentID:   doc.ParentID,
+		ChunkIndex: doc.ChunkIndex,
+		Metadata:   doc.Metadata,
+		Tags:       doc.Tags,
+		ACL:        doc.ACL,
+		Version:    doc.CurrentVersion(),
+		CreatedAt:  doc.CreatedAt,
+		UpdatedAt:  doc.UpdatedAt,
+		DeletedAt:  doc.DeletedAt,
+		ExpiresAt:  doc.ExpiresAt,
+		External:   doc.External(),
+	}
+}
+
+// newDocumentResponses converte uma lista de documentos, sempre não nula
+func newDocumentResponses(docs []domain.Document) []DocumentResponse {
+	result := make([]DocumentResponse, 0, len(docs))
+	for _, doc := range docs {
+		result = append(result, newDocumentResponse(doc))
+	}
+	return result
+}
+
+// DocumentListResponse é a resposta de GET /v1/documents
+type DocumentListResponse struct {
+	Documents  []DocumentResponse `json:"documents"`
+	NextCursor string             `json:"next_cursor,omitempty"` // Ausente na última página
+}
+
+// SearchRequest é o corpo de POST /v1/search
+type SearchRequest struct {
+	Query string `json:"query"`
+	// Groups são os grupos do usuário, usados na ACL dos documentos;
+	// ignorados com chave de API, que usa os grupos da chave
+	Groups   []string          `json:"groups,omitempty"`
+	Category string            `json:"category,omitempty"` // Apenas documentos da categoria
+	Metadata map[string]string `json:"metadata,omitempty"` // Apenas documentos com todos esses metadados
+	Tags     []string          `json:"tags,omitempty"`     // Apenas documentos com essas tags
+	TagMode  string            `json:"tag_mode,omitempty"` // "any" (padrão) ou "all"
+	// IncludeDeleted inclui os documentos excluídos logicamente, que por
+	// padrão ficam de fora da busca
+	IncludeDeleted bool `json:"include_deleted,omitempty"`
+}
+
+// filter converte a requisição no filtro da busca, com os grupos informados
+func (r SearchRequest) filter(groups []string) domain.SearchFilter {
+	return domain.SearchFilter{
+		Category:       r.Category,
+		Metadata:       r.Metadata,
+		Tags:           domain.NormalizeTags(r.Tags),
+		TagMode:        domain.TagMode(r.TagMode),
+		Groups:         domain.NormalizeGroups(groups),
+		IncludeDeleted: r.IncludeDeleted,
+	}
+}
+
+// SearchResponse é a resposta de POST /v1/search: os documentos mais
+// relevantes e as contagens dos encontrados, para exibir os filtros
+type SearchResponse struct {
+	Documents []DocumentResponse `json:"documents"`
+	Total     int                `json:"total"` // Documentos lógicos encontrados
+	Facets    Facets             `json:"facets"`
+}
+
+// Facets são as contagens dos documentos encontrados por valor de cada campo
+type Facets struct {
+	Categories []FacetCount `json:"categories"`
+	Tags       []FacetCount `json:"tags"`
+	Months     []FacetCount `json:"months"` // Mês de criação, no formato 2006-01
+}
+
+// FacetCount é a quantidade de documentos com um valor
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// newSearchResponse converte o resultado da busca com facets
+func newSearchResponse(search *domain.FacetedSearch) SearchResponse {
+	return SearchResponse{
+		Documents: newDocumentResponses(search.Documents),
+		Total:     search.Total,
+		Facets: Facets{
+			Categories: newFacetCounts(search.Facets.Categories),
+			Tags:       newFacetCounts(search.Facets.Tags),
+			Months:     newFacetCounts(search.Facets.Months),
+		},
+	}
+}
+
+// newFacetCounts converte as contagens de um facet
+func newFacetCounts(counts []domain.FacetCount) []FacetCount {
+	result := make([]FacetCount, 0, len(counts))
+	for _, c := range counts {
+		result = append(result, FacetCount{Value: c.Value, Count: c.Count})
+	}
+	return result
+}
+
+// DocumentVersionResponse é uma versão de um documento
+type DocumentVersionResponse struct {
+	Version   int               `json:"version"`
+	Title     string            `json:"title"`
+	Content   string            `json:"content"`
+	Link      string            `json:"link"`
+	Category  string            `json:"category"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	CreatedAt time.Time         `json:"created_at,omitzero"` // Quando a versão passou a valer
+}
+
+// DocumentVersionsResponse é a resposta de GET /v1/documents/{id}/versions
+type DocumentVersionsResponse struct {
+	DocumentID string                    `json:"document_id"`
+	Versions   []DocumentVersionResponse `json:"versions"` // Da mais antiga para a atual
+}
+
+// newDocumentVersionsResponse converte o histórico do domínio
+func newDocumentVersionsResponse(id string, versions []domain.DocumentVersion) DocumentVersionsResponse {
+	resp := DocumentVersionsResponse{DocumentID: id, Versions: make([]DocumentVersionResponse, 0, len(versions))}
+	for _, v := range versions {
+		resp.Versions = append(resp.Versions, DocumentVersionResponse{
+			Version:   v.Version,
+			Title:     v.Title,
+			Content:   v.Content,
+			Link:      v.Link,
+			Category:  v.Category,
+			Metadata:  v.Metadata,
+			Tags:      v.Tags,
+			CreatedAt: v.CreatedAt,
+		})
+	}
+	return resp
+}
+
+// APIKeyRequest é o corpo de POST /v1/admin/api-keys
+type APIKeyRequest struct {
+	Name     string   `json:"name"`
+	UserID   string   `json:"user_id"`             // Usuário ao qual as perguntas são atribuídas
+	TenantID string   `json:"tenant_id,omitempty"` // Tenant acessado com a chave (padrão: tenant padrão)
+	Groups   []string `json:"groups,omitempty"`    // Grupos do usuário, usados na ACL dos documentos
+}
+
+// APIKeyResponse é uma chave de API, sem o segredo
+type APIKeyResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	UserID    string    `json:"user_id"`
+	TenantID  string    `json:"tenant_id,omitempty"`
+	Groups    []string  `json:"groups,omitempty"`
+	Prefix    string    `json:"prefix"` // Início do segredo, para identificar a chave
+	CreatedAt time.Time `json:"created_at"`
+	RevokedAt time.Time `json:"revoked_at,omitzero"` // Presente apenas em chaves revogadas
+}
+
+// newAPIKeyResponse converte a chave do domínio
+func newAPIKeyResponse(key domain.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		UserID:    key.UserID,
+		TenantID:  key.TenantID,
+		Groups:    key.Groups,
+		Prefix:    key.Prefix,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+// CreatedAPIKeyResponse é a resposta de POST /v1/admin/api-keys, a única que
+// traz o segredo da chave
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// APIKeyListResponse é a resposta de GET /v1/admin/api-keys
+type APIKeyListResponse struct {
+	Keys []APIKeyResponse `json:"keys"`
+}
+
+// HealthResponse é a resposta de GET /livez
+type HealthResponse struct {
+	Status string `json:"status"` // "ok" ou "unavailable"
+}
+
+// ReadinessResponse é a resposta de GET /readyz e GET /healthz
+type ReadinessResponse struct {
+	Status       string               `json:"status"` // "ok" ou "unavailable"
+	Dependencies []DependencyResponse `json:"dependencies"`
+}
+
+// DependencyResponse é a situação de uma dependência. O motivo das falhas
+// fica apenas no log, já que a rota não exige autenticação.
+type DependencyResponse struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"` // "ok" ou "unavailable"
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// newReadinessResponse converte o resultado das verificações
+func newReadinessResponse(report domain.HealthReport) ReadinessResponse {
+	resp := ReadinessResponse{Status: report.Status, Dependencies: make([]DependencyResponse, 0, len(report.Dependencies))}
+	for _, dep := range report.Dependencies {
+		resp.Dependencies = append(resp.Dependencies, DependencyResponse{
+			Name:      dep.Name,
+			Status:    dep.Status,
+			LatencyMS: dep.Latency.Milliseconds(),
+		})
+	}
+	return resp
+}
+
+// TokenEvent é um trecho da resposta enviado durante o streaming
+type TokenEvent struct {
+	Content string `json:"content"`
+}
+
+// ErrorResponse é o envelope das respostas de erro. Os clientes devem tratar
+// os erros pelo código, estável entre versões; a mensagem pode mudar.
+type ErrorResponse struct {
+	Code      string       `json:"code"` // Um dos códigos Code*, como document_not_found
+	Message   string       `json:"message"`
+	Error     string       `json:"error"`           // O mesmo que Message, mantido por compatibilidade
+	Field     string       `json:"field,omitempty"` // Primeiro campo inválido, nos erros de validação
+	Fields    []FieldError `json:"fields,omitempty"`
+	Retryable bool         `json:"retryable"` // A mesma requisição pode ser repetida mais tarde
+}
+
+// FieldError é um campo inválido da requisição
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ReembedResponse é a resposta de POST /v1/admin/documents/{id}/reembed
+type ReembedResponse struct {
+	ID       string `json:"id"`
+	Embedded int    `json:"embedded"` // Documentos (ou chunks) com o embedding gerado novamente
+}
+
+// TimeoutResponse é o envelope de erro de uma pergunta interrompida, com o
+// que foi obtido até o momento
+type TimeoutResponse struct {
+	Code          string             `json:"code"` // Sempre timeout
+	Message       string             `json:"message"`
+	Error         string             `json:"error"` // O mesmo que Message, mantido por compatibilidade
+	Retryable     bool               `json:"retryable"`
+	PartialAnswer string             `json:"partial_answer"`
+	Sources       []DocumentResponse `json:"sources"`
+}
+
+// newTimeoutResponse converte o resultado parcial do domínio
+func newTimeoutResponse(timeout *domain.TimeoutResult) *TimeoutResponse {
+	return &TimeoutResponse{
+		Code:          CodeTimeout,
+		Message:       timeout.Error(),
+		Error:         timeout.Error(),
+		Retryable:     true,
+		PartialAnswer: timeout.PartialAnswer,
+		Sources:       newDocumentResponses(timeout.Sources),
+	}
+}
+
+// QualityTrendResponse é o corpo de GET /v1/quality
+type QualityTrendResponse struct {
+	Days []QualityDay `json:"days"` // Do dia mais antigo para o mais recente, apenas dias com avaliações
+}
+
+// QualityDay são as médias das notas das respostas avaliadas em um dia (UTC)
+type QualityDay struct {
+	Day              string   `json:"day"` // AAAA-MM-DD
+	Samples          int      `json:"samples"`
+	Faithfulness     *float64 `json:"faithfulness,omitempty"`      // Média das respostas com fontes
+	ContextRelevance *float64 `json:"context_relevance,omitempty"` // Média das respostas com fontes
+	AnswerRelevance  float64  `json:"answer_relevance"`
+}
+
+// IngestRequest é o corpo de POST /v1/ingest
+type IngestRequest struct {
+	Documents []DocumentRequest `json:"documents"`
+}
+
+// JobResponse é um job de ingestão, com o progresso e os erros de cada documento
+type JobResponse struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"` // queued, running, completed ou failed
+	Total      int        `json:"total"`
+	Processed  int        `json:"processed"`
+	Succeeded  int        `json:"succeeded"`
+	Skipped    int        `json:"skipped"` // Conteúdo já indexado
+	Failed     int        `json:"failed"`
+	Chunks     int        `json:"chunks"`
+	Errors     []JobError `json:"errors"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  time.Time  `json:"started_at,omitzero"`
+	FinishedAt time.Time  `json:"finished_at,omitzero"`
+}
+
+// JobError é a falha na gravação de um documento do job
+type JobError struct {
+	Index int    `json:"index"` // Posição do documento em documents
+	Title string `json:"title"`
+	Error string `json:"error"`
+}
+
+// newJobResponse converte o job do domínio
+func newJobResponse(job domain.Job) JobResponse {
+	resp := JobResponse{
+		ID:         job.ID,
+		Status:     job.Status,
+		Total:      job.Total,
+		Processed:  job.Processed,
+		Succeeded:  job.Succeeded,
+		Skipped:    job.Skipped,
+		Failed:     job.Failed,
+		Chunks:     job.Chunks,
+		Errors:     make([]JobError, 0, len(job.Errors)),
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+	for _, e := range job.Errors {
+		resp.Errors = append(resp.Errors, JobError{Index: e.Index, Title: e.Title, Error: e.Error})
+	}
+	return resp
+}
+
+// AuditEntryResponse é uma entrada do log de auditoria
+type AuditEntryResponse struct {
+	ID        string         `json:"id"`
+	Time      time.Time      `json:"time"`
+	Action    string         `json:"action"`
+	Actor     string         `json:"actor,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+	SessionID string         `json:"session_id,omitempty"`
+	Resource  string         `json:"resource,omitempty"` // ID do documento ou nome da ferramenta
+	Details   map[string]any `json:"details,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// AuditListResponse é a resposta de GET /v1/admin/audit
+type AuditListResponse struct {
+	Entries    []AuditEntryResponse `json:"entries"`
+	NextCursor string               `json:"next_cursor,omitempty"` // Ausente na última página
+}
+
+// newAuditListResponse converte as entradas do domínio
+func newAuditListResponse(entries []domain.AuditEntry, next string) AuditListResponse {
+	resp := AuditListResponse{Entries: make([]AuditEntryResponse, 0, len(entries)), NextCursor: next}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, AuditEntryResponse{
+			ID:        e.ID,
+			Time:      e.Time,
+			Action:    e.Action,
+			Actor:     e.Actor,
+			RequestID: e.RequestID,
+			SessionID: e.SessionID,
+			Resource:  e.Resource,
+			Details:   e.Details,
+			Error:     e.Error,
+		})
+	}
+	return resp
+}
+
+// ConversationResponse é o histórico de uma sessão
+type ConversationResponse struct {
+	SessionID string                `json:"session_id"`
+	Messages  []ConversationMessage `json:"messages"` // Turnos ainda não resumidos, do mais antigo ao mais recente
+	// Summary resume os turnos mais antigos, que não estão em messages
+	Summary            string    `json:"summary,omitempty"`
+	SummarizedMessages int       `json:"summarized_messages,omitempty"` // Mensagens incorporadas ao resumo
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// ConversationMessage é uma pergunta (user) ou resposta (assistant) da sessão
+type ConversationMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// newConversationResponse converte a conversa do domínio
+func newConversationResponse(conv domain.Conversation) ConversationResponse {
+	resp := ConversationResponse{
+		SessionID:          conv.SessionID,
+		Messages:           make([]ConversationMessage, 0, len(conv.Messages)),
+		Summary:            conv.Summary,
+		SummarizedMessages: conv.SummarizedMessages,
+		CreatedAt:          conv.CreatedAt,
+		UpdatedAt:          conv.UpdatedAt,
+	}
+	for _, msg := range conv.Messages {
+		resp.Messages = append(resp.Messages, ConversationMessage{Role: msg.Role, Content: msg.Content})
+	}
+	return resp
+}
+
+// FeedbackRequest é o corpo de POST /v1/feedback: a avaliação de uma
+// resposta, com a pergunta, a resposta e as fontes como devolvidas em
+// POST /v1/query
+type FeedbackRequest struct {
+	Rating    string           `json:"rating"` // "up" ou "down"
+	Comment   string           `json:"comment,omitempty"`
+	Query     string           `json:"query"`
+	Answer    string           `json:"answer"`
+	Sources   []FeedbackSource `json:"sources,omitempty"`
+	SessionID string           `json:"session_id,omitempty"`
+	UserID    string           `json:"user_id,omitempty"`    // Ignorado com chave de API, que usa o usuário da chave
+	RequestID string           `json:"request_id,omitempty"` // X-Request-ID da pergunta, para cruzar com o log de auditoria
+	Model     string           `json:"model,omitempty"`
+	// PromptVersion identifica as versões dos prompts usados na resposta
+	PromptVersion string `json:"prompt_version,omitempty"`
+}
+
+// FeedbackSource identifica uma fonte da resposta avaliada; aceita os
+// itens de sources da resposta da pergunta
+type FeedbackSource struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+	Link  string `json:"link,omitempty"`
+}
+
+// toDomain converte a requisição para a entidade do domínio, atribuída ao
+// usuário informado
+func (r FeedbackRequest) toDomain(userID string) *domain.Feedback {
+	feedback := &domain.Feedback{
+		Rating:        r.Rating,
+		Comment:       r.Comment,
+		Query:         r.Query,
+		Answer:        r.Answer,
+		SessionID:     r.SessionID,
+		UserID:        userID,
+		RequestID:     r.RequestID,
+		Model:         r.Model,
+		PromptVersion: r.PromptVersion,
+	}
+	for _, source := range r.Sources {
+		feedback.Sources = append(feedback.Sources, domain.FeedbackSource{ID: source.ID, Title: source.Title, Link: source.Link})
+	}
+	return feedback
+}
+
+// FeedbackResponse é uma avaliação guardada
+type FeedbackResponse struct {
+	ID            string           `json:"id"`
+	Rating        string           `json:"rating"`
+	Comment       string           `json:"comment,omitempty"`
+	Query         string           `json:"query"`
+	Answer        string           `json:"answer"`
+	Sources       []FeedbackSource `json:"sources"`
+	SessionID     string           `json:"session_id,omitempty"`
+	UserID        string           `json:"user_id,omitempty"`
+	RequestID     string           `json:"request_id,omitempty"`
+	Model         string           `json:"model,omitempty"`
+	PromptVersion string           `json:"prompt_version,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// newFeedbackResponse converte a avaliação do domínio
+func newFeedbackResponse(feedback domain.Feedback) FeedbackResponse {
+	resp := FeedbackResponse{
+		ID:            feedback.ID,
+		Rating:        feedback.Rating,
+		Comment:       feedback.Comment,
+		Query:         feedback.Query,
+		Answer:        feedback.Answer,
+		Sources:       make([]FeedbackSource, 0, len(feedback.Sources)),
+		SessionID:     feedback.SessionID,
+		UserID:        feedback.UserID,
+		RequestID:     feedback.RequestID,
+		Model:         feedback.Model,
+		PromptVersion: feedback.PromptVersion,
+		CreatedAt:     feedback.CreatedAt,
+	}
+	for _, source := range feedback.Sources {
+		resp.Sources = append(resp.Sources, FeedbackSource{ID: source.ID, Title: source.Title, Link: source.Link})
+	}
+	return resp
+}
+
+// FeedbackListResponse é a resposta de GET /v1/admin/feedback
+type FeedbackListResponse struct {
+	Feedback   []FeedbackResponse `json:"feedback"`
+	NextCursor string             `json:"next_cursor,omitempty"` // Ausente na última página
+}
+
+// AnalyticsResponse é a resposta de GET /v1/admin/analytics
+type AnalyticsResponse struct {
+	Since        time.Time `json:"since"` // Início do período, à meia-noite (UTC)
+	Queries      int       `json:"queries"`
+	Failed       int       `json:"failed"`
+	ZeroHits     int       `json:"zero_hits"` // Consultas que buscaram na base e não encontraram nada
+	AvgLatencyMS float64   `json:"avg_latency_ms"`
+	P95LatencyMS float64   `json:"p95_latency_ms"`
+	// TopQueries e ZeroHitQueries vão da consulta mais para a menos frequente
+	TopQueries     []QueryCount `json:"top_queries"`
+	ZeroHitQueries []QueryCount `json:"zero_hit_queries"`
+	// Categories conta as consultas por categoria pedida; a categoria vazia
+	// reúne as consultas sem filtro
+	Categories []CategoryCount `json:"categories"`
+}
+
+// QueryCount é a quantidade de vezes que uma consulta foi feita
+type QueryCount struct {
+	Query string `json:"query"`
+	Count int    `json:"count"`
+}
+
+// CategoryCount é a quantidade de consultas de uma categoria
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// newAnalyticsResponse converte as estatísticas do domínio
+func newAnalyticsResponse(since time.Time, stats *domain.QueryStats) AnalyticsResponse {
+	resp := AnalyticsResponse{
+		Since:          since,
+		Queries:        stats.Queries,
+		Failed:         stats.Failed,
+		ZeroHits:       stats.ZeroHits,
+		AvgLatencyMS:   stats.AvgLatencyMS,
+		P95LatencyMS:   stats.P95LatencyMS,
+		TopQueries:     make([]QueryCount, 0, len(stats.TopQueries)),
+		ZeroHitQueries: make([]QueryCount, 0, len(stats.ZeroHitQueries)),
+		Categories:     make([]CategoryCount, 0, len(stats.Categories)),
+	}
+	for _, q := range stats.TopQueries {
+		resp.TopQueries = append(resp.TopQueries, QueryCount{Query: q.Query, Count: q.Count})
+	}
+	for _, q := range stats.ZeroHitQueries {
+		resp.ZeroHitQueries = append(resp.ZeroHitQueries, QueryCount{Query: q.Query, Count: q.Count})
+	}
+	for _, c := range stats.Categories {
+		resp.Categories = append(resp.Categories, CategoryCount{Category: c.Category, Count: c.Count})
+	}
+	return resp
+}
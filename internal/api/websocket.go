@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+// Tipos das mensagens enviadas pelo servidor no chat via WebSocket
+const (
+	chatToken = "token" // Trecho da resposta gerada
+	chatDone  = "done"  // Resposta completa do turno
+	chatError = "error" // Falha ao processar o turno
+)
+
+// ChatRequest é uma mensagem enviada pelo cliente: uma nova pergunta na sessão
+type ChatRequest struct {
+	Query  string   `json:"query"`
+	UserID string   `json:"user_id,omitempty"`
+	Groups []string `json:"groups,omitempty"` // Grupos do usuário, como em QueryRequest
+
+	// Filtros das buscas do turno, como em QueryRequest
+	Category string            `json:"category,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	TagMode  string            `json:"tag_mode,omitempty"`
+	Fuzzy    *bool             `json:"fuzzy,omitempty"`
+
+	// ResponseSchema pede também a resposta estruturada, como em QueryRequest
+	ResponseSchema map[string]any `json:"response_schema,omitempty"`
+	Language       string         `json:"language,omitempty"` // Idioma da resposta, como em QueryRequest
+
+	// Formato, parâmetros de geração e modelo da resposta, como em QueryRequest
+	Style           string   `json:"style,omitempty"`
+	MaxAnswerTokens int      `json:"max_answer_tokens,omitempty"`
+	Temperature     *float64 `json:"temperature,omitempty"`
+	TopP            *float64 `json:"top_p,omitempty"`
+	Seed            *int     `json:"seed,omitempty"`
+	Model           string   `json:"model,omitempty"`
+}
+
+// ChatMessage é uma mensagem enviada pelo servidor. Os campos preenchidos
+// dependem do tipo: Content em "token", Response em "done" e Error (com a
+// resposta parcial, em caso de timeout) em "error".
+type ChatMessage struct {
+	Type          string             `json:"type"`
+	SessionID     string             `json:"session_id,omitempty"`
+	Content       string             `json:"content,omitempty"`
+	Response      *QueryResponse     `json:"response,omitempty"`
+	Error         string             `json:"error,omitempty"`
+	Code          string             `json:"code,omitempty"` // Código do erro, como nas respostas HTTP
+	Retryable     bool               `json:"retryable,omitempty"`
+	PartialAnswer string             `json:"partial_answer,omitempty"`
+	Sources       []DocumentResponse `json:"sources,omitempty"`
+}
+
+// handleChat mantém uma conversa via WebSocket. Cada mensagem do cliente é
+// uma pergunta, respondida com mensagens "token" durante a geração e uma
+// mensagem "done" ao final. Todos os turnos usam a mesma sessão, informada em
+// ?session_id= para retomar uma conversa ou criada no primeiro turno.
+func (h *Handler) handleChat(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: h.allowedOrigins})
+	if err != nil {
+		log.Printf("Erro ao abrir WebSocket: %v", err)
+		return
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(maxBodySize)
+
+	// O contexto é cancelado quando o cliente fecha a conexão, interrompendo
+	// o turno em andamento
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// As mensagens são lidas em paralelo ao processamento, para detectar o
+	// fechamento da conexão mesmo durante a geração de uma resposta
+	requests := make(chan ChatRequest)
+	go func() {
+		defer cancel()
+		defer close(requests)
+		for {
+			var req ChatRequest
+			if err := wsjson.Read(ctx, conn, &req); err != nil {
+				if websocket.CloseStatus(err) == -1 && ctx.Err() == nil {
+					log.Printf("Erro ao ler mensagem do WebSocket: %v", err)
+				}
+				return
+			}
+			select {
+			case requests <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	sessionID := r.URL.Query().Get("session_id")
+	for req := range requests {
+		resp, err := h.chatTurn(ctx, conn, domain.RAGRequest{
+			Query:     req.Query,
+			SessionID: sessionID,
+			UserID:    requestUserID(ctx, req.UserID),
+			Groups:    requestGroups(ctx, req.Groups),
+			Category:  req.Category,
+			Metadata:  req.Metadata,
+			Tags:      req.Tags,
+			TagMode:   domain.TagMode(req.TagMode),
+			Fuzzy:     req.Fuzzy,
+			Language:  req.Language,
+			Style:     domain.AnswerStyle(req.Style),
+
+			ResponseSchema:  req.ResponseSchema,
+			MaxAnswerTokens: req.MaxAnswerTokens,
+			Temperature:     req.Temperature,
+			TopP:            req.TopP,
+			Seed:            req.Seed,
+			Model:           req.Model,
+		})
+		if err != nil {
+			// Conexão encerrada pelo cliente no meio do turno
+			if ctx.Err() != nil {
+				return
+			}
+			if writeErr := wsjson.Write(ctx, conn, chatErrorMessage(err, sessionID)); writeErr != nil {
+				return
+			}
+			continue
+		}
+
+		// Os próximos turnos continuam a sessão criada no primeiro
+		sessionID = resp.SessionID
+		if err := wsjson.Write(ctx, conn, ChatMessage{Type: chatDone, SessionID: sessionID, Response: newQueryResponse(resp)}); err != nil {
+			return
+		}
+	}
+
+	conn.Close(websocket.StatusNormalClosure, "")
+}
+
+// chatTurn processa uma pergunta, enviando os trechos da resposta à medida
+// que são gerados
+func (h *Handler) chatTurn(ctx context.Context, conn *websocket.Conn, req domain.RAGRequest) (*domain.RAGResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	return h.service.ProcessQueryStream(ctx, req, func(token string) {
+		if err := wsjson.Write(ctx, conn, ChatMessage{Type: chatToken, Content: token}); err != nil {
+			cancel()
+		}
+	})
+}
+
+// chatErrorMessage converte erros do turno em uma mensagem "error", com o
+// código e a indicação de nova tentativa das respostas HTTP
+func chatErrorMessage(err error, sessionID string) ChatMessage {
+	e := classifyError(err)
+	if e.code == CodeInternal {
+		log.Printf("Erro no chat: %v", err)
+	}
+	msg := ChatMessage{Type: chatError, SessionID: sessionID, Error: e.message, Code: e.code, Retryable: retryable(e.status)}
+	var timeout *domain.TimeoutResult
+	if errors.As(err, &timeout) {
+		msg.PartialAnswer = timeout.PartialAnswer
+		msg.Sources = newDocumentResponses(timeout.Sources)
+	}
+	return msg
+}
@@ -0,0 +1,334 @@
+// Package app monta o serviço RAG a partir das variáveis de ambiente, com a
+// mesma configuração em todos os pontos de entrada (servidor HTTP, MCP, bots
+// e comando api): cadeia de provedores de LLM com novas tentativas e circuit
+// breaker, moderação, caches, limite de perguntas, sessões, ferramentas,
+// webhooks, auditoria e estatísticas. Assim, nenhum ponto de entrada contorna
+// as proteções do servidor HTTP.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alextavella/agentic-rag/internal/analytics"
+	"github.com/alextavella/agentic-rag/internal/cache"
+	"github.com/alextavella/agentic-rag/internal/chunking"
+	"github.com/alextavella/agentic-rag/internal/database"
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/guardrail"
+	"github.com/alextavella/agentic-rag/internal/infrastructure/llm"
+	"github.com/alextavella/agentic-rag/internal/infrastructure/mcp"
+	"github.com/alextavella/agentic-rag/internal/ingest"
+	"github.com/alextavella/agentic-rag/internal/metrics"
+	"github.com/alextavella/agentic-rag/internal/pricing"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+	"github.com/alextavella/agentic-rag/internal/ratelimit"
+	"github.com/alextavella/agentic-rag/internal/rerank"
+	"github.com/alextavella/agentic-rag/internal/resilience"
+	"github.com/alextavella/agentic-rag/internal/routing"
+	"github.com/alextavella/agentic-rag/internal/sandbox"
+	"github.com/alextavella/agentic-rag/internal/service"
+	"github.com/alextavella/agentic-rag/internal/session"
+	"github.com/alextavella/agentic-rag/internal/sqlquery"
+	"github.com/alextavella/agentic-rag/internal/tokenizer"
+	"github.com/alextavella/agentic-rag/internal/tools"
+	"github.com/alextavella/agentic-rag/internal/tracing"
+	"github.com/alextavella/agentic-rag/internal/webhook"
+	"github.com/alextavella/agentic-rag/internal/websearch"
+)
+
+// App é o serviço RAG montado com as dependências compartilhadas pelos
+// pontos de entrada. Os campos opcionais ficam nulos quando a funcionalidade
+// está desabilitada.
+type App struct {
+	// Service é o serviço RAG instrumentado para /metrics e, com
+	// QUERY_ANALYTICS=true, registrando as consultas
+	Service domain.RAGService
+
+	LLMConfig llm.Config
+	// Repository é o banco com novas tentativas, traces e métricas
+	Repository domain.DocumentRepository
+	Splitter   chunking.Splitter
+	Embedder   domain.EmbeddingClient
+
+	ResponseCache domain.ResponseCache
+	QueryCache    domain.QueryCache
+	Events        domain.EventPublisher
+	Audit         domain.AuditRepository
+	Quality       domain.QualityRepository
+	Feedback      domain.FeedbackRepository
+	Analytics     domain.AnalyticsRepository
+
+	rag     *service.RAGServiceImpl
+	closers []func() error // Liberados em ordem inversa em Close
+}
+
+// New monta o serviço RAG com a configuração do ambiente, usando o banco
+// informado. Em caso de erro, as conexões já abertas são fechadas; o banco
+// continua com quem o abriu.
+func New(ctx context.Context, db database.Store) (_ *App, err error) {
+	a := &App{LLMConfig: llm.ConfigFromEnv()}
+	defer func() {
+		if err != nil {
+			a.closeAll()
+		}
+	}()
+
+	// Cada provedor de LLM_PROVIDER e LLM_FALLBACK repete as chamadas que
+	// falham por motivos transitórios e recusa as chamadas enquanto está
+	// fora do ar, passando a vez ao próximo
+	retryPolicy := resilience.RetryPolicyFromEnv()
+	breakerConfig := resilience.BreakerConfigFromEnv()
+	client, err := llm.NewChain(a.LLMConfig, func(provider domain.LLMClient) domain.LLMClient {
+		return resilience.NewCircuitBreaker(resilience.NewRetryLLMClient(provider, retryPolicy), breakerConfig)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar cliente de LLM: %w", err)
+	}
+	// Mascara dados pessoais nas mensagens enviadas ao LLM, quando PII_REDACTION=true
+	if pipeline := guardrail.New(guardrail.ConfigFromEnv()); pipeline != nil {
+		client = guardrail.NewLLMClient(client, pipeline)
+	}
+	// Instrumenta todas as chamadas ao LLM (agente e reranker) para /metrics e traces
+	// e mede o consumo de tokens usado no custo das respostas
+	client = metrics.NewLLMClient(tracing.NewLLMClient(pricing.NewLLMClient(client)))
+
+	// Documentos longos recebidos pela API são divididos em chunks
+	if a.Splitter, err = chunking.NewSplitter(chunking.ConfigFromEnv()); err != nil {
+		return nil, fmt.Errorf("erro na configuração de chunking: %w", err)
+	}
+	// Preços dos modelos, com os valores de LLM_PRICES_FILE sobre os padrões
+	prices, err := pricing.TableFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar tabela de preços: %w", err)
+	}
+	// Janelas de contexto dos modelos, com os valores de LLM_CONTEXT_WINDOWS_FILE sobre os padrões
+	windows, err := tokenizer.ContextWindowsFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar janelas de contexto: %w", err)
+	}
+	// Prompts enviados ao LLM, com os arquivos de PROMPTS_DIR sobre os padrões
+	promptSet, err := prompts.New(prompts.ConfigFromEnv())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar prompts: %w", err)
+	}
+
+	opts := []service.Option{
+		service.WithConfig(service.ConfigFromEnv()),
+		service.WithConversationRepository(db.Conversations()),
+		service.WithUsageRepository(db.Usage()),
+		service.WithPriceTable(prices),
+		service.WithPrompts(promptSet),
+		// Conta os tokens dos prompts com a codificação do modelo configurado
+		service.WithTokenizer(tokenizer.New(a.LLMConfig.Model())),
+		service.WithSplitter(a.Splitter),
+	}
+	// Limita os prompts à janela de contexto do modelo, descartando o histórico mais antigo
+	if window, ok := windows.Lookup(a.LLMConfig.Model()); ok {
+		opts = append(opts, service.WithContextWindow(window))
+	}
+	// A busca vetorial é habilitada quando há um cliente de embeddings disponível
+	if embeddingClient := llm.NewEmbeddingClient(a.LLMConfig); embeddingClient != nil {
+		a.Embedder = resilience.NewRetryEmbeddingClient(embeddingClient, retryPolicy)
+		opts = append(opts, service.WithEmbeddingClient(a.Embedder))
+	}
+	// Reordena os documentos recuperados, quando configurado em RERANKER
+	reranker, err := rerank.New(rerank.ConfigFromEnv(), client)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar reranker: %w", err)
+	}
+	if reranker != nil {
+		opts = append(opts, service.WithReranker(reranker))
+	}
+	// Escolhe o modelo de cada pergunta pela complexidade, quando configurado em ROUTER_SMALL_MODEL e ROUTER_LARGE_MODEL
+	router, err := routing.New(routing.ConfigFromEnv())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar roteador de modelos: %w", err)
+	}
+	if router != nil {
+		opts = append(opts, service.WithModelRouter(router))
+	}
+	// Busca na web quando a base não tem a resposta, quando configurada em WEB_SEARCH_PROVIDER
+	webSearcher, err := websearch.New(websearch.ConfigFromEnv())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar busca na web: %w", err)
+	}
+	if webSearcher != nil {
+		opts = append(opts, service.WithWebSearcher(webSearcher))
+	}
+
+	registry, err := a.toolRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, service.WithToolRegistry(registry))
+
+	// Reaproveita respostas de perguntas semelhantes, quando REDIS_URL está definida
+	cacheConfig := cache.ConfigFromEnv()
+	responseCache, err := cache.New(ctx, cacheConfig)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao cache: %w", err)
+	}
+	if responseCache != nil {
+		a.closers = append(a.closers, responseCache.Close)
+		a.ResponseCache = responseCache
+		opts = append(opts, service.WithResponseCache(responseCache))
+	}
+	// Reaproveita respostas de perguntas idênticas, quando QUERY_CACHE está definida
+	queryCache, err := cache.NewQueryCache(ctx, cacheConfig)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao configurar cache de perguntas: %w", err)
+	}
+	if queryCache != nil {
+		a.closers = append(a.closers, queryCache.Close)
+		a.QueryCache = queryCache
+		opts = append(opts, service.WithQueryCache(queryCache))
+	}
+	// Modera perguntas e respostas com a OpenAI, conforme a política de RAG_MODERATION
+	if moderator := llm.NewModerationClient(a.LLMConfig); moderator != nil {
+		opts = append(opts, service.WithModerationClient(moderator))
+	}
+	// Guarda as notas das respostas avaliadas por amostragem (RAG_QUALITY_SAMPLE_RATE)
+	if repo := db.Quality(); repo != nil {
+		a.Quality = metrics.NewQualityRepository(repo)
+		opts = append(opts, service.WithQualityRepository(a.Quality))
+	}
+	// Avaliações dos usuários, recebidas em POST /v1/feedback e usadas na
+	// ordenação das buscas (RAG_FEEDBACK_RANKING)
+	if feedback := db.Feedback(); feedback != nil {
+		a.Feedback = feedback
+		opts = append(opts, service.WithFeedbackRepository(feedback))
+	}
+	// Limita as perguntas de cada usuário, quando RATE_LIMIT_PER_MINUTE está definida
+	limiter, err := ratelimit.New(ctx, ratelimit.ConfigFromEnv())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao configurar limite de perguntas: %w", err)
+	}
+	if limiter != nil {
+		a.closers = append(a.closers, limiter.Close)
+		opts = append(opts, service.WithRateLimiter(limiter))
+	}
+	// Estado das sessões entre as perguntas (SESSION_STORE=memory ou redis)
+	sessions, err := session.New(ctx, session.ConfigFromEnv())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao configurar sessões: %w", err)
+	}
+	if sessions != nil {
+		a.closers = append(a.closers, sessions.Close)
+		opts = append(opts, service.WithSessionStore(sessions))
+	}
+	// Notifica os documentos inseridos e as perguntas respondidas aos endpoints de WEBHOOK_URLS
+	dispatcher, err := webhook.New(webhook.ConfigFromEnv())
+	if err != nil {
+		return nil, fmt.Errorf("erro na configuração dos webhooks: %w", err)
+	}
+	if dispatcher != nil {
+		a.closers = append(a.closers, dispatcher.Close)
+		a.Events = dispatcher
+		opts = append(opts, service.WithEventPublisher(dispatcher))
+	}
+	// Registra perguntas, respostas, ferramentas e alterações de documentos (AUDIT_LOG=true)
+	if a.Audit, err = database.AuditFromEnv(db); err != nil {
+		return nil, fmt.Errorf("erro ao configurar auditoria: %w", err)
+	}
+	if a.Audit != nil {
+		opts = append(opts, service.WithAuditLog(a.Audit))
+	}
+	// Registra cada pergunta e busca para as estatísticas de uso (QUERY_ANALYTICS=true)
+	if a.Analytics, err = database.AnalyticsFromEnv(db); err != nil {
+		return nil, fmt.Errorf("erro ao configurar estatísticas das consultas: %w", err)
+	}
+
+	// As operações no banco e no serviço também são instrumentadas
+	a.Repository = metrics.NewDocumentRepository(tracing.NewDocumentRepository(resilience.NewRetryRepository(db, retryPolicy)))
+	a.rag = service.NewRAGService(client, a.Repository, opts...)
+	a.Service = metrics.NewService(a.rag)
+	if a.Analytics != nil {
+		recorder := analytics.NewService(a.Service, a.Analytics)
+		a.closers = append(a.closers, recorder.Close)
+		a.Service = recorder
+	}
+	return a, nil
+}
+
+// toolRegistry registra as ferramentas do agente: as embutidas (calculadora
+// e, quando configurada em CODE_RUNNER, execução de código isolada), as
+// consultas SQL de SQL_TOOL_DATABASES_FILE e as dos servidores MCP de
+// MCP_SERVERS_FILE
+func (a *App) toolRegistry(ctx context.Context) (*tools.Registry, error) {
+	codeRunner, err := sandbox.New(sandbox.ConfigFromEnv())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar executor de código: %w", err)
+	}
+	registry := tools.NewRegistry()
+	if err := tools.RegisterBuiltins(registry, codeRunner); err != nil {
+		return nil, fmt.Errorf("erro ao registrar ferramentas: %w", err)
+	}
+
+	sqlConfig, err := sqlquery.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("erro na configuração da ferramenta de SQL: %w", err)
+	}
+	sqlTool, err := sqlquery.New(ctx, sqlConfig)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar ferramenta de SQL: %w", err)
+	}
+	if sqlTool != nil {
+		a.closers = append(a.closers, func() error { sqlTool.Close(); return nil })
+		if err := registry.Register(sqlTool.Definition(), sqlTool.Handle); err != nil {
+			return nil, fmt.Errorf("erro ao registrar ferramenta de SQL: %w", err)
+		}
+	}
+
+	mcpServers, err := mcp.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("erro na configuração dos servidores MCP: %w", err)
+	}
+	for _, client := range mcp.RegisterTools(ctx, registry, mcpServers) {
+		a.closers = append(a.closers, client.Close)
+	}
+	return registry, nil
+}
+
+// IngestOptions retorna as opções da ingestão que mantêm os caches, os
+// webhooks e a auditoria coerentes com o serviço
+func (a *App) IngestOptions() []ingest.Option {
+	opts := []ingest.Option{ingest.WithSplitter(a.Splitter)}
+	if a.Embedder != nil {
+		opts = append(opts, ingest.WithEmbeddingClient(a.Embedder))
+	}
+	if a.ResponseCache != nil {
+		opts = append(opts, ingest.WithResponseCache(a.ResponseCache))
+	}
+	if a.QueryCache != nil {
+		opts = append(opts, ingest.WithQueryCache(a.QueryCache))
+	}
+	if a.Events != nil {
+		opts = append(opts, ingest.WithEventPublisher(a.Events))
+	}
+	if a.Audit != nil {
+		opts = append(opts, ingest.WithAuditLog(a.Audit))
+	}
+	return opts
+}
+
+// Close espera as tarefas em segundo plano do serviço (como a avaliação de
+// qualidade e a gravação das conversas) até o fim do contexto e fecha as
+// conexões abertas por New. O banco continua aberto.
+func (a *App) Close(ctx context.Context) error {
+	err := a.rag.Close(ctx)
+	return errors.Join(err, a.closeAll())
+}
+
+// closeAll libera as conexões em ordem inversa à de abertura
+func (a *App) closeAll() error {
+	var errs []error
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		if err := a.closers[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	a.closers = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,153 @@
+// Package backup lê e grava arquivos de exportação da base, com um
+// registro (documento ou conversa) por entrada, em JSONL ou BSON
+package backup
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Formatos de arquivo suportados
+const (
+	FormatJSONL = "jsonl" // Um objeto JSON por linha
+	FormatBSON  = "bson"  // Documentos BSON concatenados, como no mongodump
+)
+
+// FormatFromPath deduz o formato pela extensão do arquivo (JSONL por padrão)
+func FormatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".bson") {
+		return FormatBSON
+	}
+	return FormatJSONL
+}
+
+// documentRecord é o documento no JSONL, incluindo o embedding, que fica
+// fora do JSON de domain.Document
+type documentRecord struct {
+	domain.Document
+	Embedding []float32 `json:"embedding,omitempty"`
+}
+
+// Writer grava registros em um arquivo de exportação
+type Writer struct {
+	w      *bufio.Writer
+	format string
+	enc    *json.Encoder
+}
+
+// NewWriter cria um Writer no formato informado. Flush deve ser chamado ao final.
+func NewWriter(w io.Writer, format string) (*Writer, error) {
+	if format != FormatJSONL && format != FormatBSON {
+		return nil, fmt.Errorf("formato de backup desconhecido: %q", format)
+	}
+	bw := bufio.NewWriter(w)
+	return &Writer{w: bw, format: format, enc: json.NewEncoder(bw)}, nil
+}
+
+// WriteDocument grava um documento com o seu embedding
+func (w *Writer) WriteDocument(doc domain.Document) error {
+	if w.format == FormatJSONL {
+		return w.write(documentRecord{Document: doc, Embedding: doc.Embedding})
+	}
+	return w.write(doc)
+}
+
+// WriteConversation grava uma conversa
+func (w *Writer) WriteConversation(conv domain.Conversation) error {
+	return w.write(conv)
+}
+
+// Flush grava os dados pendentes no destino
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+func (w *Writer) write(v any) error {
+	if w.format == FormatJSONL {
+		if err := w.enc.Encode(v); err != nil {
+			return fmt.Errorf("erro ao gravar registro: %w", err)
+		}
+		return nil
+	}
+
+	data, err := bson.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar registro: %w", err)
+	}
+	if _, err := w.w.Write(data); err != nil {
+		return fmt.Errorf("erro ao gravar registro: %w", err)
+	}
+	return nil
+}
+
+// Reader lê registros de um arquivo de exportação. Os métodos retornam
+// io.EOF ao fim do arquivo.
+type Reader struct {
+	r      *bufio.Reader
+	format string
+	dec    *json.Decoder
+}
+
+// NewReader cria um Reader no formato informado
+func NewReader(r io.Reader, format string) (*Reader, error) {
+	if format != FormatJSONL && format != FormatBSON {
+		return nil, fmt.Errorf("formato de backup desconhecido: %q", format)
+	}
+	br := bufio.NewReader(r)
+	return &Reader{r: br, format: format, dec: json.NewDecoder(br)}, nil
+}
+
+// ReadDocument lê o próximo documento, com o seu embedding
+func (r *Reader) ReadDocument() (domain.Document, error) {
+	if r.format == FormatJSONL {
+		var record documentRecord
+		if err := r.read(&record); err != nil {
+			return domain.Document{}, err
+		}
+		record.Document.Embedding = record.Embedding
+		return record.Document, nil
+	}
+
+	var doc domain.Document
+	err := r.read(&doc)
+	return doc, err
+}
+
+// ReadConversation lê a próxima conversa
+func (r *Reader) ReadConversation() (domain.Conversation, error) {
+	var conv domain.Conversation
+	err := r.read(&conv)
+	return conv, err
+}
+
+func (r *Reader) read(v any) error {
+	if r.format == FormatJSONL {
+		if err := r.dec.Decode(v); err != nil {
+			if errors.Is(err, io.EOF) {
+				return io.EOF
+			}
+			return fmt.Errorf("erro ao ler registro: %w", err)
+		}
+		return nil
+	}
+
+	raw, err := bson.ReadDocument(r.r)
+	if errors.Is(err, io.EOF) {
+		return io.EOF
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao ler registro: %w", err)
+	}
+	if err := bson.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("erro ao decodificar registro: %w", err)
+	}
+	return nil
+}
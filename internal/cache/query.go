@@ -0,0 +1,223 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultQueryCacheSize é a quantidade padrão de respostas do cache "memory"
+const DefaultQueryCacheSize = 1000
+
+// queryKeyPrefix é o prefixo das chaves do cache de perguntas exatas no Redis
+const queryKeyPrefix = "rag:query:"
+
+// QueryCache é um domain.QueryCache que mantém recursos a liberar
+type QueryCache interface {
+	domain.QueryCache
+	Close() error
+}
+
+// NewQueryCache cria o cache de perguntas exatas configurado em QueryCache.
+// Retorna nil quando o cache está desabilitado.
+func NewQueryCache(ctx context.Context, cfg Config) (QueryCache, error) {
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	switch cfg.QueryCache {
+	case "":
+		return nil, nil
+	case "memory":
+		return NewMemoryQueryCache(cfg.QueryCacheSize, ttl), nil
+	case "redis":
+		if cfg.RedisURL == "" {
+			return nil, errors.New("QUERY_CACHE=redis requer REDIS_URL")
+		}
+		opts, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao interpretar REDIS_URL: %w", err)
+		}
+		client := redis.NewClient(opts)
+		if err := client.Ping(ctx).Err(); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("erro ao conectar ao Redis: %w", err)
+		}
+		return &RedisQueryCache{client: client, ttl: ttl}, nil
+	default:
+		return nil, fmt.Errorf("cache de perguntas desconhecido: %q (use memory ou redis)", cfg.QueryCache)
+	}
+}
+
+// MemoryQueryCache implementa domain.QueryCache em memória, descartando as
+// respostas usadas há mais tempo quando atinge a capacidade. O cache é
+// local ao processo: a invalidação feita por outros processos (como o
+// comando de ingestão) não o alcança, e as respostas duram no máximo o TTL.
+type MemoryQueryCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List // Da resposta usada mais recentemente para a mais antiga
+	items    map[string]*list.Element
+}
+
+// memoryEntry é o valor de cada elemento da lista do MemoryQueryCache
+type memoryEntry struct {
+	key       string
+	response  domain.RAGResponse
+	expiresAt time.Time
+}
+
+// NewMemoryQueryCache cria um cache em memória com a capacidade e o TTL
+// informados; valores não positivos usam os padrões
+func NewMemoryQueryCache(capacity int, ttl time.Duration) *MemoryQueryCache {
+	if capacity <= 0 {
+		capacity = DefaultQueryCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &MemoryQueryCache{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get retorna uma cópia da resposta guardada com a chave
+func (c *MemoryQueryCache) Get(ctx context.Context, key string) (*domain.RAGResponse, error) {
+	key = tenantKey(ctx, key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, nil
+	}
+	e := elem.Value.(*memoryEntry)
+	if time.Now().After(e.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.items, key)
+		return nil, nil
+	}
+	c.order.MoveToFront(elem)
+
+	resp := e.response
+	return &resp, nil
+}
+
+// Set guarda uma cópia da resposta, descartando a usada há mais tempo quando
+// o cache está cheio
+func (c *MemoryQueryCache) Set(ctx context.Context, key string, resp *domain.RAGResponse) error {
+	key = tenantKey(ctx, key)
+	e := &memoryEntry{key: key, response: cacheable(resp), expiresAt: time.Now().Add(c.ttl)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value = e
+		c.order.MoveToFront(elem)
+		return nil
+	}
+	c.items[key] = c.order.PushFront(e)
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Invalidate descarta todas as respostas guardadas
+func (c *MemoryQueryCache) Invalidate(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.items)
+	return nil
+}
+
+// Close não tem recursos a liberar
+func (c *MemoryQueryCache) Close() error {
+	return nil
+}
+
+// RedisQueryCache implementa domain.QueryCache no Redis, com TTL em cada
+// resposta. As chaves incluem a geração do cache semântico, então a
+// invalidação feita por qualquer um dos dois caches, em qualquer processo,
+// descarta as respostas de ambos.
+type RedisQueryCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// Get retorna a resposta guardada com a chave
+func (c *RedisQueryCache) Get(ctx context.Context, key string) (*domain.RAGResponse, error) {
+	gen, err := generation(ctx, c.client)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.client.Get(ctx, queryKey(ctx, gen, key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler resposta do cache: %w", err)
+	}
+
+	var resp domain.RAGResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar resposta do cache: %w", err)
+	}
+	return &resp, nil
+}
+
+// Set guarda a resposta com a chave
+func (c *RedisQueryCache) Set(ctx context.Context, key string, resp *domain.RAGResponse) error {
+	gen, err := generation(ctx, c.client)
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(cacheable(resp))
+	if err != nil {
+		return fmt.Errorf("erro ao serializar resposta: %w", err)
+	}
+	if err := c.client.Set(ctx, queryKey(ctx, gen, key), value, c.ttl).Err(); err != nil {
+		return fmt.Errorf("erro ao guardar resposta no cache: %w", err)
+	}
+	return nil
+}
+
+// Invalidate descarta as respostas guardadas, iniciando uma nova geração
+func (c *RedisQueryCache) Invalidate(ctx context.Context) error {
+	return invalidate(ctx, c.client)
+}
+
+// Close fecha a conexão com o Redis
+func (c *RedisQueryCache) Close() error {
+	return c.client.Close()
+}
+
+// tenantKey combina a chave com o tenant do contexto
+func tenantKey(ctx context.Context, key string) string {
+	return "t=" + domain.TenantFromContext(ctx) + ":" + key
+}
+
+// queryKey retorna a chave no Redis da resposta de uma geração no tenant do contexto
+func queryKey(ctx context.Context, gen int64, key string) string {
+	return fmt.Sprintf("%s%d:%s", queryKeyPrefix, gen, tenantKey(ctx, key))
+}
@@ -0,0 +1,244 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/vector"
+	"github.com/redis/go-redis/v9"
+)
+
+// Valores padrão do cache semântico
+const (
+	DefaultTTL                 = time.Hour
+	DefaultSimilarityThreshold = 0.95
+)
+
+// keyPrefix é o prefixo das chaves do cache no Redis
+const keyPrefix = "rag:cache:"
+
+// generationKey guarda a geração atual, comum ao cache semântico e ao de
+// perguntas exatas: invalidar um deles invalida os dois
+const generationKey = keyPrefix + "generation"
+
+// scanBatchSize é a quantidade de chaves lidas por iteração ao procurar uma resposta
+const scanBatchSize = 100
+
+// Config contém as configurações dos caches de respostas
+type Config struct {
+	RedisURL string // Endereço do Redis; vazio desabilita o cache semântico
+	// TTL é o tempo de vida de cada resposta guardada
+	TTL time.Duration
+	// SimilarityThreshold é a similaridade de cosseno mínima (0 a 1) para
+	// considerar duas perguntas equivalentes
+	SimilarityThreshold float64
+
+	// QueryCache é o armazenamento do cache de perguntas exatas: "memory",
+	// "redis" (usando RedisURL) ou vazio para desabilitá-lo
+	QueryCache string
+	// QueryCacheSize é a quantidade máxima de respostas do cache "memory"
+	QueryCacheSize int
+}
+
+// ConfigFromEnv lê a configuração do cache a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	cfg := Config{
+		RedisURL:   os.Getenv("REDIS_URL"),
+		QueryCache: os.Getenv("QUERY_CACHE"),
+	}
+	cfg.TTL, _ = time.ParseDuration(os.Getenv("CACHE_TTL"))
+	cfg.SimilarityThreshold, _ = strconv.ParseFloat(os.Getenv("CACHE_SIMILARITY_THRESHOLD"), 64)
+	cfg.QueryCacheSize, _ = strconv.Atoi(os.Getenv("QUERY_CACHE_SIZE"))
+	return cfg
+}
+
+// SemanticCache implementa domain.ResponseCache no Redis. Cada resposta é
+// guardada em uma chave com TTL, junto ao embedding da pergunta; a busca
+// compara o vetor com todas as entradas, o que é adequado para caches de
+// até alguns milhares de respostas.
+//
+// As chaves incluem o tenant do contexto, então cada tenant só reaproveita as
+// próprias respostas. A invalidação incrementa uma geração, comum a todos os
+// tenants, que faz parte das chaves: as entradas antigas deixam de ser lidas
+// e expiram pelo TTL.
+type SemanticCache struct {
+	client    *redis.Client
+	ttl       time.Duration
+	threshold float64
+}
+
+// entry é o valor guardado no Redis para cada resposta
+type entry struct {
+	Vector   []float32          `json:"vector"`
+	Response domain.RAGResponse `json:"response"`
+}
+
+// New cria o cache semântico configurado. Retorna nil quando o cache está desabilitado.
+func New(ctx context.Context, cfg Config) (*SemanticCache, error) {
+	if cfg.RedisURL == "" {
+		return nil, nil
+	}
+
+	opts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar REDIS_URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("erro ao conectar ao Redis: %w", err)
+	}
+
+	c := &SemanticCache{
+		client:    client,
+		ttl:       cfg.TTL,
+		threshold: cfg.SimilarityThreshold,
+	}
+	if c.ttl <= 0 {
+		c.ttl = DefaultTTL
+	}
+	if c.threshold <= 0 || c.threshold > 1 {
+		c.threshold = DefaultSimilarityThreshold
+	}
+	return c, nil
+}
+
+// Close fecha a conexão com o Redis
+func (c *SemanticCache) Close() error {
+	return c.client.Close()
+}
+
+// Lookup retorna a resposta guardada mais semelhante ao vetor, desde que a
+// similaridade atinja o limite configurado
+func (c *SemanticCache) Lookup(ctx context.Context, embedding []float32) (*domain.RAGResponse, error) {
+	gen, err := c.generation(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *domain.RAGResponse
+	bestScore := c.threshold
+
+	iter := c.client.Scan(ctx, 0, entryPattern(ctx, gen), scanBatchSize).Iterator()
+	var keys []string
+	flush := func() error {
+		if len(keys) == 0 {
+			return nil
+		}
+		values, err := c.client.MGet(ctx, keys...).Result()
+		if err != nil {
+			return fmt.Errorf("erro ao ler respostas do cache: %w", err)
+		}
+		keys = keys[:0]
+
+		for _, value := range values {
+			raw, ok := value.(string)
+			if !ok {
+				continue // Expirou entre o SCAN e o MGET
+			}
+			var e entry
+			if err := json.Unmarshal([]byte(raw), &e); err != nil || len(e.Vector) != len(embedding) {
+				continue
+			}
+			if score := vector.Cosine(embedding, e.Vector); score >= bestScore {
+				bestScore = score
+				best = &e.Response
+			}
+		}
+		return nil
+	}
+
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == scanBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao percorrer o cache: %w", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return best, nil
+}
+
+// Store guarda a resposta da pergunta representada pelo vetor
+func (c *SemanticCache) Store(ctx context.Context, vector []float32, resp *domain.RAGResponse) error {
+	gen, err := c.generation(ctx)
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(entry{Vector: vector, Response: cacheable(resp)})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar resposta: %w", err)
+	}
+
+	key := entryPrefix(ctx, gen) + rand.Text()
+	if err := c.client.Set(ctx, key, value, c.ttl).Err(); err != nil {
+		return fmt.Errorf("erro ao guardar resposta no cache: %w", err)
+	}
+	return nil
+}
+
+// Invalidate descarta as respostas guardadas, iniciando uma nova geração
+func (c *SemanticCache) Invalidate(ctx context.Context) error {
+	return invalidate(ctx, c.client)
+}
+
+// generation retorna a geração atual do cache
+func (c *SemanticCache) generation(ctx context.Context) (int64, error) {
+	return generation(ctx, c.client)
+}
+
+// invalidate inicia uma nova geração, descartando as respostas do cache
+// semântico e do cache de perguntas exatas no Redis
+func invalidate(ctx context.Context, client *redis.Client) error {
+	if err := client.Incr(ctx, generationKey).Err(); err != nil {
+		return fmt.Errorf("erro ao invalidar o cache: %w", err)
+	}
+	return nil
+}
+
+// generation retorna a geração atual dos caches no Redis
+func generation(ctx context.Context, client *redis.Client) (int64, error) {
+	gen, err := client.Get(ctx, generationKey).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("erro ao ler a geração do cache: %w", err)
+	}
+	return gen, nil
+}
+
+// cacheable retorna a cópia da resposta que é guardada: dados da sessão e de
+// diagnóstico não fazem parte da resposta reaproveitada
+func cacheable(resp *domain.RAGResponse) domain.RAGResponse {
+	cached := *resp
+	cached.SessionID = ""
+	cached.Steps = nil
+	return cached
+}
+
+// entryPrefix retorna o prefixo das chaves de uma geração no tenant do
+// contexto. IDs de tenant não contêm ":", então o prefixo do tenant padrão
+// (vazio) não coincide com o de outros tenants.
+func entryPrefix(ctx context.Context, gen int64) string {
+	return fmt.Sprintf("%s%d:t=%s:", keyPrefix, gen, domain.TenantFromContext(ctx))
+}
+
+// entryPattern retorna o padrão das chaves de uma geração no tenant do contexto
+func entryPattern(ctx context.Context, gen int64) string {
+	return entryPrefix(ctx, gen) + "*"
+}
@@ -0,0 +1,132 @@
+package chunking
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// minOverlap é o tamanho mínimo, em caracteres, para considerar que dois
+// chunks vizinhos compartilham um trecho sobreposto
+const minOverlap = 10
+
+// ChunkDocument divide um documento lógico em chunks ligados pelo parentID.
+// Documentos que cabem em um único chunk são retornados sem alterações.
+func ChunkDocument(doc domain.Document, splitter Splitter, parentID string) []domain.Document {
+	parts := splitter.Split(doc.Content)
+	if len(parts) <= 1 {
+		return []domain.Document{doc}
+	}
+
+	chunks := make([]domain.Document, 0, len(parts))
+	for i, part := range parts {
+		chunk := doc
+		chunk.ID = ""
+		chunk.Content = part
+		chunk.Embedding = nil
+		chunk.ParentID = parentID
+		chunk.ChunkIndex = i
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// Reassemble une os chunks de um mesmo documento lógico, na ordem do índice,
+// removendo o trecho sobreposto entre chunks vizinhos. O documento
+// resultante usa o parentID como ID.
+func Reassemble(chunks []domain.Document) domain.Document {
+	sorted := make([]domain.Document, len(chunks))
+	copy(sorted, chunks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ChunkIndex < sorted[j].ChunkIndex
+	})
+
+	doc := sorted[0]
+	doc.ID = doc.ParentID
+	doc.ParentID = ""
+	doc.ChunkIndex = 0
+	doc.Embedding = nil
+
+	var content strings.Builder
+	content.WriteString(sorted[0].Content)
+	for i := 1; i < len(sorted); i++ {
+		prev, next := sorted[i-1], sorted[i]
+		if next.ChunkIndex == prev.ChunkIndex+1 {
+			if n := overlapLength(prev.Content, next.Content); n > 0 {
+				content.WriteString(next.Content[n:])
+				continue
+			}
+		}
+		content.WriteString("\n")
+		content.WriteString(next.Content)
+	}
+	doc.Content = content.String()
+	return doc
+}
+
+// MergeChunks agrupa os chunks de um mesmo documento lógico em um único
+// documento, mantendo a ordem de relevância da primeira ocorrência.
+// Documentos que não são chunks são mantidos como estão.
+func MergeChunks(docs []domain.Document) []domain.Document {
+	groups := map[string][]domain.Document{}
+	var order []string
+	var result []domain.Document
+	positions := map[string]int{}
+
+	for _, doc := range docs {
+		if doc.ParentID == "" {
+			result = append(result, doc)
+			continue
+		}
+		if _, ok := groups[doc.ParentID]; !ok {
+			order = append(order, doc.ParentID)
+			positions[doc.ParentID] = len(result)
+			result = append(result, domain.Document{}) // Reservado para o documento unido
+		}
+		groups[doc.ParentID] = append(groups[doc.ParentID], doc)
+	}
+
+	for _, parentID := range order {
+		result[positions[parentID]] = Reassemble(groups[parentID])
+	}
+	return result
+}
+
+// overlapLength retorna o tamanho, em bytes, do maior sufixo de prev que
+// também é prefixo de next
+func overlapLength(prev, next string) int {
+	for n := min(len(prev), len(next)); n >= minOverlap; n-- {
+		if strings.HasSuffix(prev, next[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+// ExpandWindow acrescenta ao chunk até sentences frases do texto original
+// antes e depois dele. O texto original é o documento lógico remontado a
+// partir de todos os chunks, então as frases podem vir dos chunks vizinhos.
+// Chunks fora do documento remontado são retornados sem alterações.
+func ExpandWindow(chunk domain.Document, chunks []domain.Document, sentences int) domain.Document {
+	if sentences <= 0 || len(chunks) == 0 {
+		return chunk
+	}
+	original := Reassemble(chunks).Content
+	start := strings.Index(original, chunk.Content)
+	if start < 0 {
+		return chunk
+	}
+	end := start + len(chunk.Content)
+
+	// O trecho anterior pode terminar no meio de uma frase, que é completada
+	// pelo início do chunk e conta como uma das frases
+	before := splitSentences(original[:start])
+	before = before[max(len(before)-sentences, 0):]
+	after := splitSentences(original[end:])
+	after = after[:min(len(after), sentences)]
+
+	parts := append(before, strings.TrimSpace(chunk.Content))
+	chunk.Content = strings.Join(append(parts, after...), " ")
+	return chunk
+}
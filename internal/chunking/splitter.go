@@ -0,0 +1,217 @@
+package chunking
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Estratégias de divisão disponíveis
+const (
+	StrategyRecursive = "recursive"
+	StrategySentence  = "sentence"
+)
+
+// Valores padrão da divisão em chunks, medidos em caracteres
+const (
+	DefaultChunkSize    = 1000
+	DefaultChunkOverlap = 200
+)
+
+// Config contém as configurações de divisão em chunks
+type Config struct {
+	Strategy     string // "recursive" (padrão) ou "sentence"
+	ChunkSize    int    // Tamanho máximo de cada chunk, em caracteres
+	ChunkOverlap int    // Quantidade de caracteres repetidos entre chunks vizinhos
+}
+
+// ConfigFromEnv lê a configuração de chunks a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	cfg := Config{Strategy: os.Getenv("CHUNK_STRATEGY")}
+	cfg.ChunkSize, _ = strconv.Atoi(os.Getenv("CHUNK_SIZE"))
+	cfg.ChunkOverlap, _ = strconv.Atoi(os.Getenv("CHUNK_OVERLAP"))
+	return cfg
+}
+
+// Splitter divide um texto em trechos menores
+type Splitter interface {
+	Split(text string) []string
+}
+
+// NewSplitter cria o splitter da estratégia configurada
+func NewSplitter(cfg Config) (Splitter, error) {
+	if cfg.ChunkSize <= 0 {
+		cfg.ChunkSize = DefaultChunkSize
+	}
+	if cfg.ChunkOverlap <= 0 {
+		cfg.ChunkOverlap = min(DefaultChunkOverlap, cfg.ChunkSize/5)
+	}
+	if cfg.ChunkOverlap >= cfg.ChunkSize {
+		return nil, fmt.Errorf("sobreposição (%d) deve ser menor que o tamanho do chunk (%d)", cfg.ChunkOverlap, cfg.ChunkSize)
+	}
+
+	switch cfg.Strategy {
+	case "", StrategyRecursive:
+		return &RecursiveSplitter{
+			size:       cfg.ChunkSize,
+			overlap:    cfg.ChunkOverlap,
+			separators: []string{"\n\n", "\n", ". ", " "},
+		}, nil
+	case StrategySentence:
+		return &SentenceSplitter{size: cfg.ChunkSize, overlap: cfg.ChunkOverlap}, nil
+	default:
+		return nil, fmt.Errorf("estratégia de chunking desconhecida: %q", cfg.Strategy)
+	}
+}
+
+// RecursiveSplitter divide o texto pelo maior separador possível (parágrafos,
+// linhas, frases e, por fim, palavras), recorrendo ao próximo separador
+// apenas para os trechos que ainda excedem o tamanho máximo
+type RecursiveSplitter struct {
+	size       int
+	overlap    int
+	separators []string
+}
+
+// Split divide o texto em chunks de até size caracteres
+func (s *RecursiveSplitter) Split(text string) []string {
+	return s.split(strings.TrimSpace(text), s.separators)
+}
+
+// split divide o texto usando o primeiro separador presente
+func (s *RecursiveSplitter) split(text string, separators []string) []string {
+	if text == "" {
+		return nil
+	}
+	if runeLen(text) <= s.size {
+		return []string{text}
+	}
+
+	for i, sep := range separators {
+		if !strings.Contains(text, sep) {
+			continue
+		}
+
+		var pieces []string
+		for _, part := range strings.Split(text, sep) {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if runeLen(part) > s.size {
+				pieces = append(pieces, s.split(part, separators[i+1:])...)
+				continue
+			}
+			pieces = append(pieces, part)
+		}
+		return merge(pieces, sep, s.size, s.overlap)
+	}
+
+	// Nenhum separador encontrado: divide pelo número de caracteres
+	return hardSplit(text, s.size, s.overlap)
+}
+
+// SentenceSplitter agrupa frases inteiras em chunks, sem quebrá-las no meio
+// (exceto frases maiores que o próprio chunk)
+type SentenceSplitter struct {
+	size    int
+	overlap int
+}
+
+// Split divide o texto em chunks de até size caracteres
+func (s *SentenceSplitter) Split(text string) []string {
+	var pieces []string
+	for _, sentence := range splitSentences(text) {
+		if runeLen(sentence) > s.size {
+			pieces = append(pieces, hardSplit(sentence, s.size, s.overlap)...)
+			continue
+		}
+		pieces = append(pieces, sentence)
+	}
+	return merge(pieces, " ", s.size, s.overlap)
+}
+
+// splitSentences separa o texto em frases, considerando que uma frase
+// termina em '.', '!' ou '?' seguido de espaço
+func splitSentences(text string) []string {
+	var sentences []string
+	runes := []rune(text)
+	start := 0
+	for i, r := range runes {
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+		if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			continue
+		}
+		if sentence := strings.TrimSpace(string(runes[start : i+1])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = i + 1
+	}
+	if sentence := strings.TrimSpace(string(runes[start:])); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+	return sentences
+}
+
+// merge junta os trechos em chunks de até size caracteres. Ao fechar um
+// chunk, os últimos trechos (até overlap caracteres) são repetidos no início
+// do próximo, preservando o contexto entre chunks vizinhos.
+func merge(pieces []string, sep string, size, overlap int) []string {
+	var chunks []string
+	var window []string
+	total := 0 // Tamanho da janela unida pelo separador
+	sepLen := runeLen(sep)
+
+	for _, piece := range pieces {
+		pieceLen := runeLen(piece)
+		if len(window) > 0 && total+sepLen+pieceLen > size {
+			chunks = append(chunks, strings.Join(window, sep))
+
+			// Descarta o início da janela, mantendo o final como sobreposição
+			for len(window) > 0 && (total > overlap || total+sepLen+pieceLen > size) {
+				total -= runeLen(window[0])
+				if len(window) > 1 {
+					total -= sepLen
+				}
+				window = window[1:]
+			}
+		}
+
+		if len(window) > 0 {
+			total += sepLen
+		}
+		window = append(window, piece)
+		total += pieceLen
+	}
+
+	if len(window) > 0 {
+		chunks = append(chunks, strings.Join(window, sep))
+	}
+	return chunks
+}
+
+// hardSplit divide o texto em pedaços de size caracteres com sobreposição
+func hardSplit(text string, size, overlap int) []string {
+	runes := []rune(text)
+	step := size - overlap
+
+	var chunks []string
+	for start := 0; start < len(runes); start += step {
+		end := min(start+size, len(runes))
+		chunks = append(chunks, string(runes[start:end]))
+		if end == len(runes) {
+			break
+		}
+	}
+	return chunks
+}
+
+// runeLen retorna o tamanho do texto em caracteres
+func runeLen(s string) int {
+	return utf8.RuneCountInString(s)
+}
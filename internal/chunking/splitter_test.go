@@ -0,0 +1,98 @@
+package chunking
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		text string
+		want []string
+	}{
+		{
+			name: "texto vazio",
+			cfg:  Config{ChunkSize: 20},
+			text: "  \n ",
+		},
+		{
+			name: "texto menor que o chunk",
+			cfg:  Config{ChunkSize: 20},
+			text: "  curto  ",
+			want: []string{"curto"},
+		},
+		{
+			name: "parágrafos",
+			cfg:  Config{ChunkSize: 20, ChunkOverlap: 5},
+			text: "Primeiro parágrafo.\n\nSegundo parágrafo.",
+			want: []string{"Primeiro parágrafo.", "Segundo parágrafo."},
+		},
+		{
+			name: "palavras com sobreposição",
+			cfg:  Config{ChunkSize: 10, ChunkOverlap: 4},
+			text: "aaa bbb ccc ddd",
+			want: []string{"aaa bbb", "bbb ccc", "ccc ddd"},
+		},
+		{
+			name: "sem separadores",
+			cfg:  Config{ChunkSize: 4, ChunkOverlap: 1},
+			text: "abcdefghij",
+			want: []string{"abcd", "defg", "ghij"},
+		},
+		{
+			name: "conta caracteres, não bytes",
+			cfg:  Config{ChunkSize: 2},
+			text: "ááááá",
+			want: []string{"áá", "áá", "á"},
+		},
+		{
+			name: "frases inteiras",
+			cfg:  Config{Strategy: StrategySentence, ChunkSize: 30, ChunkOverlap: 1},
+			text: "Olá mundo. Tudo bem? Sim! 3.14 é pi.",
+			want: []string{"Olá mundo. Tudo bem? Sim!", "3.14 é pi."},
+		},
+		{
+			name: "frase maior que o chunk",
+			cfg:  Config{Strategy: StrategySentence, ChunkSize: 4, ChunkOverlap: 1},
+			text: "abcdefghij",
+			want: []string{"abcd", "defg", "ghij"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter, err := NewSplitter(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewSplitter: %v", err)
+			}
+			got := splitter.Split(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %q, esperado %q", tt.text, got, tt.want)
+			}
+			for _, chunk := range got {
+				if runeLen(chunk) > tt.cfg.ChunkSize {
+					t.Errorf("chunk %q maior que %d caracteres", chunk, tt.cfg.ChunkSize)
+				}
+			}
+		})
+	}
+}
+
+func TestNewSplitterErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "sobreposição igual ao chunk", cfg: Config{ChunkSize: 10, ChunkOverlap: 10}},
+		{name: "sobreposição maior que o padrão", cfg: Config{ChunkOverlap: DefaultChunkSize + 1}},
+		{name: "estratégia desconhecida", cfg: Config{Strategy: "semantic"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSplitter(tt.cfg); err == nil {
+				t.Errorf("NewSplitter(%+v) sem erro", tt.cfg)
+			}
+		})
+	}
+}
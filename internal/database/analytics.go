@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// analyticsCollection é a coleção do registro das consultas
+const analyticsCollection = "query_analytics"
+
+// AnalyticsRepository implementa domain.AnalyticsRepository no MongoDB, com
+// um documento por consulta. O percentil de latência usa $percentile, que
+// requer o MongoDB 7.0 ou mais recente.
+type AnalyticsRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAnalyticsRepository cria o repositório das estatísticas de uso usando
+// a mesma conexão do MongoDB
+func NewAnalyticsRepository(db *MongoDB) *AnalyticsRepository {
+	return &AnalyticsRepository{
+		collection: db.database.Collection(analyticsCollection),
+	}
+}
+
+// Record guarda a consulta no tenant do contexto
+func (r *AnalyticsRepository) Record(ctx context.Context, record domain.QueryRecord) error {
+	record.TenantID = domain.TenantFromContext(ctx)
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	if _, err := r.collection.InsertOne(ctx, record); err != nil {
+		return fmt.Errorf("erro ao registrar consulta: %w", err)
+	}
+	return nil
+}
+
+// Stats calcula as estatísticas das consultas do tenant do contexto em uma
+// única agregação, com um $facet para cada lista
+func (r *AnalyticsRepository) Stats(ctx context.Context, since time.Time, limit int) (*domain.QueryStats, error) {
+	countBy := func(field string) []bson.M {
+		return []bson.M{
+			{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+			{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+			{"$limit": limit},
+		}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: tenantFilter(ctx, bson.M{"created_at": bson.M{"$gte": since}})}},
+		{{Key: "$facet", Value: bson.M{
+			"summary": []bson.M{{"$group": bson.M{
+				"_id":       nil,
+				"queries":   bson.M{"$sum": 1},
+				"failed":    bson.M{"$sum": bson.M{"$cond": bson.A{"$failed", 1, 0}}},
+				"zero_hits": bson.M{"$sum": bson.M{"$cond": bson.A{"$zero_hit", 1, 0}}},
+				"avg":       bson.M{"$avg": "$latency_ms"},
+				"p95":       bson.M{"$percentile": bson.M{"input": "$latency_ms", "p": bson.A{0.95}, "method": "approximate"}},
+			}}},
+			"top":        countBy("query"),
+			"zero_hit":   append([]bson.M{{"$match": bson.M{"zero_hit": true}}}, countBy("query")...),
+			"categories": countBy("category"),
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular estatísticas das consultas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	type group struct {
+		Key   string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	var result []struct {
+		Summary []struct {
+			Queries  int       `bson:"queries"`
+			Failed   int       `bson:"failed"`
+			ZeroHits int       `bson:"zero_hits"`
+			Avg      float64   `bson:"avg"`
+			P95      []float64 `bson:"p95"`
+		} `bson:"summary"`
+		Top        []group `bson:"top"`
+		ZeroHit    []group `bson:"zero_hit"`
+		Categories []group `bson:"categories"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar estatísticas das consultas: %w", err)
+	}
+
+	stats := &domain.QueryStats{
+		TopQueries:     []domain.QueryCount{},
+		ZeroHitQueries: []domain.QueryCount{},
+		Categories:     []domain.CategoryCount{},
+	}
+	if len(result) == 0 {
+		return stats, nil
+	}
+	if summary := result[0].Summary; len(summary) > 0 {
+		stats.Queries = summary[0].Queries
+		stats.Failed = summary[0].Failed
+		stats.ZeroHits = summary[0].ZeroHits
+		stats.AvgLatencyMS = summary[0].Avg
+		if len(summary[0].P95) > 0 {
+			stats.P95LatencyMS = summary[0].P95[0]
+		}
+	}
+	for _, g := range result[0].Top {
+		stats.TopQueries = append(stats.TopQueries, domain.QueryCount{Query: g.Key, Count: g.Count})
+	}
+	for _, g := range result[0].ZeroHit {
+		stats.ZeroHitQueries = append(stats.ZeroHitQueries, domain.QueryCount{Query: g.Key, Count: g.Count})
+	}
+	for _, g := range result[0].Categories {
+		stats.Categories = append(stats.Categories, domain.CategoryCount{Category: g.Key, Count: g.Count})
+	}
+	return stats, nil
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// APIKeyRepository implementa domain.APIKeyRepository no MongoDB
+type APIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAPIKeyRepository cria o repositório de chaves de API usando a mesma
+// conexão do MongoDB
+func NewAPIKeyRepository(db *MongoDB) *APIKeyRepository {
+	return &APIKeyRepository{
+		collection: db.database.Collection("api_keys"),
+	}
+}
+
+// Create grava a chave com um novo ID. Enquanto a chave está ativa, o
+// documento não tem revoked_at.
+func (r *APIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	key.ID = primitive.NewObjectID().Hex()
+	if _, err := r.collection.InsertOne(ctx, key); err != nil {
+		key.ID = ""
+		return fmt.Errorf("erro ao gravar chave de API: %w", err)
+	}
+	return nil
+}
+
+// FindByHash busca a chave pelo hash do segredo
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := r.collection.FindOne(ctx, bson.M{"hash": hash}).Decode(&key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar chave de API: %w", err)
+	}
+	return &key, nil
+}
+
+// List retorna todas as chaves, da mais recente para a mais antiga
+func (r *APIKeyRepository) List(ctx context.Context) ([]domain.APIKey, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar chaves de API: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	keys := []domain.APIKey{}
+	if err = cursor.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar chaves de API: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke marca a chave como revogada, mantendo a data da primeira revogação
+func (r *APIKeyRepository) Revoke(ctx context.Context, id string) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$min": bson.M{"revoked_at": time.Now()}})
+	if err != nil {
+		return fmt.Errorf("erro ao revogar chave de API: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
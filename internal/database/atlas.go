@@ -0,0 +1,257 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Valores padrão do Atlas Vector Search
+const (
+	defaultAtlasVectorSize  = 1536 // Dimensão do text-embedding-3-small
+	defaultAtlasSimilarity  = "cosine"
+	defaultAtlasCandidates  = 200
+	atlasVectorSearchFactor = 10 // Resultados do $vectorSearch por resultado da busca, antes dos filtros
+	atlasFuzzyMaxEdits      = 2  // Letras trocadas, incluídas ou removidas toleradas em cada termo
+)
+
+// AtlasConfig contém as configurações do Atlas Vector Search e do Atlas
+// Search. Sem VectorIndex, a busca vetorial do MongoDB é feita na aplicação;
+// sem SearchIndex, a busca tolerante a erros de digitação também.
+type AtlasConfig struct {
+	VectorIndex string // Nome do índice vetorial; vazio desabilita
+	VectorSize  int    // Dimensão dos embeddings (padrão: 1536)
+	Similarity  string // cosine (padrão), euclidean ou dotProduct
+	// Candidates é a quantidade de vizinhos avaliados pelo índice em cada
+	// busca (numCandidates); mais candidatos melhoram a precisão
+	Candidates int
+	// SearchIndex é o nome do índice do Atlas Search usado na busca textual
+	// tolerante a erros de digitação; vazio desabilita
+	SearchIndex string
+}
+
+// atlasConfigFromEnv lê a configuração do Atlas a partir das variáveis de ambiente
+func atlasConfigFromEnv() AtlasConfig {
+	cfg := AtlasConfig{
+		VectorIndex: os.Getenv("MONGO_VECTOR_INDEX"),
+		Similarity:  os.Getenv("MONGO_VECTOR_SIMILARITY"),
+		SearchIndex: os.Getenv("MONGO_SEARCH_INDEX"),
+	}
+	cfg.VectorSize, _ = strconv.Atoi(os.Getenv("MONGO_VECTOR_SIZE"))
+	cfg.Candidates, _ = strconv.Atoi(os.Getenv("MONGO_VECTOR_CANDIDATES"))
+	return cfg
+}
+
+// withDefaults preenche os valores não configurados
+func (cfg AtlasConfig) withDefaults() AtlasConfig {
+	if cfg.VectorSize <= 0 {
+		cfg.VectorSize = defaultAtlasVectorSize
+	}
+	if cfg.Similarity == "" {
+		cfg.Similarity = defaultAtlasSimilarity
+	}
+	if cfg.Candidates < searchLimit*atlasVectorSearchFactor {
+		cfg.Candidates = max(defaultAtlasCandidates, searchLimit*atlasVectorSearchFactor)
+	}
+	return cfg
+}
+
+// setupAtlasIndexes cria os índices do Atlas configurados e ainda
+// inexistentes. O índice vetorial tem campos de filtro, que permitem
+// restringir a busca ao tenant, à categoria e às tags antes de escolher os
+// vizinhos; o índice de busca cobre o título e o conteúdo.
+func (m *MongoDB) setupAtlasIndexes(ctx context.Context) error {
+	if m.atlas.VectorIndex != "" {
+		definition := bson.M{
+			"fields": bson.A{
+				bson.M{
+					"type":          "vector",
+					"path":          "embedding",
+					"numDimensions": m.atlas.VectorSize,
+					"similarity":    m.atlas.Similarity,
+				},
+				bson.M{"type": "filter", "path": "tenant_id"},
+				bson.M{"type": "filter", "path": "category"},
+				bson.M{"type": "filter", "path": "tags"},
+			},
+		}
+		if err := m.ensureSearchIndex(ctx, m.atlas.VectorIndex, "vectorSearch", definition); err != nil {
+			return fmt.Errorf("erro ao criar índice vetorial: %w", err)
+		}
+	}
+
+	if m.atlas.SearchIndex != "" {
+		definition := bson.M{
+			"mappings": bson.M{
+				"dynamic": false,
+				"fields": bson.M{
+					"title":   bson.M{"type": "string"},
+					"content": bson.M{"type": "string"},
+				},
+			},
+		}
+		if err := m.ensureSearchIndex(ctx, m.atlas.SearchIndex, "search", definition); err != nil {
+			return fmt.Errorf("erro ao criar índice de busca: %w", err)
+		}
+	}
+	return nil
+}
+
+// ensureSearchIndex cria o índice do Atlas com o nome informado, quando ele
+// ainda não existe
+func (m *MongoDB) ensureSearchIndex(ctx context.Context, name, indexType string, definition bson.M) error {
+	exists, _, err := m.searchIndexStatus(ctx, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = m.collection.SearchIndexes().CreateOne(ctx, mongo.SearchIndexModel{
+		Definition: definition,
+		Options:    options.SearchIndexes().SetName(name).SetType(indexType),
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Índice %s criado; o Atlas o constrói em segundo plano", name)
+	return nil
+}
+
+// searchIndexStatus informa se o índice do Atlas existe e se já pode ser consultado
+func (m *MongoDB) searchIndexStatus(ctx context.Context, name string) (exists, queryable bool, err error) {
+	cursor, err := m.collection.SearchIndexes().List(ctx, options.SearchIndexes().SetName(name))
+	if err != nil {
+		return false, false, fmt.Errorf("erro ao listar índices do Atlas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var indexes []struct {
+		Queryable bool `bson:"queryable"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return false, false, fmt.Errorf("erro ao decodificar índices do Atlas: %w", err)
+	}
+	if len(indexes) == 0 {
+		return false, false, nil
+	}
+	return true, indexes[0].Queryable, nil
+}
+
+// checkAtlasIndexes verifica se os índices do Atlas configurados existem e
+// já podem ser consultados
+func (m *MongoDB) checkAtlasIndexes(ctx context.Context) error {
+	for _, name := range []string{m.atlas.VectorIndex, m.atlas.SearchIndex} {
+		if name == "" {
+			continue
+		}
+		exists, queryable, err := m.searchIndexStatus(ctx, name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("índice %q do Atlas não encontrado", name)
+		}
+		if !queryable {
+			return fmt.Errorf("índice %q do Atlas ainda em construção", name)
+		}
+	}
+	return nil
+}
+
+// searchByAtlasVector busca os documentos mais similares ao vetor com o
+// estágio $vectorSearch. O índice filtra o tenant, a categoria e as tags; a
+// exclusão, a validade, os metadados e a ACL são verificados em seguida, por
+// isso o índice retorna mais documentos que o limite da busca.
+func (m *MongoDB) searchByAtlasVector(ctx context.Context, vector []float32, searchFilter domain.SearchFilter) ([]domain.Document, error) {
+	vectorSearch := bson.M{
+		"index":         m.atlas.VectorIndex,
+		"path":          "embedding",
+		"queryVector":   vector,
+		"numCandidates": m.atlas.Candidates,
+		"limit":         searchLimit * atlasVectorSearchFactor,
+	}
+	if filter := atlasPrefilter(ctx, searchFilter); len(filter) > 0 {
+		vectorSearch["filter"] = filter
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$vectorSearch", Value: vectorSearch}},
+		{{Key: "$match", Value: activeDocuments(ctx, searchFilter)}},
+		{{Key: "$limit", Value: searchLimit}},
+	}
+	cursor, err := m.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("erro na busca vetorial: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := []domain.Document{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resultados: %w", err)
+	}
+	return results, nil
+}
+
+// atlasPrefilter monta o filtro aplicado pelo índice vetorial, com os
+// operadores aceitos pelo $vectorSearch. Os documentos do tenant padrão não
+// têm tenant_id, então ficam restritos apenas pelo $match seguinte.
+func atlasPrefilter(ctx context.Context, searchFilter domain.SearchFilter) bson.M {
+	var conditions bson.A
+	if tenantID := domain.TenantFromContext(ctx); tenantID != "" {
+		conditions = append(conditions, bson.M{"tenant_id": bson.M{"$eq": tenantID}})
+	}
+	if searchFilter.Category != "" {
+		conditions = append(conditions, bson.M{"category": bson.M{"$eq": searchFilter.Category}})
+	}
+	if len(searchFilter.Tags) > 0 {
+		conditions = append(conditions, bson.M{"tags": bson.M{"$in": searchFilter.Tags}})
+	}
+	switch len(conditions) {
+	case 0:
+		return nil
+	case 1:
+		return conditions[0].(bson.M)
+	default:
+		return bson.M{"$and": conditions}
+	}
+}
+
+// searchByAtlasText busca os documentos pelo título e pelo conteúdo com o
+// operador text do Atlas Search, tolerando erros de digitação em cada termo.
+// A exclusão, a validade, o filtro e a ACL são verificados em seguida.
+func (m *MongoDB) searchByAtlasText(ctx context.Context, query string, searchFilter domain.SearchFilter) ([]domain.Document, error) {
+	search := bson.M{
+		"index": m.atlas.SearchIndex,
+		"text": bson.M{
+			"query": query,
+			"path":  bson.A{"title", "content"},
+			"fuzzy": bson.M{"maxEdits": atlasFuzzyMaxEdits},
+		},
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$search", Value: search}},
+		{{Key: "$match", Value: activeDocuments(ctx, searchFilter)}},
+		{{Key: "$limit", Value: searchLimit}},
+	}
+	cursor, err := m.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("erro na busca textual: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := []domain.Document{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resultados: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// auditCollection é a coleção do log de auditoria
+const auditCollection = "audit_log"
+
+// AuditRepository implementa domain.AuditRepository no MongoDB, com um
+// documento por entrada. A aplicação apenas insere e consulta as entradas;
+// para impedir alterações na base, conceda ao usuário da aplicação apenas
+// as ações find e insert nesta coleção.
+type AuditRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAuditRepository cria o repositório de auditoria usando a mesma conexão do MongoDB
+func NewAuditRepository(db *MongoDB) *AuditRepository {
+	return &AuditRepository{
+		collection: db.database.Collection(auditCollection),
+	}
+}
+
+// Append grava a entrada no tenant do contexto
+func (r *AuditRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	entry.FillFromContext(ctx)
+	entry.ID = ""
+	result, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar auditoria: %w", err)
+	}
+	if objectID, ok := result.InsertedID.(primitive.ObjectID); ok {
+		entry.ID = objectID.Hex()
+	}
+	return nil
+}
+
+// List retorna uma página de entradas da mais recente para a mais antiga,
+// pela ordem de inserção. O cursor guarda o último ID da página anterior.
+func (r *AuditRepository) List(ctx context.Context, filter domain.AuditFilter, cursor string, limit int) ([]domain.AuditEntry, string, error) {
+	query := tenantFilter(ctx, bson.M{})
+	for field, value := range map[string]string{
+		"action":     filter.Action,
+		"actor":      filter.Actor,
+		"request_id": filter.RequestID,
+		"resource":   filter.Resource,
+	} {
+		if value != "" {
+			query[field] = value
+		}
+	}
+	period := bson.M{}
+	if !filter.Since.IsZero() {
+		period["$gte"] = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		period["$lt"] = filter.Until
+	}
+	if len(period) > 0 {
+		query["time"] = period
+	}
+	if cursor != "" {
+		id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		before, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+		query["_id"] = bson.M{"$lt": before}
+	}
+
+	// Uma entrada a mais indica se existe uma próxima página
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(int64(limit) + 1)
+
+	found, err := r.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao consultar auditoria: %w", err)
+	}
+	defer found.Close(ctx)
+
+	entries := []domain.AuditEntry{}
+	if err := found.All(ctx, &entries); err != nil {
+		return nil, "", fmt.Errorf("erro ao decodificar auditoria: %w", err)
+	}
+	if len(entries) <= limit {
+		return entries, "", nil
+	}
+	entries = entries[:limit]
+	return entries, encodeCursor(entries[limit-1].ID), nil
+}
@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExportFilter restringe os registros exportados. Campos vazios não filtram.
+type ExportFilter struct {
+	Category string    // Apenas documentos da categoria (não se aplica a conversas)
+	Since    time.Time // Criados a partir deste momento
+	Until    time.Time // Criados antes deste momento
+}
+
+// createdAtRange monta o filtro de data do MongoDB, ou nil quando não há limites
+func (f ExportFilter) createdAtRange() bson.M {
+	if f.Since.IsZero() && f.Until.IsZero() {
+		return nil
+	}
+	rng := bson.M{}
+	if !f.Since.IsZero() {
+		rng["$gte"] = f.Since
+	}
+	if !f.Until.IsZero() {
+		rng["$lt"] = f.Until
+	}
+	return rng
+}
+
+// ExportDocuments percorre os documentos que atendem ao filtro, com os
+// embeddings, chamando fn para cada um
+func (m *MongoDB) ExportDocuments(ctx context.Context, filter ExportFilter, fn func(domain.Document) error) error {
+	query := bson.M{}
+	if filter.Category != "" {
+		query["category"] = filter.Category
+	}
+	if rng := filter.createdAtRange(); rng != nil {
+		query["created_at"] = rng
+	}
+
+	cursor, err := m.collection.Find(ctx, query)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar documentos: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc domain.Document
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("erro ao decodificar documento: %w", err)
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("erro ao percorrer documentos: %w", err)
+	}
+	return nil
+}
+
+// RestoreDocuments grava os documentos mantendo ID, embedding e data de
+// criação, substituindo os que já existem. IDs que não são ObjectIDs (vindos
+// de outro banco) são trocados por novos.
+func (m *MongoDB) RestoreDocuments(ctx context.Context, docs []domain.Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		objectID, err := primitive.ObjectIDFromHex(doc.ID)
+		if err != nil {
+			objectID = primitive.NewObjectID()
+		}
+		doc.ID = ""
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": objectID}).
+			SetReplacement(doc).
+			SetUpsert(true))
+	}
+
+	if _, err := m.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("erro ao restaurar documentos: %w", err)
+	}
+	return nil
+}
+
+// ExportConversations percorre as conversas criadas no período do filtro
+func (m *MongoDB) ExportConversations(ctx context.Context, filter ExportFilter, fn func(domain.Conversation) error) error {
+	query := bson.M{}
+	if rng := filter.createdAtRange(); rng != nil {
+		query["created_at"] = rng
+	}
+
+	cursor, err := m.database.Collection("conversations").Find(ctx, query)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar conversas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var conv domain.Conversation
+		if err := cursor.Decode(&conv); err != nil {
+			return fmt.Errorf("erro ao decodificar conversa: %w", err)
+		}
+		if err := fn(conv); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("erro ao percorrer conversas: %w", err)
+	}
+	return nil
+}
+
+// RestoreConversations grava as conversas mantendo as datas originais,
+// substituindo as sessões que já existem
+func (m *MongoDB) RestoreConversations(ctx context.Context, convs []domain.Conversation) error {
+	if len(convs) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(convs))
+	for _, conv := range convs {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": conv.SessionID}).
+			SetReplacement(conv).
+			SetUpsert(true))
+	}
+
+	_, err := m.database.Collection("conversations").BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("erro ao restaurar conversas: %w", err)
+	}
+	return nil
+}
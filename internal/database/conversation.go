@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ConversationRepository implementa domain.ConversationRepository no MongoDB
+type ConversationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewConversationRepository cria o repositório de conversas usando a
+// mesma conexão do MongoDB
+func NewConversationRepository(db *MongoDB) *ConversationRepository {
+	return &ConversationRepository{
+		collection: db.database.Collection("conversations"),
+	}
+}
+
+// FindBySessionID busca a conversa da sessão no tenant do contexto
+func (r *ConversationRepository) FindBySessionID(ctx context.Context, sessionID string) (*domain.Conversation, error) {
+	var conv domain.Conversation
+	err := r.collection.FindOne(ctx, tenantFilter(ctx, bson.M{"_id": sessionID})).Decode(&conv)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar conversa: %w", err)
+	}
+	return &conv, nil
+}
+
+// Save cria ou substitui a conversa da sessão no tenant do contexto. Uma
+// sessão de outro tenant não é substituída: a inserção falha pelo ID repetido.
+func (r *ConversationRepository) Save(ctx context.Context, conv *domain.Conversation) error {
+	now := time.Now()
+	if conv.CreatedAt.IsZero() {
+		conv.CreatedAt = now
+	}
+	conv.UpdatedAt = now
+	conv.TenantID = domain.TenantFromContext(ctx)
+
+	filter := tenantFilter(ctx, bson.M{"_id": conv.SessionID})
+	_, err := r.collection.ReplaceOne(ctx, filter, conv, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("erro ao salvar conversa: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package database
+
+import (
+	"encoding/base64"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// encodeCursor gera o cursor de paginação a partir do ID do documento em que
+// a próxima página começa (ou termina a atual, conforme o banco)
+func encodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// decodeCursor recupera o ID guardado no cursor
+func decodeCursor(cursor string) (string, error) {
+	id, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(id) == 0 {
+		return "", domain.ErrInvalidCursor
+	}
+	return string(id), nil
+}
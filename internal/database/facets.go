@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongoLogicalID identifica o documento lógico de um documento ou chunk,
+// para que os chunks de um mesmo documento sejam contados uma vez
+var mongoLogicalID = bson.M{"$ifNull": bson.A{"$parent_id", bson.M{"$toString": "$_id"}}}
+
+// SearchWithFacets faz a busca textual e conta os documentos encontrados por
+// categoria, tag e mês de criação em uma única agregação, com um $facet para
+// os resultados, o total e cada contagem
+func (m *MongoDB) SearchWithFacets(ctx context.Context, query string, searchFilter domain.SearchFilter) (*domain.FacetedSearch, error) {
+	filter := activeDocuments(ctx, searchFilter)
+	filter["$text"] = bson.M{"$search": query}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: bson.M{
+			"documents": bson.A{
+				bson.M{"$sort": bson.M{"score": bson.M{"$meta": "textScore"}}},
+				bson.M{"$limit": searchLimit},
+				bson.M{"$project": bson.M{"embedding": 0}},
+			},
+			"total": bson.A{
+				bson.M{"$group": bson.M{"_id": mongoLogicalID}},
+				bson.M{"$count": "count"},
+			},
+			"categories": mongoFacet(bson.M{"category": bson.M{"$nin": bson.A{"", nil}}}, "$category", bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}),
+			"tags": append(bson.A{bson.M{"$unwind": "$tags"}},
+				mongoFacet(nil, "$tags", bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}})...),
+			"months": mongoFacet(bson.M{"created_at": bson.M{"$type": "date"}},
+				bson.M{"$dateToString": bson.M{"format": "%Y-%m", "date": "$created_at"}}, bson.D{{Key: "_id", Value: -1}}),
+		}}},
+	}
+
+	cursor, err := m.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documentos: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	type facetCount struct {
+		Value string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	var results []struct {
+		Documents  []domain.Document `bson:"documents"`
+		Total      []facetCount      `bson:"total"`
+		Categories []facetCount      `bson:"categories"`
+		Tags       []facetCount      `bson:"tags"`
+		Months     []facetCount      `bson:"months"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resultados: %w", err)
+	}
+
+	search := &domain.FacetedSearch{Documents: []domain.Document{}}
+	if len(results) == 0 {
+		return search, nil
+	}
+	convert := func(counts []facetCount) []domain.FacetCount {
+		facets := make([]domain.FacetCount, len(counts))
+		for i, c := range counts {
+			facets[i] = domain.FacetCount{Value: c.Value, Count: c.Count}
+		}
+		return facets
+	}
+	result := results[0]
+	if result.Documents != nil {
+		search.Documents = result.Documents
+	}
+	if len(result.Total) > 0 {
+		search.Total = result.Total[0].Count
+	}
+	search.Facets = domain.Facets{
+		Categories: convert(result.Categories),
+		Tags:       convert(result.Tags),
+		Months:     convert(result.Months),
+	}
+	return search, nil
+}
+
+// mongoFacet monta o estágio de um facet: os documentos que atendem a match
+// são agrupados por documento lógico e valor, e depois contados por valor,
+// na ordem de sort
+func mongoFacet(match bson.M, value any, sort bson.D) bson.A {
+	var stages bson.A
+	if match != nil {
+		stages = append(stages, bson.M{"$match": match})
+	}
+	return append(stages,
+		bson.M{"$group": bson.M{"_id": bson.M{"doc": mongoLogicalID, "value": value}}},
+		bson.M{"$group": bson.M{"_id": "$_id.value", "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": sort},
+		bson.M{"$limit": domain.FacetLimit},
+	)
+}
@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// feedbackCollection é a coleção das avaliações dos usuários
+const feedbackCollection = "feedback"
+
+// FeedbackRepository implementa domain.FeedbackRepository no MongoDB, com
+// um documento por avaliação
+type FeedbackRepository struct {
+	collection *mongo.Collection
+}
+
+// NewFeedbackRepository cria o repositório de avaliações dos usuários usando
+// a mesma conexão do MongoDB
+func NewFeedbackRepository(db *MongoDB) *FeedbackRepository {
+	return &FeedbackRepository{
+		collection: db.database.Collection(feedbackCollection),
+	}
+}
+
+// Record guarda a avaliação no tenant do contexto
+func (r *FeedbackRepository) Record(ctx context.Context, feedback *domain.Feedback) error {
+	feedback.ID = ""
+	feedback.TenantID = domain.TenantFromContext(ctx)
+	if feedback.CreatedAt.IsZero() {
+		feedback.CreatedAt = time.Now()
+	}
+	result, err := r.collection.InsertOne(ctx, feedback)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar feedback: %w", err)
+	}
+	if objectID, ok := result.InsertedID.(primitive.ObjectID); ok {
+		feedback.ID = objectID.Hex()
+	}
+	return nil
+}
+
+// List retorna uma página de avaliações da mais recente para a mais antiga,
+// pela ordem de inserção. O cursor guarda o último ID da página anterior.
+func (r *FeedbackRepository) List(ctx context.Context, filter domain.FeedbackFilter, cursor string, limit int) ([]domain.Feedback, string, error) {
+	query := tenantFilter(ctx, bson.M{})
+	for field, value := range map[string]string{
+		"rating":     filter.Rating,
+		"user_id":    filter.UserID,
+		"session_id": filter.SessionID,
+	} {
+		if value != "" {
+			query[field] = value
+		}
+	}
+	period := bson.M{}
+	if !filter.Since.IsZero() {
+		period["$gte"] = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		period["$lt"] = filter.Until
+	}
+	if len(period) > 0 {
+		query["created_at"] = period
+	}
+	if cursor != "" {
+		id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		before, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+		query["_id"] = bson.M{"$lt": before}
+	}
+
+	// Uma avaliação a mais indica se existe uma próxima página
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(int64(limit) + 1)
+
+	found, err := r.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao consultar feedback: %w", err)
+	}
+	defer found.Close(ctx)
+
+	feedback := []domain.Feedback{}
+	if err := found.All(ctx, &feedback); err != nil {
+		return nil, "", fmt.Errorf("erro ao decodificar feedback: %w", err)
+	}
+	if len(feedback) <= limit {
+		return feedback, "", nil
+	}
+	feedback = feedback[:limit]
+	return feedback, encodeCursor(feedback[limit-1].ID), nil
+}
+
+// DocumentStats conta as avaliações do tenant do contexto por fonte citada
+func (r *FeedbackRepository) DocumentStats(ctx context.Context, since time.Time) (map[string]domain.DocumentFeedback, error) {
+	count := func(rating string) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$rating", rating}}, 1, 0}}}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: tenantFilter(ctx, bson.M{"created_at": bson.M{"$gte": since}})}},
+		{{Key: "$unwind", Value: "$sources"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":  "$sources.id",
+			"up":   count(domain.FeedbackUp),
+			"down": count(domain.FeedbackDown),
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar feedback por documento: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	stats := map[string]domain.DocumentFeedback{}
+	for cursor.Next(ctx) {
+		var row struct {
+			ID   string `bson:"_id"`
+			Up   int    `bson:"up"`
+			Down int    `bson:"down"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar feedback por documento: %w", err)
+		}
+		stats[row.ID] = domain.DocumentFeedback{Up: row.Up, Down: row.Down}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao contar feedback por documento: %w", err)
+	}
+	return stats, nil
+}
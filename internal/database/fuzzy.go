@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// fuzzyThreshold é a similaridade mínima entre um termo da consulta e uma
+// palavra do documento para que sejam considerados iguais; "goroutins" e
+// "goroutine" têm similaridade 0,67
+const fuzzyThreshold = 0.5
+
+// searchFuzzy busca os documentos cujo título ou conteúdo tenham palavras
+// parecidas com os termos da consulta, comparando os trigramas de cada
+// palavra, como o pg_trgm do PostgreSQL. A comparação é feita na aplicação,
+// sobre todos os documentos que atendem ao filtro, o que é adequado para
+// bases pequenas; nas maiores, configure o índice do Atlas Search.
+func (m *MongoDB) searchFuzzy(ctx context.Context, query string, searchFilter domain.SearchFilter) ([]domain.Document, error) {
+	terms := words(query)
+	if len(terms) == 0 {
+		return []domain.Document{}, nil
+	}
+
+	cursor, err := m.collection.Find(ctx, activeDocuments(ctx, searchFilter), options.Find().SetProjection(bson.M{"embedding": 0}))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documentos: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	type scoredDocument struct {
+		doc   domain.Document
+		score float64
+	}
+
+	// Pontua cada documento pelos termos da consulta que ele contém,
+	// exatamente ou com erros de digitação
+	queryTrigrams := make([]map[string]bool, len(terms))
+	for i, term := range terms {
+		queryTrigrams[i] = trigrams(term)
+	}
+	var scored []scoredDocument
+	for cursor.Next(ctx) {
+		var doc domain.Document
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar resultados: %w", err)
+		}
+		docWords := map[string]map[string]bool{}
+		for _, word := range words(doc.Title + " " + doc.Content) {
+			if docWords[word] == nil {
+				docWords[word] = trigrams(word)
+			}
+		}
+
+		var score float64
+		for _, termTrigrams := range queryTrigrams {
+			best := 0.0
+			for _, wordTrigrams := range docWords {
+				best = max(best, trigramSimilarity(termTrigrams, wordTrigrams))
+				if best == 1 {
+					break
+				}
+			}
+			if best >= fuzzyThreshold {
+				score += best
+			}
+		}
+		if score > 0 {
+			scored = append(scored, scoredDocument{doc: doc, score: score})
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao percorrer resultados: %w", err)
+	}
+
+	// Ordena do mais parecido para o menos parecido
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+
+	results := []domain.Document{}
+	for i := 0; i < len(scored) && i < searchLimit; i++ {
+		results = append(results, scored[i].doc)
+	}
+	return results, nil
+}
+
+// words separa o texto em palavras minúsculas, descartando a pontuação
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// trigrams retorna as sequências de três letras da palavra, com dois espaços
+// antes e um depois, para que o início da palavra pese mais que o fim
+func trigrams(word string) map[string]bool {
+	runes := []rune("  " + word + " ")
+	set := make(map[string]bool, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		set[string(runes[i:i+3])] = true
+	}
+	return set
+}
+
+// trigramSimilarity é a fração dos trigramas compartilhados pelas duas
+// palavras, de 0 (nenhum) a 1 (todos)
+func trigramSimilarity(a, b map[string]bool) float64 {
+	shared := 0
+	for trigram := range a {
+		if b[trigram] {
+			shared++
+		}
+	}
+	total := len(a) + len(b) - shared
+	if total == 0 {
+		return 0
+	}
+	return float64(shared) / float64(total)
+}
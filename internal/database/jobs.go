@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// jobCollection é a coleção da fila de jobs de ingestão
+const jobCollection = "ingest_jobs"
+
+// jobRetention é o tempo que os jobs terminados ficam disponíveis para consulta
+const jobRetention = 7 * 24 * time.Hour
+
+// JobRepository implementa domain.JobRepository no MongoDB, com um documento
+// por job. Os workers disputam os jobs com findOneAndUpdate, então vários
+// servidores podem processar a mesma fila.
+type JobRepository struct {
+	collection *mongo.Collection
+}
+
+// NewJobRepository cria o repositório de jobs usando a mesma conexão do MongoDB
+func NewJobRepository(db *MongoDB) *JobRepository {
+	return &JobRepository{
+		collection: db.database.Collection(jobCollection),
+	}
+}
+
+// Create enfileira o job no tenant do contexto com um novo ID
+func (r *JobRepository) Create(ctx context.Context, job *domain.Job) error {
+	job.ID = primitive.NewObjectID().Hex()
+	job.TenantID = domain.TenantFromContext(ctx)
+	job.Actor = domain.ActorFromContext(ctx)
+	job.RequestID = domain.RequestIDFromContext(ctx)
+	job.Status = domain.JobQueued
+	job.Total = len(job.Documents)
+	job.CreatedAt = time.Now()
+	if _, err := r.collection.InsertOne(ctx, job); err != nil {
+		job.ID = ""
+		return fmt.Errorf("erro ao enfileirar job: %w", err)
+	}
+	return nil
+}
+
+// FindByID busca o job no tenant do contexto, sem os documentos
+func (r *JobRepository) FindByID(ctx context.Context, id string) (*domain.Job, error) {
+	var job domain.Job
+	findOptions := options.FindOne().SetProjection(bson.M{"documents": 0})
+	err := r.collection.FindOne(ctx, tenantFilter(ctx, bson.M{"_id": id}), findOptions).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar job: %w", err)
+	}
+	return &job, nil
+}
+
+// Claim reserva o job mais antigo na fila ou com a reserva expirada
+func (r *JobRepository) Claim(ctx context.Context, lease time.Duration) (*domain.Job, error) {
+	now := time.Now()
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": domain.JobQueued},
+		bson.M{"status": domain.JobRunning, "locked_until": bson.M{"$lt": now}},
+	}}
+	update := bson.M{
+		"$set": bson.M{"status": domain.JobRunning, "locked_until": now.Add(lease)},
+		"$min": bson.M{"started_at": now},
+	}
+	claimOptions := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job domain.Job
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, claimOptions).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao reservar job: %w", err)
+	}
+	return &job, nil
+}
+
+// Update grava o progresso do job. Ao terminar, os documentos e a reserva
+// são removidos e o job expira depois de 7 dias.
+func (r *JobRepository) Update(ctx context.Context, job *domain.Job, lease time.Duration) error {
+	set := bson.M{
+		"status":    job.Status,
+		"processed": job.Processed,
+		"succeeded": job.Succeeded,
+		"skipped":   job.Skipped,
+		"failed":    job.Failed,
+		"chunks":    job.Chunks,
+		"errors":    job.Errors,
+	}
+	update := bson.M{"$set": set}
+	if job.Finished() {
+		set["finished_at"] = job.FinishedAt
+		update["$unset"] = bson.M{"documents": "", "locked_until": ""}
+	} else {
+		set["locked_until"] = time.Now().Add(lease)
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": job.ID}, update)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar job: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrJobNotFound
+	}
+	return nil
+}
@@ -2,45 +2,51 @@ package database
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"time"
 
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/vector"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// Document representa um documento armazenado no MongoDB
-type Document struct {
-	Title    string `bson:"title" json:"title"`
-	Content  string `bson:"content" json:"content"`
-	Link     string `bson:"link" json:"link"`
-	Category string `bson:"category" json:"category"`
-}
+// searchLimit é a quantidade máxima de documentos retornados por busca
+const searchLimit = 5
 
-// MongoDB encapsula a conexão e operações com o MongoDB
+// MongoDB encapsula a conexão e operações com o MongoDB.
+// Implementa domain.DocumentRepository.
 type MongoDB struct {
 	client     *mongo.Client
 	database   *mongo.Database
 	collection *mongo.Collection
+	atlas      AtlasConfig
+	text       TextIndexConfig
 }
 
-// NewMongoDB cria uma nova instância de conexão com o MongoDB
-func NewMongoDB(ctx context.Context, uri string) (*MongoDB, error) {
+// NewMongoDB cria uma nova instância de conexão com o MongoDB. Com
+// atlas.VectorIndex, a busca vetorial usa o Atlas Vector Search; com
+// atlas.SearchIndex, a busca tolerante a erros de digitação usa o Atlas Search.
+// O índice de texto é criado com o idioma de text.
+func NewMongoDB(ctx context.Context, uri string, atlas AtlasConfig, text TextIndexConfig) (*MongoDB, error) {
 	// Configura as opções de conexão
 	clientOptions := options.Client().ApplyURI(uri)
 
 	// Conecta ao MongoDB
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao conectar ao MongoDB: %v", err)
+		return nil, fmt.Errorf("erro ao conectar ao MongoDB: %w", err)
 	}
 
 	// Verifica a conexão
 	err = client.Ping(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao pingar o MongoDB: %v", err)
+		return nil, fmt.Errorf("erro ao pingar o MongoDB: %w", err)
 	}
 
 	// Seleciona o banco de dados e coleção
@@ -51,79 +57,666 @@ func NewMongoDB(ctx context.Context, uri string) (*MongoDB, error) {
 		client:     client,
 		database:   database,
 		collection: collection,
+		atlas:      atlas.withDefaults(),
+		text:       text.withDefaults(),
 	}, nil
 }
 
+// Conversations retorna o repositório de conversas que usa a mesma conexão
+func (m *MongoDB) Conversations() domain.ConversationRepository {
+	return NewConversationRepository(m)
+}
+
+// Usage retorna o repositório de consumo que usa a mesma conexão
+func (m *MongoDB) Usage() domain.UsageRepository {
+	return NewUsageRepository(m)
+}
+
+// APIKeys retorna o repositório de chaves de API que usa a mesma conexão
+func (m *MongoDB) APIKeys() domain.APIKeyRepository {
+	return NewAPIKeyRepository(m)
+}
+
+// Quality retorna o repositório das respostas avaliadas que usa a mesma conexão
+func (m *MongoDB) Quality() domain.QualityRepository {
+	return NewQualityRepository(m)
+}
+
+// Feedback retorna o repositório das avaliações dos usuários que usa a mesma conexão
+func (m *MongoDB) Feedback() domain.FeedbackRepository {
+	return NewFeedbackRepository(m)
+}
+
+// Analytics retorna o registro das consultas que usa a mesma conexão
+func (m *MongoDB) Analytics() domain.AnalyticsRepository {
+	return NewAnalyticsRepository(m)
+}
+
+// Jobs retorna a fila de jobs de ingestão que usa a mesma conexão
+func (m *MongoDB) Jobs() domain.JobRepository {
+	return NewJobRepository(m)
+}
+
+// Audit retorna o log de auditoria que usa a mesma conexão
+func (m *MongoDB) Audit() domain.AuditRepository {
+	return NewAuditRepository(m)
+}
+
 // Close fecha a conexão com o MongoDB
 func (m *MongoDB) Close(ctx context.Context) error {
 	return m.client.Disconnect(ctx)
 }
 
-// ClearCollection limpa a coleção de documentos
-func (m *MongoDB) ClearCollection(ctx context.Context) error {
-	_, err := m.collection.DeleteMany(ctx, bson.M{})
+// HealthCheck verifica se o MongoDB está acessível
+func (m *MongoDB) HealthCheck(ctx context.Context) error {
+	if err := m.client.Ping(ctx, nil); err != nil {
+		return fmt.Errorf("erro ao pingar o MongoDB: %w", err)
+	}
+	return nil
+}
+
+// Clear remove todos os documentos da coleção e o histórico de versões
+func (m *MongoDB) Clear(ctx context.Context) error {
+	if _, err := m.collection.DeleteMany(ctx, bson.M{}); err != nil {
+		return err
+	}
+	_, err := m.versions().DeleteMany(ctx, bson.M{})
 	return err
 }
 
-// SearchDocuments busca documentos baseado em uma query
-func (m *MongoDB) SearchDocuments(ctx context.Context, query string) (string, error) {
+// DeleteByLink remove todos os documentos (e chunks) com o link informado
+func (m *MongoDB) DeleteByLink(ctx context.Context, link string) error {
+	if _, err := m.collection.DeleteMany(ctx, tenantFilter(ctx, bson.M{"link": link})); err != nil {
+		return fmt.Errorf("erro ao remover documentos: %w", err)
+	}
+	return nil
+}
+
+// SearchDocuments busca documentos baseado em uma query. Com searchFilter.Fuzzy, a
+// busca tolera erros de digitação: usa o Atlas Search, quando configurado, ou
+// compara os trigramas das palavras quando o índice de texto não encontra nada.
+func (m *MongoDB) SearchDocuments(ctx context.Context, query string, searchFilter domain.SearchFilter) ([]domain.Document, error) {
+	if searchFilter.Fuzzy && m.atlas.SearchIndex != "" {
+		return m.searchByAtlasText(ctx, query, searchFilter)
+	}
+
 	// Cria um filtro de busca usando texto
-	filter := bson.M{
-		"$text": bson.M{
-			"$search": query,
-		},
+	filter := activeDocuments(ctx, searchFilter)
+	filter["$text"] = bson.M{
+		"$search": query,
 	}
 
-	// Configura as opções de busca
+	// Configura as opções de busca: os mais relevantes primeiro, conforme os
+	// pesos dos campos no índice de texto
 	findOptions := options.Find()
-	findOptions.SetLimit(5) // Limita a 5 resultados
+	findOptions.SetSort(bson.M{"score": bson.M{"$meta": "textScore"}})
+	findOptions.SetLimit(searchLimit) // Limita a 5 resultados
 
 	// Executa a busca
 	cursor, err := m.collection.Find(ctx, filter, findOptions)
 	if err != nil {
-		return "[]", fmt.Errorf("erro ao buscar documentos: %v", err)
+		return nil, fmt.Errorf("erro ao buscar documentos: %w", err)
 	}
 	defer cursor.Close(ctx)
 
 	// Decodifica os resultados
-	var results []Document
+	results := []domain.Document{}
 	if err = cursor.All(ctx, &results); err != nil {
-		return "[]", fmt.Errorf("erro ao decodificar resultados: %v", err)
+		return nil, fmt.Errorf("erro ao decodificar resultados: %w", err)
+	}
+
+	if len(results) == 0 && searchFilter.Fuzzy {
+		return m.searchFuzzy(ctx, query, searchFilter)
+	}
+	return results, nil
+}
+
+// SearchByVector busca os documentos mais similares ao vetor informado.
+// Com o Atlas Vector Search configurado, a busca usa o índice vetorial; nos
+// demais casos, a similaridade de cosseno é calculada na aplicação, pois o
+// MongoDB local não possui índice vetorial, o que é adequado para bases pequenas.
+func (m *MongoDB) SearchByVector(ctx context.Context, embedding []float32, searchFilter domain.SearchFilter) ([]domain.Document, error) {
+	if m.atlas.VectorIndex != "" {
+		return m.searchByAtlasVector(ctx, embedding, searchFilter)
 	}
 
-	// Converte os resultados para JSON
-	jsonResults, err := json.Marshal(results)
+	filter := activeDocuments(ctx, searchFilter)
+	filter["embedding"] = bson.M{"$exists": true}
+
+	cursor, err := m.collection.Find(ctx, filter)
 	if err != nil {
-		return "[]", fmt.Errorf("erro ao converter para JSON: %v", err)
+		return nil, fmt.Errorf("erro ao buscar documentos: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	type scoredDocument struct {
+		doc   domain.Document
+		score float64
+	}
+
+	// Calcula a similaridade de cada documento com o vetor da consulta
+	var scored []scoredDocument
+	for cursor.Next(ctx) {
+		var doc domain.Document
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar resultados: %w", err)
+		}
+		if len(doc.Embedding) != len(embedding) {
+			continue
+		}
+		scored = append(scored, scoredDocument{doc: doc, score: vector.Cosine(embedding, doc.Embedding)})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao percorrer resultados: %w", err)
+	}
+
+	// Ordena do mais similar para o menos similar
+	sort.Slice(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+
+	results := []domain.Document{}
+	for i := 0; i < len(scored) && i < searchLimit; i++ {
+		results = append(results, scored[i].doc)
 	}
+	return results, nil
+}
 
-	return string(jsonResults), nil
+// aclFilter restringe os documentos aos sem ACL e aos cuja ACL contém um
+// dos grupos
+func aclFilter(groups []string) bson.M {
+	// "acl.0" não existe em documentos sem ACL ou com a lista vazia
+	access := bson.A{bson.M{"acl.0": bson.M{"$exists": false}}}
+	if len(groups) > 0 {
+		access = append(access, bson.M{"acl": bson.M{"$in": groups}})
+	}
+	return bson.M{"$or": access}
 }
 
-// InsertDocument insere um novo documento no MongoDB
-func (m *MongoDB) InsertDocument(ctx context.Context, doc Document) error {
-	_, err := m.collection.InsertOne(ctx, doc)
+// activeDocuments monta o filtro dos documentos não excluídos (exceto com
+// filter.IncludeDeleted) e não expirados do tenant que atendem ao filtro de
+// busca e cuja ACL, se houver, contém um dos grupos do filtro. O índice TTL
+// remove os expirados apenas a cada minuto, então a validade também é
+// verificada aqui.
+func activeDocuments(ctx context.Context, filter domain.SearchFilter) bson.M {
+	query := tenantFilter(ctx, bson.M{
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$exists": false}},
+			bson.M{"expires_at": bson.M{"$gt": time.Now()}},
+		},
+	})
+	if !filter.IncludeDeleted {
+		query["deleted_at"] = bson.M{"$exists": false}
+	}
+	if filter.Category != "" {
+		query["category"] = filter.Category
+	}
+	for key, value := range filter.Metadata {
+		query["metadata."+key] = value
+	}
+	if len(filter.Tags) > 0 {
+		operator := "$in"
+		if filter.MatchAllTags() {
+			operator = "$all"
+		}
+		query["tags"] = bson.M{operator: filter.Tags}
+	}
+	clauses := bson.A{aclFilter(filter.Groups)}
+	for _, condition := range filter.Conditions {
+		clauses = append(clauses, metadataCondition(condition))
+	}
+	query["$and"] = clauses
+	return query
+}
+
+// metadataCondition converte a condição sobre os metadados em um filtro. Os
+// metadados são gravados como texto, então as faixas convertem o valor para
+// número, e os valores não numéricos ficam nulos e fora da faixa.
+func metadataCondition(condition domain.MetadataCondition) bson.M {
+	field := "metadata." + condition.Key
+	switch condition.Op {
+	case domain.MetadataEquals:
+		return bson.M{field: condition.Values[0]}
+	case domain.MetadataIn:
+		return bson.M{field: bson.M{"$in": condition.Values}}
+	case domain.MetadataExists:
+		return bson.M{field: bson.M{"$exists": true}}
+	case domain.MetadataVersionRange:
+		return versionCondition(field, condition)
+	}
+
+	value := bson.M{"$convert": bson.M{"input": "$" + field, "to": "double", "onError": nil, "onNull": nil}}
+	bounds := bson.A{bson.M{"$ne": bson.A{value, nil}}}
+	if condition.Min != nil {
+		operator := "$gte"
+		if condition.MinExclusive {
+			operator = "$gt"
+		}
+		bounds = append(bounds, bson.M{operator: bson.A{value, *condition.Min}})
+	}
+	if condition.Max != nil {
+		operator := "$lte"
+		if condition.MaxExclusive {
+			operator = "$lt"
+		}
+		bounds = append(bounds, bson.M{operator: bson.A{value, *condition.Max}})
+	}
+	return bson.M{"$expr": bson.M{"$and": bounds}}
+}
+
+// versionCondition compara o valor do metadado como versão: o texto é
+// separado nos pontos e as partes, convertidas em números, são comparadas em
+// ordem, como fazem as expressões de agregação com arrays
+func versionCondition(field string, condition domain.MetadataCondition) bson.M {
+	value := bson.M{"$map": bson.M{
+		"input": bson.M{"$split": bson.A{"$" + field, "."}},
+		"in":    bson.M{"$toLong": "$$this"},
+	}}
+	// Valores que não são versões ficam de fora antes da conversão
+	bounds := bson.A{bson.M{"$regexMatch": bson.M{"input": bson.M{"$ifNull": bson.A{"$" + field, ""}}, "regex": `^[0-9]+(\.[0-9]+)*$`}}}
+	if parts, ok := domain.VersionParts(condition.MinVersion); ok {
+		operator := "$gte"
+		if condition.MinExclusive {
+			operator = "$gt"
+		}
+		bounds = append(bounds, bson.M{operator: bson.A{value, parts}})
+	}
+	if parts, ok := domain.VersionParts(condition.MaxVersion); ok {
+		operator := "$lte"
+		if condition.MaxExclusive {
+			operator = "$lt"
+		}
+		bounds = append(bounds, bson.M{operator: bson.A{value, parts}})
+	}
+	// $cond evita a conversão dos valores recusados pela expressão regular
+	return bson.M{"$expr": bson.M{"$cond": bson.A{bounds[0], bson.M{"$and": bounds[1:]}, false}}}
+}
+
+// tenantFilter restringe a consulta ao tenant do contexto. Documentos e
+// conversas do tenant padrão não têm o campo tenant_id.
+func tenantFilter(ctx context.Context, filter bson.M) bson.M {
+	if tenantID := domain.TenantFromContext(ctx); tenantID != "" {
+		filter["tenant_id"] = tenantID
+	} else {
+		filter["tenant_id"] = bson.M{"$exists": false}
+	}
+	return filter
+}
+
+// FindByID busca um documento pelo ID
+func (m *MongoDB) FindByID(ctx context.Context, id string) (*domain.Document, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrDocumentNotFound
+	}
+
+	var doc domain.Document
+	err = m.collection.FindOne(ctx, tenantFilter(ctx, bson.M{"_id": objectID})).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrDocumentNotFound
+	}
 	if err != nil {
-		return fmt.Errorf("erro ao inserir documento: %v", err)
+		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+	return &doc, nil
+}
+
+// FindByParentID busca os chunks de um documento lógico, ordenados pelo índice
+func (m *MongoDB) FindByParentID(ctx context.Context, parentID string) ([]domain.Document, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "chunk_index", Value: 1}})
+
+	filter := tenantFilter(ctx, bson.M{"parent_id": parentID, "deleted_at": bson.M{"$exists": false}})
+	cursor, err := m.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar chunks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	results := []domain.Document{}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resultados: %w", err)
+	}
+	return results, nil
+}
+
+// FindByContentHash busca o primeiro documento (ou chunk) do tenant com o
+// hash de conteúdo, inclusive entre os excluídos
+func (m *MongoDB) FindByContentHash(ctx context.Context, hash string) (*domain.Document, error) {
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "chunk_index", Value: 1}})
+
+	var doc domain.Document
+	err := m.collection.FindOne(ctx, tenantFilter(ctx, bson.M{"content_hash": hash}), findOptions).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrDocumentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+	return &doc, nil
+}
+
+// List retorna uma página de documentos em ordem de ID. O cursor guarda o
+// último ID da página anterior.
+func (m *MongoDB) List(ctx context.Context, filter domain.DocumentFilter, cursor string, limit int) ([]domain.Document, string, error) {
+	query := tenantFilter(ctx, bson.M{})
+	if filter.Category != "" {
+		query["category"] = filter.Category
+	}
+	if filter.ParentID != "" {
+		query["parent_id"] = filter.ParentID
+	}
+	if !filter.IncludeDeleted {
+		query["deleted_at"] = bson.M{"$exists": false}
+	}
+	if filter.RestrictACL {
+		query["$and"] = bson.A{aclFilter(filter.Groups)}
+	}
+	if cursor != "" {
+		id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+		query["_id"] = bson.M{"$gt": after}
+	}
+
+	// Um documento a mais indica se existe uma próxima página
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit) + 1).
+		SetProjection(bson.M{"embedding": 0})
+
+	found, err := m.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao listar documentos: %w", err)
+	}
+	defer found.Close(ctx)
+
+	results := []domain.Document{}
+	if err = found.All(ctx, &results); err != nil {
+		return nil, "", fmt.Errorf("erro ao decodificar resultados: %w", err)
+	}
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	return results, encodeCursor(results[limit-1].ID), nil
+}
+
+// InsertDocument insere um novo documento no MongoDB, no tenant do
+// contexto, e preenche o seu ID
+func (m *MongoDB) InsertDocument(ctx context.Context, doc *domain.Document) error {
+	if doc.CreatedAt.IsZero() {
+		doc.CreatedAt = time.Now()
+	}
+	doc.TenantID = domain.TenantFromContext(ctx)
+	result, err := m.collection.InsertOne(ctx, doc)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrDuplicateDocument
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao inserir documento: %w", err)
+	}
+	if objectID, ok := result.InsertedID.(primitive.ObjectID); ok {
+		doc.ID = objectID.Hex()
+	}
+	return nil
+}
+
+// InsertMany insere os documentos em uma única operação e preenche o ID de
+// cada um. A inserção não é ordenada: uma falha não impede a gravação dos
+// demais documentos, que mantêm o ID preenchido.
+func (m *MongoDB) InsertMany(ctx context.Context, docs []*domain.Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	tenantID := domain.TenantFromContext(ctx)
+	values := make([]any, len(docs))
+	for i, doc := range docs {
+		if doc.CreatedAt.IsZero() {
+			doc.CreatedAt = now
+		}
+		doc.TenantID = tenantID
+		values[i] = doc
+	}
+
+	result, err := m.collection.InsertMany(ctx, values, options.InsertMany().SetOrdered(false))
+	if result != nil {
+		for i, id := range result.InsertedIDs {
+			if objectID, ok := id.(primitive.ObjectID); ok {
+				docs[i].ID = objectID.Hex()
+			}
+		}
+	}
+	if err != nil {
+		// Os IDs devolvidos incluem os documentos que falharam
+		var bulkErr mongo.BulkWriteException
+		if errors.As(err, &bulkErr) {
+			for _, writeErr := range bulkErr.WriteErrors {
+				docs[writeErr.Index].ID = ""
+			}
+		} else {
+			for _, doc := range docs {
+				doc.ID = ""
+			}
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("erro ao inserir documentos: %w: %w", domain.ErrDuplicateDocument, err)
+		}
+		return fmt.Errorf("erro ao inserir documentos: %w", err)
 	}
 	return nil
 }
 
-// SetupTextIndex configura um índice de texto para busca
-func (m *MongoDB) SetupTextIndex(ctx context.Context) error {
-	// Cria um índice de texto nos campos title e content
-	model := mongo.IndexModel{
-		Keys: bson.D{
-			{Key: "title", Value: "text"},
-			{Key: "content", Value: "text"},
+// SoftDelete marca o documento (ou os chunks do documento lógico) como excluído
+func (m *MongoDB) SoftDelete(ctx context.Context, id string) error {
+	return m.setDeleted(ctx, id, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+}
+
+// SetEmbedding substitui o embedding do documento, sem criar uma versão
+func (m *MongoDB) SetEmbedding(ctx context.Context, id string, embedding []float32) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrDocumentNotFound
+	}
+
+	result, err := m.collection.UpdateOne(ctx, tenantFilter(ctx, bson.M{"_id": objectID}), bson.M{"$set": bson.M{"embedding": embedding}})
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar embedding: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrDocumentNotFound
+	}
+	return nil
+}
+
+// Restore remove a marca de exclusão do documento (ou dos chunks)
+func (m *MongoDB) Restore(ctx context.Context, id string) error {
+	return m.setDeleted(ctx, id, bson.M{"$unset": bson.M{"deleted_at": ""}})
+}
+
+// setDeleted aplica a atualização ao documento com o ID e aos chunks cujo
+// ParentID é o ID
+func (m *MongoDB) setDeleted(ctx context.Context, id string, update bson.M) error {
+	match := bson.A{bson.M{"parent_id": id}}
+	if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+		match = append(match, bson.M{"_id": objectID})
+	}
+
+	result, err := m.collection.UpdateMany(ctx, tenantFilter(ctx, bson.M{"$or": match}), update)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar documento: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrDocumentNotFound
+	}
+	return nil
+}
+
+// CheckIndexes verifica se a coleção de documentos tem o índice de texto,
+// sem o qual a busca falha, e os índices do Atlas, quando configurados
+func (m *MongoDB) CheckIndexes(ctx context.Context) error {
+	if err := m.checkAtlasIndexes(ctx); err != nil {
+		return err
+	}
+
+	specs, err := m.collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao listar índices: %w", err)
+	}
+	for _, spec := range specs {
+		if _, err := spec.KeysDocument.LookupErr("_fts"); err == nil {
+			return nil
+		}
+	}
+	return errors.New("índice de texto não encontrado")
+}
+
+// SetupIndexes configura o índice de texto usado na busca
+func (m *MongoDB) SetupIndexes(ctx context.Context) error {
+	if err := m.setupDocumentIndexes(ctx); err != nil {
+		return err
+	}
+
+	// Uma versão de cada documento só pode ser guardada uma vez
+	_, err := m.versions().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "document_id", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice de versões: %w", err)
+	}
+
+	// Cada chave de API é procurada pelo hash do segredo
+	_, err = m.database.Collection("api_keys").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "hash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice de chaves de API: %w", err)
+	}
+
+	// As médias das avaliações são calculadas por tenant e período
+	_, err = m.database.Collection(qualityCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice de avaliações: %w", err)
+	}
+
+	// As avaliações dos usuários são listadas por tenant, da mais recente
+	// para a mais antiga, com ou sem o filtro de avaliação, e contadas por
+	// documento desde uma data
+	_, err = m.database.Collection(feedbackCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "rating", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índices de feedback: %w", err)
+	}
+
+	// As estatísticas das consultas são calculadas por tenant e período
+	_, err = m.database.Collection(analyticsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice de estatísticas das consultas: %w", err)
+	}
+
+	// Os workers reservam o job mais antigo na fila, e os jobs terminados
+	// expiram depois do período de retenção
+	_, err = m.database.Collection(jobCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "finished_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(jobRetention.Seconds())),
 		},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índices de jobs: %w", err)
 	}
 
-	_, err := m.collection.Indexes().CreateOne(ctx, model)
+	// O log de auditoria é consultado por período, ator, requisição e recurso
+	_, err = m.database.Collection(auditCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "time", Value: -1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "actor", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "request_id", Value: 1}}},
+		{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "resource", Value: 1}, {Key: "_id", Value: -1}}},
+	})
 	if err != nil {
-		return fmt.Errorf("erro ao criar índice de texto: %v", err)
+		return fmt.Errorf("erro ao criar índices de auditoria: %w", err)
 	}
 
 	log.Println("Índice de texto criado com sucesso")
 	return nil
 }
+
+// setupDocumentIndexes cria os índices da coleção de documentos
+func (m *MongoDB) setupDocumentIndexes(ctx context.Context) error {
+	// Cria (ou recria, quando o idioma ou os pesos mudam) o índice de texto
+	if err := m.setupTextIndex(ctx); err != nil {
+		return err
+	}
+
+	// Índice usado para substituir os documentos de uma página reindexada
+	_, err := m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "link", Value: 1}}})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice de link: %w", err)
+	}
+
+	// Índice usado para restringir as consultas ao tenant
+	_, err = m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "tenant_id", Value: 1}}})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice de tenant: %w", err)
+	}
+
+	// Índice usado nos filtros por categoria das buscas
+	_, err = m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "category", Value: 1}}})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice de categoria: %w", err)
+	}
+
+	// Índice usado nos filtros por tag das buscas
+	_, err = m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "tags", Value: 1}}})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice de tags: %w", err)
+	}
+
+	// Índice usado no controle de acesso das buscas
+	_, err = m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "acl", Value: 1}}})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice de ACL: %w", err)
+	}
+
+	// O MongoDB remove os documentos (e chunks) quando a validade termina
+	_, err = m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice de validade: %w", err)
+	}
+
+	// Um conteúdo só pode ser gravado uma vez por tenant; cada chunk do
+	// documento lógico tem o seu índice. Documentos anteriores à
+	// deduplicação não têm o hash e ficam fora do índice.
+	_, err = m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "content_hash", Value: 1}, {Key: "chunk_index", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"content_hash": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice de conteúdo: %w", err)
+	}
+
+	// Índices do Atlas Vector Search e do Atlas Search, quando configurados
+	return m.setupAtlasIndexes(ctx)
+}
@@ -0,0 +1,754 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// postgresSchema cria as extensões pgvector e pg_trgm e as tabelas usadas
+// pela aplicação. A coluna embedding não fixa a dimensão, para aceitar
+// qualquer modelo de embeddings; por isso a busca vetorial é exata (sem
+// índice aproximado).
+const postgresSchema = `
+CREATE EXTENSION IF NOT EXISTS vector;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE IF NOT EXISTS documents (
+	id          TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+	title       TEXT NOT NULL,
+	content     TEXT NOT NULL,
+	link        TEXT NOT NULL DEFAULT '',
+	category    TEXT NOT NULL DEFAULT '',
+	tenant_id   TEXT NOT NULL DEFAULT '',
+	embedding   vector,
+	parent_id   TEXT NOT NULL DEFAULT '',
+	chunk_index INTEGER NOT NULL DEFAULT 0,
+	metadata    JSONB NOT NULL DEFAULT '{}',
+	tags        TEXT[] NOT NULL DEFAULT '{}',
+	acl         TEXT[] NOT NULL DEFAULT '{}',
+	version     INTEGER NOT NULL DEFAULT 0,
+	created_at  TIMESTAMPTZ,
+	updated_at  TIMESTAMPTZ,
+	deleted_at  TIMESTAMPTZ,
+	content_hash TEXT NOT NULL DEFAULT '',
+	expires_at  TIMESTAMPTZ,
+	search      tsvector GENERATED ALWAYS AS (to_tsvector('simple', title || ' ' || content)) STORED
+);
+
+-- Bases criadas antes das colunas de metadados, datas, versão, exclusão lógica, tags, tenant, hash do conteúdo, validade e ACL
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS content_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS acl TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS documents_search_idx ON documents USING GIN (search);
+CREATE INDEX IF NOT EXISTS documents_trgm_idx ON documents USING GIN ((title || ' ' || content) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS documents_link_idx ON documents (link);
+CREATE INDEX IF NOT EXISTS documents_parent_id_idx ON documents (parent_id) WHERE parent_id <> '';
+CREATE INDEX IF NOT EXISTS documents_tags_idx ON documents USING GIN (tags);
+CREATE INDEX IF NOT EXISTS documents_acl_idx ON documents USING GIN (acl);
+CREATE INDEX IF NOT EXISTS documents_tenant_id_idx ON documents (tenant_id);
+CREATE INDEX IF NOT EXISTS documents_category_idx ON documents (category);
+CREATE INDEX IF NOT EXISTS documents_metadata_idx ON documents USING GIN (metadata);
+-- Um conteúdo só pode ser gravado uma vez por tenant, exceto nos documentos anteriores à deduplicação
+CREATE UNIQUE INDEX IF NOT EXISTS documents_content_hash_idx ON documents (tenant_id, content_hash, chunk_index) WHERE content_hash <> '';
+
+CREATE TABLE IF NOT EXISTS document_versions (
+	document_id TEXT NOT NULL,
+	tenant_id   TEXT NOT NULL DEFAULT '',
+	version     INTEGER NOT NULL,
+	title       TEXT NOT NULL,
+	content     TEXT NOT NULL,
+	link        TEXT NOT NULL DEFAULT '',
+	category    TEXT NOT NULL DEFAULT '',
+	metadata    JSONB NOT NULL DEFAULT '{}',
+	tags        TEXT[] NOT NULL DEFAULT '{}',
+	created_at  TIMESTAMPTZ,
+	PRIMARY KEY (document_id, version)
+);
+
+ALTER TABLE document_versions ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE document_versions ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS conversations (
+	session_id TEXT PRIMARY KEY,
+	tenant_id  TEXT NOT NULL DEFAULT '',
+	messages   JSONB NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS summary TEXT NOT NULL DEFAULT '';
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS summarized_messages INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS user_id TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS usage (
+	key               TEXT PRIMARY KEY,
+	requests          BIGINT NOT NULL,
+	prompt_tokens     BIGINT NOT NULL,
+	completion_tokens BIGINT NOT NULL,
+	cost_usd          DOUBLE PRECISION NOT NULL,
+	updated_at        TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+	id         TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+	name       TEXT NOT NULL DEFAULT '',
+	user_id    TEXT NOT NULL,
+	tenant_id  TEXT NOT NULL DEFAULT '',
+	groups     TEXT[] NOT NULL DEFAULT '{}',
+	hash       TEXT NOT NULL UNIQUE,
+	prefix     TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	revoked_at TIMESTAMPTZ
+);
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS groups TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS quality_samples (
+	id                BIGSERIAL PRIMARY KEY,
+	tenant_id         TEXT NOT NULL DEFAULT '',
+	query             TEXT NOT NULL,
+	answer            TEXT NOT NULL,
+	model             TEXT NOT NULL DEFAULT '',
+	prompt_version    TEXT NOT NULL DEFAULT '',
+	faithfulness      DOUBLE PRECISION,
+	context_relevance DOUBLE PRECISION,
+	answer_relevance  DOUBLE PRECISION NOT NULL,
+	created_at        TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS quality_samples_tenant_created_idx ON quality_samples (tenant_id, created_at);
+
+CREATE TABLE IF NOT EXISTS feedback (
+	id             BIGSERIAL PRIMARY KEY,
+	tenant_id      TEXT NOT NULL DEFAULT '',
+	rating         TEXT NOT NULL,
+	comment        TEXT NOT NULL DEFAULT '',
+	query          TEXT NOT NULL,
+	answer         TEXT NOT NULL,
+	sources        JSONB NOT NULL DEFAULT '[]',
+	session_id     TEXT NOT NULL DEFAULT '',
+	user_id        TEXT NOT NULL DEFAULT '',
+	request_id     TEXT NOT NULL DEFAULT '',
+	model          TEXT NOT NULL DEFAULT '',
+	prompt_version TEXT NOT NULL DEFAULT '',
+	created_at     TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS feedback_tenant_id_idx ON feedback (tenant_id, id);
+CREATE INDEX IF NOT EXISTS feedback_tenant_rating_idx ON feedback (tenant_id, rating, id);
+CREATE INDEX IF NOT EXISTS feedback_tenant_created_idx ON feedback (tenant_id, created_at);
+
+CREATE TABLE IF NOT EXISTS query_analytics (
+	id         BIGSERIAL PRIMARY KEY,
+	tenant_id  TEXT NOT NULL DEFAULT '',
+	kind       TEXT NOT NULL,
+	query      TEXT NOT NULL,
+	category   TEXT NOT NULL DEFAULT '',
+	results    INTEGER NOT NULL,
+	zero_hit   BOOLEAN NOT NULL,
+	failed     BOOLEAN NOT NULL DEFAULT false,
+	latency_ms DOUBLE PRECISION NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS query_analytics_tenant_created_idx ON query_analytics (tenant_id, created_at);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+	id         BIGSERIAL PRIMARY KEY,
+	tenant_id  TEXT NOT NULL DEFAULT '',
+	time       TIMESTAMPTZ NOT NULL,
+	action     TEXT NOT NULL,
+	actor      TEXT NOT NULL DEFAULT '',
+	request_id TEXT NOT NULL DEFAULT '',
+	session_id TEXT NOT NULL DEFAULT '',
+	resource   TEXT NOT NULL DEFAULT '',
+	details    JSONB NOT NULL DEFAULT '{}',
+	error      TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_tenant_id_idx ON audit_log (tenant_id, id);
+CREATE INDEX IF NOT EXISTS audit_log_tenant_time_idx ON audit_log (tenant_id, time);
+CREATE INDEX IF NOT EXISTS audit_log_tenant_actor_idx ON audit_log (tenant_id, actor, id);
+CREATE INDEX IF NOT EXISTS audit_log_tenant_request_idx ON audit_log (tenant_id, request_id);
+CREATE INDEX IF NOT EXISTS audit_log_tenant_resource_idx ON audit_log (tenant_id, resource, id);
+
+-- O log de auditoria só recebe novas entradas
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'o log de auditoria não pode ser alterado';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER audit_log_no_update BEFORE UPDATE OR DELETE ON audit_log
+	FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+CREATE OR REPLACE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+`
+
+// documentColumns são as colunas lidas ao carregar documentos
+const documentColumns = "id, title, content, link, category, tenant_id, parent_id, chunk_index, metadata, tags, version, created_at, updated_at, deleted_at, content_hash, expires_at, acl"
+
+// Postgres implementa domain.DocumentRepository no PostgreSQL, usando
+// pgvector para a busca vetorial e o full-text search nativo para a textual
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgres cria uma nova conexão com o PostgreSQL
+func NewPostgres(ctx context.Context, url string) (*Postgres, error) {
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao PostgreSQL: %w", err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("erro ao pingar o PostgreSQL: %w", err)
+	}
+
+	return &Postgres{pool: pool}, nil
+}
+
+// Conversations retorna o repositório de conversas que usa a mesma conexão
+func (p *Postgres) Conversations() domain.ConversationRepository {
+	return NewPostgresConversationRepository(p)
+}
+
+// Usage retorna o repositório de consumo que usa a mesma conexão
+func (p *Postgres) Usage() domain.UsageRepository {
+	return NewPostgresUsageRepository(p)
+}
+
+// APIKeys retorna o repositório de chaves de API que usa a mesma conexão
+func (p *Postgres) APIKeys() domain.APIKeyRepository {
+	return NewPostgresAPIKeyRepository(p)
+}
+
+// Quality retorna o repositório das respostas avaliadas que usa a mesma conexão
+func (p *Postgres) Quality() domain.QualityRepository {
+	return NewPostgresQualityRepository(p)
+}
+
+// Feedback retorna o repositório das avaliações dos usuários que usa a mesma conexão
+func (p *Postgres) Feedback() domain.FeedbackRepository {
+	return NewPostgresFeedbackRepository(p)
+}
+
+// Analytics retorna o registro das consultas que usa a mesma conexão
+func (p *Postgres) Analytics() domain.AnalyticsRepository {
+	return NewPostgresAnalyticsRepository(p)
+}
+
+// Jobs retorna nil: a fila de jobs de ingestão é guardada apenas no MongoDB
+func (p *Postgres) Jobs() domain.JobRepository {
+	return nil
+}
+
+// Audit retorna o log de auditoria que usa a mesma conexão
+func (p *Postgres) Audit() domain.AuditRepository {
+	return NewPostgresAuditRepository(p)
+}
+
+// Close fecha a conexão com o PostgreSQL
+func (p *Postgres) Close(ctx context.Context) error {
+	p.pool.Close()
+	return nil
+}
+
+// HealthCheck verifica se o PostgreSQL está acessível
+func (p *Postgres) HealthCheck(ctx context.Context) error {
+	if err := p.pool.Ping(ctx); err != nil {
+		return fmt.Errorf("erro ao pingar o PostgreSQL: %w", err)
+	}
+	return nil
+}
+
+// Clear remove todos os documentos da tabela
+func (p *Postgres) Clear(ctx context.Context) error {
+	_, err := p.pool.Exec(ctx, "TRUNCATE documents, document_versions")
+	return err
+}
+
+// DeleteByLink remove todos os documentos (e chunks) com o link informado
+func (p *Postgres) DeleteByLink(ctx context.Context, link string) error {
+	if _, err := p.pool.Exec(ctx, "DELETE FROM documents WHERE link = $1 AND tenant_id = $2", link, domain.TenantFromContext(ctx)); err != nil {
+		return fmt.Errorf("erro ao remover documentos: %w", err)
+	}
+	return nil
+}
+
+// CheckIndexes verifica se o esquema foi criado, pelo índice da busca textual
+func (p *Postgres) CheckIndexes(ctx context.Context) error {
+	var exists bool
+	if err := p.pool.QueryRow(ctx, "SELECT to_regclass('documents_search_idx') IS NOT NULL").Scan(&exists); err != nil {
+		return fmt.Errorf("erro ao verificar esquema: %w", err)
+	}
+	if !exists {
+		return errors.New("índice de busca não encontrado")
+	}
+	return nil
+}
+
+// SetupIndexes cria a extensão pgvector, as tabelas e os índices de busca
+func (p *Postgres) SetupIndexes(ctx context.Context) error {
+	if _, err := p.pool.Exec(ctx, postgresSchema); err != nil {
+		return fmt.Errorf("erro ao criar esquema: %w", err)
+	}
+
+	log.Println("Esquema do PostgreSQL criado com sucesso")
+	return nil
+}
+
+// SearchDocuments busca documentos pelo full-text search, do mais para o
+// menos relevante. Como no índice de texto do MongoDB, basta que o documento
+// contenha um dos termos da consulta. Com filter.Fuzzy, quando nenhum
+// documento contém os termos, busca os que têm palavras parecidas.
+func (p *Postgres) SearchDocuments(ctx context.Context, query string, filter domain.SearchFilter) ([]domain.Document, error) {
+	rows, err := p.pool.Query(ctx, `
+		WITH q AS (
+			SELECT replace(plainto_tsquery('simple', $1)::text, '&', '|')::tsquery AS query
+		)
+		SELECT `+documentColumns+`
+		FROM documents, q
+		WHERE search @@ q.query AND `+searchCondition(filter, 3)+`
+		ORDER BY ts_rank(search, q.query) DESC
+		LIMIT $2`, append([]any{query, searchLimit}, searchArgs(ctx, filter)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documentos: %w", err)
+	}
+	docs, err := scanDocuments(rows)
+	if err != nil || len(docs) > 0 || !filter.Fuzzy {
+		return docs, err
+	}
+	return p.searchFuzzy(ctx, query, filter)
+}
+
+// searchFuzzy busca os documentos com palavras parecidas com algum dos termos
+// da consulta, pela similaridade de trigramas do pg_trgm (operador <%), do
+// mais para o menos parecido
+func (p *Postgres) searchFuzzy(ctx context.Context, query string, filter domain.SearchFilter) ([]domain.Document, error) {
+	terms := words(query)
+	if len(terms) == 0 {
+		return []domain.Document{}, nil
+	}
+
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+documentColumns+`
+		FROM documents
+		WHERE EXISTS (SELECT 1 FROM unnest($1::text[]) AS term WHERE term <% (title || ' ' || content))
+			AND `+searchCondition(filter, 3)+`
+		ORDER BY (SELECT sum(word_similarity(term, title || ' ' || content)) FROM unnest($1::text[]) AS term) DESC
+		LIMIT $2`, append([]any{terms, searchLimit}, searchArgs(ctx, filter)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documentos: %w", err)
+	}
+	return scanDocuments(rows)
+}
+
+// SearchByVector busca os documentos mais similares ao vetor informado,
+// usando a distância de cosseno do pgvector
+func (p *Postgres) SearchByVector(ctx context.Context, vector []float32, filter domain.SearchFilter) ([]domain.Document, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+documentColumns+`
+		FROM documents
+		WHERE embedding IS NOT NULL AND vector_dims(embedding) = $2 AND `+searchCondition(filter, 4)+`
+		ORDER BY embedding <=> $1::vector
+		LIMIT $3`, append([]any{formatVector(vector), len(vector), searchLimit}, searchArgs(ctx, filter)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documentos: %w", err)
+	}
+	return scanDocuments(rows)
+}
+
+// searchCondition monta a condição do filtro de busca e do tenant, cujos
+// valores são os parâmetros retornados por searchArgs, a partir do número
+// arg, e exclui os documentos expirados e, exceto com filter.IncludeDeleted,
+// os excluídos logicamente. Com TagMatchAll, o documento precisa conter
+// todas as tags (@>); senão, basta ter uma em comum (&&). Campos vazios (ou
+// nulos) do filtro não restringem, exceto os grupos: documentos com ACL só
+// são retornados quando ela tem um grupo em comum com o filtro.
+func searchCondition(filter domain.SearchFilter, arg int) string {
+	operator := "&&"
+	if filter.MatchAllTags() {
+		operator = "@>"
+	}
+	conditions, _ := metadataConditions(filter.Conditions, arg+6)
+	return fmt.Sprintf(`(COALESCE(cardinality($%[1]d::text[]), 0) = 0 OR tags %[2]s $%[1]d::text[])
+		AND ($%[3]d = '' OR category = $%[3]d)
+		AND metadata @> COALESCE($%[4]d::jsonb, '{}')
+		AND tenant_id = $%[5]d
+		AND (expires_at IS NULL OR expires_at > now())
+		AND (cardinality(acl) = 0 OR acl && COALESCE($%[6]d::text[], '{}'))
+		AND ($%[7]d OR deleted_at IS NULL)`, arg, operator, arg+1, arg+2, arg+3, arg+4, arg+5) + conditions
+}
+
+// searchArgs retorna os parâmetros usados por searchCondition
+func searchArgs(ctx context.Context, filter domain.SearchFilter) []any {
+	_, conditionArgs := metadataConditions(filter.Conditions, 0)
+	return append([]any{filter.Tags, filter.Category, filter.Metadata, domain.TenantFromContext(ctx), filter.Groups, filter.IncludeDeleted}, conditionArgs...)
+}
+
+// numericText reconhece os valores de metadados que podem ser convertidos em número
+const numericText = `'^\s*[-+]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?\s*$'`
+
+// versionText reconhece os valores de metadados que são versões, como 1.20.3
+const versionText = `'^[0-9]+(\.[0-9]+)*$'`
+
+// metadataConditions monta as condições sobre os metadados, cada uma
+// iniciada por AND, com os parâmetros a partir do número arg. As faixas
+// convertem o valor para número; valores não numéricos ficam nulos e fora
+// da faixa.
+func metadataConditions(conditions []domain.MetadataCondition, arg int) (string, []any) {
+	var sql strings.Builder
+	var args []any
+	next := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", arg+len(args)-1)
+	}
+	for _, condition := range conditions {
+		key := next(condition.Key)
+		switch condition.Op {
+		case domain.MetadataEquals:
+			fmt.Fprintf(&sql, "\n\t\tAND metadata->>%s::text = %s::text", key, next(condition.Values[0]))
+		case domain.MetadataIn:
+			fmt.Fprintf(&sql, "\n\t\tAND metadata->>%s::text = ANY(%s::text[])", key, next(condition.Values))
+		case domain.MetadataExists:
+			fmt.Fprintf(&sql, "\n\t\tAND metadata ? %s::text", key)
+		case domain.MetadataRange:
+			value := fmt.Sprintf("(CASE WHEN metadata->>%[1]s::text ~ %[2]s THEN (metadata->>%[1]s::text)::float8 END)", key, numericText)
+			if condition.Min != nil {
+				operator := ">="
+				if condition.MinExclusive {
+					operator = ">"
+				}
+				fmt.Fprintf(&sql, "\n\t\tAND %s %s %s::float8", value, operator, next(*condition.Min))
+			}
+			if condition.Max != nil {
+				operator := "<="
+				if condition.MaxExclusive {
+					operator = "<"
+				}
+				fmt.Fprintf(&sql, "\n\t\tAND %s %s %s::float8", value, operator, next(*condition.Max))
+			}
+		case domain.MetadataVersionRange:
+			// Arrays são comparados elemento a elemento, então 1.10 vem depois de 1.9
+			value := fmt.Sprintf("(CASE WHEN metadata->>%[1]s::text ~ %[2]s THEN string_to_array(metadata->>%[1]s::text, '.')::numeric[] END)", key, versionText)
+			if parts, ok := domain.VersionParts(condition.MinVersion); ok {
+				operator := ">="
+				if condition.MinExclusive {
+					operator = ">"
+				}
+				fmt.Fprintf(&sql, "\n\t\tAND %s %s %s::numeric[]", value, operator, next(parts))
+			}
+			if parts, ok := domain.VersionParts(condition.MaxVersion); ok {
+				operator := "<="
+				if condition.MaxExclusive {
+					operator = "<"
+				}
+				fmt.Fprintf(&sql, "\n\t\tAND %s %s %s::numeric[]", value, operator, next(parts))
+			}
+		}
+	}
+	return sql.String(), args
+}
+
+// FindByID busca um documento pelo ID
+func (p *Postgres) FindByID(ctx context.Context, id string) (*domain.Document, error) {
+	rows, err := p.pool.Query(ctx, "SELECT "+documentColumns+" FROM documents WHERE id = $1 AND tenant_id = $2", id, domain.TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+
+	docs, err := scanDocuments(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, domain.ErrDocumentNotFound
+	}
+	return &docs[0], nil
+}
+
+// FindByParentID busca os chunks de um documento lógico, ordenados pelo índice
+func (p *Postgres) FindByParentID(ctx context.Context, parentID string) ([]domain.Document, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+documentColumns+`
+		FROM documents
+		WHERE parent_id = $1 AND deleted_at IS NULL AND tenant_id = $2
+		ORDER BY chunk_index`, parentID, domain.TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar chunks: %w", err)
+	}
+	return scanDocuments(rows)
+}
+
+// FindByContentHash busca o primeiro documento (ou chunk) do tenant com o
+// hash de conteúdo, inclusive entre os excluídos
+func (p *Postgres) FindByContentHash(ctx context.Context, hash string) (*domain.Document, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+documentColumns+`
+		FROM documents
+		WHERE content_hash = $1 AND tenant_id = $2
+		ORDER BY chunk_index
+		LIMIT 1`, hash, domain.TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+
+	docs, err := scanDocuments(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, domain.ErrDocumentNotFound
+	}
+	return &docs[0], nil
+}
+
+// List retorna uma página de documentos em ordem de ID. O cursor guarda o
+// último ID da página anterior.
+func (p *Postgres) List(ctx context.Context, filter domain.DocumentFilter, cursor string, limit int) ([]domain.Document, string, error) {
+	after := ""
+	if cursor != "" {
+		var err error
+		if after, err = decodeCursor(cursor); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Um documento a mais indica se existe uma próxima página
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+documentColumns+`
+		FROM documents
+		WHERE ($1 = '' OR category = $1) AND id > $2 AND ($4 OR deleted_at IS NULL) AND tenant_id = $5
+			AND (NOT $6 OR cardinality(acl) = 0 OR acl && COALESCE($7::text[], '{}'))
+			AND ($8 = '' OR parent_id = $8)
+		ORDER BY id
+		LIMIT $3`, filter.Category, after, limit+1, filter.IncludeDeleted, domain.TenantFromContext(ctx),
+		filter.RestrictACL, filter.Groups, filter.ParentID)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao listar documentos: %w", err)
+	}
+
+	results, err := scanDocuments(rows)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	results = results[:limit]
+	return results, encodeCursor(results[limit-1].ID), nil
+}
+
+// InsertDocument insere um novo documento e preenche o seu ID
+func (p *Postgres) InsertDocument(ctx context.Context, doc *domain.Document) error {
+	return p.InsertMany(ctx, []*domain.Document{doc})
+}
+
+// InsertMany insere os documentos no tenant do contexto e preenche o ID de
+// cada um. Como no MongoDB, uma falha não impede a gravação dos demais
+// documentos: cada um é inserido em um savepoint da mesma transação, e
+// apenas os gravados ficam com o ID preenchido. O erro reúne as falhas de
+// cada documento.
+func (p *Postgres) InsertMany(ctx context.Context, docs []*domain.Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir documentos: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	tenantID := domain.TenantFromContext(ctx)
+	ids := make([]string, len(docs))
+	var errs []error
+	duplicate := false
+	for i, doc := range docs {
+		var embedding *string
+		if len(doc.Embedding) > 0 {
+			v := formatVector(doc.Embedding)
+			embedding = &v
+		}
+		if doc.CreatedAt.IsZero() {
+			doc.CreatedAt = now
+		}
+		doc.TenantID = tenantID
+
+		// Uma falha desfaz apenas o savepoint do documento, e a transação segue
+		err := pgx.BeginFunc(ctx, tx, func(savepoint pgx.Tx) error {
+			return savepoint.QueryRow(ctx, `
+				INSERT INTO documents (title, content, link, category, tenant_id, embedding, parent_id, chunk_index, metadata, tags, created_at, content_hash, expires_at, acl)
+				VALUES ($1, $2, $3, $4, $5, $6::vector, $7, $8, COALESCE($9::jsonb, '{}'), COALESCE($10::text[], '{}'), $11, $12, $13, COALESCE($14::text[], '{}'))
+				RETURNING id`,
+				doc.Title, doc.Content, doc.Link, doc.Category, doc.TenantID, embedding, doc.ParentID, doc.ChunkIndex, doc.Metadata, doc.Tags, doc.CreatedAt,
+				doc.ContentHash, optionalTime(doc.ExpiresAt), doc.ACL,
+			).Scan(&ids[i])
+		})
+		if err != nil {
+			ids[i] = ""
+			duplicate = duplicate || isUniqueViolation(err)
+			errs = append(errs, fmt.Errorf("documento %d: %w", i, err))
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		for _, doc := range docs {
+			doc.ID = ""
+		}
+		return fmt.Errorf("erro ao inserir documentos: %w", err)
+	}
+	for i, doc := range docs {
+		doc.ID = ids[i]
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	if duplicate {
+		return fmt.Errorf("erro ao inserir documentos: %w: %w", domain.ErrDuplicateDocument, errors.Join(errs...))
+	}
+	return fmt.Errorf("erro ao inserir documentos: %w", errors.Join(errs...))
+}
+
+// SoftDelete marca o documento (ou os chunks do documento lógico) como excluído
+func (p *Postgres) SoftDelete(ctx context.Context, id string) error {
+	return p.setDeleted(ctx, "UPDATE documents SET deleted_at = now() WHERE (id = $1 OR parent_id = $1) AND tenant_id = $2", id)
+}
+
+// Restore remove a marca de exclusão do documento (ou dos chunks)
+func (p *Postgres) Restore(ctx context.Context, id string) error {
+	return p.setDeleted(ctx, "UPDATE documents SET deleted_at = NULL WHERE (id = $1 OR parent_id = $1) AND tenant_id = $2", id)
+}
+
+// SetEmbedding substitui o embedding do documento, sem criar uma versão
+func (p *Postgres) SetEmbedding(ctx context.Context, id string, embedding []float32) error {
+	tag, err := p.pool.Exec(ctx, "UPDATE documents SET embedding = $1::vector WHERE id = $2 AND tenant_id = $3",
+		formatVector(embedding), id, domain.TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar embedding: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDocumentNotFound
+	}
+	return nil
+}
+
+// setDeleted executa a atualização da marca de exclusão no tenant do contexto
+func (p *Postgres) setDeleted(ctx context.Context, sql, id string) error {
+	tag, err := p.pool.Exec(ctx, sql, id, domain.TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar documento: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDocumentNotFound
+	}
+	return nil
+}
+
+// scanDocuments lê as linhas de uma consulta com documentColumns
+func scanDocuments(rows pgx.Rows) ([]domain.Document, error) {
+	defer rows.Close()
+
+	results := []domain.Document{}
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao percorrer resultados: %w", err)
+	}
+	return results, nil
+}
+
+// scanDocument lê a linha atual de uma consulta com documentColumns, seguidas
+// das colunas extras informadas em dest
+func scanDocument(rows pgx.Rows, dest ...any) (domain.Document, error) {
+	var doc domain.Document
+	// As datas são nulas quando não se aplicam ou em documentos gravados antes das colunas existirem
+	var createdAt, updatedAt, deletedAt, expiresAt *time.Time
+	fields := append([]any{
+		&doc.ID, &doc.Title, &doc.Content, &doc.Link, &doc.Category, &doc.TenantID, &doc.ParentID, &doc.ChunkIndex, &doc.Metadata,
+		&doc.Tags, &doc.Version, &createdAt, &updatedAt, &deletedAt, &doc.ContentHash, &expiresAt, &doc.ACL,
+	}, dest...)
+	if err := rows.Scan(fields...); err != nil {
+		return doc, fmt.Errorf("erro ao decodificar resultados: %w", err)
+	}
+	doc.CreatedAt = valueOrZero(createdAt)
+	doc.UpdatedAt = valueOrZero(updatedAt)
+	doc.DeletedAt = valueOrZero(deletedAt)
+	doc.ExpiresAt = valueOrZero(expiresAt)
+	return doc, nil
+}
+
+// isUniqueViolation indica se o erro é a violação de um índice único
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" // unique_violation
+}
+
+// valueOrZero retorna a data apontada, ou a data zero quando nula
+func valueOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// formatVector converte o vetor para a representação textual do pgvector
+func formatVector(vector []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range vector {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// parseVector converte a representação textual do pgvector em vetor
+func parseVector(text string) ([]float32, error) {
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "["), "]")
+	if text == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(text, ",")
+	vector := make([]float32, len(parts))
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(part, 32)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao decodificar embedding: %w", err)
+		}
+		vector[i] = float32(v)
+	}
+	return vector, nil
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresAnalyticsRepository implementa domain.AnalyticsRepository no
+// PostgreSQL, com uma linha por consulta
+type PostgresAnalyticsRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresAnalyticsRepository cria o repositório das estatísticas de uso
+// usando a mesma conexão do PostgreSQL
+func NewPostgresAnalyticsRepository(db *Postgres) *PostgresAnalyticsRepository {
+	return &PostgresAnalyticsRepository{pool: db.pool}
+}
+
+// Record guarda a consulta no tenant do contexto
+func (r *PostgresAnalyticsRepository) Record(ctx context.Context, record domain.QueryRecord) error {
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO query_analytics (tenant_id, kind, query, category, results, zero_hit, failed, latency_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		domain.TenantFromContext(ctx), record.Kind, record.Query, record.Category, record.Results,
+		record.ZeroHit, record.Failed, record.LatencyMS, record.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar consulta: %w", err)
+	}
+	return nil
+}
+
+// Stats calcula as estatísticas das consultas do tenant do contexto
+func (r *PostgresAnalyticsRepository) Stats(ctx context.Context, since time.Time, limit int) (*domain.QueryStats, error) {
+	tenantID := domain.TenantFromContext(ctx)
+	stats := &domain.QueryStats{}
+	err := r.pool.QueryRow(ctx, `
+		SELECT count(*), count(*) FILTER (WHERE failed), count(*) FILTER (WHERE zero_hit),
+			COALESCE(avg(latency_ms), 0), COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms), 0)
+		FROM query_analytics
+		WHERE tenant_id = $1 AND created_at >= $2`,
+		tenantID, since,
+	).Scan(&stats.Queries, &stats.Failed, &stats.ZeroHits, &stats.AvgLatencyMS, &stats.P95LatencyMS)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular estatísticas das consultas: %w", err)
+	}
+
+	// countBy conta as consultas agrupadas pela coluna, das mais frequentes
+	// para as menos frequentes
+	countBy := func(column, where string, fn func(key string, count int)) error {
+		rows, err := r.pool.Query(ctx, `
+			SELECT `+column+`, count(*) AS count
+			FROM query_analytics
+			WHERE tenant_id = $1 AND created_at >= $2`+where+`
+			GROUP BY 1
+			ORDER BY count DESC, 1
+			LIMIT $3`,
+			tenantID, since, limit)
+		if err != nil {
+			return fmt.Errorf("erro ao calcular estatísticas das consultas: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var key string
+			var count int
+			if err := rows.Scan(&key, &count); err != nil {
+				return fmt.Errorf("erro ao ler estatísticas das consultas: %w", err)
+			}
+			fn(key, count)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("erro ao calcular estatísticas das consultas: %w", err)
+		}
+		return nil
+	}
+
+	stats.TopQueries = []domain.QueryCount{}
+	stats.ZeroHitQueries = []domain.QueryCount{}
+	stats.Categories = []domain.CategoryCount{}
+	if err := countBy("query", "", func(query string, count int) {
+		stats.TopQueries = append(stats.TopQueries, domain.QueryCount{Query: query, Count: count})
+	}); err != nil {
+		return nil, err
+	}
+	if err := countBy("query", " AND zero_hit", func(query string, count int) {
+		stats.ZeroHitQueries = append(stats.ZeroHitQueries, domain.QueryCount{Query: query, Count: count})
+	}); err != nil {
+		return nil, err
+	}
+	if err := countBy("category", "", func(category string, count int) {
+		stats.Categories = append(stats.Categories, domain.CategoryCount{Category: category, Count: count})
+	}); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// apiKeyColumns são as colunas lidas ao carregar chaves de API
+const apiKeyColumns = "id, name, user_id, tenant_id, groups, hash, prefix, created_at, revoked_at"
+
+// PostgresAPIKeyRepository implementa domain.APIKeyRepository no PostgreSQL
+type PostgresAPIKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresAPIKeyRepository cria o repositório de chaves de API usando a
+// mesma conexão do PostgreSQL
+func NewPostgresAPIKeyRepository(db *Postgres) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{pool: db.pool}
+}
+
+// Create grava a chave e preenche o ID gerado pela base
+func (r *PostgresAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO api_keys (name, user_id, tenant_id, groups, hash, prefix, created_at)
+		VALUES ($1, $2, $3, COALESCE($4::text[], '{}'), $5, $6, $7)
+		RETURNING id`,
+		key.Name, key.UserID, key.TenantID, key.Groups, key.Hash, key.Prefix, key.CreatedAt,
+	).Scan(&key.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar chave de API: %w", err)
+	}
+	return nil
+}
+
+// FindByHash busca a chave pelo hash do segredo
+func (r *PostgresAPIKeyRepository) FindByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	row := r.pool.QueryRow(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE hash = $1", hash)
+	key, err := scanAPIKey(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar chave de API: %w", err)
+	}
+	return key, nil
+}
+
+// List retorna todas as chaves, da mais recente para a mais antiga
+func (r *PostgresAPIKeyRepository) List(ctx context.Context) ([]domain.APIKey, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY created_at DESC")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar chaves de API: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler chave de API: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao listar chaves de API: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke marca a chave como revogada, mantendo a data da primeira revogação
+func (r *PostgresAPIKeyRepository) Revoke(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx,
+		"UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $2) WHERE id = $1", id, time.Now())
+	if err != nil {
+		return fmt.Errorf("erro ao revogar chave de API: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// scanAPIKey lê uma linha com as colunas de apiKeyColumns
+func scanAPIKey(row pgx.Row) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var revokedAt *time.Time
+	err := row.Scan(&key.ID, &key.Name, &key.UserID, &key.TenantID, &key.Groups, &key.Hash, &key.Prefix, &key.CreatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt != nil {
+		key.RevokedAt = *revokedAt
+	}
+	return &key, nil
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// auditColumns são as colunas lidas ao carregar entradas de auditoria
+const auditColumns = "id::text, tenant_id, time, action, actor, request_id, session_id, resource, details, error"
+
+// PostgresAuditRepository implementa domain.AuditRepository no PostgreSQL,
+// com uma linha por entrada. Os triggers criados em SetupIndexes recusam
+// alterações e remoções na tabela.
+type PostgresAuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresAuditRepository cria o repositório de auditoria usando a mesma
+// conexão do PostgreSQL
+func NewPostgresAuditRepository(db *Postgres) *PostgresAuditRepository {
+	return &PostgresAuditRepository{pool: db.pool}
+}
+
+// Append grava a entrada no tenant do contexto e preenche o ID gerado pela base
+func (r *PostgresAuditRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	entry.FillFromContext(ctx)
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO audit_log (tenant_id, time, action, actor, request_id, session_id, resource, details, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8::jsonb, '{}'), $9)
+		RETURNING id::text`,
+		entry.TenantID, entry.Time, entry.Action, entry.Actor, entry.RequestID, entry.SessionID, entry.Resource,
+		entry.Details, entry.Error,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar auditoria: %w", err)
+	}
+	return nil
+}
+
+// List retorna uma página de entradas da mais recente para a mais antiga,
+// pela ordem de inserção. O cursor guarda o último ID da página anterior.
+func (r *PostgresAuditRepository) List(ctx context.Context, filter domain.AuditFilter, cursor string, limit int) ([]domain.AuditEntry, string, error) {
+	var before int64
+	if cursor != "" {
+		id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if before, err = strconv.ParseInt(id, 10, 64); err != nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+	}
+
+	// Uma entrada a mais indica se existe uma próxima página
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+auditColumns+`
+		FROM audit_log
+		WHERE tenant_id = $1 AND ($2 = 0 OR id < $2)
+			AND ($3 = '' OR action = $3) AND ($4 = '' OR actor = $4)
+			AND ($5 = '' OR request_id = $5) AND ($6 = '' OR resource = $6)
+			AND ($7::timestamptz IS NULL OR time >= $7) AND ($8::timestamptz IS NULL OR time < $8)
+		ORDER BY id DESC
+		LIMIT $9`,
+		domain.TenantFromContext(ctx), before, filter.Action, filter.Actor, filter.RequestID, filter.Resource,
+		optionalTime(filter.Since), optionalTime(filter.Until), limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao consultar auditoria: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var e domain.AuditEntry
+		if err := rows.Scan(&e.ID, &e.TenantID, &e.Time, &e.Action, &e.Actor, &e.RequestID, &e.SessionID, &e.Resource, &e.Details, &e.Error); err != nil {
+			return nil, "", fmt.Errorf("erro ao ler auditoria: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("erro ao consultar auditoria: %w", err)
+	}
+	if len(entries) <= limit {
+		return entries, "", nil
+	}
+	entries = entries[:limit]
+	return entries, encodeCursor(entries[limit-1].ID), nil
+}
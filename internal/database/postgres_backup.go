@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// ExportDocuments percorre os documentos que atendem ao filtro, com os
+// embeddings, chamando fn para cada um
+func (p *Postgres) ExportDocuments(ctx context.Context, filter ExportFilter, fn func(domain.Document) error) error {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+documentColumns+`, embedding::text
+		FROM documents
+		WHERE ($1 = '' OR category = $1)
+		  AND ($2::timestamptz IS NULL OR created_at >= $2)
+		  AND ($3::timestamptz IS NULL OR created_at < $3)
+		ORDER BY created_at, id`,
+		filter.Category, optionalTime(filter.Since), optionalTime(filter.Until))
+	if err != nil {
+		return fmt.Errorf("erro ao buscar documentos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var embedding *string
+		doc, err := scanDocument(rows, &embedding)
+		if err != nil {
+			return err
+		}
+		if embedding != nil {
+			if doc.Embedding, err = parseVector(*embedding); err != nil {
+				return err
+			}
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("erro ao percorrer documentos: %w", err)
+	}
+	return nil
+}
+
+// RestoreDocuments grava os documentos mantendo ID, embedding e data de
+// criação, substituindo os que já existem
+func (p *Postgres) RestoreDocuments(ctx context.Context, docs []domain.Document) error {
+	return p.restoreDocuments(ctx, "documents", docs)
+}
+
+// restoreDocuments grava os documentos na tabela informada, que tem as
+// colunas da tabela documents
+func (p *Postgres) restoreDocuments(ctx context.Context, table string, docs []domain.Document) error {
+	batch := &pgx.Batch{}
+	for _, doc := range docs {
+		var embedding *string
+		if len(doc.Embedding) > 0 {
+			v := formatVector(doc.Embedding)
+			embedding = &v
+		}
+
+		batch.Queue(`
+			INSERT INTO `+table+` (id, title, content, link, category, embedding, parent_id, chunk_index, metadata, version, created_at, updated_at, deleted_at, tags, tenant_id, content_hash, expires_at, acl)
+			VALUES (COALESCE(NULLIF($1, ''), gen_random_uuid()::text), $2, $3, $4, $5, $6::vector, $7, $8, COALESCE($9::jsonb, '{}'), $10, $11, $12, $13, COALESCE($14::text[], '{}'), $15, $16, $17, COALESCE($18::text[], '{}'))
+			ON CONFLICT (id) DO UPDATE
+			SET title = EXCLUDED.title, content = EXCLUDED.content, link = EXCLUDED.link,
+				category = EXCLUDED.category, embedding = EXCLUDED.embedding, parent_id = EXCLUDED.parent_id,
+				chunk_index = EXCLUDED.chunk_index, metadata = EXCLUDED.metadata, version = EXCLUDED.version,
+				created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at, deleted_at = EXCLUDED.deleted_at,
+				tags = EXCLUDED.tags, tenant_id = EXCLUDED.tenant_id, content_hash = EXCLUDED.content_hash,
+				expires_at = EXCLUDED.expires_at, acl = EXCLUDED.acl`,
+			doc.ID, doc.Title, doc.Content, doc.Link, doc.Category, embedding, doc.ParentID, doc.ChunkIndex, doc.Metadata,
+			doc.Version, optionalTime(doc.CreatedAt), optionalTime(doc.UpdatedAt), optionalTime(doc.DeletedAt), doc.Tags, doc.TenantID,
+			doc.ContentHash, optionalTime(doc.ExpiresAt), doc.ACL)
+	}
+
+	if err := p.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("erro ao restaurar documentos: %w", err)
+	}
+	return nil
+}
+
+// ExportConversations percorre as conversas criadas no período do filtro
+func (p *Postgres) ExportConversations(ctx context.Context, filter ExportFilter, fn func(domain.Conversation) error) error {
+	rows, err := p.pool.Query(ctx, `
+		SELECT session_id, tenant_id, user_id, messages, summary, summarized_messages, created_at, updated_at
+		FROM conversations
+		WHERE ($1::timestamptz IS NULL OR created_at >= $1)
+		  AND ($2::timestamptz IS NULL OR created_at < $2)
+		ORDER BY created_at`,
+		optionalTime(filter.Since), optionalTime(filter.Until))
+	if err != nil {
+		return fmt.Errorf("erro ao buscar conversas: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var conv domain.Conversation
+		var messages []byte
+		if err := rows.Scan(&conv.SessionID, &conv.TenantID, &conv.UserID, &messages, &conv.Summary, &conv.SummarizedMessages, &conv.CreatedAt, &conv.UpdatedAt); err != nil {
+			return fmt.Errorf("erro ao decodificar conversa: %w", err)
+		}
+		if err := json.Unmarshal(messages, &conv.Messages); err != nil {
+			return fmt.Errorf("erro ao decodificar mensagens da conversa: %w", err)
+		}
+		if err := fn(conv); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("erro ao percorrer conversas: %w", err)
+	}
+	return nil
+}
+
+// RestoreConversations grava as conversas mantendo as datas originais,
+// substituindo as sessões que já existem
+func (p *Postgres) RestoreConversations(ctx context.Context, convs []domain.Conversation) error {
+	batch := &pgx.Batch{}
+	for _, conv := range convs {
+		messages, err := json.Marshal(conv.Messages)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar mensagens da conversa: %w", err)
+		}
+		batch.Queue(`
+			INSERT INTO conversations (session_id, tenant_id, user_id, messages, summary, summarized_messages, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (session_id) DO UPDATE
+			SET tenant_id = EXCLUDED.tenant_id, user_id = EXCLUDED.user_id, messages = EXCLUDED.messages,
+				summary = EXCLUDED.summary, summarized_messages = EXCLUDED.summarized_messages,
+				created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at`,
+			conv.SessionID, conv.TenantID, conv.UserID, messages, conv.Summary, conv.SummarizedMessages, conv.CreatedAt, conv.UpdatedAt)
+	}
+
+	if err := p.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("erro ao restaurar conversas: %w", err)
+	}
+	return nil
+}
+
+// optionalTime converte o tempo zero em NULL
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresConversationRepository implementa domain.ConversationRepository no
+// PostgreSQL, guardando as mensagens da sessão em uma coluna JSONB
+type PostgresConversationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresConversationRepository cria o repositório de conversas usando a
+// mesma conexão do PostgreSQL
+func NewPostgresConversationRepository(db *Postgres) *PostgresConversationRepository {
+	return &PostgresConversationRepository{pool: db.pool}
+}
+
+// FindBySessionID busca a conversa da sessão no tenant do contexto
+func (r *PostgresConversationRepository) FindBySessionID(ctx context.Context, sessionID string) (*domain.Conversation, error) {
+	conv := domain.Conversation{SessionID: sessionID, TenantID: domain.TenantFromContext(ctx)}
+	var messages []byte
+	err := r.pool.QueryRow(ctx,
+		"SELECT user_id, messages, summary, summarized_messages, created_at, updated_at FROM conversations WHERE session_id = $1 AND tenant_id = $2", sessionID, conv.TenantID,
+	).Scan(&conv.UserID, &messages, &conv.Summary, &conv.SummarizedMessages, &conv.CreatedAt, &conv.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar conversa: %w", err)
+	}
+
+	if err := json.Unmarshal(messages, &conv.Messages); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar mensagens da conversa: %w", err)
+	}
+	return &conv, nil
+}
+
+// Save cria ou substitui a conversa da sessão no tenant do contexto. Uma
+// sessão de outro tenant não é substituída.
+func (r *PostgresConversationRepository) Save(ctx context.Context, conv *domain.Conversation) error {
+	now := time.Now()
+	if conv.CreatedAt.IsZero() {
+		conv.CreatedAt = now
+	}
+	conv.UpdatedAt = now
+	conv.TenantID = domain.TenantFromContext(ctx)
+
+	messages, err := json.Marshal(conv.Messages)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar mensagens da conversa: %w", err)
+	}
+
+	tag, err := r.pool.Exec(ctx, `
+		INSERT INTO conversations (session_id, tenant_id, user_id, messages, summary, summarized_messages, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (session_id) DO UPDATE
+		SET user_id = EXCLUDED.user_id, messages = EXCLUDED.messages, summary = EXCLUDED.summary,
+			summarized_messages = EXCLUDED.summarized_messages, updated_at = EXCLUDED.updated_at
+		WHERE conversations.tenant_id = EXCLUDED.tenant_id`,
+		conv.SessionID, conv.TenantID, conv.UserID, messages, conv.Summary, conv.SummarizedMessages, conv.CreatedAt, conv.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar conversa: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("erro ao salvar conversa: sessão %s pertence a outro tenant", conv.SessionID)
+	}
+	return nil
+}
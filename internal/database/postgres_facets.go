@@ -0,0 +1,81 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// SearchWithFacets faz a busca textual e conta os documentos lógicos
+// encontrados por categoria, tag e mês de criação (em UTC)
+func (p *Postgres) SearchWithFacets(ctx context.Context, query string, filter domain.SearchFilter) (*domain.FacetedSearch, error) {
+	docs, err := p.SearchDocuments(ctx, query, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.pool.Query(ctx, `
+		WITH q AS (
+			SELECT replace(plainto_tsquery('simple', $1)::text, '&', '|')::tsquery AS query
+		), matched AS (
+			SELECT CASE WHEN parent_id <> '' THEN parent_id ELSE id END AS doc, category, tags, created_at
+			FROM documents, q
+			WHERE search @@ q.query AND `+searchCondition(filter, 3)+`
+		)
+		SELECT 'total', '', count(DISTINCT doc) FROM matched
+		UNION ALL (
+			SELECT 'category', category, count(DISTINCT doc) FROM matched
+			WHERE category <> '' GROUP BY category ORDER BY 3 DESC, 2 LIMIT $2
+		)
+		UNION ALL (
+			SELECT 'tag', tag, count(DISTINCT doc) FROM matched, unnest(tags) AS tag
+			GROUP BY tag ORDER BY 3 DESC, 2 LIMIT $2
+		)
+		UNION ALL (
+			SELECT 'month', to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM'), count(DISTINCT doc) FROM matched
+			WHERE created_at IS NOT NULL GROUP BY 2 ORDER BY 2 DESC LIMIT $2
+		)`, append([]any{query, domain.FacetLimit}, searchArgs(ctx, filter)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar facets: %w", err)
+	}
+	defer rows.Close()
+
+	search := &domain.FacetedSearch{
+		Documents: docs,
+		Facets:    domain.Facets{Categories: []domain.FacetCount{}, Tags: []domain.FacetCount{}, Months: []domain.FacetCount{}},
+	}
+	for rows.Next() {
+		var kind string
+		var count domain.FacetCount
+		if err := rows.Scan(&kind, &count.Value, &count.Count); err != nil {
+			return nil, fmt.Errorf("erro ao ler facets: %w", err)
+		}
+		switch kind {
+		case "total":
+			search.Total = count.Count
+		case "category":
+			search.Facets.Categories = append(search.Facets.Categories, count)
+		case "tag":
+			search.Facets.Tags = append(search.Facets.Tags, count)
+		case "month":
+			search.Facets.Months = append(search.Facets.Months, count)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler facets: %w", err)
+	}
+
+	// O UNION ALL não garante a ordem das partes
+	byCount := func(a, b domain.FacetCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Value, b.Value))
+	}
+	slices.SortFunc(search.Facets.Categories, byCount)
+	slices.SortFunc(search.Facets.Tags, byCount)
+	slices.SortFunc(search.Facets.Months, func(a, b domain.FacetCount) int {
+		return cmp.Compare(b.Value, a.Value)
+	})
+	return search, nil
+}
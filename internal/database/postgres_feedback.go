@@ -0,0 +1,128 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// feedbackColumns são as colunas lidas ao carregar avaliações
+const feedbackColumns = "id::text, tenant_id, rating, comment, query, answer, sources, session_id, user_id, request_id, model, prompt_version, created_at"
+
+// PostgresFeedbackRepository implementa domain.FeedbackRepository no
+// PostgreSQL, com uma linha por avaliação
+type PostgresFeedbackRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresFeedbackRepository cria o repositório de avaliações dos
+// usuários usando a mesma conexão do PostgreSQL
+func NewPostgresFeedbackRepository(db *Postgres) *PostgresFeedbackRepository {
+	return &PostgresFeedbackRepository{pool: db.pool}
+}
+
+// Record guarda a avaliação no tenant do contexto e preenche o ID gerado pela base
+func (r *PostgresFeedbackRepository) Record(ctx context.Context, feedback *domain.Feedback) error {
+	feedback.TenantID = domain.TenantFromContext(ctx)
+	if feedback.CreatedAt.IsZero() {
+		feedback.CreatedAt = time.Now()
+	}
+	sources := feedback.Sources
+	if sources == nil {
+		sources = []domain.FeedbackSource{}
+	}
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO feedback (tenant_id, rating, comment, query, answer, sources, session_id, user_id,
+			request_id, model, prompt_version, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id::text`,
+		feedback.TenantID, feedback.Rating, feedback.Comment, feedback.Query, feedback.Answer, sources,
+		feedback.SessionID, feedback.UserID, feedback.RequestID, feedback.Model, feedback.PromptVersion,
+		feedback.CreatedAt,
+	).Scan(&feedback.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar feedback: %w", err)
+	}
+	return nil
+}
+
+// List retorna uma página de avaliações da mais recente para a mais antiga,
+// pela ordem de inserção. O cursor guarda o último ID da página anterior.
+func (r *PostgresFeedbackRepository) List(ctx context.Context, filter domain.FeedbackFilter, cursor string, limit int) ([]domain.Feedback, string, error) {
+	var before int64
+	if cursor != "" {
+		id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if before, err = strconv.ParseInt(id, 10, 64); err != nil {
+			return nil, "", domain.ErrInvalidCursor
+		}
+	}
+
+	// Uma avaliação a mais indica se existe uma próxima página
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+feedbackColumns+`
+		FROM feedback
+		WHERE tenant_id = $1 AND ($2 = 0 OR id < $2)
+			AND ($3 = '' OR rating = $3) AND ($4 = '' OR user_id = $4) AND ($5 = '' OR session_id = $5)
+			AND ($6::timestamptz IS NULL OR created_at >= $6) AND ($7::timestamptz IS NULL OR created_at < $7)
+		ORDER BY id DESC
+		LIMIT $8`,
+		domain.TenantFromContext(ctx), before, filter.Rating, filter.UserID, filter.SessionID,
+		optionalTime(filter.Since), optionalTime(filter.Until), limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao consultar feedback: %w", err)
+	}
+	defer rows.Close()
+
+	feedback := []domain.Feedback{}
+	for rows.Next() {
+		var f domain.Feedback
+		if err := rows.Scan(&f.ID, &f.TenantID, &f.Rating, &f.Comment, &f.Query, &f.Answer, &f.Sources,
+			&f.SessionID, &f.UserID, &f.RequestID, &f.Model, &f.PromptVersion, &f.CreatedAt); err != nil {
+			return nil, "", fmt.Errorf("erro ao ler feedback: %w", err)
+		}
+		feedback = append(feedback, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("erro ao consultar feedback: %w", err)
+	}
+	if len(feedback) <= limit {
+		return feedback, "", nil
+	}
+	feedback = feedback[:limit]
+	return feedback, encodeCursor(feedback[limit-1].ID), nil
+}
+
+// DocumentStats conta as avaliações do tenant do contexto por fonte citada
+func (r *PostgresFeedbackRepository) DocumentStats(ctx context.Context, since time.Time) (map[string]domain.DocumentFeedback, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT source->>'id', count(*) FILTER (WHERE rating = 'up'), count(*) FILTER (WHERE rating = 'down')
+		FROM feedback, jsonb_array_elements(sources) AS source
+		WHERE tenant_id = $1 AND created_at >= $2
+		GROUP BY 1`,
+		domain.TenantFromContext(ctx), since)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar feedback por documento: %w", err)
+	}
+	defer rows.Close()
+
+	stats := map[string]domain.DocumentFeedback{}
+	for rows.Next() {
+		var id string
+		var f domain.DocumentFeedback
+		if err := rows.Scan(&id, &f.Up, &f.Down); err != nil {
+			return nil, fmt.Errorf("erro ao ler feedback por documento: %w", err)
+		}
+		stats[id] = f
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao contar feedback por documento: %w", err)
+	}
+	return stats, nil
+}
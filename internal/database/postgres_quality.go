@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresQualityRepository implementa domain.QualityRepository no
+// PostgreSQL, com uma linha por resposta avaliada
+type PostgresQualityRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresQualityRepository cria o repositório de avaliações usando a
+// mesma conexão do PostgreSQL
+func NewPostgresQualityRepository(db *Postgres) *PostgresQualityRepository {
+	return &PostgresQualityRepository{pool: db.pool}
+}
+
+// Record guarda a avaliação no tenant do contexto
+func (r *PostgresQualityRepository) Record(ctx context.Context, sample domain.QualitySample) error {
+	if sample.CreatedAt.IsZero() {
+		sample.CreatedAt = time.Now()
+	}
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO quality_samples (tenant_id, query, answer, model, prompt_version,
+			faithfulness, context_relevance, answer_relevance, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		domain.TenantFromContext(ctx), sample.Query, sample.Answer, sample.Model, sample.PromptVersion,
+		sample.Scores.Faithfulness, sample.Scores.ContextRelevance, sample.Scores.AnswerRelevance, sample.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar avaliação: %w", err)
+	}
+	return nil
+}
+
+// Trend agrupa as avaliações do tenant do contexto por dia (em UTC). As
+// médias de fidelidade e relevância das fontes ignoram as respostas sem fontes.
+func (r *PostgresQualityRepository) Trend(ctx context.Context, since time.Time) ([]domain.QualityTrend, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT date_trunc('day', created_at AT TIME ZONE 'UTC') AS day, count(*),
+			avg(faithfulness), avg(context_relevance), avg(answer_relevance)
+		FROM quality_samples
+		WHERE tenant_id = $1 AND created_at >= $2
+		GROUP BY day
+		ORDER BY day`,
+		domain.TenantFromContext(ctx), since)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular médias das avaliações: %w", err)
+	}
+	defer rows.Close()
+
+	var trend []domain.QualityTrend
+	for rows.Next() {
+		var t domain.QualityTrend
+		if err := rows.Scan(&t.Day, &t.Samples, &t.Faithfulness, &t.ContextRelevance, &t.AnswerRelevance); err != nil {
+			return nil, fmt.Errorf("erro ao ler médias das avaliações: %w", err)
+		}
+		t.Day = time.Date(t.Day.Year(), t.Day.Month(), t.Day.Day(), 0, 0, 0, 0, time.UTC)
+		trend = append(trend, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao calcular médias das avaliações: %w", err)
+	}
+	return trend, nil
+}
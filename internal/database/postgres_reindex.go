@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// postgresReindex grava os documentos em uma tabela à parte, com as colunas
+// e os índices da tabela documents, que a substitui no Commit
+type postgresReindex struct {
+	db *Postgres
+}
+
+// BeginReindex cria a tabela da reindexação, descartando a de uma
+// reindexação anterior interrompida
+func (p *Postgres) BeginReindex(ctx context.Context) (Reindex, error) {
+	_, err := p.pool.Exec(ctx, `
+		DROP TABLE IF EXISTS `+reindexCollection+`;
+		CREATE TABLE `+reindexCollection+` (LIKE documents INCLUDING ALL);`)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao preparar a reindexação: %w", err)
+	}
+	return &postgresReindex{db: p}, nil
+}
+
+// Write grava os documentos na tabela da reindexação
+func (r *postgresReindex) Write(ctx context.Context, docs []domain.Document) error {
+	return r.db.restoreDocuments(ctx, reindexCollection, docs)
+}
+
+// Commit remove a tabela documents e renomeia a da reindexação, com os seus
+// índices, em uma transação: as buscas veem a base antiga ou a nova, nunca
+// uma mistura das duas
+func (r *postgresReindex) Commit(ctx context.Context) error {
+	err := pgx.BeginFunc(ctx, r.db.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			DROP TABLE documents;
+			ALTER TABLE `+reindexCollection+` RENAME TO documents;`)
+		if err != nil {
+			return err
+		}
+
+		// Os índices copiados por LIKE recebem o nome da tabela nova como
+		// prefixo; sem renomeá-los, SetupIndexes criaria índices repetidos
+		rows, err := tx.Query(ctx, `SELECT indexname FROM pg_indexes WHERE tablename = 'documents' AND indexname LIKE $1`,
+			reindexCollection+`\_%`)
+		if err != nil {
+			return err
+		}
+		indexes, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+		for _, index := range indexes {
+			renamed := "documents" + strings.TrimPrefix(index, reindexCollection)
+			if _, err := tx.Exec(ctx, `ALTER INDEX `+pgx.Identifier{index}.Sanitize()+` RENAME TO `+pgx.Identifier{renamed}.Sanitize()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao trocar a tabela de documentos: %w", err)
+	}
+	return nil
+}
+
+// Abort remove a tabela da reindexação
+func (r *postgresReindex) Abort(ctx context.Context) error {
+	_, err := r.db.pool.Exec(ctx, `DROP TABLE IF EXISTS `+reindexCollection)
+	return err
+}
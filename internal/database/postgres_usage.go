@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresUsageRepository implementa domain.UsageRepository no PostgreSQL,
+// com uma linha por sessão e por usuário
+type PostgresUsageRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresUsageRepository cria o repositório de consumo usando a mesma
+// conexão do PostgreSQL
+func NewPostgresUsageRepository(db *Postgres) *PostgresUsageRepository {
+	return &PostgresUsageRepository{pool: db.pool}
+}
+
+// Record soma o consumo aos totais da sessão e do usuário
+func (r *PostgresUsageRepository) Record(ctx context.Context, sessionID, userID string, usage domain.TokenUsage, costUSD float64) error {
+	now := time.Now()
+	for _, key := range usageKeys(ctx, sessionID, userID) {
+		_, err := r.pool.Exec(ctx, `
+			INSERT INTO usage (key, requests, prompt_tokens, completion_tokens, cost_usd, updated_at)
+			VALUES ($1, 1, $2, $3, $4, $5)
+			ON CONFLICT (key) DO UPDATE
+			SET requests = usage.requests + 1,
+				prompt_tokens = usage.prompt_tokens + EXCLUDED.prompt_tokens,
+				completion_tokens = usage.completion_tokens + EXCLUDED.completion_tokens,
+				cost_usd = usage.cost_usd + EXCLUDED.cost_usd,
+				updated_at = EXCLUDED.updated_at`,
+			key, usage.PromptTokens, usage.CompletionTokens, costUSD, now)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar consumo: %w", err)
+		}
+	}
+	return nil
+}
+
+// FindBySessionID retorna os totais da sessão
+func (r *PostgresUsageRepository) FindBySessionID(ctx context.Context, sessionID string) (*domain.UsageTotals, error) {
+	return r.find(ctx, usageKey(ctx, usageSessionPrefix, sessionID))
+}
+
+// FindByUserID retorna os totais do usuário
+func (r *PostgresUsageRepository) FindByUserID(ctx context.Context, userID string) (*domain.UsageTotals, error) {
+	return r.find(ctx, usageKey(ctx, usageUserPrefix, userID))
+}
+
+// find busca os totais da chave, zerados quando ainda não há consumo
+func (r *PostgresUsageRepository) find(ctx context.Context, key string) (*domain.UsageTotals, error) {
+	var totals domain.UsageTotals
+	err := r.pool.QueryRow(ctx,
+		"SELECT requests, prompt_tokens, completion_tokens, cost_usd, updated_at FROM usage WHERE key = $1", key,
+	).Scan(&totals.Requests, &totals.PromptTokens, &totals.CompletionTokens, &totals.CostUSD, &totals.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &domain.UsageTotals{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar consumo: %w", err)
+	}
+	return &totals, nil
+}
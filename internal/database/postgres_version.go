@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Update substitui o conteúdo do documento e guarda o estado anterior em
+// document_versions, na mesma transação. A linha do documento fica
+// bloqueada até o fim, então atualizações simultâneas são serializadas.
+func (p *Postgres) Update(ctx context.Context, doc *domain.Document) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, "SELECT "+documentColumns+" FROM documents WHERE id = $1 AND tenant_id = $2 FOR UPDATE",
+		doc.ID, domain.TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+	docs, err := scanDocuments(rows)
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return domain.ErrDocumentNotFound
+	}
+	current := docs[0]
+
+	previous := domain.NewDocumentVersion(current)
+	_, err = tx.Exec(ctx, `
+		INSERT INTO document_versions (document_id, tenant_id, version, title, content, link, category, metadata, tags, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8::jsonb, '{}'), COALESCE($9::text[], '{}'), $10)`,
+		previous.DocumentID, previous.TenantID, previous.Version, previous.Title, previous.Content, previous.Link, previous.Category,
+		previous.Metadata, previous.Tags, optionalTime(previous.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("erro ao guardar versão: %w", err)
+	}
+
+	var embedding *string
+	if len(doc.Embedding) > 0 {
+		v := formatVector(doc.Embedding)
+		embedding = &v
+	}
+	version := current.CurrentVersion() + 1
+	now := time.Now()
+	_, err = tx.Exec(ctx, `
+		UPDATE documents
+		SET title = $2, content = $3, link = $4, category = $5, embedding = $6::vector,
+			metadata = COALESCE($7::jsonb, '{}'), tags = COALESCE($8::text[], '{}'), version = $9, updated_at = $10,
+			content_hash = $11, expires_at = $12, acl = COALESCE($13::text[], '{}')
+		WHERE id = $1`,
+		doc.ID, doc.Title, doc.Content, doc.Link, doc.Category, embedding, doc.Metadata, doc.Tags, version, now, doc.ContentHash,
+		optionalTime(doc.ExpiresAt), doc.ACL)
+	if isUniqueViolation(err) {
+		return domain.ErrDuplicateDocument
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar documento: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar atualização: %w", err)
+	}
+
+	doc.TenantID, doc.ParentID, doc.ChunkIndex = current.TenantID, current.ParentID, current.ChunkIndex
+	doc.CreatedAt, doc.DeletedAt = current.CreatedAt, current.DeletedAt
+	doc.Version, doc.UpdatedAt = version, now
+	return nil
+}
+
+// GetVersionHistory retorna as versões anteriores do documento, da mais
+// antiga para a mais recente
+func (p *Postgres) GetVersionHistory(ctx context.Context, id string) ([]domain.DocumentVersion, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT document_id, tenant_id, version, title, content, link, category, metadata, tags, created_at
+		FROM document_versions
+		WHERE document_id = $1 AND tenant_id = $2
+		ORDER BY version`, id, domain.TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar versões: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []domain.DocumentVersion{}
+	for rows.Next() {
+		var v domain.DocumentVersion
+		var createdAt *time.Time
+		if err := rows.Scan(&v.DocumentID, &v.TenantID, &v.Version, &v.Title, &v.Content, &v.Link, &v.Category, &v.Metadata, &v.Tags, &createdAt); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar versão: %w", err)
+		}
+		v.CreatedAt = valueOrZero(createdAt)
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao percorrer versões: %w", err)
+	}
+	return versions, nil
+}
@@ -0,0 +1,769 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Valores padrão do Qdrant
+const (
+	defaultQdrantURL        = "http://localhost:6333"
+	defaultQdrantCollection = "documents"
+	defaultQdrantVectorSize = 1536 // Dimensão do text-embedding-3-small
+)
+
+// Limites das operações em lote no Qdrant
+const (
+	qdrantUpsertBatchSize = 100
+	qdrantScrollPageSize  = 256
+)
+
+// qdrantPayloadFields seleciona o payload retornado nas buscas, sem o
+// histórico de versões dos documentos atualizados
+var qdrantPayloadFields = map[string]any{"exclude": []string{"history"}}
+
+// qdrantVectorName é o nome do vetor dos documentos na coleção. Com um vetor
+// nomeado, documentos sem embedding podem ser guardados apenas com o payload.
+const qdrantVectorName = "embedding"
+
+// QdrantConfig contém as configurações de acesso ao Qdrant
+type QdrantConfig struct {
+	URL        string // Endereço da API REST (padrão: http://localhost:6333)
+	APIKey     string
+	Collection string // Nome da coleção (padrão: documents)
+	VectorSize int    // Dimensão dos embeddings (padrão: 1536)
+}
+
+// Qdrant implementa domain.DocumentRepository no Qdrant, usando a API REST.
+// O documento é guardado no payload do ponto e o ID do ponto é o ID do documento.
+type Qdrant struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	collection string
+	vectorSize int
+}
+
+// NewQdrant cria um cliente para a coleção configurada e verifica a conexão
+func NewQdrant(ctx context.Context, cfg QdrantConfig) (*Qdrant, error) {
+	q := &Qdrant{
+		httpClient: http.DefaultClient,
+		baseURL:    strings.TrimRight(cfg.URL, "/"),
+		apiKey:     cfg.APIKey,
+		collection: cfg.Collection,
+		vectorSize: cfg.VectorSize,
+	}
+	if q.baseURL == "" {
+		q.baseURL = defaultQdrantURL
+	}
+	if q.collection == "" {
+		q.collection = defaultQdrantCollection
+	}
+	if q.vectorSize <= 0 {
+		q.vectorSize = defaultQdrantVectorSize
+	}
+
+	if err := q.HealthCheck(ctx); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Conversations retorna nil: o Qdrant guarda apenas documentos, então o
+// histórico de conversas fica desabilitado
+func (q *Qdrant) Conversations() domain.ConversationRepository {
+	return nil
+}
+
+// Usage retorna nil: o consumo por sessão e usuário não é registrado no Qdrant
+func (q *Qdrant) Usage() domain.UsageRepository {
+	return nil
+}
+
+// APIKeys retorna nil: as chaves de API não são guardadas no Qdrant
+func (q *Qdrant) APIKeys() domain.APIKeyRepository {
+	return nil
+}
+
+// Quality retorna nil: as respostas avaliadas não são guardadas no Qdrant
+func (q *Qdrant) Quality() domain.QualityRepository {
+	return nil
+}
+
+// Feedback retorna nil: as avaliações dos usuários não são guardadas no Qdrant
+func (q *Qdrant) Feedback() domain.FeedbackRepository {
+	return nil
+}
+
+// Analytics retorna nil: o registro das consultas não é guardado no Qdrant
+func (q *Qdrant) Analytics() domain.AnalyticsRepository {
+	return nil
+}
+
+// Jobs retorna nil: a fila de jobs de ingestão não é guardada no Qdrant
+func (q *Qdrant) Jobs() domain.JobRepository {
+	return nil
+}
+
+// Audit retorna nil: o log de auditoria não é guardado no Qdrant
+func (q *Qdrant) Audit() domain.AuditRepository {
+	return nil
+}
+
+// Close não faz nada: o cliente HTTP não mantém conexão própria
+func (q *Qdrant) Close(ctx context.Context) error {
+	return nil
+}
+
+// HealthCheck verifica se o Qdrant está acessível
+func (q *Qdrant) HealthCheck(ctx context.Context) error {
+	if err := q.do(ctx, http.MethodGet, "/healthz", nil, nil); err != nil {
+		return fmt.Errorf("erro ao verificar o Qdrant: %w", err)
+	}
+	return nil
+}
+
+// CheckIndexes verifica se a coleção existe e tem o índice textual do
+// conteúdo. Depois de uma reindexação, verifica a coleção apontada pelo alias.
+func (q *Qdrant) CheckIndexes(ctx context.Context) error {
+	name := q.collection
+	target, aliased, err := q.aliasTarget(ctx)
+	if err != nil {
+		return err
+	}
+	if aliased {
+		name = target
+	}
+
+	var info struct {
+		Result struct {
+			PayloadSchema map[string]json.RawMessage `json:"payload_schema"`
+		} `json:"result"`
+	}
+	if err := q.do(ctx, http.MethodGet, "/collections/"+url.PathEscape(name), nil, &info); err != nil {
+		return fmt.Errorf("erro ao verificar coleção: %w", err)
+	}
+	if _, ok := info.Result.PayloadSchema["content"]; !ok {
+		return errors.New("índice textual do conteúdo não encontrado")
+	}
+	return nil
+}
+
+// SetupIndexes cria a coleção, se ainda não existir, e os índices de payload
+// usados nos filtros e na busca textual
+func (q *Qdrant) SetupIndexes(ctx context.Context) error {
+	var exists struct {
+		Result struct {
+			Exists bool `json:"exists"`
+		} `json:"result"`
+	}
+	if err := q.do(ctx, http.MethodGet, q.collectionPath("/exists"), nil, &exists); err != nil {
+		return fmt.Errorf("erro ao verificar coleção: %w", err)
+	}
+
+	// Depois de uma reindexação, o nome configurado é um alias da coleção
+	if !exists.Result.Exists {
+		_, aliased, err := q.aliasTarget(ctx)
+		if err != nil {
+			return err
+		}
+		exists.Result.Exists = aliased
+	}
+
+	if !exists.Result.Exists {
+		body := map[string]any{
+			"vectors": map[string]any{
+				qdrantVectorName: map[string]any{"size": q.vectorSize, "distance": "Cosine"},
+			},
+		}
+		if err := q.do(ctx, http.MethodPut, q.collectionPath(""), body, nil); err != nil {
+			return fmt.Errorf("erro ao criar coleção: %w", err)
+		}
+	}
+
+	indexes := map[string]string{
+		"category":     "keyword",
+		"parent_id":    "keyword",
+		"link":         "keyword",
+		"tags":         "keyword",
+		"acl":          "keyword",
+		"tenant_id":    "keyword",
+		"content_hash": "keyword",
+		"created_at":   "datetime",
+		"expires_at":   "datetime",
+		"title":        "text",
+		"content":      "text",
+	}
+	for field, schema := range indexes {
+		body := map[string]any{"field_name": field, "field_schema": schema}
+		if err := q.do(ctx, http.MethodPut, q.collectionPath("/index?wait=true"), body, nil); err != nil {
+			return fmt.Errorf("erro ao criar índice de %s: %w", field, err)
+		}
+	}
+
+	log.Println("Coleção e índices do Qdrant criados com sucesso")
+	return nil
+}
+
+// Clear remove a coleção e a recria vazia. Depois de uma reindexação, o
+// nome configurado é um alias, e a coleção removida é a apontada por ele.
+func (q *Qdrant) Clear(ctx context.Context) error {
+	name := q.collection
+	target, aliased, err := q.aliasTarget(ctx)
+	if err != nil {
+		return err
+	}
+	if aliased {
+		name = target
+	}
+	if err := q.deleteCollection(ctx, name); err != nil {
+		return err
+	}
+	return q.SetupIndexes(ctx)
+}
+
+// DeleteByLink remove todos os documentos (e chunks) com o link informado
+func (q *Qdrant) DeleteByLink(ctx context.Context, link string) error {
+	body := map[string]any{"filter": withTenant(ctx, payloadFilter(map[string]string{"link": link}))}
+	if err := q.do(ctx, http.MethodPost, q.collectionPath("/points/delete?wait=true"), body, nil); err != nil {
+		return fmt.Errorf("erro ao remover documentos: %w", err)
+	}
+	return nil
+}
+
+// SearchDocuments busca documentos cujo título ou conteúdo contenha algum
+// dos termos da consulta, usando o índice de texto do payload. O índice não
+// tolera erros de digitação, então searchFilter.Fuzzy é ignorado.
+func (q *Qdrant) SearchDocuments(ctx context.Context, query string, searchFilter domain.SearchFilter) ([]domain.Document, error) {
+	if err := checkQdrantConditions(searchFilter); err != nil {
+		return nil, err
+	}
+	var should []map[string]any
+	for _, term := range strings.Fields(query) {
+		for _, field := range []string{"title", "content"} {
+			should = append(should, map[string]any{"key": field, "match": map[string]any{"text": term}})
+		}
+	}
+	if len(should) == 0 {
+		return []domain.Document{}, nil
+	}
+
+	filter := withSearchFilter(withTenant(ctx, withoutExpired(map[string]any{"should": should})), searchFilter)
+	points, _, err := q.scroll(ctx, filter, searchLimit, nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documentos: %w", err)
+	}
+	return pointsToDocuments(points)
+}
+
+// SearchByVector busca os documentos mais similares ao vetor informado
+func (q *Qdrant) SearchByVector(ctx context.Context, vector []float32, filter domain.SearchFilter) ([]domain.Document, error) {
+	if err := checkQdrantConditions(filter); err != nil {
+		return nil, err
+	}
+	return q.searchPoints(ctx, vector, withSearchFilter(withTenant(ctx, withoutExpired(payloadFilter(nil))), filter))
+}
+
+// SearchWithFacets não é oferecida pelo Qdrant, cuja API não agrupa os
+// resultados de uma busca textual
+func (q *Qdrant) SearchWithFacets(ctx context.Context, query string, filter domain.SearchFilter) (*domain.FacetedSearch, error) {
+	return nil, domain.ErrFacetsUnsupported
+}
+
+// SearchByVectorWithFilter funciona como SearchByVector, restringindo a busca
+// aos documentos cujo payload tenha exatamente os valores informados
+// (por exemplo, {"category": "performance"})
+func (q *Qdrant) SearchByVectorWithFilter(ctx context.Context, vector []float32, filter map[string]string) ([]domain.Document, error) {
+	return q.searchPoints(ctx, vector, withTenant(ctx, withoutExpired(withoutDeleted(payloadFilter(filter)))))
+}
+
+// searchPoints busca os pontos mais similares ao vetor entre os que atendem
+// ao filtro do Qdrant
+func (q *Qdrant) searchPoints(ctx context.Context, vector []float32, filter map[string]any) ([]domain.Document, error) {
+	if len(vector) != q.vectorSize {
+		return []domain.Document{}, nil
+	}
+
+	body := map[string]any{
+		"vector":       map[string]any{"name": qdrantVectorName, "vector": vector},
+		"filter":       filter,
+		"limit":        searchLimit,
+		"with_payload": qdrantPayloadFields,
+	}
+
+	var resp struct {
+		Result []qdrantPoint `json:"result"`
+	}
+	if err := q.do(ctx, http.MethodPost, q.collectionPath("/points/search"), body, &resp); err != nil {
+		return nil, fmt.Errorf("erro ao buscar documentos: %w", err)
+	}
+	return pointsToDocuments(resp.Result)
+}
+
+// FindByID busca um documento pelo ID. Pontos de outros tenants são tratados
+// como não encontrados.
+func (q *Qdrant) FindByID(ctx context.Context, id string) (*domain.Document, error) {
+	var resp struct {
+		Result qdrantPoint `json:"result"`
+	}
+	err := q.do(ctx, http.MethodGet, q.collectionPath("/points/"+url.PathEscape(id)), nil, &resp)
+	if err != nil {
+		// IDs inexistentes ou em formato inválido são tratados como não encontrados
+		var statusErr *qdrantStatusError
+		if errors.As(err, &statusErr) && (statusErr.status == http.StatusNotFound || statusErr.status == http.StatusBadRequest) {
+			return nil, domain.ErrDocumentNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+
+	docs, err := pointsToDocuments([]qdrantPoint{resp.Result})
+	if err != nil {
+		return nil, err
+	}
+	if docs[0].TenantID != domain.TenantFromContext(ctx) {
+		return nil, domain.ErrDocumentNotFound
+	}
+	return &docs[0], nil
+}
+
+// FindByParentID busca os chunks de um documento lógico, ordenados pelo índice
+func (q *Qdrant) FindByParentID(ctx context.Context, parentID string) ([]domain.Document, error) {
+	filter := withTenant(ctx, withoutDeleted(payloadFilter(map[string]string{"parent_id": parentID})))
+
+	var points []qdrantPoint
+	var offset any
+	for {
+		page, next, err := q.scroll(ctx, filter, qdrantScrollPageSize, offset, false)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar chunks: %w", err)
+		}
+		points = append(points, page...)
+		if next == nil {
+			break
+		}
+		offset = next
+	}
+
+	docs, err := pointsToDocuments(points)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].ChunkIndex < docs[j].ChunkIndex
+	})
+	return docs, nil
+}
+
+// FindByContentHash busca o primeiro documento (ou chunk) do tenant com o
+// hash de conteúdo, inclusive entre os excluídos. O Qdrant não tem índices
+// únicos, então a deduplicação depende desta consulta antes da inserção.
+func (q *Qdrant) FindByContentHash(ctx context.Context, hash string) (*domain.Document, error) {
+	filter := withTenant(ctx, payloadFilter(map[string]string{"content_hash": hash}))
+	points, _, err := q.scroll(ctx, filter, qdrantScrollPageSize, nil, false)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+
+	docs, err := pointsToDocuments(points)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, domain.ErrDocumentNotFound
+	}
+	first := docs[0]
+	for _, doc := range docs[1:] {
+		if doc.ChunkIndex < first.ChunkIndex {
+			first = doc
+		}
+	}
+	return &first, nil
+}
+
+// List retorna uma página de documentos na ordem de ID do Qdrant. O cursor
+// guarda o ID do primeiro documento da próxima página.
+func (q *Qdrant) List(ctx context.Context, filter domain.DocumentFilter, cursor string, limit int) ([]domain.Document, string, error) {
+	var offset any
+	if cursor != "" {
+		id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if !uuidPattern.MatchString(id) {
+			return nil, "", domain.ErrInvalidCursor
+		}
+		offset = id
+	}
+
+	conditions := map[string]string{}
+	if filter.Category != "" {
+		conditions["category"] = filter.Category
+	}
+	if filter.ParentID != "" {
+		conditions["parent_id"] = filter.ParentID
+	}
+
+	query := withTenant(ctx, payloadFilter(conditions))
+	if !filter.IncludeDeleted {
+		query = withoutDeleted(query)
+	}
+	if filter.RestrictACL {
+		query = withACL(query, filter.Groups)
+	}
+	points, next, err := q.scroll(ctx, query, limit, offset, false)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao listar documentos: %w", err)
+	}
+	docs, err := pointsToDocuments(points)
+	if err != nil {
+		return nil, "", err
+	}
+	if next == nil {
+		return docs, "", nil
+	}
+	return docs, encodeCursor(fmt.Sprint(next)), nil
+}
+
+// SoftDelete marca o documento (ou os chunks do documento lógico) como excluído
+func (q *Qdrant) SoftDelete(ctx context.Context, id string) error {
+	payload := map[string]any{"deleted_at": time.Now().Format(time.RFC3339Nano)}
+	return q.setDeleted(ctx, id, "/points/payload?wait=true", map[string]any{"payload": payload})
+}
+
+// Restore remove a marca de exclusão do documento (ou dos chunks)
+func (q *Qdrant) Restore(ctx context.Context, id string) error {
+	return q.setDeleted(ctx, id, "/points/payload/delete?wait=true", map[string]any{"keys": []string{"deleted_at"}})
+}
+
+// SetEmbedding substitui o vetor do ponto, sem alterar o payload nem criar
+// uma versão
+func (q *Qdrant) SetEmbedding(ctx context.Context, id string, embedding []float32) error {
+	// Confirma que o ponto existe no tenant do contexto
+	if _, err := q.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	body := map[string]any{"points": []map[string]any{{
+		"id":     id,
+		"vector": map[string]any{qdrantVectorName: embedding},
+	}}}
+	if err := q.do(ctx, http.MethodPut, q.collectionPath("/points/vectors?wait=true"), body, nil); err != nil {
+		return fmt.Errorf("erro ao atualizar embedding: %w", err)
+	}
+	return nil
+}
+
+// setDeleted aplica a operação de payload ao ponto com o ID e aos chunks
+// cujo parent_id é o ID
+func (q *Qdrant) setDeleted(ctx context.Context, id, path string, body map[string]any) error {
+	should := []map[string]any{{"key": "parent_id", "match": map[string]any{"value": id}}}
+	if uuidPattern.MatchString(id) {
+		should = append(should, map[string]any{"has_id": []string{id}})
+	}
+	filter := withTenant(ctx, map[string]any{"should": should})
+
+	var count struct {
+		Result struct {
+			Count int `json:"count"`
+		} `json:"result"`
+	}
+	countBody := map[string]any{"filter": filter, "exact": true}
+	if err := q.do(ctx, http.MethodPost, q.collectionPath("/points/count"), countBody, &count); err != nil {
+		return fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+	if count.Result.Count == 0 {
+		return domain.ErrDocumentNotFound
+	}
+
+	body["filter"] = filter
+	if err := q.do(ctx, http.MethodPost, q.collectionPath(path), body, nil); err != nil {
+		return fmt.Errorf("erro ao atualizar documento: %w", err)
+	}
+	return nil
+}
+
+// InsertDocument insere um novo documento e preenche o seu ID
+func (q *Qdrant) InsertDocument(ctx context.Context, doc *domain.Document) error {
+	return q.InsertMany(ctx, []*domain.Document{doc})
+}
+
+// InsertMany insere os documentos em lotes, no tenant do contexto, e
+// preenche o ID de cada um
+func (q *Qdrant) InsertMany(ctx context.Context, docs []*domain.Document) error {
+	tenantID := domain.TenantFromContext(ctx)
+	for start := 0; start < len(docs); start += qdrantUpsertBatchSize {
+		batch := docs[start:min(start+qdrantUpsertBatchSize, len(docs))]
+
+		ids := make([]string, len(batch))
+		for i, doc := range batch {
+			ids[i] = newPointID()
+			if doc.CreatedAt.IsZero() {
+				doc.CreatedAt = time.Now()
+			}
+			doc.TenantID = tenantID
+		}
+		if err := q.upsertPoints(ctx, batch, ids); err != nil {
+			return fmt.Errorf("erro ao inserir documentos: %w", err)
+		}
+		for i, doc := range batch {
+			doc.ID = ids[i]
+		}
+	}
+	return nil
+}
+
+// upsertPoints grava os documentos como pontos com os IDs informados,
+// substituindo os pontos que já existem
+func (q *Qdrant) upsertPoints(ctx context.Context, docs []*domain.Document, ids []string) error {
+	points := make([]map[string]any, 0, len(docs))
+	for i, doc := range docs {
+		payload := *doc
+		payload.ID = ""
+
+		point := map[string]any{"id": ids[i], "payload": payload, "vector": map[string]any{}}
+		if len(doc.Embedding) > 0 {
+			point["vector"] = map[string]any{qdrantVectorName: doc.Embedding}
+		}
+		points = append(points, point)
+	}
+
+	body := map[string]any{"points": points}
+	return q.do(ctx, http.MethodPut, q.collectionPath("/points?wait=true"), body, nil)
+}
+
+// qdrantPoint é um ponto retornado pela API, com o documento no payload
+type qdrantPoint struct {
+	ID      any                  `json:"id"`
+	Payload json.RawMessage      `json:"payload"`
+	Vector  map[string][]float32 `json:"vector,omitempty"` // Presente apenas quando solicitado
+}
+
+// scroll lista os pontos que atendem ao filtro, retornando o offset da
+// próxima página (nil quando não há mais pontos). Com withVector, os
+// embeddings também são retornados.
+func (q *Qdrant) scroll(ctx context.Context, filter map[string]any, limit int, offset any, withVector bool) ([]qdrantPoint, any, error) {
+	body := map[string]any{
+		"filter":       filter,
+		"limit":        limit,
+		"with_payload": qdrantPayloadFields,
+		"with_vector":  withVector,
+	}
+	if offset != nil {
+		body["offset"] = offset
+	}
+
+	var resp struct {
+		Result struct {
+			Points         []qdrantPoint `json:"points"`
+			NextPageOffset any           `json:"next_page_offset"`
+		} `json:"result"`
+	}
+	if err := q.do(ctx, http.MethodPost, q.collectionPath("/points/scroll"), body, &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Result.Points, resp.Result.NextPageOffset, nil
+}
+
+// collectionPath monta o caminho de uma operação na coleção
+func (q *Qdrant) collectionPath(suffix string) string {
+	return "/collections/" + url.PathEscape(q.collection) + suffix
+}
+
+// qdrantStatusError é retornado quando a API responde com erro
+type qdrantStatusError struct {
+	status int
+	body   string
+}
+
+// Error implementa a interface error
+func (e *qdrantStatusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.status, e.body)
+}
+
+// Temporary indica se o status é de uma falha transitória (sobrecarga ou
+// indisponibilidade), que pode ser repetida
+func (e *qdrantStatusError) Temporary() bool {
+	return e.status == http.StatusTooManyRequests || e.status >= http.StatusInternalServerError
+}
+
+// do executa uma chamada à API, serializando body e decodificando a resposta em out
+func (q *Qdrant) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar requisição: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, q.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if q.apiKey != "" {
+		req.Header.Set("api-key", q.apiKey)
+	}
+
+	resp, err := q.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return &qdrantStatusError{status: resp.StatusCode, body: string(raw)}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta: %w", err)
+	}
+	return nil
+}
+
+// payloadFilter monta um filtro do Qdrant que exige os valores informados
+func payloadFilter(values map[string]string) map[string]any {
+	must := make([]map[string]any, 0, len(values))
+	for key, value := range values {
+		must = append(must, map[string]any{"key": key, "match": map[string]any{"value": value}})
+	}
+	return map[string]any{"must": must}
+}
+
+// withoutDeleted acrescenta ao filtro a exclusão dos documentos excluídos
+// logicamente, que têm deleted_at no payload
+func withoutDeleted(filter map[string]any) map[string]any {
+	must, _ := filter["must"].([]map[string]any)
+	filter["must"] = append(must, map[string]any{"is_empty": map[string]any{"key": "deleted_at"}})
+	return filter
+}
+
+// withoutExpired acrescenta ao filtro a exclusão dos documentos cuja
+// validade (expires_at) já passou. O Qdrant não remove pontos expirados.
+func withoutExpired(filter map[string]any) map[string]any {
+	must, _ := filter["must"].([]map[string]any)
+	filter["must"] = append(must, map[string]any{"should": []map[string]any{
+		{"is_empty": map[string]any{"key": "expires_at"}},
+		{"key": "expires_at", "range": map[string]any{"gt": time.Now().Format(time.RFC3339Nano)}},
+	}})
+	return filter
+}
+
+// withSearchFilter acrescenta ao filtro as condições do filtro de busca. Com
+// TagMatchAll, exige cada uma das tags; senão, basta uma delas. Documentos
+// com ACL só atendem ao filtro se ela contiver um dos grupos, e os excluídos
+// logicamente, apenas com search.IncludeDeleted.
+func withSearchFilter(filter map[string]any, search domain.SearchFilter) map[string]any {
+	must, _ := filter["must"].([]map[string]any)
+	if search.Category != "" {
+		must = append(must, map[string]any{"key": "category", "match": map[string]any{"value": search.Category}})
+	}
+	for key, value := range search.Metadata {
+		must = append(must, map[string]any{"key": "metadata." + key, "match": map[string]any{"value": value}})
+	}
+	for _, condition := range search.Conditions {
+		key := "metadata." + condition.Key
+		switch condition.Op {
+		case domain.MetadataEquals:
+			must = append(must, map[string]any{"key": key, "match": map[string]any{"value": condition.Values[0]}})
+		case domain.MetadataIn:
+			must = append(must, map[string]any{"key": key, "match": map[string]any{"any": condition.Values}})
+		case domain.MetadataExists:
+			must = append(must, map[string]any{"must_not": []map[string]any{{"is_empty": map[string]any{"key": key}}}})
+		}
+	}
+	switch {
+	case len(search.Tags) == 0:
+	case search.MatchAllTags():
+		for _, tag := range search.Tags {
+			must = append(must, map[string]any{"key": "tags", "match": map[string]any{"value": tag}})
+		}
+	default:
+		must = append(must, map[string]any{"key": "tags", "match": map[string]any{"any": search.Tags}})
+	}
+	filter["must"] = must
+	if !search.IncludeDeleted {
+		filter = withoutDeleted(filter)
+	}
+	return withACL(filter, search.Groups)
+}
+
+// withACL acrescenta ao filtro a restrição aos documentos sem ACL e aos cuja
+// ACL contém um dos grupos
+func withACL(filter map[string]any, groups []string) map[string]any {
+	must, _ := filter["must"].([]map[string]any)
+	access := []map[string]any{{"is_empty": map[string]any{"key": "acl"}}}
+	if len(groups) > 0 {
+		access = append(access, map[string]any{"key": "acl", "match": map[string]any{"any": groups}})
+	}
+	filter["must"] = append(must, map[string]any{"should": access})
+	return filter
+}
+
+// checkQdrantConditions recusa as faixas de valores e de versões dos
+// metadados: o Qdrant só compara faixas de valores numéricos, e os metadados
+// são gravados como texto
+func checkQdrantConditions(filter domain.SearchFilter) error {
+	for _, condition := range filter.Conditions {
+		if condition.Op == domain.MetadataRange || condition.Op == domain.MetadataVersionRange {
+			return &domain.ValidationError{Field: "conditions", Message: fmt.Sprintf("%s: o Qdrant não compara faixas de valores dos metadados", condition.Key)}
+		}
+	}
+	return nil
+}
+
+// withTenant acrescenta ao filtro o tenant do contexto. Pontos do tenant
+// padrão não têm tenant_id no payload.
+func withTenant(ctx context.Context, filter map[string]any) map[string]any {
+	must, _ := filter["must"].([]map[string]any)
+	if tenantID := domain.TenantFromContext(ctx); tenantID != "" {
+		must = append(must, map[string]any{"key": "tenant_id", "match": map[string]any{"value": tenantID}})
+	} else {
+		must = append(must, map[string]any{"is_empty": map[string]any{"key": "tenant_id"}})
+	}
+	filter["must"] = must
+	return filter
+}
+
+// pointsToDocuments converte os pontos em documentos, usando o ID do ponto
+func pointsToDocuments(points []qdrantPoint) ([]domain.Document, error) {
+	docs := make([]domain.Document, 0, len(points))
+	for _, point := range points {
+		var doc domain.Document
+		if err := json.Unmarshal(point.Payload, &doc); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar resultados: %w", err)
+		}
+		doc.ID = fmt.Sprint(point.ID)
+		doc.Embedding = point.Vector[qdrantVectorName]
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// newPointID gera um UUID v4, formato de ID aceito pelo Qdrant
+func newPointID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
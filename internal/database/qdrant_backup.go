@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// uuidPattern reconhece IDs no formato UUID, o formato de ID de ponto usado na coleção
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`)
+
+// ExportDocuments percorre os documentos que atendem ao filtro, com os
+// embeddings, chamando fn para cada um
+func (q *Qdrant) ExportDocuments(ctx context.Context, filter ExportFilter, fn func(domain.Document) error) error {
+	must := []map[string]any{}
+	if filter.Category != "" {
+		must = append(must, map[string]any{"key": "category", "match": map[string]any{"value": filter.Category}})
+	}
+	if !filter.Since.IsZero() || !filter.Until.IsZero() {
+		rng := map[string]any{}
+		if !filter.Since.IsZero() {
+			rng["gte"] = filter.Since.Format(time.RFC3339Nano)
+		}
+		if !filter.Until.IsZero() {
+			rng["lt"] = filter.Until.Format(time.RFC3339Nano)
+		}
+		must = append(must, map[string]any{"key": "created_at", "range": rng})
+	}
+
+	var offset any
+	for {
+		page, next, err := q.scroll(ctx, map[string]any{"must": must}, qdrantScrollPageSize, offset, true)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar documentos: %w", err)
+		}
+		docs, err := pointsToDocuments(page)
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			if err := fn(doc); err != nil {
+				return err
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		offset = next
+	}
+}
+
+// RestoreDocuments grava os documentos mantendo ID, embedding e data de
+// criação, substituindo os que já existem. IDs que não são aceitos pelo
+// Qdrant (vindos de outro banco) são trocados por novos.
+func (q *Qdrant) RestoreDocuments(ctx context.Context, docs []domain.Document) error {
+	for start := 0; start < len(docs); start += qdrantUpsertBatchSize {
+		batch := make([]*domain.Document, 0, qdrantUpsertBatchSize)
+		ids := make([]string, 0, qdrantUpsertBatchSize)
+		for i := start; i < min(start+qdrantUpsertBatchSize, len(docs)); i++ {
+			id := docs[i].ID
+			if !uuidPattern.MatchString(id) {
+				id = newPointID()
+			}
+			batch = append(batch, &docs[i])
+			ids = append(ids, id)
+		}
+		if err := q.upsertPoints(ctx, batch, ids); err != nil {
+			return fmt.Errorf("erro ao restaurar documentos: %w", err)
+		}
+	}
+	return nil
+}
+
+// ExportConversations não exporta nada: o Qdrant não guarda conversas
+func (q *Qdrant) ExportConversations(ctx context.Context, filter ExportFilter, fn func(domain.Conversation) error) error {
+	return nil
+}
+
+// RestoreConversations falha quando há conversas: o Qdrant não as guarda
+func (q *Qdrant) RestoreConversations(ctx context.Context, convs []domain.Conversation) error {
+	if len(convs) > 0 {
+		return fmt.Errorf("o Qdrant não guarda conversas")
+	}
+	return nil
+}
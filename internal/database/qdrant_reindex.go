@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// qdrantReindex grava os documentos em uma coleção nova, que passa a ser
+// usada pelo nome configurado por meio de um alias do Qdrant
+type qdrantReindex struct {
+	live    *Qdrant
+	staging *Qdrant
+}
+
+// BeginReindex cria uma coleção nova, com o nome configurado seguido do
+// momento da reindexação, com a dimensão de QDRANT_VECTOR_SIZE e os índices
+// de payload
+func (q *Qdrant) BeginReindex(ctx context.Context) (Reindex, error) {
+	staging := *q
+	staging.collection = q.collection + "_" + strconv.FormatInt(time.Now().Unix(), 10)
+	if err := staging.SetupIndexes(ctx); err != nil {
+		return nil, fmt.Errorf("erro ao preparar a reindexação: %w", err)
+	}
+	return &qdrantReindex{live: q, staging: &staging}, nil
+}
+
+// Write grava os documentos na coleção nova
+func (r *qdrantReindex) Write(ctx context.Context, docs []domain.Document) error {
+	return r.staging.RestoreDocuments(ctx, docs)
+}
+
+// Commit aponta o alias com o nome configurado para a coleção nova e remove a
+// coleção anterior. Quando o nome ainda é de uma coleção, e não de um alias
+// (a primeira reindexação), a coleção é removida antes da criação do alias,
+// e as buscas falham nesse intervalo; nas seguintes, a troca é atômica.
+func (r *qdrantReindex) Commit(ctx context.Context) error {
+	previous, aliased, err := r.live.aliasTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	var actions []map[string]any
+	if aliased {
+		actions = append(actions, map[string]any{"delete_alias": map[string]any{"alias_name": r.live.collection}})
+	} else {
+		log.Printf("Removendo a coleção %s para criar o alias; as buscas falham até a troca", r.live.collection)
+		if err := r.live.deleteCollection(ctx, r.live.collection); err != nil {
+			return err
+		}
+	}
+	actions = append(actions, map[string]any{"create_alias": map[string]any{
+		"alias_name":      r.live.collection,
+		"collection_name": r.staging.collection,
+	}})
+	if err := r.live.do(ctx, http.MethodPost, "/collections/aliases", map[string]any{"actions": actions}, nil); err != nil {
+		return fmt.Errorf("erro ao trocar a coleção de documentos: %w", err)
+	}
+
+	if aliased {
+		if err := r.live.deleteCollection(ctx, previous); err != nil {
+			log.Printf("Aviso ao remover a coleção anterior %s: %v", previous, err)
+		}
+	}
+	return nil
+}
+
+// Abort remove a coleção nova
+func (r *qdrantReindex) Abort(ctx context.Context) error {
+	return r.staging.deleteCollection(ctx, r.staging.collection)
+}
+
+// aliasTarget retorna a coleção para a qual o nome configurado aponta,
+// quando ele é um alias
+func (q *Qdrant) aliasTarget(ctx context.Context) (string, bool, error) {
+	var resp struct {
+		Result struct {
+			Aliases []struct {
+				AliasName      string `json:"alias_name"`
+				CollectionName string `json:"collection_name"`
+			} `json:"aliases"`
+		} `json:"result"`
+	}
+	if err := q.do(ctx, http.MethodGet, "/aliases", nil, &resp); err != nil {
+		return "", false, fmt.Errorf("erro ao listar aliases: %w", err)
+	}
+	for _, alias := range resp.Result.Aliases {
+		if alias.AliasName == q.collection {
+			return alias.CollectionName, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// deleteCollection remove a coleção com o nome informado
+func (q *Qdrant) deleteCollection(ctx context.Context, name string) error {
+	if err := q.do(ctx, http.MethodDelete, "/collections/"+url.PathEscape(name), nil, nil); err != nil {
+		return fmt.Errorf("erro ao remover coleção: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// qdrantVersionedPayload é o payload de um documento atualizado: o Qdrant não
+// tem coleções auxiliares, então as versões anteriores ficam no próprio ponto
+type qdrantVersionedPayload struct {
+	domain.Document
+	History []domain.DocumentVersion `json:"history,omitempty"`
+}
+
+// Update substitui o conteúdo do documento, acrescentando o estado anterior
+// ao histórico guardado no payload. O Qdrant não tem transações, então em
+// atualizações simultâneas prevalece a última.
+func (q *Qdrant) Update(ctx context.Context, doc *domain.Document) error {
+	current, err := q.getVersionedPayload(ctx, doc.ID)
+	if err != nil {
+		return err
+	}
+
+	updated := *doc
+	updated.ID = ""
+	updated.TenantID, updated.ParentID, updated.ChunkIndex = current.TenantID, current.ParentID, current.ChunkIndex
+	updated.CreatedAt, updated.DeletedAt = current.CreatedAt, current.DeletedAt
+	updated.Version = current.CurrentVersion() + 1
+	updated.UpdatedAt = time.Now()
+
+	payload := qdrantVersionedPayload{
+		Document: updated,
+		History:  append(current.History, domain.NewDocumentVersion(current.Document)),
+	}
+	point := map[string]any{"id": doc.ID, "payload": payload, "vector": map[string]any{}}
+	if len(doc.Embedding) > 0 {
+		point["vector"] = map[string]any{qdrantVectorName: doc.Embedding}
+	}
+
+	body := map[string]any{"points": []map[string]any{point}}
+	if err := q.do(ctx, http.MethodPut, q.collectionPath("/points?wait=true"), body, nil); err != nil {
+		return fmt.Errorf("erro ao atualizar documento: %w", err)
+	}
+
+	updated.ID = doc.ID
+	updated.Embedding = doc.Embedding
+	*doc = updated
+	return nil
+}
+
+// GetVersionHistory retorna as versões anteriores do documento, da mais
+// antiga para a mais recente
+func (q *Qdrant) GetVersionHistory(ctx context.Context, id string) ([]domain.DocumentVersion, error) {
+	current, err := q.getVersionedPayload(ctx, id)
+	if errors.Is(err, domain.ErrDocumentNotFound) {
+		return []domain.DocumentVersion{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if current.History == nil {
+		return []domain.DocumentVersion{}, nil
+	}
+	return current.History, nil
+}
+
+// getVersionedPayload busca o ponto com o documento e o seu histórico no
+// tenant do contexto
+func (q *Qdrant) getVersionedPayload(ctx context.Context, id string) (*qdrantVersionedPayload, error) {
+	if !uuidPattern.MatchString(id) {
+		return nil, domain.ErrDocumentNotFound
+	}
+
+	var resp struct {
+		Result qdrantPoint `json:"result"`
+	}
+	err := q.do(ctx, http.MethodGet, q.collectionPath("/points/"+url.PathEscape(id)), nil, &resp)
+	if err != nil {
+		var statusErr *qdrantStatusError
+		if errors.As(err, &statusErr) && statusErr.status == http.StatusNotFound {
+			return nil, domain.ErrDocumentNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+
+	var payload qdrantVersionedPayload
+	if err := json.Unmarshal(resp.Result.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar documento: %w", err)
+	}
+	if payload.TenantID != domain.TenantFromContext(ctx) {
+		return nil, domain.ErrDocumentNotFound
+	}
+	payload.ID = id
+	return &payload, nil
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// qualityCollection é a coleção das respostas avaliadas
+const qualityCollection = "quality_samples"
+
+// QualityRepository implementa domain.QualityRepository no MongoDB, com um
+// documento por resposta avaliada
+type QualityRepository struct {
+	collection *mongo.Collection
+}
+
+// NewQualityRepository cria o repositório de avaliações usando a mesma conexão do MongoDB
+func NewQualityRepository(db *MongoDB) *QualityRepository {
+	return &QualityRepository{
+		collection: db.database.Collection(qualityCollection),
+	}
+}
+
+// Record guarda a avaliação no tenant do contexto
+func (r *QualityRepository) Record(ctx context.Context, sample domain.QualitySample) error {
+	sample.TenantID = domain.TenantFromContext(ctx)
+	if sample.CreatedAt.IsZero() {
+		sample.CreatedAt = time.Now()
+	}
+	if _, err := r.collection.InsertOne(ctx, sample); err != nil {
+		return fmt.Errorf("erro ao registrar avaliação: %w", err)
+	}
+	return nil
+}
+
+// Trend agrupa as avaliações do tenant do contexto por dia. As médias de
+// fidelidade e relevância das fontes ignoram as respostas sem fontes.
+func (r *QualityRepository) Trend(ctx context.Context, since time.Time) ([]domain.QualityTrend, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: tenantFilter(ctx, bson.M{"created_at": bson.M{"$gte": since}})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":               bson.M{"$dateTrunc": bson.M{"date": "$created_at", "unit": "day"}},
+			"samples":           bson.M{"$sum": 1},
+			"faithfulness":      bson.M{"$avg": "$scores.faithfulness"},
+			"context_relevance": bson.M{"$avg": "$scores.context_relevance"},
+			"answer_relevance":  bson.M{"$avg": "$scores.answer_relevance"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline, options.Aggregate())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao calcular médias das avaliações: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var trend []domain.QualityTrend
+	for cursor.Next(ctx) {
+		var row struct {
+			Day              time.Time `bson:"_id"`
+			Samples          int       `bson:"samples"`
+			Faithfulness     *float64  `bson:"faithfulness"`
+			ContextRelevance *float64  `bson:"context_relevance"`
+			AnswerRelevance  float64   `bson:"answer_relevance"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar médias das avaliações: %w", err)
+		}
+		trend = append(trend, domain.QualityTrend{
+			Day:              row.Day.UTC(),
+			Samples:          row.Samples,
+			Faithfulness:     row.Faithfulness,
+			ContextRelevance: row.ContextRelevance,
+			AnswerRelevance:  row.AnswerRelevance,
+		})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao calcular médias das avaliações: %w", err)
+	}
+	return trend, nil
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// reindexCollection é a coleção (ou tabela) em que a reindexação grava os
+// documentos antes da troca
+const reindexCollection = "documents_reindex"
+
+// Reindex é uma reindexação em andamento: os documentos são gravados em uma
+// base nova, ao lado da atual, que continua atendendo as buscas até Commit
+type Reindex interface {
+	// Write grava documentos na base nova mantendo ID, tenant, embedding e
+	// datas, como RestoreDocuments
+	Write(ctx context.Context, docs []domain.Document) error
+	// Commit troca a base atual pela nova de uma vez e descarta a antiga
+	Commit(ctx context.Context) error
+	// Abort descarta a base nova, mantendo a atual
+	Abort(ctx context.Context) error
+}
+
+// mongoReindex grava os documentos em uma coleção à parte, que substitui a
+// coleção de documentos com renameCollection
+type mongoReindex struct {
+	live    *MongoDB
+	staging *MongoDB
+}
+
+// BeginReindex cria a coleção da reindexação, descartando a de uma
+// reindexação anterior interrompida
+func (m *MongoDB) BeginReindex(ctx context.Context) (Reindex, error) {
+	staging := &MongoDB{client: m.client, database: m.database, collection: m.database.Collection(reindexCollection), atlas: m.atlas, text: m.text}
+	if err := staging.collection.Drop(ctx); err != nil {
+		return nil, fmt.Errorf("erro ao preparar a reindexação: %w", err)
+	}
+	return &mongoReindex{live: m, staging: staging}, nil
+}
+
+// Write grava os documentos na coleção da reindexação
+func (r *mongoReindex) Write(ctx context.Context, docs []domain.Document) error {
+	return r.staging.RestoreDocuments(ctx, docs)
+}
+
+// Commit cria os índices da coleção nova e a renomeia para o nome da coleção
+// de documentos, removendo a antiga na mesma operação
+func (r *mongoReindex) Commit(ctx context.Context) error {
+	if err := r.staging.setupDocumentIndexes(ctx); err != nil {
+		return err
+	}
+
+	dbName := r.live.database.Name()
+	command := bson.D{
+		{Key: "renameCollection", Value: dbName + "." + reindexCollection},
+		{Key: "to", Value: dbName + "." + r.live.collection.Name()},
+		{Key: "dropTarget", Value: true},
+	}
+	if err := r.live.client.Database("admin").RunCommand(ctx, command).Err(); err != nil {
+		return fmt.Errorf("erro ao trocar a coleção de documentos: %w", err)
+	}
+	return nil
+}
+
+// Abort remove a coleção da reindexação
+func (r *mongoReindex) Abort(ctx context.Context) error {
+	return r.staging.collection.Drop(ctx)
+}
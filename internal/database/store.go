@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Bancos de dados suportados
+const (
+	DriverMongo    = "mongo"
+	DriverPostgres = "postgres"
+	DriverQdrant   = "qdrant"
+)
+
+// Config contém as configurações de acesso ao banco de dados
+type Config struct {
+	Driver      string // "mongo" (padrão), "postgres" ou "qdrant"
+	MongoURI    string
+	MongoAtlas  AtlasConfig     // Atlas Vector Search e Atlas Search
+	MongoText   TextIndexConfig // Idioma do índice de texto
+	PostgresURL string
+	Qdrant      QdrantConfig
+}
+
+// ConfigFromEnv lê a configuração do banco a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	return Config{
+		Driver:      os.Getenv("DB_DRIVER"),
+		MongoURI:    os.Getenv("MONGO_URI"),
+		MongoAtlas:  atlasConfigFromEnv(),
+		MongoText:   textIndexConfigFromEnv(),
+		PostgresURL: os.Getenv("POSTGRES_URL"),
+		Qdrant:      qdrantConfigFromEnv(),
+	}
+}
+
+// qdrantConfigFromEnv lê a configuração do Qdrant a partir das variáveis de ambiente
+func qdrantConfigFromEnv() QdrantConfig {
+	cfg := QdrantConfig{
+		URL:        os.Getenv("QDRANT_URL"),
+		APIKey:     os.Getenv("QDRANT_API_KEY"),
+		Collection: os.Getenv("QDRANT_COLLECTION"),
+	}
+	cfg.VectorSize, _ = strconv.Atoi(os.Getenv("QDRANT_VECTOR_SIZE"))
+	return cfg
+}
+
+// Store é o banco de documentos usado pela aplicação, com as operações de
+// manutenção necessárias para o servidor, a CLI e o seed
+type Store interface {
+	domain.DocumentRepository
+	// Conversations retorna o repositório de conversas do mesmo banco, ou nil
+	// quando o banco não guarda conversas
+	Conversations() domain.ConversationRepository
+	// Usage retorna o repositório de consumo do mesmo banco, ou nil quando o
+	// banco não registra consumo
+	Usage() domain.UsageRepository
+	// APIKeys retorna o repositório de chaves de API do mesmo banco, ou nil
+	// quando o banco não guarda chaves
+	APIKeys() domain.APIKeyRepository
+	// Quality retorna o repositório das respostas avaliadas do mesmo banco,
+	// ou nil quando o banco não guarda avaliações
+	Quality() domain.QualityRepository
+	// Feedback retorna o repositório das avaliações dos usuários do mesmo
+	// banco, ou nil quando o banco não guarda avaliações
+	Feedback() domain.FeedbackRepository
+	// Analytics retorna o registro das consultas do mesmo banco, ou nil
+	// quando o banco não guarda as estatísticas de uso
+	Analytics() domain.AnalyticsRepository
+	// Jobs retorna a fila de jobs de ingestão do mesmo banco, ou nil quando
+	// o banco não guarda jobs
+	Jobs() domain.JobRepository
+	// Audit retorna o log de auditoria do mesmo banco, ou nil quando o banco
+	// não guarda auditoria
+	Audit() domain.AuditRepository
+	// SetupIndexes cria os índices (e, quando necessário, o esquema) usados na busca
+	SetupIndexes(ctx context.Context) error
+	// CheckIndexes verifica se os índices usados na busca existem
+	CheckIndexes(ctx context.Context) error
+	// DeleteByLink remove os documentos do tenant do contexto com o link
+	// informado, usado para substituir uma página reindexada
+	DeleteByLink(ctx context.Context, link string) error
+	// ExportDocuments percorre os documentos que atendem ao filtro, de todos
+	// os tenants e com os embeddings, para backup ou migração entre ambientes
+	ExportDocuments(ctx context.Context, filter ExportFilter, fn func(domain.Document) error) error
+	// RestoreDocuments grava documentos exportados mantendo ID, tenant,
+	// embedding e data de criação, substituindo os que já existem
+	RestoreDocuments(ctx context.Context, docs []domain.Document) error
+	// ExportConversations percorre as conversas criadas no período do filtro
+	ExportConversations(ctx context.Context, filter ExportFilter, fn func(domain.Conversation) error) error
+	// RestoreConversations grava conversas exportadas mantendo as datas originais
+	RestoreConversations(ctx context.Context, convs []domain.Conversation) error
+	// BeginReindex inicia uma reindexação: os documentos gravados nela
+	// substituem todos os atuais, de uma vez, no Commit
+	BeginReindex(ctx context.Context) (Reindex, error)
+	// Clear remove todos os documentos, de todos os tenants
+	Clear(ctx context.Context) error
+	// Close encerra a conexão
+	Close(ctx context.Context) error
+}
+
+// Open conecta ao banco configurado em Driver
+func Open(ctx context.Context, cfg Config) (Store, error) {
+	switch cfg.Driver {
+	case "", DriverMongo:
+		return NewMongoDB(ctx, cfg.MongoURI, cfg.MongoAtlas, cfg.MongoText)
+	case DriverPostgres:
+		return NewPostgres(ctx, cfg.PostgresURL)
+	case DriverQdrant:
+		return NewQdrant(ctx, cfg.Qdrant)
+	default:
+		return nil, fmt.Errorf("banco de dados desconhecido: %q", cfg.Driver)
+	}
+}
+
+// AuditFromEnv retorna o log de auditoria do banco quando AUDIT_LOG=true, ou
+// nil quando a auditoria está desabilitada. Retorna erro se o banco não
+// guardar auditoria.
+func AuditFromEnv(store Store) (domain.AuditRepository, error) {
+	if os.Getenv("AUDIT_LOG") != "true" {
+		return nil, nil
+	}
+	audit := store.Audit()
+	if audit == nil {
+		return nil, errors.New("o banco configurado não guarda o log de auditoria")
+	}
+	return audit, nil
+}
+
+// AnalyticsFromEnv retorna o registro das consultas do banco quando
+// QUERY_ANALYTICS=true, ou nil quando as estatísticas de uso estão
+// desabilitadas. Retorna erro se o banco não guardar as estatísticas.
+func AnalyticsFromEnv(store Store) (domain.AnalyticsRepository, error) {
+	if os.Getenv("QUERY_ANALYTICS") != "true" {
+		return nil, nil
+	}
+	analytics := store.Analytics()
+	if analytics == nil {
+		return nil, errors.New("o banco configurado não guarda as estatísticas das consultas")
+	}
+	return analytics, nil
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Valores padrão do índice de texto do MongoDB
+const (
+	textIndexName           = "documents_text"
+	defaultTextLanguage     = "portuguese" // Idioma dos documentos de exemplo
+	defaultLanguageOverride = "language"   // Campo padrão do MongoDB
+)
+
+// textIndexFields são os campos que podem fazer parte do índice de texto
+var textIndexFields = []string{"title", "content", "tags", "category"}
+
+// defaultTextWeights faz as ocorrências no título pesarem mais que as
+// ocorrências no conteúdo
+var defaultTextWeights = map[string]int{"title": 10, "content": 1}
+
+// TextIndexConfig contém as configurações do índice de texto do MongoDB
+type TextIndexConfig struct {
+	// Language é o idioma usado na redução das palavras ao radical (stemming)
+	// e nas stop words, como "portuguese" (padrão), "english" ou "none"
+	Language string
+	// LanguageOverride é o campo do documento que, quando presente, define o
+	// idioma daquele documento no lugar de Language (padrão: "language")
+	LanguageOverride string
+	// Weights são os campos indexados e o peso de cada um na relevância dos
+	// resultados, entre title, content, tags e category (padrão: title 10 e
+	// content 1)
+	Weights map[string]int
+}
+
+// textIndexConfigFromEnv lê a configuração do índice de texto a partir das
+// variáveis de ambiente
+func textIndexConfigFromEnv() TextIndexConfig {
+	return TextIndexConfig{
+		Language:         os.Getenv("MONGO_TEXT_LANGUAGE"),
+		LanguageOverride: os.Getenv("MONGO_TEXT_LANGUAGE_OVERRIDE"),
+		Weights:          parseTextWeights(os.Getenv("MONGO_TEXT_WEIGHTS")),
+	}
+}
+
+// parseTextWeights lê os pesos no formato "title:10,content:1,tags:5".
+// Campos desconhecidos e pesos inválidos são ignorados com um aviso.
+func parseTextWeights(value string) map[string]int {
+	weights := map[string]int{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		field, weight, _ := strings.Cut(item, ":")
+		field = strings.TrimSpace(field)
+		n, err := strconv.Atoi(strings.TrimSpace(weight))
+		if !slices.Contains(textIndexFields, field) || err != nil || n <= 0 {
+			log.Printf("Aviso: peso do índice de texto inválido em MONGO_TEXT_WEIGHTS: %q", item)
+			continue
+		}
+		weights[field] = n
+	}
+	return weights
+}
+
+// withDefaults preenche os valores não configurados
+func (cfg TextIndexConfig) withDefaults() TextIndexConfig {
+	if cfg.Language == "" {
+		cfg.Language = defaultTextLanguage
+	}
+	if cfg.LanguageOverride == "" {
+		cfg.LanguageOverride = defaultLanguageOverride
+	}
+	if len(cfg.Weights) == 0 {
+		cfg.Weights = maps.Clone(defaultTextWeights)
+	}
+	return cfg
+}
+
+// textIndexSpec é a parte da especificação de um índice usada para
+// identificar o índice de texto e comparar a sua configuração
+type textIndexSpec struct {
+	Name             string         `bson:"name"`
+	Key              bson.M         `bson:"key"`
+	DefaultLanguage  string         `bson:"default_language"`
+	LanguageOverride string         `bson:"language_override"`
+	Weights          map[string]int `bson:"weights"`
+}
+
+// setupTextIndex cria o índice de texto nos campos com peso configurado. A
+// coleção só pode ter um índice de texto, então um índice com outro idioma ou
+// outros pesos é removido e recriado; enquanto isso, a busca textual falha.
+func (m *MongoDB) setupTextIndex(ctx context.Context) error {
+	current, err := m.textIndex(ctx)
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		if current.DefaultLanguage == m.text.Language && current.LanguageOverride == m.text.LanguageOverride &&
+			maps.Equal(current.Weights, m.text.Weights) {
+			return nil
+		}
+		log.Printf("Índice de texto %s com outra configuração (idioma %q, campo de idioma %q, pesos %v); recriando",
+			current.Name, current.DefaultLanguage, current.LanguageOverride, current.Weights)
+		if _, err := m.collection.Indexes().DropOne(ctx, current.Name); err != nil {
+			return fmt.Errorf("erro ao remover índice de texto: %w", err)
+		}
+	}
+
+	var keys, weights bson.D
+	for _, field := range slices.Sorted(maps.Keys(m.text.Weights)) {
+		keys = append(keys, bson.E{Key: field, Value: "text"})
+		weights = append(weights, bson.E{Key: field, Value: m.text.Weights[field]})
+	}
+	_, err = m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: keys,
+		Options: options.Index().
+			SetName(textIndexName).
+			SetDefaultLanguage(m.text.Language).
+			SetLanguageOverride(m.text.LanguageOverride).
+			SetWeights(weights),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar índice de texto: %w", err)
+	}
+	return nil
+}
+
+// textIndex retorna a especificação do índice de texto da coleção, ou nil
+// quando ele não existe
+func (m *MongoDB) textIndex(ctx context.Context) (*textIndexSpec, error) {
+	cursor, err := m.collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar índices: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var specs []textIndexSpec
+	if err := cursor.All(ctx, &specs); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar índices: %w", err)
+	}
+	for _, spec := range specs {
+		if _, ok := spec.Key["_fts"]; ok {
+			return &spec, nil
+		}
+	}
+	return nil, nil
+}
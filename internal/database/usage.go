@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Prefixos das chaves de consumo, que separam sessões e usuários
+const (
+	usageSessionPrefix = "session:"
+	usageUserPrefix    = "user:"
+)
+
+// UsageRepository implementa domain.UsageRepository no MongoDB, com um
+// documento por sessão e por usuário
+type UsageRepository struct {
+	collection *mongo.Collection
+}
+
+// NewUsageRepository cria o repositório de consumo usando a mesma conexão do MongoDB
+func NewUsageRepository(db *MongoDB) *UsageRepository {
+	return &UsageRepository{
+		collection: db.database.Collection("usage"),
+	}
+}
+
+// Record soma o consumo aos totais da sessão e do usuário
+func (r *UsageRepository) Record(ctx context.Context, sessionID, userID string, usage domain.TokenUsage, costUSD float64) error {
+	update := bson.M{
+		"$inc": bson.M{
+			"requests":          1,
+			"prompt_tokens":     usage.PromptTokens,
+			"completion_tokens": usage.CompletionTokens,
+			"cost_usd":          costUSD,
+		},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	for _, key := range usageKeys(ctx, sessionID, userID) {
+		_, err := r.collection.UpdateOne(ctx, bson.M{"_id": key}, update, options.Update().SetUpsert(true))
+		if err != nil {
+			return fmt.Errorf("erro ao registrar consumo: %w", err)
+		}
+	}
+	return nil
+}
+
+// FindBySessionID retorna os totais da sessão
+func (r *UsageRepository) FindBySessionID(ctx context.Context, sessionID string) (*domain.UsageTotals, error) {
+	return r.find(ctx, usageKey(ctx, usageSessionPrefix, sessionID))
+}
+
+// FindByUserID retorna os totais do usuário
+func (r *UsageRepository) FindByUserID(ctx context.Context, userID string) (*domain.UsageTotals, error) {
+	return r.find(ctx, usageKey(ctx, usageUserPrefix, userID))
+}
+
+// find busca os totais da chave, zerados quando ainda não há consumo
+func (r *UsageRepository) find(ctx context.Context, key string) (*domain.UsageTotals, error) {
+	var totals domain.UsageTotals
+	err := r.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&totals)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &domain.UsageTotals{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar consumo: %w", err)
+	}
+	return &totals, nil
+}
+
+// usageKeys retorna as chaves que recebem o consumo, ignorando IDs vazios
+func usageKeys(ctx context.Context, sessionID, userID string) []string {
+	var keys []string
+	if sessionID != "" {
+		keys = append(keys, usageKey(ctx, usageSessionPrefix, sessionID))
+	}
+	if userID != "" {
+		keys = append(keys, usageKey(ctx, usageUserPrefix, userID))
+	}
+	return keys
+}
+
+// usageKey monta a chave de uma sessão ou usuário. Fora do tenant padrão, a
+// chave começa pelo tenant do contexto, para que os totais não se misturem.
+func usageKey(ctx context.Context, prefix, id string) string {
+	if tenantID := domain.TenantFromContext(ctx); tenantID != "" {
+		return "tenant:" + tenantID + ":" + prefix + id
+	}
+	return prefix + id
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// versions retorna a coleção com as versões anteriores dos documentos
+func (m *MongoDB) versions() *mongo.Collection {
+	return m.database.Collection("document_versions")
+}
+
+// Update substitui o conteúdo do documento e guarda o estado anterior em
+// document_versions. O índice único por documento e versão faz com que, de
+// duas atualizações simultâneas, apenas a primeira seja aplicada.
+func (m *MongoDB) Update(ctx context.Context, doc *domain.Document) error {
+	objectID, err := primitive.ObjectIDFromHex(doc.ID)
+	if err != nil {
+		return domain.ErrDocumentNotFound
+	}
+
+	var current domain.Document
+	err = m.collection.FindOne(ctx, tenantFilter(ctx, bson.M{"_id": objectID})).Decode(&current)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.ErrDocumentNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+
+	if _, err := m.versions().InsertOne(ctx, domain.NewDocumentVersion(current)); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrVersionConflict
+		}
+		return fmt.Errorf("erro ao guardar versão: %w", err)
+	}
+
+	version := current.CurrentVersion() + 1
+	now := time.Now()
+	set := bson.M{
+		"title":      doc.Title,
+		"content":    doc.Content,
+		"link":       doc.Link,
+		"category":   doc.Category,
+		"version":    version,
+		"updated_at": now,
+	}
+	unset := bson.M{}
+	if doc.ContentHash != "" {
+		set["content_hash"] = doc.ContentHash
+	} else {
+		unset["content_hash"] = ""
+	}
+	if !doc.ExpiresAt.IsZero() {
+		set["expires_at"] = doc.ExpiresAt
+	} else {
+		unset["expires_at"] = ""
+	}
+	if len(doc.Metadata) > 0 {
+		set["metadata"] = doc.Metadata
+	} else {
+		unset["metadata"] = ""
+	}
+	if len(doc.Tags) > 0 {
+		set["tags"] = doc.Tags
+	} else {
+		unset["tags"] = ""
+	}
+	if len(doc.ACL) > 0 {
+		set["acl"] = doc.ACL
+	} else {
+		unset["acl"] = ""
+	}
+	if len(doc.Embedding) > 0 {
+		set["embedding"] = doc.Embedding
+	} else {
+		unset["embedding"] = ""
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	if _, err := m.collection.UpdateOne(ctx, tenantFilter(ctx, bson.M{"_id": objectID}), update); err != nil {
+		// A versão guardada impediria as próximas atualizações, que
+		// encontrariam o índice único ocupado. O descarte não é cancelado
+		// junto com a requisição, para valer também em timeouts.
+		filter := tenantFilter(ctx, bson.M{"document_id": doc.ID, "version": current.CurrentVersion()})
+		if _, err := m.versions().DeleteOne(context.WithoutCancel(ctx), filter); err != nil {
+			log.Printf("Aviso ao descartar versão do documento %s: %v", doc.ID, err)
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrDuplicateDocument
+		}
+		return fmt.Errorf("erro ao atualizar documento: %w", err)
+	}
+
+	doc.TenantID, doc.ParentID, doc.ChunkIndex = current.TenantID, current.ParentID, current.ChunkIndex
+	doc.CreatedAt, doc.DeletedAt = current.CreatedAt, current.DeletedAt
+	doc.Version, doc.UpdatedAt = version, now
+	return nil
+}
+
+// GetVersionHistory retorna as versões anteriores do documento, da mais
+// antiga para a mais recente
+func (m *MongoDB) GetVersionHistory(ctx context.Context, id string) ([]domain.DocumentVersion, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+
+	cursor, err := m.versions().Find(ctx, tenantFilter(ctx, bson.M{"document_id": id}), findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar versões: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	versions := []domain.DocumentVersion{}
+	if err = cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar versões: %w", err)
+	}
+	return versions, nil
+}
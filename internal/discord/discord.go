@@ -0,0 +1,381 @@
+// Package discord atende o comando /ask do Discord pelo endpoint de
+// interações (HTTP), respondendo com o agente do RAG e atualizando a
+// mensagem à medida que a resposta é gerada
+package discord
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Valores padrão do bot
+const (
+	defaultAPIURL   = "https://discord.com/api/v10"
+	defaultTimeout  = 2 * time.Minute
+	editInterval    = 1500 * time.Millisecond // Intervalo mínimo entre as edições da mensagem
+	maxMessageSize  = 2000                    // Limite de caracteres de uma mensagem do Discord
+	maxRequestBody  = 1 << 20
+	maxSourcesShown = 5
+)
+
+// Tipos de interação e de resposta do Discord
+const (
+	interactionPing               = 1
+	interactionApplicationCommand = 2
+	responsePong                  = 1
+	responseDeferredMessage       = 5
+)
+
+// askCommand é o nome do comando de pergunta
+const askCommand = "ask"
+
+// Config contém as configurações do bot
+type Config struct {
+	ApplicationID string
+	PublicKey     string // Chave pública da aplicação, em hexadecimal, usada na verificação das interações
+	BotToken      string // Usado apenas no registro dos comandos
+	APIURL        string
+	Timeout       time.Duration // Tempo máximo de cada pergunta (padrão: 2m; o Discord aceita edições por 15m)
+}
+
+// ConfigFromEnv lê a configuração do bot a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	timeout, _ := time.ParseDuration(os.Getenv("DISCORD_TIMEOUT"))
+	return Config{
+		ApplicationID: os.Getenv("DISCORD_APPLICATION_ID"),
+		PublicKey:     os.Getenv("DISCORD_PUBLIC_KEY"),
+		BotToken:      os.Getenv("DISCORD_BOT_TOKEN"),
+		APIURL:        os.Getenv("DISCORD_API_URL"),
+		Timeout:       timeout,
+	}
+}
+
+// Bot responde as interações do Discord com o serviço RAG
+type Bot struct {
+	svc        domain.RAGService
+	config     Config
+	publicKey  ed25519.PublicKey
+	httpClient *http.Client
+	wg         sync.WaitGroup // Perguntas em andamento
+}
+
+// New cria o bot
+func New(svc domain.RAGService, cfg Config) (*Bot, error) {
+	if cfg.ApplicationID == "" {
+		return nil, fmt.Errorf("DISCORD_APPLICATION_ID não definida")
+	}
+	key, err := hex.DecodeString(cfg.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("DISCORD_PUBLIC_KEY inválida")
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = defaultAPIURL
+	}
+	cfg.APIURL = strings.TrimRight(cfg.APIURL, "/")
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	return &Bot{svc: svc, config: cfg, publicKey: key, httpClient: http.DefaultClient}, nil
+}
+
+// RegisterCommands registra o comando /ask na aplicação, substituindo os
+// comandos globais existentes
+func (b *Bot) RegisterCommands(ctx context.Context) error {
+	if b.config.BotToken == "" {
+		return fmt.Errorf("DISCORD_BOT_TOKEN não definida")
+	}
+	commands := []map[string]any{{
+		"name":        askCommand,
+		"description": "Pergunte à base de conhecimento",
+		"options": []map[string]any{{
+			"type":        3, // STRING
+			"name":        "question",
+			"description": "Sua pergunta",
+			"required":    true,
+		}},
+	}}
+	return b.request(ctx, http.MethodPut, "/applications/"+b.config.ApplicationID+"/commands", "Bot "+b.config.BotToken, commands)
+}
+
+// Wait espera as perguntas em andamento terminarem
+func (b *Bot) Wait() {
+	b.wg.Wait()
+}
+
+// interaction é a parte usada de uma interação recebida do Discord
+type interaction struct {
+	Type      int    `json:"type"`
+	Token     string `json:"token"`
+	ChannelID string `json:"channel_id"`
+	Member    *struct {
+		User user `json:"user"`
+	} `json:"member"` // Presente nas interações em servidores
+	User *user `json:"user"` // Presente nas mensagens diretas
+	Data struct {
+		Name    string `json:"name"`
+		Options []struct {
+			Name  string `json:"name"`
+			Value any    `json:"value"`
+		} `json:"options"`
+	} `json:"data"`
+}
+
+// user é o autor da interação
+type user struct {
+	ID string `json:"id"`
+}
+
+// userID retorna o autor da interação
+func (i interaction) userID() string {
+	if i.Member != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// option retorna o valor de uma opção do comando
+func (i interaction) option(name string) string {
+	for _, opt := range i.Data.Options {
+		if opt.Name == name {
+			value, _ := opt.Value.(string)
+			return value
+		}
+	}
+	return ""
+}
+
+// ServeHTTP recebe as interações do Discord. Cada interação é verificada
+// pela assinatura; o /ask é confirmado na hora e respondido em segundo plano,
+// já que o Discord espera a confirmação em até 3 segundos.
+func (b *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	if err != nil {
+		http.Error(w, "corpo inválido", http.StatusBadRequest)
+		return
+	}
+	if !b.verify(r.Header, body) {
+		http.Error(w, "assinatura inválida", http.StatusUnauthorized)
+		return
+	}
+
+	var in interaction
+	if err := json.Unmarshal(body, &in); err != nil {
+		http.Error(w, "interação inválida", http.StatusBadRequest)
+		return
+	}
+	switch {
+	case in.Type == interactionPing:
+		writeJSON(w, map[string]int{"type": responsePong})
+	case in.Type == interactionApplicationCommand && in.Data.Name == askCommand:
+		writeJSON(w, map[string]int{"type": responseDeferredMessage})
+		// A resposta continua depois da requisição, com os valores do
+		// contexto dela (como o tenant)
+		ctx := context.WithoutCancel(r.Context())
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.answer(ctx, in)
+		}()
+	default:
+		http.Error(w, "interação não suportada", http.StatusBadRequest)
+	}
+}
+
+// verify confere a assinatura Ed25519 da interação
+func (b *Bot) verify(header http.Header, body []byte) bool {
+	signature, err := hex.DecodeString(header.Get("X-Signature-Ed25519"))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return false
+	}
+	timestamp := header.Get("X-Signature-Timestamp")
+	return ed25519.Verify(b.publicKey, append([]byte(timestamp), body...), signature)
+}
+
+// answer responde o /ask, editando a mensagem com o texto já gerado a cada
+// editInterval e, ao final, com a resposta completa e as fontes. A sessão é
+// a do usuário no canal, então as perguntas seguintes usam o histórico.
+func (b *Bot) answer(ctx context.Context, in interaction) {
+	base := ctx
+	ctx, cancel := context.WithTimeout(ctx, b.config.Timeout)
+	defer cancel()
+
+	question := in.option("question")
+	userID := in.userID()
+	var mu sync.Mutex
+	var partial strings.Builder
+	changed := false
+
+	// Edita a mensagem enquanto a resposta é gerada
+	done := make(chan struct{})
+	editorDone := make(chan struct{})
+	go func() {
+		defer close(editorDone)
+		ticker := time.NewTicker(editInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				mu.Lock()
+				text, ok := partial.String(), changed
+				changed = false
+				mu.Unlock()
+				if ok {
+					b.edit(ctx, in.Token, truncate(text+" …"))
+				}
+			}
+		}
+	}()
+
+	resp, err := b.svc.ProcessQueryStream(ctx, domain.RAGRequest{
+		Query:     question,
+		SessionID: "discord-" + in.ChannelID + "-" + userID,
+		UserID:    "discord:" + userID,
+	}, func(token string) {
+		mu.Lock()
+		partial.WriteString(token)
+		changed = true
+		mu.Unlock()
+	})
+	close(done)
+	<-editorDone
+
+	if err != nil {
+		log.Printf("Erro ao responder pergunta do Discord: %v", err)
+		b.edit(base, in.Token, truncate(errorMessage(err)))
+		return
+	}
+	b.edit(base, in.Token, formatAnswer(resp))
+}
+
+// edit substitui o conteúdo da resposta à interação
+func (b *Bot) edit(ctx context.Context, token, content string) {
+	path := "/webhooks/" + b.config.ApplicationID + "/" + token + "/messages/@original"
+	body := map[string]any{"content": content, "allowed_mentions": map[string]any{"parse": []string{}}}
+	if err := b.request(ctx, http.MethodPatch, path, "", body); err != nil {
+		log.Printf("Aviso ao editar mensagem do Discord: %v", err)
+	}
+}
+
+// request chama a API do Discord
+func (b *Bot) request(ctx context.Context, method, path, authorization string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, b.config.APIURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro na chamada ao Discord: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return fmt.Errorf("erro na chamada ao Discord (status %d): %s", resp.StatusCode, raw)
+	}
+	return nil
+}
+
+// formatAnswer monta a mensagem final: a resposta e as fontes citadas ou,
+// sem citações, as primeiras fontes consultadas
+func formatAnswer(resp *domain.RAGResponse) string {
+	var sources []string
+	if len(resp.Citations) > 0 {
+		for _, c := range resp.Citations {
+			sources = append(sources, fmt.Sprintf("[%d] %s", c.Marker, sourceLink(c.Title, c.Link, c.External)))
+		}
+	} else {
+		for _, doc := range resp.Sources[:min(len(resp.Sources), maxSourcesShown)] {
+			sources = append(sources, "- "+sourceLink(doc.Title, doc.Link, doc.External()))
+		}
+	}
+	if len(sources) == 0 {
+		return truncate(resp.Answer)
+	}
+
+	footer := "\n\n**Fontes:**\n" + strings.Join(sources, "\n")
+	if utf8.RuneCountInString(footer) >= maxMessageSize/2 {
+		return truncate(resp.Answer)
+	}
+	return truncate(resp.Answer, footer) + footer
+}
+
+// sourceLink formata uma fonte como link, sem a pré-visualização do Discord
+func sourceLink(title, link string, external bool) string {
+	text := title
+	if link != "" {
+		text = fmt.Sprintf("[%s](<%s>)", title, link)
+	}
+	if external {
+		text += " (web)"
+	}
+	return text
+}
+
+// truncate corta o texto para caber em uma mensagem, junto com o sufixo
+func truncate(text string, suffix ...string) string {
+	limit := maxMessageSize - utf8.RuneCountInString(strings.Join(suffix, ""))
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// errorMessage é a mensagem exibida ao usuário quando a pergunta falha
+func errorMessage(err error) string {
+	var timeout *domain.TimeoutResult
+	var validationErr *domain.ValidationError
+	var rateLimitErr *domain.RateLimitError
+	switch {
+	case errors.As(err, &timeout):
+		if timeout.PartialAnswer != "" {
+			return timeout.PartialAnswer + "\n\n_(resposta interrompida pelo limite de tempo)_"
+		}
+		return "A pergunta excedeu o tempo limite."
+	case errors.As(err, &validationErr), errors.As(err, &rateLimitErr),
+		errors.Is(err, domain.ErrPromptTooLarge), errors.Is(err, domain.ErrContentBlocked):
+		return err.Error()
+	case errors.Is(err, domain.ErrLLMUnavailable), errors.Is(err, domain.ErrLLMQuotaExceeded):
+		return domain.ErrLLMUnavailable.Error()
+	default:
+		return "Não foi possível responder à pergunta."
+	}
+}
+
+// writeJSON responde a interação em JSON
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
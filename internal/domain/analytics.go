@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Tipos de consulta registrados nas estatísticas de uso
+const (
+	QueryKindAgent  = "query"  // Pergunta ao agente, com ou sem streaming
+	QueryKindSearch = "search" // Busca direta na base, sem o agente
+)
+
+// QueryRecord é o registro de uma consulta para as estatísticas de uso
+type QueryRecord struct {
+	TenantID string `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"` // Preenchido pelo repositório a partir do contexto
+	Kind     string `bson:"kind" json:"kind"`                               // QueryKindAgent ou QueryKindSearch
+	// Query é o texto da consulta em minúsculas e com os espaços
+	// normalizados, para que as repetições sejam agrupadas
+	Query    string `bson:"query" json:"query"`
+	Category string `bson:"category,omitempty" json:"category,omitempty"` // Categoria pedida no filtro
+	Results  int    `bson:"results" json:"results"`                       // Documentos encontrados ou usados como fonte
+	// ZeroHit indica uma consulta que buscou na base e não encontrou nada
+	ZeroHit   bool      `bson:"zero_hit" json:"zero_hit"`
+	Failed    bool      `bson:"failed,omitempty" json:"failed,omitempty"`
+	LatencyMS float64   `bson:"latency_ms" json:"latency_ms"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// QueryStats são as estatísticas das consultas de um período
+type QueryStats struct {
+	Queries      int     `json:"queries"`
+	Failed       int     `json:"failed"`
+	ZeroHits     int     `json:"zero_hits"`
+	AvgLatencyMS float64 `json:"avg_latency_ms"`
+	P95LatencyMS float64 `json:"p95_latency_ms"`
+	// TopQueries são as consultas mais frequentes, da mais para a menos frequente
+	TopQueries []QueryCount `json:"top_queries"`
+	// ZeroHitQueries são as consultas sem resultados mais frequentes
+	ZeroHitQueries []QueryCount `json:"zero_hit_queries"`
+	// Categories conta as consultas por categoria pedida, da mais para a
+	// menos frequente; a categoria vazia reúne as consultas sem filtro
+	Categories []CategoryCount `json:"categories"`
+}
+
+// QueryCount é a quantidade de vezes que uma consulta foi feita
+type QueryCount struct {
+	Query string `json:"query"`
+	Count int    `json:"count"`
+}
+
+// CategoryCount é a quantidade de consultas de uma categoria
+type CategoryCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// AnalyticsRepository guarda o registro das consultas e calcula as suas
+// estatísticas, separadas por tenant (o do contexto)
+type AnalyticsRepository interface {
+	// Record guarda o registro da consulta. Sem CreatedAt, usa o momento atual.
+	Record(ctx context.Context, record QueryRecord) error
+	// Stats calcula as estatísticas das consultas feitas desde a data
+	// informada, com até limit itens em cada lista
+	Stats(ctx context.Context, since time.Time, limit int) (*QueryStats, error)
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAPIKeyNotFound indica que a chave de API não existe
+var ErrAPIKeyNotFound = errors.New("chave de API não encontrada")
+
+// apiKeyPrefix identifica as chaves geradas pela aplicação
+const apiKeyPrefix = "rag_"
+
+// APIKey é uma chave de acesso à API. Apenas o hash do segredo é guardado;
+// o segredo é exibido uma única vez, na criação.
+type APIKey struct {
+	ID        string    `bson:"_id,omitempty" json:"id"`
+	Name      string    `bson:"name" json:"name"`                               // Descrição da chave (ex: "backend de vendas")
+	UserID    string    `bson:"user_id" json:"user_id"`                         // Usuário ao qual as perguntas feitas com a chave são atribuídas
+	TenantID  string    `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"` // Tenant acessado com a chave
+	Groups    []string  `bson:"groups,omitempty" json:"groups,omitempty"`       // Grupos do usuário, usados na ACL dos documentos
+	Hash      string    `bson:"hash" json:"-"`                                  // SHA-256 do segredo
+	Prefix    string    `bson:"prefix" json:"prefix"`                           // Início do segredo, para identificar a chave
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	RevokedAt time.Time `bson:"revoked_at,omitempty" json:"revoked_at,omitzero"`
+}
+
+// Revoked informa se a chave foi revogada
+func (k APIKey) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// NewAPIKey gera uma chave para o usuário, membro dos grupos informados, e
+// retorna o segredo, que não é guardado e não pode ser recuperado depois
+func NewAPIKey(name, userID, tenantID string, groups []string) (*APIKey, string, error) {
+	if userID == "" {
+		return nil, "", &ValidationError{Field: "user_id", Message: "não pode ser vazio"}
+	}
+	if err := ValidateTenantID(tenantID); err != nil {
+		return nil, "", err
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", fmt.Errorf("erro ao gerar chave de API: %v", err)
+	}
+	secret := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	key := &APIKey{
+		Name:      name,
+		UserID:    userID,
+		TenantID:  tenantID,
+		Groups:    NormalizeGroups(groups),
+		Hash:      HashAPIKey(secret),
+		Prefix:    secret[:len(apiKeyPrefix)+6],
+		CreatedAt: time.Now(),
+	}
+	return key, secret, nil
+}
+
+// HashAPIKey retorna o hash com que o segredo é guardado e procurado na base
+func HashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyRepository guarda as chaves de acesso à API. As chaves não são
+// separadas por tenant: o tenant faz parte da própria chave.
+type APIKeyRepository interface {
+	// Create grava a chave e preenche o seu ID
+	Create(ctx context.Context, key *APIKey) error
+	// FindByHash busca a chave pelo hash do segredo, inclusive as revogadas
+	FindByHash(ctx context.Context, hash string) (*APIKey, error)
+	// List retorna todas as chaves, da mais recente para a mais antiga
+	List(ctx context.Context) ([]APIKey, error)
+	// Revoke revoga a chave; revogar uma chave já revogada não tem efeito
+	Revoke(ctx context.Context, id string) error
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Ações registradas no log de auditoria
+const (
+	AuditQuery            = "query"             // Pergunta recebida
+	AuditAnswer           = "answer"            // Resposta entregue, ou o erro que a impediu
+	AuditToolCall         = "tool_call"         // Ferramenta executada pelo agente
+	AuditDocumentCreate   = "document.create"   // Documento inserido
+	AuditDocumentUpdate   = "document.update"   // Documento alterado
+	AuditDocumentRollback = "document.rollback" // Documento voltado a uma versão anterior
+	AuditDocumentDelete   = "document.delete"   // Documento excluído logicamente
+	AuditDocumentRestore  = "document.restore"  // Exclusão lógica desfeita
+	AuditDocumentReembed  = "document.reembed"  // Embedding gerado novamente
+)
+
+// AuditEntry é um registro do log de auditoria: quem fez o quê, quando e em
+// qual requisição. As entradas nunca são alteradas nem removidas.
+type AuditEntry struct {
+	ID        string    `bson:"_id,omitempty" json:"id"`
+	TenantID  string    `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	Time      time.Time `bson:"time" json:"time"`
+	Action    string    `bson:"action" json:"action"`
+	Actor     string    `bson:"actor,omitempty" json:"actor,omitempty"`           // Usuário ou chave que executou a ação
+	RequestID string    `bson:"request_id,omitempty" json:"request_id,omitempty"` // Requisição em que a ação foi executada
+	SessionID string    `bson:"session_id,omitempty" json:"session_id,omitempty"`
+	Resource  string    `bson:"resource,omitempty" json:"resource,omitempty"` // ID do documento ou nome da ferramenta
+	// Details guarda os dados da ação, como a pergunta, a resposta e as
+	// fontes ou os argumentos da ferramenta
+	Details map[string]any `bson:"details,omitempty" json:"details,omitempty"`
+	Error   string         `bson:"error,omitempty" json:"error,omitempty"` // Motivo da falha, quando a ação falhou
+}
+
+// AuditFilter restringe as entradas listadas. Campos vazios não filtram.
+type AuditFilter struct {
+	Action    string
+	Actor     string
+	RequestID string
+	Resource  string
+	Since     time.Time // Inclusive
+	Until     time.Time // Exclusive
+}
+
+// AuditRepository guarda o log de auditoria, separado por tenant. O log só
+// recebe novas entradas: não há operações de alteração ou remoção.
+type AuditRepository interface {
+	// Append grava a entrada no tenant do contexto e preenche o seu ID. Sem
+	// Time, Actor e RequestID, usa o momento atual e o ator e a requisição
+	// do contexto.
+	Append(ctx context.Context, entry *AuditEntry) error
+	// List retorna até limit entradas do tenant do contexto que atendem ao
+	// filtro, da mais recente para a mais antiga, a partir do cursor (vazio
+	// na primeira página), e o cursor da próxima página, vazio quando não há
+	// mais entradas
+	List(ctx context.Context, filter AuditFilter, cursor string, limit int) ([]AuditEntry, string, error)
+}
+
+// actorKey é a chave do ator no contexto
+type actorKey struct{}
+
+// WithActor guarda no contexto quem executa as operações, registrado no log
+// de auditoria
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext retorna o ator guardado no contexto, ou vazio
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// requestIDKey é a chave do ID da requisição no contexto
+type requestIDKey struct{}
+
+// WithRequestID guarda no contexto o ID da requisição que originou as
+// operações, para relacioná-las no log de auditoria
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext retorna o ID da requisição guardado no contexto, ou vazio
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// FillFromContext completa a entrada com o momento atual e o ator e a
+// requisição do contexto, mantendo os campos já preenchidos
+func (e *AuditEntry) FillFromContext(ctx context.Context) {
+	e.TenantID = TenantFromContext(ctx)
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.Actor == "" {
+		e.Actor = ActorFromContext(ctx)
+	}
+	if e.RequestID == "" {
+		e.RequestID = RequestIDFromContext(ctx)
+	}
+}
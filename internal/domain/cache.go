@@ -0,0 +1,28 @@
+package domain
+
+import "context"
+
+// ResponseCache guarda respostas já geradas, indexadas pelo embedding da
+// pergunta, para responder perguntas semelhantes sem chamar o LLM. As
+// respostas são separadas pelo tenant do contexto.
+type ResponseCache interface {
+	// Lookup retorna a resposta de uma pergunta semelhante ao vetor, ou nil se não houver
+	Lookup(ctx context.Context, vector []float32) (*RAGResponse, error)
+	// Store guarda a resposta da pergunta representada pelo vetor
+	Store(ctx context.Context, vector []float32, resp *RAGResponse) error
+	// Invalidate descarta todas as respostas guardadas
+	Invalidate(ctx context.Context) error
+}
+
+// QueryCache guarda respostas pela pergunta exata: a chave, montada pelo
+// serviço, combina a pergunta normalizada e os filtros da busca. Dispensa o
+// embedding da pergunta, então é consultado antes do ResponseCache. As
+// respostas são separadas pelo tenant do contexto.
+type QueryCache interface {
+	// Get retorna a resposta guardada com a chave, ou nil se não houver
+	Get(ctx context.Context, key string) (*RAGResponse, error)
+	// Set guarda a resposta com a chave
+	Set(ctx context.Context, key string, resp *RAGResponse) error
+	// Invalidate descarta todas as respostas guardadas
+	Invalidate(ctx context.Context) error
+}
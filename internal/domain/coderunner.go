@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+// CodeResult é o resultado da execução de um programa
+type CodeResult struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr,omitempty"`
+	ExitCode int    `json:"exit_code"`
+	TimedOut bool   `json:"timed_out,omitempty"` // O programa foi interrompido pelo limite de tempo
+}
+
+// CodeRunner executa pequenos programas isolados, sem rede e sem acesso aos
+// arquivos do servidor
+type CodeRunner interface {
+	// Languages retorna as linguagens aceitas
+	Languages() []string
+	// Run executa o programa e retorna a saída. Falhas do próprio programa,
+	// como erros de compilação, vêm no resultado; o erro indica que não foi
+	// possível executá-lo.
+	Run(ctx context.Context, language, code string) (*CodeResult, error)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrConversationNotFound indica que não existe conversa para a sessão
+var ErrConversationNotFound = errors.New("conversa não encontrada")
+
+// Conversation representa o histórico de mensagens de uma sessão
+type Conversation struct {
+	SessionID string `bson:"_id" json:"session_id"`
+	TenantID  string `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"` // Preenchido pelo repositório a partir do contexto
+	// UserID é o dono da conversa, o primeiro usuário identificado que
+	// perguntou nela; vazio em conversas anônimas
+	UserID    string    `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Messages  []Message `bson:"messages" json:"messages"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+
+	// Summary resume os turnos mais antigos, retirados de Messages para que
+	// o histórico caiba no contexto; vazio enquanto nenhum turno foi resumido
+	Summary string `bson:"summary,omitempty" json:"summary,omitempty"`
+	// SummarizedMessages é a quantidade de mensagens incorporadas ao resumo
+	SummarizedMessages int `bson:"summarized_messages,omitempty" json:"summarized_messages,omitempty"`
+}
+
+// AccessibleTo indica se o usuário pode ler e continuar a conversa: as
+// anônimas são de todos, e as com dono, apenas dele
+func (c *Conversation) AccessibleTo(userID string) bool {
+	return c.UserID == "" || c.UserID == userID
+}
+
+// ConversationRepository define as operações de persistência de conversas,
+// restritas ao tenant do contexto
+type ConversationRepository interface {
+	// FindBySessionID busca a conversa da sessão, retornando
+	// ErrConversationNotFound se ela não existir no tenant
+	FindBySessionID(ctx context.Context, sessionID string) (*Conversation, error)
+	// Save cria ou substitui a conversa da sessão
+	Save(ctx context.Context, conv *Conversation) error
+}
@@ -0,0 +1,339 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Document representa um documento da base de conhecimento
+type Document struct {
+	ID       string `bson:"_id,omitempty" json:"id,omitempty"`
+	Title    string `bson:"title" json:"title"`
+	Content  string `bson:"content" json:"content"`
+	Link     string `bson:"link" json:"link"`
+	Category string `bson:"category" json:"category"`
+
+	// TenantID é o tenant dono do documento, preenchido pelo repositório a
+	// partir do contexto; vazio no tenant padrão
+	TenantID string `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+
+	// Metadata guarda atributos livres do documento, como autor ou origem
+	Metadata map[string]string `bson:"metadata,omitempty" json:"metadata,omitempty"`
+
+	// Tags classificam o documento com mais detalhe que a categoria e podem
+	// ser usadas para restringir as buscas
+	Tags []string `bson:"tags,omitempty" json:"tags,omitempty"`
+
+	// ACL lista os grupos (ou papéis) que podem ver o documento nas buscas
+	// e nas leituras pela API; vazia, o documento é visível a todos os usuários do tenant
+	ACL []string `bson:"acl,omitempty" json:"acl,omitempty"`
+
+	// Embedding é o vetor semântico do documento, usado na busca vetorial
+	Embedding []float32 `bson:"embedding,omitempty" json:"-"`
+
+	// ContentHash é o hash do conteúdo normalizado do documento lógico,
+	// calculado por ContentHash; os chunks herdam o hash do documento. Vazio
+	// em documentos inseridos antes da deduplicação.
+	ContentHash string `bson:"content_hash,omitempty" json:"content_hash,omitempty"`
+
+	// Documentos longos são divididos em chunks ligados pelo ParentID
+	ParentID   string `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	ChunkIndex int    `bson:"chunk_index,omitempty" json:"chunk_index,omitempty"`
+
+	// Version aumenta a cada Update; zero em documentos nunca atualizados
+	Version int `bson:"version,omitempty" json:"version,omitempty"`
+
+	// CreatedAt é o momento da inserção, preenchido pelo repositório
+	CreatedAt time.Time `bson:"created_at,omitempty" json:"created_at,omitzero"`
+	// UpdatedAt é o momento da última atualização, preenchido pelo repositório
+	UpdatedAt time.Time `bson:"updated_at,omitempty" json:"updated_at,omitzero"`
+	// DeletedAt é o momento da exclusão lógica; documentos excluídos ficam
+	// fora das buscas até serem restaurados
+	DeletedAt time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitzero"`
+	// ExpiresAt é o momento em que o documento deixa de valer, como em notas
+	// de versão e anúncios; zero em documentos sem validade. Documentos
+	// expirados ficam fora das buscas e são removidos pelo MongoDB.
+	ExpiresAt time.Time `bson:"expires_at,omitempty" json:"expires_at,omitzero"`
+}
+
+// Deleted indica se o documento foi excluído logicamente
+func (d Document) Deleted() bool {
+	return !d.DeletedAt.IsZero()
+}
+
+// Expired indica se o documento tem validade e ela já passou no momento informado
+func (d Document) Expired(now time.Time) bool {
+	return !d.ExpiresAt.IsZero() && !d.ExpiresAt.After(now)
+}
+
+// CurrentVersion retorna o número da versão atual, a partir de 1
+func (d Document) CurrentVersion() int {
+	return max(d.Version, 1)
+}
+
+// EmbeddingText retorna o texto usado para gerar o embedding do documento
+func (d Document) EmbeddingText() string {
+	return d.Title + "\n" + d.Content
+}
+
+// ContentHash calcula o hash SHA-256 do conteúdo normalizado: sem diferença
+// de maiúsculas e com os espaços, quebras de linha e tabulações reduzidos a
+// um espaço, para que cópias com formatação diferente sejam reconhecidas
+func ContentHash(content string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(content)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// ErrDuplicateDocument indica que já existe um documento com o mesmo conteúdo
+var ErrDuplicateDocument = errors.New("já existe um documento com o mesmo conteúdo")
+
+// DuplicateDocumentError identifica o documento que já tem o conteúdo
+type DuplicateDocumentError struct {
+	ID string // ID do documento existente (ou do documento lógico dos chunks)
+}
+
+// Error implementa a interface error
+func (e *DuplicateDocumentError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrDuplicateDocument, e.ID)
+}
+
+// Unwrap permite identificar o erro com errors.Is(err, ErrDuplicateDocument)
+func (e *DuplicateDocumentError) Unwrap() error {
+	return ErrDuplicateDocument
+}
+
+// DocumentFilter restringe os documentos listados. Campos vazios não filtram.
+type DocumentFilter struct {
+	Category       string
+	ParentID       string // Restringe aos chunks do documento lógico
+	IncludeDeleted bool   // Inclui os documentos excluídos logicamente
+
+	// RestrictACL aplica a ACL dos documentos com os grupos em Groups, como
+	// SearchFilter.Groups nas buscas. Sem ela, como na manutenção da base,
+	// todos os documentos são listados.
+	RestrictACL bool
+	Groups      []string
+}
+
+// NormalizeTags padroniza as tags para comparação: sem espaços nas pontas,
+// em minúsculas, sem vazias e sem repetidas, na ordem original
+func NormalizeTags(tags []string) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// NormalizeGroups padroniza os grupos de uma ACL ou de um usuário: sem
+// espaços nas pontas, sem vazios e sem repetidos, na ordem original. Ao
+// contrário das tags, maiúsculas e minúsculas são diferenciadas, pois os
+// grupos costumam vir de um provedor de identidade.
+func NormalizeGroups(groups []string) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, group := range groups {
+		group = strings.TrimSpace(group)
+		if group == "" || seen[group] {
+			continue
+		}
+		seen[group] = true
+		result = append(result, group)
+	}
+	return result
+}
+
+// VisibleTo indica se o documento pode ser visto por quem tem os grupos
+// informados: documentos sem ACL são visíveis a todos, e os demais apenas
+// quando a ACL contém um dos grupos
+func (d Document) VisibleTo(groups []string) bool {
+	if len(d.ACL) == 0 {
+		return true
+	}
+	for _, group := range groups {
+		if slices.Contains(d.ACL, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// readerGroupsKey identifica no contexto os grupos de quem lê os documentos
+type readerGroupsKey struct{}
+
+// WithReaderGroups guarda no contexto os grupos de quem lê os documentos. A
+// leitura pelo ID, o histórico de versões e a listagem passam a respeitar a
+// ACL, como as buscas, e os documentos fora dela não são encontrados.
+func WithReaderGroups(ctx context.Context, groups []string) context.Context {
+	return context.WithValue(ctx, readerGroupsKey{}, NormalizeGroups(groups))
+}
+
+// ReaderGroupsFromContext retorna os grupos guardados por WithReaderGroups.
+// O segundo valor é falso quando o contexto não restringe as leituras, como
+// na manutenção da base.
+func ReaderGroupsFromContext(ctx context.Context) ([]string, bool) {
+	groups, ok := ctx.Value(readerGroupsKey{}).([]string)
+	return groups, ok
+}
+
+// TagMode define como as tags de um SearchFilter são combinadas
+type TagMode string
+
+const (
+	TagMatchAny TagMode = "any" // O documento tem ao menos uma das tags
+	TagMatchAll TagMode = "all" // O documento tem todas as tags
+)
+
+// SearchFilter restringe os documentos retornados pelas buscas. Campos vazios
+// não filtram.
+type SearchFilter struct {
+	Category string
+	Metadata map[string]string // O documento precisa ter todos os valores informados
+	Tags     []string
+	TagMode  TagMode // Padrão: TagMatchAny
+	// Conditions são comparações sobre os metadados, como faixas de valores,
+	// que o documento precisa atender todas
+	Conditions []MetadataCondition
+
+	// Groups são os grupos de quem busca. Documentos com ACL só são
+	// retornados quando ela contém um desses grupos; sem grupos, apenas os
+	// documentos sem ACL são retornados.
+	Groups []string
+
+	// Fuzzy tolera erros de digitação na busca textual, como "goroutins"
+	// encontrando os documentos sobre goroutines
+	Fuzzy bool
+
+	// IncludeDeleted inclui nas buscas os documentos excluídos logicamente,
+	// que por padrão ficam de fora
+	IncludeDeleted bool
+}
+
+// Empty indica se o filtro não restringe nem amplia as buscas além da ACL
+// dos documentos, isto é, se o resultado é o mesmo para qualquer usuário
+func (f SearchFilter) Empty() bool {
+	return f.Category == "" && len(f.Metadata) == 0 && len(f.Tags) == 0 && len(f.Conditions) == 0 && len(f.Groups) == 0 && !f.IncludeDeleted
+}
+
+// Merge completa o filtro com os campos de other. Os campos já preenchidos
+// prevalecem e as condições de other são somadas às do filtro, então o
+// resultado nunca é menos restritivo que o filtro. Os grupos são sempre os
+// do filtro, pois identificam quem busca, assim como IncludeDeleted.
+func (f SearchFilter) Merge(other SearchFilter) SearchFilter {
+	if f.Category == "" {
+		f.Category = other.Category
+	}
+	if len(f.Tags) == 0 {
+		f.Tags, f.TagMode = other.Tags, other.TagMode
+	}
+	if len(other.Metadata) > 0 {
+		metadata := make(map[string]string, len(f.Metadata)+len(other.Metadata))
+		maps.Copy(metadata, other.Metadata)
+		maps.Copy(metadata, f.Metadata)
+		f.Metadata = metadata
+	}
+	if len(other.Conditions) > 0 {
+		f.Conditions = append(slices.Clip(f.Conditions), other.Conditions...)
+	}
+	return f
+}
+
+// MatchAllTags indica se o documento precisa ter todas as tags do filtro
+func (f SearchFilter) MatchAllTags() bool {
+	return f.TagMode == TagMatchAll
+}
+
+// Validate verifica o modo de combinação das tags, as chaves de metadados,
+// que não podem ser vazias nem conter "." ou "$", e as condições
+func (f SearchFilter) Validate() error {
+	switch f.TagMode {
+	case "", TagMatchAny, TagMatchAll:
+	default:
+		return &ValidationError{Field: "tag_mode", Message: "deve ser any ou all"}
+	}
+	for key := range f.Metadata {
+		if key == "" || strings.ContainsAny(key, ".$") {
+			return &ValidationError{Field: "metadata", Message: fmt.Sprintf("chave inválida: %q", key)}
+		}
+	}
+	for _, condition := range f.Conditions {
+		if err := condition.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DocumentRepository define as operações de persistência de documentos.
+// Todas as operações se restringem ao tenant do contexto (TenantFromContext):
+// documentos de outros tenants não são encontrados nem alterados. As buscas
+// respeitam também a ACL dos documentos (SearchFilter.Groups), assim como a
+// listagem com DocumentFilter.RestrictACL; as demais operações, usadas na
+// manutenção da base, não.
+// Documentos excluídos logicamente ficam fora das buscas, de FindByParentID
+// e da listagem (a menos que o filtro os inclua); FindByID os retorna com
+// DeletedAt preenchido. Documentos expirados (ExpiresAt) ficam fora apenas
+// das buscas.
+type DocumentRepository interface {
+	// SearchDocuments busca documentos relevantes para a query que atendem ao filtro
+	SearchDocuments(ctx context.Context, query string, filter SearchFilter) ([]Document, error)
+	// SearchByVector busca os documentos mais similares ao vetor informado
+	// que atendem ao filtro
+	SearchByVector(ctx context.Context, vector []float32, filter SearchFilter) ([]Document, error)
+	// SearchWithFacets faz a busca textual de SearchDocuments e conta os
+	// documentos encontrados por categoria, tag e mês de criação. Retorna
+	// ErrFacetsUnsupported nos bancos sem agregações.
+	SearchWithFacets(ctx context.Context, query string, filter SearchFilter) (*FacetedSearch, error)
+	// FindByID busca um documento pelo ID, retornando ErrDocumentNotFound se não existir
+	FindByID(ctx context.Context, id string) (*Document, error)
+	// FindByParentID busca os chunks de um documento lógico, em ordem
+	FindByParentID(ctx context.Context, parentID string) ([]Document, error)
+	// FindByContentHash busca um documento (ou chunk) com o ContentHash
+	// informado, inclusive entre os excluídos, retornando ErrDocumentNotFound
+	// se não existir
+	FindByContentHash(ctx context.Context, hash string) (*Document, error)
+	// List retorna até limit documentos que atendem ao filtro, a partir do
+	// cursor (vazio na primeira página), e o cursor da próxima página, vazio
+	// quando não há mais documentos. Os embeddings não são carregados.
+	List(ctx context.Context, filter DocumentFilter, cursor string, limit int) ([]Document, string, error)
+	// InsertDocument insere um novo documento e preenche o seu ID. Retorna
+	// ErrDuplicateDocument se o tenant já tiver um documento com o mesmo
+	// ContentHash (e o mesmo ChunkIndex), nos bancos com índice único.
+	InsertDocument(ctx context.Context, doc *Document) error
+	// InsertMany insere vários documentos de uma vez e preenche o ID de cada
+	// um. Em caso de falha, apenas os documentos gravados têm o ID preenchido.
+	InsertMany(ctx context.Context, docs []*Document) error
+	// Update substitui título, conteúdo, link, categoria, metadados, tags,
+	// ACL, validade e embedding do documento com o ID de doc, guardando o estado
+	// anterior no histórico, e preenche a nova versão. Retorna ErrDocumentNotFound se o
+	// documento não existir e ErrVersionConflict se ele for alterado ao mesmo tempo.
+	Update(ctx context.Context, doc *Document) error
+	// GetVersionHistory retorna as versões anteriores do documento, da mais
+	// antiga para a mais recente
+	GetVersionHistory(ctx context.Context, id string) ([]DocumentVersion, error)
+	// SetEmbedding substitui apenas o embedding do documento (ou chunk) com o
+	// ID informado, sem criar uma versão, retornando ErrDocumentNotFound se
+	// não existir
+	SetEmbedding(ctx context.Context, id string, embedding []float32) error
+	// SoftDelete exclui logicamente o documento com o ID informado, ou os
+	// chunks cujo ParentID é o ID, retornando ErrDocumentNotFound se não existir
+	SoftDelete(ctx context.Context, id string) error
+	// Restore desfaz a exclusão lógica feita por SoftDelete
+	Restore(ctx context.Context, id string) error
+	// HealthCheck verifica se a base está acessível
+	HealthCheck(ctx context.Context) error
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "context"
+
+// EmbeddingClient define a interface de geração de embeddings (vetores
+// que representam o significado de um texto)
+type EmbeddingClient interface {
+	// Embed gera um embedding para cada texto, na mesma ordem da entrada.
+	// Listas longas podem ser enviadas de uma vez: a divisão em lotes que
+	// respeitem os limites do provedor é responsabilidade do cliente.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Dimensions retorna a dimensão dos embeddings gerados, ou 0 quando ela
+	// não é conhecida antes do primeiro embedding
+	Dimensions() int
+}
+
+// queryEmbeddingKey marca no contexto os embeddings de consultas
+type queryEmbeddingKey struct{}
+
+// WithQueryEmbedding indica que os textos enviados ao EmbeddingClient com o
+// contexto são consultas, e não documentos. Provedores com modelos
+// assimétricos (como Cohere e Voyage) geram embeddings diferentes para cada
+// caso; os demais ignoram a indicação.
+func WithQueryEmbedding(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryEmbeddingKey{}, true)
+}
+
+// IsQueryEmbedding informa se o contexto foi marcado com WithQueryEmbedding
+func IsQueryEmbedding(ctx context.Context) bool {
+	query, _ := ctx.Value(queryEmbeddingKey{}).(bool)
+	return query
+}
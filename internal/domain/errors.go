@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrDocumentNotFound indica que o documento solicitado não existe
+var ErrDocumentNotFound = errors.New("documento não encontrado")
+
+// ErrLLMUnavailable indica que o LLM está fora do ar e as chamadas estão
+// sendo recusadas sem tentativa, até que ele volte a responder
+var ErrLLMUnavailable = errors.New("LLM indisponível no momento")
+
+// ErrLLMQuotaExceeded indica que a cota contratada no provedor de LLM acabou
+var ErrLLMQuotaExceeded = errors.New("cota do provedor de LLM esgotada")
+
+// ErrPromptTooLarge indica que o prompt não cabe no orçamento de contexto
+var ErrPromptTooLarge = errors.New("prompt excede o orçamento de contexto")
+
+// ErrStructuredAnswer indica que o LLM não produziu uma resposta válida
+// para o schema pedido em RAGRequest.ResponseSchema
+var ErrStructuredAnswer = errors.New("resposta não corresponde ao schema pedido")
+
+// ErrInvalidCursor indica que o cursor de paginação não foi gerado pela listagem
+var ErrInvalidCursor = errors.New("cursor de paginação inválido")
+
+// ErrEmbeddingsDisabled indica que a operação precisa de um cliente de
+// embeddings, que não foi configurado
+var ErrEmbeddingsDisabled = errors.New("nenhum cliente de embeddings configurado")
+
+// ErrFacetsUnsupported indica que o banco configurado não calcula facets
+var ErrFacetsUnsupported = errors.New("o banco configurado não oferece busca com facets")
+
+// ValidationError indica que um campo da entrada é inválido
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error implementa a interface error
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("campo '%s' inválido: %s", e.Field, e.Message)
+}
+
+// ValidationErrors reúne todos os campos inválidos de uma entrada, para que
+// o cliente corrija todos de uma vez
+type ValidationErrors []*ValidationError
+
+// Error implementa a interface error
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap permite identificar cada campo com errors.As(err, *ValidationError)
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// PromptTooLargeError indica que o prompt a ser enviado ao LLM excede o
+// orçamento de contexto configurado. A pergunta falha antes da chamada, em
+// vez de ser recusada pelo provedor.
+type PromptTooLargeError struct {
+	Tokens int // Tokens do prompt
+	Limit  int // Orçamento de contexto
+}
+
+// Error implementa a interface error
+func (e *PromptTooLargeError) Error() string {
+	return fmt.Sprintf("prompt com %d tokens excede o limite de %d tokens", e.Tokens, e.Limit)
+}
+
+// Unwrap permite identificar o erro com errors.Is(err, ErrPromptTooLarge)
+func (e *PromptTooLargeError) Unwrap() error {
+	return ErrPromptTooLarge
+}
+
+// TemporaryError marca uma falha transitória (sobrecarga, limite de
+// requisições do provedor, queda de conexão), que pode ter sucesso se a
+// operação for repetida
+type TemporaryError struct {
+	Err error
+	// RetryAfter é o tempo de espera indicado pelo provedor, quando informado
+	RetryAfter time.Duration
+}
+
+// Error implementa a interface error
+func (e *TemporaryError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap retorna o erro original
+func (e *TemporaryError) Unwrap() error {
+	return e.Err
+}
+
+// Temporary indica que a falha é transitória
+func (e *TemporaryError) Temporary() bool {
+	return true
+}
+
+// mongoTransientLabels são os rótulos com que o driver do MongoDB marca
+// falhas que podem ser repetidas
+var mongoTransientLabels = []string{"NetworkError", "RetryableWriteError", "TransientTransactionError"}
+
+// IsTemporaryError indica se a operação que falhou com err pode ser repetida.
+// Além de *TemporaryError, reconhece erros de rede e os erros transitórios
+// marcados pelos drivers dos bancos. Cancelamentos e prazos esgotados do
+// contexto nunca são temporários: repetir não ajudaria quem chamou.
+func IsTemporaryError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) && temporary.Temporary() {
+		return true
+	}
+	// Erros do PostgreSQL (pgconn) que garantem que nada foi enviado ao servidor
+	var safeToRetry interface{ SafeToRetry() bool }
+	if errors.As(err, &safeToRetry) && safeToRetry.SafeToRetry() {
+		return true
+	}
+	var labeled interface{ HasErrorLabel(string) bool }
+	if errors.As(err, &labeled) {
+		for _, label := range mongoTransientLabels {
+			if labeled.HasErrorLabel(label) {
+				return true
+			}
+		}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// RetryAfter retorna o tempo de espera indicado pelo provedor em um
+// *TemporaryError, ou 0
+func RetryAfter(err error) time.Duration {
+	var temporary *TemporaryError
+	if errors.As(err, &temporary) {
+		return temporary.RetryAfter
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// QualityScores são as notas de qualidade de uma resposta (0 a 1), dadas
+// por um LLM no estilo do RAGAS
+type QualityScores struct {
+	// Faithfulness é a fração das afirmações da resposta sustentadas pelas
+	// fontes; nil quando a resposta não usou fontes
+	Faithfulness *float64 `bson:"faithfulness,omitempty" json:"faithfulness,omitempty"`
+	// ContextRelevance é a fração das fontes relevantes para a pergunta;
+	// nil quando a resposta não usou fontes
+	ContextRelevance *float64 `bson:"context_relevance,omitempty" json:"context_relevance,omitempty"`
+	// AnswerRelevance indica o quanto a resposta atende à pergunta
+	AnswerRelevance float64 `bson:"answer_relevance" json:"answer_relevance"`
+}
+
+// QualitySample é uma resposta avaliada, guardada para acompanhar a
+// evolução da qualidade
+type QualitySample struct {
+	TenantID      string        `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"` // Preenchido pelo repositório a partir do contexto
+	Query         string        `bson:"query" json:"query"`
+	Answer        string        `bson:"answer" json:"answer"`
+	Model         string        `bson:"model,omitempty" json:"model,omitempty"`
+	PromptVersion string        `bson:"prompt_version,omitempty" json:"prompt_version,omitempty"`
+	Scores        QualityScores `bson:"scores" json:"scores"`
+	CreatedAt     time.Time     `bson:"created_at" json:"created_at"`
+}
+
+// QualityTrend são as médias das notas das respostas avaliadas em um dia
+type QualityTrend struct {
+	Day              time.Time `json:"day"`
+	Samples          int       `json:"samples"`
+	Faithfulness     *float64  `json:"faithfulness,omitempty"`      // Média das respostas com fontes
+	ContextRelevance *float64  `json:"context_relevance,omitempty"` // Média das respostas com fontes
+	AnswerRelevance  float64   `json:"answer_relevance"`
+}
+
+// QualityRepository guarda as respostas avaliadas, separadas por tenant (o
+// do contexto)
+type QualityRepository interface {
+	// Record guarda a avaliação de uma resposta
+	Record(ctx context.Context, sample QualitySample) error
+	// Trend retorna as médias diárias (em UTC) desde a data informada, do
+	// dia mais antigo para o mais recente
+	Trend(ctx context.Context, since time.Time) ([]QualityTrend, error)
+}
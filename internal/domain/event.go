@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Tipos de evento publicados para sistemas externos
+const (
+	EventDocumentIngested = "document.ingested" // Documento gravado na base
+	EventDocumentFailed   = "document.failed"   // Documento que não pôde ser gravado
+	EventQueryAnswered    = "query.answered"    // Pergunta respondida
+)
+
+// Event é uma notificação sobre uma mudança na base ou uma pergunta
+// respondida, entregue aos sistemas externos interessados
+type Event struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	TenantID string    `json:"tenant_id,omitempty"`
+	Data     any       `json:"data"` // DocumentEvent ou QueryEvent, conforme o tipo
+}
+
+// DocumentEvent descreve o documento de document.ingested e document.failed
+type DocumentEvent struct {
+	ID       string `json:"id,omitempty"` // Vazio quando nenhum chunk foi gravado
+	Title    string `json:"title"`
+	Link     string `json:"link,omitempty"`
+	Category string `json:"category,omitempty"`
+	Chunks   int    `json:"chunks,omitempty"` // Chunks gravados
+	Error    string `json:"error,omitempty"`  // Motivo da falha, em document.failed
+}
+
+// QueryEvent descreve a pergunta respondida de query.answered
+type QueryEvent struct {
+	SessionID  string     `json:"session_id,omitempty"`
+	UserID     string     `json:"user_id,omitempty"`
+	Query      string     `json:"query"`
+	Answer     string     `json:"answer"`
+	Sources    []string   `json:"sources,omitempty"` // IDs dos documentos usados como contexto
+	UsedSearch bool       `json:"used_search"`
+	Cached     bool       `json:"cached"`
+	Usage      TokenUsage `json:"usage"`
+	CostUSD    float64    `json:"cost_usd"`
+}
+
+// EventPublisher entrega eventos a sistemas externos. Publish não bloqueia
+// nem falha: a entrega acontece em segundo plano e as falhas são apenas
+// registradas, para não atrasar a ingestão e as respostas.
+type EventPublisher interface {
+	Publish(ctx context.Context, eventType string, data any)
+}
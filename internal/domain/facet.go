@@ -0,0 +1,29 @@
+package domain
+
+// FacetLimit é a quantidade máxima de valores retornados em cada facet
+const FacetLimit = 20
+
+// FacetCount é a quantidade de documentos com um valor de facet
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facets são as contagens dos documentos encontrados por valor de cada
+// campo, usadas para exibir os filtros junto aos resultados. Cada documento
+// lógico conta uma vez, mesmo dividido em chunks; os valores vêm do mais
+// para o menos frequente, até FacetLimit.
+type Facets struct {
+	Categories []FacetCount `json:"categories"`
+	Tags       []FacetCount `json:"tags"`
+	// Months agrupa os documentos pelo mês de criação (no formato 2006-01),
+	// do mais recente para o mais antigo
+	Months []FacetCount `json:"months"`
+}
+
+// FacetedSearch é o resultado de uma busca com facets
+type FacetedSearch struct {
+	Documents []Document // Documentos mais relevantes, como em SearchDocuments
+	Total     int        // Documentos lógicos que atendem à busca e ao filtro
+	Facets    Facets
+}
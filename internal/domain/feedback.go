@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Avaliações possíveis de uma resposta
+const (
+	FeedbackUp   = "up"   // Resposta útil
+	FeedbackDown = "down" // Resposta errada, incompleta ou inútil
+)
+
+// Feedback é a avaliação de uma resposta dada pelo usuário, guardada com a
+// pergunta, a resposta e as fontes para a triagem dos problemas de
+// qualidade e a montagem de datasets de avaliação
+type Feedback struct {
+	ID       string `bson:"_id,omitempty" json:"id"`
+	TenantID string `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"` // Preenchido pelo repositório a partir do contexto
+	Rating   string `bson:"rating" json:"rating"`                           // FeedbackUp ou FeedbackDown
+	Comment  string `bson:"comment,omitempty" json:"comment,omitempty"`
+	Query    string `bson:"query" json:"query"`
+	Answer   string `bson:"answer" json:"answer"`
+	// Sources são as fontes citadas na resposta avaliada
+	Sources   []FeedbackSource `bson:"sources,omitempty" json:"sources,omitempty"`
+	SessionID string           `bson:"session_id,omitempty" json:"session_id,omitempty"`
+	UserID    string           `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	RequestID string           `bson:"request_id,omitempty" json:"request_id,omitempty"` // Requisição da pergunta (X-Request-ID)
+	Model     string           `bson:"model,omitempty" json:"model,omitempty"`
+	// PromptVersion identifica as versões dos prompts usados na resposta
+	PromptVersion string    `bson:"prompt_version,omitempty" json:"prompt_version,omitempty"`
+	CreatedAt     time.Time `bson:"created_at" json:"created_at"`
+}
+
+// FeedbackSource identifica uma fonte da resposta avaliada
+type FeedbackSource struct {
+	ID    string `bson:"id" json:"id"`
+	Title string `bson:"title,omitempty" json:"title,omitempty"`
+	Link  string `bson:"link,omitempty" json:"link,omitempty"`
+}
+
+// FeedbackFilter restringe as avaliações listadas. Campos vazios não filtram.
+type FeedbackFilter struct {
+	Rating    string
+	UserID    string
+	SessionID string
+	Since     time.Time // Inclusive
+	Until     time.Time // Exclusive
+}
+
+// DocumentFeedback conta as avaliações das respostas que citaram um documento
+type DocumentFeedback struct {
+	Up   int `json:"up"`
+	Down int `json:"down"`
+}
+
+// FeedbackRepository guarda as avaliações das respostas, separadas por
+// tenant (o do contexto)
+type FeedbackRepository interface {
+	// Record guarda a avaliação e preenche o seu ID. Sem CreatedAt, usa o
+	// momento atual.
+	Record(ctx context.Context, feedback *Feedback) error
+	// List retorna até limit avaliações que atendem ao filtro, da mais
+	// recente para a mais antiga, a partir do cursor (vazio na primeira
+	// página), e o cursor da próxima página, vazio quando não há mais
+	List(ctx context.Context, filter FeedbackFilter, cursor string, limit int) ([]Feedback, string, error)
+	// DocumentStats conta, por ID de documento, as avaliações feitas desde a
+	// data informada das respostas que citaram o documento
+	DocumentStats(ctx context.Context, since time.Time) (map[string]DocumentFeedback, error)
+}
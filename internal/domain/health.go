@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Situações de uma dependência e do serviço nas verificações de prontidão
+const (
+	HealthOK          = "ok"
+	HealthUnavailable = "unavailable"
+)
+
+// DependencyHealth é a situação de uma dependência do serviço
+type DependencyHealth struct {
+	Name    string
+	Status  string // HealthOK ou HealthUnavailable
+	Latency time.Duration
+	Err     error // Motivo da falha, quando indisponível
+}
+
+// HealthReport é o resultado das verificações de prontidão
+type HealthReport struct {
+	Status       string // HealthOK quando todas as dependências estão disponíveis
+	Dependencies []DependencyHealth
+}
+
+// ReadinessChecker verifica se as dependências do serviço estão prontas
+// para atender as requisições
+type ReadinessChecker interface {
+	// CheckReadiness verifica todas as dependências, informando a situação de cada uma
+	CheckReadiness(ctx context.Context) HealthReport
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrJobNotFound indica que o job de ingestão não existe
+var ErrJobNotFound = errors.New("job não encontrado")
+
+// Situações de um job de ingestão
+const (
+	JobQueued    = "queued"    // Aguardando um worker
+	JobRunning   = "running"   // Em processamento
+	JobCompleted = "completed" // Todos os documentos processados, com ou sem erros
+	JobFailed    = "failed"    // Nenhum documento pôde ser gravado
+)
+
+// Job é uma ingestão em segundo plano: os documentos recebidos são gravados
+// um a um por um worker, que registra o progresso e os erros de cada um
+type Job struct {
+	ID        string     `bson:"_id,omitempty"`
+	TenantID  string     `bson:"tenant_id,omitempty"`
+	Actor     string     `bson:"actor,omitempty"`      // Quem enfileirou, registrado no log de auditoria
+	RequestID string     `bson:"request_id,omitempty"` // Requisição que enfileirou o job
+	Status    string     `bson:"status"`
+	Documents []Document `bson:"documents,omitempty"` // Documentos a gravar, descartados ao concluir
+
+	Total     int        `bson:"total"`
+	Processed int        `bson:"processed"` // Documentos já processados, na ordem recebida
+	Succeeded int        `bson:"succeeded"`
+	Skipped   int        `bson:"skipped"` // Conteúdo já indexado
+	Failed    int        `bson:"failed"`
+	Chunks    int        `bson:"chunks"` // Chunks gravados
+	Errors    []JobError `bson:"errors,omitempty"`
+
+	CreatedAt   time.Time `bson:"created_at"`
+	StartedAt   time.Time `bson:"started_at,omitempty"`
+	FinishedAt  time.Time `bson:"finished_at,omitempty"`
+	LockedUntil time.Time `bson:"locked_until,omitempty"` // Fim da reserva do worker que processa o job
+}
+
+// JobError é a falha na gravação de um documento do job
+type JobError struct {
+	Index int    `bson:"index"` // Posição do documento na lista recebida
+	Title string `bson:"title"`
+	Error string `bson:"error"`
+}
+
+// Finished informa se o job terminou
+func (j Job) Finished() bool {
+	return j.Status == JobCompleted || j.Status == JobFailed
+}
+
+// JobRepository guarda a fila de jobs de ingestão
+type JobRepository interface {
+	// Create enfileira o job no tenant do contexto, com o ator e a requisição
+	// do contexto, e preenche o seu ID
+	Create(ctx context.Context, job *Job) error
+	// FindByID busca o job pelo ID no tenant do contexto
+	FindByID(ctx context.Context, id string) (*Job, error)
+	// Claim reserva, por lease, o job mais antigo na fila, de qualquer
+	// tenant, ou um em processamento cuja reserva expirou (o worker parou no
+	// meio). Retorna nil quando não há jobs a processar.
+	Claim(ctx context.Context, lease time.Duration) (*Job, error)
+	// Update grava o progresso e a situação do job e renova a reserva por
+	// lease; ao terminar, os documentos são descartados
+	Update(ctx context.Context, job *Job, lease time.Duration) error
+}
@@ -0,0 +1,120 @@
+package domain
+
+import "context"
+
+// Papéis possíveis de uma mensagem na conversa
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// Message representa uma mensagem trocada com o LLM
+type Message struct {
+	Role       string     `bson:"role" json:"role"`
+	Content    string     `bson:"content" json:"content"`
+	Name       string     `bson:"name,omitempty" json:"name,omitempty"`
+	ToolCallID string     `bson:"tool_call_id,omitempty" json:"tool_call_id,omitempty"`
+	ToolCalls  []ToolCall `bson:"tool_calls,omitempty" json:"tool_calls,omitempty"`
+
+	// Usage é o consumo da chamada que gerou a mensagem; não é persistido
+	Usage TokenUsage `bson:"-" json:"-"`
+}
+
+// TokenUsage representa os tokens consumidos em chamadas ao LLM
+type TokenUsage struct {
+	Model            string `json:"model,omitempty"` // Modelo que atendeu a chamada
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+}
+
+// Total retorna a soma dos tokens de entrada e de saída
+func (u TokenUsage) Total() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// ToolCall representa uma chamada de ferramenta solicitada pelo LLM
+type ToolCall struct {
+	ID        string `bson:"id" json:"id"`
+	Name      string `bson:"name" json:"name"`
+	Arguments string `bson:"arguments" json:"arguments"` // Argumentos em JSON
+}
+
+// Tool descreve uma ferramenta que o agente pode usar
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"` // JSON Schema dos argumentos
+}
+
+// ToolResult é o resultado da execução de uma ferramenta
+type ToolResult struct {
+	Content string     // Conteúdo devolvido ao LLM
+	Sources []Document // Documentos usados, incluídos nas fontes da resposta
+}
+
+// ToolHandler executa uma ferramenta a partir dos argumentos em JSON
+type ToolHandler func(ctx context.Context, arguments string) (*ToolResult, error)
+
+// LLMChunk representa um pedaço da resposta gerada em streaming
+type LLMChunk struct {
+	Content   string     // Trecho de texto gerado
+	ToolCalls []ToolCall // Chamadas de ferramentas completas, enviadas no último pedaço
+	Done      bool       // Indica o fim da resposta
+	Usage     TokenUsage // Consumo da chamada, enviado no último pedaço
+	Err       error      // Erro ocorrido durante o streaming
+}
+
+// LLMClient define a interface de comunicação com o modelo de linguagem
+type LLMClient interface {
+	// GenerateResponse envia as mensagens ao modelo e retorna a mensagem gerada
+	GenerateResponse(ctx context.Context, messages []Message, tools []Tool) (*Message, error)
+	// GenerateResponseStream envia as mensagens ao modelo e retorna os pedaços
+	// da resposta à medida que são gerados. O canal é fechado ao final.
+	GenerateResponseStream(ctx context.Context, messages []Message, tools []Tool) (<-chan LLMChunk, error)
+}
+
+// responseSchemaKey é a chave do schema de resposta no contexto
+type responseSchemaKey struct{}
+
+// WithResponseSchema pede, nas chamadas ao LLM sem streaming feitas com o
+// contexto, uma resposta em JSON que siga o JSON Schema informado. Cada
+// provedor usa o recurso que tiver: response_format na OpenAI, uma
+// ferramenta de uso obrigatório na Anthropic e format no Ollama.
+func WithResponseSchema(ctx context.Context, schema map[string]any) context.Context {
+	return context.WithValue(ctx, responseSchemaKey{}, schema)
+}
+
+// ResponseSchemaFromContext retorna o schema de resposta guardado no
+// contexto, ou nil
+func ResponseSchemaFromContext(ctx context.Context) map[string]any {
+	schema, _ := ctx.Value(responseSchemaKey{}).(map[string]any)
+	return schema
+}
+
+// Generation são os parâmetros de geração das chamadas ao LLM. Campos
+// zerados ou nulos usam o padrão do provedor.
+type Generation struct {
+	Model       string   // Modelo usado no lugar do configurado; vazio usa o configurado
+	MaxTokens   int      // Limite de tokens gerados; provedores com um limite configurado usam o menor dos dois
+	Temperature *float64 // Aleatoriedade da amostragem, de 0 (determinística) a 2
+	TopP        *float64 // Fração da massa de probabilidade considerada na amostragem (nucleus sampling)
+	Seed        *int     // Semente da amostragem, para repetir respostas; ignorada por provedores sem suporte
+}
+
+// generationKey é a chave dos parâmetros de geração no contexto
+type generationKey struct{}
+
+// WithGeneration aplica os parâmetros de geração às chamadas ao LLM feitas
+// com o contexto
+func WithGeneration(ctx context.Context, gen Generation) context.Context {
+	return context.WithValue(ctx, generationKey{}, gen)
+}
+
+// GenerationFromContext retorna os parâmetros de geração guardados no
+// contexto, zerados quando não há nenhum
+func GenerationFromContext(ctx context.Context) Generation {
+	gen, _ := ctx.Value(generationKey{}).(Generation)
+	return gen
+}
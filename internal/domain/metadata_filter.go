@@ -0,0 +1,308 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MetadataOp é a comparação feita por uma condição sobre os metadados
+type MetadataOp string
+
+const (
+	MetadataEquals       MetadataOp = "eq"            // O valor é igual ao informado
+	MetadataIn           MetadataOp = "in"            // O valor é um dos informados
+	MetadataRange        MetadataOp = "range"         // O valor, numérico, está entre os limites
+	MetadataVersionRange MetadataOp = "version_range" // O valor, uma versão como 1.20.3, está entre os limites
+	MetadataExists       MetadataOp = "exists"        // O documento tem a chave
+)
+
+// MetadataCondition é uma condição sobre um valor dos metadados do documento.
+// As faixas comparam os valores como números, e as de versões comparam cada
+// parte da versão como um número, então 1.10 vem depois de 1.9; documentos
+// cujo valor não é numérico (ou não é uma versão) não atendem à condição.
+type MetadataCondition struct {
+	Key    string     `json:"key"`
+	Op     MetadataOp `json:"op"`
+	Values []string   `json:"values,omitempty"` // Um valor em eq; um ou mais em in
+
+	// Limites da faixa; nulos não restringem
+	Min          *float64 `json:"min,omitempty"`
+	Max          *float64 `json:"max,omitempty"`
+	MinExclusive bool     `json:"min_exclusive,omitempty"` // > em vez de >=
+	MaxExclusive bool     `json:"max_exclusive,omitempty"` // < em vez de <=
+
+	// Limites da faixa de versões, como "1.20"; vazios não restringem.
+	// Usam também MinExclusive e MaxExclusive.
+	MinVersion string `json:"min_version,omitempty"`
+	MaxVersion string `json:"max_version,omitempty"`
+}
+
+// versionPattern reconhece as versões comparadas por MetadataVersionRange:
+// números separados por pontos
+var versionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)*$`)
+
+// VersionParts separa a versão nas suas partes numéricas, comparadas em
+// ordem por MetadataVersionRange. O segundo valor é falso quando o texto não
+// é uma versão.
+func VersionParts(version string) ([]int64, bool) {
+	if !versionPattern.MatchString(version) {
+		return nil, false
+	}
+	var parts []int64
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
+
+// Validate verifica a chave, que segue as regras de SearchFilter.Metadata, e
+// os valores exigidos pela comparação
+func (c MetadataCondition) Validate() error {
+	if c.Key == "" || strings.ContainsAny(c.Key, ".$") {
+		return &ValidationError{Field: "conditions", Message: fmt.Sprintf("chave inválida: %q", c.Key)}
+	}
+	switch c.Op {
+	case MetadataEquals:
+		if len(c.Values) != 1 {
+			return &ValidationError{Field: "conditions", Message: fmt.Sprintf("%s: eq exige um valor", c.Key)}
+		}
+	case MetadataIn:
+		if len(c.Values) == 0 {
+			return &ValidationError{Field: "conditions", Message: fmt.Sprintf("%s: in exige ao menos um valor", c.Key)}
+		}
+	case MetadataRange:
+		if c.Min == nil && c.Max == nil {
+			return &ValidationError{Field: "conditions", Message: fmt.Sprintf("%s: range exige um limite", c.Key)}
+		}
+	case MetadataVersionRange:
+		if c.MinVersion == "" && c.MaxVersion == "" {
+			return &ValidationError{Field: "conditions", Message: fmt.Sprintf("%s: version_range exige um limite", c.Key)}
+		}
+		for _, version := range []string{c.MinVersion, c.MaxVersion} {
+			if _, ok := VersionParts(version); version != "" && !ok {
+				return &ValidationError{Field: "conditions", Message: fmt.Sprintf("%s: versão inválida %q", c.Key, version)}
+			}
+		}
+	case MetadataExists:
+	default:
+		return &ValidationError{Field: "conditions", Message: fmt.Sprintf("%s: comparação desconhecida %q", c.Key, c.Op)}
+	}
+	return nil
+}
+
+// filterClause reconhece uma cláusula da expressão de filtro: a chave, a
+// comparação e o restante, que é o valor
+var filterClause = regexp.MustCompile(`(?i)^\s*([^\s=<>!]+)\s*(>=|<=|=|>|<|\s+in\s+|\s+exists\s*$)\s*(.*?)\s*$`)
+
+// ParseSearchFilter interpreta uma expressão de filtro com cláusulas unidas
+// por AND, como `category=performance AND version>=v"1.20" AND status IN
+// (draft, review) AND owner EXISTS`. As cláusulas comparam os metadados do
+// documento, exceto as de category, que restringe a categoria (apenas com
+// =), e as de tags (= ou IN), que restringem as tags. Os valores podem estar
+// entre aspas simples ou duplas, que protegem AND, vírgulas e espaços.
+//
+// As faixas comparam números, como em price>=10.50, exceto quando o limite é
+// um literal de versão, v"1.20": nesse caso, os valores são comparados como
+// versões, parte a parte, e version>=v"1.20" inclui 1.100 mas não 1.9. O
+// tipo da comparação nunca é deduzido do formato do número.
+func ParseSearchFilter(expr string) (SearchFilter, error) {
+	var filter SearchFilter
+	if strings.TrimSpace(expr) == "" {
+		return filter, nil
+	}
+
+	clauses, err := splitUnquoted(strings.TrimSpace(expr), andSeparator)
+	if err != nil {
+		return SearchFilter{}, err
+	}
+	tagClauses, tagsIn := 0, false
+	for _, clause := range clauses {
+		match := filterClause.FindStringSubmatch(clause)
+		if match == nil {
+			return SearchFilter{}, &ValidationError{Field: "filter", Message: fmt.Sprintf("cláusula inválida: %q", clause)}
+		}
+		key, op, value := match[1], strings.ToLower(strings.TrimSpace(match[2])), match[3]
+
+		var condition MetadataCondition
+		switch {
+		case strings.EqualFold(key, "category"):
+			if op != "=" {
+				return SearchFilter{}, &ValidationError{Field: "filter", Message: fmt.Sprintf("category aceita apenas =: %q", clause)}
+			}
+			filter.Category = unquote(value)
+			continue
+		case strings.EqualFold(key, "tags"):
+			// Cada tags=x exige a tag; uma lista em IN exige uma das tags
+			var tags []string
+			switch op {
+			case "=":
+				tags = []string{unquote(value)}
+			case "in":
+				if tags, err = parseList(value, clause); err != nil {
+					return SearchFilter{}, err
+				}
+				tagsIn = true
+			default:
+				return SearchFilter{}, &ValidationError{Field: "filter", Message: fmt.Sprintf("tags aceita apenas = e IN: %q", clause)}
+			}
+			if tagClauses++; tagsIn && tagClauses > 1 {
+				return SearchFilter{}, &ValidationError{Field: "filter", Message: "tags IN não pode ser combinada com outras cláusulas de tags"}
+			}
+			filter.Tags, filter.TagMode = append(filter.Tags, tags...), TagMatchAll
+			if tagsIn {
+				filter.TagMode = TagMatchAny
+			}
+			continue
+		}
+
+		switch op {
+		case "=":
+			condition = MetadataCondition{Key: key, Op: MetadataEquals, Values: []string{unquote(value)}}
+		case "in":
+			values, err := parseList(value, clause)
+			if err != nil {
+				return SearchFilter{}, err
+			}
+			condition = MetadataCondition{Key: key, Op: MetadataIn, Values: values}
+		case "exists":
+			condition = MetadataCondition{Key: key, Op: MetadataExists}
+		default:
+			if condition, err = parseRange(key, op, value, clause); err != nil {
+				return SearchFilter{}, err
+			}
+		}
+		if err := condition.Validate(); err != nil {
+			return SearchFilter{}, err
+		}
+		filter.Conditions = append(filter.Conditions, condition)
+	}
+	return filter, nil
+}
+
+// parseRange interpreta uma cláusula com >, >=, < ou <=, como faixa de
+// números ou, quando o limite é um literal de versão (v"1.20"), de versões
+func parseRange(key, op, value, clause string) (MetadataCondition, error) {
+	if literal, ok := strings.CutPrefix(value, "v"); ok && unquote(literal) != literal {
+		bound := unquote(literal)
+		if _, ok := VersionParts(bound); !ok {
+			return MetadataCondition{}, &ValidationError{Field: "filter", Message: fmt.Sprintf("versão inválida em %q", clause)}
+		}
+		condition := MetadataCondition{Key: key, Op: MetadataVersionRange}
+		switch op {
+		case ">", ">=":
+			condition.MinVersion, condition.MinExclusive = bound, op == ">"
+		case "<", "<=":
+			condition.MaxVersion, condition.MaxExclusive = bound, op == "<"
+		}
+		return condition, nil
+	}
+
+	bound := unquote(value)
+	n, err := strconv.ParseFloat(bound, 64)
+	if err != nil || bound != value {
+		return MetadataCondition{}, &ValidationError{Field: "filter", Message: fmt.Sprintf("valor não numérico em %q", clause)}
+	}
+	condition := MetadataCondition{Key: key, Op: MetadataRange}
+	switch op {
+	case ">", ">=":
+		condition.Min, condition.MinExclusive = &n, op == ">"
+	case "<", "<=":
+		condition.Max, condition.MaxExclusive = &n, op == "<"
+	}
+	return condition, nil
+}
+
+// parseList interpreta a lista entre parênteses de uma cláusula IN, separada
+// por vírgulas fora das aspas
+func parseList(value, clause string) ([]string, error) {
+	list, ok := strings.CutPrefix(value, "(")
+	if list, ok = strings.CutSuffix(list, ")"); !ok {
+		return nil, &ValidationError{Field: "filter", Message: fmt.Sprintf("lista inválida em %q", clause)}
+	}
+	items, err := splitUnquoted(list, commaSeparator)
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, item := range items {
+		if item = unquote(strings.TrimSpace(item)); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values, nil
+}
+
+// andSeparator reconhece um AND entre espaços na posição i, retornando o
+// seu tamanho com os espaços, ou zero
+func andSeparator(s string, i int) int {
+	if !isSpace(s[i]) {
+		return 0
+	}
+	j := i
+	for j < len(s) && isSpace(s[j]) {
+		j++
+	}
+	if j+3 >= len(s) || !strings.EqualFold(s[j:j+3], "and") || !isSpace(s[j+3]) {
+		return 0
+	}
+	j += 3
+	for j < len(s) && isSpace(s[j]) {
+		j++
+	}
+	return j - i
+}
+
+// commaSeparator reconhece uma vírgula na posição i
+func commaSeparator(s string, i int) int {
+	if s[i] == ',' {
+		return 1
+	}
+	return 0
+}
+
+// isSpace indica se o byte é um espaço em branco ASCII
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// splitUnquoted separa o texto nos separadores reconhecidos por sep que
+// estão fora de aspas simples ou duplas
+func splitUnquoted(s string, sep func(s string, i int) int) ([]string, error) {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		default:
+			if n := sep(s, i); n > 0 {
+				parts = append(parts, s[start:i])
+				start = i + n
+				i += n - 1
+			}
+		}
+	}
+	if quote != 0 {
+		return nil, &ValidationError{Field: "filter", Message: fmt.Sprintf("aspas não fechadas em %q", s)}
+	}
+	return append(parts, s[start:]), nil
+}
+
+// unquote remove as aspas simples ou duplas ao redor do valor
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func float(n float64) *float64 { return &n }
+
+func TestParseSearchFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want SearchFilter
+	}{
+		{name: "vazia", expr: "  "},
+		{
+			name: "categoria",
+			expr: "category=performance",
+			want: SearchFilter{Category: "performance"},
+		},
+		{
+			name: "faixa numérica com zero à direita",
+			expr: "price>=10.50",
+			want: SearchFilter{Conditions: []MetadataCondition{
+				{Key: "price", Op: MetadataRange, Min: float(10.5)},
+			}},
+		},
+		{
+			name: "faixa numérica que parece versão",
+			expr: "version>=1.9",
+			want: SearchFilter{Conditions: []MetadataCondition{
+				{Key: "version", Op: MetadataRange, Min: float(1.9)},
+			}},
+		},
+		{
+			name: "faixa numérica exclusiva",
+			expr: "price<100",
+			want: SearchFilter{Conditions: []MetadataCondition{
+				{Key: "price", Op: MetadataRange, Max: float(100), MaxExclusive: true},
+			}},
+		},
+		{
+			name: "literal de versão",
+			expr: `version>=v"1.20"`,
+			want: SearchFilter{Conditions: []MetadataCondition{
+				{Key: "version", Op: MetadataVersionRange, MinVersion: "1.20"},
+			}},
+		},
+		{
+			name: "literal de versão com aspas simples",
+			expr: "version<v'2.0.1'",
+			want: SearchFilter{Conditions: []MetadataCondition{
+				{Key: "version", Op: MetadataVersionRange, MaxVersion: "2.0.1", MaxExclusive: true},
+			}},
+		},
+		{
+			name: "igualdade, lista e existência",
+			expr: `status IN (draft, 'in review') and owner EXISTS AND team="a AND b"`,
+			want: SearchFilter{Conditions: []MetadataCondition{
+				{Key: "status", Op: MetadataIn, Values: []string{"draft", "in review"}},
+				{Key: "owner", Op: MetadataExists},
+				{Key: "team", Op: MetadataEquals, Values: []string{"a AND b"}},
+			}},
+		},
+		{
+			name: "todas as tags",
+			expr: "tags=go AND tags=performance",
+			want: SearchFilter{Tags: []string{"go", "performance"}, TagMode: TagMatchAll},
+		},
+		{
+			name: "uma das tags",
+			expr: "tags IN (go, rust)",
+			want: SearchFilter{Tags: []string{"go", "rust"}, TagMode: TagMatchAny},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSearchFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseSearchFilter(%q): %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSearchFilter(%q) = %+v, esperado %+v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSearchFilterErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{name: "cláusula sem comparação", expr: "category"},
+		{name: "categoria com faixa", expr: "category>a"},
+		{name: "número entre aspas", expr: `price>="10"`},
+		{name: "valor não numérico", expr: "price>=abc"},
+		{name: "literal de versão inválido", expr: `version>=v"1.x"`},
+		{name: "versão sem literal", expr: "version>=1.2.3"},
+		{name: "aspas não fechadas", expr: `team="a AND b`},
+		{name: "lista sem parênteses", expr: "status IN draft"},
+		{name: "tags com faixa", expr: "tags>go"},
+		{name: "tags IN com outras tags", expr: "tags IN (go) AND tags=rust"},
+		{name: "chave inválida", expr: "a.b=c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSearchFilter(tt.expr)
+			var validation *ValidationError
+			if !errors.As(err, &validation) {
+				t.Errorf("ParseSearchFilter(%q) = %v, esperado um ValidationError", tt.expr, err)
+			}
+		})
+	}
+}
+
+func TestVersionParts(t *testing.T) {
+	tests := []struct {
+		version string
+		want    []int64
+		ok      bool
+	}{
+		{version: "1.20", want: []int64{1, 20}, ok: true},
+		{version: "1.100.3", want: []int64{1, 100, 3}, ok: true},
+		{version: "2", want: []int64{2}, ok: true},
+		{version: "1.x"},
+		{version: "v1.2"},
+		{version: "1..2"},
+		{version: ""},
+	}
+	for _, tt := range tests {
+		got, ok := VersionParts(tt.version)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("VersionParts(%q) = %v, %v, esperado %v, %v", tt.version, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrContentBlocked indica que a pergunta ou a resposta foi bloqueada pela moderação
+var ErrContentBlocked = errors.New("conteúdo bloqueado pela moderação")
+
+// Etapas em que o conteúdo é moderado
+const (
+	ModerationQuery  = "query"  // Pergunta do usuário
+	ModerationAnswer = "answer" // Resposta gerada pelo agente
+)
+
+// ContentBlockedError indica que o conteúdo de uma etapa foi bloqueado
+type ContentBlockedError struct {
+	Stage      string   // ModerationQuery ou ModerationAnswer
+	Categories []string // Categorias sinalizadas (ex: "violence")
+}
+
+// Error implementa a interface error
+func (e *ContentBlockedError) Error() string {
+	stage := "pergunta"
+	if e.Stage == ModerationAnswer {
+		stage = "resposta"
+	}
+	return fmt.Sprintf("%s bloqueada pela moderação: %s", stage, strings.Join(e.Categories, ", "))
+}
+
+// Unwrap permite identificar o erro com errors.Is(err, ErrContentBlocked)
+func (e *ContentBlockedError) Unwrap() error {
+	return ErrContentBlocked
+}
+
+// ModerationResult é a classificação de um texto pela moderação
+type ModerationResult struct {
+	Stage      string   `json:"stage,omitempty"` // Etapa moderada, preenchida pelo serviço
+	Flagged    bool     `json:"flagged"`
+	Categories []string `json:"categories,omitempty"` // Categorias sinalizadas
+}
+
+// ModerationClient classifica textos quanto a conteúdo impróprio
+type ModerationClient interface {
+	Moderate(ctx context.Context, text string) (*ModerationResult, error)
+}
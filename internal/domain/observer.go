@@ -0,0 +1,27 @@
+package domain
+
+import "context"
+
+// QueryObserver acompanha as etapas de uma pergunta, para exibir o progresso
+// ou coletar telemetria própria sem alterar o fluxo do agente. Os métodos são
+// chamados de forma síncrona, às vezes de várias goroutines ao mesmo tempo
+// (as ferramentas rodam em paralelo): devem ser rápidos e seguros para uso
+// concorrente. Embuta NopObserver para implementar apenas parte deles.
+type QueryObserver interface {
+	// OnRetrievalStart é chamado antes de cada busca do agente na base
+	OnRetrievalStart(ctx context.Context, query string, filter SearchFilter)
+	// OnToolCall é chamado após cada ferramenta executada, com o erro da execução
+	OnToolCall(ctx context.Context, call ToolCall, err error)
+	// OnToken é chamado a cada trecho de texto gerado, apenas em streaming
+	OnToken(ctx context.Context, token string)
+	// OnComplete é chamado ao fim de cada pergunta, com a resposta ou o erro
+	OnComplete(ctx context.Context, req RAGRequest, resp *RAGResponse, err error)
+}
+
+// NopObserver implementa QueryObserver sem fazer nada
+type NopObserver struct{}
+
+func (NopObserver) OnRetrievalStart(context.Context, string, SearchFilter)      {}
+func (NopObserver) OnToolCall(context.Context, ToolCall, error)                 {}
+func (NopObserver) OnToken(context.Context, string)                             {}
+func (NopObserver) OnComplete(context.Context, RAGRequest, *RAGResponse, error) {}
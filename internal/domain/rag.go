@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// RAGRequest representa uma pergunta enviada ao agente
+type RAGRequest struct {
+	Query     string `json:"query"`
+	SessionID string `json:"session_id,omitempty"` // Sessão usada para manter o histórico
+	UserID    string `json:"user_id,omitempty"`    // Usuário que fez a pergunta, usado no controle de consumo
+	// TenantID restringe a pergunta aos documentos e conversas do tenant.
+	// Vazio mantém o tenant do contexto.
+	TenantID string `json:"tenant_id,omitempty"`
+	// Groups são os grupos (ou papéis) do usuário. As buscas só retornam os
+	// documentos sem ACL ou liberados a um desses grupos.
+	Groups []string `json:"groups,omitempty"`
+
+	// Category, Metadata e Tags restringem as buscas do agente aos
+	// documentos da categoria, com os metadados e com as tags informados
+	// (combinadas conforme TagMode)
+	Category string            `json:"category,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	TagMode  TagMode           `json:"tag_mode,omitempty"`
+	// Fuzzy habilita ou desabilita a tolerância a erros de digitação na
+	// busca textual; nulo mantém o padrão da configuração
+	Fuzzy *bool `json:"fuzzy,omitempty"`
+
+	// ResponseSchema pede, além do texto, a resposta como um objeto JSON
+	// validado contra este JSON Schema, retornado em RAGResponse.StructuredAnswer
+	ResponseSchema map[string]any `json:"response_schema,omitempty"`
+	// Language é o idioma da resposta, como "pt" ou "en-US", mesmo que os
+	// documentos estejam em outro idioma. Vazio ou "auto" usa o idioma
+	// detectado na pergunta.
+	Language string `json:"language,omitempty"`
+	// Style é o formato da resposta; vazio deixa o formato a critério do modelo
+	Style AnswerStyle `json:"style,omitempty"`
+	// MaxAnswerTokens limita os tokens gerados em cada chamada ao LLM;
+	// zero usa o limite do provedor
+	MaxAnswerTokens int `json:"max_answer_tokens,omitempty"`
+	// Temperature, TopP e Seed substituem os parâmetros de geração padrão
+	// da configuração; nulos mantêm o padrão
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	Seed        *int     `json:"seed,omitempty"`
+	// Model escolhe, entre os modelos permitidos na configuração, o modelo
+	// que gera a resposta; vazio usa o modelo configurado no provedor
+	Model string `json:"model,omitempty"`
+}
+
+// AnswerStyle define o formato da resposta pedido ao modelo
+type AnswerStyle string
+
+const (
+	AnswerConcise  AnswerStyle = "concise"  // Resposta curta e direta
+	AnswerDetailed AnswerStyle = "detailed" // Resposta completa, com explicações e exemplos
+	AnswerBullets  AnswerStyle = "bullet"   // Resposta em tópicos
+)
+
+// SearchFilter retorna o filtro aplicado às buscas feitas para a pergunta
+func (r RAGRequest) SearchFilter() SearchFilter {
+	return SearchFilter{
+		Category: r.Category,
+		Metadata: r.Metadata,
+		Tags:     NormalizeTags(r.Tags),
+		TagMode:  r.TagMode,
+		Groups:   NormalizeGroups(r.Groups),
+		Fuzzy:    r.Fuzzy != nil && *r.Fuzzy,
+	}
+}
+
+// RAGResponse representa a resposta final do agente
+type RAGResponse struct {
+	Answer        string             `json:"answer"`
+	Sources       []Document         `json:"sources"`     // Documentos usados como contexto
+	UsedSearch    bool               `json:"used_search"` // Indica se o agente consultou a base
+	SessionID     string             `json:"session_id,omitempty"`
+	Steps         []AgentStep        `json:"steps,omitempty"`          // Passos executados pelo agente, para diagnóstico
+	Cached        bool               `json:"cached,omitempty"`         // Indica se a resposta veio do cache
+	Model         string             `json:"model,omitempty"`          // Modelo que gerou a resposta final, inclusive quando outro provedor assumiu
+	PromptVersion string             `json:"prompt_version,omitempty"` // Versões dos prompts usados (ex: "answer@v1,system@v1")
+	Citations     []Citation         `json:"citations,omitempty"`      // Fontes citadas na resposta com marcadores como [1]
+	Groundedness  *Groundedness      `json:"groundedness,omitempty"`   // Verificação da resposta contra as fontes, quando habilitada
+	Moderation    []ModerationResult `json:"moderation,omitempty"`     // Pergunta e resposta sinalizadas pela moderação, sem bloqueio
+	Language      string             `json:"language,omitempty"`       // Idioma pedido ao modelo, informado ou detectado; vazio quando não detectado
+	// SourcesSummarized indica que as fontes excederam o orçamento de
+	// contexto e o agente recebeu resumos delas no lugar do conteúdo
+	SourcesSummarized bool `json:"sources_summarized,omitempty"`
+	// StructuredAnswer é a resposta no formato de RAGRequest.ResponseSchema, quando pedido
+	StructuredAnswer json.RawMessage `json:"structured_answer,omitempty"`
+	Usage            TokenUsage      `json:"usage"`    // Tokens consumidos nas chamadas ao LLM
+	CostUSD          float64         `json:"cost_usd"` // Custo estimado da pergunta, em dólares
+}
+
+// Citation liga um marcador de citação da resposta, como [1], a uma das fontes
+type Citation struct {
+	Marker   int    `json:"marker"` // Número citado na resposta
+	Source   int    `json:"source"` // Índice da fonte em RAGResponse.Sources
+	Title    string `json:"title"`
+	Link     string `json:"link"`
+	External bool   `json:"external,omitempty"` // A fonte veio da busca na web, fora da base
+}
+
+// Groundedness é o resultado da verificação de que as afirmações da resposta
+// são sustentadas pelas fontes
+type Groundedness struct {
+	Score       float64  `json:"score"`                 // Fração das afirmações sustentadas (0 a 1)
+	Unsupported []string `json:"unsupported,omitempty"` // Afirmações não sustentadas
+	Regenerated bool     `json:"regenerated,omitempty"` // Indica se a resposta foi gerada novamente por ter ficado abaixo do limite
+}
+
+// AgentStep registra uma iteração do agente: a chamada ao LLM e as
+// ferramentas executadas em seguida
+type AgentStep struct {
+	Iteration int        `json:"iteration"`
+	Content   string     `json:"content,omitempty"`    // Texto gerado pelo LLM nesta iteração
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"` // Ferramentas solicitadas pelo LLM
+	Tokens    int        `json:"tokens"`               // Tokens estimados consumidos na iteração
+}
+
+// RAGService define as operações do agente RAG
+type RAGService interface {
+	// ProcessQuery responde à pergunta, consultando a base quando necessário
+	ProcessQuery(ctx context.Context, req RAGRequest) (*RAGResponse, error)
+	// ProcessQueryStream responde à pergunta chamando onToken a cada trecho gerado
+	ProcessQueryStream(ctx context.Context, req RAGRequest, onToken func(token string)) (*RAGResponse, error)
+	// SearchDocuments busca documentos diretamente na base
+	SearchDocuments(ctx context.Context, query string, filter SearchFilter) ([]Document, error)
+	// SearchWithFacets busca documentos na base e conta os encontrados por
+	// categoria, tag e mês, para exibir os filtros junto aos resultados
+	SearchWithFacets(ctx context.Context, query string, filter SearchFilter) (*FacetedSearch, error)
+	// AddDocument valida e insere um novo documento na base
+	AddDocument(ctx context.Context, doc *Document) error
+	// GetDocument busca um documento pelo ID
+	GetDocument(ctx context.Context, id string) (*Document, error)
+	// ListDocuments retorna uma página de documentos e o cursor da próxima
+	ListDocuments(ctx context.Context, filter DocumentFilter, cursor string, limit int) ([]Document, string, error)
+	// UpdateDocument substitui o conteúdo de um documento, guardando a versão anterior
+	UpdateDocument(ctx context.Context, id string, doc *Document) error
+	// GetVersionHistory retorna todas as versões do documento, até a atual
+	GetVersionHistory(ctx context.Context, id string) ([]DocumentVersion, error)
+	// Rollback volta o documento ao conteúdo de uma versão anterior
+	Rollback(ctx context.Context, id string, version int) (*Document, error)
+	// DeleteDocument exclui logicamente um documento, que pode ser restaurado
+	DeleteDocument(ctx context.Context, id string) error
+	// RestoreDocument desfaz a exclusão lógica de um documento
+	RestoreDocument(ctx context.Context, id string) error
+	// ReembedDocument gera novamente o embedding de um documento (ou dos seus
+	// chunks) e retorna quantos foram atualizados
+	ReembedDocument(ctx context.Context, id string) (int, error)
+	// HealthCheck verifica se as dependências do serviço estão acessíveis
+	HealthCheck(ctx context.Context) error
+}
+
+// TimeoutResult é retornado quando o contexto da requisição é cancelado
+// no meio do processamento. Carrega o que já havia sido obtido até então.
+type TimeoutResult struct {
+	Sources       []Document // Documentos já encontrados
+	PartialAnswer string     // Resposta parcial já gerada, quando houver
+	Err           error      // Erro do contexto (Canceled ou DeadlineExceeded)
+}
+
+// Error implementa a interface error
+func (r *TimeoutResult) Error() string {
+	return fmt.Sprintf("processamento interrompido com %d fonte(s) encontrada(s): %v", len(r.Sources), r.Err)
+}
+
+// Unwrap permite usar errors.Is com context.Canceled e context.DeadlineExceeded
+func (r *TimeoutResult) Unwrap() error {
+	return r.Err
+}
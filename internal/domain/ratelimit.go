@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRateLimited indica que o cliente excedeu o limite de perguntas
+var ErrRateLimited = errors.New("limite de requisições excedido")
+
+// RateLimitError é o erro retornado quando o limite de um cliente é
+// excedido, com o tempo até a próxima pergunta ser aceita. Corresponde a
+// ErrRateLimited em errors.Is.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+// Error implementa a interface error
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v, tente novamente em %s", ErrRateLimited, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap permite identificar o erro com errors.Is(err, ErrRateLimited)
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// RateLimiter limita a quantidade de perguntas de cada cliente
+type RateLimiter interface {
+	// Allow consome uma requisição do cliente identificado pela chave, ou
+	// retorna um *RateLimitError quando o limite foi atingido
+	Allow(ctx context.Context, key string) error
+}
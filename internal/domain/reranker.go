@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// Reranker reordena os documentos recuperados pela relevância em relação à consulta
+type Reranker interface {
+	// Rerank retorna os documentos do mais para o menos relevante
+	Rerank(ctx context.Context, query string, docs []Document) ([]Document, error)
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrSessionNotFound indica que a sessão não existe ou expirou
+var ErrSessionNotFound = errors.New("sessão não encontrada")
+
+// Session é o estado de uma sessão mantido entre as perguntas, compartilhado
+// pelas instâncias da aplicação quando o armazenamento é externo
+type Session struct {
+	ID string `json:"id"`
+	// ConversationID é a conversa em uso pela sessão; vazio usa a conversa
+	// com o ID da sessão
+	ConversationID string `json:"conversation_id,omitempty"`
+	// UserID é o dono da sessão, o primeiro usuário identificado que
+	// perguntou nela; vazio em sessões anônimas
+	UserID string `json:"user_id,omitempty"`
+	// Preferences são as preferências de resposta informadas nas perguntas
+	// anteriores, aplicadas às seguintes que não as informam
+	Preferences SessionPreferences `json:"preferences"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// AccessibleTo indica se o usuário pode usar a sessão: as anônimas são de
+// todos, e as com dono, apenas dele
+func (s *Session) AccessibleTo(userID string) bool {
+	return s.UserID == "" || s.UserID == userID
+}
+
+// SessionPreferences são as preferências de resposta guardadas na sessão
+type SessionPreferences struct {
+	Language string      `json:"language,omitempty"`
+	Style    AnswerStyle `json:"style,omitempty"`
+	Model    string      `json:"model,omitempty"`
+}
+
+// SessionStore define o armazenamento do estado das sessões, restrito ao
+// tenant do contexto. As sessões e os contadores expiram depois de um
+// tempo sem uso definido pela implementação.
+type SessionStore interface {
+	// Get busca o estado da sessão, retornando ErrSessionNotFound se ela não
+	// existir ou tiver expirado
+	Get(ctx context.Context, id string) (*Session, error)
+	// Save cria ou substitui o estado da sessão
+	Save(ctx context.Context, session *Session) error
+	// Delete remove o estado e os contadores da sessão
+	Delete(ctx context.Context, id string) error
+	// Increment soma um ao contador da sessão e retorna o novo valor. O
+	// contador volta a zero depois de window desde o primeiro incremento.
+	Increment(ctx context.Context, id, counter string, window time.Duration) (int64, error)
+}
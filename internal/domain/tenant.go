@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+)
+
+// MaxTenantIDLength é o tamanho máximo do identificador de um tenant
+const MaxTenantIDLength = 64
+
+// tenantIDPattern restringe os caracteres do identificador, que é usado em
+// chaves e filtros das bases
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tenantKey é a chave do tenant no contexto
+type tenantKey struct{}
+
+// WithTenant guarda no contexto o tenant ao qual as operações se aplicam.
+// Os repositórios leem, gravam e buscam apenas os dados desse tenant.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext retorna o tenant guardado no contexto. Vazio indica o
+// tenant padrão, ao qual pertencem os dados gravados sem tenant.
+func TenantFromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantKey{}).(string)
+	return tenantID
+}
+
+// ValidateTenantID verifica se o identificador do tenant é aceito: até
+// MaxTenantIDLength letras, dígitos, "-" ou "_". Vazio indica o tenant padrão.
+func ValidateTenantID(tenantID string) error {
+	if tenantID == "" {
+		return nil
+	}
+	if len(tenantID) > MaxTenantIDLength || !tenantIDPattern.MatchString(tenantID) {
+		return &ValidationError{Field: "tenant_id", Message: "use até 64 letras, dígitos, - ou _"}
+	}
+	return nil
+}
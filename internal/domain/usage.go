@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UsageTotals acumula o consumo de uma sessão ou de um usuário
+type UsageTotals struct {
+	Requests         int       `bson:"requests" json:"requests"`
+	PromptTokens     int       `bson:"prompt_tokens" json:"prompt_tokens"`
+	CompletionTokens int       `bson:"completion_tokens" json:"completion_tokens"`
+	CostUSD          float64   `bson:"cost_usd" json:"cost_usd"`
+	UpdatedAt        time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// UsageRepository guarda o consumo de tokens e o custo agregados por sessão e
+// por usuário, separados por tenant (o do contexto)
+type UsageRepository interface {
+	// Record soma o consumo de uma pergunta aos totais da sessão e do usuário.
+	// IDs vazios são ignorados.
+	Record(ctx context.Context, sessionID, userID string, usage TokenUsage, costUSD float64) error
+	// FindBySessionID retorna os totais da sessão (zerados se não houver consumo)
+	FindBySessionID(ctx context.Context, sessionID string) (*UsageTotals, error)
+	// FindByUserID retorna os totais do usuário (zerados se não houver consumo)
+	FindByUserID(ctx context.Context, userID string) (*UsageTotals, error)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrVersionNotFound indica que o documento não tem a versão solicitada
+var ErrVersionNotFound = errors.New("versão não encontrada")
+
+// ErrVersionConflict indica que o documento foi alterado por outra operação
+// durante a atualização
+var ErrVersionConflict = errors.New("documento alterado por outra operação")
+
+// DocumentVersion é o conteúdo de um documento em uma versão anterior,
+// guardado a cada atualização para auditoria e rollback
+type DocumentVersion struct {
+	DocumentID string            `bson:"document_id" json:"document_id"`
+	TenantID   string            `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	Version    int               `bson:"version" json:"version"`
+	Title      string            `bson:"title" json:"title"`
+	Content    string            `bson:"content" json:"content"`
+	Link       string            `bson:"link" json:"link"`
+	Category   string            `bson:"category" json:"category"`
+	Metadata   map[string]string `bson:"metadata,omitempty" json:"metadata,omitempty"`
+	Tags       []string          `bson:"tags,omitempty" json:"tags,omitempty"`
+	// CreatedAt é o momento em que esta versão passou a valer
+	CreatedAt time.Time `bson:"created_at,omitempty" json:"created_at,omitzero"`
+}
+
+// NewDocumentVersion registra o estado atual do documento como uma versão
+func NewDocumentVersion(doc Document) DocumentVersion {
+	createdAt := doc.UpdatedAt
+	if createdAt.IsZero() {
+		createdAt = doc.CreatedAt
+	}
+	return DocumentVersion{
+		DocumentID: doc.ID,
+		TenantID:   doc.TenantID,
+		Version:    doc.CurrentVersion(),
+		Title:      doc.Title,
+		Content:    doc.Content,
+		Link:       doc.Link,
+		Category:   doc.Category,
+		Metadata:   doc.Metadata,
+		Tags:       doc.Tags,
+		CreatedAt:  createdAt,
+	}
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "context"
+
+// Origem das fontes entregues ao agente
+const (
+	// MetadataOrigin é a chave de metadados com a origem de uma fonte
+	MetadataOrigin = "origin"
+	// OriginWeb marca as fontes vindas da busca na web, fora da base
+	OriginWeb = "web"
+)
+
+// WebResult é uma página encontrada na busca na web
+type WebResult struct {
+	Title   string
+	URL     string
+	Snippet string // Trecho da página exibido pelo buscador
+}
+
+// WebSearcher busca páginas na web, fora da base de documentos
+type WebSearcher interface {
+	// Search retorna as páginas mais relevantes para a consulta
+	Search(ctx context.Context, query string) ([]WebResult, error)
+}
+
+// External indica se o documento veio de fora da base, como os resultados
+// da busca na web
+func (d Document) External() bool {
+	return d.Metadata[MetadataOrigin] == OriginWeb
+}
@@ -0,0 +1,159 @@
+// Package evaluation avalia a qualidade das respostas do RAG com um LLM como
+// juiz, no estilo do RAGAS: fidelidade às fontes (faithfulness), relevância
+// das fontes para a pergunta (context relevance) e relevância da resposta
+// (answer relevance). É usado pelo comando eval e pela amostragem do serviço.
+// Também gera perguntas sintéticas a partir dos documentos, para montar
+// datasets de avaliação.
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/llmjson"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+)
+
+// maxSourceChars limita o conteúdo de cada fonte enviado ao juiz
+const maxSourceChars = 4000
+
+// source é uma fonte numerada enviada ao juiz
+type source struct {
+	Index   int
+	Title   string
+	Content string
+}
+
+// Judge dá notas às respostas usando um LLM
+type Judge struct {
+	llm     domain.LLMClient
+	prompts *prompts.Set
+}
+
+// NewJudge cria o juiz com os prompts informados (nil usa os padrão)
+func NewJudge(llm domain.LLMClient, promptSet *prompts.Set) *Judge {
+	if promptSet == nil {
+		promptSet = prompts.Default()
+	}
+	return &Judge{llm: llm, prompts: promptSet}
+}
+
+// Score calcula as três notas da resposta. Sem fontes, apenas a relevância
+// da resposta é calculada.
+func (j *Judge) Score(ctx context.Context, question, answer string, sources []domain.Document) (*domain.QualityScores, error) {
+	scores := &domain.QualityScores{}
+	if len(sources) > 0 {
+		faithfulness, _, err := j.Faithfulness(ctx, answer, sources)
+		if err != nil {
+			return nil, err
+		}
+		relevance, err := j.ContextRelevance(ctx, question, sources)
+		if err != nil {
+			return nil, err
+		}
+		scores.Faithfulness, scores.ContextRelevance = &faithfulness, &relevance
+	}
+
+	relevance, err := j.AnswerRelevance(ctx, question, answer)
+	if err != nil {
+		return nil, err
+	}
+	scores.AnswerRelevance = relevance
+	return scores, nil
+}
+
+// Faithfulness pede ao LLM que classifique cada afirmação da resposta e
+// retorna a fração das sustentadas pelas fontes, com as não sustentadas.
+// Respostas sem afirmações verificáveis recebem nota 1.
+func (j *Judge) Faithfulness(ctx context.Context, answer string, sources []domain.Document) (float64, []string, error) {
+	var verdict struct {
+		Claims []struct {
+			Claim     string `json:"claim"`
+			Supported bool   `json:"supported"`
+		} `json:"claims"`
+	}
+	data := map[string]any{"Answer": answer, "Sources": numberSources(sources)}
+	if err := j.ask(ctx, prompts.Groundedness, data, &verdict); err != nil {
+		return 0, nil, fmt.Errorf("erro ao verificar a fidelidade da resposta: %w", err)
+	}
+
+	if len(verdict.Claims) == 0 {
+		return 1, nil, nil
+	}
+	var unsupported []string
+	for _, claim := range verdict.Claims {
+		if !claim.Supported {
+			unsupported = append(unsupported, claim.Claim)
+		}
+	}
+	supported := len(verdict.Claims) - len(unsupported)
+	return float64(supported) / float64(len(verdict.Claims)), unsupported, nil
+}
+
+// ContextRelevance retorna a fração das fontes relevantes para a pergunta
+func (j *Judge) ContextRelevance(ctx context.Context, question string, sources []domain.Document) (float64, error) {
+	if len(sources) == 0 {
+		return 0, nil
+	}
+
+	var verdict struct {
+		Relevant []int `json:"relevant"`
+	}
+	data := map[string]any{"Question": question, "Sources": numberSources(sources)}
+	if err := j.ask(ctx, prompts.ContextRelevance, data, &verdict); err != nil {
+		return 0, fmt.Errorf("erro ao avaliar a relevância das fontes: %w", err)
+	}
+
+	// Ignora números fora da lista e repetidos
+	relevant := map[int]bool{}
+	for _, index := range verdict.Relevant {
+		if index >= 1 && index <= len(sources) {
+			relevant[index] = true
+		}
+	}
+	return float64(len(relevant)) / float64(len(sources)), nil
+}
+
+// AnswerRelevance retorna a nota do LLM para o quanto a resposta atende à pergunta
+func (j *Judge) AnswerRelevance(ctx context.Context, question, answer string) (float64, error) {
+	var verdict struct {
+		Score float64 `json:"score"`
+	}
+	data := map[string]any{"Question": question, "Answer": answer}
+	if err := j.ask(ctx, prompts.AnswerRelevance, data, &verdict); err != nil {
+		return 0, fmt.Errorf("erro ao avaliar a relevância da resposta: %w", err)
+	}
+	return min(max(verdict.Score, 0), 1), nil
+}
+
+// ask envia o prompt ao LLM e interpreta o objeto JSON da resposta em v
+func (j *Judge) ask(ctx context.Context, name string, data map[string]any, v any) error {
+	prompt, err := j.prompts.Render(name, data)
+	if err != nil {
+		return err
+	}
+	msg, err := j.llm.GenerateResponse(ctx, []domain.Message{{Role: domain.RoleUser, Content: prompt}}, nil)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(llmjson.Extract(msg.Content)), v); err != nil {
+		return fmt.Errorf("resposta do juiz inválida: %w", err)
+	}
+	return nil
+}
+
+// numberSources numera as fontes para o juiz, limitando o conteúdo de cada uma
+func numberSources(docs []domain.Document) []source {
+	sources := make([]source, 0, len(docs))
+	for i, doc := range docs {
+		content := doc.Content
+		if utf8.RuneCountInString(content) > maxSourceChars {
+			content = string([]rune(content)[:maxSourceChars]) + "..."
+		}
+		sources = append(sources, source{Index: i + 1, Title: doc.Title, Content: content})
+	}
+	return sources
+}
@@ -0,0 +1,44 @@
+package evaluation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+)
+
+// SyntheticQuestion é uma pergunta gerada a partir de um documento, com a
+// resposta de referência
+type SyntheticQuestion struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// GenerateQuestions pede ao LLM até count perguntas realistas que o
+// documento responde. Perguntas vazias são descartadas.
+func (j *Judge) GenerateQuestions(ctx context.Context, doc domain.Document, count int) ([]SyntheticQuestion, error) {
+	var generated struct {
+		Questions []SyntheticQuestion `json:"questions"`
+	}
+	source := numberSources([]domain.Document{doc})[0]
+	data := map[string]any{"Count": count, "Title": source.Title, "Content": source.Content}
+	if err := j.ask(ctx, prompts.QuestionGeneration, data, &generated); err != nil {
+		return nil, fmt.Errorf("erro ao gerar perguntas: %w", err)
+	}
+
+	questions := make([]SyntheticQuestion, 0, count)
+	for _, q := range generated.Questions {
+		q.Question = strings.TrimSpace(q.Question)
+		q.Answer = strings.TrimSpace(q.Answer)
+		if q.Question == "" {
+			continue
+		}
+		questions = append(questions, q)
+		if len(questions) == count {
+			break
+		}
+	}
+	return questions, nil
+}
@@ -0,0 +1,57 @@
+package guardrail
+
+import (
+	"context"
+	"log"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// LLMClient decora um domain.LLMClient aplicando o pipeline às mensagens
+// antes de enviá-las: perguntas, histórico, argumentos das chamadas de
+// ferramentas e documentos devolvidos por elas chegam ao provedor já
+// mascarados. As ocorrências são
+// registradas no log, sem os valores mascarados.
+type LLMClient struct {
+	next     domain.LLMClient
+	pipeline *Pipeline
+}
+
+// NewLLMClient aplica o pipeline às chamadas ao cliente de LLM informado
+func NewLLMClient(next domain.LLMClient, pipeline *Pipeline) *LLMClient {
+	return &LLMClient{next: next, pipeline: pipeline}
+}
+
+// GenerateResponse implementa domain.LLMClient
+func (c *LLMClient) GenerateResponse(ctx context.Context, messages []domain.Message, tools []domain.Tool) (*domain.Message, error) {
+	return c.next.GenerateResponse(ctx, c.redact(messages), tools)
+}
+
+// GenerateResponseStream implementa domain.LLMClient
+func (c *LLMClient) GenerateResponseStream(ctx context.Context, messages []domain.Message, tools []domain.Tool) (<-chan domain.LLMChunk, error) {
+	return c.next.GenerateResponseStream(ctx, c.redact(messages), tools)
+}
+
+// redact retorna uma cópia das mensagens com o conteúdo e os argumentos das
+// chamadas de ferramentas mascarados
+func (c *LLMClient) redact(messages []domain.Message) []domain.Message {
+	report := Report{}
+	redacted := make([]domain.Message, len(messages))
+	for i, msg := range messages {
+		msg.Content = c.pipeline.Redact(msg.Content, report)
+		if len(msg.ToolCalls) > 0 {
+			calls := make([]domain.ToolCall, len(msg.ToolCalls))
+			for j, call := range msg.ToolCalls {
+				call.Arguments = c.pipeline.RedactJSON(call.Arguments, report)
+				calls[j] = call
+			}
+			msg.ToolCalls = calls
+		}
+		redacted[i] = msg
+	}
+
+	if report.Total() > 0 {
+		log.Printf("Guardrail de PII: %d ocorrências mascaradas (%s)", report.Total(), report)
+	}
+	return redacted
+}
@@ -0,0 +1,54 @@
+package guardrail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// recordingClient guarda as mensagens recebidas
+type recordingClient struct {
+	messages []domain.Message
+}
+
+func (c *recordingClient) GenerateResponse(_ context.Context, messages []domain.Message, _ []domain.Tool) (*domain.Message, error) {
+	c.messages = messages
+	return &domain.Message{Role: domain.RoleAssistant}, nil
+}
+
+func (c *recordingClient) GenerateResponseStream(_ context.Context, messages []domain.Message, _ []domain.Tool) (<-chan domain.LLMChunk, error) {
+	c.messages = messages
+	ch := make(chan domain.LLMChunk)
+	close(ch)
+	return ch, nil
+}
+
+func TestLLMClientRedactsToolCallArguments(t *testing.T) {
+	next := &recordingClient{}
+	client := NewLLMClient(next, NewPipeline(PIIGuards()...))
+	calls := []domain.ToolCall{{ID: "call_1", Name: "search_documents", Arguments: `{"query":"pedido de ana@example.com"}`}}
+	messages := []domain.Message{
+		{Role: domain.RoleUser, Content: "meu email é ana@example.com"},
+		{Role: domain.RoleAssistant, ToolCalls: calls},
+	}
+
+	if _, err := client.GenerateResponse(context.Background(), messages, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := next.messages[0].Content; got != "meu email é [EMAIL]" {
+		t.Errorf("Content = %q", got)
+	}
+	got := next.messages[1].ToolCalls[0]
+	if got.Arguments != `{"query":"pedido de [EMAIL]"}` {
+		t.Errorf("Arguments = %q", got.Arguments)
+	}
+	if got.ID != "call_1" || got.Name != "search_documents" {
+		t.Errorf("ToolCall = %+v, want ID e Name preservados", got)
+	}
+	// As mensagens originais, guardadas no histórico, não são alteradas
+	if calls[0].Arguments != `{"query":"pedido de ana@example.com"}` {
+		t.Errorf("argumentos originais alterados: %q", calls[0].Arguments)
+	}
+}
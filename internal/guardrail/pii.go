@@ -0,0 +1,170 @@
+// Package guardrail aplica proteções ao conteúdo enviado aos LLMs, como o
+// mascaramento de dados pessoais (PII) nas perguntas e nos documentos.
+package guardrail
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Guard detecta um tipo de dado sensível e o mascara no texto
+type Guard interface {
+	// Name identifica o tipo de dado no relatório (ex: "email")
+	Name() string
+	// Redact retorna o texto mascarado e a quantidade de ocorrências
+	Redact(text string) (string, int)
+}
+
+// Report conta as ocorrências mascaradas de cada tipo de dado
+type Report map[string]int
+
+// Total retorna a quantidade de ocorrências mascaradas
+func (r Report) Total() int {
+	total := 0
+	for _, n := range r {
+		total += n
+	}
+	return total
+}
+
+// String descreve as ocorrências em ordem alfabética (ex: "cpf=1 email=2")
+func (r Report) String() string {
+	kinds := make([]string, 0, len(r))
+	for kind, n := range r {
+		if n > 0 {
+			kinds = append(kinds, fmt.Sprintf("%s=%d", kind, n))
+		}
+	}
+	sort.Strings(kinds)
+	return strings.Join(kinds, " ")
+}
+
+// patternGuard mascara as ocorrências de uma expressão regular
+type patternGuard struct {
+	name    string
+	pattern *regexp.Regexp
+	mask    string
+}
+
+// Name implementa Guard
+func (g patternGuard) Name() string {
+	return g.name
+}
+
+// Redact implementa Guard
+func (g patternGuard) Redact(text string) (string, int) {
+	count := 0
+	redacted := g.pattern.ReplaceAllStringFunc(text, func(string) string {
+		count++
+		return g.mask
+	})
+	return redacted, count
+}
+
+// phonePattern reconhece telefones com código do país, com DDD (entre
+// parênteses ou seguido de um separador) ou, sem eles, celulares de 8 ou 9
+// dígitos começando com 9. Sequências como 2023-2024 e 1234 5678, sem DDD,
+// não são telefones.
+var phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s-]?(?:\(\d{2,3}\)\s?|\d{2,3}[\s.-]?)?|\(\d{2,3}\)\s?|\b\d{2,3}[\s.-])(?:\d{4,5}[\s.-]?|\d{3}[\s.-])\d{4}\b|\b9\d{3,4}[\s.-]?\d{4}\b`)
+
+// PIIGuards retorna os guards de dados pessoais, na ordem em que são
+// aplicados: os documentos (CPF, CNPJ, SSN) vêm antes dos telefones, que
+// têm formatos parecidos
+func PIIGuards() []Guard {
+	return []Guard{
+		patternGuard{"email", regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[EMAIL]"},
+		patternGuard{"cnpj", regexp.MustCompile(`\b\d{2}\.\d{3}\.\d{3}/\d{4}-\d{2}\b`), "[CNPJ]"},
+		patternGuard{"cpf", regexp.MustCompile(`\b\d{3}\.\d{3}\.\d{3}-\d{2}\b`), "[CPF]"},
+		patternGuard{"ssn", regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), "[SSN]"},
+		patternGuard{"phone", phonePattern, "[TELEFONE]"},
+	}
+}
+
+// Pipeline aplica os guards em sequência
+type Pipeline struct {
+	guards []Guard
+}
+
+// NewPipeline cria um pipeline com os guards informados
+func NewPipeline(guards ...Guard) *Pipeline {
+	return &Pipeline{guards: guards}
+}
+
+// Redact aplica todos os guards ao texto e acumula as ocorrências no relatório
+func (p *Pipeline) Redact(text string, report Report) string {
+	for _, guard := range p.guards {
+		var n int
+		text, n = guard.Redact(text)
+		if n > 0 {
+			report[guard.Name()] += n
+		}
+	}
+	return text
+}
+
+// RedactJSON aplica os guards aos textos de um documento JSON, como os
+// argumentos das chamadas de ferramentas, mantendo-o válido. Chaves e
+// números ficam como estão; JSON inválido é mascarado como texto.
+func (p *Pipeline) RedactJSON(data string, report Report) string {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return p.Redact(data, report)
+	}
+
+	before := report.Total()
+	value = p.redactValue(value, report)
+	if report.Total() == before {
+		return data
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return p.Redact(data, report)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// redactValue mascara os textos de um valor JSON decodificado
+func (p *Pipeline) redactValue(value any, report Report) any {
+	switch v := value.(type) {
+	case string:
+		return p.Redact(v, report)
+	case []any:
+		for i := range v {
+			v[i] = p.redactValue(v[i], report)
+		}
+	case map[string]any:
+		for key := range v {
+			v[key] = p.redactValue(v[key], report)
+		}
+	}
+	return value
+}
+
+// Config contém as configurações dos guardrails
+type Config struct {
+	// PIIRedaction habilita o mascaramento de emails, telefones, CPF, CNPJ e
+	// SSN no conteúdo enviado ao LLM
+	PIIRedaction bool
+}
+
+// ConfigFromEnv lê a configuração dos guardrails a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	return Config{PIIRedaction: os.Getenv("PII_REDACTION") == "true"}
+}
+
+// New cria o pipeline configurado. Retorna nil quando nenhum guardrail está habilitado.
+func New(cfg Config) *Pipeline {
+	if !cfg.PIIRedaction {
+		return nil
+	}
+	return NewPipeline(PIIGuards()...)
+}
@@ -0,0 +1,112 @@
+package guardrail
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPIIGuardsRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"email", "fale com ana.silva@example.com hoje", "fale com [EMAIL] hoje"},
+		{"cpf", "CPF 123.456.789-09", "CPF [CPF]"},
+		{"cnpj", "CNPJ 12.345.678/0001-90", "CNPJ [CNPJ]"},
+		{"ssn", "SSN 123-45-6789", "SSN [SSN]"},
+		{"celular com DDD entre parênteses", "ligue (11) 91234-5678", "ligue [TELEFONE]"},
+		{"celular com DDD", "ligue 11 91234-5678", "ligue [TELEFONE]"},
+		{"fixo com DDD", "ligue 11 3456-7890", "ligue [TELEFONE]"},
+		{"código do país", "ligue +55 11 91234-5678", "ligue [TELEFONE]"},
+		{"código do país sem separadores", "ligue +5511912345678", "ligue [TELEFONE]"},
+		{"americano", "call +1 (555) 123-4567", "call [TELEFONE]"},
+		{"celular sem DDD", "ligue 91234-5678", "ligue [TELEFONE]"},
+		{"celular antigo sem DDD", "ligue 9123-4567", "ligue [TELEFONE]"},
+		{"intervalo de anos", "vigente em 2023-2024", "vigente em 2023-2024"},
+		{"dois grupos de quatro dígitos", "códigos 1234 5678", "códigos 1234 5678"},
+		{"oito dígitos sem DDD", "pedido 12345678", "pedido 12345678"},
+		{"data", "em 2024-01-15", "em 2024-01-15"},
+		{"CEP", "CEP 01310-100", "CEP 01310-100"},
+		{"valor", "R$ 1.234.567,89", "R$ 1.234.567,89"},
+		{"versão", "Go 1.22.3", "Go 1.22.3"},
+	}
+
+	pipeline := NewPipeline(PIIGuards()...)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pipeline.Redact(tt.text, Report{}); got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipelineRedactJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		want   map[string]any
+		masked int
+	}{
+		{
+			name:   "textos",
+			data:   `{"query":"contato ana@example.com","filters":["(11) 91234-5678"],"limit":5}`,
+			want:   map[string]any{"query": "contato [EMAIL]", "filters": []any{"[TELEFONE]"}, "limit": json.Number("5")},
+			masked: 2,
+		},
+		{
+			name: "sem dados pessoais",
+			data: `{"query":"vigência 2023-2024"}`,
+			want: map[string]any{"query": "vigência 2023-2024"},
+		},
+	}
+
+	pipeline := NewPipeline(PIIGuards()...)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Report{}
+			got := pipeline.RedactJSON(tt.data, report)
+			if report.Total() != tt.masked {
+				t.Errorf("RedactJSON(%q) masked %d, want %d", tt.data, report.Total(), tt.masked)
+			}
+			if tt.masked == 0 && got != tt.data {
+				t.Errorf("RedactJSON(%q) = %q, want unchanged", tt.data, got)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+
+	t.Run("JSON inválido", func(t *testing.T) {
+		got := pipeline.RedactJSON(`{"query": "ana@example.com"`, Report{})
+		if want := `{"query": "[EMAIL]"`; got != want {
+			t.Errorf("RedactJSON = %q, want %q", got, want)
+		}
+	})
+}
+
+// assertJSON verifica se o JSON é válido e igual ao valor esperado
+func assertJSON(t *testing.T, data string, want map[string]any) {
+	t.Helper()
+	got, err := json.Marshal(decodeJSON(t, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(expected) {
+		t.Errorf("JSON = %s, want %s", got, expected)
+	}
+}
+
+// decodeJSON decodifica o JSON, falhando o teste se ele for inválido
+func decodeJSON(t *testing.T, data string) any {
+	t.Helper()
+	var value any
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		t.Fatalf("JSON inválido %q: %v", data, err)
+	}
+	return value
+}
@@ -0,0 +1,134 @@
+// Package health verifica as dependências do servidor para as sondas de
+// prontidão (readiness), com o resultado de cada uma
+package health
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Valores padrão das verificações
+const (
+	DefaultTimeout  = 5 * time.Second
+	DefaultCacheTTL = time.Minute
+)
+
+// Config contém as configurações das verificações
+type Config struct {
+	Timeout  time.Duration // Tempo máximo de cada verificação (padrão: 5s)
+	CacheTTL time.Duration // Validade do resultado das verificações de serviços externos (padrão: 1m)
+}
+
+// ConfigFromEnv lê a configuração das verificações a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	var cfg Config
+	cfg.Timeout, _ = time.ParseDuration(os.Getenv("HEALTH_CHECK_TIMEOUT"))
+	cfg.CacheTTL, _ = time.ParseDuration(os.Getenv("HEALTH_CHECK_CACHE_TTL"))
+	return cfg
+}
+
+// CheckFunc verifica uma dependência, retornando o motivo da falha
+type CheckFunc func(ctx context.Context) error
+
+// check é uma verificação registrada
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker implementa domain.ReadinessChecker com as verificações registradas
+type Checker struct {
+	checks   []check
+	timeout  time.Duration
+	cacheTTL time.Duration
+}
+
+// New cria um Checker sem verificações
+func New(cfg Config) *Checker {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = DefaultCacheTTL
+	}
+	return &Checker{timeout: cfg.Timeout, cacheTTL: cfg.CacheTTL}
+}
+
+// Add registra a verificação de uma dependência, executada a cada
+// CheckReadiness
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// AddCached registra a verificação de um serviço externo, cujo resultado é
+// reaproveitado por CacheTTL para que as sondas frequentes não gerem uma
+// chamada (e, em APIs pagas, consumo de cota) a cada execução
+func (c *Checker) AddCached(name string, fn CheckFunc) {
+	cached := &cachedCheck{fn: fn, ttl: c.cacheTTL}
+	c.Add(name, cached.run)
+}
+
+// CheckReadiness executa as verificações em paralelo, cada uma limitada por
+// Timeout, e registra no log as que falharam. As dependências ficam na ordem
+// em que foram registradas.
+func (c *Checker) CheckReadiness(ctx context.Context) domain.HealthReport {
+	report := domain.HealthReport{Status: domain.HealthOK, Dependencies: make([]domain.DependencyHealth, len(c.checks))}
+	var wg sync.WaitGroup
+	for i, chk := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := chk.fn(checkCtx)
+			dep := domain.DependencyHealth{Name: chk.name, Status: domain.HealthOK, Latency: time.Since(start), Err: err}
+			if err != nil {
+				dep.Status = domain.HealthUnavailable
+			}
+			report.Dependencies[i] = dep
+		}()
+	}
+	wg.Wait()
+
+	for _, dep := range report.Dependencies {
+		if dep.Err != nil {
+			report.Status = domain.HealthUnavailable
+			log.Printf("Verificação de %s falhou: %v", dep.Name, dep.Err)
+		}
+	}
+	return report
+}
+
+// cachedCheck guarda o último resultado de uma verificação
+type cachedCheck struct {
+	fn  CheckFunc
+	ttl time.Duration
+
+	mu        sync.Mutex
+	err       error
+	checkedAt time.Time
+}
+
+// run executa a verificação quando o último resultado expirou. Verificações
+// simultâneas esperam a que está em andamento.
+func (c *cachedCheck) run(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checkedAt.IsZero() && time.Since(c.checkedAt) < c.ttl {
+		return c.err
+	}
+	err := c.fn(ctx)
+	if ctx.Err() != nil {
+		// Verificação interrompida (prazo ou sonda cancelada), refeita na próxima
+		return err
+	}
+	c.err, c.checkedAt = err, time.Now()
+	return err
+}
@@ -0,0 +1,415 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Valores padrão do cliente da Anthropic
+const (
+	defaultAnthropicBaseURL   = "https://api.anthropic.com/v1"
+	defaultAnthropicModel     = "claude-sonnet-4-5"
+	defaultAnthropicMaxTokens = 4096
+	anthropicAPIVersion       = "2023-06-01"
+)
+
+// structuredAnswerTool é o nome da resposta estruturada pedida com
+// domain.WithResponseSchema: a ferramenta de uso obrigatório na Anthropic e
+// o schema do response_format na OpenAI
+const structuredAnswerTool = "structured_answer"
+
+// AnthropicConfig contém as configurações do cliente da Anthropic
+type AnthropicConfig struct {
+	APIKey    string
+	Model     string // Modelo usado nas chamadas (ex: "claude-sonnet-4-5")
+	MaxTokens int    // Limite de tokens da resposta, obrigatório na API
+	BaseURL   string // Opcional: permite apontar para um proxy
+}
+
+// AnthropicClient implementa domain.LLMClient usando a Messages API da Anthropic
+type AnthropicClient struct {
+	httpClient *http.Client
+	apiKey     string
+	model      string
+	maxTokens  int
+	baseURL    string
+}
+
+// NewAnthropicClient cria um novo cliente da Anthropic
+func NewAnthropicClient(cfg AnthropicConfig) *AnthropicClient {
+	client := &AnthropicClient{
+		httpClient: http.DefaultClient,
+		apiKey:     cfg.APIKey,
+		model:      cfg.Model,
+		maxTokens:  cfg.MaxTokens,
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+	}
+	if client.model == "" {
+		client.model = defaultAnthropicModel
+	}
+	if client.maxTokens <= 0 {
+		client.maxTokens = defaultAnthropicMaxTokens
+	}
+	if client.baseURL == "" {
+		client.baseURL = defaultAnthropicBaseURL
+	}
+	return client
+}
+
+// anthropicRequest é o corpo de uma chamada à Messages API
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	System    string             `json:"system,omitempty"`
+	Messages  []anthropicMessage `json:"messages"`
+	Tools     []anthropicTool    `json:"tools,omitempty"`
+	// Parâmetros de amostragem; a API não aceita uma semente
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	// ToolChoice obriga o uso de uma ferramenta
+	ToolChoice *anthropicToolChoice `json:"tool_choice,omitempty"`
+	Stream     bool                 `json:"stream,omitempty"`
+}
+
+// anthropicToolChoice define como o modelo escolhe as ferramentas
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+// anthropicMessage é uma mensagem no formato da Anthropic
+type anthropicMessage struct {
+	Role    string           `json:"role"`
+	Content []anthropicBlock `json:"content"`
+}
+
+// anthropicBlock é um bloco de conteúdo (texto, uso ou resultado de ferramenta)
+type anthropicBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+}
+
+// anthropicTool descreve uma ferramenta no formato da Anthropic
+type anthropicTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+// anthropicResponse é a resposta de uma chamada à Messages API
+type anthropicResponse struct {
+	Model   string           `json:"model"`
+	Content []anthropicBlock `json:"content"`
+	Usage   anthropicUsage   `json:"usage"`
+}
+
+// anthropicUsage é o consumo de tokens informado pela API
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// anthropicError é o corpo de erro retornado pela API
+type anthropicError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// GenerateResponse envia as mensagens ao modelo e retorna a mensagem gerada.
+// Com um schema de resposta no contexto, o modelo é obrigado a usar uma
+// ferramenta com esse schema, cujos argumentos viram o conteúdo da mensagem.
+func (c *AnthropicClient) GenerateResponse(ctx context.Context, messages []domain.Message, tools []domain.Tool) (*domain.Message, error) {
+	req := c.buildRequest(ctx, messages, tools, false)
+	schema := domain.ResponseSchemaFromContext(ctx)
+	if schema != nil {
+		req.Tools = append(req.Tools, anthropicTool{
+			Name:        structuredAnswerTool,
+			Description: "Returns the answer in the requested format",
+			InputSchema: schema,
+		})
+		req.ToolChoice = &anthropicToolChoice{Type: "tool", Name: structuredAnswerTool}
+	}
+
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta da Anthropic: %w", err)
+	}
+
+	msg := &domain.Message{Role: domain.RoleAssistant}
+	var content strings.Builder
+	for _, block := range body.Content {
+		switch block.Type {
+		case "text":
+			content.WriteString(block.Text)
+		case "tool_use":
+			if schema != nil && block.Name == structuredAnswerTool {
+				content.Write(block.Input)
+				continue
+			}
+			msg.ToolCalls = append(msg.ToolCalls, domain.ToolCall{
+				ID:        block.ID,
+				Name:      block.Name,
+				Arguments: string(block.Input),
+			})
+		}
+	}
+	msg.Content = content.String()
+	msg.Usage = domain.TokenUsage{
+		Model:            body.Model,
+		PromptTokens:     body.Usage.InputTokens,
+		CompletionTokens: body.Usage.OutputTokens,
+	}
+	return msg, nil
+}
+
+// GenerateResponseStream envia as mensagens ao modelo e retorna os pedaços
+// da resposta à medida que são gerados
+func (c *AnthropicClient) GenerateResponseStream(ctx context.Context, messages []domain.Message, tools []domain.Tool) (<-chan domain.LLMChunk, error) {
+	resp, err := c.send(ctx, c.buildRequest(ctx, messages, tools, true))
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan domain.LLMChunk)
+	go func() {
+		defer close(chunks)
+		defer resp.Body.Close()
+
+		// send entrega o pedaço, desistindo se o contexto for cancelado
+		send := func(chunk domain.LLMChunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// Os blocos de uso de ferramenta chegam fragmentados e são montados pelo índice
+		toolCalls := map[int]*domain.ToolCall{}
+		var order []int
+
+		// Os tokens de entrada chegam em message_start e os de saída em message_delta
+		usage := domain.TokenUsage{Model: c.model}
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+
+			var event struct {
+				Type         string         `json:"type"`
+				Index        int            `json:"index"`
+				ContentBlock anthropicBlock `json:"content_block"`
+				Delta        struct {
+					Type        string `json:"type"`
+					Text        string `json:"text"`
+					PartialJSON string `json:"partial_json"`
+				} `json:"delta"`
+				Message anthropicResponse `json:"message"`
+				Usage   anthropicUsage    `json:"usage"`
+				Error   struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				send(domain.LLMChunk{Err: fmt.Errorf("erro ao decodificar evento da Anthropic: %w", err)})
+				return
+			}
+
+			switch event.Type {
+			case "message_start":
+				if event.Message.Model != "" {
+					usage.Model = event.Message.Model
+				}
+				usage.PromptTokens = event.Message.Usage.InputTokens
+			case "message_delta":
+				usage.CompletionTokens = event.Usage.OutputTokens
+			case "content_block_start":
+				if event.ContentBlock.Type == "tool_use" {
+					toolCalls[event.Index] = &domain.ToolCall{ID: event.ContentBlock.ID, Name: event.ContentBlock.Name}
+					order = append(order, event.Index)
+				}
+			case "content_block_delta":
+				switch event.Delta.Type {
+				case "text_delta":
+					if !send(domain.LLMChunk{Content: event.Delta.Text}) {
+						return
+					}
+				case "input_json_delta":
+					if tc, ok := toolCalls[event.Index]; ok {
+						tc.Arguments += event.Delta.PartialJSON
+					}
+				}
+			case "message_stop":
+				var calls []domain.ToolCall
+				for _, index := range order {
+					tc := *toolCalls[index]
+					if tc.Arguments == "" {
+						tc.Arguments = "{}"
+					}
+					calls = append(calls, tc)
+				}
+				send(domain.LLMChunk{ToolCalls: calls, Done: true, Usage: usage})
+				return
+			case "error":
+				send(domain.LLMChunk{Err: fmt.Errorf("erro no streaming da Anthropic: %s", event.Error.Message)})
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			send(domain.LLMChunk{Err: fmt.Errorf("erro no streaming da Anthropic: %w", err)})
+		}
+	}()
+
+	return chunks, nil
+}
+
+// buildRequest traduz as mensagens e ferramentas do domínio para o formato da Anthropic.
+// Mensagens de sistema vão para o campo system, chamadas de ferramentas viram
+// blocos tool_use e os resultados viram blocos tool_result em mensagens do usuário.
+// Os parâmetros de geração do contexto são aplicados, e o limite de tokens,
+// quando menor, substitui o configurado.
+func (c *AnthropicClient) buildRequest(ctx context.Context, messages []domain.Message, tools []domain.Tool, stream bool) anthropicRequest {
+	gen := domain.GenerationFromContext(ctx)
+	req := anthropicRequest{
+		Model:       cmp.Or(gen.Model, c.model),
+		MaxTokens:   c.maxTokens,
+		Temperature: gen.Temperature,
+		TopP:        gen.TopP,
+		Stream:      stream,
+	}
+	if gen.MaxTokens > 0 && gen.MaxTokens < req.MaxTokens {
+		req.MaxTokens = gen.MaxTokens
+	}
+
+	var system []string
+	for _, m := range messages {
+		var role string
+		var blocks []anthropicBlock
+
+		switch m.Role {
+		case domain.RoleSystem:
+			system = append(system, m.Content)
+			continue
+		case domain.RoleTool:
+			role = "user"
+			blocks = append(blocks, anthropicBlock{Type: "tool_result", ToolUseID: m.ToolCallID, Content: m.Content})
+		case domain.RoleAssistant:
+			role = "assistant"
+			if m.Content != "" {
+				blocks = append(blocks, anthropicBlock{Type: "text", Text: m.Content})
+			}
+			for _, tc := range m.ToolCalls {
+				input := json.RawMessage(tc.Arguments)
+				if !json.Valid(input) {
+					input = json.RawMessage("{}")
+				}
+				blocks = append(blocks, anthropicBlock{Type: "tool_use", ID: tc.ID, Name: tc.Name, Input: input})
+			}
+		default:
+			role = "user"
+			blocks = append(blocks, anthropicBlock{Type: "text", Text: m.Content})
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+
+		// A API exige alternância de papéis: mensagens seguidas do mesmo papel são unidas
+		if n := len(req.Messages); n > 0 && req.Messages[n-1].Role == role {
+			req.Messages[n-1].Content = append(req.Messages[n-1].Content, blocks...)
+			continue
+		}
+		req.Messages = append(req.Messages, anthropicMessage{Role: role, Content: blocks})
+	}
+	req.System = strings.Join(system, "\n\n")
+
+	for _, t := range tools {
+		req.Tools = append(req.Tools, anthropicTool{
+			Name:        t.Name,
+			Description: t.Description,
+			InputSchema: t.Parameters,
+		})
+	}
+	return req
+}
+
+// HealthCheck verifica a chave listando os modelos disponíveis, sem gerar
+// tokens
+func (c *AnthropicClient) HealthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/models?limit=1", nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição para a Anthropic: %w", err)
+	}
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar acesso à Anthropic: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return statusError(resp, fmt.Errorf("erro ao verificar acesso à Anthropic (status %d)", resp.StatusCode))
+	}
+	return nil
+}
+
+// send executa a chamada à Messages API, convertendo respostas de erro
+func (c *AnthropicClient) send(ctx context.Context, body anthropicRequest) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar requisição para a Anthropic: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/messages", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição para a Anthropic: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro na chamada à Anthropic: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+		var apiErr anthropicError
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, statusError(resp, fmt.Errorf("erro na chamada à Anthropic (status %d, %s): %s", resp.StatusCode, apiErr.Error.Type, apiErr.Error.Message))
+		}
+		return nil, statusError(resp, fmt.Errorf("erro na chamada à Anthropic (status %d): %s", resp.StatusCode, raw))
+	}
+
+	return resp, nil
+}
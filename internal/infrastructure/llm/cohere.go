@@ -0,0 +1,145 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Valores padrão do cliente da Cohere
+const (
+	defaultCohereBaseURL        = "https://api.cohere.com/v2"
+	defaultCohereEmbeddingModel = "embed-v4.0"
+	// defaultCohereBatchSize é o limite de textos por requisição da Cohere
+	defaultCohereBatchSize = 96
+)
+
+// CohereConfig contém as configurações do cliente de embeddings da Cohere
+type CohereConfig struct {
+	APIKey         string
+	BaseURL        string // Endereço da API (padrão: https://api.cohere.com/v2)
+	EmbeddingModel string // ex: embed-v4.0, embed-multilingual-v3.0
+	// EmbeddingDimensions reduz a dimensão dos embeddings, no embed-v4.0
+	// (256, 512, 1024 ou 1536); 0 usa a dimensão padrão do modelo
+	EmbeddingDimensions int
+}
+
+// CohereClient implementa domain.EmbeddingClient usando a API da Cohere
+type CohereClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	model      string
+	dimensions int
+}
+
+// NewCohereClient cria um novo cliente da Cohere
+func NewCohereClient(cfg CohereConfig) *CohereClient {
+	c := &CohereClient{
+		httpClient: http.DefaultClient,
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:     cfg.APIKey,
+		model:      cfg.EmbeddingModel,
+		dimensions: cfg.EmbeddingDimensions,
+	}
+	if c.baseURL == "" {
+		c.baseURL = defaultCohereBaseURL
+	}
+	if c.model == "" {
+		c.model = defaultCohereEmbeddingModel
+	}
+	return c
+}
+
+// cohereEmbedRequest é o corpo de uma chamada a /embed
+type cohereEmbedRequest struct {
+	Model           string   `json:"model"`
+	Texts           []string `json:"texts"`
+	InputType       string   `json:"input_type"`
+	EmbeddingTypes  []string `json:"embedding_types"`
+	OutputDimension int      `json:"output_dimension,omitempty"`
+	Truncate        string   `json:"truncate"`
+}
+
+// cohereEmbedResponse é a resposta de /embed
+type cohereEmbedResponse struct {
+	Embeddings struct {
+		Float [][]float32 `json:"float"`
+	} `json:"embeddings"`
+}
+
+// Embed gera um embedding para cada texto, na mesma ordem da entrada. Os
+// textos são tratados como consultas quando o contexto foi marcado com
+// domain.WithQueryEmbedding, e como documentos nos demais casos.
+func (c *CohereClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	inputType := "search_document"
+	if domain.IsQueryEmbedding(ctx) {
+		inputType = "search_query"
+	}
+	payload, err := json.Marshal(cohereEmbedRequest{
+		Model:           c.model,
+		Texts:           texts,
+		InputType:       inputType,
+		EmbeddingTypes:  []string{"float"},
+		OutputDimension: c.dimensions,
+		Truncate:        "END",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar requisição à Cohere: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/embed", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição à Cohere: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar embeddings na Cohere: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, statusError(resp, fmt.Errorf("erro ao gerar embeddings na Cohere (status %d): %s", resp.StatusCode, raw))
+	}
+
+	var result cohereEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta da Cohere: %w", err)
+	}
+	if len(result.Embeddings.Float) != len(texts) {
+		return nil, fmt.Errorf("quantidade de embeddings inesperada: %d (esperado %d)", len(result.Embeddings.Float), len(texts))
+	}
+	return result.Embeddings.Float, nil
+}
+
+// cohereEmbeddingDimensions são as dimensões padrão dos modelos de embeddings da Cohere
+var cohereEmbeddingDimensions = map[string]int{
+	"embed-v4.0":                    1536,
+	"embed-english-v3.0":            1024,
+	"embed-multilingual-v3.0":       1024,
+	"embed-english-light-v3.0":      384,
+	"embed-multilingual-light-v3.0": 384,
+}
+
+// Dimensions implementa domain.EmbeddingClient: a dimensão configurada ou a
+// padrão do modelo, e 0 para modelos desconhecidos
+func (c *CohereClient) Dimensions() int {
+	if c.dimensions > 0 {
+		return c.dimensions
+	}
+	return cohereEmbeddingDimensions[c.model]
+}
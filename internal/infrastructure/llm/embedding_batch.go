@@ -0,0 +1,161 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/tokenizer"
+)
+
+// Valores padrão da divisão em lotes, abaixo dos limites da OpenAI (2048
+// textos e 300 mil tokens por requisição)
+const (
+	DefaultEmbeddingBatchSize   = 256
+	DefaultEmbeddingBatchTokens = 100000
+)
+
+// EmbeddingBatchConfig contém os limites das requisições de embeddings
+type EmbeddingBatchConfig struct {
+	MaxTexts  int // Textos por requisição
+	MaxTokens int // Tokens (estimados) por requisição; um texto maior vai sozinho
+	// TokensPerMinute limita os tokens enviados por minuto, somando todas as
+	// chamadas do processo, para não esgotar o limite da conta. 0 desabilita.
+	TokensPerMinute int
+}
+
+// embeddingBatchConfigFromEnv lê os limites das requisições de embeddings
+func embeddingBatchConfigFromEnv() EmbeddingBatchConfig {
+	var cfg EmbeddingBatchConfig
+	cfg.MaxTexts, _ = strconv.Atoi(os.Getenv("EMBEDDING_BATCH_SIZE"))
+	cfg.MaxTokens, _ = strconv.Atoi(os.Getenv("EMBEDDING_BATCH_TOKENS"))
+	cfg.TokensPerMinute, _ = strconv.Atoi(os.Getenv("EMBEDDING_TOKENS_PER_MINUTE"))
+	return cfg
+}
+
+// BatchEmbeddingClient decora um domain.EmbeddingClient dividindo listas
+// longas em lotes enviados em sequência e, com TokensPerMinute, esperando
+// antes de cada lote que exceder o limite do último minuto. Os embeddings
+// com dimensão diferente da informada pelo cliente são recusados.
+type BatchEmbeddingClient struct {
+	next      domain.EmbeddingClient
+	cfg       EmbeddingBatchConfig
+	tokenizer tokenizer.Tokenizer
+
+	mu   sync.Mutex
+	sent []sentBatch // Lotes enviados no último minuto, do mais antigo ao mais novo
+}
+
+// sentBatch é um lote já enviado, usado no limite de tokens por minuto
+type sentBatch struct {
+	at     time.Time
+	tokens int
+}
+
+// NewBatchEmbeddingClient aplica os limites de cfg ao cliente de embeddings
+func NewBatchEmbeddingClient(next domain.EmbeddingClient, cfg EmbeddingBatchConfig) *BatchEmbeddingClient {
+	if cfg.MaxTexts <= 0 {
+		cfg.MaxTexts = DefaultEmbeddingBatchSize
+	}
+	if cfg.MaxTokens <= 0 {
+		cfg.MaxTokens = DefaultEmbeddingBatchTokens
+	}
+	return &BatchEmbeddingClient{next: next, cfg: cfg, tokenizer: tokenizer.Estimator{}}
+}
+
+// embeddingBatch é uma parte dos textos enviada em uma requisição
+type embeddingBatch struct {
+	texts  []string
+	tokens int
+}
+
+// Embed implementa domain.EmbeddingClient
+func (c *BatchEmbeddingClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	vectors := make([][]float32, 0, len(texts))
+	for _, batch := range c.split(texts) {
+		if err := c.throttle(ctx, batch.tokens); err != nil {
+			return nil, err
+		}
+		got, err := c.next.Embed(ctx, batch.texts)
+		if err != nil {
+			return nil, err
+		}
+		if len(got) != len(batch.texts) {
+			return nil, fmt.Errorf("quantidade de embeddings inesperada: %d (esperado %d)", len(got), len(batch.texts))
+		}
+		// A dimensão pode ser conhecida só após o primeiro lote
+		dimensions := c.next.Dimensions()
+		for _, v := range got {
+			if dimensions > 0 && len(v) != dimensions {
+				return nil, fmt.Errorf("embedding com dimensão %d (esperado %d)", len(v), dimensions)
+			}
+		}
+		vectors = append(vectors, got...)
+	}
+	return vectors, nil
+}
+
+// Dimensions implementa domain.EmbeddingClient
+func (c *BatchEmbeddingClient) Dimensions() int {
+	return c.next.Dimensions()
+}
+
+// split divide os textos em lotes de até MaxTexts textos e MaxTokens tokens
+func (c *BatchEmbeddingClient) split(texts []string) []embeddingBatch {
+	var batches []embeddingBatch
+	var current embeddingBatch
+	for _, text := range texts {
+		tokens := c.tokenizer.Count(text)
+		if len(current.texts) > 0 && (len(current.texts) == c.cfg.MaxTexts || current.tokens+tokens > c.cfg.MaxTokens) {
+			batches = append(batches, current)
+			current = embeddingBatch{}
+		}
+		current.texts = append(current.texts, text)
+		current.tokens += tokens
+	}
+	return append(batches, current)
+}
+
+// throttle espera até que o lote caiba no limite de tokens do último minuto
+// e o reserva. Um lote maior que o limite é enviado quando nenhum outro foi
+// enviado no último minuto.
+func (c *BatchEmbeddingClient) throttle(ctx context.Context, tokens int) error {
+	if c.cfg.TokensPerMinute <= 0 {
+		return nil
+	}
+
+	for {
+		c.mu.Lock()
+		now := time.Now()
+		used := 0
+		for len(c.sent) > 0 && now.Sub(c.sent[0].at) >= time.Minute {
+			c.sent = c.sent[1:]
+		}
+		for _, b := range c.sent {
+			used += b.tokens
+		}
+		if len(c.sent) == 0 || used+tokens <= c.cfg.TokensPerMinute {
+			c.sent = append(c.sent, sentBatch{at: now, tokens: tokens})
+			c.mu.Unlock()
+			return nil
+		}
+		wait := c.sent[0].at.Add(time.Minute).Sub(now)
+		c.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
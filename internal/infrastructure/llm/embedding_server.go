@@ -0,0 +1,146 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// Formatos de API dos servidores de embeddings
+const (
+	EmbeddingFormatOpenAI = "openai" // POST /embeddings, compatível com a OpenAI (vLLM, LocalAI, LM Studio, Ollama)
+	EmbeddingFormatTEI    = "tei"    // POST /embed, do Text Embeddings Inference da Hugging Face
+)
+
+// defaultTEIBatchSize é o padrão de --max-client-batch-size do TEI
+const defaultTEIBatchSize = 32
+
+// EmbeddingServerConfig contém as configurações de um servidor de embeddings
+// próprio, usado no lugar da OpenAI
+type EmbeddingServerConfig struct {
+	BaseURL string // Endereço da API (ex: http://localhost:8081/v1); vazio desabilita
+	Format  string // EmbeddingFormatOpenAI (padrão) ou EmbeddingFormatTEI
+	APIKey  string // Opcional: enviada como Bearer
+	Model   string // Modelo, no formato openai; o TEI serve um único modelo
+	// Dimensions é a dimensão esperada dos embeddings; 0 adota a do primeiro
+	// embedding recebido
+	Dimensions int
+}
+
+// EmbeddingServerClient implementa domain.EmbeddingClient em um servidor de
+// embeddings compatível com a OpenAI ou com o Text Embeddings Inference,
+// para gerar os embeddings sem enviar os documentos para fora
+type EmbeddingServerClient struct {
+	httpClient *http.Client
+	cfg        EmbeddingServerConfig
+	dimensions atomic.Int64
+}
+
+// NewEmbeddingServerClient cria o cliente do servidor de embeddings
+func NewEmbeddingServerClient(cfg EmbeddingServerConfig) *EmbeddingServerClient {
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.Format != EmbeddingFormatTEI {
+		cfg.Format = EmbeddingFormatOpenAI
+	}
+	c := &EmbeddingServerClient{httpClient: http.DefaultClient, cfg: cfg}
+	c.dimensions.Store(int64(cfg.Dimensions))
+	return c
+}
+
+// openAIEmbeddingRequest é o corpo de uma chamada a /embeddings
+type openAIEmbeddingRequest struct {
+	Input []string `json:"input"`
+	Model string   `json:"model,omitempty"`
+}
+
+// openAIEmbeddingResponse é a resposta de /embeddings
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// teiRequest é o corpo de uma chamada a /embed; textos maiores que o limite
+// do modelo são cortados em vez de recusados
+type teiRequest struct {
+	Inputs   []string `json:"inputs"`
+	Truncate bool     `json:"truncate"`
+}
+
+// Embed implementa domain.EmbeddingClient
+func (c *EmbeddingServerClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	var vectors [][]float32
+	if c.cfg.Format == EmbeddingFormatTEI {
+		if err := c.post(ctx, "/embed", teiRequest{Inputs: texts, Truncate: true}, &vectors); err != nil {
+			return nil, err
+		}
+	} else {
+		var resp openAIEmbeddingResponse
+		if err := c.post(ctx, "/embeddings", openAIEmbeddingRequest{Input: texts, Model: c.cfg.Model}, &resp); err != nil {
+			return nil, err
+		}
+		// Os vetores são identificados pelo índice da entrada
+		vectors = make([][]float32, len(texts))
+		for _, e := range resp.Data {
+			if e.Index < 0 || e.Index >= len(vectors) {
+				return nil, fmt.Errorf("índice de embedding inválido: %d", e.Index)
+			}
+			vectors[e.Index] = e.Embedding
+		}
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("quantidade de embeddings inesperada: %d (esperado %d)", len(vectors), len(texts))
+	}
+	if len(vectors[0]) > 0 {
+		c.dimensions.CompareAndSwap(0, int64(len(vectors[0])))
+	}
+	return vectors, nil
+}
+
+// Dimensions implementa domain.EmbeddingClient: a dimensão configurada ou a
+// do primeiro embedding recebido
+func (c *EmbeddingServerClient) Dimensions() int {
+	return int(c.dimensions.Load())
+}
+
+// post envia a requisição ao servidor e decodifica a resposta em out
+func (c *EmbeddingServerClient) post(ctx context.Context, path string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar requisição de embeddings: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição de embeddings: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro na chamada ao servidor de embeddings: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return statusError(resp, fmt.Errorf("erro na chamada ao servidor de embeddings (status %d): %s", resp.StatusCode, raw))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta do servidor de embeddings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// isTemporaryStatus indica se o status HTTP é de uma falha transitória do
+// provedor: limite de requisições (429), erro interno ou sobrecarga (5xx)
+func isTemporaryStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// statusError marca o erro da resposta como temporário quando o status
+// indica uma falha transitória, com a espera pedida em Retry-After
+func statusError(resp *http.Response, err error) error {
+	if !isTemporaryStatus(resp.StatusCode) {
+		return err
+	}
+	seconds, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+	return &domain.TemporaryError{Err: err, RetryAfter: time.Duration(seconds) * time.Second}
+}
+
+// openAIError marca como temporários os erros da OpenAI com status 429 ou
+// 5xx. A cota esgotada também responde 429, mas não se resolve com novas
+// tentativas e é identificada por domain.ErrLLMQuotaExceeded.
+func openAIError(err error) error {
+	status := 0
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		if apiErr.Code == "insufficient_quota" {
+			return fmt.Errorf("%w: %w", domain.ErrLLMQuotaExceeded, err)
+		}
+		status = apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr):
+		status = reqErr.HTTPStatusCode
+	}
+	if !isTemporaryStatus(status) {
+		return err
+	}
+	return &domain.TemporaryError{Err: err}
+}
@@ -0,0 +1,141 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// fallbackProvider é um dos clientes da cadeia de provedores
+type fallbackProvider struct {
+	name   string // "provedor" ou "provedor:modelo", para os logs
+	client domain.LLMClient
+}
+
+// FallbackClient implementa domain.LLMClient repassando cada chamada ao
+// primeiro provedor da cadeia e, quando ele está indisponível (erros
+// temporários, circuito aberto ou cota esgotada), aos seguintes, em ordem.
+// Outros erros, como requisições inválidas, são devolvidos sem tentar os
+// demais provedores. O modelo dos parâmetros de geração do contexto vale
+// apenas para o primeiro provedor; os demais usam o próprio modelo.
+type FallbackClient struct {
+	providers []fallbackProvider
+}
+
+// NewChain cria o cliente do provedor principal seguido dos provedores de
+// cfg.Fallbacks. Sem fallbacks, retorna o cliente do provedor principal.
+// decorate, quando informado, é aplicado a cada provedor antes de montar a
+// cadeia, para que cada um tenha, por exemplo, as próprias novas tentativas
+// e o próprio circuit breaker.
+func NewChain(cfg Config, decorate func(domain.LLMClient) domain.LLMClient) (domain.LLMClient, error) {
+	names := append([]string{cfg.Provider}, cfg.Fallbacks...)
+	providers := make([]fallbackProvider, 0, len(names))
+	for _, name := range names {
+		providerCfg, err := withProvider(cfg, name)
+		if err != nil {
+			return nil, err
+		}
+		client, err := newProvider(providerCfg)
+		if err != nil {
+			return nil, err
+		}
+		if decorate != nil {
+			client = decorate(client)
+		}
+		if name == "" {
+			name = DefaultProvider
+		}
+		providers = append(providers, fallbackProvider{name: name, client: client})
+	}
+
+	if len(providers) == 1 {
+		return providers[0].client, nil
+	}
+	return &FallbackClient{providers: providers}, nil
+}
+
+// withProvider retorna a configuração com o provedor e, se informado, o
+// modelo de uma entrada "provedor:modelo"
+func withProvider(cfg Config, entry string) (Config, error) {
+	provider, model, hasModel := strings.Cut(entry, ":")
+	cfg.Provider = provider
+	if !hasModel {
+		return cfg, nil
+	}
+	if model == "" {
+		return cfg, fmt.Errorf("modelo vazio no provedor de LLM %q", entry)
+	}
+
+	switch strings.ToLower(provider) {
+	case "", "openai":
+		cfg.OpenAI.Model = model
+	case "azure":
+		cfg.Azure.AzureDeployment = model
+	case "anthropic":
+		cfg.Anthropic.Model = model
+	case "ollama":
+		cfg.Ollama.Model = model
+	default:
+		return cfg, fmt.Errorf("o provedor de LLM %q não aceita modelo em %q", provider, entry)
+	}
+	return cfg, nil
+}
+
+// GenerateResponse implementa domain.LLMClient
+func (c *FallbackClient) GenerateResponse(ctx context.Context, messages []domain.Message, tools []domain.Tool) (*domain.Message, error) {
+	var err error
+	for i, provider := range c.providers {
+		var msg *domain.Message
+		msg, err = provider.client.GenerateResponse(providerContext(ctx, i), messages, tools)
+		if !c.fallThrough(i, err) {
+			return msg, err
+		}
+	}
+	return nil, err
+}
+
+// GenerateResponseStream implementa domain.LLMClient. Só passa ao próximo
+// provedor se a abertura do streaming falhar: depois que os primeiros
+// pedaços foram entregues, a resposta não pode mais ser trocada.
+func (c *FallbackClient) GenerateResponseStream(ctx context.Context, messages []domain.Message, tools []domain.Tool) (<-chan domain.LLMChunk, error) {
+	var err error
+	for i, provider := range c.providers {
+		var chunks <-chan domain.LLMChunk
+		chunks, err = provider.client.GenerateResponseStream(providerContext(ctx, i), messages, tools)
+		if !c.fallThrough(i, err) {
+			return chunks, err
+		}
+	}
+	return nil, err
+}
+
+// fallThrough indica se a chamada deve passar ao provedor seguinte ao de
+// índice i, registrando a troca
+func (c *FallbackClient) fallThrough(i int, err error) bool {
+	if err == nil || i == len(c.providers)-1 || !isUnavailable(err) {
+		return false
+	}
+	log.Printf("Provedor de LLM %s indisponível, usando %s: %v", c.providers[i].name, c.providers[i+1].name, err)
+	return true
+}
+
+// providerContext retorna o contexto da chamada ao provedor de índice i,
+// sem o modelo pedido nos parâmetros de geração quando ele não é o primeiro
+func providerContext(ctx context.Context, i int) context.Context {
+	gen := domain.GenerationFromContext(ctx)
+	if i == 0 || gen.Model == "" {
+		return ctx
+	}
+	gen.Model = ""
+	return domain.WithGeneration(ctx, gen)
+}
+
+// isUnavailable indica se o erro significa que o provedor não pode atender
+// no momento, em vez de um problema na própria requisição
+func isUnavailable(err error) bool {
+	return domain.IsTemporaryError(err) || errors.Is(err, domain.ErrLLMUnavailable) || errors.Is(err, domain.ErrLLMQuotaExceeded)
+}
@@ -0,0 +1,309 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Valores padrão do cliente do Ollama
+const (
+	defaultOllamaBaseURL = "http://localhost:11434"
+	defaultOllamaModel   = "llama3.1"
+)
+
+// OllamaConfig contém as configurações do cliente do Ollama
+type OllamaConfig struct {
+	BaseURL string // Endereço do servidor do Ollama
+	Model   string // Modelo local (ex: "llama3.1", "mistral")
+}
+
+// OllamaClient implementa domain.LLMClient usando a API de chat do Ollama,
+// permitindo executar o agente com modelos locais, sem acesso à internet
+type OllamaClient struct {
+	httpClient *http.Client
+	baseURL    string
+	model      string
+}
+
+// NewOllamaClient cria um novo cliente do Ollama
+func NewOllamaClient(cfg OllamaConfig) *OllamaClient {
+	client := &OllamaClient{
+		httpClient: http.DefaultClient,
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		model:      cfg.Model,
+	}
+	if client.baseURL == "" {
+		client.baseURL = defaultOllamaBaseURL
+	}
+	if client.model == "" {
+		client.model = defaultOllamaModel
+	}
+	return client
+}
+
+// ollamaRequest é o corpo de uma chamada a /api/chat
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Tools    []ollamaTool    `json:"tools,omitempty"`
+	Format   map[string]any  `json:"format,omitempty"` // JSON Schema da resposta
+	Options  *ollamaOptions  `json:"options,omitempty"`
+	Stream   bool            `json:"stream"`
+}
+
+// ollamaOptions são os parâmetros de geração do Ollama
+type ollamaOptions struct {
+	NumPredict  int      `json:"num_predict,omitempty"` // Limite de tokens gerados
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	Seed        *int     `json:"seed,omitempty"`
+}
+
+// ollamaMessage é uma mensagem no formato do Ollama
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
+}
+
+// ollamaToolCall é uma chamada de ferramenta no formato do Ollama, cujos
+// argumentos são um objeto JSON e não uma string
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// ollamaTool descreve uma ferramenta no formato do Ollama
+type ollamaTool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		Parameters  map[string]any `json:"parameters"`
+	} `json:"function"`
+}
+
+// ollamaResponse é uma resposta (ou um pedaço, em streaming) de /api/chat
+type ollamaResponse struct {
+	Model   string        `json:"model"`
+	Message ollamaMessage `json:"message"`
+	Done    bool          `json:"done"`
+	Error   string        `json:"error"`
+
+	// Consumo de tokens, informado na última resposta
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+// usage retorna o consumo de tokens informado na resposta
+func (r ollamaResponse) usage() domain.TokenUsage {
+	return domain.TokenUsage{
+		Model:            r.Model,
+		PromptTokens:     r.PromptEvalCount,
+		CompletionTokens: r.EvalCount,
+	}
+}
+
+// GenerateResponse envia as mensagens ao modelo e retorna a mensagem gerada,
+// no formato do schema de resposta do contexto, quando há um
+func (c *OllamaClient) GenerateResponse(ctx context.Context, messages []domain.Message, tools []domain.Tool) (*domain.Message, error) {
+	req := c.buildRequest(ctx, messages, tools, false)
+	req.Format = domain.ResponseSchemaFromContext(ctx)
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta do Ollama: %w", err)
+	}
+
+	return &domain.Message{
+		Role:      domain.RoleAssistant,
+		Content:   body.Message.Content,
+		ToolCalls: fromOllamaToolCalls(body.Message.ToolCalls, 0),
+		Usage:     body.usage(),
+	}, nil
+}
+
+// GenerateResponseStream envia as mensagens ao modelo e retorna os pedaços
+// da resposta à medida que são gerados
+func (c *OllamaClient) GenerateResponseStream(ctx context.Context, messages []domain.Message, tools []domain.Tool) (<-chan domain.LLMChunk, error) {
+	resp, err := c.send(ctx, c.buildRequest(ctx, messages, tools, true))
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan domain.LLMChunk)
+	go func() {
+		defer close(chunks)
+		defer resp.Body.Close()
+
+		// send entrega o pedaço, desistindo se o contexto for cancelado
+		send := func(chunk domain.LLMChunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// O Ollama envia uma linha JSON por pedaço; as chamadas de ferramentas
+		// chegam completas, mas podem estar distribuídas em mais de uma linha
+		var toolCalls []domain.ToolCall
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var body ollamaResponse
+			if err := json.Unmarshal(scanner.Bytes(), &body); err != nil {
+				send(domain.LLMChunk{Err: fmt.Errorf("erro ao decodificar resposta do Ollama: %w", err)})
+				return
+			}
+			if body.Error != "" {
+				send(domain.LLMChunk{Err: fmt.Errorf("erro no streaming do Ollama: %s", body.Error)})
+				return
+			}
+
+			toolCalls = append(toolCalls, fromOllamaToolCalls(body.Message.ToolCalls, len(toolCalls))...)
+			if body.Message.Content != "" {
+				if !send(domain.LLMChunk{Content: body.Message.Content}) {
+					return
+				}
+			}
+			if body.Done {
+				send(domain.LLMChunk{ToolCalls: toolCalls, Done: true, Usage: body.usage()})
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			send(domain.LLMChunk{Err: fmt.Errorf("erro no streaming do Ollama: %w", err)})
+		}
+	}()
+
+	return chunks, nil
+}
+
+// buildRequest traduz as mensagens e ferramentas do domínio para o formato do Ollama
+func (c *OllamaClient) buildRequest(ctx context.Context, messages []domain.Message, tools []domain.Tool, stream bool) ollamaRequest {
+	gen := domain.GenerationFromContext(ctx)
+	req := ollamaRequest{
+		Model:  cmp.Or(gen.Model, c.model),
+		Stream: stream,
+	}
+	if gen.MaxTokens > 0 || gen.Temperature != nil || gen.TopP != nil || gen.Seed != nil {
+		req.Options = &ollamaOptions{
+			NumPredict:  gen.MaxTokens,
+			Temperature: gen.Temperature,
+			TopP:        gen.TopP,
+			Seed:        gen.Seed,
+		}
+	}
+
+	for _, m := range messages {
+		msg := ollamaMessage{
+			Role:    m.Role,
+			Content: m.Content,
+		}
+		if m.Role == domain.RoleTool {
+			msg.ToolName = m.Name
+		}
+		for _, tc := range m.ToolCalls {
+			var call ollamaToolCall
+			call.Function.Name = tc.Name
+			call.Function.Arguments = json.RawMessage(tc.Arguments)
+			if !json.Valid(call.Function.Arguments) {
+				call.Function.Arguments = json.RawMessage("{}")
+			}
+			msg.ToolCalls = append(msg.ToolCalls, call)
+		}
+		req.Messages = append(req.Messages, msg)
+	}
+
+	for _, t := range tools {
+		tool := ollamaTool{Type: "function"}
+		tool.Function.Name = t.Name
+		tool.Function.Description = t.Description
+		tool.Function.Parameters = t.Parameters
+		req.Tools = append(req.Tools, tool)
+	}
+	return req
+}
+
+// fromOllamaToolCalls converte as chamadas de ferramentas do Ollama. Como o
+// Ollama não identifica as chamadas, os IDs são gerados a partir da posição.
+func fromOllamaToolCalls(calls []ollamaToolCall, offset int) []domain.ToolCall {
+	var result []domain.ToolCall
+	for i, tc := range calls {
+		result = append(result, domain.ToolCall{
+			ID:        fmt.Sprintf("call_%d", offset+i),
+			Name:      tc.Function.Name,
+			Arguments: string(tc.Function.Arguments),
+		})
+	}
+	return result
+}
+
+// HealthCheck verifica se o Ollama está acessível listando os modelos locais
+func (c *OllamaClient) HealthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/tags", nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição para o Ollama: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar acesso ao Ollama: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return statusError(resp, fmt.Errorf("erro ao verificar acesso ao Ollama (status %d)", resp.StatusCode))
+	}
+	return nil
+}
+
+// send executa a chamada a /api/chat, convertendo respostas de erro
+func (c *OllamaClient) send(ctx context.Context, body ollamaRequest) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar requisição para o Ollama: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/chat", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição para o Ollama: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro na chamada ao Ollama: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+		var body ollamaResponse
+		if json.Unmarshal(raw, &body) == nil && body.Error != "" {
+			return nil, statusError(resp, fmt.Errorf("erro na chamada ao Ollama (status %d): %s", resp.StatusCode, body.Error))
+		}
+		return nil, statusError(resp, fmt.Errorf("erro na chamada ao Ollama (status %d): %s", resp.StatusCode, raw))
+	}
+
+	return resp, nil
+}
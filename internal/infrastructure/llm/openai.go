@@ -0,0 +1,399 @@
+package llm
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// OpenAIConfig contém as configurações do cliente da OpenAI
+type OpenAIConfig struct {
+	APIKey         string
+	Model          string // Modelo usado nas chamadas de chat
+	EmbeddingModel string // Modelo usado na geração de embeddings
+	// EmbeddingDimensions reduz a dimensão dos embeddings, nos modelos
+	// text-embedding-3; 0 usa a dimensão padrão do modelo
+	EmbeddingDimensions int
+
+	// Azure OpenAI: quando AzureEndpoint é informado, as chamadas são feitas
+	// ao recurso do Azure usando os deployments configurados
+	AzureEndpoint            string // ex: https://meu-recurso.openai.azure.com
+	AzureDeployment          string // Deployment usado nas chamadas de chat
+	AzureEmbeddingDeployment string // Deployment usado na geração de embeddings
+	AzureAPIVersion          string // ex: 2024-06-01
+	AzureUseEntraID          bool   // Autentica com token do Entra ID (Bearer) no lugar da api-key
+}
+
+// defaultAzureAPIVersion é a versão da API do Azure OpenAI usada por padrão
+const defaultAzureAPIVersion = "2024-06-01"
+
+// OpenAIClient implementa domain.LLMClient, domain.EmbeddingClient e
+// domain.ModerationClient usando a API da OpenAI
+type OpenAIClient struct {
+	client              *openai.Client
+	model               string
+	embeddingModel      string
+	embeddingDimensions int
+}
+
+// NewOpenAIClient cria um novo cliente da OpenAI
+func NewOpenAIClient(cfg OpenAIConfig) *OpenAIClient {
+	model := cfg.Model
+	if model == "" {
+		model = openai.GPT4TurboPreview
+	}
+	embeddingModel := cfg.EmbeddingModel
+	if embeddingModel == "" {
+		embeddingModel = string(openai.SmallEmbedding3)
+	}
+
+	clientConfig := openai.DefaultConfig(cfg.APIKey)
+	if cfg.AzureEndpoint != "" {
+		clientConfig = newAzureConfig(cfg, model, embeddingModel)
+	}
+
+	return &OpenAIClient{
+		client:              openai.NewClientWithConfig(clientConfig),
+		model:               model,
+		embeddingModel:      embeddingModel,
+		embeddingDimensions: cfg.EmbeddingDimensions,
+	}
+}
+
+// newAzureConfig monta a configuração de acesso ao Azure OpenAI. No Azure as
+// chamadas são roteadas pelo nome do deployment, e não pelo nome do modelo.
+func newAzureConfig(cfg OpenAIConfig, model, embeddingModel string) openai.ClientConfig {
+	clientConfig := openai.DefaultAzureConfig(cfg.APIKey, cfg.AzureEndpoint)
+	if cfg.AzureUseEntraID {
+		clientConfig.APIType = openai.APITypeAzureAD
+	}
+
+	clientConfig.APIVersion = cfg.AzureAPIVersion
+	if clientConfig.APIVersion == "" {
+		clientConfig.APIVersion = defaultAzureAPIVersion
+	}
+
+	defaultMapper := clientConfig.AzureModelMapperFunc
+	clientConfig.AzureModelMapperFunc = func(name string) string {
+		switch {
+		case name == model && cfg.AzureDeployment != "":
+			return cfg.AzureDeployment
+		case name == embeddingModel && cfg.AzureEmbeddingDeployment != "":
+			return cfg.AzureEmbeddingDeployment
+		}
+		return defaultMapper(name)
+	}
+	return clientConfig
+}
+
+// buildRequest monta a requisição de chat com as mensagens, as ferramentas e
+// os parâmetros de geração do contexto. No Azure, o modelo do contexto é o
+// nome do deployment.
+func (c *OpenAIClient) buildRequest(ctx context.Context, messages []domain.Message, tools []domain.Tool) openai.ChatCompletionRequest {
+	gen := domain.GenerationFromContext(ctx)
+	req := openai.ChatCompletionRequest{
+		Model:     cmp.Or(gen.Model, c.model),
+		Messages:  toOpenAIMessages(messages),
+		Tools:     toOpenAITools(tools),
+		MaxTokens: gen.MaxTokens,
+		Seed:      gen.Seed,
+	}
+	if gen.Temperature != nil {
+		// A biblioteca omite a temperatura zerada, que a API trataria como a
+		// padrão (1); o menor valor positivo mantém a geração determinística
+		req.Temperature = max(float32(*gen.Temperature), math.SmallestNonzeroFloat32)
+	}
+	if gen.TopP != nil {
+		req.TopP = float32(*gen.TopP)
+	}
+	return req
+}
+
+// GenerateResponse envia as mensagens ao modelo e retorna a mensagem gerada,
+// no formato do schema de resposta do contexto, quando há um
+func (c *OpenAIClient) GenerateResponse(ctx context.Context, messages []domain.Message, tools []domain.Tool) (*domain.Message, error) {
+	req := c.buildRequest(ctx, messages, tools)
+	req.ResponseFormat = toOpenAIResponseFormat(domain.ResponseSchemaFromContext(ctx))
+	resp, err := c.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("erro na chamada à OpenAI: %w", openAIError(err))
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("resposta da OpenAI sem escolhas")
+	}
+
+	msg := fromOpenAIMessage(resp.Choices[0].Message)
+	msg.Usage = fromOpenAIUsage(resp.Model, resp.Usage)
+	return &msg, nil
+}
+
+// GenerateResponseStream envia as mensagens ao modelo e retorna os pedaços
+// da resposta à medida que são gerados
+func (c *OpenAIClient) GenerateResponseStream(ctx context.Context, messages []domain.Message, tools []domain.Tool) (<-chan domain.LLMChunk, error) {
+	req := c.buildRequest(ctx, messages, tools)
+	// O consumo de tokens chega em um pedaço extra, antes do fim do streaming
+	req.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+	stream, err := c.client.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("erro na chamada à OpenAI: %w", openAIError(err))
+	}
+
+	chunks := make(chan domain.LLMChunk)
+	go func() {
+		defer close(chunks)
+		defer stream.Close()
+
+		// send entrega o pedaço, desistindo se o contexto for cancelado
+		send := func(chunk domain.LLMChunk) bool {
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// As chamadas de ferramentas chegam fragmentadas e são montadas pelo índice
+		var toolCalls []domain.ToolCall
+		var usage domain.TokenUsage
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				send(domain.LLMChunk{ToolCalls: toolCalls, Done: true, Usage: usage})
+				return
+			}
+			if err != nil {
+				send(domain.LLMChunk{Err: fmt.Errorf("erro no streaming da OpenAI: %w", err)})
+				return
+			}
+			if resp.Usage != nil {
+				usage = fromOpenAIUsage(resp.Model, *resp.Usage)
+			}
+			if len(resp.Choices) == 0 {
+				continue
+			}
+
+			delta := resp.Choices[0].Delta
+			for _, tc := range delta.ToolCalls {
+				index := len(toolCalls)
+				if tc.Index != nil {
+					index = *tc.Index
+				}
+				for len(toolCalls) <= index {
+					toolCalls = append(toolCalls, domain.ToolCall{})
+				}
+				if tc.ID != "" {
+					toolCalls[index].ID = tc.ID
+				}
+				toolCalls[index].Name += tc.Function.Name
+				toolCalls[index].Arguments += tc.Function.Arguments
+			}
+
+			if delta.Content != "" {
+				if !send(domain.LLMChunk{Content: delta.Content}) {
+					return
+				}
+			}
+		}
+	}()
+
+	return chunks, nil
+}
+
+// Embed gera um embedding para cada texto, na mesma ordem da entrada
+func (c *OpenAIClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	resp, err := c.client.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+		Input:      texts,
+		Model:      openai.EmbeddingModel(c.embeddingModel),
+		Dimensions: c.embeddingDimensions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar embeddings: %w", openAIError(err))
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("quantidade de embeddings inesperada: %d (esperado %d)", len(resp.Data), len(texts))
+	}
+
+	// A API identifica cada vetor pelo índice da entrada
+	vectors := make([][]float32, len(texts))
+	for _, e := range resp.Data {
+		if e.Index < 0 || e.Index >= len(vectors) {
+			return nil, fmt.Errorf("índice de embedding inválido: %d", e.Index)
+		}
+		vectors[e.Index] = e.Embedding
+	}
+	return vectors, nil
+}
+
+// openAIEmbeddingDimensions são as dimensões padrão dos modelos de embeddings da OpenAI
+var openAIEmbeddingDimensions = map[string]int{
+	string(openai.SmallEmbedding3): 1536,
+	string(openai.LargeEmbedding3): 3072,
+	string(openai.AdaEmbeddingV2):  1536,
+}
+
+// Dimensions implementa domain.EmbeddingClient: a dimensão configurada ou a
+// padrão do modelo, e 0 para modelos desconhecidos
+func (c *OpenAIClient) Dimensions() int {
+	if c.embeddingDimensions > 0 {
+		return c.embeddingDimensions
+	}
+	return openAIEmbeddingDimensions[c.embeddingModel]
+}
+
+// toOpenAIMessages converte as mensagens do domínio para o formato da OpenAI
+func toOpenAIMessages(messages []domain.Message) []openai.ChatCompletionMessage {
+	result := make([]openai.ChatCompletionMessage, 0, len(messages))
+	for _, m := range messages {
+		msg := openai.ChatCompletionMessage{
+			Role:       m.Role,
+			Content:    m.Content,
+			Name:       m.Name,
+			ToolCallID: m.ToolCallID,
+		}
+		for _, tc := range m.ToolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, openai.ToolCall{
+				ID:   tc.ID,
+				Type: openai.ToolTypeFunction,
+				Function: openai.FunctionCall{
+					Name:      tc.Name,
+					Arguments: tc.Arguments,
+				},
+			})
+		}
+		result = append(result, msg)
+	}
+	return result
+}
+
+// toOpenAITools converte as ferramentas do domínio para o formato da OpenAI
+func toOpenAITools(tools []domain.Tool) []openai.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	result := make([]openai.Tool, 0, len(tools))
+	for _, t := range tools {
+		result = append(result, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			},
+		})
+	}
+	return result
+}
+
+// jsonSchema permite enviar um schema do domínio no response_format
+type jsonSchema map[string]any
+
+// MarshalJSON implementa json.Marshaler
+func (s jsonSchema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any(s))
+}
+
+// toOpenAIResponseFormat pede a resposta no JSON Schema informado, ou
+// retorna nil para uma resposta em texto. O modo strict não é usado, pois
+// exige restrições que schemas comuns não seguem.
+func toOpenAIResponseFormat(schema map[string]any) *openai.ChatCompletionResponseFormat {
+	if schema == nil {
+		return nil
+	}
+	return &openai.ChatCompletionResponseFormat{
+		Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+		JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+			Name:   structuredAnswerTool,
+			Schema: jsonSchema(schema),
+		},
+	}
+}
+
+// fromOpenAIUsage converte o consumo de tokens informado pela OpenAI
+func fromOpenAIUsage(model string, u openai.Usage) domain.TokenUsage {
+	return domain.TokenUsage{
+		Model:            model,
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+	}
+}
+
+// fromOpenAIMessage converte uma mensagem da OpenAI para o formato do domínio
+func fromOpenAIMessage(m openai.ChatCompletionMessage) domain.Message {
+	msg := domain.Message{
+		Role:    m.Role,
+		Content: m.Content,
+	}
+	for _, tc := range m.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, domain.ToolCall{
+			ID:        tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: tc.Function.Arguments,
+		})
+	}
+	return msg
+}
+
+// HealthCheck verifica a chave listando os modelos disponíveis, sem gerar
+// tokens
+func (c *OpenAIClient) HealthCheck(ctx context.Context) error {
+	if _, err := c.client.ListModels(ctx); err != nil {
+		return fmt.Errorf("erro ao verificar acesso à OpenAI: %w", openAIError(err))
+	}
+	return nil
+}
+
+// Moderate classifica o texto com o endpoint de moderação da OpenAI
+func (c *OpenAIClient) Moderate(ctx context.Context, text string) (*domain.ModerationResult, error) {
+	resp, err := c.client.Moderations(ctx, openai.ModerationRequest{
+		Input: text,
+		Model: openai.ModerationOmniLatest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro na moderação: %w", openAIError(err))
+	}
+
+	result := &domain.ModerationResult{}
+	for _, r := range resp.Results {
+		if !r.Flagged {
+			continue
+		}
+		result.Flagged = true
+		result.Categories = append(result.Categories, flaggedCategories(r.Categories)...)
+	}
+	return result, nil
+}
+
+// flaggedCategories retorna os nomes das categorias sinalizadas, como a API os informa
+func flaggedCategories(categories openai.ResultCategories) []string {
+	raw, err := json.Marshal(categories)
+	if err != nil {
+		return nil
+	}
+	var flags map[string]bool
+	if err := json.Unmarshal(raw, &flags); err != nil {
+		return nil
+	}
+
+	var names []string
+	for name, flagged := range flags {
+		if flagged {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
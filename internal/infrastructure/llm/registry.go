@@ -0,0 +1,264 @@
+package llm
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/sashabaranov/go-openai"
+)
+
+// DefaultProvider é o provedor usado quando nenhum é configurado
+const DefaultProvider = "openai"
+
+// Config reúne as configurações de todos os provedores de LLM
+type Config struct {
+	Provider  string // Nome do provedor registrado (ex: "openai", "azure", "anthropic", "ollama")
+	OpenAI    OpenAIConfig
+	Azure     OpenAIConfig // Azure OpenAI, usando os campos Azure* da configuração
+	Anthropic AnthropicConfig
+	Ollama    OllamaConfig
+	Cohere    CohereConfig // Apenas embeddings
+	Voyage    VoyageConfig // Apenas embeddings
+	// EmbeddingProvider escolhe o provedor dos embeddings ("cohere" ou
+	// "voyage"); vazio usa o servidor próprio, o Azure OpenAI ou a OpenAI,
+	// nessa ordem
+	EmbeddingProvider string
+	// Embedding são os limites das requisições de embeddings
+	Embedding EmbeddingBatchConfig
+	// EmbeddingServer é o servidor de embeddings próprio, usado no lugar da
+	// OpenAI quando configurado
+	EmbeddingServer EmbeddingServerConfig
+
+	// Fallbacks são os provedores usados, em ordem, quando o anterior está
+	// indisponível ou sem cota, no formato "provedor" ou "provedor:modelo"
+	// (ex: "openai:gpt-4o-mini", "anthropic")
+	Fallbacks []string
+}
+
+// ConfigFromEnv lê a configuração dos provedores a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	embeddingDimensions, _ := strconv.Atoi(os.Getenv("EMBEDDING_DIMENSIONS"))
+	return Config{
+		Provider:          os.Getenv("LLM_PROVIDER"),
+		Fallbacks:         splitList(os.Getenv("LLM_FALLBACK")),
+		EmbeddingProvider: strings.ToLower(os.Getenv("EMBEDDING_PROVIDER")),
+		Embedding:         embeddingBatchConfigFromEnv(),
+		EmbeddingServer: EmbeddingServerConfig{
+			BaseURL:    os.Getenv("EMBEDDING_BASE_URL"),
+			Format:     strings.ToLower(os.Getenv("EMBEDDING_API_FORMAT")),
+			APIKey:     os.Getenv("EMBEDDING_API_KEY"),
+			Model:      os.Getenv("EMBEDDING_MODEL"),
+			Dimensions: embeddingDimensions,
+		},
+		OpenAI: OpenAIConfig{
+			APIKey:              os.Getenv("OPENAI_API_KEY"),
+			Model:               os.Getenv("OPENAI_MODEL"),
+			EmbeddingDimensions: embeddingDimensions,
+		},
+		Azure: OpenAIConfig{
+			APIKey:                   os.Getenv("AZURE_OPENAI_API_KEY"),
+			AzureEndpoint:            os.Getenv("AZURE_OPENAI_ENDPOINT"),
+			AzureDeployment:          os.Getenv("AZURE_OPENAI_DEPLOYMENT"),
+			AzureEmbeddingDeployment: os.Getenv("AZURE_OPENAI_EMBEDDING_DEPLOYMENT"),
+			AzureAPIVersion:          os.Getenv("AZURE_OPENAI_API_VERSION"),
+			AzureUseEntraID:          os.Getenv("AZURE_OPENAI_USE_ENTRA_ID") == "true",
+			EmbeddingDimensions:      embeddingDimensions,
+		},
+		Cohere: CohereConfig{
+			APIKey:              os.Getenv("COHERE_API_KEY"),
+			EmbeddingModel:      os.Getenv("COHERE_EMBEDDING_MODEL"),
+			EmbeddingDimensions: embeddingDimensions,
+		},
+		Voyage: VoyageConfig{
+			APIKey:              os.Getenv("VOYAGE_API_KEY"),
+			EmbeddingModel:      os.Getenv("VOYAGE_EMBEDDING_MODEL"),
+			EmbeddingDimensions: embeddingDimensions,
+		},
+		Anthropic: AnthropicConfig{
+			APIKey: os.Getenv("ANTHROPIC_API_KEY"),
+			Model:  os.Getenv("ANTHROPIC_MODEL"),
+		},
+		Ollama: OllamaConfig{
+			BaseURL: os.Getenv("OLLAMA_BASE_URL"),
+			Model:   os.Getenv("OLLAMA_MODEL"),
+		},
+	}
+}
+
+// Model retorna o modelo de chat usado por cfg.Provider, com o padrão do
+// provedor quando não configurado. No Azure é o nome do deployment; para
+// provedores registrados fora deste pacote, retorna vazio.
+func (cfg Config) Model() string {
+	switch strings.ToLower(cfg.Provider) {
+	case "", DefaultProvider:
+		return cmp.Or(cfg.OpenAI.Model, openai.GPT4TurboPreview)
+	case "azure":
+		return cfg.Azure.AzureDeployment
+	case "anthropic":
+		return cmp.Or(cfg.Anthropic.Model, defaultAnthropicModel)
+	case "ollama":
+		return cmp.Or(cfg.Ollama.Model, defaultOllamaModel)
+	default:
+		return ""
+	}
+}
+
+// NewEmbeddingClient cria o cliente de embeddings disponível na configuração:
+// o provedor de cfg.EmbeddingProvider quando informado; senão o servidor
+// próprio quando há EMBEDDING_BASE_URL, o Azure OpenAI quando ele é o
+// provedor e possui deployment de embeddings, ou a OpenAI quando há chave. As
+// listas de textos são divididas em lotes conforme cfg.Embedding. Retorna nil
+// se nenhum estiver disponível.
+func NewEmbeddingClient(cfg Config) domain.EmbeddingClient {
+	batch := cfg.Embedding
+	switch cfg.EmbeddingProvider {
+	case "cohere":
+		if cfg.Cohere.APIKey == "" {
+			return nil
+		}
+		if batch.MaxTexts <= 0 {
+			batch.MaxTexts = defaultCohereBatchSize
+		}
+		return NewBatchEmbeddingClient(NewCohereClient(cfg.Cohere), batch)
+	case "voyage":
+		if cfg.Voyage.APIKey == "" {
+			return nil
+		}
+		if batch.MaxTexts <= 0 {
+			batch.MaxTexts = defaultVoyageBatchSize
+		}
+		return NewBatchEmbeddingClient(NewVoyageClient(cfg.Voyage), batch)
+	}
+
+	if cfg.EmbeddingServer.BaseURL != "" {
+		if cfg.EmbeddingServer.Format == EmbeddingFormatTEI && batch.MaxTexts <= 0 {
+			batch.MaxTexts = defaultTEIBatchSize
+		}
+		return NewBatchEmbeddingClient(NewEmbeddingServerClient(cfg.EmbeddingServer), batch)
+	}
+	if strings.EqualFold(cfg.Provider, "azure") && cfg.Azure.AzureEmbeddingDeployment != "" {
+		return NewBatchEmbeddingClient(NewOpenAIClient(cfg.Azure), batch)
+	}
+	if cfg.OpenAI.APIKey != "" {
+		return NewBatchEmbeddingClient(NewOpenAIClient(cfg.OpenAI), batch)
+	}
+	return nil
+}
+
+// NewModerationClient cria o cliente do endpoint de moderação da OpenAI,
+// quando há chave. Retorna nil se não estiver disponível.
+func NewModerationClient(cfg Config) domain.ModerationClient {
+	if cfg.OpenAI.APIKey != "" {
+		return NewOpenAIClient(cfg.OpenAI)
+	}
+	return nil
+}
+
+// Factory cria um cliente de LLM a partir da configuração
+type Factory func(cfg Config) (domain.LLMClient, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register registra um provedor de LLM pelo nome. Registrar o mesmo nome
+// duas vezes substitui o provedor anterior.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[strings.ToLower(name)] = factory
+}
+
+// Providers retorna os nomes dos provedores registrados, em ordem alfabética
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New cria o cliente do provedor configurado em cfg.Provider, seguido dos
+// provedores de cfg.Fallbacks quando houver
+func New(cfg Config) (domain.LLMClient, error) {
+	return NewChain(cfg, nil)
+}
+
+// HealthChecker é implementado pelos provedores que verificam o acesso (a
+// chave e a disponibilidade da API) sem gerar uma resposta
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// NewHealthChecker cria um cliente do provedor configurado em cfg.Provider
+// para as verificações de prontidão, sem as repetições nem os fallbacks das
+// chamadas. Retorna nil quando o provedor não oferece a verificação.
+func NewHealthChecker(cfg Config) (HealthChecker, error) {
+	client, err := newProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	checker, _ := client.(HealthChecker)
+	return checker, nil
+}
+
+// newProvider cria o cliente do provedor configurado em cfg.Provider
+func newProvider(cfg Config) (domain.LLMClient, error) {
+	name := strings.ToLower(cfg.Provider)
+	if name == "" {
+		name = DefaultProvider
+	}
+
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("provedor de LLM desconhecido: %q (disponíveis: %s)", name, strings.Join(Providers(), ", "))
+	}
+
+	return factory(cfg)
+}
+
+// splitList separa uma lista de valores separados por vírgula, ignorando os vazios
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func init() {
+	Register("openai", func(cfg Config) (domain.LLMClient, error) {
+		return NewOpenAIClient(cfg.OpenAI), nil
+	})
+	Register("azure", func(cfg Config) (domain.LLMClient, error) {
+		if cfg.Azure.AzureEndpoint == "" || cfg.Azure.AzureDeployment == "" {
+			return nil, fmt.Errorf("AZURE_OPENAI_ENDPOINT e AZURE_OPENAI_DEPLOYMENT são obrigatórias")
+		}
+		return NewOpenAIClient(cfg.Azure), nil
+	})
+	Register("anthropic", func(cfg Config) (domain.LLMClient, error) {
+		if cfg.Anthropic.APIKey == "" {
+			return nil, fmt.Errorf("ANTHROPIC_API_KEY não definida")
+		}
+		return NewAnthropicClient(cfg.Anthropic), nil
+	})
+	Register("ollama", func(cfg Config) (domain.LLMClient, error) {
+		return NewOllamaClient(cfg.Ollama), nil
+	})
+}
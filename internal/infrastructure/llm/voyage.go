@@ -0,0 +1,152 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Valores padrão do cliente da Voyage AI
+const (
+	defaultVoyageBaseURL        = "https://api.voyageai.com/v1"
+	defaultVoyageEmbeddingModel = "voyage-3.5"
+	// defaultVoyageBatchSize mantém os lotes abaixo do limite de tokens por
+	// requisição dos modelos menores (120 mil)
+	defaultVoyageBatchSize = 128
+)
+
+// VoyageConfig contém as configurações do cliente de embeddings da Voyage AI
+type VoyageConfig struct {
+	APIKey  string
+	BaseURL string // Endereço da API (padrão: https://api.voyageai.com/v1)
+	// EmbeddingModel é o modelo dos embeddings, como voyage-3.5 ou, nas bases
+	// de código, voyage-code-3
+	EmbeddingModel string
+	// EmbeddingDimensions reduz a dimensão dos embeddings, nos modelos que
+	// aceitam (256, 512, 1024 ou 2048); 0 usa a dimensão padrão do modelo
+	EmbeddingDimensions int
+}
+
+// VoyageClient implementa domain.EmbeddingClient usando a API da Voyage AI
+type VoyageClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	model      string
+	dimensions int
+}
+
+// NewVoyageClient cria um novo cliente da Voyage AI
+func NewVoyageClient(cfg VoyageConfig) *VoyageClient {
+	c := &VoyageClient{
+		httpClient: http.DefaultClient,
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:     cfg.APIKey,
+		model:      cfg.EmbeddingModel,
+		dimensions: cfg.EmbeddingDimensions,
+	}
+	if c.baseURL == "" {
+		c.baseURL = defaultVoyageBaseURL
+	}
+	if c.model == "" {
+		c.model = defaultVoyageEmbeddingModel
+	}
+	return c
+}
+
+// voyageEmbedRequest é o corpo de uma chamada a /embeddings
+type voyageEmbedRequest struct {
+	Input           []string `json:"input"`
+	Model           string   `json:"model"`
+	InputType       string   `json:"input_type"`
+	OutputDimension int      `json:"output_dimension,omitempty"`
+	Truncation      bool     `json:"truncation"`
+}
+
+// Embed gera um embedding para cada texto, na mesma ordem da entrada. Os
+// textos são tratados como consultas quando o contexto foi marcado com
+// domain.WithQueryEmbedding, e como documentos nos demais casos.
+func (c *VoyageClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	inputType := "document"
+	if domain.IsQueryEmbedding(ctx) {
+		inputType = "query"
+	}
+	payload, err := json.Marshal(voyageEmbedRequest{
+		Input:           texts,
+		Model:           c.model,
+		InputType:       inputType,
+		OutputDimension: c.dimensions,
+		Truncation:      true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar requisição à Voyage AI: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/embeddings", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição à Voyage AI: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar embeddings na Voyage AI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, statusError(resp, fmt.Errorf("erro ao gerar embeddings na Voyage AI (status %d): %s", resp.StatusCode, raw))
+	}
+
+	// A resposta segue o formato da OpenAI
+	var result openAIEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta da Voyage AI: %w", err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("quantidade de embeddings inesperada: %d (esperado %d)", len(result.Data), len(texts))
+	}
+	vectors := make([][]float32, len(texts))
+	for _, e := range result.Data {
+		if e.Index < 0 || e.Index >= len(vectors) {
+			return nil, fmt.Errorf("índice de embedding inválido: %d", e.Index)
+		}
+		vectors[e.Index] = e.Embedding
+	}
+	return vectors, nil
+}
+
+// voyageEmbeddingDimensions são as dimensões padrão dos modelos de embeddings da Voyage AI
+var voyageEmbeddingDimensions = map[string]int{
+	"voyage-3.5":            1024,
+	"voyage-3.5-lite":       1024,
+	"voyage-3-large":        1024,
+	"voyage-3":              1024,
+	"voyage-3-lite":         512,
+	"voyage-code-3":         1024,
+	"voyage-code-2":         1536,
+	"voyage-finance-2":      1024,
+	"voyage-law-2":          1024,
+	"voyage-multilingual-2": 1024,
+}
+
+// Dimensions implementa domain.EmbeddingClient: a dimensão configurada ou a
+// padrão do modelo, e 0 para modelos desconhecidos
+func (c *VoyageClient) Dimensions() int {
+	if c.dimensions > 0 {
+		return c.dimensions
+	}
+	return voyageEmbeddingDimensions[c.model]
+}
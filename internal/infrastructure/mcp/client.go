@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	protocol "github.com/alextavella/agentic-rag/internal/mcp"
+	"github.com/alextavella/agentic-rag/internal/tools"
+)
+
+// clientInfo identifica o cliente no initialize
+var clientInfo = protocol.Implementation{Name: "agentic-rag", Version: "1.0.0"}
+
+// maxToolNameLength é o maior nome de ferramenta aceito pelos provedores de LLM
+const maxToolNameLength = 64
+
+// invalidToolChars reconhece os caracteres não aceitos nos nomes das ferramentas
+var invalidToolChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Client é a conexão com um servidor MCP
+type Client struct {
+	name      string
+	transport transport
+	nextID    atomic.Int64
+}
+
+// Connect conecta ao servidor e faz a inicialização do protocolo
+func Connect(ctx context.Context, cfg ServerConfig) (*Client, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	c := &Client{name: cfg.Name}
+	if cfg.Command != "" {
+		t, err := startStdio(cfg)
+		if err != nil {
+			return nil, err
+		}
+		c.transport = t
+	} else {
+		c.transport = newHTTPTransport(cfg)
+	}
+
+	var result protocol.InitializeResult
+	err := c.call(ctx, "initialize", protocol.InitializeParams{
+		ProtocolVersion: protocol.ProtocolVersion,
+		Capabilities:    map[string]any{},
+		ClientInfo:      clientInfo,
+	}, &result)
+	if err == nil {
+		err = c.transport.notify(ctx, protocol.Request{JSONRPC: "2.0", Method: "notifications/initialized"})
+	}
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("erro ao inicializar o servidor MCP %s: %w", cfg.Name, err)
+	}
+	return c, nil
+}
+
+// Name retorna o nome do servidor
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Close encerra a conexão e, no stdio, o processo do servidor
+func (c *Client) Close() error {
+	return c.transport.close()
+}
+
+// ListTools retorna as ferramentas do servidor, percorrendo todas as páginas
+func (c *Client) ListTools(ctx context.Context) ([]protocol.Tool, error) {
+	var all []protocol.Tool
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		var page protocol.ListToolsResult
+		if err := c.call(ctx, "tools/list", params, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Tools...)
+		if page.NextCursor == "" {
+			return all, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool executa uma ferramenta do servidor com os argumentos em JSON
+func (c *Client) CallTool(ctx context.Context, name, arguments string) (*protocol.CallToolResult, error) {
+	if arguments == "" {
+		arguments = "{}"
+	}
+	var result protocol.CallToolResult
+	params := protocol.CallToolParams{Name: name, Arguments: json.RawMessage(arguments)}
+	if err := c.call(ctx, "tools/call", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// call envia a requisição e decodifica o resultado em out
+func (c *Client) call(ctx context.Context, method string, params, out any) error {
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	id := json.RawMessage(strconv.FormatInt(c.nextID.Add(1), 10))
+	resp, err := c.transport.call(ctx, protocol.Request{JSONRPC: "2.0", ID: id, Method: method, Params: payload})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("erro do servidor MCP %s em %s: %w", c.name, method, resp.Error)
+	}
+	if err := json.Unmarshal(resp.Result, out); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta de %s: %w", method, err)
+	}
+	return nil
+}
+
+// RegisterTools conecta aos servidores e registra as ferramentas de cada um
+// no registro, com o nome do servidor como prefixo (como jira_search). Um
+// servidor que não responde é ignorado, com um aviso no log, para não
+// impedir a inicialização. Os clientes devem ser encerrados com Close.
+func RegisterTools(ctx context.Context, registry *tools.Registry, servers []ServerConfig) []*Client {
+	var clients []*Client
+	for _, cfg := range servers {
+		client, err := Connect(ctx, cfg)
+		if err != nil {
+			log.Printf("Aviso: servidor MCP %s ignorado: %v", cfg.Name, err)
+			continue
+		}
+		serverTools, err := client.ListTools(ctx)
+		if err != nil {
+			log.Printf("Aviso: servidor MCP %s ignorado: %v", cfg.Name, err)
+			client.Close()
+			continue
+		}
+
+		registered := 0
+		for _, tool := range serverTools {
+			name := toolName(cfg.Name, tool.Name)
+			if err := registry.Register(domain.Tool{
+				Name:        name,
+				Description: tool.Description,
+				Parameters:  inputSchema(tool.InputSchema),
+			}, client.handler(tool.Name)); err != nil {
+				log.Printf("Aviso: ferramenta %s do servidor MCP %s ignorada: %v", tool.Name, cfg.Name, err)
+				continue
+			}
+			registered++
+		}
+		log.Printf("Servidor MCP %s conectado: %d ferramentas registradas", cfg.Name, registered)
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// handler cria o handler que executa a ferramenta no servidor. Erros
+// informados pela ferramenta são devolvidos como erro, para que o agente os veja.
+func (c *Client) handler(name string) domain.ToolHandler {
+	return func(ctx context.Context, arguments string) (*domain.ToolResult, error) {
+		result, err := c.CallTool(ctx, name, arguments)
+		if err != nil {
+			return nil, err
+		}
+		if result.IsError {
+			return nil, fmt.Errorf("erro na ferramenta %s do servidor MCP %s: %s", name, c.name, result.Text())
+		}
+		return &domain.ToolResult{Content: result.Text()}, nil
+	}
+}
+
+// toolName forma o nome da ferramenta no registro, com os caracteres e o
+// tamanho aceitos pelos provedores de LLM
+func toolName(server, tool string) string {
+	name := server + "_" + invalidToolChars.ReplaceAllString(tool, "_")
+	if len(name) > maxToolNameLength {
+		name = name[:maxToolNameLength]
+	}
+	return name
+}
+
+// inputSchema garante um schema de objeto, exigido pelos provedores de LLM
+// mesmo para ferramentas sem parâmetros
+func inputSchema(schema map[string]any) map[string]any {
+	if schema == nil {
+		return map[string]any{"type": "object", "properties": map[string]any{}}
+	}
+	return schema
+}
@@ -0,0 +1,65 @@
+// Package mcp é o cliente do Model Context Protocol: conecta aos servidores
+// MCP configurados, descobre as ferramentas de cada um e as registra para o
+// agente, que passa a usá-las sem mudanças no código
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// namePattern restringe os nomes dos servidores, usados como prefixo das
+// ferramentas, aos caracteres aceitos pelos provedores de LLM
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,32}$`)
+
+// ServerConfig é um servidor MCP. Com Command, o servidor é iniciado como um
+// processo e usa o transporte stdio; com URL, usa o transporte HTTP.
+type ServerConfig struct {
+	Name    string            `json:"name"`              // Prefixo das ferramentas do servidor
+	Command string            `json:"command,omitempty"` // Programa do servidor (stdio)
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`     // Variáveis acrescentadas ao ambiente do processo
+	URL     string            `json:"url,omitempty"`     // Endpoint do servidor (HTTP)
+	Headers map[string]string `json:"headers,omitempty"` // Cabeçalhos das requisições HTTP, como Authorization
+}
+
+// ConfigFromEnv lê os servidores do arquivo JSON em MCP_SERVERS_FILE. Sem o
+// arquivo, nenhum servidor é usado.
+func ConfigFromEnv() ([]ServerConfig, error) {
+	path := os.Getenv("MCP_SERVERS_FILE")
+	if path == "" {
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler servidores MCP: %w", err)
+	}
+	var servers []ServerConfig
+	if err := json.Unmarshal(raw, &servers); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar servidores MCP: %w", err)
+	}
+	seen := map[string]bool{}
+	for i, server := range servers {
+		if err := server.validate(); err != nil {
+			return nil, fmt.Errorf("servidor MCP %d de %s: %w", i+1, path, err)
+		}
+		if seen[server.Name] {
+			return nil, fmt.Errorf("servidor MCP %q repetido em %s", server.Name, path)
+		}
+		seen[server.Name] = true
+	}
+	return servers, nil
+}
+
+// validate verifica o nome e o transporte do servidor
+func (c ServerConfig) validate() error {
+	if !namePattern.MatchString(c.Name) {
+		return fmt.Errorf("nome inválido: %q (use letras, números, _ e -)", c.Name)
+	}
+	if (c.Command == "") == (c.URL == "") {
+		return fmt.Errorf("informe command ou url")
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	protocol "github.com/alextavella/agentic-rag/internal/mcp"
+)
+
+// maxMessageSize limita o tamanho de cada mensagem recebida do servidor
+const maxMessageSize = 10 << 20
+
+// transport envia as mensagens JSON-RPC ao servidor
+type transport interface {
+	// call envia a requisição e espera a resposta
+	call(ctx context.Context, req protocol.Request) (*protocol.Response, error)
+	// notify envia uma notificação, que não tem resposta
+	notify(ctx context.Context, req protocol.Request) error
+	close() error
+}
+
+// stdioTransport conversa com um servidor iniciado como processo, uma
+// mensagem JSON por linha na entrada e na saída padrão
+type stdioTransport struct {
+	name  string
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	pending map[string]chan *protocol.Response
+	done    chan struct{} // Fechado quando o processo encerra a saída
+}
+
+// startStdio inicia o processo do servidor
+func startStdio(cfg ServerConfig) (*stdioTransport, error) {
+	cmd := exec.Command(cfg.Command, cfg.Args...)
+	cmd.Env = os.Environ()
+	for key, value := range cfg.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("erro ao iniciar o servidor: %w", err)
+	}
+
+	t := &stdioTransport{
+		name:    cfg.Name,
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: map[string]chan *protocol.Response{},
+		done:    make(chan struct{}),
+	}
+	go t.read(stdout)
+	return t, nil
+}
+
+// read entrega as respostas às chamadas pendentes e responde às
+// requisições do servidor, até o fim da saída do processo
+func (t *stdioTransport) read(stdout io.Reader) {
+	defer close(t.done)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		var msg struct {
+			protocol.Response
+			Method string `json:"method"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			log.Printf("Aviso: mensagem inválida do servidor MCP %s: %v", t.name, err)
+			continue
+		}
+		if msg.Method != "" {
+			t.answer(msg.ID, msg.Method)
+			continue
+		}
+
+		t.mu.Lock()
+		ch, ok := t.pending[string(msg.ID)]
+		delete(t.pending, string(msg.ID))
+		t.mu.Unlock()
+		if ok {
+			ch <- &msg.Response
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("Aviso: erro ao ler o servidor MCP %s: %v", t.name, err)
+	}
+}
+
+// answer responde às requisições do servidor: ping é atendido e os demais
+// métodos, como sampling, não são suportados. Notificações são ignoradas.
+func (t *stdioTransport) answer(id json.RawMessage, method string) {
+	if len(id) == 0 {
+		return
+	}
+	resp := protocol.Response{JSONRPC: "2.0", ID: id}
+	if method == "ping" {
+		resp.Result = json.RawMessage("{}")
+	} else {
+		resp.Error = &protocol.Error{Code: protocol.CodeMethodNotFound, Message: "método não suportado: " + method}
+	}
+	if err := t.write(resp); err != nil {
+		log.Printf("Aviso: erro ao responder ao servidor MCP %s: %v", t.name, err)
+	}
+}
+
+// write envia uma mensagem ao processo
+func (t *stdioTransport) write(msg any) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err = t.stdin.Write(append(payload, '\n'))
+	return err
+}
+
+func (t *stdioTransport) call(ctx context.Context, req protocol.Request) (*protocol.Response, error) {
+	ch := make(chan *protocol.Response, 1)
+	t.mu.Lock()
+	t.pending[string(req.ID)] = ch
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, string(req.ID))
+		t.mu.Unlock()
+	}()
+
+	if err := t.write(req); err != nil {
+		return nil, fmt.Errorf("erro ao enviar ao servidor: %w", err)
+	}
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-t.done:
+		return nil, fmt.Errorf("o servidor foi encerrado")
+	case <-ctx.Done():
+		// Avisa o servidor para interromper a requisição
+		t.notify(context.Background(), protocol.Request{
+			JSONRPC: "2.0",
+			Method:  "notifications/cancelled",
+			Params:  json.RawMessage(fmt.Sprintf(`{"requestId":%s}`, req.ID)),
+		})
+		return nil, ctx.Err()
+	}
+}
+
+func (t *stdioTransport) notify(_ context.Context, req protocol.Request) error {
+	return t.write(req)
+}
+
+// close fecha a entrada do processo, o que encerra os servidores stdio, e
+// espera o processo terminar
+func (t *stdioTransport) close() error {
+	t.stdin.Close()
+	return t.cmd.Wait()
+}
+
+// httpTransport conversa com um servidor pelo transporte HTTP: cada
+// mensagem é um POST, e a resposta vem em JSON ou em um stream SSE
+type httpTransport struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+
+	mu        sync.Mutex
+	sessionID string // Mcp-Session-Id informado pelo servidor no initialize
+}
+
+// newHTTPTransport cria o transporte HTTP
+func newHTTPTransport(cfg ServerConfig) *httpTransport {
+	return &httpTransport{client: http.DefaultClient, url: cfg.URL, headers: maps.Clone(cfg.Headers)}
+}
+
+func (t *httpTransport) call(ctx context.Context, req protocol.Request) (*protocol.Response, error) {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return readEventStream(resp.Body, req.ID)
+	}
+	var result protocol.Response
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxMessageSize)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta: %w", err)
+	}
+	return &result, nil
+}
+
+func (t *httpTransport) notify(ctx context.Context, req protocol.Request) error {
+	resp, err := t.post(ctx, req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// post envia a mensagem e verifica o status da resposta
+func (t *httpTransport) post(ctx context.Context, msg protocol.Request) (*http.Response, error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("MCP-Protocol-Version", protocol.ProtocolVersion)
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	t.mu.Lock()
+	if t.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", t.sessionID)
+	}
+	t.mu.Unlock()
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro na requisição ao servidor: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, fmt.Errorf("erro na requisição ao servidor (status %d): %s", resp.StatusCode, raw)
+	}
+	if session := resp.Header.Get("Mcp-Session-Id"); session != "" {
+		t.mu.Lock()
+		t.sessionID = session
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// close encerra a sessão no servidor, quando há uma
+func (t *httpTransport) close() error {
+	t.mu.Lock()
+	session := t.sessionID
+	t.mu.Unlock()
+	if session == "" {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Mcp-Session-Id", session)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// readEventStream lê os eventos SSE até a resposta com o ID da requisição;
+// notificações e requisições do servidor enviadas no stream são ignoradas
+func readEventStream(body io.Reader, id json.RawMessage) (*protocol.Response, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data.WriteString(strings.TrimPrefix(value, " "))
+			continue
+		}
+		if line != "" || data.Len() == 0 {
+			continue
+		}
+
+		// Linha vazia: fim do evento
+		var msg struct {
+			protocol.Response
+			Method string `json:"method"`
+		}
+		err := json.Unmarshal([]byte(data.String()), &msg)
+		data.Reset()
+		if err == nil && msg.Method == "" && bytes.Equal(msg.ID, id) {
+			return &msg.Response, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler eventos: %w", err)
+	}
+	return nil, fmt.Errorf("stream encerrado sem a resposta")
+}
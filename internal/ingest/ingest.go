@@ -0,0 +1,329 @@
+package ingest
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/alextavella/agentic-rag/internal/chunking"
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// insertBatchSize é a quantidade máxima de chunks gravados por chamada a InsertMany
+const insertBatchSize = 500
+
+// Ingester insere documentos na base: divide os longos em chunks, gera os
+// embeddings e descarta as respostas em cache ao final
+type Ingester struct {
+	repo     domain.DocumentRepository
+	splitter chunking.Splitter      // Opcional: divide documentos longos em chunks
+	embedder domain.EmbeddingClient // Opcional: gera os embeddings da busca vetorial
+	cache    domain.ResponseCache   // Opcional: invalidado após a inserção
+	queries  domain.QueryCache      // Opcional: invalidado após a inserção
+	replacer LinkDeleter            // Opcional: substitui documentos com o mesmo link
+	events   domain.EventPublisher  // Opcional: notifica os documentos gravados e os que falharam
+	audit    domain.AuditRepository // Opcional: registra os documentos gravados e os que falharam
+	strict   bool                   // Retorna erro quando algum lote não é gravado
+}
+
+// LinkDeleter remove os documentos já indexados a partir de um link
+type LinkDeleter interface {
+	DeleteByLink(ctx context.Context, link string) error
+}
+
+// Option configura dependências opcionais do Ingester
+type Option func(*Ingester)
+
+// WithSplitter habilita a divisão de documentos longos em chunks
+func WithSplitter(splitter chunking.Splitter) Option {
+	return func(i *Ingester) {
+		i.splitter = splitter
+	}
+}
+
+// WithEmbeddingClient habilita a geração de embeddings para a busca vetorial
+func WithEmbeddingClient(embedder domain.EmbeddingClient) Option {
+	return func(i *Ingester) {
+		i.embedder = embedder
+	}
+}
+
+// WithResponseCache invalida o cache de respostas após cada ingestão
+func WithResponseCache(cache domain.ResponseCache) Option {
+	return func(i *Ingester) {
+		i.cache = cache
+	}
+}
+
+// WithQueryCache invalida o cache de perguntas idênticas após cada ingestão
+func WithQueryCache(cache domain.QueryCache) Option {
+	return func(i *Ingester) {
+		i.queries = cache
+	}
+}
+
+// WithReplaceByLink faz com que cada documento substitua os já indexados com
+// o mesmo link, permitindo reindexar páginas sem duplicá-las
+func WithReplaceByLink(deleter LinkDeleter) Option {
+	return func(i *Ingester) {
+		i.replacer = deleter
+	}
+}
+
+// WithEventPublisher publica document.ingested para cada documento gravado e
+// document.failed para os que não puderam ser gravados
+func WithEventPublisher(events domain.EventPublisher) Option {
+	return func(i *Ingester) {
+		i.events = events
+	}
+}
+
+// WithAuditLog registra no log de auditoria a inserção de cada documento,
+// com o erro quando ele não pôde ser gravado
+func WithAuditLog(repo domain.AuditRepository) Option {
+	return func(i *Ingester) {
+		i.audit = repo
+	}
+}
+
+// WithStrictBatches faz Ingest retornar erro quando algum lote de chunks não
+// é gravado, depois de tentar os demais, para que quem chamou possa repetir
+// a ingestão. Repetir é seguro: os documentos já gravados são ignorados pelo
+// hash de conteúdo.
+func WithStrictBatches() Option {
+	return func(i *Ingester) {
+		i.strict = true
+	}
+}
+
+// New cria um Ingester que grava no repositório informado
+func New(repo domain.DocumentRepository, opts ...Option) *Ingester {
+	i := &Ingester{repo: repo}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// Ingest insere os documentos e retorna quantos chunks foram gravados.
+// Documentos cujo conteúdo já está na base (ou repetido na própria lista)
+// são ignorados, sem gerar embeddings nem substituir os do mesmo link.
+// Os chunks são gravados em lotes; falhas em um lote são registradas e não
+// interrompem os demais (com WithStrictBatches, também são retornadas).
+func (i *Ingester) Ingest(ctx context.Context, documents []domain.Document) (int, error) {
+	documents, err := i.dedupe(ctx, documents)
+	if err != nil {
+		return 0, err
+	}
+
+	// owners guarda o índice, em documents, do documento de cada chunk
+	chunks := documents
+	owners := make([]int, len(documents))
+	for j := range owners {
+		owners[j] = j
+	}
+	if i.splitter != nil {
+		chunks, owners = nil, nil
+		for j, doc := range documents {
+			parts := chunking.ChunkDocument(doc, i.splitter, rand.Text())
+			chunks = append(chunks, parts...)
+			for range parts {
+				owners = append(owners, j)
+			}
+		}
+	}
+
+	if err := i.embed(ctx, chunks); err != nil {
+		i.publishFailed(ctx, documents, err)
+		return 0, err
+	}
+
+	// Os documentos antigos só são removidos depois que os novos embeddings
+	// foram gerados, para não perder a página se a geração falhar
+	if err := i.replace(ctx, documents); err != nil {
+		i.publishFailed(ctx, documents, err)
+		return 0, err
+	}
+
+	inserted := 0
+	failures := make(map[int]error) // Erro do lote dos documentos com chunks não gravados
+	var batchErrs []error
+	for start := 0; start < len(chunks); start += insertBatchSize {
+		batch := make([]*domain.Document, 0, insertBatchSize)
+		for j := start; j < min(start+insertBatchSize, len(chunks)); j++ {
+			chunks[j].ID = "" // Preenchido pelo repositório apenas se gravado
+			batch = append(batch, &chunks[j])
+		}
+		err := i.repo.InsertMany(ctx, batch)
+		for k, chunk := range batch {
+			if chunk.ID != "" {
+				inserted++
+			} else if err != nil {
+				failures[owners[start+k]] = err
+			}
+		}
+		if err != nil {
+			log.Printf("Erro ao inserir lote de %d documentos: %v", len(batch), err)
+			batchErrs = append(batchErrs, err)
+		}
+	}
+
+	// Respostas guardadas podem não refletir os novos documentos
+	if inserted > 0 && i.cache != nil {
+		if err := i.cache.Invalidate(ctx); err != nil {
+			log.Printf("Aviso ao invalidar o cache: %v", err)
+		}
+	}
+	if inserted > 0 && i.queries != nil {
+		if err := i.queries.Invalidate(ctx); err != nil {
+			log.Printf("Aviso ao invalidar o cache de perguntas: %v", err)
+		}
+	}
+	i.publish(ctx, documents, chunks, owners, failures)
+	if i.strict && len(batchErrs) > 0 {
+		return inserted, fmt.Errorf("%d de %d lotes não foram gravados: %w", len(batchErrs), (len(chunks)+insertBatchSize-1)/insertBatchSize, errors.Join(batchErrs...))
+	}
+	return inserted, nil
+}
+
+// publish notifica e registra, para cada documento, a gravação dos seus chunks ou a
+// falha do lote em que algum deles não foi gravado
+func (i *Ingester) publish(ctx context.Context, documents, chunks []domain.Document, owners []int, failures map[int]error) {
+	if i.events == nil && i.audit == nil {
+		return
+	}
+
+	events := make([]domain.DocumentEvent, len(documents))
+	for j, doc := range documents {
+		events[j] = documentEvent(doc)
+	}
+	for k, chunk := range chunks {
+		event := &events[owners[k]]
+		if chunk.ID == "" {
+			continue
+		}
+		event.Chunks++
+		if event.ID = chunk.ID; chunk.ParentID != "" {
+			event.ID = chunk.ParentID
+		}
+	}
+	for j, event := range events {
+		if err, ok := failures[j]; ok {
+			// Os chunks já gravados continuam na base e são informados
+			event.Error = err.Error()
+			i.report(ctx, domain.EventDocumentFailed, event)
+		} else if event.Chunks > 0 {
+			i.report(ctx, domain.EventDocumentIngested, event)
+		}
+	}
+}
+
+// publishFailed notifica e registra a falha de todos os documentos
+func (i *Ingester) publishFailed(ctx context.Context, documents []domain.Document, err error) {
+	if i.events == nil && i.audit == nil {
+		return
+	}
+	for _, doc := range documents {
+		event := documentEvent(doc)
+		event.Error = err.Error()
+		i.report(ctx, domain.EventDocumentFailed, event)
+	}
+}
+
+// report publica o evento do documento e o registra no log de auditoria.
+// Falhas ao registrar não interrompem a ingestão e são apenas registradas.
+func (i *Ingester) report(ctx context.Context, eventType string, event domain.DocumentEvent) {
+	if i.events != nil {
+		i.events.Publish(ctx, eventType, event)
+	}
+	if i.audit == nil {
+		return
+	}
+	entry := domain.AuditEntry{
+		Action:   domain.AuditDocumentCreate,
+		Resource: event.ID,
+		Details:  map[string]any{"title": event.Title, "link": event.Link, "chunks": event.Chunks},
+		Error:    event.Error,
+	}
+	if err := i.audit.Append(context.WithoutCancel(ctx), &entry); err != nil {
+		log.Printf("Aviso ao registrar auditoria (%s): %v", entry.Action, err)
+	}
+}
+
+// documentEvent descreve o documento, ainda sem o ID e os chunks gravados
+func documentEvent(doc domain.Document) domain.DocumentEvent {
+	return domain.DocumentEvent{Title: doc.Title, Link: doc.Link, Category: doc.Category}
+}
+
+// dedupe preenche o hash de conteúdo dos documentos e descarta os que já
+// estão na base, inclusive os excluídos logicamente, ou que se repetem na lista
+func (i *Ingester) dedupe(ctx context.Context, documents []domain.Document) ([]domain.Document, error) {
+	unique := make([]domain.Document, 0, len(documents))
+	seen := make(map[string]bool)
+	skipped := 0
+	for _, doc := range documents {
+		doc.ContentHash = domain.ContentHash(doc.Content)
+		if seen[doc.ContentHash] {
+			skipped++
+			continue
+		}
+		seen[doc.ContentHash] = true
+
+		existing, err := i.repo.FindByContentHash(ctx, doc.ContentHash)
+		if err == nil {
+			log.Printf("Documento %q ignorado: conteúdo já indexado em %q", doc.Title, existing.Title)
+			skipped++
+			continue
+		}
+		if !errors.Is(err, domain.ErrDocumentNotFound) {
+			return nil, err
+		}
+		unique = append(unique, doc)
+	}
+	if skipped > 0 {
+		log.Printf("%d documentos duplicados ignorados", skipped)
+	}
+	return unique, nil
+}
+
+// replace remove os documentos já indexados com os mesmos links
+func (i *Ingester) replace(ctx context.Context, documents []domain.Document) error {
+	if i.replacer == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, doc := range documents {
+		if doc.Link == "" || seen[doc.Link] {
+			continue
+		}
+		seen[doc.Link] = true
+		if err := i.replacer.DeleteByLink(ctx, doc.Link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// embed preenche o embedding de cada documento, quando há cliente de embeddings
+func (i *Ingester) embed(ctx context.Context, documents []domain.Document) error {
+	if i.embedder == nil || len(documents) == 0 {
+		return nil
+	}
+
+	texts := make([]string, len(documents))
+	for j, doc := range documents {
+		texts[j] = doc.EmbeddingText()
+	}
+
+	vectors, err := i.embedder.Embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("erro ao gerar embeddings: %w", err)
+	}
+	for j := range documents {
+		documents[j].Embedding = vectors[j]
+	}
+	return nil
+}
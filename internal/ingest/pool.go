@@ -0,0 +1,68 @@
+package ingest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// DefaultWorkers é a quantidade padrão de lotes gravados em paralelo
+const DefaultWorkers = 4
+
+// Pool grava lotes de documentos com o Ingester em paralelo, com no máximo
+// workers lotes em andamento. Os lotes são independentes: um conteúdo
+// repetido em lotes gravados ao mesmo tempo pode passar pela deduplicação
+// nos dois, e a segunda gravação é recusada pelo índice único do banco.
+type Pool struct {
+	ingester *Ingester
+	slots    chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex // Serializa as chamadas de done
+}
+
+// NewPool cria um pool com até workers lotes em paralelo (padrão: 4)
+func NewPool(ingester *Ingester, workers int) *Pool {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	return &Pool{ingester: ingester, slots: make(chan struct{}, workers)}
+}
+
+// Submit grava o lote em segundo plano e chama done com o resultado de
+// Ingest. Enquanto todos os workers estão ocupados, Submit espera, para que
+// quem lê os documentos não avance muito à frente da gravação. As chamadas
+// de done nunca acontecem ao mesmo tempo, então podem atualizar contadores
+// sem sincronização. Com o contexto cancelado, o lote não é gravado e done
+// recebe o erro do contexto.
+func (p *Pool) Submit(ctx context.Context, documents []domain.Document, done func(chunks int, err error)) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		p.finish(done, 0, ctx.Err())
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		chunks, err := p.ingester.Ingest(ctx, documents)
+		<-p.slots
+		p.finish(done, chunks, err)
+	}()
+}
+
+// Wait espera os lotes enviados terminarem
+func (p *Pool) Wait() {
+	p.wg.Wait()
+}
+
+// finish chama done, um lote por vez
+func (p *Pool) finish(done func(int, error), chunks int, err error) {
+	if done == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	done(chunks, err)
+}
@@ -0,0 +1,143 @@
+// Package jobs processa em segundo plano os jobs de ingestão enfileirados
+// em POST /v1/ingest, gravando os documentos de cada job um a um
+package jobs
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/ingest"
+)
+
+// Valores padrão dos workers
+const (
+	DefaultWorkers      = 2
+	DefaultPollInterval = 2 * time.Second
+)
+
+// lease é o prazo da reserva de um job, renovado a cada documento. Se o
+// worker parar no meio do job, outro o retoma do último documento
+// processado depois desse prazo.
+const lease = 5 * time.Minute
+
+// Config contém as configurações dos workers
+type Config struct {
+	Workers      int           // Jobs processados em paralelo (padrão: 2)
+	PollInterval time.Duration // Espera entre as consultas à fila vazia (padrão: 2s)
+}
+
+// ConfigFromEnv lê a configuração dos workers a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	var cfg Config
+	cfg.Workers, _ = strconv.Atoi(os.Getenv("JOB_WORKERS"))
+	cfg.PollInterval, _ = time.ParseDuration(os.Getenv("JOB_POLL_INTERVAL"))
+	return cfg
+}
+
+// Runner consome a fila de jobs com um grupo de workers
+type Runner struct {
+	repo         domain.JobRepository
+	ingester     *ingest.Ingester // Deve retornar as falhas de gravação (ingest.WithStrictBatches)
+	workers      int
+	pollInterval time.Duration
+}
+
+// New cria os workers com a configuração de cfg
+func New(repo domain.JobRepository, ingester *ingest.Ingester, cfg Config) *Runner {
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultWorkers
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	return &Runner{repo: repo, ingester: ingester, workers: cfg.Workers, pollInterval: cfg.PollInterval}
+}
+
+// Run processa os jobs até o cancelamento do contexto e espera os workers
+// pararem. Um job interrompido fica reservado até o fim do prazo e é
+// retomado depois, por este ou outro servidor.
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range r.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// work reserva e processa um job por vez, esperando pollInterval quando a
+// fila está vazia ou a consulta falha
+func (r *Runner) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := r.repo.Claim(ctx, lease)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Erro ao consultar a fila de jobs: %v", err)
+		}
+		if job != nil {
+			r.process(ctx, job)
+			continue
+		}
+
+		select {
+		case <-time.After(r.pollInterval):
+		case <-ctx.Done():
+		}
+	}
+}
+
+// process grava os documentos ainda não processados do job, no tenant em
+// que ele foi criado, registrando o progresso depois de cada um
+func (r *Runner) process(ctx context.Context, job *domain.Job) {
+	tenantCtx := domain.WithTenant(ctx, job.TenantID)
+	// Os documentos gravados são atribuídos a quem enfileirou o job
+	tenantCtx = domain.WithRequestID(domain.WithActor(tenantCtx, job.Actor), job.RequestID)
+	log.Printf("Processando job %s: %d de %d documentos processados", job.ID, job.Processed, job.Total)
+
+	for job.Processed < len(job.Documents) {
+		doc := job.Documents[job.Processed]
+		chunks, err := r.ingester.Ingest(tenantCtx, []domain.Document{doc})
+		if ctx.Err() != nil {
+			// O documento interrompido é processado de novo na retomada
+			return
+		}
+
+		switch {
+		case err != nil:
+			job.Failed++
+			job.Errors = append(job.Errors, domain.JobError{Index: job.Processed, Title: doc.Title, Error: err.Error()})
+		case chunks == 0:
+			job.Skipped++
+		default:
+			job.Succeeded++
+			job.Chunks += chunks
+		}
+		job.Processed++
+
+		if job.Processed < len(job.Documents) {
+			if err := r.repo.Update(ctx, job, lease); err != nil && ctx.Err() == nil {
+				log.Printf("Erro ao registrar o progresso do job %s: %v", job.ID, err)
+			}
+		}
+	}
+
+	job.Status = domain.JobCompleted
+	if job.Failed > 0 && job.Failed == job.Total {
+		job.Status = domain.JobFailed
+	}
+	job.FinishedAt = time.Now()
+	// O resultado é gravado mesmo que o servidor esteja encerrando
+	if err := r.repo.Update(context.WithoutCancel(ctx), job, lease); err != nil {
+		log.Printf("Erro ao concluir o job %s: %v", job.ID, err)
+		return
+	}
+	log.Printf("Job %s concluído: %d gravados, %d ignorados, %d com erro, %d chunks",
+		job.ID, job.Succeeded, job.Skipped, job.Failed, job.Chunks)
+}
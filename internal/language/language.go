@@ -0,0 +1,127 @@
+// Package language identifica o idioma de um texto e descreve os códigos de
+// idioma (ISO 639-1, como "pt" ou "en-US") usados para escolher o idioma das
+// respostas
+package language
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Auto pede que o idioma seja detectado a partir da pergunta
+const Auto = "auto"
+
+// minHits é a quantidade mínima de palavras reconhecidas para que o idioma
+// detectado seja confiável
+const minHits = 2
+
+// codePattern reconhece códigos como "pt", "en-US" ou "zh-Hant"
+var codePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// names são os nomes, em inglês, usados no prompt para os idiomas mais comuns
+var names = map[string]string{
+	"pt": "Portuguese",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"de": "German",
+	"it": "Italian",
+	"nl": "Dutch",
+	"ja": "Japanese",
+	"zh": "Chinese",
+	"ko": "Korean",
+	"ru": "Russian",
+}
+
+// stopwords são palavras frequentes de cada idioma detectado. Palavras
+// comuns a vários idiomas (como "a", "de" e "que") contam para todos eles, e
+// o desempate fica com as exclusivas.
+var stopwords = map[string][]string{
+	"pt": {"o", "a", "os", "as", "um", "uma", "de", "do", "da", "dos", "das", "em", "no", "na", "nos", "para", "por", "com", "não", "que", "como", "qual", "quais", "quando", "onde", "é", "são", "está", "isso", "esse", "essa", "meu", "minha", "você", "ao", "pelo", "pela", "também", "mais", "ou", "posso", "fazer", "sobre", "tem"},
+	"en": {"the", "a", "an", "of", "to", "in", "on", "for", "with", "is", "are", "was", "what", "how", "which", "when", "where", "why", "do", "does", "can", "i", "my", "you", "it", "this", "that", "and", "or", "not", "about", "from", "should", "there", "be"},
+	"es": {"el", "la", "los", "las", "un", "una", "de", "del", "en", "para", "por", "con", "no", "que", "cómo", "cuál", "cuáles", "cuándo", "dónde", "es", "son", "está", "esto", "ese", "esa", "mi", "usted", "al", "también", "más", "o", "puedo", "hacer", "sobre", "tiene", "y", "qué", "hay"},
+	"fr": {"le", "la", "les", "un", "une", "des", "de", "du", "en", "dans", "pour", "par", "avec", "ne", "pas", "que", "comment", "quel", "quelle", "quand", "où", "est", "sont", "ce", "cette", "mon", "ma", "vous", "je", "au", "aussi", "plus", "ou", "et", "sur", "il", "qu'est-ce"},
+	"de": {"der", "die", "das", "ein", "eine", "den", "dem", "des", "in", "für", "mit", "von", "zu", "nicht", "wie", "was", "welche", "wann", "wo", "ist", "sind", "ich", "mein", "sie", "es", "und", "oder", "auch", "kann", "auf", "über", "warum"},
+	"it": {"il", "lo", "la", "gli", "le", "un", "una", "di", "del", "della", "in", "per", "con", "non", "che", "come", "quale", "quali", "quando", "dove", "è", "sono", "questo", "questa", "mio", "mia", "io", "al", "anche", "più", "o", "e", "posso", "fare", "perché"},
+}
+
+// accents são as letras que indicam fortemente um idioma
+var accents = map[string]string{
+	"pt": "ãõç",
+	"es": "ñ¿¡",
+	"de": "ßäöü",
+	"fr": "èêëœç",
+}
+
+// index associa cada palavra aos idiomas em que ela aparece
+var index = buildIndex()
+
+// buildIndex monta o índice das palavras de stopwords
+func buildIndex() map[string][]string {
+	idx := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, w := range words {
+			idx[w] = append(idx[w], lang)
+		}
+	}
+	return idx
+}
+
+// Valid informa se o código tem o formato de um código de idioma
+func Valid(code string) bool {
+	return codePattern.MatchString(code)
+}
+
+// Name retorna o nome do idioma usado no prompt, como "Portuguese", ou o
+// próprio código quando o idioma não é conhecido
+func Name(code string) string {
+	base, _, _ := strings.Cut(code, "-")
+	if name, ok := names[strings.ToLower(base)]; ok {
+		return name
+	}
+	return code
+}
+
+// Detect retorna o código do idioma do texto entre pt, en, es, fr, de e it,
+// ou vazio quando o texto é curto ou ambíguo demais para decidir
+func Detect(text string) string {
+	scores := make(map[string]int)
+	hits := 0
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\'' && r != '-'
+	})
+	for _, w := range words {
+		langs, ok := index[w]
+		if !ok {
+			continue
+		}
+		hits++
+		for _, lang := range langs {
+			// Palavras exclusivas de um idioma valem mais que as comuns
+			scores[lang] += 1 + 2/len(langs)
+		}
+	}
+	for lang, marks := range accents {
+		if strings.ContainsAny(text, marks) {
+			scores[lang] += 2
+		}
+	}
+	if hits < minHits {
+		return ""
+	}
+
+	best, bestScore, tie := "", 0, false
+	for lang, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, tie = lang, score, false
+		case score == bestScore:
+			tie = true
+		}
+	}
+	if tie {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,16 @@
+// Package llmjson extrai os objetos JSON das respostas do LLM, que às vezes
+// vêm acompanhados de explicações ou dentro de blocos de código
+package llmjson
+
+import "strings"
+
+// Extract remove texto ou blocos de código ao redor do objeto JSON. Sem um
+// objeto, retorna o texto inalterado.
+func Extract(text string) string {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return text
+	}
+	return text[start : end+1]
+}
@@ -0,0 +1,412 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// maxPageSize limita o tamanho das páginas baixadas
+const maxPageSize = 10 << 20 // 10 MB
+
+// userAgent identifica o ingestor nas requisições às páginas
+const userAgent = "agentic-rag-ingest/1.0"
+
+// boilerplatePattern reconhece classes e IDs de elementos que não fazem
+// parte do conteúdo principal (menus, anúncios, rodapés etc.)
+var boilerplatePattern = regexp.MustCompile(`(?i)(^|[-_\s])(nav|navbar|menu|header|footer|sidebar|aside|ad|ads|advert|advertisement|banner|cookie|consent|comment|comments|share|social|promo|related|breadcrumbs?|popup|modal|newsletter|subscribe)([-_\s]|$)`)
+
+// contentPattern reconhece classes e IDs de blocos de conteúdo, que não são
+// removidos mesmo que também casem com boilerplatePattern (ex.: "main-content has-sidebar")
+var contentPattern = regexp.MustCompile(`(?i)article|body|column|content|main|post|entry`)
+
+// boilerplateTags são removidas por completo antes da extração
+var boilerplateTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Nav: true,
+	atom.Header: true, atom.Footer: true, atom.Aside: true, atom.Form: true,
+	atom.Iframe: true, atom.Svg: true, atom.Button: true, atom.Template: true,
+}
+
+// boilerplateRoles são papéis ARIA de regiões que não são conteúdo
+var boilerplateRoles = map[string]bool{
+	"navigation": true, "banner": true, "contentinfo": true, "complementary": true, "search": true,
+}
+
+// blockTags quebram linha na extração do texto
+var blockTags = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Li: true, atom.Ul: true, atom.Ol: true, atom.Pre: true, atom.Blockquote: true,
+	atom.Table: true, atom.Tr: true, atom.Br: true, atom.Dd: true, atom.Dt: true, atom.Figcaption: true,
+}
+
+// HTMLLoader carrega páginas HTML, locais ou baixadas da web, extraindo
+// apenas o conteúdo principal: menus, anúncios e rodapés são descartados
+type HTMLLoader struct {
+	httpClient *http.Client
+	validators *Validators // Opcional: habilita os downloads condicionais
+}
+
+// HTMLOption configura dependências opcionais do HTMLLoader
+type HTMLOption func(*HTMLLoader)
+
+// WithValidators faz com que LoadURL envie o ETag e o Last-Modified do
+// último download de cada página e retorne ErrNotModified quando o servidor
+// responder que ela não mudou
+func WithValidators(validators *Validators) HTMLOption {
+	return func(l *HTMLLoader) {
+		l.validators = validators
+	}
+}
+
+// NewHTMLLoader cria um loader de páginas HTML. Com client nil, usa um
+// cliente com timeout de 30 segundos.
+func NewHTMLLoader(client *http.Client, opts ...HTMLOption) *HTMLLoader {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	l := &HTMLLoader{httpClient: client}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Load implementa Loader para arquivos .html locais
+func (l *HTMLLoader) Load(path, rel string) ([]domain.Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	doc, err := ParseHTML(file, nil)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Title == "" {
+		doc.Title = titleFromPath(rel)
+	}
+	if doc.Link == "" {
+		doc.Link = linkFromPath(rel)
+	}
+	doc.Category = categoryFromPath(rel)
+	return []domain.Document{doc}, nil
+}
+
+// LoadURL baixa a página e extrai o seu conteúdo. O link do documento é o
+// endereço canônico da página, quando informado, ou o endereço final após
+// os redirecionamentos.
+func (l *HTMLLoader) LoadURL(ctx context.Context, target string) (*domain.Document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição para %s: %w", target, err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	if l.validators != nil {
+		l.validators.apply(req, target)
+	}
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao baixar %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && l.validators != nil {
+		return nil, fmt.Errorf("%s: %w", target, ErrNotModified)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("erro ao baixar %s: status %d", target, resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !strings.Contains(contentType, "html") {
+		return nil, fmt.Errorf("conteúdo de %s não é HTML: %s", target, contentType)
+	}
+
+	doc, err := ParseHTML(io.LimitReader(resp.Body, maxPageSize), resp.Request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar %s: %w", target, err)
+	}
+	if doc.Link == "" {
+		doc.Link = resp.Request.URL.String()
+	}
+	if doc.Title == "" {
+		doc.Title = resp.Request.URL.Host + resp.Request.URL.Path
+	}
+	doc.Metadata["source_url"] = resp.Request.URL.String()
+	if l.validators != nil {
+		l.validators.store(target, resp.Header)
+	}
+	return &doc, nil
+}
+
+// ParseHTML extrai o título, o link canônico, a descrição e o texto do
+// conteúdo principal da página. pageURL, quando informado, resolve links
+// canônicos relativos.
+func ParseHTML(r io.Reader, pageURL *url.URL) (domain.Document, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return domain.Document{}, err
+	}
+
+	head := pageHead(root, pageURL)
+	removeBoilerplate(root)
+
+	content := mainContent(root)
+	if content == nil {
+		return domain.Document{}, fmt.Errorf("página sem conteúdo")
+	}
+
+	doc := domain.Document{
+		Title:    head.title,
+		Content:  extractText(content),
+		Link:     head.canonical,
+		Metadata: map[string]string{},
+	}
+	if head.description != "" {
+		doc.Metadata["description"] = head.description
+	}
+	if doc.Title == "" {
+		if h1 := findFirst(content, atom.H1); h1 != nil {
+			doc.Title = collapseSpaces(textOf(h1))
+		}
+	}
+	return doc, nil
+}
+
+// headInfo reúne as informações do cabeçalho da página
+type headInfo struct {
+	title       string
+	canonical   string
+	description string
+}
+
+// pageHead lê o título (og:title ou <title>), o link canônico e a descrição
+func pageHead(root *html.Node, pageURL *url.URL) headInfo {
+	var info headInfo
+	var ogTitle string
+	walk(root, func(n *html.Node) bool {
+		switch n.DataAtom {
+		case atom.Title:
+			if info.title == "" {
+				info.title = collapseSpaces(textOf(n))
+			}
+		case atom.Meta:
+			name := strings.ToLower(attr(n, "name") + attr(n, "property"))
+			switch name {
+			case "og:title":
+				ogTitle = strings.TrimSpace(attr(n, "content"))
+			case "description", "og:description":
+				if info.description == "" {
+					info.description = strings.TrimSpace(attr(n, "content"))
+				}
+			}
+		case atom.Link:
+			if strings.EqualFold(attr(n, "rel"), "canonical") && info.canonical == "" {
+				info.canonical = resolveURL(pageURL, attr(n, "href"))
+			}
+		case atom.Body:
+			return false
+		}
+		return true
+	})
+	if ogTitle != "" {
+		info.title = ogTitle
+	}
+	return info
+}
+
+// removeBoilerplate remove da árvore os elementos que não são conteúdo
+func removeBoilerplate(root *html.Node) {
+	var remove []*html.Node
+	walk(root, func(n *html.Node) bool {
+		if n.Type == html.CommentNode {
+			remove = append(remove, n)
+			return false
+		}
+		if n.Type != html.ElementNode {
+			return true
+		}
+		if boilerplateTags[n.DataAtom] || boilerplateRoles[attr(n, "role")] || attr(n, "aria-hidden") == "true" || isBoilerplateBlock(n) {
+			remove = append(remove, n)
+			return false
+		}
+		return true
+	})
+	for _, n := range remove {
+		n.Parent.RemoveChild(n)
+	}
+}
+
+// isBoilerplateBlock indica se a classe ou o ID do elemento sugerem que ele
+// não faz parte do conteúdo
+func isBoilerplateBlock(n *html.Node) bool {
+	if n.DataAtom == atom.Body || n.DataAtom == atom.Html {
+		return false
+	}
+	names := attr(n, "class") + " " + attr(n, "id")
+	return boilerplatePattern.MatchString(names) && !contentPattern.MatchString(names)
+}
+
+// mainContent escolhe o elemento com o conteúdo principal: <article>,
+// <main> ou, na falta deles, o bloco com mais texto fora de links
+func mainContent(root *html.Node) *html.Node {
+	for _, tag := range []atom.Atom{atom.Article, atom.Main} {
+		if n := findFirst(root, tag); n != nil {
+			return n
+		}
+	}
+
+	body := findFirst(root, atom.Body)
+	if body == nil {
+		return nil
+	}
+
+	best, bestScore := body, 0.0
+	walk(body, func(n *html.Node) bool {
+		if n.Type == html.ElementNode && (n.DataAtom == atom.Div || n.DataAtom == atom.Section || n.DataAtom == atom.Td) {
+			if score := contentScore(n); score > bestScore {
+				best, bestScore = n, score
+			}
+		}
+		return true
+	})
+	return best
+}
+
+// contentScore pontua o bloco pelo texto dos parágrafos que contém,
+// penalizando blocos formados principalmente por links
+func contentScore(n *html.Node) float64 {
+	text := len(collapseSpaces(textOf(n)))
+	if text == 0 {
+		return 0
+	}
+	links := 0
+	paragraphs := 0
+	walk(n, func(c *html.Node) bool {
+		switch c.DataAtom {
+		case atom.A:
+			links += len(collapseSpaces(textOf(c)))
+			return false
+		case atom.P:
+			paragraphs++
+		}
+		return true
+	})
+	linkDensity := float64(links) / float64(text)
+	return float64(text) * (1 - linkDensity) * float64(1+paragraphs)
+}
+
+// extractText converte o conteúdo em texto, com uma linha por bloco
+func extractText(n *html.Node) string {
+	var b strings.Builder
+	var visit func(n *html.Node, pre bool)
+	visit = func(n *html.Node, pre bool) {
+		switch n.Type {
+		case html.TextNode:
+			// Fora de <pre>, quebras de linha do código-fonte são apenas espaços
+			if !pre {
+				b.WriteString(strings.ReplaceAll(n.Data, "\n", " "))
+				return
+			}
+			b.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			if blockTags[n.DataAtom] {
+				b.WriteString("\n")
+				defer b.WriteString("\n")
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c, pre || n.DataAtom == atom.Pre)
+		}
+	}
+	visit(n, false)
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = collapseSpaces(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// walk percorre a árvore em profundidade; visit retorna false para não
+// descer nos filhos do nó
+func walk(n *html.Node, visit func(*html.Node) bool) {
+	if !visit(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, visit)
+	}
+}
+
+// findFirst retorna o primeiro elemento com a tag informada
+func findFirst(root *html.Node, tag atom.Atom) *html.Node {
+	var found *html.Node
+	walk(root, func(n *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if n.Type == html.ElementNode && n.DataAtom == tag {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// textOf concatena o texto de todos os descendentes do nó
+func textOf(n *html.Node) string {
+	var b strings.Builder
+	walk(n, func(c *html.Node) bool {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+			b.WriteString(" ")
+		}
+		return true
+	})
+	return b.String()
+}
+
+// attr retorna o valor do atributo do elemento
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// resolveURL resolve o endereço relativo à página
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if base == nil || ref == "" {
+		return ref
+	}
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(parsed).String()
+}
+
+// collapseSpaces troca sequências de espaços em branco por um único espaço
+func collapseSpaces(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
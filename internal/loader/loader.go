@@ -0,0 +1,89 @@
+package loader
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Loader extrai os documentos de um arquivo da base de conhecimento
+type Loader interface {
+	// Load lê o arquivo em path. rel é o caminho relativo à raiz da base,
+	// usado para derivar o link e a categoria dos documentos.
+	Load(path, rel string) ([]domain.Document, error)
+}
+
+// loaders associa cada extensão de arquivo suportada ao seu loader
+var loaders = map[string]Loader{
+	".md":       MarkdownLoader{},
+	".markdown": MarkdownLoader{},
+	".pdf":      PDFLoader{},
+	".html":     NewHTMLLoader(nil),
+	".htm":      NewHTMLLoader(nil),
+}
+
+// ForPath retorna o loader adequado à extensão do arquivo
+func ForPath(path string) (Loader, bool) {
+	loader, ok := loaders[strings.ToLower(filepath.Ext(path))]
+	return loader, ok
+}
+
+// LoadDir percorre o diretório recursivamente e carrega todos os arquivos
+// com extensão suportada; os demais são ignorados
+func LoadDir(dir string) ([]domain.Document, error) {
+	var documents []domain.Document
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		loader, ok := ForPath(path)
+		if !ok {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		docs, err := loader.Load(path, rel)
+		if err != nil {
+			return fmt.Errorf("erro ao carregar %s: %w", path, err)
+		}
+		documents = append(documents, docs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// linkFromPath deriva o link do documento a partir do caminho relativo, sem a extensão
+func linkFromPath(rel string) string {
+	return "/" + filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+}
+
+// categoryFromPath usa o nome da pasta do arquivo como categoria
+func categoryFromPath(rel string) string {
+	folder := filepath.Base(filepath.Dir(rel))
+	if folder == "." {
+		return ""
+	}
+	return folder
+}
+
+// titleFromPath usa o nome do arquivo, sem a extensão, como título
+func titleFromPath(rel string) string {
+	return strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel))
+}
+
+// parseTags converte uma lista de tags separadas por vírgula
+func parseTags(value string) []string {
+	return domain.NormalizeTags(strings.Split(value, ","))
+}
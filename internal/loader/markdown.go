@@ -0,0 +1,121 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// frontMatterDelimiter abre e fecha o bloco de front-matter no início do arquivo
+const frontMatterDelimiter = "---"
+
+// MarkdownLoader carrega arquivos Markdown, gerando um documento por arquivo
+type MarkdownLoader struct{}
+
+// Load implementa Loader
+func (MarkdownLoader) Load(path, rel string) ([]domain.Document, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseMarkdown(rel, string(raw))
+	if err != nil {
+		return nil, err
+	}
+	return []domain.Document{doc}, nil
+}
+
+// parseMarkdown converte o conteúdo de um arquivo Markdown em documento. O
+// título vem do primeiro cabeçalho (ou do nome do arquivo), a categoria da
+// pasta e o link do caminho relativo. Os campos title, category e link do
+// front-matter têm precedência, tags é uma lista separada por vírgulas e os
+// demais vão para os metadados.
+func parseMarkdown(rel, text string) (domain.Document, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	meta, body, err := splitFrontMatter(text)
+	if err != nil {
+		return domain.Document{}, err
+	}
+
+	title, content := extractTitle(body)
+	doc := domain.Document{
+		Title:    title,
+		Content:  strings.TrimSpace(content),
+		Link:     linkFromPath(rel),
+		Category: categoryFromPath(rel),
+	}
+	if doc.Title == "" {
+		doc.Title = titleFromPath(rel)
+	}
+
+	for key, value := range meta {
+		switch key {
+		case "title":
+			doc.Title = value
+		case "category":
+			doc.Category = value
+		case "link":
+			doc.Link = value
+		case "tags":
+			doc.Tags = parseTags(value)
+		default:
+			if doc.Metadata == nil {
+				doc.Metadata = make(map[string]string)
+			}
+			doc.Metadata[key] = value
+		}
+	}
+	return doc, nil
+}
+
+// splitFrontMatter separa o bloco de front-matter (pares "chave: valor"
+// entre linhas "---") do corpo do arquivo
+func splitFrontMatter(text string) (map[string]string, string, error) {
+	if !strings.HasPrefix(text, frontMatterDelimiter+"\n") {
+		return nil, text, nil
+	}
+
+	rest := text[len(frontMatterDelimiter)+1:]
+	header, body, found := strings.Cut(rest, "\n"+frontMatterDelimiter+"\n")
+	if !found {
+		// O delimitador de fechamento pode ser a última linha do arquivo
+		header, found = strings.CutSuffix(rest, "\n"+frontMatterDelimiter)
+		if !found {
+			return nil, "", fmt.Errorf("front-matter sem delimitador de fechamento")
+		}
+	}
+
+	meta := make(map[string]string)
+	for _, line := range strings.Split(header, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, "", fmt.Errorf("linha inválida no front-matter: %q", line)
+		}
+		value = strings.TrimSpace(value)
+		meta[strings.TrimSpace(key)] = strings.Trim(value, `"'`)
+	}
+	return meta, body, nil
+}
+
+// extractTitle retorna o texto do primeiro cabeçalho e o corpo sem ele
+func extractTitle(body string) (string, string) {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		title := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+		if title == "" {
+			continue
+		}
+		return title, strings.Join(append(lines[:i:i], lines[i+1:]...), "\n")
+	}
+	return "", body
+}
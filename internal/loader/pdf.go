@@ -0,0 +1,62 @@
+package loader
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/ledongthuc/pdf"
+)
+
+// PDFLoader carrega arquivos PDF, gerando um documento por página com o
+// número da página nos metadados. Páginas sem texto extraível (por exemplo,
+// digitalizadas) são ignoradas.
+type PDFLoader struct{}
+
+// Load implementa Loader
+func (PDFLoader) Load(path, rel string) (docs []domain.Document, err error) {
+	// O parser entra em pânico com alguns arquivos malformados
+	defer func() {
+		if r := recover(); r != nil {
+			docs, err = nil, fmt.Errorf("PDF inválido: %v", r)
+		}
+	}()
+
+	file, reader, err := pdf.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir PDF: %w", err)
+	}
+	defer file.Close()
+
+	title := strings.TrimSpace(reader.Trailer().Key("Info").Key("Title").Text())
+	if title == "" {
+		title = titleFromPath(rel)
+	}
+	link := linkFromPath(rel) + ".pdf"
+	pages := reader.NumPage()
+
+	for number := 1; number <= pages; number++ {
+		text, err := reader.Page(number).GetPlainText(nil)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao extrair texto da página %d: %w", number, err)
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		docs = append(docs, domain.Document{
+			Title:    title,
+			Content:  text,
+			Link:     fmt.Sprintf("%s#page=%d", link, number),
+			Category: categoryFromPath(rel),
+			Metadata: map[string]string{
+				"page":   strconv.Itoa(number),
+				"pages":  strconv.Itoa(pages),
+				"source": rel,
+			},
+		})
+	}
+	return docs, nil
+}
@@ -0,0 +1,188 @@
+package loader
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// maxRecordSize limita o tamanho de uma linha dos arquivos JSONL
+const maxRecordSize = 10 << 20 // 10 MB
+
+// RecordReader lê documentos, um registro por vez, de arquivos de
+// importação em massa (CSV ou JSONL)
+type RecordReader interface {
+	// Next retorna o próximo documento, ou io.EOF ao final do arquivo. Erros
+	// de um registro específico são do tipo *RecordError e não impedem a
+	// leitura dos seguintes.
+	Next() (domain.Document, error)
+}
+
+// RecordError indica um registro inválido no arquivo de importação
+type RecordError struct {
+	Line int // Linha do registro no arquivo
+	Err  error
+}
+
+// Error implementa a interface error
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("linha %d: %v", e.Line, e.Err)
+}
+
+// Unwrap permite inspecionar o erro original
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// NewRecordReader cria o leitor adequado ao formato informado ("csv" ou "jsonl")
+func NewRecordReader(r io.Reader, format string) (RecordReader, error) {
+	switch strings.ToLower(format) {
+	case "csv":
+		return NewCSVReader(r)
+	case "jsonl", "ndjson":
+		return NewJSONLReader(r), nil
+	default:
+		return nil, fmt.Errorf("formato de importação desconhecido: %q", format)
+	}
+}
+
+// CSVReader lê documentos de um CSV com cabeçalho. As colunas title,
+// content, link e category preenchem o documento, as colunas tags e acl são
+// listas separadas por vírgulas, a coluna expires_at é uma data RFC 3339 e as
+// demais vão para os metadados.
+type CSVReader struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+// NewCSVReader lê o cabeçalho do CSV e cria o leitor
+func NewCSVReader(r io.Reader) (*CSVReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // O número de campos é validado por registro
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler cabeçalho do CSV: %w", err)
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		columns[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	if !slices.Contains(columns, "title") || !slices.Contains(columns, "content") {
+		return nil, fmt.Errorf("o CSV precisa das colunas title e content")
+	}
+	return &CSVReader{reader: reader, columns: columns}, nil
+}
+
+// Next implementa RecordReader
+func (r *CSVReader) Next() (domain.Document, error) {
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return domain.Document{}, io.EOF
+	}
+	line, _ := r.reader.FieldPos(0)
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return domain.Document{}, &RecordError{Line: parseErr.StartLine, Err: parseErr.Err}
+		}
+		return domain.Document{}, err
+	}
+	if len(record) != len(r.columns) {
+		return domain.Document{}, &RecordError{Line: line, Err: fmt.Errorf("esperados %d campos, encontrados %d", len(r.columns), len(record))}
+	}
+
+	var doc domain.Document
+	for i, value := range record {
+		switch r.columns[i] {
+		case "title":
+			doc.Title = value
+		case "content":
+			doc.Content = value
+		case "link":
+			doc.Link = value
+		case "category":
+			doc.Category = value
+		case "tags":
+			doc.Tags = parseTags(value)
+		case "acl":
+			doc.ACL = domain.NormalizeGroups(strings.Split(value, ","))
+		case "expires_at":
+			if value == "" {
+				continue
+			}
+			expiresAt, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return domain.Document{}, &RecordError{Line: line, Err: fmt.Errorf("expires_at deve estar no formato RFC 3339: %q", value)}
+			}
+			doc.ExpiresAt = expiresAt
+		default:
+			if value == "" {
+				continue
+			}
+			if doc.Metadata == nil {
+				doc.Metadata = make(map[string]string)
+			}
+			doc.Metadata[r.columns[i]] = value
+		}
+	}
+	return doc, validateRecord(doc, line)
+}
+
+// JSONLReader lê documentos de um arquivo com um objeto JSON por linha, nos
+// campos title, content, link, category, metadata, tags, acl e expires_at
+type JSONLReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewJSONLReader cria o leitor de JSONL
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxRecordSize)
+	return &JSONLReader{scanner: scanner}
+}
+
+// Next implementa RecordReader. Linhas em branco são ignoradas.
+func (r *JSONLReader) Next() (domain.Document, error) {
+	for r.scanner.Scan() {
+		r.line++
+		raw := strings.TrimSpace(r.scanner.Text())
+		if raw == "" {
+			continue
+		}
+
+		var doc domain.Document
+		if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+			return domain.Document{}, &RecordError{Line: r.line, Err: fmt.Errorf("JSON inválido: %w", err)}
+		}
+		doc.ID = ""
+		doc.Tags = domain.NormalizeTags(doc.Tags)
+		doc.ACL = domain.NormalizeGroups(doc.ACL)
+		return doc, validateRecord(doc, r.line)
+	}
+	if err := r.scanner.Err(); err != nil {
+		return domain.Document{}, fmt.Errorf("erro ao ler linha %d: %w", r.line+1, err)
+	}
+	return domain.Document{}, io.EOF
+}
+
+// validateRecord verifica os campos obrigatórios do registro
+func validateRecord(doc domain.Document, line int) error {
+	switch {
+	case strings.TrimSpace(doc.Title) == "":
+		return &RecordError{Line: line, Err: fmt.Errorf("title é obrigatório")}
+	case strings.TrimSpace(doc.Content) == "":
+		return &RecordError{Line: line, Err: fmt.Errorf("content é obrigatório")}
+	}
+	return nil
+}
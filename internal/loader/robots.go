@@ -0,0 +1,172 @@
+package loader
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// robotsRule é uma regra Allow ou Disallow do robots.txt
+type robotsRule struct {
+	allow   bool
+	pattern *regexp.Regexp
+	length  int // Tamanho do caminho da regra, usado para escolher a mais específica
+}
+
+// robotsPolicy guarda as regras aplicáveis ao ingestor em um host
+type robotsPolicy struct {
+	rules       []robotsRule
+	disallowAll bool // robots.txt inacessível por erro do servidor
+}
+
+// allowed indica se o caminho pode ser acessado. Vale a regra mais
+// específica (mais longa); em caso de empate, Allow prevalece.
+func (p *robotsPolicy) allowed(path string) bool {
+	if p.disallowAll {
+		return false
+	}
+
+	allowed, best := true, -1
+	for _, rule := range p.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > best || (rule.length == best && rule.allow) {
+			allowed, best = rule.allow, rule.length
+		}
+	}
+	return allowed
+}
+
+// robotsCache busca e guarda o robots.txt de cada host
+type robotsCache struct {
+	httpClient *http.Client
+	agent      string
+
+	mu       sync.Mutex
+	policies map[string]*robotsPolicy
+}
+
+// newRobotsCache cria o cache de robots.txt para o user agent informado
+func newRobotsCache(client *http.Client, agent string) *robotsCache {
+	return &robotsCache{httpClient: client, agent: agent, policies: make(map[string]*robotsPolicy)}
+}
+
+// Allowed indica se o robots.txt do host permite acessar o endereço
+func (c *robotsCache) Allowed(ctx context.Context, target *url.URL) bool {
+	host := target.Scheme + "://" + target.Host
+
+	// O lock é mantido durante a busca para que o arquivo seja baixado uma
+	// única vez, mesmo com várias páginas do host sendo processadas
+	c.mu.Lock()
+	policy, ok := c.policies[host]
+	if !ok {
+		policy = c.fetch(ctx, host)
+		c.policies[host] = policy
+	}
+	c.mu.Unlock()
+
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if target.RawQuery != "" {
+		path += "?" + target.RawQuery
+	}
+	return policy.allowed(path)
+}
+
+// fetch baixa o robots.txt do host. Seguindo a RFC 9309, a ausência do
+// arquivo (4xx) libera todo o site e erros do servidor bloqueiam tudo.
+func (c *robotsCache) fetch(ctx context.Context, host string) *robotsPolicy {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, host+"/robots.txt", nil)
+	if err != nil {
+		return &robotsPolicy{disallowAll: true}
+	}
+	req.Header.Set("User-Agent", c.agent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &robotsPolicy{disallowAll: true}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return &robotsPolicy{disallowAll: true}
+	case resp.StatusCode != http.StatusOK:
+		return &robotsPolicy{}
+	}
+	return parseRobots(io.LimitReader(resp.Body, 512<<10), c.agent)
+}
+
+// parseRobots lê as regras do grupo do user agent informado ou, se não
+// houver, do grupo "*"
+func parseRobots(r io.Reader, agent string) *robotsPolicy {
+	product := strings.ToLower(strings.SplitN(agent, "/", 2)[0])
+
+	var specific, wildcard []robotsRule
+	var groupAgents []string
+	inRules := false // Um User-agent depois de regras inicia um novo grupo
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if inRules {
+				groupAgents = nil
+				inRules = false
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue // "Disallow:" vazio não bloqueia nada
+			}
+			rule := robotsRule{allow: key == "allow", pattern: robotsPattern(value), length: len(value)}
+			for _, name := range groupAgents {
+				switch {
+				case name == "*":
+					wildcard = append(wildcard, rule)
+				case strings.Contains(product, name):
+					specific = append(specific, rule)
+				}
+			}
+		}
+	}
+
+	if specific != nil {
+		return &robotsPolicy{rules: specific}
+	}
+	return &robotsPolicy{rules: wildcard}
+}
+
+// robotsPattern converte o caminho da regra em expressão regular, com
+// suporte aos curingas "*" e "$" (fim do caminho)
+func robotsPattern(path string) *regexp.Regexp {
+	anchored := strings.HasSuffix(path, "$")
+	path = strings.TrimSuffix(path, "$")
+
+	parts := strings.Split(path, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
@@ -0,0 +1,246 @@
+package loader
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Valores padrão do crawler
+const (
+	defaultConcurrency = 4
+	maxSitemapDepth    = 3        // Níveis de sitemaps aninhados em um índice
+	maxSitemapSize     = 50 << 20 // Tamanho máximo de um sitemap, descompactado
+	maxSitemapURLs     = 50_000   // Limite de URLs por sitemap definido pelo protocolo
+)
+
+// CrawlerConfig contém as configurações do crawler de sitemaps
+type CrawlerConfig struct {
+	Concurrency int // Páginas baixadas em paralelo (padrão: 4)
+	MaxPages    int // Limite de páginas processadas; 0 processa todas
+
+	// Validators, se definido, habilita os downloads condicionais: páginas
+	// que não mudaram desde o último download são ignoradas
+	Validators *Validators
+}
+
+// Crawler percorre um sitemap.xml e carrega as páginas listadas, respeitando
+// o robots.txt de cada host
+type Crawler struct {
+	httpClient *http.Client
+	pages      *HTMLLoader
+	robots     *robotsCache
+	config     CrawlerConfig
+}
+
+// NewCrawler cria um crawler de sitemaps. Com client nil, usa o mesmo
+// cliente padrão do HTMLLoader.
+func NewCrawler(client *http.Client, cfg CrawlerConfig) *Crawler {
+	var opts []HTMLOption
+	if cfg.Validators != nil {
+		opts = append(opts, WithValidators(cfg.Validators))
+	}
+	pages := NewHTMLLoader(client, opts...)
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultConcurrency
+	}
+	return &Crawler{
+		httpClient: pages.httpClient,
+		pages:      pages,
+		robots:     newRobotsCache(pages.httpClient, userAgent),
+		config:     cfg,
+	}
+}
+
+// Crawl lê o sitemap (ou índice de sitemaps) e chama handle para cada página
+// carregada. As páginas são baixadas em paralelo, mas handle é sempre
+// chamado na goroutine de Crawl. Páginas bloqueadas pelo robots.txt, de
+// outros hosts ou com erro são registradas e ignoradas.
+func (c *Crawler) Crawl(ctx context.Context, sitemapURL string, handle func(doc *domain.Document) error) error {
+	root, err := url.Parse(sitemapURL)
+	if err != nil {
+		return fmt.Errorf("endereço de sitemap inválido: %w", err)
+	}
+
+	urls, err := c.collect(ctx, root, 0, make(map[string]bool))
+	if err != nil {
+		return err
+	}
+	if c.config.MaxPages > 0 && len(urls) > c.config.MaxPages {
+		urls = urls[:c.config.MaxPages]
+	}
+	log.Printf("%d páginas encontradas no sitemap", len(urls))
+
+	// Um erro em handle interrompe os downloads ainda pendentes
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan string)
+	results := make(chan *domain.Document)
+
+	var wg sync.WaitGroup
+	for range c.config.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range jobs {
+				if doc := c.fetchPage(ctx, target); doc != nil {
+					select {
+					case results <- doc:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, target := range urls {
+			select {
+			case jobs <- target:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var handleErr error
+	for doc := range results {
+		if handleErr != nil {
+			continue // Esvazia o canal para encerrar os workers
+		}
+		if handleErr = handle(doc); handleErr != nil {
+			cancel()
+		}
+	}
+	if handleErr != nil {
+		return handleErr
+	}
+	return ctx.Err()
+}
+
+// fetchPage baixa uma página, se o robots.txt permitir
+func (c *Crawler) fetchPage(ctx context.Context, target string) *domain.Document {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		log.Printf("Endereço inválido no sitemap: %s", target)
+		return nil
+	}
+	if !c.robots.Allowed(ctx, parsed) {
+		log.Printf("Página bloqueada pelo robots.txt: %s", target)
+		return nil
+	}
+
+	doc, err := c.pages.LoadURL(ctx, target)
+	if errors.Is(err, ErrNotModified) {
+		return nil
+	}
+	if err != nil {
+		log.Printf("Aviso ao carregar página: %v", err)
+		return nil
+	}
+	return doc
+}
+
+// sitemapFile representa tanto um <urlset> quanto um <sitemapindex>
+type sitemapFile struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+// sitemapLoc é uma entrada do sitemap
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// collect lê o sitemap e retorna as URLs de páginas do mesmo host, sem
+// repetições, seguindo índices de sitemaps até maxSitemapDepth níveis
+func (c *Crawler) collect(ctx context.Context, sitemapURL *url.URL, depth int, seen map[string]bool) ([]string, error) {
+	file, err := c.fetchSitemap(ctx, sitemapURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	for _, entry := range file.URLs {
+		loc, err := sitemapURL.Parse(strings.TrimSpace(entry.Loc))
+		if err != nil || loc.Host != sitemapURL.Host {
+			continue // O protocolo só permite URLs do host do sitemap
+		}
+		loc.Fragment = ""
+		if key := loc.String(); !seen[key] && len(urls) < maxSitemapURLs {
+			seen[key] = true
+			urls = append(urls, key)
+		}
+	}
+
+	for _, entry := range file.Sitemaps {
+		if depth+1 > maxSitemapDepth {
+			log.Printf("Sitemap ignorado, aninhamento acima de %d níveis: %s", maxSitemapDepth, entry.Loc)
+			continue
+		}
+		child, err := sitemapURL.Parse(strings.TrimSpace(entry.Loc))
+		if err != nil {
+			continue
+		}
+		childURLs, err := c.collect(ctx, child, depth+1, seen)
+		if err != nil {
+			log.Printf("Aviso ao ler sitemap %s: %v", child, err)
+			continue
+		}
+		urls = append(urls, childURLs...)
+	}
+	return urls, nil
+}
+
+// fetchSitemap baixa e decodifica um sitemap, descompactando os .gz
+func (c *Crawler) fetchSitemap(ctx context.Context, target string) (*sitemapFile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição para %s: %w", target, err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao baixar sitemap %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("erro ao baixar sitemap %s: status %d", target, resp.StatusCode)
+	}
+
+	var body io.Reader = resp.Body
+	if strings.HasSuffix(resp.Request.URL.Path, ".gz") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao descompactar sitemap %s: %w", target, err)
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	var file sitemapFile
+	if err := xml.NewDecoder(io.LimitReader(body, maxSitemapSize)).Decode(&file); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar sitemap %s: %w", target, err)
+	}
+	return &file, nil
+}
@@ -0,0 +1,59 @@
+package loader
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrNotModified indica que a página não mudou desde o último download, de
+// acordo com o ETag ou o Last-Modified informados pelo servidor
+var ErrNotModified = errors.New("página não modificada")
+
+// validator guarda os cabeçalhos de validação de uma página
+type validator struct {
+	etag         string
+	lastModified string
+}
+
+// Validators guarda, em memória, o ETag e o Last-Modified das páginas
+// baixadas, enviados nos downloads seguintes da mesma página para que o
+// servidor responda 304 quando nada mudou. É seguro para uso concorrente.
+type Validators struct {
+	mu      sync.Mutex
+	entries map[string]validator
+}
+
+// NewValidators cria um cache de validadores vazio
+func NewValidators() *Validators {
+	return &Validators{entries: make(map[string]validator)}
+}
+
+// apply acrescenta à requisição os cabeçalhos condicionais do endereço
+func (v *Validators) apply(req *http.Request, target string) {
+	v.mu.Lock()
+	entry, ok := v.entries[target]
+	v.mu.Unlock()
+	if !ok {
+		return
+	}
+	if entry.etag != "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	if entry.lastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.lastModified)
+	}
+}
+
+// store guarda os validadores da resposta; respostas sem eles removem os anteriores
+func (v *Validators) store(target string, header http.Header) {
+	entry := validator{etag: header.Get("ETag"), lastModified: header.Get("Last-Modified")}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if entry == (validator{}) {
+		delete(v.entries, target)
+		return
+	}
+	v.entries[target] = entry
+}
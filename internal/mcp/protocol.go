@@ -0,0 +1,119 @@
+// Package mcp implementa o Model Context Protocol (JSON-RPC 2.0 por stdio
+// ou HTTP), usado para expor as ferramentas do RAG a agentes externos
+package mcp
+
+import "encoding/json"
+
+// ProtocolVersion é a versão mais recente do protocolo suportada
+const ProtocolVersion = "2025-06-18"
+
+// supportedVersions são as versões aceitas na negociação, da mais recente
+// para a mais antiga
+var supportedVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// Códigos de erro do JSON-RPC
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Request é uma requisição ou notificação JSON-RPC; notificações não têm ID
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// IsNotification indica se a mensagem não espera resposta
+func (r Request) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+// Response é a resposta a uma requisição JSON-RPC
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error é o erro de uma resposta JSON-RPC
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Implementation identifica o cliente ou o servidor
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// InitializeParams são os parâmetros de initialize
+type InitializeParams struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ClientInfo      Implementation `json:"clientInfo"`
+}
+
+// InitializeResult é a resposta de initialize
+type InitializeResult struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ServerInfo      Implementation `json:"serverInfo"`
+}
+
+// Tool é uma ferramenta oferecida pelo servidor
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+// ListToolsResult é a resposta de tools/list
+type ListToolsResult struct {
+	Tools      []Tool `json:"tools"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// CallToolParams são os parâmetros de tools/call
+type CallToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// Content é um trecho do resultado de uma ferramenta
+type Content struct {
+	Type string `json:"type"` // Apenas "text" é produzido; os demais tipos são ignorados
+	Text string `json:"text,omitempty"`
+}
+
+// CallToolResult é a resposta de tools/call. Erros da ferramenta vêm com
+// IsError, para que o agente os veja, e não como erros JSON-RPC.
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// Text une os trechos de texto do resultado
+func (r CallToolResult) Text() string {
+	var text string
+	for _, c := range r.Content {
+		if c.Type != "text" {
+			continue
+		}
+		if text != "" {
+			text += "\n"
+		}
+		text += c.Text
+	}
+	return text
+}
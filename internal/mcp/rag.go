@@ -0,0 +1,212 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/tools"
+)
+
+// Nomes das ferramentas do RAG expostas pelo servidor
+const (
+	SearchDocumentsTool = "search_documents"
+	AnswerQuestionTool  = "answer_question"
+)
+
+// maxSearchResults limita os documentos devolvidos por search_documents
+const maxSearchResults = 50
+
+// RAGTools cria o registro com as ferramentas do RAG: search_documents, que
+// busca documentos na base, e answer_question, que responde com o agente.
+// Cada pergunta é limitada a timeout; zero não limita.
+func RAGTools(svc domain.RAGService, timeout time.Duration) (*tools.Registry, error) {
+	h := &ragHandlers{svc: svc, timeout: timeout}
+	registry := tools.NewRegistry()
+	if err := registry.Register(searchDocumentsTool(), h.searchDocuments); err != nil {
+		return nil, err
+	}
+	if err := registry.Register(answerQuestionTool(), h.answerQuestion); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// ragHandlers executa as ferramentas com o serviço RAG
+type ragHandlers struct {
+	svc     domain.RAGService
+	timeout time.Duration
+}
+
+// searchDocumentsTool define a busca direta na base
+func searchDocumentsTool() domain.Tool {
+	return domain.Tool{
+		Name:        SearchDocumentsTool,
+		Description: "Search the knowledge base and return the most relevant documents, with title, link and content",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Text to search for",
+				},
+				"category": map[string]any{
+					"type":        "string",
+					"description": "Optional category to restrict the search to",
+				},
+				"tags": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Optional tags; documents with any of them are returned",
+				},
+				"filter": map[string]any{
+					"type": "string",
+					"description": "Optional filter expression on the document metadata, with clauses joined by AND: " +
+						"key=value, key IN (a, b), key>=n, key>n, key<=n, key<n and key EXISTS; " +
+						"category=value restricts the category and tags=value or tags IN (a, b) the tags. " +
+						"Quote values with spaces or commas. Ranges compare numbers; to compare versions part by part, " +
+						"write the bound as a version literal (version>=v\"1.20\" matches 1.100 but not 1.9). " +
+						"Example: version>=v\"1.20\" AND status IN (published, reviewed)",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"minimum":     1,
+					"maximum":     maxSearchResults,
+					"description": "Maximum number of documents to return",
+				},
+			},
+			"required": []string{"query"},
+		},
+	}
+}
+
+// answerQuestionTool define a pergunta respondida pelo agente do RAG
+func answerQuestionTool() domain.Tool {
+	return domain.Tool{
+		Name:        AnswerQuestionTool,
+		Description: "Answer a question with the knowledge base agent, which searches the documents and cites its sources",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"question": map[string]any{
+					"type":        "string",
+					"description": "Question to answer",
+				},
+				"session_id": map[string]any{
+					"type":        "string",
+					"description": "Optional session returned by a previous answer, to keep the conversation history",
+				},
+				"category": map[string]any{
+					"type":        "string",
+					"description": "Optional category to restrict the searches to",
+				},
+			},
+			"required": []string{"question"},
+		},
+	}
+}
+
+// documentResult é um documento devolvido pelas ferramentas
+type documentResult struct {
+	ID       string   `json:"id,omitempty"`
+	Title    string   `json:"title"`
+	Link     string   `json:"link,omitempty"`
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Content  string   `json:"content,omitempty"`
+}
+
+// newDocumentResult converte o documento do domínio
+func newDocumentResult(doc domain.Document, withContent bool) documentResult {
+	result := documentResult{
+		ID:       doc.ID,
+		Title:    doc.Title,
+		Link:     doc.Link,
+		Category: doc.Category,
+		Tags:     doc.Tags,
+	}
+	if withContent {
+		result.Content = doc.Content
+	}
+	return result
+}
+
+// searchDocuments executa search_documents
+func (h *ragHandlers) searchDocuments(ctx context.Context, arguments string) (*domain.ToolResult, error) {
+	var args struct {
+		Query    string   `json:"query"`
+		Category string   `json:"category"`
+		Tags     []string `json:"tags"`
+		Filter   string   `json:"filter"`
+		Limit    int      `json:"limit"`
+	}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return nil, fmt.Errorf("erro ao processar argumentos: %w", err)
+	}
+	expression, err := domain.ParseSearchFilter(args.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := h.svc.SearchDocuments(ctx, args.Query, domain.SearchFilter{Category: args.Category, Tags: args.Tags}.Merge(expression))
+	if err != nil {
+		return nil, err
+	}
+	if args.Limit > 0 && len(docs) > min(args.Limit, maxSearchResults) {
+		docs = docs[:min(args.Limit, maxSearchResults)]
+	}
+
+	results := make([]documentResult, 0, len(docs))
+	for _, doc := range docs {
+		results = append(results, newDocumentResult(doc, true))
+	}
+	return marshalResult(results)
+}
+
+// answerQuestion executa answer_question
+func (h *ragHandlers) answerQuestion(ctx context.Context, arguments string) (*domain.ToolResult, error) {
+	var args struct {
+		Question  string `json:"question"`
+		SessionID string `json:"session_id"`
+		Category  string `json:"category"`
+	}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return nil, fmt.Errorf("erro ao processar argumentos: %w", err)
+	}
+
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+	resp, err := h.svc.ProcessQuery(ctx, domain.RAGRequest{
+		Query:     args.Question,
+		SessionID: args.SessionID,
+		Category:  args.Category,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]documentResult, 0, len(resp.Sources))
+	for _, doc := range resp.Sources {
+		sources = append(sources, newDocumentResult(doc, false))
+	}
+	return marshalResult(struct {
+		Answer    string            `json:"answer"`
+		SessionID string            `json:"session_id,omitempty"`
+		Sources   []documentResult  `json:"sources"`
+		Citations []domain.Citation `json:"citations,omitempty"`
+	}{resp.Answer, resp.SessionID, sources, resp.Citations})
+}
+
+// marshalResult converte o resultado da ferramenta para JSON
+func marshalResult(v any) (*domain.ToolResult, error) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao converter para JSON: %w", err)
+	}
+	return &domain.ToolResult{Content: string(content)}, nil
+}
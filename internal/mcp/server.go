@@ -0,0 +1,203 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/tools"
+)
+
+// maxMessageSize limita o tamanho de cada mensagem recebida
+const maxMessageSize = 10 << 20
+
+// Server atende as requisições MCP com as ferramentas do registro
+type Server struct {
+	info  Implementation
+	tools *tools.Registry
+	token string // Opcional: exigido como Bearer nas requisições HTTP
+}
+
+// ServerOption configura o servidor
+type ServerOption func(*Server)
+
+// WithAuthToken exige o token informado no cabeçalho Authorization das
+// requisições HTTP
+func WithAuthToken(token string) ServerOption {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// NewServer cria um servidor que expõe as ferramentas do registro
+func NewServer(info Implementation, registry *tools.Registry, opts ...ServerOption) *Server {
+	s := &Server{info: info, tools: registry}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Handle processa uma mensagem JSON-RPC e retorna a resposta, ou nil para
+// notificações e respostas
+func (s *Server) Handle(ctx context.Context, raw []byte) *Response {
+	var req Request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return errorResponse(nil, &Error{Code: CodeParseError, Message: "mensagem JSON inválida"})
+	}
+	if req.Method == "" {
+		// Respostas a requisições do servidor, que não faz nenhuma
+		return nil
+	}
+	if req.JSONRPC != "2.0" {
+		return errorResponse(req.ID, &Error{Code: CodeInvalidRequest, Message: "jsonrpc deve ser 2.0"})
+	}
+
+	result, err := s.dispatch(ctx, req)
+	if req.IsNotification() {
+		return nil
+	}
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeInternalError, Message: err.Error()}
+		}
+		return errorResponse(req.ID, rpcErr)
+	}
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(req.ID, &Error{Code: CodeInternalError, Message: err.Error()})
+	}
+	return &Response{JSONRPC: "2.0", ID: req.ID, Result: payload}
+}
+
+// dispatch executa o método da requisição
+func (s *Server) dispatch(ctx context.Context, req Request) (any, error) {
+	switch req.Method {
+	case "initialize":
+		var params InitializeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+		version := ProtocolVersion
+		if slices.Contains(supportedVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		return InitializeResult{
+			ProtocolVersion: version,
+			Capabilities:    map[string]any{"tools": map[string]any{}},
+			ServerInfo:      s.info,
+		}, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		list := ListToolsResult{Tools: []Tool{}}
+		for _, tool := range s.tools.Tools() {
+			list.Tools = append(list.Tools, Tool{Name: tool.Name, Description: tool.Description, InputSchema: tool.Parameters})
+		}
+		return list, nil
+	case "tools/call":
+		var params CallToolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+		if !s.tools.Has(params.Name) {
+			return nil, &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("ferramenta desconhecida: %s", params.Name)}
+		}
+		arguments := string(params.Arguments)
+		if arguments == "" || arguments == "null" {
+			arguments = "{}"
+		}
+		result, err := s.tools.Execute(ctx, domain.ToolCall{Name: params.Name, Arguments: arguments})
+		if err != nil {
+			return CallToolResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+		}
+		return CallToolResult{Content: []Content{{Type: "text", Text: result.Content}}}, nil
+	default:
+		if req.IsNotification() {
+			// notifications/initialized, notifications/cancelled etc.
+			return nil, nil
+		}
+		return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("método desconhecido: %s", req.Method)}
+	}
+}
+
+// ServeStdio lê as mensagens de r, uma por linha, e escreve as respostas em
+// w, até o fim da entrada ou o cancelamento do contexto. As requisições são
+// atendidas em paralelo, então uma pergunta demorada não bloqueia as demais.
+func (s *Server) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+	for scanner.Scan() {
+		line := slices.Clone(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := s.Handle(ctx, line)
+			if resp == nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err := encoder.Encode(resp); err != nil {
+				log.Printf("Erro ao enviar resposta MCP: %v", err)
+			}
+		}()
+	}
+	return scanner.Err()
+}
+
+// ServeHTTP atende o transporte HTTP do protocolo: cada POST traz uma
+// mensagem e recebe a resposta em JSON, sem sessões nem streaming
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+s.token)) != 1 {
+		http.Error(w, "token inválido", http.StatusUnauthorized)
+		return
+	}
+
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, "mensagem muito grande", http.StatusRequestEntityTooLarge)
+		return
+	}
+	resp := s.Handle(r.Context(), raw)
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// errorResponse cria a resposta de erro
+func errorResponse(id json.RawMessage, err *Error) *Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &Response{JSONRPC: "2.0", ID: id, Error: err}
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// LLMClient decora um domain.LLMClient registrando a latência das chamadas
+type LLMClient struct {
+	next domain.LLMClient
+}
+
+// NewLLMClient instrumenta o cliente de LLM informado
+func NewLLMClient(next domain.LLMClient) *LLMClient {
+	return &LLMClient{next: next}
+}
+
+// GenerateResponse implementa domain.LLMClient
+func (c *LLMClient) GenerateResponse(ctx context.Context, messages []domain.Message, tools []domain.Tool) (*domain.Message, error) {
+	start := time.Now()
+	msg, err := c.next.GenerateResponse(ctx, messages, tools)
+	observe(llmDuration, start, "generate", status(err))
+	return msg, err
+}
+
+// GenerateResponseStream implementa domain.LLMClient. A duração é medida até
+// o fim do streaming, repassando os pedaços recebidos sem alterá-los.
+func (c *LLMClient) GenerateResponseStream(ctx context.Context, messages []domain.Message, tools []domain.Tool) (<-chan domain.LLMChunk, error) {
+	start := time.Now()
+	chunks, err := c.next.GenerateResponseStream(ctx, messages, tools)
+	if err != nil {
+		observe(llmDuration, start, "stream", status(err))
+		return nil, err
+	}
+
+	out := make(chan domain.LLMChunk)
+	go func() {
+		defer close(out)
+		var streamErr error
+		abandoned := false // Quem consome desistiu: o canal original é apenas esvaziado
+		for chunk := range chunks {
+			if chunk.Err != nil && streamErr == nil {
+				streamErr = chunk.Err
+			}
+			if abandoned {
+				continue
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				abandoned = true
+				if streamErr == nil {
+					streamErr = ctx.Err()
+				}
+			}
+		}
+		observe(llmDuration, start, "stream", status(streamErr))
+	}()
+	return out, nil
+}
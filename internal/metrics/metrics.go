@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Coletores expostos em /metrics
+var (
+	queriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rag_queries_total",
+		Help: "Perguntas processadas pelo agente, por resultado (ok, timeout, error) e cache.",
+	}, []string{"status", "cached"})
+
+	serviceDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rag_service_duration_seconds",
+		Help:    "Duração das operações do serviço RAG, incluindo a busca direta na base.",
+		Buckets: []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"method"})
+
+	llmDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rag_llm_duration_seconds",
+		Help:    "Duração das chamadas ao LLM, por operação (generate ou stream).",
+		Buckets: []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"operation", "status"})
+
+	tokensTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "rag_tokens_total",
+		Help: "Tokens consumidos pelo agente (estimados a partir do texto das mensagens).",
+	})
+
+	errorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rag_errors_total",
+		Help: "Erros retornados pelo serviço RAG, por tipo.",
+	}, []string{"type"})
+
+	dbDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rag_db_operation_duration_seconds",
+		Help:    "Duração das operações no banco de documentos, por operação.",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "status"})
+
+	qualityScore = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rag_quality_score",
+		Help:    "Notas das respostas avaliadas por amostragem, por métrica (faithfulness, context_relevance, answer_relevance).",
+		Buckets: prometheus.LinearBuckets(0.1, 0.1, 10),
+	}, []string{"metric"})
+)
+
+// Handler retorna o handler HTTP que expõe as métricas no formato do Prometheus
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// observe registra a duração desde start no histograma informado
+func observe(histogram *prometheus.HistogramVec, start time.Time, labels ...string) {
+	histogram.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+}
+
+// status retorna o rótulo de resultado de uma operação
+func status(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// errorType classifica o erro para o rótulo de rag_errors_total
+func errorType(err error) string {
+	var validation *domain.ValidationError
+	var timeout *domain.TimeoutResult
+	switch {
+	case errors.As(err, &validation), errors.Is(err, domain.ErrInvalidCursor), errors.Is(err, domain.ErrPromptTooLarge):
+		return "validation"
+	case errors.Is(err, domain.ErrDocumentNotFound), errors.Is(err, domain.ErrVersionNotFound):
+		return "not_found"
+	case errors.Is(err, domain.ErrVersionConflict), errors.Is(err, domain.ErrDuplicateDocument):
+		return "conflict"
+	case errors.Is(err, domain.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, domain.ErrContentBlocked):
+		return "blocked"
+	case errors.Is(err, domain.ErrLLMUnavailable), errors.Is(err, domain.ErrLLMQuotaExceeded):
+		return "unavailable"
+	case errors.As(err, &timeout), errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return "timeout"
+	default:
+		return "internal"
+	}
+}
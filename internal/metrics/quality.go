@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// QualityRepository decora um domain.QualityRepository registrando as notas
+// das respostas avaliadas em rag_quality_score
+type QualityRepository struct {
+	next domain.QualityRepository
+}
+
+// NewQualityRepository instrumenta o repositório informado
+func NewQualityRepository(next domain.QualityRepository) *QualityRepository {
+	return &QualityRepository{next: next}
+}
+
+// Record implementa domain.QualityRepository. As notas são registradas
+// mesmo que a gravação falhe.
+func (r *QualityRepository) Record(ctx context.Context, sample domain.QualitySample) error {
+	if sample.Scores.Faithfulness != nil {
+		qualityScore.WithLabelValues("faithfulness").Observe(*sample.Scores.Faithfulness)
+	}
+	if sample.Scores.ContextRelevance != nil {
+		qualityScore.WithLabelValues("context_relevance").Observe(*sample.Scores.ContextRelevance)
+	}
+	qualityScore.WithLabelValues("answer_relevance").Observe(sample.Scores.AnswerRelevance)
+
+	start := time.Now()
+	err := r.next.Record(ctx, sample)
+	observe(dbDuration, start, "quality_record", status(err))
+	return err
+}
+
+// Trend implementa domain.QualityRepository
+func (r *QualityRepository) Trend(ctx context.Context, since time.Time) ([]domain.QualityTrend, error) {
+	start := time.Now()
+	trend, err := r.next.Trend(ctx, since)
+	observe(dbDuration, start, "quality_trend", status(err))
+	return trend, err
+}
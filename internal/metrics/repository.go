@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// DocumentRepository decora um domain.DocumentRepository registrando a
+// duração de cada operação no banco
+type DocumentRepository struct {
+	next domain.DocumentRepository
+}
+
+// NewDocumentRepository instrumenta o repositório informado
+func NewDocumentRepository(next domain.DocumentRepository) *DocumentRepository {
+	return &DocumentRepository{next: next}
+}
+
+// SearchDocuments implementa domain.DocumentRepository
+func (r *DocumentRepository) SearchDocuments(ctx context.Context, query string, filter domain.SearchFilter) ([]domain.Document, error) {
+	start := time.Now()
+	docs, err := r.next.SearchDocuments(ctx, query, filter)
+	observe(dbDuration, start, "search_text", status(err))
+	return docs, err
+}
+
+// SearchByVector implementa domain.DocumentRepository
+func (r *DocumentRepository) SearchByVector(ctx context.Context, vector []float32, filter domain.SearchFilter) ([]domain.Document, error) {
+	start := time.Now()
+	docs, err := r.next.SearchByVector(ctx, vector, filter)
+	observe(dbDuration, start, "search_vector", status(err))
+	return docs, err
+}
+
+// SearchWithFacets implementa domain.DocumentRepository
+func (r *DocumentRepository) SearchWithFacets(ctx context.Context, query string, filter domain.SearchFilter) (*domain.FacetedSearch, error) {
+	start := time.Now()
+	search, err := r.next.SearchWithFacets(ctx, query, filter)
+	observe(dbDuration, start, "search_facets", status(err))
+	return search, err
+}
+
+// FindByID implementa domain.DocumentRepository
+func (r *DocumentRepository) FindByID(ctx context.Context, id string) (*domain.Document, error) {
+	start := time.Now()
+	doc, err := r.next.FindByID(ctx, id)
+	observe(dbDuration, start, "find_by_id", lookupStatus(err))
+	return doc, err
+}
+
+// FindByParentID implementa domain.DocumentRepository
+func (r *DocumentRepository) FindByParentID(ctx context.Context, parentID string) ([]domain.Document, error) {
+	start := time.Now()
+	docs, err := r.next.FindByParentID(ctx, parentID)
+	observe(dbDuration, start, "find_by_parent_id", status(err))
+	return docs, err
+}
+
+// FindByContentHash implementa domain.DocumentRepository
+func (r *DocumentRepository) FindByContentHash(ctx context.Context, hash string) (*domain.Document, error) {
+	start := time.Now()
+	doc, err := r.next.FindByContentHash(ctx, hash)
+	observe(dbDuration, start, "find_by_content_hash", lookupStatus(err))
+	return doc, err
+}
+
+// List implementa domain.DocumentRepository
+func (r *DocumentRepository) List(ctx context.Context, filter domain.DocumentFilter, cursor string, limit int) ([]domain.Document, string, error) {
+	start := time.Now()
+	docs, next, err := r.next.List(ctx, filter, cursor, limit)
+	observe(dbDuration, start, "list", lookupStatus(err))
+	return docs, next, err
+}
+
+// InsertDocument implementa domain.DocumentRepository
+func (r *DocumentRepository) InsertDocument(ctx context.Context, doc *domain.Document) error {
+	start := time.Now()
+	err := r.next.InsertDocument(ctx, doc)
+	observe(dbDuration, start, "insert", lookupStatus(err))
+	return err
+}
+
+// InsertMany implementa domain.DocumentRepository
+func (r *DocumentRepository) InsertMany(ctx context.Context, docs []*domain.Document) error {
+	start := time.Now()
+	err := r.next.InsertMany(ctx, docs)
+	observe(dbDuration, start, "insert_many", lookupStatus(err))
+	return err
+}
+
+// Update implementa domain.DocumentRepository
+func (r *DocumentRepository) Update(ctx context.Context, doc *domain.Document) error {
+	start := time.Now()
+	err := r.next.Update(ctx, doc)
+	observe(dbDuration, start, "update", lookupStatus(err))
+	return err
+}
+
+// GetVersionHistory implementa domain.DocumentRepository
+func (r *DocumentRepository) GetVersionHistory(ctx context.Context, id string) ([]domain.DocumentVersion, error) {
+	start := time.Now()
+	versions, err := r.next.GetVersionHistory(ctx, id)
+	observe(dbDuration, start, "get_version_history", status(err))
+	return versions, err
+}
+
+// SoftDelete implementa domain.DocumentRepository
+func (r *DocumentRepository) SoftDelete(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.next.SoftDelete(ctx, id)
+	observe(dbDuration, start, "soft_delete", lookupStatus(err))
+	return err
+}
+
+// SetEmbedding implementa domain.DocumentRepository
+func (r *DocumentRepository) SetEmbedding(ctx context.Context, id string, embedding []float32) error {
+	start := time.Now()
+	err := r.next.SetEmbedding(ctx, id, embedding)
+	observe(dbDuration, start, "set_embedding", lookupStatus(err))
+	return err
+}
+
+// Restore implementa domain.DocumentRepository
+func (r *DocumentRepository) Restore(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.next.Restore(ctx, id)
+	observe(dbDuration, start, "restore", lookupStatus(err))
+	return err
+}
+
+// HealthCheck implementa domain.DocumentRepository
+func (r *DocumentRepository) HealthCheck(ctx context.Context) error {
+	start := time.Now()
+	err := r.next.HealthCheck(ctx)
+	observe(dbDuration, start, "health_check", status(err))
+	return err
+}
+
+// lookupStatus funciona como status, mas considera documento inexistente,
+// cursor inválido e conflito de versão resultados esperados, e não falhas do banco
+func lookupStatus(err error) string {
+	if errors.Is(err, domain.ErrDocumentNotFound) || errors.Is(err, domain.ErrInvalidCursor) || errors.Is(err, domain.ErrVersionConflict) ||
+		errors.Is(err, domain.ErrDuplicateDocument) {
+		return "ok"
+	}
+	return status(err)
+}
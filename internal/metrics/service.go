@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Service decora um domain.RAGService registrando as métricas de cada operação
+type Service struct {
+	next domain.RAGService
+}
+
+// NewService instrumenta o serviço informado
+func NewService(next domain.RAGService) *Service {
+	return &Service{next: next}
+}
+
+// ProcessQuery implementa domain.RAGService
+func (s *Service) ProcessQuery(ctx context.Context, req domain.RAGRequest) (*domain.RAGResponse, error) {
+	defer observe(serviceDuration, time.Now(), "process_query")
+	resp, err := s.next.ProcessQuery(ctx, req)
+	recordQuery(resp, err)
+	return resp, err
+}
+
+// ProcessQueryStream implementa domain.RAGService
+func (s *Service) ProcessQueryStream(ctx context.Context, req domain.RAGRequest, onToken func(token string)) (*domain.RAGResponse, error) {
+	defer observe(serviceDuration, time.Now(), "process_query_stream")
+	resp, err := s.next.ProcessQueryStream(ctx, req, onToken)
+	recordQuery(resp, err)
+	return resp, err
+}
+
+// SearchDocuments implementa domain.RAGService
+func (s *Service) SearchDocuments(ctx context.Context, query string, filter domain.SearchFilter) ([]domain.Document, error) {
+	defer observe(serviceDuration, time.Now(), "search_documents")
+	docs, err := s.next.SearchDocuments(ctx, query, filter)
+	recordError(err)
+	return docs, err
+}
+
+// SearchWithFacets implementa domain.RAGService
+func (s *Service) SearchWithFacets(ctx context.Context, query string, filter domain.SearchFilter) (*domain.FacetedSearch, error) {
+	defer observe(serviceDuration, time.Now(), "search_facets")
+	search, err := s.next.SearchWithFacets(ctx, query, filter)
+	recordError(err)
+	return search, err
+}
+
+// AddDocument implementa domain.RAGService
+func (s *Service) AddDocument(ctx context.Context, doc *domain.Document) error {
+	defer observe(serviceDuration, time.Now(), "add_document")
+	err := s.next.AddDocument(ctx, doc)
+	recordError(err)
+	return err
+}
+
+// GetDocument implementa domain.RAGService
+func (s *Service) GetDocument(ctx context.Context, id string) (*domain.Document, error) {
+	defer observe(serviceDuration, time.Now(), "get_document")
+	doc, err := s.next.GetDocument(ctx, id)
+	recordError(err)
+	return doc, err
+}
+
+// ListDocuments implementa domain.RAGService
+func (s *Service) ListDocuments(ctx context.Context, filter domain.DocumentFilter, cursor string, limit int) ([]domain.Document, string, error) {
+	defer observe(serviceDuration, time.Now(), "list_documents")
+	docs, next, err := s.next.ListDocuments(ctx, filter, cursor, limit)
+	recordError(err)
+	return docs, next, err
+}
+
+// UpdateDocument implementa domain.RAGService
+func (s *Service) UpdateDocument(ctx context.Context, id string, doc *domain.Document) error {
+	defer observe(serviceDuration, time.Now(), "update_document")
+	err := s.next.UpdateDocument(ctx, id, doc)
+	recordError(err)
+	return err
+}
+
+// GetVersionHistory implementa domain.RAGService
+func (s *Service) GetVersionHistory(ctx context.Context, id string) ([]domain.DocumentVersion, error) {
+	defer observe(serviceDuration, time.Now(), "get_version_history")
+	versions, err := s.next.GetVersionHistory(ctx, id)
+	recordError(err)
+	return versions, err
+}
+
+// Rollback implementa domain.RAGService
+func (s *Service) Rollback(ctx context.Context, id string, version int) (*domain.Document, error) {
+	defer observe(serviceDuration, time.Now(), "rollback")
+	doc, err := s.next.Rollback(ctx, id, version)
+	recordError(err)
+	return doc, err
+}
+
+// DeleteDocument implementa domain.RAGService
+func (s *Service) DeleteDocument(ctx context.Context, id string) error {
+	defer observe(serviceDuration, time.Now(), "delete_document")
+	err := s.next.DeleteDocument(ctx, id)
+	recordError(err)
+	return err
+}
+
+// RestoreDocument implementa domain.RAGService
+func (s *Service) RestoreDocument(ctx context.Context, id string) error {
+	defer observe(serviceDuration, time.Now(), "restore_document")
+	err := s.next.RestoreDocument(ctx, id)
+	recordError(err)
+	return err
+}
+
+// ReembedDocument implementa domain.RAGService
+func (s *Service) ReembedDocument(ctx context.Context, id string) (int, error) {
+	defer observe(serviceDuration, time.Now(), "reembed_document")
+	updated, err := s.next.ReembedDocument(ctx, id)
+	recordError(err)
+	return updated, err
+}
+
+// HealthCheck implementa domain.RAGService
+func (s *Service) HealthCheck(ctx context.Context) error {
+	err := s.next.HealthCheck(ctx)
+	recordError(err)
+	return err
+}
+
+// recordQuery registra o resultado de uma pergunta e os tokens consumidos
+func recordQuery(resp *domain.RAGResponse, err error) {
+	if err != nil {
+		kind := errorType(err)
+		recordError(err)
+		if kind != "timeout" && kind != "rate_limited" {
+			kind = "error"
+		}
+		queriesTotal.WithLabelValues(kind, "false").Inc()
+		return
+	}
+
+	queriesTotal.WithLabelValues("ok", strconv.FormatBool(resp.Cached)).Inc()
+	for _, step := range resp.Steps {
+		tokensTotal.Add(float64(step.Tokens))
+	}
+}
+
+// recordError contabiliza o erro pelo seu tipo
+func recordError(err error) {
+	if err != nil {
+		errorsTotal.WithLabelValues(errorType(err)).Inc()
+	}
+}
@@ -0,0 +1,60 @@
+package pricing
+
+import (
+	"context"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// LLMClient decora um domain.LLMClient registrando o consumo de cada chamada
+// no Meter do contexto (veja WithMeter)
+type LLMClient struct {
+	next domain.LLMClient
+}
+
+// NewLLMClient registra o consumo das chamadas ao cliente de LLM informado
+func NewLLMClient(next domain.LLMClient) *LLMClient {
+	return &LLMClient{next: next}
+}
+
+// GenerateResponse implementa domain.LLMClient
+func (c *LLMClient) GenerateResponse(ctx context.Context, messages []domain.Message, tools []domain.Tool) (*domain.Message, error) {
+	msg, err := c.next.GenerateResponse(ctx, messages, tools)
+	if err == nil {
+		meterFrom(ctx).Add(msg.Usage)
+	}
+	return msg, err
+}
+
+// GenerateResponseStream implementa domain.LLMClient. O consumo vem no
+// último pedaço do streaming; os pedaços são repassados sem alteração.
+func (c *LLMClient) GenerateResponseStream(ctx context.Context, messages []domain.Message, tools []domain.Tool) (<-chan domain.LLMChunk, error) {
+	chunks, err := c.next.GenerateResponseStream(ctx, messages, tools)
+	if err != nil {
+		return nil, err
+	}
+	meter := meterFrom(ctx)
+	if meter == nil {
+		return chunks, nil
+	}
+
+	out := make(chan domain.LLMChunk)
+	go func() {
+		defer close(out)
+		abandoned := false // Quem consome desistiu: o canal original é apenas esvaziado
+		for chunk := range chunks {
+			if chunk.Done {
+				meter.Add(chunk.Usage)
+			}
+			if abandoned {
+				continue
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				abandoned = true
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,71 @@
+package pricing
+
+import (
+	"context"
+	"sync"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// meterKey é a chave do medidor de consumo no contexto
+type meterKey struct{}
+
+// Meter acumula o consumo das chamadas ao LLM feitas durante uma requisição.
+// É seguro para uso concorrente, já que as ferramentas do agente rodam em paralelo.
+type Meter struct {
+	mu    sync.Mutex
+	calls []domain.TokenUsage
+}
+
+// WithMeter retorna um contexto com um novo medidor de consumo. As chamadas
+// feitas com esse contexto por um LLMClient deste pacote são registradas nele.
+func WithMeter(ctx context.Context) (context.Context, *Meter) {
+	meter := &Meter{}
+	return context.WithValue(ctx, meterKey{}, meter), meter
+}
+
+// meterFrom retorna o medidor do contexto, ou nil se não houver
+func meterFrom(ctx context.Context) *Meter {
+	meter, _ := ctx.Value(meterKey{}).(*Meter)
+	return meter
+}
+
+// Add registra o consumo de uma chamada. Chamadas sem tokens são ignoradas.
+func (m *Meter) Add(usage domain.TokenUsage) {
+	if m == nil || usage.Total() == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, usage)
+}
+
+// Usage retorna o total de tokens consumidos. O modelo informado é o da
+// primeira chamada registrada.
+func (m *Meter) Usage() domain.TokenUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var total domain.TokenUsage
+	for _, call := range m.calls {
+		if total.Model == "" {
+			total.Model = call.Model
+		}
+		total.PromptTokens += call.PromptTokens
+		total.CompletionTokens += call.CompletionTokens
+	}
+	return total
+}
+
+// Cost retorna o custo total das chamadas, calculado com o preço do modelo
+// de cada uma delas
+func (m *Meter) Cost(table Table) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cost := 0.0
+	for _, call := range m.calls {
+		cost += table.Cost(call)
+	}
+	return cost
+}
@@ -0,0 +1,90 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Price é o preço de um modelo, em dólares por milhão de tokens
+type Price struct {
+	Prompt     float64 `json:"prompt"`     // Tokens de entrada
+	Completion float64 `json:"completion"` // Tokens de saída
+}
+
+// Table associa o nome (ou prefixo do nome) de cada modelo ao seu preço
+type Table map[string]Price
+
+// DefaultTable retorna os preços de tabela dos modelos mais usados. Modelos
+// locais (Ollama) não têm custo e ficam de fora.
+func DefaultTable() Table {
+	return Table{
+		"gpt-4-turbo":       {Prompt: 10, Completion: 30},
+		"gpt-4-0125":        {Prompt: 10, Completion: 30},
+		"gpt-4o":            {Prompt: 2.5, Completion: 10},
+		"gpt-4o-mini":       {Prompt: 0.15, Completion: 0.6},
+		"gpt-4.1":           {Prompt: 2, Completion: 8},
+		"gpt-4.1-mini":      {Prompt: 0.4, Completion: 1.6},
+		"claude-sonnet-4":   {Prompt: 3, Completion: 15},
+		"claude-haiku-4":    {Prompt: 1, Completion: 5},
+		"claude-opus-4":     {Prompt: 15, Completion: 75},
+		"claude-3-5-haiku":  {Prompt: 0.8, Completion: 4},
+		"claude-3-7-sonnet": {Prompt: 3, Completion: 15},
+	}
+}
+
+// TableFromEnv retorna a tabela padrão, sobrescrita pelos preços do arquivo
+// JSON indicado em LLM_PRICES_FILE (no formato {"modelo": {"prompt": 1, "completion": 2}})
+func TableFromEnv() (Table, error) {
+	table := DefaultTable()
+
+	path := os.Getenv("LLM_PRICES_FILE")
+	if path == "" {
+		return table, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler tabela de preços: %w", err)
+	}
+	var custom Table
+	if err := json.Unmarshal(raw, &custom); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar tabela de preços: %w", err)
+	}
+	maps.Copy(table, custom)
+	return table, nil
+}
+
+// Cost calcula o custo, em dólares, do consumo informado. O modelo é
+// procurado pelo nome exato e, se não houver, pelo maior prefixo cadastrado
+// (por exemplo, "gpt-4o-2024-08-06" usa o preço de "gpt-4o"). Modelos sem
+// preço têm custo zero.
+func (t Table) Cost(usage domain.TokenUsage) float64 {
+	price, ok := t.lookup(usage.Model)
+	if !ok {
+		return 0
+	}
+	return (float64(usage.PromptTokens)*price.Prompt + float64(usage.CompletionTokens)*price.Completion) / 1_000_000
+}
+
+// lookup busca o preço do modelo pelo nome exato ou pelo maior prefixo
+func (t Table) lookup(model string) (Price, bool) {
+	if price, ok := t[model]; ok {
+		return price, true
+	}
+
+	var best string
+	for name := range t {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return Price{}, false
+	}
+	return t[best], true
+}
@@ -0,0 +1,193 @@
+// Package prompts guarda os prompts enviados aos LLMs como templates
+// (text/template) nomeados e versionados. Os templates padrão são embutidos
+// no binário e podem ser substituídos por arquivos de um diretório.
+package prompts
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Nomes dos prompts usados pelo serviço RAG
+const (
+	// System orienta o agente sobre quando consultar a base (dados:
+	// SearchTool, o nome da ferramenta de busca, WebSearchTool, o da busca
+	// na web ou vazio, Tools, as ferramentas, Language, o nome do idioma
+	// da resposta ou vazio, Style, o estilo da resposta ou vazio, e
+	// MaxTokens, o limite de tokens da resposta ou zero)
+	System = "system"
+	// Answer pede a resposta final com os documentos já recuperados, quando
+	// o agente não pode mais chamar ferramentas
+	Answer = "answer"
+	// Expansion pede reformulações da consulta (dados: Count, Query)
+	Expansion = "expansion"
+	// HyDE pede uma resposta hipotética para a consulta (dados: Query)
+	HyDE = "hyde"
+	// Groundedness pede a verificação das afirmações da resposta contra as
+	// fontes (dados: Answer e Sources, com Index, Title e Content)
+	Groundedness = "groundedness"
+	// Revision pede uma nova resposta sem as afirmações não sustentadas
+	// pelas fontes (dados: Unsupported)
+	Revision = "revision"
+	// ContextRelevance pede a classificação das fontes relevantes para a
+	// pergunta (dados: Question e Sources, com Index, Title e Content)
+	ContextRelevance = "context_relevance"
+	// AnswerRelevance pede uma nota para o quanto a resposta atende à
+	// pergunta (dados: Question e Answer)
+	AnswerRelevance = "answer_relevance"
+	// Structured pede a resposta como um objeto JSON que siga um schema
+	// (dados: Query, Answer, Schema e, ao tentar de novo, Previous e Error)
+	Structured = "structured"
+	// QuestionGeneration pede perguntas de teste, com respostas de
+	// referência, sobre um documento (dados: Count, Title e Content)
+	QuestionGeneration = "question_generation"
+	// Summary pede o resumo de uma fonte focado na pergunta, para que as
+	// fontes caibam no contexto (dados: Words, Query, Title e Content)
+	Summary = "summary"
+	// Map pede a resposta parcial à pergunta com um único documento, na
+	// síntese map-reduce (dados: Query, Title e Content)
+	Map = "map"
+	// Reduce pede a resposta final combinando as respostas parciais, na
+	// síntese map-reduce (dados: Query, Language, Style, MaxTokens e
+	// Partials, com Ref, Title e Answer)
+	Reduce = "reduce"
+	// ConversationSummary pede o resumo dos turnos mais antigos de uma
+	// conversa (dados: Words, Previous, o resumo anterior ou vazio, e
+	// Messages, com Role e Content)
+	ConversationSummary = "conversation_summary"
+	// HistorySummary apresenta ao agente o resumo dos turnos mais antigos da
+	// conversa (dados: Summary)
+	HistorySummary = "history_summary"
+)
+
+// extension é a extensão dos arquivos de template; o nome do arquivo sem ela
+// é o nome do prompt
+const extension = ".tmpl"
+
+//go:embed templates/*.tmpl
+var defaults embed.FS
+
+// versionPattern reconhece a versão declarada no início do template, no
+// formato {{/* version: v2 */}}
+var versionPattern = regexp.MustCompile(`^\{\{-?\s*/\*\s*version:\s*(\S+)\s*\*/\s*-?\}\}`)
+
+// Template é um prompt nomeado e versionado
+type Template struct {
+	Name    string
+	Version string // Declarada no template ou, na falta dela, derivada do conteúdo
+	tmpl    *template.Template
+}
+
+// Parse interpreta o texto do template. Sem versão declarada, a versão é o
+// início do hash do conteúdo, então qualquer alteração muda a versão.
+func Parse(name, text string) (*Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar o prompt %q: %w", name, err)
+	}
+
+	version := ""
+	if match := versionPattern.FindStringSubmatch(text); match != nil {
+		version = match[1]
+	} else {
+		sum := sha256.Sum256([]byte(text))
+		version = "sha-" + hex.EncodeToString(sum[:4])
+	}
+	return &Template{Name: name, Version: version, tmpl: tmpl}, nil
+}
+
+// Render executa o template com os dados informados
+func (t *Template) Render(data any) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("erro ao gerar o prompt %q: %w", t.Name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Set é o conjunto de prompts disponíveis
+type Set struct {
+	templates map[string]*Template
+}
+
+// Config contém as configurações dos prompts
+type Config struct {
+	// Dir é o diretório com arquivos <nome>.tmpl que substituem os prompts
+	// padrão ou adicionam novos; vazio usa apenas os padrões
+	Dir string
+}
+
+// ConfigFromEnv lê a configuração dos prompts a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	return Config{Dir: os.Getenv("PROMPTS_DIR")}
+}
+
+// Default retorna o conjunto com os prompts padrão
+func Default() *Set {
+	set, err := load(&Set{templates: map[string]*Template{}}, defaults, "templates")
+	if err != nil {
+		panic(err) // Os templates embutidos são válidos
+	}
+	return set
+}
+
+// New retorna os prompts padrão, substituídos pelos do diretório configurado
+func New(cfg Config) (*Set, error) {
+	set := Default()
+	if cfg.Dir == "" {
+		return set, nil
+	}
+	return load(set, os.DirFS(cfg.Dir), ".")
+}
+
+// load adiciona ao conjunto os templates do diretório dir em fsys
+func load(set *Set, fsys fs.FS, dir string) (*Set, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*"+extension))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar prompts: %w", err)
+	}
+
+	for _, file := range files {
+		raw, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler prompt: %w", err)
+		}
+		name := strings.TrimSuffix(path.Base(file), extension)
+		tmpl, err := Parse(name, string(raw))
+		if err != nil {
+			return nil, err
+		}
+		set.templates[name] = tmpl
+	}
+	return set, nil
+}
+
+// Render executa o prompt com os dados informados
+func (s *Set) Render(name string, data any) (string, error) {
+	tmpl, ok := s.templates[name]
+	if !ok {
+		return "", fmt.Errorf("prompt %q não encontrado", name)
+	}
+	return tmpl.Render(data)
+}
+
+// Version identifica as versões de todos os prompts do conjunto, no formato
+// "nome@versão" separado por vírgulas e em ordem alfabética, para que uma
+// resposta possa ser reproduzida com os mesmos prompts
+func (s *Set) Version() string {
+	versions := make([]string, 0, len(s.templates))
+	for _, name := range slices.Sorted(maps.Keys(s.templates)) {
+		versions = append(versions, name+"@"+s.templates[name].Version)
+	}
+	return strings.Join(versions, ",")
+}
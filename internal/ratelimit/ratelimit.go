@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Armazenamentos disponíveis para os limites
+const (
+	BackendMemory = "memory" // Limites na memória do processo (padrão)
+	BackendRedis  = "redis"  // Limites no Redis, compartilhados entre instâncias
+)
+
+// sweepInterval é o intervalo mínimo entre as limpezas dos clientes inativos
+const sweepInterval = time.Minute
+
+// Config contém as configurações do limite de perguntas por cliente
+type Config struct {
+	// RequestsPerMinute é a taxa de perguntas aceitas por cliente; 0 desabilita o limite
+	RequestsPerMinute float64
+	// Burst é a quantidade de perguntas aceitas de uma vez, acumuladas
+	// enquanto o cliente não usa a API (padrão: RequestsPerMinute)
+	Burst   int
+	Backend string // "memory" (padrão) ou "redis"
+	// RedisURL é o endereço do Redis usado com o backend "redis"
+	RedisURL string
+}
+
+// ConfigFromEnv lê a configuração do limite a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Backend:  os.Getenv("RATE_LIMIT_BACKEND"),
+		RedisURL: os.Getenv("REDIS_URL"),
+	}
+	cfg.RequestsPerMinute, _ = strconv.ParseFloat(os.Getenv("RATE_LIMIT_PER_MINUTE"), 64)
+	cfg.Burst, _ = strconv.Atoi(os.Getenv("RATE_LIMIT_BURST"))
+	return cfg
+}
+
+// Limiter é um domain.RateLimiter que mantém recursos abertos
+type Limiter interface {
+	domain.RateLimiter
+	// Close libera as conexões usadas pelo limite
+	Close() error
+}
+
+// New cria o limite configurado, com um token bucket por cliente. Retorna
+// nil quando o limite está desabilitado.
+func New(ctx context.Context, cfg Config) (Limiter, error) {
+	if cfg.RequestsPerMinute <= 0 {
+		return nil, nil
+	}
+	rate := cfg.RequestsPerMinute / 60
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = max(int(math.Ceil(cfg.RequestsPerMinute)), 1)
+	}
+
+	switch cfg.Backend {
+	case "", BackendMemory:
+		return NewMemoryLimiter(rate, burst), nil
+	case BackendRedis:
+		if cfg.RedisURL == "" {
+			return nil, fmt.Errorf("REDIS_URL não definida")
+		}
+		return NewRedisLimiter(ctx, cfg.RedisURL, rate, burst)
+	default:
+		return nil, fmt.Errorf("backend de limite desconhecido: %q", cfg.Backend)
+	}
+}
+
+// MemoryLimiter implementa o token bucket na memória do processo. Com várias
+// instâncias, cada uma aplica o limite separadamente.
+type MemoryLimiter struct {
+	rate  float64 // Requisições repostas por segundo
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// bucket guarda as requisições disponíveis de um cliente
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewMemoryLimiter cria um limite de rate requisições por segundo, com até
+// burst requisições acumuladas
+func NewMemoryLimiter(rate float64, burst int) *MemoryLimiter {
+	return &MemoryLimiter{
+		rate:      rate,
+		burst:     float64(burst),
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow consome uma requisição do cliente
+func (l *MemoryLimiter) Allow(ctx context.Context, key string) error {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.updated = now
+
+	if b.tokens < 1 {
+		wait := (1 - b.tokens) / l.rate
+		return &domain.RateLimitError{RetryAfter: time.Duration(wait * float64(time.Second))}
+	}
+	b.tokens--
+	return nil
+}
+
+// Close não faz nada: o limite não mantém conexões
+func (l *MemoryLimiter) Close() error {
+	return nil
+}
+
+// refill retorna as requisições disponíveis no bucket no instante informado
+func (l *MemoryLimiter) refill(b *bucket, now time.Time) float64 {
+	return min(l.burst, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+}
+
+// sweep descarta os buckets cheios, que seriam recriados iguais, para que
+// clientes inativos não ocupem memória
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
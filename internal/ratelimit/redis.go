@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix é o prefixo das chaves dos limites no Redis
+const keyPrefix = "rag:ratelimit:"
+
+// tokenBucket atualiza o bucket do cliente de forma atômica e retorna 0
+// quando a requisição é aceita ou os milissegundos até a próxima ser aceita.
+// O relógio do Redis é usado para que todas as instâncias concordem.
+var tokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate)
+
+local wait = 0
+if tokens < 1 then
+	wait = math.ceil((1 - tokens) / rate)
+else
+	tokens = tokens - 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate))
+return wait
+`)
+
+// RedisLimiter implementa o token bucket no Redis, compartilhando os limites
+// entre todas as instâncias da aplicação
+type RedisLimiter struct {
+	client *redis.Client
+	rate   float64 // Requisições repostas por milissegundo
+	burst  int
+}
+
+// NewRedisLimiter conecta ao Redis e cria um limite de rate requisições por
+// segundo, com até burst requisições acumuladas
+func NewRedisLimiter(ctx context.Context, url string, rate float64, burst int) (*RedisLimiter, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar REDIS_URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("erro ao conectar ao Redis: %w", err)
+	}
+
+	return &RedisLimiter{client: client, rate: rate / 1000, burst: burst}, nil
+}
+
+// Allow consome uma requisição do cliente
+func (l *RedisLimiter) Allow(ctx context.Context, key string) error {
+	wait, err := tokenBucket.Run(ctx, l.client, []string{keyPrefix + key}, l.rate, l.burst).Int64()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar limite de requisições: %w", err)
+	}
+	if wait > 0 {
+		return &domain.RateLimitError{RetryAfter: time.Duration(wait) * time.Millisecond}
+	}
+	return nil
+}
+
+// Close fecha a conexão com o Redis
+func (l *RedisLimiter) Close() error {
+	return l.client.Close()
+}
@@ -0,0 +1,124 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/ingest"
+	"github.com/alextavella/agentic-rag/internal/loader"
+	"github.com/robfig/cron/v3"
+)
+
+// Result resume uma atualização de fonte
+type Result struct {
+	Fetched int // Páginas baixadas (as não modificadas, pelo ETag, não contam)
+	Changed int // Páginas com conteúdo novo, divididas e indexadas novamente
+	Chunks  int // Chunks gravados
+}
+
+// Refresher baixa novamente as fontes e indexa apenas as páginas que
+// mudaram: o ETag e o Last-Modified de cada página evitam o download das
+// que não mudaram, e o hash do conteúdo evita gerar de novo os embeddings
+// das que foram baixadas sem alteração
+type Refresher struct {
+	ingester    *ingest.Ingester // Deve substituir as páginas pelo link (ingest.WithReplaceByLink)
+	validators  *loader.Validators
+	concurrency int
+}
+
+// New cria um Refresher que grava as páginas alteradas com o ingester,
+// baixando até concurrency páginas de cada sitemap em paralelo
+func New(ingester *ingest.Ingester, concurrency int) *Refresher {
+	return &Refresher{ingester: ingester, validators: loader.NewValidators(), concurrency: concurrency}
+}
+
+// Refresh baixa a fonte e indexa as páginas alteradas, no tenant da fonte
+func (r *Refresher) Refresh(ctx context.Context, source Source) (Result, error) {
+	ctx = domain.WithTenant(ctx, source.Tenant)
+
+	var result Result
+	handle := func(doc *domain.Document) error {
+		result.Fetched++
+		doc.Category = source.Category
+		chunks, err := r.ingester.Ingest(ctx, []domain.Document{*doc})
+		if err != nil {
+			return err
+		}
+		if chunks > 0 {
+			result.Changed++
+			result.Chunks += chunks
+		}
+		return nil
+	}
+
+	if source.Type == SourceSitemap {
+		crawler := loader.NewCrawler(nil, loader.CrawlerConfig{
+			Concurrency: r.concurrency,
+			MaxPages:    source.MaxPages,
+			Validators:  r.validators,
+		})
+		return result, crawler.Crawl(ctx, source.URL, handle)
+	}
+
+	doc, err := loader.NewHTMLLoader(nil, loader.WithValidators(r.validators)).LoadURL(ctx, source.URL)
+	if errors.Is(err, loader.ErrNotModified) {
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	return result, handle(doc)
+}
+
+// RefreshAll atualiza as fontes uma vez, em sequência, e retorna o primeiro
+// erro; uma fonte com erro não impede a atualização das demais
+func (r *Refresher) RefreshAll(ctx context.Context, sources []Source, timeout time.Duration) error {
+	var firstErr error
+	for _, source := range sources {
+		if err := r.run(ctx, source, timeout); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Run agenda a atualização de cada fonte conforme o seu schedule e bloqueia
+// até o cancelamento do contexto, quando espera as atualizações em
+// andamento terminarem. Uma fonte cuja atualização anterior ainda não
+// terminou pula o horário.
+func (r *Refresher) Run(ctx context.Context, sources []Source, timeout time.Duration) error {
+	scheduler := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	for _, source := range sources {
+		_, err := scheduler.AddFunc(source.Schedule, func() {
+			r.run(ctx, source, timeout)
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Fonte %s agendada: %s", source.Name, source.Schedule)
+	}
+
+	scheduler.Start()
+	<-ctx.Done()
+	<-scheduler.Stop().Done()
+	return nil
+}
+
+// run atualiza a fonte, limitada ao timeout, e registra o resultado no log
+func (r *Refresher) run(ctx context.Context, source Source, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := r.Refresh(ctx, source)
+	if err != nil {
+		log.Printf("Erro ao atualizar a fonte %s: %v", source.Name, err)
+		return err
+	}
+	log.Printf("Fonte %s atualizada em %s: %d páginas baixadas, %d alteradas, %d chunks gravados",
+		source.Name, time.Since(start).Round(time.Millisecond), result.Fetched, result.Changed, result.Chunks)
+	return nil
+}
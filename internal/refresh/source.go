@@ -0,0 +1,72 @@
+package refresh
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/robfig/cron/v3"
+)
+
+// Tipos de fonte
+const (
+	SourcePage    = "page"    // Uma única página
+	SourceSitemap = "sitemap" // Todas as páginas de um sitemap.xml
+)
+
+// Source é uma fonte de documentos baixada novamente de tempos em tempos
+type Source struct {
+	Name     string `json:"name"`      // Nome usado nos logs (padrão: o endereço)
+	Type     string `json:"type"`      // page (padrão) ou sitemap
+	URL      string `json:"url"`       // Endereço da página ou do sitemap
+	Schedule string `json:"schedule"`  // Expressão cron de 5 campos, ou @hourly, @every 6h etc.
+	Category string `json:"category"`  // Categoria dos documentos
+	Tenant   string `json:"tenant"`    // Tenant dos documentos (vazio no tenant padrão)
+	MaxPages int    `json:"max_pages"` // Limite de páginas do sitemap; 0 processa todas
+}
+
+// LoadSources lê a lista de fontes de um arquivo JSON e valida cada uma
+func LoadSources(path string) ([]Source, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler fontes: %w", err)
+	}
+
+	var sources []Source
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar fontes de %s: %w", path, err)
+	}
+	for i := range sources {
+		if err := sources[i].validate(); err != nil {
+			return nil, fmt.Errorf("fonte %d de %s: %w", i+1, path, err)
+		}
+	}
+	return sources, nil
+}
+
+// validate verifica os campos da fonte e preenche os padrões
+func (s *Source) validate() error {
+	target, err := url.Parse(s.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("url inválida: %q", s.URL)
+	}
+	if s.Name == "" {
+		s.Name = s.URL
+	}
+	switch s.Type {
+	case "":
+		s.Type = SourcePage
+	case SourcePage, SourceSitemap:
+	default:
+		return fmt.Errorf("tipo deve ser %s ou %s: %q", SourcePage, SourceSitemap, s.Type)
+	}
+	if _, err := cron.ParseStandard(s.Schedule); err != nil {
+		return fmt.Errorf("schedule inválido %q: %w", s.Schedule, err)
+	}
+	if s.MaxPages < 0 {
+		return fmt.Errorf("max_pages não pode ser negativo")
+	}
+	return domain.ValidateTenantID(s.Tenant)
+}
@@ -0,0 +1,117 @@
+package rerank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Valores padrão do reranker externo
+const (
+	defaultAPIBaseURL = "https://api.cohere.com/v2"
+	defaultAPIModel   = "rerank-v3.5"
+)
+
+// APIConfig contém as configurações de um serviço externo de rerank
+type APIConfig struct {
+	BaseURL string // Endereço da API (padrão: Cohere)
+	APIKey  string
+	Model   string
+}
+
+// APIReranker reordena os documentos usando um serviço externo compatível
+// com a API de rerank da Cohere (POST /rerank com query e documents)
+type APIReranker struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	model      string
+}
+
+// NewAPIReranker cria um reranker para o serviço externo configurado
+func NewAPIReranker(cfg APIConfig) *APIReranker {
+	r := &APIReranker{
+		httpClient: http.DefaultClient,
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		apiKey:     cfg.APIKey,
+		model:      cfg.Model,
+	}
+	if r.baseURL == "" {
+		r.baseURL = defaultAPIBaseURL
+	}
+	if r.model == "" {
+		r.model = defaultAPIModel
+	}
+	return r
+}
+
+// apiRequest é o corpo de uma chamada a /rerank
+type apiRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+}
+
+// apiResponse é a resposta de /rerank
+type apiResponse struct {
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+}
+
+// Rerank retorna os documentos do mais para o menos relevante
+func (r *APIReranker) Rerank(ctx context.Context, query string, docs []domain.Document) ([]domain.Document, error) {
+	if len(docs) < 2 {
+		return docs, nil
+	}
+
+	body := apiRequest{Model: r.model, Query: query}
+	for _, doc := range docs {
+		body.Documents = append(body.Documents, doc.EmbeddingText())
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar requisição de rerank: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL+"/rerank", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição de rerank: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+r.apiKey)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro na chamada de rerank: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, fmt.Errorf("erro na chamada de rerank (status %d): %s", resp.StatusCode, raw)
+	}
+
+	var result apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta de rerank: %w", err)
+	}
+
+	sort.SliceStable(result.Results, func(i, j int) bool {
+		return result.Results[i].RelevanceScore > result.Results[j].RelevanceScore
+	})
+	ranking := make([]int, len(result.Results))
+	for i, item := range result.Results {
+		ranking[i] = item.Index
+	}
+	return reorder(docs, ranking), nil
+}
@@ -0,0 +1,90 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/llmjson"
+)
+
+// maxSnippetLength limita o conteúdo de cada documento enviado ao LLM
+const maxSnippetLength = 1000
+
+// rerankPrompt instrui o modelo a ordenar os documentos pela relevância
+const rerankPrompt = `You are a search relevance judge. Rank the documents below by how well they answer the query.
+Reply only with a JSON object in the format {"ranking": [<document numbers, most relevant first>]}.
+
+Query: %s
+
+Documents:
+%s`
+
+// LLMReranker reordena os documentos pedindo ao próprio LLM que os classifique
+type LLMReranker struct {
+	llm domain.LLMClient
+}
+
+// NewLLMReranker cria um reranker baseado no LLM informado
+func NewLLMReranker(llm domain.LLMClient) *LLMReranker {
+	return &LLMReranker{llm: llm}
+}
+
+// Rerank retorna os documentos do mais para o menos relevante
+func (r *LLMReranker) Rerank(ctx context.Context, query string, docs []domain.Document) ([]domain.Document, error) {
+	if len(docs) < 2 {
+		return docs, nil
+	}
+
+	var list strings.Builder
+	for i, doc := range docs {
+		fmt.Fprintf(&list, "[%d] %s\n%s\n\n", i, doc.Title, truncate(doc.Content, maxSnippetLength))
+	}
+
+	resp, err := r.llm.GenerateResponse(ctx, []domain.Message{
+		{Role: domain.RoleUser, Content: fmt.Sprintf(rerankPrompt, query, list.String())},
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao reordenar documentos: %w", err)
+	}
+
+	var result struct {
+		Ranking []int `json:"ranking"`
+	}
+	if err := json.Unmarshal([]byte(llmjson.Extract(resp.Content)), &result); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar ordenação do LLM: %w", err)
+	}
+
+	return reorder(docs, result.Ranking), nil
+}
+
+// reorder aplica a ordem de índices informada. Índices inválidos ou repetidos
+// são ignorados e os documentos não citados são mantidos no final, na ordem original.
+func reorder(docs []domain.Document, ranking []int) []domain.Document {
+	used := make([]bool, len(docs))
+	result := make([]domain.Document, 0, len(docs))
+	for _, index := range ranking {
+		if index < 0 || index >= len(docs) || used[index] {
+			continue
+		}
+		used[index] = true
+		result = append(result, docs[index])
+	}
+	for i, doc := range docs {
+		if !used[i] {
+			result = append(result, doc)
+		}
+	}
+	return result
+}
+
+// truncate limita o texto a max caracteres
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "..."
+}
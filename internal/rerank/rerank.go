@@ -0,0 +1,64 @@
+package rerank
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Tipos de reranker disponíveis
+const (
+	TypeNone = ""    // Sem rerank: mantém a ordem da busca
+	TypeLLM  = "llm" // Ordenação feita pelo próprio LLM do agente
+	TypeAPI  = "api" // Serviço externo compatível com a API da Cohere
+	// TypeCohere usa a API da Cohere, com a chave de COHERE_API_KEY quando
+	// RERANK_API_KEY não é definida
+	TypeCohere = "cohere"
+)
+
+// Config contém as configurações do reranker
+type Config struct {
+	Type         string
+	API          APIConfig
+	CohereAPIKey string // Chave da Cohere compartilhada com os embeddings
+}
+
+// ConfigFromEnv lê a configuração do reranker a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	return Config{
+		Type: os.Getenv("RERANKER"),
+		API: APIConfig{
+			BaseURL: os.Getenv("RERANK_API_URL"),
+			APIKey:  os.Getenv("RERANK_API_KEY"),
+			Model:   os.Getenv("RERANK_MODEL"),
+		},
+		CohereAPIKey: os.Getenv("COHERE_API_KEY"),
+	}
+}
+
+// New cria o reranker configurado. Retorna nil quando o rerank está desabilitado.
+func New(cfg Config, llm domain.LLMClient) (domain.Reranker, error) {
+	switch cfg.Type {
+	case TypeNone:
+		return nil, nil
+	case TypeLLM:
+		return NewLLMReranker(llm), nil
+	case TypeAPI:
+		if cfg.API.APIKey == "" {
+			return nil, fmt.Errorf("RERANK_API_KEY não definida")
+		}
+		return NewAPIReranker(cfg.API), nil
+	case TypeCohere:
+		api := cfg.API
+		if api.APIKey == "" {
+			api.APIKey = cfg.CohereAPIKey
+		}
+		if api.APIKey == "" {
+			return nil, fmt.Errorf("COHERE_API_KEY não definida")
+		}
+		return NewAPIReranker(api), nil
+	default:
+		return nil, fmt.Errorf("tipo de reranker desconhecido: %q", cfg.Type)
+	}
+}
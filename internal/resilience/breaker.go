@@ -0,0 +1,208 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Valores padrão do circuit breaker
+const (
+	DefaultFailureThreshold = 5
+	DefaultOpenTimeout      = 30 * time.Second
+)
+
+// State é o estado do circuito
+type State int
+
+// Estados do circuito
+const (
+	StateClosed   State = iota // Chamadas repassadas normalmente
+	StateOpen                  // Chamadas recusadas com domain.ErrLLMUnavailable
+	StateHalfOpen              // Uma chamada de teste decide se o circuito fecha
+)
+
+// String implementa fmt.Stringer
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "aberto"
+	case StateHalfOpen:
+		return "semiaberto"
+	default:
+		return "fechado"
+	}
+}
+
+// BreakerConfig contém as configurações do circuit breaker
+type BreakerConfig struct {
+	// FailureThreshold é a quantidade de falhas seguidas que abre o circuito
+	FailureThreshold int
+	// OpenTimeout é o tempo que o circuito fica aberto antes da chamada de teste
+	OpenTimeout time.Duration
+}
+
+// BreakerConfigFromEnv lê a configuração do circuit breaker a partir das
+// variáveis de ambiente
+func BreakerConfigFromEnv() BreakerConfig {
+	var cfg BreakerConfig
+	cfg.FailureThreshold, _ = strconv.Atoi(os.Getenv("LLM_BREAKER_FAILURES"))
+	cfg.OpenTimeout, _ = time.ParseDuration(os.Getenv("LLM_BREAKER_OPEN_TIMEOUT"))
+	return cfg
+}
+
+// CircuitBreaker decora um domain.LLMClient interrompendo as chamadas depois
+// de falhas seguidas (erros ou timeouts). Com o circuito aberto, as chamadas
+// falham imediatamente com domain.ErrLLMUnavailable; passado OpenTimeout,
+// uma única chamada de teste é repassada e, se tiver sucesso, fecha o
+// circuito. Chamadas canceladas por quem as fez não contam como falha.
+type CircuitBreaker struct {
+	next        domain.LLMClient
+	threshold   int
+	openTimeout time.Duration
+
+	mu       sync.Mutex
+	state    State
+	failures int // Falhas seguidas com o circuito fechado
+	openedAt time.Time
+	probing  bool // Há uma chamada de teste em andamento
+}
+
+// NewCircuitBreaker protege o cliente de LLM informado
+func NewCircuitBreaker(next domain.LLMClient, cfg BreakerConfig) *CircuitBreaker {
+	b := &CircuitBreaker{
+		next:        next,
+		threshold:   cfg.FailureThreshold,
+		openTimeout: cfg.OpenTimeout,
+	}
+	if b.threshold <= 0 {
+		b.threshold = DefaultFailureThreshold
+	}
+	if b.openTimeout <= 0 {
+		b.openTimeout = DefaultOpenTimeout
+	}
+	return b
+}
+
+// State retorna o estado atual do circuito
+func (b *CircuitBreaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// GenerateResponse implementa domain.LLMClient
+func (b *CircuitBreaker) GenerateResponse(ctx context.Context, messages []domain.Message, tools []domain.Tool) (*domain.Message, error) {
+	probe, err := b.acquire()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := b.next.GenerateResponse(ctx, messages, tools)
+	b.release(ctx, probe, err)
+	return msg, err
+}
+
+// GenerateResponseStream implementa domain.LLMClient. O resultado da chamada
+// só é conhecido ao fim do streaming; os pedaços são repassados sem alteração.
+func (b *CircuitBreaker) GenerateResponseStream(ctx context.Context, messages []domain.Message, tools []domain.Tool) (<-chan domain.LLMChunk, error) {
+	probe, err := b.acquire()
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := b.next.GenerateResponseStream(ctx, messages, tools)
+	if err != nil {
+		b.release(ctx, probe, err)
+		return nil, err
+	}
+
+	out := make(chan domain.LLMChunk)
+	go func() {
+		defer close(out)
+		var streamErr error
+		abandoned := false // Quem consome desistiu: o canal original é apenas esvaziado
+		for chunk := range chunks {
+			if chunk.Err != nil && streamErr == nil {
+				streamErr = chunk.Err
+			}
+			if abandoned {
+				continue
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				abandoned = true
+				if streamErr == nil {
+					streamErr = ctx.Err()
+				}
+			}
+		}
+		b.release(ctx, probe, streamErr)
+	}()
+	return out, nil
+}
+
+// acquire verifica se a chamada pode ser feita e informa se ela é a chamada
+// de teste do circuito semiaberto
+func (b *CircuitBreaker) acquire() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return false, domain.ErrLLMUnavailable
+		}
+		b.state = StateHalfOpen
+		fallthrough
+	case StateHalfOpen:
+		if b.probing {
+			return false, domain.ErrLLMUnavailable
+		}
+		b.probing = true
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// release registra o resultado da chamada
+func (b *CircuitBreaker) release(ctx context.Context, probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+	}
+	switch {
+	case err != nil && errors.Is(err, context.Canceled) && ctx.Err() != nil:
+		// Cancelada por quem chamou: não diz nada sobre o LLM
+	case err == nil:
+		b.failures = 0
+		if probe {
+			b.state = StateClosed
+			log.Println("Circuito do LLM fechado: chamada de teste bem-sucedida")
+		}
+	case probe:
+		b.open()
+		log.Printf("Circuito do LLM aberto novamente: chamada de teste falhou: %v", err)
+	case b.state == StateClosed:
+		b.failures++
+		if b.failures >= b.threshold {
+			log.Printf("Circuito do LLM aberto após %d falhas seguidas: %v", b.failures, err)
+			b.open()
+		}
+	}
+}
+
+// open abre o circuito a partir de agora
+func (b *CircuitBreaker) open() {
+	b.state = StateOpen
+	b.openedAt = time.Now()
+	b.failures = 0
+}
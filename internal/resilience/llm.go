@@ -0,0 +1,59 @@
+package resilience
+
+import (
+	"context"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// RetryLLMClient decora um domain.LLMClient repetindo as chamadas que falham
+// com erros temporários, como o limite de requisições (429) do provedor
+type RetryLLMClient struct {
+	next   domain.LLMClient
+	policy RetryPolicy
+}
+
+// NewRetryLLMClient aplica a política de novas tentativas ao cliente de LLM
+func NewRetryLLMClient(next domain.LLMClient, policy RetryPolicy) *RetryLLMClient {
+	return &RetryLLMClient{next: next, policy: policy}
+}
+
+// GenerateResponse implementa domain.LLMClient
+func (c *RetryLLMClient) GenerateResponse(ctx context.Context, messages []domain.Message, tools []domain.Tool) (*domain.Message, error) {
+	return Retry(ctx, c.policy, "chamada ao LLM", func(ctx context.Context) (*domain.Message, error) {
+		return c.next.GenerateResponse(ctx, messages, tools)
+	})
+}
+
+// GenerateResponseStream implementa domain.LLMClient. Apenas a abertura do
+// streaming é repetida: depois que os primeiros pedaços foram entregues, uma
+// nova tentativa duplicaria o texto já recebido.
+func (c *RetryLLMClient) GenerateResponseStream(ctx context.Context, messages []domain.Message, tools []domain.Tool) (<-chan domain.LLMChunk, error) {
+	return Retry(ctx, c.policy, "chamada ao LLM", func(ctx context.Context) (<-chan domain.LLMChunk, error) {
+		return c.next.GenerateResponseStream(ctx, messages, tools)
+	})
+}
+
+// RetryEmbeddingClient decora um domain.EmbeddingClient repetindo as
+// chamadas que falham com erros temporários
+type RetryEmbeddingClient struct {
+	next   domain.EmbeddingClient
+	policy RetryPolicy
+}
+
+// NewRetryEmbeddingClient aplica a política de novas tentativas ao cliente de embeddings
+func NewRetryEmbeddingClient(next domain.EmbeddingClient, policy RetryPolicy) *RetryEmbeddingClient {
+	return &RetryEmbeddingClient{next: next, policy: policy}
+}
+
+// Embed implementa domain.EmbeddingClient
+func (c *RetryEmbeddingClient) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return Retry(ctx, c.policy, "geração de embeddings", func(ctx context.Context) ([][]float32, error) {
+		return c.next.Embed(ctx, texts)
+	})
+}
+
+// Dimensions implementa domain.EmbeddingClient
+func (c *RetryEmbeddingClient) Dimensions() int {
+	return c.next.Dimensions()
+}
@@ -0,0 +1,119 @@
+package resilience
+
+import (
+	"context"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// RetryRepository decora um domain.DocumentRepository repetindo as leituras
+// que falham com erros temporários. As escritas não são repetidas: uma
+// inserção ou atualização que falhou depois de chegar à base seria aplicada
+// duas vezes.
+type RetryRepository struct {
+	next   domain.DocumentRepository
+	policy RetryPolicy
+}
+
+// NewRetryRepository aplica a política de novas tentativas às leituras do repositório
+func NewRetryRepository(next domain.DocumentRepository, policy RetryPolicy) *RetryRepository {
+	return &RetryRepository{next: next, policy: policy}
+}
+
+// SearchDocuments implementa domain.DocumentRepository
+func (r *RetryRepository) SearchDocuments(ctx context.Context, query string, filter domain.SearchFilter) ([]domain.Document, error) {
+	return Retry(ctx, r.policy, "busca textual", func(ctx context.Context) ([]domain.Document, error) {
+		return r.next.SearchDocuments(ctx, query, filter)
+	})
+}
+
+// SearchByVector implementa domain.DocumentRepository
+func (r *RetryRepository) SearchByVector(ctx context.Context, vector []float32, filter domain.SearchFilter) ([]domain.Document, error) {
+	return Retry(ctx, r.policy, "busca vetorial", func(ctx context.Context) ([]domain.Document, error) {
+		return r.next.SearchByVector(ctx, vector, filter)
+	})
+}
+
+// SearchWithFacets implementa domain.DocumentRepository
+func (r *RetryRepository) SearchWithFacets(ctx context.Context, query string, filter domain.SearchFilter) (*domain.FacetedSearch, error) {
+	return Retry(ctx, r.policy, "busca com facets", func(ctx context.Context) (*domain.FacetedSearch, error) {
+		return r.next.SearchWithFacets(ctx, query, filter)
+	})
+}
+
+// FindByID implementa domain.DocumentRepository
+func (r *RetryRepository) FindByID(ctx context.Context, id string) (*domain.Document, error) {
+	return Retry(ctx, r.policy, "busca de documento", func(ctx context.Context) (*domain.Document, error) {
+		return r.next.FindByID(ctx, id)
+	})
+}
+
+// FindByParentID implementa domain.DocumentRepository
+func (r *RetryRepository) FindByParentID(ctx context.Context, parentID string) ([]domain.Document, error) {
+	return Retry(ctx, r.policy, "busca de chunks", func(ctx context.Context) ([]domain.Document, error) {
+		return r.next.FindByParentID(ctx, parentID)
+	})
+}
+
+// FindByContentHash implementa domain.DocumentRepository
+func (r *RetryRepository) FindByContentHash(ctx context.Context, hash string) (*domain.Document, error) {
+	return Retry(ctx, r.policy, "busca por conteúdo", func(ctx context.Context) (*domain.Document, error) {
+		return r.next.FindByContentHash(ctx, hash)
+	})
+}
+
+// List implementa domain.DocumentRepository
+func (r *RetryRepository) List(ctx context.Context, filter domain.DocumentFilter, cursor string, limit int) ([]domain.Document, string, error) {
+	type page struct {
+		docs []domain.Document
+		next string
+	}
+	result, err := Retry(ctx, r.policy, "listagem de documentos", func(ctx context.Context) (page, error) {
+		docs, next, err := r.next.List(ctx, filter, cursor, limit)
+		return page{docs, next}, err
+	})
+	return result.docs, result.next, err
+}
+
+// GetVersionHistory implementa domain.DocumentRepository
+func (r *RetryRepository) GetVersionHistory(ctx context.Context, id string) ([]domain.DocumentVersion, error) {
+	return Retry(ctx, r.policy, "histórico de versões", func(ctx context.Context) ([]domain.DocumentVersion, error) {
+		return r.next.GetVersionHistory(ctx, id)
+	})
+}
+
+// InsertDocument implementa domain.DocumentRepository, sem novas tentativas
+func (r *RetryRepository) InsertDocument(ctx context.Context, doc *domain.Document) error {
+	return r.next.InsertDocument(ctx, doc)
+}
+
+// InsertMany implementa domain.DocumentRepository, sem novas tentativas
+func (r *RetryRepository) InsertMany(ctx context.Context, docs []*domain.Document) error {
+	return r.next.InsertMany(ctx, docs)
+}
+
+// Update implementa domain.DocumentRepository, sem novas tentativas
+func (r *RetryRepository) Update(ctx context.Context, doc *domain.Document) error {
+	return r.next.Update(ctx, doc)
+}
+
+// SoftDelete implementa domain.DocumentRepository, sem novas tentativas
+func (r *RetryRepository) SoftDelete(ctx context.Context, id string) error {
+	return r.next.SoftDelete(ctx, id)
+}
+
+// SetEmbedding implementa domain.DocumentRepository, sem novas tentativas
+func (r *RetryRepository) SetEmbedding(ctx context.Context, id string, embedding []float32) error {
+	return r.next.SetEmbedding(ctx, id, embedding)
+}
+
+// Restore implementa domain.DocumentRepository, sem novas tentativas
+func (r *RetryRepository) Restore(ctx context.Context, id string) error {
+	return r.next.Restore(ctx, id)
+}
+
+// HealthCheck implementa domain.DocumentRepository. A verificação não é
+// repetida, para refletir o estado atual da base.
+func (r *RetryRepository) HealthCheck(ctx context.Context) error {
+	return r.next.HealthCheck(ctx)
+}
@@ -0,0 +1,94 @@
+package resilience
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Valores padrão da política de novas tentativas
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 200 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+	DefaultJitter         = 0.5
+)
+
+// RetryPolicy define como as operações que falham com erros temporários
+// (domain.IsTemporaryError) são repetidas. A espera dobra a cada tentativa,
+// limitada a MaxBackoff, e é respeitada a espera pedida pelo provedor
+// (domain.RetryAfter) quando maior.
+type RetryPolicy struct {
+	// MaxAttempts é a quantidade total de tentativas, incluindo a primeira;
+	// 1 desabilita as novas tentativas
+	MaxAttempts    int
+	InitialBackoff time.Duration // Espera antes da segunda tentativa
+	MaxBackoff     time.Duration // Espera máxima entre tentativas
+	// Jitter é a fração (0 a 1) da espera sorteada a cada tentativa, para que
+	// clientes que falharam juntos não repitam ao mesmo tempo
+	Jitter float64
+}
+
+// RetryPolicyFromEnv lê a política de novas tentativas a partir das
+// variáveis de ambiente
+func RetryPolicyFromEnv() RetryPolicy {
+	var p RetryPolicy
+	p.MaxAttempts, _ = strconv.Atoi(os.Getenv("RETRY_MAX_ATTEMPTS"))
+	p.InitialBackoff, _ = time.ParseDuration(os.Getenv("RETRY_INITIAL_BACKOFF"))
+	p.MaxBackoff, _ = time.ParseDuration(os.Getenv("RETRY_MAX_BACKOFF"))
+	p.Jitter, _ = strconv.ParseFloat(os.Getenv("RETRY_JITTER"), 64)
+	return p
+}
+
+// withDefaults preenche os campos não configurados com os valores padrão
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxBackoff
+	}
+	if p.Jitter <= 0 || p.Jitter > 1 {
+		p.Jitter = DefaultJitter
+	}
+	return p
+}
+
+// backoff retorna a espera antes da tentativa seguinte à informada
+func (p RetryPolicy) backoff(attempt int, err error) time.Duration {
+	wait := min(p.InitialBackoff<<(attempt-1), p.MaxBackoff)
+	wait -= time.Duration(rand.Float64() * p.Jitter * float64(wait))
+	return max(wait, domain.RetryAfter(err))
+}
+
+// Retry executa op e a repete, conforme a política, enquanto falhar com
+// erros temporários. Retorna o erro da última tentativa, ou o do contexto se
+// ele for cancelado durante uma espera.
+func Retry[T any](ctx context.Context, policy RetryPolicy, name string, op func(ctx context.Context) (T, error)) (T, error) {
+	policy = policy.withDefaults()
+	for attempt := 1; ; attempt++ {
+		result, err := op(ctx)
+		if err == nil || attempt >= policy.MaxAttempts || !domain.IsTemporaryError(err) {
+			return result, err
+		}
+
+		wait := policy.backoff(attempt, err)
+		log.Printf("Falha temporária em %s (tentativa %d de %d), repetindo em %s: %v", name, attempt, policy.MaxAttempts, wait, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
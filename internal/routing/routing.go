@@ -0,0 +1,140 @@
+// Package routing escolhe, para cada pergunta, entre um modelo pequeno e um
+// grande, conforme a complexidade estimada da pergunta, para que consultas
+// simples não paguem o preço do modelo mais capaz
+package routing
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Níveis de modelo escolhidos pelo roteador
+const (
+	TierSmall = "small"
+	TierLarge = "large"
+)
+
+// Valores padrão da configuração do roteador
+const (
+	DefaultThreshold = 2
+	DefaultLongQuery = 40 // Palavras
+)
+
+// Config contém as configurações do roteador de modelos
+type Config struct {
+	SmallModel string // Modelo das perguntas simples
+	LargeModel string // Modelo das perguntas complexas
+	// Threshold é a nota de complexidade a partir da qual o modelo grande é usado
+	Threshold int
+	// LongQuery é a quantidade de palavras a partir da qual a pergunta é longa
+	LongQuery int
+}
+
+// ConfigFromEnv lê a configuração do roteador a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	cfg := Config{
+		SmallModel: os.Getenv("ROUTER_SMALL_MODEL"),
+		LargeModel: os.Getenv("ROUTER_LARGE_MODEL"),
+	}
+	cfg.Threshold, _ = strconv.Atoi(os.Getenv("ROUTER_THRESHOLD"))
+	cfg.LongQuery, _ = strconv.Atoi(os.Getenv("ROUTER_LONG_QUERY"))
+	return cfg
+}
+
+// Router classifica as perguntas e escolhe o modelo de cada uma
+type Router struct {
+	small     string
+	large     string
+	threshold int
+	longQuery int
+}
+
+// New cria o roteador. Retorna nil quando nenhum modelo é configurado.
+func New(cfg Config) (*Router, error) {
+	if cfg.SmallModel == "" && cfg.LargeModel == "" {
+		return nil, nil
+	}
+	if cfg.SmallModel == "" || cfg.LargeModel == "" {
+		return nil, fmt.Errorf("ROUTER_SMALL_MODEL e ROUTER_LARGE_MODEL devem ser definidas juntas")
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultThreshold
+	}
+	if cfg.LongQuery <= 0 {
+		cfg.LongQuery = DefaultLongQuery
+	}
+	return &Router{
+		small:     cfg.SmallModel,
+		large:     cfg.LargeModel,
+		threshold: cfg.Threshold,
+		longQuery: cfg.LongQuery,
+	}, nil
+}
+
+// Decision é o modelo escolhido para uma pergunta e os motivos da escolha
+type Decision struct {
+	Model   string
+	Tier    string   // TierSmall ou TierLarge
+	Score   int      // Nota de complexidade da pergunta
+	Reasons []string // Sinais de complexidade encontrados
+}
+
+// String descreve a decisão para os logs
+func (d Decision) String() string {
+	reasons := "nenhum sinal de complexidade"
+	if len(d.Reasons) > 0 {
+		reasons = strings.Join(d.Reasons, ", ")
+	}
+	return fmt.Sprintf("%s (%s, nota %d: %s)", d.Model, d.Tier, d.Score, reasons)
+}
+
+var (
+	// codePattern reconhece blocos e trechos de código, chamadas de função e
+	// operadores comuns em linguagens de programação
+	codePattern = regexp.MustCompile("(?m)```|`[^`]+`|\\w+\\([^)]*\\)|[{};]\\s*$|=>|:=|==|!=|</?\\w+>")
+	// analysisPattern reconhece pedidos de comparação, explicação ou síntese,
+	// que exigem combinar vários documentos
+	analysisPattern = regexp.MustCompile(`(?i)\b(compar\w*|diferen[çc]\w*|differen\w*|vs\.?|versus|por que|why|explain\w*|expli\w*|an[áa]lis\w*|analy\w*|vantage\w*|desvantage\w*|advantage\w*|trade-?offs?|pr[óo]s e contras|pros and cons|resum\w*|summar\w*|avali\w*|evaluat\w*|estrat[ée]gi\w*|strateg\w*|impact\w*)\b`)
+	// vaguePattern reconhece referências que dependem de um contexto não
+	// informado na pergunta
+	vaguePattern = regexp.MustCompile(`(?i)\b(isso|isto|aquilo|ele|ela|eles|elas|this|that|it|they|those|these)\b`)
+)
+
+// Route classifica a pergunta e escolhe o modelo. A nota soma os sinais de
+// complexidade: pergunta longa, presença de código, pedidos de comparação ou
+// explicação, várias perguntas de uma vez e referências ambíguas.
+func (r *Router) Route(query string) Decision {
+	var d Decision
+	add := func(points int, reason string) {
+		d.Score += points
+		d.Reasons = append(d.Reasons, reason)
+	}
+
+	words := len(strings.Fields(query))
+	if words >= r.longQuery {
+		add(2, "pergunta longa")
+	}
+	if codePattern.MatchString(query) {
+		add(2, "código")
+	}
+	// Cada pedido de análise soma um ponto, até dois
+	if n := len(analysisPattern.FindAllString(query, 2)); n > 0 {
+		add(n, "comparação ou explicação")
+	}
+	if strings.Count(query, "?") > 1 {
+		add(1, "várias perguntas")
+	}
+	// Perguntas curtas que apontam para algo não informado são ambíguas
+	if words < 8 && vaguePattern.MatchString(query) {
+		add(1, "referência ambígua")
+	}
+
+	d.Model, d.Tier = r.small, TierSmall
+	if d.Score >= r.threshold {
+		d.Model, d.Tier = r.large, TierLarge
+	}
+	return d
+}
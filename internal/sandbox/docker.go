@@ -0,0 +1,161 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// DockerRunner executa cada programa em um container novo, removido ao
+// final: sem rede, sem capabilities, com o sistema de arquivos somente
+// leitura (exceto um /tmp em memória), usuário sem privilégios e limites de
+// memória, CPU e processos. O código é enviado pela entrada padrão.
+type DockerRunner struct {
+	config Config
+}
+
+// NewDockerRunner cria um executor que usa o comando docker
+func NewDockerRunner(cfg Config) *DockerRunner {
+	return &DockerRunner{config: cfg.withDefaults()}
+}
+
+// Languages retorna as linguagens aceitas
+func (r *DockerRunner) Languages() []string {
+	return []string{LanguagePython, LanguageGo}
+}
+
+// Run executa o programa em um container e retorna a saída. Ao fim do
+// timeout ou com o cancelamento do contexto, o container é removido à força.
+func (r *DockerRunner) Run(ctx context.Context, language, code string) (*domain.CodeResult, error) {
+	image, command, err := r.command(language)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := containerName()
+	if err != nil {
+		return nil, err
+	}
+	args := []string{
+		"run", "--rm", "-i",
+		"--name", name,
+		"--network", "none",
+		"--memory", r.config.Memory,
+		"--cpus", "1",
+		"--pids-limit", "64",
+		"--read-only",
+		"--tmpfs", "/tmp:rw,exec,size=64m",
+		"--cap-drop", "ALL",
+		"--security-opt", "no-new-privileges",
+		"--user", "65534:65534",
+		"--env", "HOME=/tmp",
+		image, "sh", "-c", command,
+	}
+
+	stdout := &limitedBuffer{limit: r.config.OutputLimit}
+	stderr := &limitedBuffer{limit: r.config.OutputLimit}
+	cmd := exec.Command("docker", args...)
+	cmd.Stdin = strings.NewReader(code)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("erro ao iniciar o container: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	timer := time.NewTimer(r.config.Timeout)
+	defer timer.Stop()
+
+	result := &domain.CodeResult{}
+	select {
+	case err = <-done:
+	case <-timer.C:
+		result.TimedOut = true
+		err = r.kill(name, done)
+	case <-ctx.Done():
+		r.kill(name, done)
+		return nil, ctx.Err()
+	}
+
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		return nil, fmt.Errorf("erro ao executar o container: %w", err)
+	}
+	return result, nil
+}
+
+// command retorna a imagem e o comando que executa o programa lido da
+// entrada padrão
+func (r *DockerRunner) command(language string) (string, string, error) {
+	switch language {
+	case LanguagePython:
+		return r.config.PythonImage, "python3 -I -", nil
+	case LanguageGo:
+		// Programas de um único arquivo, apenas com a biblioteca padrão
+		return r.config.GoImage, "cd /tmp && cat > main.go && GOCACHE=/tmp/cache GOPATH=/tmp/go GOTOOLCHAIN=local go run main.go", nil
+	default:
+		return "", "", fmt.Errorf("linguagem não suportada: %q", language)
+	}
+}
+
+// kill remove o container à força e espera o comando docker terminar
+func (r *DockerRunner) kill(name string, done <-chan error) error {
+	if out, err := exec.Command("docker", "rm", "-f", name).CombinedOutput(); err != nil {
+		log.Printf("Aviso ao remover o container %s: %v: %s", name, err, bytes.TrimSpace(out))
+	}
+	return <-done
+}
+
+// containerName gera um nome único para o container
+func containerName() (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("erro ao gerar nome do container: %w", err)
+	}
+	return "rag-sandbox-" + hex.EncodeToString(suffix), nil
+}
+
+// limitedBuffer guarda até limit bytes do que recebe e descarta o restante,
+// indicando o corte no fim do texto
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write guarda o que couber no limite; nunca falha, para não interromper o programa
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.buf.Write(p[:max(room, 0)])
+		b.truncated = true
+	} else {
+		b.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+// String retorna o texto guardado
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + "\n[saída cortada em " + strconv.Itoa(b.limit) + " bytes]"
+	}
+	return b.buf.String()
+}
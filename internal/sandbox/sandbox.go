@@ -0,0 +1,90 @@
+// Package sandbox executa os programas escritos pelo agente em containers
+// descartáveis, sem rede e com limites de tempo, memória e processos
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Linguagens aceitas
+const (
+	LanguagePython = "python"
+	LanguageGo     = "go"
+)
+
+// Tipos de executor disponíveis
+const (
+	RunnerNone   = ""       // Sem execução de código
+	RunnerDocker = "docker" // Containers Docker descartáveis
+)
+
+// Valores padrão do executor
+const (
+	defaultTimeout     = 10 * time.Second
+	defaultMemory      = "256m"
+	defaultOutputLimit = 16 * 1024
+	defaultPythonImage = "python:3.13-alpine"
+	defaultGoImage     = "golang:1.25-alpine"
+)
+
+// Config contém as configurações do executor de código
+type Config struct {
+	Runner      string
+	Timeout     time.Duration // Tempo máximo de cada execução (padrão: 10s)
+	Memory      string        // Limite de memória do container, no formato do Docker (padrão: 256m)
+	OutputLimit int           // Bytes guardados da saída e dos erros (padrão: 16 KiB)
+	PythonImage string
+	GoImage     string
+}
+
+// ConfigFromEnv lê a configuração do executor a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	timeout, _ := time.ParseDuration(os.Getenv("CODE_RUNNER_TIMEOUT"))
+	outputLimit, _ := strconv.Atoi(os.Getenv("CODE_RUNNER_OUTPUT_LIMIT"))
+	return Config{
+		Runner:      os.Getenv("CODE_RUNNER"),
+		Timeout:     timeout,
+		Memory:      os.Getenv("CODE_RUNNER_MEMORY"),
+		OutputLimit: outputLimit,
+		PythonImage: os.Getenv("CODE_RUNNER_PYTHON_IMAGE"),
+		GoImage:     os.Getenv("CODE_RUNNER_GO_IMAGE"),
+	}
+}
+
+// withDefaults preenche os campos não informados
+func (c Config) withDefaults() Config {
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.Memory == "" {
+		c.Memory = defaultMemory
+	}
+	if c.OutputLimit <= 0 {
+		c.OutputLimit = defaultOutputLimit
+	}
+	if c.PythonImage == "" {
+		c.PythonImage = defaultPythonImage
+	}
+	if c.GoImage == "" {
+		c.GoImage = defaultGoImage
+	}
+	return c
+}
+
+// New cria o executor configurado. Retorna nil quando a execução de código
+// está desabilitada.
+func New(cfg Config) (domain.CodeRunner, error) {
+	switch cfg.Runner {
+	case RunnerNone:
+		return nil, nil
+	case RunnerDocker:
+		return NewDockerRunner(cfg), nil
+	default:
+		return nil, fmt.Errorf("executor de código desconhecido: %q", cfg.Runner)
+	}
+}
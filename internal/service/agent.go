@@ -0,0 +1,179 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/chunking"
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+)
+
+// runAgent chama o LLM e executa as ferramentas solicitadas em sequência,
+// até que o agente responda sem pedir ferramentas. Quando MaxIterations ou
+// TokenBudget é atingido, a próxima chamada é feita sem ferramentas, forçando
+// a resposta final com o contexto obtido até então, e o prompt prompts.Answer
+// orienta o agente a responder com os documentos já recuperados. Com
+// GroundednessCheck, a resposta final é verificada contra as fontes. Com a
+// estratégia de síntese map-reduce, a busca que reúne MapReduceMinSources
+// fontes encerra o agente, e a resposta final é sintetizada por mapReduce.
+//
+// As mensagens são precedidas pelo prompt prompts.System, que orienta o
+// agente sobre quando consultar a base e pede a resposta no idioma e no
+// estilo de format (idioma vazio mantém o da pergunta). Os parâmetros de
+// geração de format valem para as chamadas do agente, mas não para as
+// ferramentas.
+// As primeiras history mensagens são o histórico da sessão. Com um limite de
+// contexto, os turnos mais antigos do histórico são descartados quando o
+// prompt não cabe, as buscas devolvem apenas os documentos que cabem e cada
+// chamada é verificada antes de ser feita.
+func (s *RAGServiceImpl) runAgent(ctx context.Context, messages []domain.Message, history int, format answerFormat, onToken func(token string)) (*domain.RAGResponse, error) {
+	// Acumula o texto já gerado, para devolvê-lo em caso de timeout
+	var partial strings.Builder
+	var sources []domain.Document
+	// seen identifica as fontes já acumuladas, pois buscas diferentes
+	// costumam devolver os mesmos chunks
+	seen := map[string]bool{}
+	var steps []domain.AgentStep
+	usedSearch := false
+	answering := false
+	tokens := 0
+	ctx, refs := withCitations(ctx)
+	ctx, summaries := withSourceSummaries(ctx)
+	question := messages[len(messages)-1].Content
+
+	// respond monta a resposta final, verificada contra as fontes
+	respond := func(msg *domain.Message) *domain.RAGResponse {
+		resp := &domain.RAGResponse{
+			Answer:        msg.Content,
+			Sources:       chunking.MergeChunks(sources),
+			UsedSearch:    usedSearch,
+			Steps:         steps,
+			Model:         msg.Usage.Model,
+			PromptVersion: s.prompts.Version(),
+			Language:      format.language,
+
+			SourcesSummarized: summaries.used.Load(),
+		}
+		s.verifyGroundedness(ctx, messages, resp, format, onToken != nil)
+		resp.Citations = refs.cite(resp.Answer, resp.Sources)
+		return resp
+	}
+
+	system, err := s.prompts.Render(prompts.System, map[string]any{
+		"SearchTool":    searchToolName,
+		"WebSearchTool": s.webSearchTool(),
+		"Tools":         s.tools.Tools(),
+		"Language":      languageName(format.language),
+		"Style":         string(format.style),
+		"MaxTokens":     format.generation.MaxTokens,
+	})
+	if err != nil {
+		return nil, err
+	}
+	messages = slices.Concat([]domain.Message{{Role: domain.RoleSystem, Content: system}}, messages)
+
+	for iteration := 1; ; iteration++ {
+		tools := s.tools.Tools()
+		if iteration >= s.config.MaxIterations || s.budgetExceeded(tokens) {
+			tools = nil
+		}
+		if tools == nil && !answering {
+			answer, err := s.prompts.Render(prompts.Answer, nil)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, domain.Message{Role: domain.RoleSystem, Content: answer})
+			answering = true
+		}
+		messages, history = s.trimHistory(messages, 1, history, tools)
+		if err := s.checkContextBudget(messages, tools); err != nil {
+			return nil, err
+		}
+
+		msg, err := s.generate(format.withGeneration(ctx), messages, tools, onToken, &partial)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, timeoutResult(ctx, sources, &partial)
+			}
+			if iteration > 1 {
+				return nil, fmt.Errorf("erro na iteração %d do agente: %w", iteration, err)
+			}
+			return nil, err
+		}
+
+		step := domain.AgentStep{
+			Iteration: iteration,
+			Content:   msg.Content,
+			ToolCalls: msg.ToolCalls,
+			Tokens:    msg.Usage.Total(),
+		}
+		// Provedores que não informam o consumo têm os tokens estimados
+		if step.Tokens == 0 {
+			step.Tokens = s.countTokens(messages, tools) + s.countTokens([]domain.Message{*msg}, nil)
+		}
+		steps = append(steps, step)
+		tokens += step.Tokens
+
+		// Sem chamadas de ferramentas: esta é a resposta final
+		if len(msg.ToolCalls) == 0 {
+			return respond(msg), nil
+		}
+
+		messages = append(messages, *msg)
+
+		// O histórico não reduz o espaço dos documentos: ele é descartado na
+		// próxima chamada, se preciso
+		toolCtx := s.withDocumentBudget(ctx, slices.Concat(messages[:1], messages[1+history:]), len(msg.ToolCalls))
+
+		// Executa as chamadas de ferramentas em paralelo
+		toolMessages, found := s.executeToolCalls(toolCtx, msg.ToolCalls)
+		for _, doc := range found {
+			// Os resultados da web não têm ID e são identificados pelo link
+			key := cmp.Or(doc.ID, doc.Link)
+			if key != "" && seen[key] {
+				continue
+			}
+			seen[key] = true
+			sources = append(sources, doc)
+		}
+		if ctx.Err() != nil {
+			return nil, timeoutResult(ctx, sources, &partial)
+		}
+		messages = append(messages, toolMessages...)
+		// Ferramentas de cálculo não consultam a base
+		usedSearch = usedSearch || len(found) > 0 || slices.ContainsFunc(msg.ToolCalls, isSearchCall)
+
+		// Com fontes suficientes, a resposta é sintetizada por map-reduce em
+		// vez de pelo agente
+		if merged := chunking.MergeChunks(sources); s.config.SynthesisStrategy == SynthesisMapReduce && len(merged) >= s.config.MapReduceMinSources {
+			msg, used, err := s.mapReduce(ctx, question, merged, format, onToken, &partial)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, timeoutResult(ctx, sources, &partial)
+				}
+				return nil, fmt.Errorf("erro na síntese map-reduce: %w", err)
+			}
+			steps = append(steps, domain.AgentStep{Iteration: iteration + 1, Content: msg.Content, Tokens: used})
+			return respond(msg), nil
+		}
+
+		if s.budgetExceeded(tokens) {
+			log.Printf("Orçamento de tokens atingido (%d de %d), forçando a resposta final", tokens, s.config.TokenBudget)
+		}
+	}
+}
+
+// budgetExceeded indica se o agente já consumiu o orçamento de tokens
+func (s *RAGServiceImpl) budgetExceeded(tokens int) bool {
+	return s.config.TokenBudget > 0 && tokens >= s.config.TokenBudget
+}
+
+// isSearchCall indica se a chamada é a uma das ferramentas de busca
+func isSearchCall(call domain.ToolCall) bool {
+	return call.Name == searchToolName || call.Name == webSearchToolName
+}
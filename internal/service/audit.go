@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"log"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// withAuditRequest garante um ID de requisição no contexto da pergunta, para
+// relacionar no log de auditoria a pergunta, as ferramentas executadas e a
+// resposta mesmo fora da API HTTP, que já define o ID
+func (s *RAGServiceImpl) withAuditRequest(ctx context.Context) context.Context {
+	if s.audit == nil || domain.RequestIDFromContext(ctx) != "" {
+		return ctx
+	}
+	return domain.WithRequestID(ctx, rand.Text())
+}
+
+// record grava a entrada no log de auditoria, com o erro da ação, se houver.
+// A entrada é gravada mesmo que a requisição tenha sido cancelada, e falhas
+// não impedem a operação e são apenas registradas.
+func (s *RAGServiceImpl) record(ctx context.Context, entry domain.AuditEntry, err error) {
+	if s.audit == nil {
+		return
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if err := s.audit.Append(context.WithoutCancel(ctx), &entry); err != nil {
+		log.Printf("Aviso ao registrar auditoria (%s): %v", entry.Action, err)
+	}
+}
+
+// recordQuery registra a pergunta recebida, atribuída ao ator do contexto
+// ou, sem ele, ao usuário informado na pergunta
+func (s *RAGServiceImpl) recordQuery(ctx context.Context, req domain.RAGRequest) {
+	s.record(ctx, domain.AuditEntry{
+		Action:    domain.AuditQuery,
+		Actor:     requestActor(ctx, req),
+		SessionID: req.SessionID,
+		Details:   map[string]any{"query": req.Query, "user_id": req.UserID, "groups": req.Groups},
+	}, nil)
+}
+
+// recordAnswer registra a resposta entregue, com as fontes usadas, ou o erro
+// que impediu a resposta
+func (s *RAGServiceImpl) recordAnswer(ctx context.Context, req domain.RAGRequest, resp *domain.RAGResponse, err error) {
+	entry := domain.AuditEntry{
+		Action:    domain.AuditAnswer,
+		Actor:     requestActor(ctx, req),
+		SessionID: req.SessionID,
+	}
+	if resp != nil {
+		sources := make([]string, 0, len(resp.Sources))
+		for _, doc := range resp.Sources {
+			if doc.ID != "" {
+				sources = append(sources, doc.ID)
+			}
+		}
+		entry.SessionID = resp.SessionID
+		entry.Details = map[string]any{
+			"answer":  resp.Answer,
+			"sources": sources,
+			"model":   resp.Model,
+			"cached":  resp.Cached,
+		}
+	}
+	s.record(ctx, entry, err)
+}
+
+// recordDocument registra uma alteração no documento com o ID informado
+func (s *RAGServiceImpl) recordDocument(ctx context.Context, action, id string, details map[string]any, err error) {
+	s.record(ctx, domain.AuditEntry{Action: action, Resource: id, Details: details}, err)
+}
+
+// requestActor retorna quem fez a pergunta: o ator do contexto, definido
+// pela autenticação da API, ou o usuário informado na pergunta
+func requestActor(ctx context.Context, req domain.RAGRequest) string {
+	if actor := domain.ActorFromContext(ctx); actor != "" {
+		return actor
+	}
+	return req.UserID
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// completionReserve são os tokens da janela de contexto reservados para a
+// resposta do modelo, limitados a um quarto da janela
+const completionReserve = 4096
+
+// messageOverhead são os tokens que cada mensagem ocupa além do conteúdo
+// (papel e delimitadores), como no cálculo da OpenAI
+const messageOverhead = 4
+
+// documentBudgetKey é a chave, no contexto, dos tokens disponíveis para os
+// documentos devolvidos por uma busca do agente
+type documentBudgetKey struct{}
+
+// countTokens conta os tokens das mensagens e das definições das ferramentas
+func (s *RAGServiceImpl) countTokens(messages []domain.Message, tools []domain.Tool) int {
+	tokens := 0
+	for _, msg := range messages {
+		tokens += messageOverhead + s.tokenizer.Count(msg.Content)
+		for _, call := range msg.ToolCalls {
+			tokens += s.tokenizer.Count(call.Name) + s.tokenizer.Count(call.Arguments)
+		}
+	}
+	for _, tool := range tools {
+		definition, _ := json.Marshal(tool)
+		tokens += s.tokenizer.Count(string(definition))
+	}
+	return tokens
+}
+
+// contextLimit retorna o máximo de tokens do prompt: o menor entre
+// ContextBudget e a janela de contexto do modelo menos a reserva para a
+// resposta. Retorna 0 quando não há limite.
+func (s *RAGServiceImpl) contextLimit() int {
+	limit := 0
+	if s.contextWindow > 0 {
+		limit = s.contextWindow - min(completionReserve, s.contextWindow/4)
+	}
+	if budget := s.config.ContextBudget; budget > 0 && (limit == 0 || budget < limit) {
+		limit = budget
+	}
+	return limit
+}
+
+// checkContextBudget falha com *domain.PromptTooLargeError quando o prompt
+// excede o limite de contexto
+func (s *RAGServiceImpl) checkContextBudget(messages []domain.Message, tools []domain.Tool) error {
+	limit := s.contextLimit()
+	if limit <= 0 {
+		return nil
+	}
+
+	if tokens := s.countTokens(messages, tools); tokens > limit {
+		return &domain.PromptTooLargeError{Tokens: tokens, Limit: limit}
+	}
+	return nil
+}
+
+// trimHistory descarta as mensagens mais antigas do histórico (as history
+// mensagens a partir de start) até que o prompt caiba no limite de contexto,
+// e retorna as mensagens e o tamanho do histórico restantes. O histórico
+// nunca começa por uma resposta cuja pergunta foi descartada.
+func (s *RAGServiceImpl) trimHistory(messages []domain.Message, start, history int, tools []domain.Tool) ([]domain.Message, int) {
+	limit := s.contextLimit()
+	if limit <= 0 || history == 0 {
+		return messages, history
+	}
+
+	tokens := s.countTokens(messages, tools)
+	drop := 0
+	for drop < history && tokens > limit {
+		tokens -= s.countTokens(messages[start+drop:start+drop+1], nil)
+		drop++
+	}
+	for drop > 0 && drop < history && messages[start+drop].Role != domain.RoleUser {
+		drop++
+	}
+	if drop == 0 {
+		return messages, history
+	}
+
+	log.Printf("Janela de contexto: descartadas %d de %d mensagens do histórico", drop, history)
+	return slices.Delete(messages, start, start+drop), history - drop
+}
+
+// withDocumentBudget guarda no contexto os tokens que cada uma das chamadas
+// de ferramentas pode ocupar com documentos: o que resta do limite de
+// contexto depois das mensagens e das ferramentas, dividido entre elas
+func (s *RAGServiceImpl) withDocumentBudget(ctx context.Context, messages []domain.Message, calls int) context.Context {
+	limit := s.contextLimit()
+	if limit <= 0 || calls == 0 {
+		return ctx
+	}
+
+	remaining := limit - s.countTokens(messages, s.tools.Tools())
+	budget := max(remaining/calls-messageOverhead, 0)
+	return context.WithValue(ctx, documentBudgetKey{}, budget)
+}
+
+// fitDocuments mantém os primeiros documentos, os mais relevantes, enquanto
+// couberem nos tokens disponíveis para a busca
+func (s *RAGServiceImpl) fitDocuments(ctx context.Context, docs []domain.Document) []domain.Document {
+	budget, ok := ctx.Value(documentBudgetKey{}).(int)
+	if !ok {
+		return docs
+	}
+
+	tokens := 0
+	for i, doc := range docs {
+		tokens += s.documentTokens(doc)
+		if tokens > budget {
+			log.Printf("Orçamento de contexto: mantidos %d de %d documentos (%d tokens disponíveis)", i, len(docs), budget)
+			return docs[:i]
+		}
+	}
+	return docs
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// cacheVector gera o embedding usado para consultar o cache de respostas.
+// Retorna nil quando o cache não se aplica: sem cache ou embeddings, quando
+// a sessão já tem histórico, pois a resposta depende da conversa, quando
+// as buscas são filtradas, pois a resposta depende do filtro (e, com grupos,
+// dos documentos com ACL que o usuário pode ver), ou quando a pergunta pede
+// um estilo ou parâmetros de geração.
+func (s *RAGServiceImpl) cacheVector(ctx context.Context, query string, conv *domain.Conversation, filter domain.SearchFilter, format answerFormat) []float32 {
+	if s.cache == nil || s.embedder == nil {
+		return nil
+	}
+	if conv != nil && (len(conv.Messages) > 0 || conv.Summary != "") {
+		return nil
+	}
+	if !filter.Empty() || format.custom {
+		return nil
+	}
+
+	vectors, err := s.embedder.Embed(domain.WithQueryEmbedding(ctx), []string{query})
+	if err != nil || len(vectors) == 0 {
+		log.Printf("Aviso ao gerar embedding para o cache: %v", err)
+		return nil
+	}
+	return vectors[0]
+}
+
+// lookupCache retorna a resposta guardada para uma pergunta semelhante.
+// Falhas no cache não impedem a resposta e são apenas registradas.
+func (s *RAGServiceImpl) lookupCache(ctx context.Context, vector []float32) *domain.RAGResponse {
+	if vector == nil {
+		return nil
+	}
+
+	resp, err := s.cache.Lookup(ctx, vector)
+	if err != nil {
+		log.Printf("Aviso ao consultar o cache: %v", err)
+		return nil
+	}
+	if resp != nil {
+		resp.Cached = true
+	}
+	return resp
+}
+
+// storeCache guarda a resposta gerada pelo agente
+func (s *RAGServiceImpl) storeCache(ctx context.Context, vector []float32, resp *domain.RAGResponse) {
+	if vector == nil {
+		return
+	}
+
+	if err := s.cache.Store(ctx, vector, resp); err != nil {
+		log.Printf("Aviso ao guardar resposta no cache: %v", err)
+	}
+}
+
+// queryCacheKey monta a chave do cache de perguntas exatas: o hash da
+// pergunta normalizada (sem diferença de maiúsculas e espaços), do estilo e
+// dos parâmetros de geração da resposta e do filtro, inclusive dos grupos, para
+// que uma resposta baseada em documentos com ACL só seja reaproveitada por
+// usuários dos mesmos grupos. Retorna vazio quando
+// o cache não se aplica: sem cache ou quando a sessão já tem histórico, pois
+// a resposta depende da conversa.
+func (s *RAGServiceImpl) queryCacheKey(query string, conv *domain.Conversation, filter domain.SearchFilter, format answerFormat) string {
+	if s.queryCache == nil {
+		return ""
+	}
+	if conv != nil && (len(conv.Messages) > 0 || conv.Summary != "") {
+		return ""
+	}
+
+	// O formato e os grupos ficam em posições fixas, os grupos codificados
+	// em JSON, para que nenhuma combinação de tags e metadados produza a
+	// mesma chave
+	groups, _ := json.Marshal(slices.Sorted(slices.Values(filter.Groups)))
+	parts := []string{
+		strings.Join(strings.Fields(strings.ToLower(query)), " "),
+		format.cacheKey(),
+		filter.Category,
+		string(groups),
+	}
+	if filter.Fuzzy {
+		parts = append(parts, "fuzzy")
+	}
+	if filter.IncludeDeleted {
+		parts = append(parts, "deleted")
+	}
+	if len(filter.Conditions) > 0 {
+		conditions, _ := json.Marshal(filter.Conditions)
+		parts = append(parts, string(conditions))
+	}
+	for _, key := range slices.Sorted(maps.Keys(filter.Metadata)) {
+		parts = append(parts, key+"="+filter.Metadata[key])
+	}
+	if len(filter.Tags) > 0 {
+		tagMode := filter.TagMode
+		if tagMode == "" {
+			tagMode = domain.TagMatchAny
+		}
+		parts = append(parts, string(tagMode))
+		parts = append(parts, slices.Sorted(slices.Values(filter.Tags))...)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// lookupQueryCache retorna a resposta guardada para a mesma pergunta.
+// Falhas no cache não impedem a resposta e são apenas registradas.
+func (s *RAGServiceImpl) lookupQueryCache(ctx context.Context, key string) *domain.RAGResponse {
+	if key == "" {
+		return nil
+	}
+
+	resp, err := s.queryCache.Get(ctx, key)
+	if err != nil {
+		log.Printf("Aviso ao consultar o cache de perguntas: %v", err)
+		return nil
+	}
+	if resp != nil {
+		resp.Cached = true
+	}
+	return resp
+}
+
+// storeQueryCache guarda a resposta gerada pelo agente para a pergunta
+func (s *RAGServiceImpl) storeQueryCache(ctx context.Context, key string, resp *domain.RAGResponse) {
+	if key == "" {
+		return
+	}
+
+	if err := s.queryCache.Set(ctx, key, resp); err != nil {
+		log.Printf("Aviso ao guardar resposta no cache de perguntas: %v", err)
+	}
+}
+
+// invalidateCache descarta as respostas guardadas após mudanças na base
+func (s *RAGServiceImpl) invalidateCache(ctx context.Context) {
+	if s.cache != nil {
+		if err := s.cache.Invalidate(ctx); err != nil {
+			log.Printf("Aviso ao invalidar o cache: %v", err)
+		}
+	}
+	if s.queryCache != nil {
+		if err := s.queryCache.Invalidate(ctx); err != nil {
+			log.Printf("Aviso ao invalidar o cache de perguntas: %v", err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// citationPattern reconhece os marcadores de citação da resposta, como [1]
+// ou [1, 3]
+var citationPattern = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// citationsKey é a chave das referências da pergunta no contexto
+type citationsKey struct{}
+
+// citationRefs numera os documentos entregues ao agente em uma pergunta, na
+// ordem em que aparecem nas buscas. Os chunks de um mesmo documento recebem
+// o mesmo número, pois são unidos em uma única fonte na resposta.
+type citationRefs struct {
+	mu   sync.Mutex
+	refs map[string]int
+	keys []string // Chave de cada número, a partir de 1
+}
+
+// citedDocument é o documento entregue ao agente, com o número usado para citá-lo
+type citedDocument struct {
+	Ref int `json:"ref"`
+	domain.Document
+}
+
+// withCitations guarda no contexto a numeração dos documentos da pergunta
+func withCitations(ctx context.Context) (context.Context, *citationRefs) {
+	refs := &citationRefs{refs: map[string]int{}}
+	return context.WithValue(ctx, citationsKey{}, refs), refs
+}
+
+// citeDocuments numera os documentos de uma busca com as referências do
+// contexto. Sem referências no contexto, os documentos são numerados a
+// partir de 1.
+func citeDocuments(ctx context.Context, docs []domain.Document) []citedDocument {
+	refs, ok := ctx.Value(citationsKey{}).(*citationRefs)
+	if !ok {
+		refs = &citationRefs{refs: map[string]int{}}
+	}
+
+	cited := make([]citedDocument, 0, len(docs))
+	for _, doc := range docs {
+		cited = append(cited, citedDocument{Ref: refs.ref(doc), Document: doc})
+	}
+	return cited
+}
+
+// ref retorna o número do documento, numerando-o se ainda não tiver um
+func (c *citationRefs) ref(doc domain.Document) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := citationKey(doc)
+	if ref, ok := c.refs[key]; ok {
+		return ref
+	}
+	c.keys = append(c.keys, key)
+	c.refs[key] = len(c.keys)
+	return len(c.keys)
+}
+
+// cite liga os marcadores da resposta às fontes, na ordem em que aparecem.
+// Marcadores que não correspondem a nenhum documento entregue ao agente são
+// ignorados.
+func (c *citationRefs) cite(answer string, sources []domain.Document) []domain.Citation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	positions := make(map[string]int, len(sources))
+	for i, doc := range sources {
+		if _, ok := positions[citationKey(doc)]; !ok {
+			positions[citationKey(doc)] = i
+		}
+	}
+
+	var citations []domain.Citation
+	seen := map[int]bool{}
+	for _, match := range citationPattern.FindAllStringSubmatch(answer, -1) {
+		for _, field := range strings.Split(match[1], ",") {
+			marker, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || seen[marker] || marker < 1 || marker > len(c.keys) {
+				continue
+			}
+			source, ok := positions[c.keys[marker-1]]
+			if !ok {
+				continue
+			}
+			seen[marker] = true
+			citations = append(citations, domain.Citation{
+				Marker:   marker,
+				Source:   source,
+				Title:    sources[source].Title,
+				Link:     sources[source].Link,
+				External: sources[source].External(),
+			})
+		}
+	}
+	return citations
+}
+
+// citationKey identifica o documento lógico: chunks e o documento unido a
+// partir deles têm a mesma chave
+func citationKey(doc domain.Document) string {
+	return cmp.Or(doc.ParentID, doc.ID, doc.Link)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+func TestCiteDocuments(t *testing.T) {
+	ctx, _ := withCitations(context.Background())
+	first := citeDocuments(ctx, []domain.Document{
+		{ID: "c1", ParentID: "p"},
+		{ID: "d"},
+		{ID: "c2", ParentID: "p"},
+	})
+	second := citeDocuments(ctx, []domain.Document{{ID: "e"}, {ID: "d"}})
+
+	var got []int
+	for _, doc := range append(first, second...) {
+		got = append(got, doc.Ref)
+	}
+	// Chunks do mesmo documento e documentos repetidos entre buscas mantêm o número
+	if want := []int{1, 2, 1, 3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("refs = %v, esperado %v", got, want)
+	}
+}
+
+func TestCite(t *testing.T) {
+	ctx, refs := withCitations(context.Background())
+	citeDocuments(ctx, []domain.Document{
+		{ID: "c1", ParentID: "p"},
+		{ID: "d"},
+		{ID: "w", Link: "https://example.com", Metadata: map[string]string{domain.MetadataOrigin: domain.OriginWeb}},
+		{ID: "omitido"},
+	})
+	// As fontes da resposta unem os chunks no documento lógico
+	sources := []domain.Document{
+		{ID: "w", Title: "Web", Link: "https://example.com", Metadata: map[string]string{domain.MetadataOrigin: domain.OriginWeb}},
+		{ID: "p", Title: "Pai"},
+		{ID: "d", Title: "Doc"},
+	}
+
+	tests := []struct {
+		name   string
+		answer string
+		want   []domain.Citation
+	}{
+		{name: "sem marcadores", answer: "Resposta sem citações."},
+		{
+			name:   "marcadores simples e listas",
+			answer: "Goroutines [2]. Canais [1, 3] e de novo [2].",
+			want: []domain.Citation{
+				{Marker: 2, Source: 2, Title: "Doc"},
+				{Marker: 1, Source: 1, Title: "Pai"},
+				{Marker: 3, Source: 0, Title: "Web", Link: "https://example.com", External: true},
+			},
+		},
+		{
+			name:   "marcadores sem fonte",
+			answer: "Fora da faixa [0] [9], não entregue [4], texto [a].",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refs.cite(tt.answer, sources); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cite(%q) = %+v, esperado %+v", tt.answer, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Estratégias de recuperação disponíveis
+const (
+	// RetrievalDirect busca diretamente pela consulta (vetorial ou textual)
+	RetrievalDirect = "direct"
+	// RetrievalHyDE gera uma resposta hipotética com o LLM e busca pelo seu
+	// embedding (Hypothetical Document Embeddings)
+	RetrievalHyDE = "hyde"
+)
+
+// Estratégias de síntese da resposta final
+const (
+	// SynthesisAgent faz o próprio agente responder com os documentos
+	// recuperados, em uma única chamada
+	SynthesisAgent = "agent"
+	// SynthesisMapReduce responde com cada fonte em paralelo e combina as
+	// respostas parciais em uma chamada final, para perguntas que dependem
+	// de muitos documentos
+	SynthesisMapReduce = "map_reduce"
+)
+
+// Ações tomadas quando a resposta fica abaixo de GroundednessThreshold
+const (
+	// GroundednessWarn antepõe um aviso à resposta
+	GroundednessWarn = "warn"
+	// GroundednessRegenerate pede ao LLM uma nova resposta sem as afirmações
+	// não sustentadas; em streaming, em que a resposta já foi enviada, avisa
+	GroundednessRegenerate = "regenerate"
+)
+
+// Políticas aplicadas ao conteúdo sinalizado pela moderação
+const (
+	// ModerationBlock recusa a pergunta com *domain.ContentBlockedError
+	ModerationBlock = "block"
+	// ModerationFlag responde normalmente e registra a sinalização em
+	// RAGResponse.Moderation
+	ModerationFlag = "flag"
+)
+
+// Valores padrão da configuração do serviço
+const (
+	DefaultQueryExpansionCount   = 3
+	DefaultMaxSearchResults      = 10
+	DefaultMaxIterations         = 5
+	DefaultMapReduceMinSources   = 4
+	DefaultConversationKeepTurns = 2
+	DefaultGroundednessThreshold = 0.7
+	DefaultFeedbackRankingWeight = 0.5
+	DefaultFeedbackRankingDays   = 90
+)
+
+// RAGConfig contém as configurações do fluxo de recuperação do serviço
+type RAGConfig struct {
+	// RetrievalStrategy define como os documentos são buscados: "direct" (padrão) ou "hyde"
+	RetrievalStrategy string
+	// QueryExpansion habilita a geração de reformulações da consulta pelo LLM
+	// antes da busca; os resultados de todas as consultas são combinados
+	QueryExpansion bool
+	// QueryExpansionCount é a quantidade de reformulações geradas (de 2 a 4)
+	QueryExpansionCount int
+	// FuzzySearch faz a busca textual tolerar erros de digitação por padrão;
+	// cada pergunta pode habilitar ou desabilitar com RAGRequest.Fuzzy
+	FuzzySearch bool
+	// MaxSearchResults limita os documentos retornados ao combinar resultados
+	MaxSearchResults int
+	// MMRLambda habilita a diversificação dos documentos da busca, depois da
+	// combinação e do rerank, com Maximal Marginal Relevance: de 0 (apenas
+	// diversidade) a 1 (apenas relevância). Nulo desabilita.
+	MMRLambda *float64
+	// FeedbackRanking reordena os documentos da busca, depois do rerank e
+	// antes da diversificação, com uma nota de cada documento calculada a
+	// partir das avaliações das respostas que o citaram (WithFeedbackRepository)
+	FeedbackRanking bool
+	// FeedbackRankingWeight é o peso da nota (de -1 a 1) somada à relevância
+	// dada pela posição na busca, que vai de 1 (primeiro) a perto de 0 (último)
+	FeedbackRankingWeight float64
+	// FeedbackRankingDays é o período, em dias, das avaliações consideradas
+	FeedbackRankingDays int
+	// SentenceWindow é a quantidade de frases do documento original
+	// acrescentadas antes e depois de cada chunk encontrado, o que ajuda nas
+	// bases divididas em chunks pequenos. 0 desabilita.
+	SentenceWindow int
+	// SourceSummarization faz as buscas cujos documentos excedem o orçamento
+	// de contexto (ContextBudget ou a janela do modelo) entregarem ao agente
+	// um resumo de cada fonte, focado na pergunta, em vez de descartarem as
+	// menos relevantes. RAGResponse.SourcesSummarized indica o resumo.
+	SourceSummarization bool
+	// SummaryModel é o modelo, do provedor principal, que resume as fontes
+	// e as conversas; um modelo mais barato reduz o custo. Vazio usa o
+	// configurado.
+	SummaryModel string
+	// ConversationSummaryTokens limita os tokens do histórico guardado de
+	// cada sessão: ao excedê-lo, os turnos mais antigos são resumidos em
+	// Conversation.Summary, que o agente recebe no lugar deles. 0 desabilita.
+	ConversationSummaryTokens int
+	// ConversationKeepTurns é a quantidade dos últimos turnos (pergunta e
+	// resposta) mantidos sem resumo
+	ConversationKeepTurns int
+	// SynthesisStrategy define como a resposta final é gerada: "agent"
+	// (padrão) ou "map_reduce"
+	SynthesisStrategy string
+	// MapReduceMinSources é a quantidade de fontes a partir da qual a síntese
+	// map-reduce é usada; com menos, o agente responde normalmente
+	MapReduceMinSources int
+	// SessionRateLimit limita as perguntas por minuto de cada sessão, com o
+	// armazenamento de WithSessionStore. 0 desabilita.
+	SessionRateLimit int
+	// MaxIterations limita as chamadas ao LLM em uma pergunta. A última
+	// chamada é feita sem ferramentas, forçando a resposta final.
+	MaxIterations int
+	// TokenBudget limita os tokens (estimados) consumidos pelo agente em uma
+	// pergunta; ao ser atingido, o agente é forçado a responder. 0 desabilita.
+	TokenBudget int
+	// ContextBudget limita os tokens do prompt de cada chamada ao LLM
+	// (mensagens e definições das ferramentas). Os documentos recuperados são
+	// cortados para caber no que resta; prompts que ainda assim excedem o
+	// limite falham com *domain.PromptTooLargeError. 0 desabilita.
+	ContextBudget int
+	// GroundednessCheck habilita uma segunda chamada ao LLM, após respostas
+	// que consultaram a base, para verificar se cada afirmação é sustentada
+	// pelas fontes. A nota é retornada em RAGResponse.Groundedness.
+	GroundednessCheck bool
+	// GroundednessThreshold é a nota mínima (0 a 1); abaixo dela,
+	// GroundednessAction é aplicada
+	GroundednessThreshold float64
+	// GroundednessAction é "warn" (padrão) ou "regenerate"
+	GroundednessAction string
+	// Moderation habilita a moderação da pergunta e da resposta, com a
+	// política "block" ou "flag". Vazio desabilita.
+	Moderation string
+	// QualitySampleRate é a fração das respostas (0 a 1) avaliadas em segundo
+	// plano por um LLM juiz (faithfulness, context relevance e answer
+	// relevance), com as notas guardadas no repositório de
+	// WithQualityRepository. 0 desabilita.
+	QualitySampleRate float64
+	// Temperature, TopP e Seed são os parâmetros de geração padrão das
+	// respostas, substituídos pelos da pergunta; nulos usam o padrão do
+	// provedor. Uma temperatura 0 com uma semente deixa as respostas
+	// reproduzíveis, por exemplo nas avaliações.
+	Temperature *float64
+	TopP        *float64
+	Seed        *int
+	// AllowedModels são os modelos que as perguntas podem escolher em
+	// RAGRequest.Model, do provedor principal. Vazio não permite a escolha.
+	AllowedModels []string
+}
+
+// ConfigFromEnv lê a configuração do serviço a partir das variáveis de ambiente
+func ConfigFromEnv() RAGConfig {
+	cfg := RAGConfig{
+		RetrievalStrategy:   os.Getenv("RAG_RETRIEVAL_STRATEGY"),
+		SynthesisStrategy:   os.Getenv("RAG_SYNTHESIS_STRATEGY"),
+		QueryExpansion:      os.Getenv("RAG_QUERY_EXPANSION") == "true",
+		FuzzySearch:         os.Getenv("RAG_FUZZY_SEARCH") == "true",
+		FeedbackRanking:     os.Getenv("RAG_FEEDBACK_RANKING") == "true",
+		SourceSummarization: os.Getenv("RAG_SOURCE_SUMMARIZATION") == "true",
+		SummaryModel:        os.Getenv("RAG_SUMMARY_MODEL"),
+		GroundednessCheck:   os.Getenv("RAG_GROUNDEDNESS_CHECK") == "true",
+		GroundednessAction:  os.Getenv("RAG_GROUNDEDNESS_ACTION"),
+		Moderation:          os.Getenv("RAG_MODERATION"),
+	}
+	cfg.QueryExpansionCount, _ = strconv.Atoi(os.Getenv("RAG_QUERY_EXPANSION_COUNT"))
+	cfg.MaxSearchResults, _ = strconv.Atoi(os.Getenv("RAG_MAX_SEARCH_RESULTS"))
+	cfg.FeedbackRankingWeight, _ = strconv.ParseFloat(os.Getenv("RAG_FEEDBACK_RANKING_WEIGHT"), 64)
+	cfg.FeedbackRankingDays, _ = strconv.Atoi(os.Getenv("RAG_FEEDBACK_RANKING_DAYS"))
+	cfg.SentenceWindow, _ = strconv.Atoi(os.Getenv("RAG_SENTENCE_WINDOW"))
+	cfg.MapReduceMinSources, _ = strconv.Atoi(os.Getenv("RAG_MAP_REDUCE_MIN_SOURCES"))
+	cfg.ConversationSummaryTokens, _ = strconv.Atoi(os.Getenv("RAG_CONVERSATION_SUMMARY_TOKENS"))
+	cfg.ConversationKeepTurns, _ = strconv.Atoi(os.Getenv("RAG_CONVERSATION_KEEP_TURNS"))
+	cfg.SessionRateLimit, _ = strconv.Atoi(os.Getenv("RAG_SESSION_RATE_LIMIT"))
+	cfg.MaxIterations, _ = strconv.Atoi(os.Getenv("RAG_MAX_ITERATIONS"))
+	cfg.TokenBudget, _ = strconv.Atoi(os.Getenv("RAG_TOKEN_BUDGET"))
+	cfg.ContextBudget, _ = strconv.Atoi(os.Getenv("RAG_CONTEXT_BUDGET"))
+	cfg.GroundednessThreshold, _ = strconv.ParseFloat(os.Getenv("RAG_GROUNDEDNESS_THRESHOLD"), 64)
+	cfg.QualitySampleRate, _ = strconv.ParseFloat(os.Getenv("RAG_QUALITY_SAMPLE_RATE"), 64)
+	cfg.Temperature = optionalFloat(os.Getenv("RAG_TEMPERATURE"))
+	cfg.TopP = optionalFloat(os.Getenv("RAG_TOP_P"))
+	cfg.MMRLambda = optionalFloat(os.Getenv("RAG_MMR_LAMBDA"))
+	if seed, err := strconv.Atoi(os.Getenv("RAG_SEED")); err == nil {
+		cfg.Seed = &seed
+	}
+	for _, model := range strings.Split(os.Getenv("RAG_ALLOWED_MODELS"), ",") {
+		if model = strings.TrimSpace(model); model != "" {
+			cfg.AllowedModels = append(cfg.AllowedModels, model)
+		}
+	}
+	return cfg
+}
+
+// optionalFloat converte o valor de uma variável de ambiente, retornando nil
+// quando ela não está definida ou é inválida
+func optionalFloat(value string) *float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &f
+}
+
+// withDefaults preenche os campos não configurados com os valores padrão
+func (c RAGConfig) withDefaults() RAGConfig {
+	if c.RetrievalStrategy == "" {
+		c.RetrievalStrategy = RetrievalDirect
+	}
+	if c.QueryExpansionCount <= 0 {
+		c.QueryExpansionCount = DefaultQueryExpansionCount
+	}
+	c.QueryExpansionCount = min(max(c.QueryExpansionCount, 2), 4)
+	if c.MaxSearchResults <= 0 {
+		c.MaxSearchResults = DefaultMaxSearchResults
+	}
+	if c.SynthesisStrategy != SynthesisMapReduce {
+		c.SynthesisStrategy = SynthesisAgent
+	}
+	if c.MapReduceMinSources <= 0 {
+		c.MapReduceMinSources = DefaultMapReduceMinSources
+	}
+	if c.ConversationKeepTurns <= 0 {
+		c.ConversationKeepTurns = DefaultConversationKeepTurns
+	}
+	if c.MaxIterations <= 0 {
+		c.MaxIterations = DefaultMaxIterations
+	}
+	if c.GroundednessThreshold <= 0 || c.GroundednessThreshold > 1 {
+		c.GroundednessThreshold = DefaultGroundednessThreshold
+	}
+	if c.GroundednessAction != GroundednessRegenerate {
+		c.GroundednessAction = GroundednessWarn
+	}
+	c.QualitySampleRate = min(max(c.QualitySampleRate, 0), 1)
+	// Parâmetros de geração fora dos limites usam o padrão do provedor
+	if !validTemperature(c.Temperature) {
+		c.Temperature = nil
+	}
+	if !validTopP(c.TopP) {
+		c.TopP = nil
+	}
+	if c.MMRLambda != nil && (*c.MMRLambda < 0 || *c.MMRLambda > 1) {
+		c.MMRLambda = nil
+	}
+	if c.FeedbackRankingWeight <= 0 {
+		c.FeedbackRankingWeight = DefaultFeedbackRankingWeight
+	}
+	if c.FeedbackRankingDays <= 0 {
+		c.FeedbackRankingDays = DefaultFeedbackRankingDays
+	}
+	c.SentenceWindow = max(c.SentenceWindow, 0)
+	// Políticas desconhecidas bloqueiam, por segurança
+	if c.Moderation != "" && c.Moderation != ModerationFlag {
+		c.Moderation = ModerationBlock
+	}
+	return c
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+)
+
+// loadConversation carrega a conversa da sessão, que passa a pertencer ao
+// usuário da pergunta se ainda não tiver dono. Retorna nil quando o
+// histórico não está habilitado. Sem sessionID, inicia uma nova sessão; a
+// conversa de outro usuário também, e a pergunta deixa de informar a sessão
+// dele, que não é lida nem alterada.
+func (s *RAGServiceImpl) loadConversation(ctx context.Context, req *domain.RAGRequest, sessionID string) (*domain.Conversation, error) {
+	if s.conversations == nil {
+		return nil, nil
+	}
+
+	if sessionID == "" {
+		return &domain.Conversation{SessionID: rand.Text(), UserID: req.UserID}, nil
+	}
+
+	conv, err := s.conversations.FindBySessionID(ctx, sessionID)
+	if errors.Is(err, domain.ErrConversationNotFound) {
+		return &domain.Conversation{SessionID: sessionID, UserID: req.UserID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !conv.AccessibleTo(req.UserID) {
+		log.Printf("Aviso: conversa %s pertence a outro usuário, iniciando uma nova", sessionID)
+		req.SessionID = ""
+		return &domain.Conversation{SessionID: rand.Text(), UserID: req.UserID}, nil
+	}
+	if conv.UserID == "" {
+		conv.UserID = req.UserID
+	}
+	return conv, nil
+}
+
+// historyMessages retorna o histórico da conversa enviado ao agente: o resumo
+// dos turnos mais antigos, quando há, seguido das mensagens restantes
+func (s *RAGServiceImpl) historyMessages(conv *domain.Conversation) ([]domain.Message, error) {
+	if conv.Summary == "" {
+		return conv.Messages, nil
+	}
+	summary, err := s.prompts.Render(prompts.HistorySummary, map[string]any{"Summary": conv.Summary})
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat([]domain.Message{{Role: domain.RoleSystem, Content: summary}}, conv.Messages), nil
+}
+
+// saveTurn adiciona a pergunta e a resposta ao histórico e persiste a conversa.
+// Apenas o texto do turno é guardado; as mensagens de ferramentas não.
+// Falhas ao salvar não impedem a resposta e são apenas registradas.
+func (s *RAGServiceImpl) saveTurn(ctx context.Context, conv *domain.Conversation, question domain.Message, answer string) {
+	conv.Messages = append(conv.Messages,
+		question,
+		domain.Message{Role: domain.RoleAssistant, Content: answer},
+	)
+	s.compactConversation(ctx, conv)
+
+	if err := s.conversations.Save(ctx, conv); err != nil {
+		log.Printf("Aviso ao salvar conversa %s: %v", conv.SessionID, err)
+	}
+}
+
+// compactConversation resume os turnos mais antigos da conversa quando o
+// histórico, com o resumo anterior, excede ConversationSummaryTokens. Os
+// últimos ConversationKeepTurns turnos são mantidos como estão, e o resumo
+// anterior é incorporado ao novo. Falhas mantêm a conversa como está; o
+// histórico que não couber no contexto é cortado na próxima pergunta.
+func (s *RAGServiceImpl) compactConversation(ctx context.Context, conv *domain.Conversation) {
+	limit := s.config.ConversationSummaryTokens
+	keep := 2 * s.config.ConversationKeepTurns
+	if limit <= 0 || len(conv.Messages) <= keep {
+		return
+	}
+	if s.tokenizer.Count(conv.Summary)+s.countTokens(conv.Messages, nil) <= limit {
+		return
+	}
+
+	older := conv.Messages[:len(conv.Messages)-keep]
+	summary, err := s.summarizeConversation(ctx, conv.Summary, older, limit/4)
+	if err != nil {
+		log.Printf("Aviso ao resumir a conversa %s, mantendo o histórico: %v", conv.SessionID, err)
+		return
+	}
+	log.Printf("Conversa %s: %d mensagens incorporadas ao resumo", conv.SessionID, len(older))
+	conv.Summary = summary
+	conv.SummarizedMessages += len(older)
+	conv.Messages = slices.Clone(conv.Messages[len(older):])
+}
+
+// summarizeConversation pede ao LLM, com o modelo de RAGConfig.SummaryModel,
+// um resumo de até limit tokens das mensagens e do resumo anterior
+func (s *RAGServiceImpl) summarizeConversation(ctx context.Context, previous string, messages []domain.Message, limit int) (string, error) {
+	prompt, err := s.prompts.Render(prompts.ConversationSummary, map[string]any{
+		"Words":    max(limit*3/4, 1),
+		"Previous": previous,
+		"Messages": messages,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	ctx = domain.WithGeneration(ctx, domain.Generation{Model: s.config.SummaryModel, MaxTokens: limit})
+	msg, err := s.llm.GenerateResponse(ctx, []domain.Message{{Role: domain.RoleUser, Content: prompt}}, nil)
+	if err != nil {
+		return "", err
+	}
+	summary := strings.TrimSpace(msg.Content)
+	if summary == "" {
+		return "", fmt.Errorf("resumo vazio")
+	}
+	return summary, nil
+}
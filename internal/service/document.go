@@ -0,0 +1,441 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/chunking"
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Limites de tamanho dos campos de um documento
+const (
+	MaxTitleLength   = 200
+	MaxContentLength = 10000 // Sem chunking, o documento é inserido inteiro
+
+	// MaxChunkedContentLength é o limite de conteúdo quando a divisão em
+	// chunks está habilitada
+	MaxChunkedContentLength = 500000
+)
+
+// Tamanho das páginas da listagem de documentos
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// AddDocument valida e insere um novo documento na base. Com chunking
+// habilitado, documentos longos são divididos em vários chunks ligados pelo
+// ParentID, e o documento recebe o ParentID como ID. Os embeddings são
+// gerados quando a busca vetorial está habilitada. Se o tenant já tiver um
+// documento com o mesmo conteúdo, nada é gravado e o erro
+// DuplicateDocumentError informa o ID do documento existente.
+func (s *RAGServiceImpl) AddDocument(ctx context.Context, doc *domain.Document) (err error) {
+	defer func() {
+		s.recordDocument(ctx, domain.AuditDocumentCreate, doc.ID, map[string]any{"title": doc.Title}, err)
+	}()
+
+	maxContent := MaxContentLength
+	if s.splitter != nil {
+		maxContent = MaxChunkedContentLength
+	}
+	if err := validateDocument(doc, maxContent); err != nil {
+		return err
+	}
+	doc.Tags = domain.NormalizeTags(doc.Tags)
+	doc.ACL = domain.NormalizeGroups(doc.ACL)
+	doc.ContentHash = domain.ContentHash(doc.Content)
+	if err := s.checkDuplicate(ctx, doc.ContentHash, ""); err != nil {
+		return err
+	}
+
+	chunks := []domain.Document{*doc}
+	if s.splitter != nil {
+		chunks = chunking.ChunkDocument(*doc, s.splitter, rand.Text())
+	}
+
+	if err := s.embedDocuments(ctx, chunks); err != nil {
+		s.publishDocument(ctx, domain.EventDocumentFailed, *doc, 0, err)
+		return err
+	}
+
+	for i := range chunks {
+		if err := s.docRepo.InsertDocument(ctx, &chunks[i]); err != nil {
+			// Os chunks anteriores já foram gravados com o ParentID
+			failed := *doc
+			failed.ID = chunks[i].ParentID
+			s.publishDocument(ctx, domain.EventDocumentFailed, failed, i, err)
+			return err
+		}
+	}
+
+	// Respostas guardadas podem não refletir o novo documento
+	s.invalidateCache(ctx)
+
+	if len(chunks) == 1 {
+		*doc = chunks[0]
+	} else {
+		doc.ID = chunks[0].ParentID
+	}
+	s.publishDocument(ctx, domain.EventDocumentIngested, *doc, len(chunks), nil)
+	return nil
+}
+
+// GetDocument busca um documento pelo ID. Se o ID for de um documento
+// dividido em chunks, os chunks são reunidos em um único documento.
+// Documentos excluídos logicamente e, com grupos no contexto
+// (domain.WithReaderGroups), os fora da ACL não são encontrados.
+func (s *RAGServiceImpl) GetDocument(ctx context.Context, id string) (*domain.Document, error) {
+	doc, err := s.docRepo.FindByID(ctx, id)
+	if err == nil && (doc.Deleted() || !readable(ctx, *doc)) {
+		return nil, domain.ErrDocumentNotFound
+	}
+	if !errors.Is(err, domain.ErrDocumentNotFound) {
+		return doc, err
+	}
+
+	chunks, err := s.docRepo.FindByParentID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, domain.ErrDocumentNotFound
+	}
+
+	reassembled := chunking.Reassemble(chunks)
+	if !readable(ctx, reassembled) {
+		return nil, domain.ErrDocumentNotFound
+	}
+	return &reassembled, nil
+}
+
+// ListDocuments retorna uma página de documentos como estão gravados (os
+// documentos longos aparecem como chunks) e o cursor da próxima página.
+// Sem limite, são retornados DefaultListLimit documentos; acima de
+// MaxListLimit, o limite é reduzido. Com grupos no contexto, apenas os
+// documentos que a ACL permite são listados.
+func (s *RAGServiceImpl) ListDocuments(ctx context.Context, filter domain.DocumentFilter, cursor string, limit int) ([]domain.Document, string, error) {
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	limit = min(limit, MaxListLimit)
+	if groups, ok := domain.ReaderGroupsFromContext(ctx); ok {
+		filter.RestrictACL, filter.Groups = true, groups
+	}
+	return s.docRepo.List(ctx, filter, cursor, limit)
+}
+
+// readable indica se o documento pode ser lido com os grupos do contexto.
+// Sem grupos no contexto, todos os documentos podem.
+func readable(ctx context.Context, doc domain.Document) bool {
+	groups, ok := domain.ReaderGroupsFromContext(ctx)
+	return !ok || doc.VisibleTo(groups)
+}
+
+// UpdateDocument substitui o conteúdo de um documento gravado, guardando a
+// versão anterior no histórico. O documento não é dividido novamente em
+// chunks, então o conteúdo é limitado a MaxContentLength; documentos já
+// divididos são editados chunk a chunk, pelo ID de cada um. Com grupos no
+// contexto, documentos fora da ACL não são encontrados.
+func (s *RAGServiceImpl) UpdateDocument(ctx context.Context, id string, doc *domain.Document) (err error) {
+	defer func() {
+		s.recordDocument(ctx, domain.AuditDocumentUpdate, id, map[string]any{"title": doc.Title}, err)
+	}()
+
+	if err := validateDocument(doc, MaxContentLength); err != nil {
+		return err
+	}
+	doc.Tags = domain.NormalizeTags(doc.Tags)
+	doc.ACL = domain.NormalizeGroups(doc.ACL)
+	current, err := s.findActive(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	doc.ID = id
+	return s.replaceDocument(ctx, doc, current)
+}
+
+// GetVersionHistory retorna todas as versões do documento, da mais antiga
+// para a atual. Com grupos no contexto, documentos fora da ACL não são
+// encontrados.
+func (s *RAGServiceImpl) GetVersionHistory(ctx context.Context, id string) ([]domain.DocumentVersion, error) {
+	current, err := s.findActive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.docRepo.GetVersionHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return append(versions, domain.NewDocumentVersion(*current)), nil
+}
+
+// Rollback volta o documento ao conteúdo de uma versão anterior. O rollback
+// é registrado como uma nova versão, então também pode ser desfeito. Com
+// grupos no contexto, documentos fora da ACL não são encontrados.
+func (s *RAGServiceImpl) Rollback(ctx context.Context, id string, version int) (_ *domain.Document, err error) {
+	defer func() {
+		s.recordDocument(ctx, domain.AuditDocumentRollback, id, map[string]any{"version": version}, err)
+	}()
+
+	current, err := s.findActive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version == current.CurrentVersion() {
+		return nil, &domain.ValidationError{Field: "version", Message: "já é a versão atual"}
+	}
+
+	versions, err := s.docRepo.GetVersionHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.Version != version {
+			continue
+		}
+		doc := &domain.Document{
+			ID:       id,
+			Title:    v.Title,
+			Content:  v.Content,
+			Link:     v.Link,
+			Category: v.Category,
+			Metadata: v.Metadata,
+			Tags:     v.Tags,
+			// A ACL e a validade não fazem parte do histórico
+			ACL:       current.ACL,
+			ExpiresAt: current.ExpiresAt,
+		}
+		if err := s.replaceDocument(ctx, doc, current); err != nil {
+			return nil, err
+		}
+		return doc, nil
+	}
+	return nil, domain.ErrVersionNotFound
+}
+
+// findActive busca um documento gravado que não foi excluído logicamente e
+// que os grupos do contexto podem ler. IDs de documentos divididos em chunks
+// são rejeitados, pois cada chunk tem o seu próprio histórico.
+func (s *RAGServiceImpl) findActive(ctx context.Context, id string) (*domain.Document, error) {
+	doc, err := s.docRepo.FindByID(ctx, id)
+	if err == nil && (doc.Deleted() || !readable(ctx, *doc)) {
+		return nil, domain.ErrDocumentNotFound
+	}
+	if !errors.Is(err, domain.ErrDocumentNotFound) {
+		return doc, err
+	}
+
+	chunks, err := s.docRepo.FindByParentID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) > 0 {
+		return nil, &domain.ValidationError{Field: "id", Message: "documento dividido em chunks; use o ID de cada chunk"}
+	}
+	return nil, domain.ErrDocumentNotFound
+}
+
+// replaceDocument gera o embedding do novo conteúdo e atualiza o documento
+// atual. Um chunk editado mantém o hash do documento lógico; um documento
+// inteiro recebe o hash do novo conteúdo, que não pode repetir o de outro.
+func (s *RAGServiceImpl) replaceDocument(ctx context.Context, doc, current *domain.Document) error {
+	doc.ContentHash = current.ContentHash
+	if current.ParentID == "" {
+		doc.ContentHash = domain.ContentHash(doc.Content)
+		if err := s.checkDuplicate(ctx, doc.ContentHash, doc.ID); err != nil {
+			return err
+		}
+	}
+
+	docs := []domain.Document{*doc}
+	if err := s.embedDocuments(ctx, docs); err != nil {
+		return err
+	}
+	doc.Embedding = docs[0].Embedding
+
+	if err := s.docRepo.Update(ctx, doc); err != nil {
+		return err
+	}
+	// Respostas guardadas podem citar o conteúdo anterior
+	s.invalidateCache(ctx)
+	return nil
+}
+
+// checkDuplicate retorna DuplicateDocumentError se outro documento do
+// tenant, que não o do ID informado, já tiver o hash de conteúdo. Documentos
+// excluídos logicamente também contam, pois podem ser restaurados.
+func (s *RAGServiceImpl) checkDuplicate(ctx context.Context, hash, id string) error {
+	existing, err := s.docRepo.FindByContentHash(ctx, hash)
+	if errors.Is(err, domain.ErrDocumentNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID == id {
+		return nil
+	}
+	if existing.ParentID != "" {
+		return &domain.DuplicateDocumentError{ID: existing.ParentID}
+	}
+	return &domain.DuplicateDocumentError{ID: existing.ID}
+}
+
+// DeleteDocument exclui logicamente o documento (ou todos os chunks do
+// documento lógico), que deixa de aparecer nas buscas até ser restaurado.
+// Com grupos no contexto, documentos fora da ACL não são encontrados.
+func (s *RAGServiceImpl) DeleteDocument(ctx context.Context, id string) error {
+	err := s.checkReadable(ctx, id)
+	if err == nil {
+		err = s.docRepo.SoftDelete(ctx, id)
+	}
+	s.recordDocument(ctx, domain.AuditDocumentDelete, id, nil, err)
+	if err != nil {
+		return err
+	}
+	// Respostas guardadas podem citar o documento excluído
+	s.invalidateCache(ctx)
+	return nil
+}
+
+// RestoreDocument desfaz a exclusão lógica do documento (ou dos chunks).
+// Com grupos no contexto, documentos fora da ACL não são encontrados.
+func (s *RAGServiceImpl) RestoreDocument(ctx context.Context, id string) error {
+	err := s.checkReadable(ctx, id)
+	if err == nil {
+		err = s.docRepo.Restore(ctx, id)
+	}
+	s.recordDocument(ctx, domain.AuditDocumentRestore, id, nil, err)
+	if err != nil {
+		return err
+	}
+	s.invalidateCache(ctx)
+	return nil
+}
+
+// checkReadable retorna ErrDocumentNotFound quando o documento com o ID, ou
+// o documento lógico cujos chunks têm esse ParentID, está fora da ACL dos
+// grupos do contexto. Os excluídos logicamente também são verificados, para
+// que a restauração respeite a ACL.
+func (s *RAGServiceImpl) checkReadable(ctx context.Context, id string) error {
+	if _, ok := domain.ReaderGroupsFromContext(ctx); !ok {
+		return nil
+	}
+	doc, err := s.docRepo.FindByID(ctx, id)
+	if err == nil {
+		if !readable(ctx, *doc) {
+			return domain.ErrDocumentNotFound
+		}
+		return nil
+	}
+	if !errors.Is(err, domain.ErrDocumentNotFound) {
+		return err
+	}
+
+	// Os chunks herdam a ACL do documento lógico, então basta o primeiro
+	chunks, _, err := s.docRepo.List(ctx, domain.DocumentFilter{ParentID: id, IncludeDeleted: true}, "", 1)
+	if err != nil {
+		return err
+	}
+	if len(chunks) > 0 && !readable(ctx, chunks[0]) {
+		return domain.ErrDocumentNotFound
+	}
+	return nil
+}
+
+// ReembedDocument gera novamente, com o cliente de embeddings atual, o
+// embedding do documento ou de todos os chunks do documento lógico, sem
+// criar uma versão. Usado para corrigir documentos gravados sem embedding ou
+// com um modelo anterior, sem reindexar a base inteira.
+func (s *RAGServiceImpl) ReembedDocument(ctx context.Context, id string) (_ int, err error) {
+	defer func() {
+		s.recordDocument(ctx, domain.AuditDocumentReembed, id, nil, err)
+	}()
+
+	if s.embedder == nil {
+		return 0, domain.ErrEmbeddingsDisabled
+	}
+
+	var docs []domain.Document
+	doc, err := s.docRepo.FindByID(ctx, id)
+	switch {
+	case err == nil && doc.Deleted():
+		return 0, domain.ErrDocumentNotFound
+	case err == nil:
+		docs = []domain.Document{*doc}
+	case errors.Is(err, domain.ErrDocumentNotFound):
+		if docs, err = s.docRepo.FindByParentID(ctx, id); err != nil {
+			return 0, err
+		}
+		if len(docs) == 0 {
+			return 0, domain.ErrDocumentNotFound
+		}
+	default:
+		return 0, err
+	}
+
+	if err := s.embedDocuments(ctx, docs); err != nil {
+		return 0, err
+	}
+	for _, doc := range docs {
+		if err := s.docRepo.SetEmbedding(ctx, doc.ID, doc.Embedding); err != nil {
+			return 0, err
+		}
+	}
+	// Respostas guardadas foram geradas com a busca anterior
+	s.invalidateCache(ctx)
+	return len(docs), nil
+}
+
+// embedDocuments gera os embeddings dos documentos em uma única chamada
+func (s *RAGServiceImpl) embedDocuments(ctx context.Context, docs []domain.Document) error {
+	if s.embedder == nil {
+		return nil
+	}
+
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.EmbeddingText()
+	}
+
+	vectors, err := s.embedder.Embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("erro ao gerar embedding do documento: %w", err)
+	}
+	for i := range docs {
+		docs[i].Embedding = vectors[i]
+	}
+	return nil
+}
+
+// validateDocument verifica os campos obrigatórios e os limites de tamanho,
+// reunindo todos os campos inválidos
+func validateDocument(doc *domain.Document, maxContent int) error {
+	var errs domain.ValidationErrors
+	switch {
+	case strings.TrimSpace(doc.Title) == "":
+		errs = append(errs, &domain.ValidationError{Field: "title", Message: "obrigatório"})
+	case len(doc.Title) > MaxTitleLength:
+		errs = append(errs, &domain.ValidationError{Field: "title", Message: fmt.Sprintf("máximo de %d caracteres", MaxTitleLength)})
+	}
+	switch {
+	case strings.TrimSpace(doc.Content) == "":
+		errs = append(errs, &domain.ValidationError{Field: "content", Message: "obrigatório"})
+	case len(doc.Content) > maxContent:
+		errs = append(errs, &domain.ValidationError{Field: "content", Message: fmt.Sprintf("máximo de %d caracteres", maxContent)})
+	}
+	if doc.Expired(time.Now()) {
+		errs = append(errs, &domain.ValidationError{Field: "expires_at", Message: "deve ser uma data futura"})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
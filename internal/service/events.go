@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// publishDocument publica o evento do documento inserido, com os chunks
+// gravados e, em document.failed, o erro
+func (s *RAGServiceImpl) publishDocument(ctx context.Context, eventType string, doc domain.Document, chunks int, err error) {
+	if s.events == nil {
+		return
+	}
+
+	event := domain.DocumentEvent{
+		Title:    doc.Title,
+		Link:     doc.Link,
+		Category: doc.Category,
+		Chunks:   chunks,
+	}
+	if chunks > 0 {
+		event.ID = doc.ID
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	s.events.Publish(ctx, eventType, event)
+}
+
+// publishAnswer publica o evento da pergunta respondida
+func (s *RAGServiceImpl) publishAnswer(ctx context.Context, req domain.RAGRequest, resp *domain.RAGResponse) {
+	if s.events == nil {
+		return
+	}
+
+	sources := make([]string, 0, len(resp.Sources))
+	for _, doc := range resp.Sources {
+		if doc.ID != "" {
+			sources = append(sources, doc.ID)
+		}
+	}
+	s.events.Publish(ctx, domain.EventQueryAnswered, domain.QueryEvent{
+		SessionID:  resp.SessionID,
+		UserID:     req.UserID,
+		Query:      req.Query,
+		Answer:     resp.Answer,
+		Sources:    sources,
+		UsedSearch: resp.UsedSearch,
+		Cached:     resp.Cached,
+		Usage:      resp.Usage,
+		CostUSD:    resp.CostUSD,
+	})
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// feedbackPriorsTTL é por quanto tempo as notas calculadas a partir das
+// avaliações são reaproveitadas antes de serem contadas de novo
+const feedbackPriorsTTL = 5 * time.Minute
+
+// feedbackPriorSmoothing equivale a avaliações neutras somadas às de cada
+// documento, para que poucas avaliações não movam muito a sua nota
+const feedbackPriorSmoothing = 5
+
+// feedbackPriors guarda, por tenant, a nota de cada documento avaliado
+type feedbackPriors struct {
+	mu       sync.Mutex
+	byTenant map[string]tenantPriors
+}
+
+// tenantPriors são as notas dos documentos de um tenant e quando foram calculadas
+type tenantPriors struct {
+	priors   map[string]float64
+	loadedAt time.Time
+}
+
+// feedbackPrior calcula a nota (de -1 a 1) de um documento a partir das
+// avaliações das respostas que o citaram: positiva quando predominam as
+// positivas, negativa quando predominam as negativas
+func feedbackPrior(stats domain.DocumentFeedback) float64 {
+	return float64(stats.Up-stats.Down) / float64(stats.Up+stats.Down+feedbackPriorSmoothing)
+}
+
+// documentPriors retorna as notas dos documentos do tenant do contexto,
+// calculadas com as avaliações dos últimos RAGConfig.FeedbackRankingDays dias
+// e guardadas por feedbackPriorsTTL
+func (s *RAGServiceImpl) documentPriors(ctx context.Context) (map[string]float64, error) {
+	tenantID := domain.TenantFromContext(ctx)
+	s.priors.mu.Lock()
+	cached, ok := s.priors.byTenant[tenantID]
+	s.priors.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) < feedbackPriorsTTL {
+		return cached.priors, nil
+	}
+
+	stats, err := s.feedback.DocumentStats(ctx, time.Now().AddDate(0, 0, -s.config.FeedbackRankingDays))
+	if err != nil {
+		return nil, err
+	}
+	priors := make(map[string]float64, len(stats))
+	for id, docStats := range stats {
+		if prior := feedbackPrior(docStats); prior != 0 {
+			priors[id] = prior
+		}
+	}
+
+	s.priors.mu.Lock()
+	if s.priors.byTenant == nil {
+		s.priors.byTenant = map[string]tenantPriors{}
+	}
+	s.priors.byTenant[tenantID] = tenantPriors{priors: priors, loadedAt: time.Now()}
+	s.priors.mu.Unlock()
+	return priors, nil
+}
+
+// applyFeedbackPriors reordena os documentos somando à relevância de cada
+// um, dada pela posição na busca (ou no rerank), a sua nota multiplicada por
+// RAGConfig.FeedbackRankingWeight. Documentos frequentes em respostas mal
+// avaliadas descem, e os frequentes em respostas bem avaliadas sobem; os sem
+// avaliações mantêm a relevância. Falhas ao contar as avaliações mantêm a
+// ordem e são apenas registradas.
+func (s *RAGServiceImpl) applyFeedbackPriors(ctx context.Context, docs []domain.Document) []domain.Document {
+	if len(docs) < 2 {
+		return docs
+	}
+	priors, err := s.documentPriors(ctx)
+	if err != nil {
+		log.Printf("Aviso ao calcular notas das avaliações, mantendo a ordem da busca: %v", err)
+		return docs
+	}
+	if len(priors) == 0 {
+		return docs
+	}
+
+	scores := make([]float64, len(docs))
+	order := make([]int, len(docs))
+	for i, doc := range docs {
+		relevance := 1 - float64(i)/float64(len(docs))
+		// As fontes avaliadas são os documentos lógicos, reunidos a partir
+		// dos chunks, então a nota de um chunk é a do seu documento
+		scores[i] = relevance + s.config.FeedbackRankingWeight*priors[cmp.Or(doc.ParentID, doc.ID)]
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	ranked := make([]domain.Document, len(docs))
+	for i, index := range order {
+		ranked[i] = docs[index]
+	}
+	return ranked
+}
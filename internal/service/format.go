@@ -0,0 +1,119 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Limites de MaxAnswerTokens: abaixo do mínimo, as chamadas de ferramentas
+// do agente podem ser cortadas
+const (
+	MinAnswerTokens = 64
+	MaxAnswerTokens = 16384
+)
+
+// MaxTemperature é a maior temperatura aceita pelos provedores
+const MaxTemperature = 2
+
+// answerFormat reúne o idioma, o estilo e os parâmetros de geração (com o
+// modelo) pedidos para a resposta
+type answerFormat struct {
+	language   string
+	style      domain.AnswerStyle
+	generation domain.Generation
+	// custom indica se a pergunta pede um estilo, parâmetros de geração ou
+	// um modelo, que tornam a resposta diferente da de uma pergunta
+	// semelhante sem eles
+	custom bool
+}
+
+// newAnswerFormat valida o formato pedido na pergunta, completa os
+// parâmetros de geração com os padrões da configuração e detecta o idioma
+// quando ele não é informado
+func (s *RAGServiceImpl) newAnswerFormat(req domain.RAGRequest) (answerFormat, error) {
+	lang, err := answerLanguage(req)
+	if err != nil {
+		return answerFormat{}, err
+	}
+	switch req.Style {
+	case "", domain.AnswerConcise, domain.AnswerDetailed, domain.AnswerBullets:
+	default:
+		return answerFormat{}, &domain.ValidationError{Field: "style", Message: "use concise, detailed ou bullet"}
+	}
+	if req.MaxAnswerTokens != 0 && (req.MaxAnswerTokens < MinAnswerTokens || req.MaxAnswerTokens > MaxAnswerTokens) {
+		return answerFormat{}, &domain.ValidationError{
+			Field:   "max_answer_tokens",
+			Message: fmt.Sprintf("deve estar entre %d e %d", MinAnswerTokens, MaxAnswerTokens),
+		}
+	}
+	if !validTemperature(req.Temperature) {
+		return answerFormat{}, &domain.ValidationError{Field: "temperature", Message: fmt.Sprintf("deve estar entre 0 e %d", MaxTemperature)}
+	}
+	if !validTopP(req.TopP) {
+		return answerFormat{}, &domain.ValidationError{Field: "top_p", Message: "deve ser maior que 0 e no máximo 1"}
+	}
+	if req.Model != "" && !slices.Contains(s.config.AllowedModels, req.Model) {
+		return answerFormat{}, &domain.ValidationError{Field: "model", Message: s.modelHint()}
+	}
+
+	return answerFormat{
+		language: lang,
+		style:    req.Style,
+		generation: domain.Generation{
+			Model:       req.Model,
+			MaxTokens:   req.MaxAnswerTokens,
+			Temperature: cmp.Or(req.Temperature, s.config.Temperature),
+			TopP:        cmp.Or(req.TopP, s.config.TopP),
+			Seed:        cmp.Or(req.Seed, s.config.Seed),
+		},
+		custom: req.Style != "" || req.MaxAnswerTokens > 0 || req.Temperature != nil || req.TopP != nil || req.Seed != nil || req.Model != "",
+	}, nil
+}
+
+// cacheKey descreve o estilo e os parâmetros de geração na chave do cache
+// de perguntas exatas
+func (f answerFormat) cacheKey() string {
+	gen := f.generation
+	return fmt.Sprintf("%s|%s|%d|%s|%s|%s", f.style, gen.Model, gen.MaxTokens, optional(gen.Temperature), optional(gen.TopP), optional(gen.Seed))
+}
+
+// modelHint descreve os modelos que a pergunta pode escolher
+func (s *RAGServiceImpl) modelHint() string {
+	if len(s.config.AllowedModels) == 0 {
+		return "a escolha do modelo não está habilitada"
+	}
+	return "use um dos modelos permitidos: " + strings.Join(s.config.AllowedModels, ", ")
+}
+
+// withGeneration aplica os parâmetros de geração às chamadas ao LLM feitas
+// com o contexto
+func (f answerFormat) withGeneration(ctx context.Context) context.Context {
+	if f.generation == (domain.Generation{}) {
+		return ctx
+	}
+	return domain.WithGeneration(ctx, f.generation)
+}
+
+// validTemperature informa se a temperatura, quando informada, está entre 0
+// e MaxTemperature
+func validTemperature(temperature *float64) bool {
+	return temperature == nil || (*temperature >= 0 && *temperature <= MaxTemperature)
+}
+
+// validTopP informa se o top_p, quando informado, está entre 0 (exclusive) e 1
+func validTopP(topP *float64) bool {
+	return topP == nil || (*topP > 0 && *topP <= 1)
+}
+
+// optional formata um valor opcional, vazio quando nulo
+func optional[T any](v *T) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(*v)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// groundednessWarning é anteposto às respostas abaixo do limite de GroundednessThreshold
+const groundednessWarning = "Aviso: parte desta resposta pode não ser sustentada pelas fontes consultadas.\n\n"
+
+// verifyGroundedness verifica a resposta contra as fontes e, abaixo do
+// limite, aplica GroundednessAction. messages é a conversa que produziu a
+// resposta, usada para pedir uma nova resposta no mesmo format. Falhas na verificação não
+// impedem a resposta e são apenas registradas.
+func (s *RAGServiceImpl) verifyGroundedness(ctx context.Context, messages []domain.Message, resp *domain.RAGResponse, format answerFormat, streaming bool) {
+	if !s.config.GroundednessCheck || len(resp.Sources) == 0 {
+		return
+	}
+
+	result, err := s.scoreGroundedness(ctx, resp.Answer, resp.Sources)
+	if err != nil {
+		log.Printf("Aviso na verificação da resposta: %v", err)
+		return
+	}
+	resp.Groundedness = result
+	if result.Score >= s.config.GroundednessThreshold {
+		return
+	}
+	log.Printf("Resposta com nota %.2f, abaixo do limite de %.2f", result.Score, s.config.GroundednessThreshold)
+
+	if s.config.GroundednessAction == GroundednessRegenerate && !streaming {
+		answer, revised, err := s.regenerate(ctx, messages, resp, result.Unsupported, format)
+		if err != nil {
+			log.Printf("Aviso ao gerar nova resposta: %v", err)
+		} else if revised.Score > result.Score {
+			resp.Answer = answer
+			revised.Regenerated = true
+			resp.Groundedness = revised
+			if revised.Score >= s.config.GroundednessThreshold {
+				return
+			}
+		}
+	}
+	resp.Answer = groundednessWarning + resp.Answer
+}
+
+// scoreGroundedness verifica a resposta contra as fontes com o juiz de
+// fidelidade do pacote evaluation
+func (s *RAGServiceImpl) scoreGroundedness(ctx context.Context, answer string, sources []domain.Document) (result *domain.Groundedness, err error) {
+	ctx, span := tracer.Start(ctx, "rag.groundedness")
+	defer func() {
+		if result != nil {
+			span.SetAttributes(attribute.Float64("rag.groundedness", result.Score))
+		}
+		endSpan(span, err)
+	}()
+
+	score, unsupported, err := s.judge.Faithfulness(ctx, answer, sources)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Groundedness{Score: score, Unsupported: unsupported}, nil
+}
+
+// regenerate pede ao LLM uma nova resposta sem as afirmações não sustentadas
+// e a verifica novamente. Os parâmetros de geração de format valem apenas
+// para a nova resposta, não para a verificação.
+func (s *RAGServiceImpl) regenerate(ctx context.Context, messages []domain.Message, resp *domain.RAGResponse, unsupported []string, format answerFormat) (string, *domain.Groundedness, error) {
+	prompt, err := s.prompts.Render(prompts.Revision, map[string]any{"Unsupported": unsupported})
+	if err != nil {
+		return "", nil, err
+	}
+
+	messages = append(messages[:len(messages):len(messages)],
+		domain.Message{Role: domain.RoleAssistant, Content: resp.Answer},
+		domain.Message{Role: domain.RoleSystem, Content: prompt},
+	)
+	msg, err := s.llm.GenerateResponse(format.withGeneration(ctx), messages, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	result, err := s.scoreGroundedness(ctx, msg.Content, resp.Sources)
+	if err != nil {
+		return "", nil, err
+	}
+	return msg.Content, result, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/language"
+)
+
+// answerLanguage retorna o idioma da resposta: o informado na pergunta ou,
+// sem ele (ou com "auto"), o detectado no texto da pergunta, vazio quando
+// não é possível detectar
+func answerLanguage(req domain.RAGRequest) (string, error) {
+	switch {
+	case req.Language == "" || strings.EqualFold(req.Language, language.Auto):
+		return language.Detect(req.Query), nil
+	case !language.Valid(req.Language):
+		return "", &domain.ValidationError{Field: "language", Message: "use um código de idioma, como pt ou en-US, ou auto"}
+	}
+	return req.Language, nil
+}
+
+// languageName retorna o nome do idioma usado no prompt, ou vazio sem idioma
+func languageName(lang string) string {
+	if lang == "" {
+		return ""
+	}
+	return language.Name(lang)
+}
+
+// cachedIn descarta a resposta guardada quando ela foi pedida em outro
+// idioma: perguntas semelhantes em idiomas diferentes podem ter embeddings
+// próximos, e a mesma pergunta pode pedir outro idioma
+func cachedIn(resp *domain.RAGResponse, lang string) *domain.RAGResponse {
+	if resp == nil || resp.Language != lang {
+		return nil
+	}
+	return resp
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+)
+
+// noAnswer é a resposta parcial de um documento sem nada relevante para a
+// pergunta, pedida no prompt prompts.Map
+const noAnswer = "NONE"
+
+// partialAnswer é a resposta à pergunta escrita a partir de uma única fonte
+type partialAnswer struct {
+	Ref    int
+	Title  string
+	Answer string
+}
+
+// mapReduce sintetiza a resposta final em duas etapas: cada fonte responde à
+// pergunta em paralelo, em uma chamada com apenas aquele documento (map), e
+// as respostas parciais são combinadas em uma última chamada, que cita as
+// fontes pelos seus números (reduce). Assim a resposta considera todas as
+// fontes, mesmo as que juntas não caberiam em um prompt. Fontes que falham
+// no map são descartadas; retorna erro apenas quando todas falham. Devolve a
+// resposta final e os tokens consumidos nas duas etapas.
+func (s *RAGServiceImpl) mapReduce(ctx context.Context, query string, sources []domain.Document, format answerFormat, onToken func(token string), partial *strings.Builder) (*domain.Message, int, error) {
+	log.Printf("Síntese map-reduce com %d fontes", len(sources))
+	cited := citeDocuments(ctx, sources)
+
+	answers := make([]string, len(cited))
+	tokens := make([]int, len(cited))
+	errs := make([]error, len(cited))
+	var wg sync.WaitGroup
+	for i, doc := range cited {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			answers[i], tokens[i], errs[i] = s.mapSource(ctx, query, doc.Document, format)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+	var partials []partialAnswer
+	var failed []error
+	for i, doc := range cited {
+		total += tokens[i]
+		if errs[i] != nil {
+			log.Printf("Aviso na resposta parcial da fonte %q, descartando-a: %v", doc.Title, errs[i])
+			failed = append(failed, errs[i])
+			continue
+		}
+		if answers[i] != "" && !strings.EqualFold(strings.Trim(answers[i], " ."), noAnswer) {
+			partials = append(partials, partialAnswer{Ref: doc.Ref, Title: doc.Title, Answer: answers[i]})
+		}
+	}
+	if len(failed) == len(cited) {
+		return nil, total, errors.Join(failed...)
+	}
+
+	prompt, err := s.prompts.Render(prompts.Reduce, map[string]any{
+		"Query":     query,
+		"Language":  languageName(format.language),
+		"Style":     string(format.style),
+		"MaxTokens": format.generation.MaxTokens,
+		"Partials":  partials,
+	})
+	if err != nil {
+		return nil, total, err
+	}
+	messages := []domain.Message{{Role: domain.RoleUser, Content: prompt}}
+	msg, err := s.generate(format.withGeneration(ctx), messages, nil, onToken, partial)
+	if err != nil {
+		return nil, total, err
+	}
+	return msg, total + s.callTokens(messages, msg), nil
+}
+
+// mapSource pede a resposta parcial à pergunta com apenas o documento e
+// retorna também os tokens consumidos
+func (s *RAGServiceImpl) mapSource(ctx context.Context, query string, doc domain.Document, format answerFormat) (string, int, error) {
+	prompt, err := s.prompts.Render(prompts.Map, map[string]any{
+		"Query":   query,
+		"Title":   doc.Title,
+		"Content": doc.Content,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	messages := []domain.Message{{Role: domain.RoleUser, Content: prompt}}
+	msg, err := s.llm.GenerateResponse(format.withGeneration(ctx), messages, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	return strings.TrimSpace(msg.Content), s.callTokens(messages, msg), nil
+}
+
+// callTokens retorna os tokens consumidos em uma chamada ao LLM, estimados
+// quando o provedor não informa o consumo
+func (s *RAGServiceImpl) callTokens(messages []domain.Message, msg *domain.Message) int {
+	if tokens := msg.Usage.Total(); tokens > 0 {
+		return tokens
+	}
+	return s.countTokens(messages, nil) + s.countTokens([]domain.Message{*msg}, nil)
+}
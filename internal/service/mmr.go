@@ -0,0 +1,91 @@
+package service
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/vector"
+)
+
+// diversify reordena os documentos com Maximal Marginal Relevance: a cada
+// passo escolhe o documento que equilibra a relevância, dada pela posição
+// na busca (ou no rerank), e a diferença para os já escolhidos. Assim os
+// primeiros documentos não são chunks quase iguais do mesmo texto, e os
+// repetidos ficam por último, os primeiros a serem cortados pelo orçamento
+// de contexto. lambda vai de 0 (apenas diversidade) a 1 (apenas relevância).
+func diversify(docs []domain.Document, lambda float64) []domain.Document {
+	if len(docs) < 3 {
+		return docs
+	}
+
+	wordSets := make([]map[string]bool, len(docs))
+	for i, doc := range docs {
+		wordSets[i] = wordSet(doc.Title + " " + doc.Content)
+	}
+	similarity := func(i, j int) float64 {
+		a, b := docs[i].Embedding, docs[j].Embedding
+		if len(a) > 0 && len(a) == len(b) {
+			return vector.Cosine(a, b)
+		}
+		return jaccard(wordSets[i], wordSets[j])
+	}
+
+	selected := make([]int, 0, len(docs))
+	picked := make([]bool, len(docs))
+	// Maior similaridade de cada documento com os já escolhidos
+	redundancy := make([]float64, len(docs))
+	for len(selected) < len(docs) {
+		best, bestScore := -1, math.Inf(-1)
+		for i := range docs {
+			if picked[i] {
+				continue
+			}
+			relevance := 1 - float64(i)/float64(len(docs))
+			score := lambda*relevance - (1-lambda)*redundancy[i]
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		selected = append(selected, best)
+		picked[best] = true
+		for i := range docs {
+			if !picked[i] {
+				redundancy[i] = max(redundancy[i], similarity(i, best))
+			}
+		}
+	}
+
+	diversified := make([]domain.Document, len(docs))
+	for i, index := range selected {
+		diversified[i] = docs[index]
+	}
+	return diversified
+}
+
+// wordSet retorna as palavras do texto, em minúsculas
+func wordSet(text string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		set[word] = true
+	}
+	return set
+}
+
+// jaccard calcula a fração das palavras que os dois textos têm em comum
+func jaccard(a, b map[string]bool) float64 {
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	total := len(a) + len(b) - shared
+	if total == 0 {
+		return 0
+	}
+	return float64(shared) / float64(total)
+}
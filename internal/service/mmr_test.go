@@ -0,0 +1,78 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+func TestDiversify(t *testing.T) {
+	goroutines := domain.Document{ID: "a", Content: "goroutines e canais em go"}
+	duplicate := domain.Document{ID: "b", Content: "goroutines e canais em Go"}
+	indexes := domain.Document{ID: "c", Content: "índices no postgres"}
+
+	tests := []struct {
+		name   string
+		docs   []domain.Document
+		lambda float64
+		want   []string
+	}{
+		{
+			name:   "menos de três documentos",
+			docs:   []domain.Document{goroutines, duplicate},
+			lambda: 0.5,
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "apenas relevância",
+			docs:   []domain.Document{goroutines, duplicate, indexes},
+			lambda: 1,
+			want:   []string{"a", "b", "c"},
+		},
+		{
+			name:   "repetido vai para o fim",
+			docs:   []domain.Document{goroutines, duplicate, indexes},
+			lambda: 0.5,
+			want:   []string{"a", "c", "b"},
+		},
+		{
+			name: "embeddings prevalecem sobre as palavras",
+			docs: []domain.Document{
+				{ID: "a", Content: "texto", Embedding: []float32{1, 0}},
+				{ID: "b", Content: "texto", Embedding: []float32{1, 0}},
+				{ID: "c", Content: "texto", Embedding: []float32{0, 1}},
+			},
+			lambda: 0.5,
+			want:   []string{"a", "c", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, doc := range diversify(tt.docs, tt.lambda) {
+				got = append(got, doc.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diversify = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "Go, canais!", b: "canais go", want: 1},
+		{a: "go canais", b: "go mutex", want: 1.0 / 3},
+		{a: "go", b: "rust", want: 0},
+		{a: "", b: "", want: 0},
+	}
+	for _, tt := range tests {
+		if got := jaccard(wordSet(tt.a), wordSet(tt.b)); got != tt.want {
+			t.Errorf("jaccard(%q, %q) = %v, esperado %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
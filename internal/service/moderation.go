@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// moderate classifica o texto de uma etapa com o cliente de moderação. Com a
+// política "block", conteúdo sinalizado falha com *domain.ContentBlockedError;
+// com "flag", a classificação é retornada para ser registrada na resposta.
+// Em streaming, a resposta já foi enviada quando é bloqueada: o cliente recebe
+// o erro em seguida e o turno não é salvo. Falhas na moderação não impedem a
+// pergunta e são apenas registradas.
+func (s *RAGServiceImpl) moderate(ctx context.Context, stage, text string) (*domain.ModerationResult, error) {
+	if s.moderator == nil || s.config.Moderation == "" || strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	ctx, span := tracer.Start(ctx, "rag.moderation", trace.WithAttributes(
+		attribute.String("rag.moderation.stage", stage),
+	))
+	result, err := s.moderator.Moderate(ctx, text)
+	if err == nil {
+		span.SetAttributes(attribute.Bool("rag.moderation.flagged", result.Flagged))
+	}
+	endSpan(span, err)
+	if err != nil {
+		log.Printf("Aviso na moderação (%s): %v", stage, err)
+		return nil, nil
+	}
+	if !result.Flagged {
+		return nil, nil
+	}
+
+	if s.config.Moderation == ModerationBlock {
+		return nil, &domain.ContentBlockedError{Stage: stage, Categories: result.Categories}
+	}
+	log.Printf("Conteúdo sinalizado pela moderação (%s): %s", stage, strings.Join(result.Categories, ", "))
+	result.Stage = stage
+	return result, nil
+}
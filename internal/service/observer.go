@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// observeTokens repassa aos observadores cada trecho entregue a onToken.
+// Sem streaming (onToken nulo), nada muda.
+func (s *RAGServiceImpl) observeTokens(ctx context.Context, onToken func(token string)) func(token string) {
+	if onToken == nil || len(s.observers) == 0 {
+		return onToken
+	}
+	return func(token string) {
+		onToken(token)
+		for _, o := range s.observers {
+			o.OnToken(ctx, token)
+		}
+	}
+}
+
+// observeRetrieval avisa os observadores do início de uma busca do agente
+func (s *RAGServiceImpl) observeRetrieval(ctx context.Context, query string, filter domain.SearchFilter) {
+	for _, o := range s.observers {
+		o.OnRetrievalStart(ctx, query, filter)
+	}
+}
+
+// observeToolCall avisa os observadores de uma ferramenta executada
+func (s *RAGServiceImpl) observeToolCall(ctx context.Context, call domain.ToolCall, err error) {
+	for _, o := range s.observers {
+		o.OnToolCall(ctx, call, err)
+	}
+}
+
+// observeComplete avisa os observadores do fim de uma pergunta
+func (s *RAGServiceImpl) observeComplete(ctx context.Context, req domain.RAGRequest, resp *domain.RAGResponse, err error) {
+	for _, o := range s.observers {
+		o.OnComplete(ctx, req, resp, err)
+	}
+}
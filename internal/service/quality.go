@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/pricing"
+)
+
+// qualityTimeout limita a avaliação de uma resposta em segundo plano
+const qualityTimeout = 2 * time.Minute
+
+// sampleQuality avalia uma fração das respostas (RAGConfig.QualitySampleRate)
+// em segundo plano, sem atrasar a resposta, e guarda as notas no repositório
+// de avaliações; sem ele, nenhuma resposta é avaliada. Respostas do
+// cache já foram avaliadas quando geradas e não entram na amostra. A
+// avaliação continua após o fim da requisição e as falhas são apenas
+// registradas.
+func (s *RAGServiceImpl) sampleQuality(ctx context.Context, query string, resp *domain.RAGResponse) {
+	if s.quality == nil || s.config.QualitySampleRate <= 0 || resp.Cached || rand.Float64() >= s.config.QualitySampleRate {
+		return
+	}
+
+	sample := domain.QualitySample{
+		Query:         query,
+		Answer:        resp.Answer,
+		Model:         resp.Model,
+		PromptVersion: resp.PromptVersion,
+	}
+	sources := resp.Sources
+
+	// O consumo do juiz não entra no medidor da pergunta, já calculado
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), qualityTimeout)
+	ctx, _ = pricing.WithMeter(ctx)
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		defer cancel()
+		ctx, span := tracer.Start(ctx, "rag.quality")
+		scores, err := s.judge.Score(ctx, sample.Query, sample.Answer, sources)
+		endSpan(span, err)
+		if err != nil {
+			log.Printf("Aviso ao avaliar a resposta: %v", err)
+			return
+		}
+
+		sample.Scores = *scores
+		sample.CreatedAt = time.Now()
+		if err := s.quality.Record(ctx, sample); err != nil {
+			log.Printf("Aviso ao registrar avaliação: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,644 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/alextavella/agentic-rag/internal/chunking"
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/evaluation"
+	"github.com/alextavella/agentic-rag/internal/pricing"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+	"github.com/alextavella/agentic-rag/internal/routing"
+	"github.com/alextavella/agentic-rag/internal/tokenizer"
+	"github.com/alextavella/agentic-rag/internal/tools"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RAGServiceImpl implementa domain.RAGService
+type RAGServiceImpl struct {
+	config   RAGConfig
+	llm      domain.LLMClient
+	docRepo  domain.DocumentRepository
+	embedder domain.EmbeddingClient // Opcional: habilita a busca vetorial
+
+	conversations domain.ConversationRepository // Opcional: habilita o histórico por sessão
+	splitter      chunking.Splitter             // Opcional: divide documentos longos em chunks
+	reranker      domain.Reranker               // Opcional: reordena os documentos recuperados
+	webSearcher   domain.WebSearcher            // Opcional: habilita a busca na web quando a base não tem a resposta
+	tools         *tools.Registry               // Ferramentas disponíveis para o agente
+	cache         domain.ResponseCache          // Opcional: reaproveita respostas de perguntas semelhantes
+	queryCache    domain.QueryCache             // Opcional: reaproveita respostas de perguntas idênticas
+	prices        pricing.Table                 // Preços usados no cálculo do custo das respostas
+	usage         domain.UsageRepository        // Opcional: acumula o consumo por sessão e usuário
+	limiter       domain.RateLimiter            // Opcional: limita as perguntas de cada usuário
+	sessions      domain.SessionStore           // Opcional: guarda o estado das sessões entre as perguntas
+	moderator     domain.ModerationClient       // Opcional: modera a pergunta e a resposta
+	tokenizer     tokenizer.Tokenizer           // Conta os tokens dos prompts
+	contextWindow int                           // Opcional: janela de contexto do modelo, em tokens
+	prompts       *prompts.Set                  // Prompts enviados ao LLM
+	judge         *evaluation.Judge             // Avalia as respostas com o LLM
+	quality       domain.QualityRepository      // Opcional: guarda as respostas avaliadas por amostragem
+	feedback      domain.FeedbackRepository     // Opcional: avaliações dos usuários usadas na ordenação das buscas
+	events        domain.EventPublisher         // Opcional: notifica documentos inseridos e perguntas respondidas
+	audit         domain.AuditRepository        // Opcional: registra perguntas, respostas, ferramentas e alterações de documentos
+	router        *routing.Router               // Opcional: escolhe o modelo de cada pergunta pela complexidade
+	observers     []domain.QueryObserver        // Acompanham as etapas de cada pergunta
+
+	background sync.WaitGroup // Avaliações de qualidade em andamento, esperadas em Close
+	priors     feedbackPriors // Notas dos documentos calculadas a partir das avaliações
+}
+
+// Option configura dependências opcionais do serviço RAG
+type Option func(*RAGServiceImpl)
+
+// WithEmbeddingClient habilita a busca vetorial usando o cliente informado
+func WithEmbeddingClient(embedder domain.EmbeddingClient) Option {
+	return func(s *RAGServiceImpl) {
+		s.embedder = embedder
+	}
+}
+
+// WithConversationRepository habilita o histórico de conversas por sessão
+func WithConversationRepository(repo domain.ConversationRepository) Option {
+	return func(s *RAGServiceImpl) {
+		s.conversations = repo
+	}
+}
+
+// WithSplitter habilita a divisão de documentos longos em chunks
+func WithSplitter(splitter chunking.Splitter) Option {
+	return func(s *RAGServiceImpl) {
+		s.splitter = splitter
+	}
+}
+
+// WithReranker habilita a reordenação dos documentos recuperados antes da
+// geração da resposta final
+func WithReranker(reranker domain.Reranker) Option {
+	return func(s *RAGServiceImpl) {
+		s.reranker = reranker
+	}
+}
+
+// WithWebSearcher habilita a ferramenta de busca na web, usada pelo agente
+// quando a busca na base não encontra documentos relevantes
+func WithWebSearcher(searcher domain.WebSearcher) Option {
+	return func(s *RAGServiceImpl) {
+		s.webSearcher = searcher
+	}
+}
+
+// WithToolRegistry define o registro de ferramentas do agente. As ferramentas
+// de busca na base e, com WithWebSearcher, na web são adicionadas
+// automaticamente, a menos que o registro já tenha uma ferramenta com o
+// mesmo nome.
+func WithToolRegistry(registry *tools.Registry) Option {
+	return func(s *RAGServiceImpl) {
+		s.tools = registry
+	}
+}
+
+// WithResponseCache habilita o cache semântico de respostas. Requer um
+// cliente de embeddings, usado para comparar as perguntas.
+func WithResponseCache(cache domain.ResponseCache) Option {
+	return func(s *RAGServiceImpl) {
+		s.cache = cache
+	}
+}
+
+// WithQueryCache habilita o cache de respostas por pergunta exata, consultado
+// antes do cache semântico e sem gerar embeddings
+func WithQueryCache(cache domain.QueryCache) Option {
+	return func(s *RAGServiceImpl) {
+		s.queryCache = cache
+	}
+}
+
+// WithPriceTable define os preços usados no cálculo do custo das respostas
+// (padrão: pricing.DefaultTable). O consumo só é medido quando o cliente de
+// LLM é decorado com pricing.NewLLMClient.
+func WithPriceTable(table pricing.Table) Option {
+	return func(s *RAGServiceImpl) {
+		s.prices = table
+	}
+}
+
+// WithUsageRepository habilita o registro do consumo e do custo agregados por
+// sessão e por usuário
+func WithUsageRepository(repo domain.UsageRepository) Option {
+	return func(s *RAGServiceImpl) {
+		s.usage = repo
+	}
+}
+
+// WithRateLimiter limita as perguntas de cada usuário (RAGRequest.UserID)
+// no tenant. Perguntas sem usuário compartilham um mesmo limite.
+func WithRateLimiter(limiter domain.RateLimiter) Option {
+	return func(s *RAGServiceImpl) {
+		s.limiter = limiter
+	}
+}
+
+// WithSessionStore guarda o estado de cada sessão (a conversa em uso, o
+// usuário e as preferências de resposta) entre as perguntas e aplica
+// RAGConfig.SessionRateLimit. Com um armazenamento compartilhado, como o
+// Redis, as instâncias atrás de um balanceador veem o mesmo estado.
+func WithSessionStore(store domain.SessionStore) Option {
+	return func(s *RAGServiceImpl) {
+		s.sessions = store
+	}
+}
+
+// WithModerationClient modera a pergunta e a resposta com o cliente
+// informado, aplicando a política de RAGConfig.Moderation
+func WithModerationClient(moderator domain.ModerationClient) Option {
+	return func(s *RAGServiceImpl) {
+		s.moderator = moderator
+	}
+}
+
+// WithQualityRepository guarda as notas das respostas avaliadas conforme
+// RAGConfig.QualitySampleRate
+func WithQualityRepository(repo domain.QualityRepository) Option {
+	return func(s *RAGServiceImpl) {
+		s.quality = repo
+	}
+}
+
+// WithFeedbackRepository usa as avaliações das respostas pelos usuários
+// para reordenar os documentos das buscas, quando RAGConfig.FeedbackRanking
+// está habilitado
+func WithFeedbackRepository(repo domain.FeedbackRepository) Option {
+	return func(s *RAGServiceImpl) {
+		s.feedback = repo
+	}
+}
+
+// WithEventPublisher publica os eventos dos documentos inseridos
+// (document.ingested e document.failed) e das perguntas respondidas
+// (query.answered)
+func WithEventPublisher(events domain.EventPublisher) Option {
+	return func(s *RAGServiceImpl) {
+		s.events = events
+	}
+}
+
+// WithAuditLog registra no log de auditoria as perguntas, as respostas, as
+// ferramentas executadas pelo agente e as alterações de documentos
+func WithAuditLog(repo domain.AuditRepository) Option {
+	return func(s *RAGServiceImpl) {
+		s.audit = repo
+	}
+}
+
+// WithModelRouter habilita a escolha automática, pela complexidade de cada
+// pergunta, entre um modelo pequeno e um grande. O modelo pedido na pergunta
+// tem precedência sobre o roteador.
+func WithModelRouter(router *routing.Router) Option {
+	return func(s *RAGServiceImpl) {
+		s.router = router
+	}
+}
+
+// WithObserver registra um observador das etapas de cada pergunta (buscas,
+// ferramentas, trechos gerados e conclusão). Pode ser usada várias vezes;
+// os observadores são chamados na ordem de registro.
+func WithObserver(observer domain.QueryObserver) Option {
+	return func(s *RAGServiceImpl) {
+		s.observers = append(s.observers, observer)
+	}
+}
+
+// WithTokenizer define como os tokens dos prompts são contados (padrão:
+// estimativa pelo número de caracteres)
+func WithTokenizer(t tokenizer.Tokenizer) Option {
+	return func(s *RAGServiceImpl) {
+		s.tokenizer = t
+	}
+}
+
+// WithContextWindow informa a janela de contexto do modelo, em tokens. Os
+// prompts ficam limitados a ela, menos uma reserva para a resposta: as
+// mensagens mais antigas do histórico são descartadas para caber.
+func WithContextWindow(tokens int) Option {
+	return func(s *RAGServiceImpl) {
+		s.contextWindow = tokens
+	}
+}
+
+// WithPrompts define os prompts enviados ao LLM (padrão: prompts.Default)
+func WithPrompts(set *prompts.Set) Option {
+	return func(s *RAGServiceImpl) {
+		s.prompts = set
+	}
+}
+
+// WithConfig define as configurações do fluxo de recuperação
+func WithConfig(cfg RAGConfig) Option {
+	return func(s *RAGServiceImpl) {
+		s.config = cfg
+	}
+}
+
+// NewRAGService cria uma nova instância do serviço RAG
+func NewRAGService(llm domain.LLMClient, docRepo domain.DocumentRepository, opts ...Option) *RAGServiceImpl {
+	s := &RAGServiceImpl{
+		llm:     llm,
+		docRepo: docRepo,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.config = s.config.withDefaults()
+	if s.prices == nil {
+		s.prices = pricing.DefaultTable()
+	}
+	if s.tokenizer == nil {
+		s.tokenizer = tokenizer.Estimator{}
+	}
+	if s.prompts == nil {
+		s.prompts = prompts.Default()
+	}
+	s.judge = evaluation.NewJudge(s.llm, s.prompts)
+
+	if s.tools == nil {
+		s.tools = tools.NewRegistry()
+	}
+	if !s.tools.Has(searchToolName) {
+		if err := s.tools.Register(CreateSearchTool(), s.handleSearch); err != nil {
+			log.Printf("Erro ao registrar ferramenta de busca: %v", err)
+		}
+	}
+	if s.webSearcher != nil && !s.tools.Has(webSearchToolName) {
+		if err := s.tools.Register(CreateWebSearchTool(), s.handleWebSearch); err != nil {
+			log.Printf("Erro ao registrar ferramenta de busca na web: %v", err)
+		}
+	}
+
+	if s.config.RetrievalStrategy == RetrievalHyDE && s.embedder == nil {
+		log.Println("Aviso: estratégia HyDE requer cliente de embeddings, usando busca direta")
+	}
+	if s.config.FeedbackRanking && s.feedback == nil {
+		log.Println("Aviso: ordenação pelas avaliações requer repositório de feedback, ordenação desabilitada")
+	}
+	if s.cache != nil && s.embedder == nil {
+		log.Println("Aviso: cache de respostas requer cliente de embeddings, cache desabilitado")
+	}
+	return s
+}
+
+// HealthCheck verifica se as dependências do serviço estão acessíveis
+func (s *RAGServiceImpl) HealthCheck(ctx context.Context) error {
+	return s.docRepo.HealthCheck(ctx)
+}
+
+// Close espera as avaliações de qualidade ainda em andamento, até o
+// cancelamento do contexto. Deve ser chamado depois que o serviço para de
+// receber perguntas, antes de fechar o banco.
+func (s *RAGServiceImpl) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("avaliações de qualidade interrompidas: %w", ctx.Err())
+	}
+}
+
+// ProcessQuery responde à pergunta do usuário, permitindo que o agente
+// decida se precisa consultar a base antes de responder.
+//
+// Se o contexto for cancelado no meio do processamento, retorna um
+// *domain.TimeoutResult com as fontes e a resposta parcial obtidas até então.
+func (s *RAGServiceImpl) ProcessQuery(ctx context.Context, req domain.RAGRequest) (*domain.RAGResponse, error) {
+	return s.processQuery(ctx, req, nil)
+}
+
+// ProcessQueryStream funciona como ProcessQuery, mas gera as respostas do
+// agente em streaming, chamando onToken para cada trecho de texto recebido
+func (s *RAGServiceImpl) ProcessQueryStream(ctx context.Context, req domain.RAGRequest, onToken func(token string)) (*domain.RAGResponse, error) {
+	return s.processQuery(ctx, req, onToken)
+}
+
+// processQuery implementa o fluxo do agente. Quando onToken é informado,
+// as chamadas ao LLM são feitas em streaming.
+func (s *RAGServiceImpl) processQuery(ctx context.Context, req domain.RAGRequest, onToken func(token string)) (resp *domain.RAGResponse, err error) {
+	ctx, span := tracer.Start(ctx, "rag.process_query", trace.WithAttributes(
+		attribute.Bool("rag.stream", onToken != nil),
+	))
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(
+				attribute.Bool("rag.cached", resp.Cached),
+				attribute.Bool("rag.used_search", resp.UsedSearch),
+				attribute.Int("rag.steps", len(resp.Steps)),
+				attribute.Int("rag.sources", len(resp.Sources)),
+				attribute.Int("rag.tokens", resp.Usage.Total()),
+				attribute.Float64("rag.cost_usd", resp.CostUSD),
+			)
+		}
+		endSpan(span, err)
+	}()
+	defer func() { s.observeComplete(ctx, req, resp, err) }()
+	onToken = s.observeTokens(ctx, onToken)
+
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, &domain.ValidationError{Field: "query", Message: "obrigatório"}
+	}
+	if req.TenantID != "" {
+		if err := domain.ValidateTenantID(req.TenantID); err != nil {
+			return nil, err
+		}
+		ctx = domain.WithTenant(ctx, req.TenantID)
+	}
+	ctx = s.withAuditRequest(ctx)
+	// As preferências guardadas na sessão completam a pergunta
+	sess := s.loadSession(ctx, &req)
+	s.recordQuery(ctx, req)
+	defer func() { s.recordAnswer(ctx, req, resp, err) }()
+
+	filter := req.SearchFilter()
+	if req.Fuzzy == nil {
+		filter.Fuzzy = s.config.FuzzySearch
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	format, err := s.newAnswerFormat(req)
+	if err != nil {
+		return nil, err
+	}
+	s.routeModel(ctx, req.Query, &format)
+	var responseSchema *jsonschema.Schema
+	if req.ResponseSchema != nil {
+		if responseSchema, err = compileResponseSchema(req.ResponseSchema); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkRateLimit(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.checkSessionLimit(ctx, sess); err != nil {
+		return nil, err
+	}
+	queryFlag, err := s.moderate(ctx, domain.ModerationQuery, req.Query)
+	if err != nil {
+		return nil, err
+	}
+	// As buscas feitas pelo agente recebem o filtro pelo contexto
+	ctx = withSearchFilter(ctx, filter)
+
+	// Mede o consumo de todas as chamadas ao LLM feitas para esta pergunta
+	ctx, meter := pricing.WithMeter(ctx)
+
+	// Carrega o histórico da sessão, quando habilitado
+	conversationID := req.SessionID
+	if sess != nil && sess.ConversationID != "" {
+		conversationID = sess.ConversationID
+	}
+	conv, err := s.loadConversation(ctx, &req, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	userMessage := domain.Message{Role: domain.RoleUser, Content: req.Query}
+	var messages []domain.Message
+	if conv != nil {
+		history, err := s.historyMessages(conv)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, history...)
+	}
+	messages = append(messages, userMessage)
+
+	// Responde pelo cache quando a mesma pergunta, ou uma semelhante, já foi
+	// respondida no mesmo idioma e formato; caso contrário, executa o agente
+	// até obter a resposta final
+	queryKey := s.queryCacheKey(req.Query, conv, filter, format)
+	resp = cachedIn(s.lookupQueryCache(ctx, queryKey), format.language)
+	var vector []float32
+	if resp == nil {
+		vector = s.cacheVector(ctx, req.Query, conv, filter, format)
+		resp = cachedIn(s.lookupCache(ctx, vector), format.language)
+	}
+	if resp != nil {
+		if onToken != nil {
+			onToken(resp.Answer)
+		}
+	} else {
+		resp, err = s.runAgent(ctx, messages, len(messages)-1, format, onToken)
+		if err != nil {
+			return nil, err
+		}
+		// Respostas bloqueadas não são guardadas; as do cache já foram moderadas
+		answerFlag, err := s.moderate(ctx, domain.ModerationAnswer, resp.Answer)
+		if err != nil {
+			return nil, err
+		}
+		if answerFlag != nil {
+			resp.Moderation = append(resp.Moderation, *answerFlag)
+		}
+		s.storeCache(ctx, vector, resp)
+		s.storeQueryCache(ctx, queryKey, resp)
+	}
+
+	// A resposta estruturada é derivada do texto a cada pergunta, pois o
+	// cache guarda apenas o texto
+	if responseSchema != nil {
+		resp.StructuredAnswer, err = s.structure(ctx, req.Query, resp, req.ResponseSchema, responseSchema)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if queryFlag != nil {
+		resp.Moderation = append([]domain.ModerationResult{*queryFlag}, resp.Moderation...)
+	}
+
+	// Respostas do cache custam apenas o que foi gasto nesta requisição
+	resp.Usage = meter.Usage()
+	resp.CostUSD = meter.Cost(s.prices)
+
+	// Persiste o novo turno da conversa
+	if conv != nil {
+		s.saveTurn(ctx, conv, userMessage, resp.Answer)
+		resp.SessionID = cmp.Or(req.SessionID, conv.SessionID)
+	}
+	s.saveSession(ctx, sess, req, conv)
+	s.recordUsage(ctx, req.UserID, resp)
+	s.sampleQuality(ctx, req.Query, resp)
+	s.publishAnswer(ctx, req, resp)
+
+	return resp, nil
+}
+
+// anonymousUser identifica, no limite de perguntas, as perguntas sem usuário
+const anonymousUser = "anonymous"
+
+// checkRateLimit consome uma pergunta do limite do usuário no tenant do
+// contexto. Falhas ao consultar o limite não bloqueiam a pergunta e são
+// apenas registradas.
+func (s *RAGServiceImpl) checkRateLimit(ctx context.Context, userID string) error {
+	if s.limiter == nil {
+		return nil
+	}
+	if userID == "" {
+		userID = anonymousUser
+	}
+
+	err := s.limiter.Allow(ctx, domain.TenantFromContext(ctx)+":"+userID)
+	if err != nil && !errors.Is(err, domain.ErrRateLimited) {
+		log.Printf("Aviso ao verificar limite de perguntas: %v", err)
+		return nil
+	}
+	return err
+}
+
+// recordUsage soma o consumo da resposta aos totais da sessão e do usuário.
+// Falhas não impedem a resposta e são apenas registradas.
+func (s *RAGServiceImpl) recordUsage(ctx context.Context, userID string, resp *domain.RAGResponse) {
+	if s.usage == nil || resp.Usage.Total() == 0 {
+		return
+	}
+
+	if err := s.usage.Record(ctx, resp.SessionID, userID, resp.Usage, resp.CostUSD); err != nil {
+		log.Printf("Aviso ao registrar consumo: %v", err)
+	}
+}
+
+// generate chama o LLM. Quando onToken é informado, usa streaming e repassa
+// cada trecho recebido, acumulando-o também em partial.
+func (s *RAGServiceImpl) generate(ctx context.Context, messages []domain.Message, tools []domain.Tool, onToken func(token string), partial *strings.Builder) (*domain.Message, error) {
+	if onToken == nil {
+		msg, err := s.llm.GenerateResponse(ctx, messages, tools)
+		if err != nil {
+			return nil, err
+		}
+		partial.WriteString(msg.Content)
+		return msg, nil
+	}
+
+	chunks, err := s.llm.GenerateResponseStream(ctx, messages, tools)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &domain.Message{Role: domain.RoleAssistant}
+	var content strings.Builder
+	done := false
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			return nil, chunk.Err
+		}
+		if chunk.Content != "" {
+			content.WriteString(chunk.Content)
+			partial.WriteString(chunk.Content)
+			onToken(chunk.Content)
+		}
+		if chunk.Done {
+			msg.ToolCalls = chunk.ToolCalls
+			msg.Usage = chunk.Usage
+			done = true
+		}
+	}
+
+	// O canal é fechado sem o último pedaço quando o contexto é cancelado
+	if !done {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("streaming encerrado antes do fim da resposta")
+	}
+
+	msg.Content = content.String()
+	return msg, nil
+}
+
+// timeoutResult monta o resultado parcial de uma pergunta interrompida
+func timeoutResult(ctx context.Context, sources []domain.Document, partial *strings.Builder) *domain.TimeoutResult {
+	return &domain.TimeoutResult{
+		Sources:       chunking.MergeChunks(sources),
+		PartialAnswer: partial.String(),
+		Err:           ctx.Err(),
+	}
+}
+
+// toolResult guarda o resultado da execução de uma chamada de ferramenta
+type toolResult struct {
+	message domain.Message
+	sources []domain.Document
+}
+
+// executeToolCalls executa as chamadas de ferramentas concorrentemente e
+// só retorna depois que todas as goroutines terminaram. As buscas recebem o
+// mesmo contexto, então um cancelamento interrompe todas elas.
+func (s *RAGServiceImpl) executeToolCalls(ctx context.Context, calls []domain.ToolCall) ([]domain.Message, []domain.Document) {
+	results := make([]toolResult, len(calls))
+
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = s.executeToolCall(ctx, call)
+		}()
+	}
+	wg.Wait()
+
+	messages := make([]domain.Message, 0, len(results))
+	var sources []domain.Document
+	for _, r := range results {
+		messages = append(messages, r.message)
+		sources = append(sources, r.sources...)
+	}
+	return messages, sources
+}
+
+// executeToolCall executa uma única chamada de ferramenta. Erros são
+// devolvidos ao agente como conteúdo da mensagem, para que ele possa
+// responder sem o resultado da ferramenta.
+func (s *RAGServiceImpl) executeToolCall(ctx context.Context, call domain.ToolCall) toolResult {
+	ctx, span := tracer.Start(ctx, "rag.tool_call", trace.WithAttributes(
+		attribute.String("rag.tool", call.Name),
+	))
+	defer span.End()
+
+	msg := domain.Message{
+		Role:       domain.RoleTool,
+		Name:       call.Name,
+		ToolCallID: call.ID,
+	}
+
+	result, err := s.tools.Execute(ctx, call)
+	s.record(ctx, domain.AuditEntry{
+		Action:   domain.AuditToolCall,
+		Resource: call.Name,
+		Details:  map[string]any{"arguments": call.Arguments},
+	}, err)
+	s.observeToolCall(ctx, call, err)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		log.Printf("Erro ao executar ferramenta %s: %v", call.Name, err)
+		content, _ := json.Marshal(map[string]string{"error": err.Error()})
+		msg.Content = string(content)
+		return toolResult{message: msg}
+	}
+
+	msg.Content = result.Content
+	return toolResult{message: msg, sources: result.Sources}
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/llmjson"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// rrfK é a constante da fusão por rank recíproco (Reciprocal Rank Fusion)
+const rrfK = 60
+
+// retrieve busca os documentos relevantes para a consulta do agente. Com a
+// expansão de consultas habilitada, busca também pelas reformulações geradas
+// pelo LLM e combina os resultados sem duplicatas.
+func (s *RAGServiceImpl) retrieve(ctx context.Context, query string, filter domain.SearchFilter) (docs []domain.Document, err error) {
+	ctx, span := tracer.Start(ctx, "rag.retrieve", trace.WithAttributes(
+		attribute.String("rag.strategy", s.config.RetrievalStrategy),
+		attribute.Bool("rag.query_expansion", s.config.QueryExpansion),
+		attribute.String("rag.category", filter.Category),
+		attribute.StringSlice("rag.tags", filter.Tags),
+		attribute.Bool("rag.fuzzy", filter.Fuzzy),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("rag.results", len(docs)))
+		endSpan(span, err)
+	}()
+	s.observeRetrieval(ctx, query, filter)
+
+	if !s.config.QueryExpansion {
+		return s.search(ctx, query, filter)
+	}
+
+	queries := []string{query}
+	expanded, err := s.expandQuery(ctx, query)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		log.Printf("Aviso na expansão da consulta, usando apenas a original: %v", err)
+	}
+	queries = append(queries, expanded...)
+
+	// Executa as buscas em paralelo
+	results := make([][]domain.Document, len(queries))
+	errs := make([]error, len(queries))
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = s.search(ctx, q, filter)
+		}()
+	}
+	wg.Wait()
+
+	// Falhas em reformulações são toleradas; a busca original precisa funcionar
+	if errs[0] != nil {
+		return nil, errs[0]
+	}
+	for i, err := range errs[1:] {
+		if err != nil {
+			log.Printf("Aviso na busca pela reformulação %q: %v", queries[i+1], err)
+		}
+	}
+
+	return fuseResults(results, s.config.MaxSearchResults), nil
+}
+
+// expandQuery pede ao LLM reformulações da consulta
+func (s *RAGServiceImpl) expandQuery(ctx context.Context, query string) ([]string, error) {
+	prompt, err := s.prompts.Render(prompts.Expansion, map[string]any{"Count": s.config.QueryExpansionCount, "Query": query})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.llm.GenerateResponse(ctx, []domain.Message{
+		{Role: domain.RoleUser, Content: prompt},
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar reformulações: %w", err)
+	}
+
+	var result struct {
+		Queries []string `json:"queries"`
+	}
+	if err := json.Unmarshal([]byte(llmjson.Extract(resp.Content)), &result); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar reformulações: %w", err)
+	}
+
+	// Descarta reformulações vazias ou iguais à consulta original
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(query)): true}
+	var queries []string
+	for _, q := range result.Queries {
+		key := strings.ToLower(strings.TrimSpace(q))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		queries = append(queries, strings.TrimSpace(q))
+		if len(queries) == s.config.QueryExpansionCount {
+			break
+		}
+	}
+	return queries, nil
+}
+
+// fuseResults combina os resultados de várias buscas com Reciprocal Rank
+// Fusion: documentos bem posicionados em mais buscas ficam à frente.
+// Documentos repetidos são unificados e o total é limitado a limit.
+func fuseResults(results [][]domain.Document, limit int) []domain.Document {
+	scores := map[string]float64{}
+	docs := map[string]domain.Document{}
+	var order []string
+
+	for _, list := range results {
+		for rank, doc := range list {
+			key := documentKey(doc)
+			if _, ok := docs[key]; !ok {
+				docs[key] = doc
+				order = append(order, key)
+			}
+			scores[key] += 1.0 / float64(rrfK+rank+1)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	fused := make([]domain.Document, 0, min(len(order), limit))
+	for _, key := range order {
+		if len(fused) == limit {
+			break
+		}
+		fused = append(fused, docs[key])
+	}
+	return fused
+}
+
+// documentKey identifica um documento (ou chunk) para remover duplicatas
+func documentKey(doc domain.Document) string {
+	if doc.ID != "" {
+		return doc.ID
+	}
+	return doc.Title + "|" + doc.Link + "|" + doc.ParentID + "|" + strconv.Itoa(doc.ChunkIndex)
+}
+
+// search busca os documentos de uma consulta usando a estratégia configurada.
+// Se a busca HyDE falhar ou não encontrar nada, recorre à busca direta.
+func (s *RAGServiceImpl) search(ctx context.Context, query string, filter domain.SearchFilter) ([]domain.Document, error) {
+	if s.config.RetrievalStrategy == RetrievalHyDE && s.embedder != nil {
+		docs, err := s.searchByHypotheticalAnswer(ctx, query, filter)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			log.Printf("Aviso na busca HyDE, usando busca direta: %v", err)
+		} else if len(docs) > 0 {
+			return docs, nil
+		}
+	}
+
+	return s.SearchDocuments(ctx, query, filter)
+}
+
+// searchByHypotheticalAnswer pede ao LLM uma resposta hipotética para a
+// consulta e busca os documentos mais similares a ela. A resposta, mesmo
+// imprecisa, costuma ficar mais próxima dos documentos do que a pergunta.
+func (s *RAGServiceImpl) searchByHypotheticalAnswer(ctx context.Context, query string, filter domain.SearchFilter) ([]domain.Document, error) {
+	prompt, err := s.prompts.Render(prompts.HyDE, map[string]any{"Query": query})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.llm.GenerateResponse(ctx, []domain.Message{
+		{Role: domain.RoleUser, Content: prompt},
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar resposta hipotética: %w", err)
+	}
+	if strings.TrimSpace(resp.Content) == "" {
+		return nil, fmt.Errorf("resposta hipotética vazia")
+	}
+
+	return s.searchByMeaning(ctx, resp.Content, filter)
+}
+
+// SearchDocuments busca documentos diretamente na base. Com um cliente de
+// embeddings configurado, busca por similaridade semântica e recorre à busca
+// textual quando a busca vetorial falha ou não encontra nada. Apenas
+// documentos que atendem ao filtro são retornados.
+func (s *RAGServiceImpl) SearchDocuments(ctx context.Context, query string, filter domain.SearchFilter) ([]domain.Document, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	if s.embedder != nil {
+		docs, err := s.searchByMeaning(ctx, query, filter)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			log.Printf("Aviso na busca vetorial, usando busca textual: %v", err)
+		} else if len(docs) > 0 {
+			return docs, nil
+		}
+	}
+
+	return s.docRepo.SearchDocuments(ctx, query, filter)
+}
+
+// SearchWithFacets busca documentos pelo texto da consulta e conta os
+// encontrados por categoria, tag e mês de criação. A busca é sempre textual,
+// pois as contagens precisam de todos os documentos que atendem à consulta, e
+// não apenas dos mais próximos.
+func (s *RAGServiceImpl) SearchWithFacets(ctx context.Context, query string, filter domain.SearchFilter) (*domain.FacetedSearch, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, &domain.ValidationError{Field: "query", Message: "obrigatório"}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return s.docRepo.SearchWithFacets(ctx, query, filter)
+}
+
+// searchByMeaning gera o embedding da consulta e busca os documentos mais similares
+func (s *RAGServiceImpl) searchByMeaning(ctx context.Context, query string, filter domain.SearchFilter) ([]domain.Document, error) {
+	vectors, err := s.embedder.Embed(domain.WithQueryEmbedding(ctx), []string{query})
+	if err != nil {
+		return nil, err
+	}
+	return s.docRepo.SearchByVector(ctx, vectors[0], filter)
+}
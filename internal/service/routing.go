@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// routeModel escolhe o modelo da pergunta pelo roteador, quando configurado,
+// registrando a decisão no log e no span da pergunta. O modelo pedido na
+// pergunta substitui o roteador.
+func (s *RAGServiceImpl) routeModel(ctx context.Context, query string, format *answerFormat) {
+	if s.router == nil || format.generation.Model != "" {
+		return
+	}
+
+	decision := s.router.Route(query)
+	format.generation.Model = decision.Model
+	log.Printf("Modelo escolhido pelo roteador: %s", decision)
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("rag.route.model", decision.Model),
+		attribute.String("rag.route.tier", decision.Tier),
+		attribute.Int("rag.route.score", decision.Score),
+	)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// searchToolName é o nome da ferramenta de busca exposta ao agente
+const searchToolName = "search_metadata"
+
+// searchFilterKey é a chave do filtro de busca da pergunta no contexto
+type searchFilterKey struct{}
+
+// withSearchFilter guarda no contexto o filtro aplicado às buscas do agente
+func withSearchFilter(ctx context.Context, filter domain.SearchFilter) context.Context {
+	return context.WithValue(ctx, searchFilterKey{}, filter)
+}
+
+// searchFilterFrom retorna o filtro guardado no contexto, ou um filtro vazio
+func searchFilterFrom(ctx context.Context) domain.SearchFilter {
+	filter, _ := ctx.Value(searchFilterKey{}).(domain.SearchFilter)
+	return filter
+}
+
+// CreateSearchTool define a ferramenta de busca que o agente poderá usar
+func CreateSearchTool() domain.Tool {
+	return domain.Tool{
+		Name:        searchToolName,
+		Description: "Search metadata in database or API from a query",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Text to search in metadata",
+				},
+				"category": map[string]any{
+					"type":        "string",
+					"description": "Optional category to restrict the search to",
+				},
+				"metadata": map[string]any{
+					"type":                 "object",
+					"description":          "Optional metadata values the documents must have",
+					"additionalProperties": map[string]any{"type": "string"},
+				},
+				"filter": map[string]any{
+					"type": "string",
+					"description": "Optional filter expression on the document metadata, with clauses joined by AND: " +
+						"key=value, key IN (a, b), key>=n, key>n, key<=n, key<n and key EXISTS; " +
+						"category=value restricts the category and tags=value or tags IN (a, b) the tags. " +
+						"Quote values with spaces or commas. Ranges compare numbers; to compare versions part by part, " +
+						"write the bound as a version literal (version>=v\"1.20\" matches 1.100 but not 1.9). Example: category=performance AND version>=v\"1.20\"",
+				},
+			},
+			"required": []string{"query"},
+		},
+	}
+}
+
+// handleSearch executa a ferramenta de busca: recupera os documentos da base
+// que atendem ao filtro da pergunta e aos filtros pedidos pelo agente,
+// reordena-os quando há reranker, ordenação pelas avaliações ou
+// diversificação e os devolve ao agente em JSON, resumidos quando excedem o
+// orçamento de contexto. O filtro da pergunta prevalece, então o agente só
+// pode restringir a busca.
+func (s *RAGServiceImpl) handleSearch(ctx context.Context, arguments string) (*domain.ToolResult, error) {
+	// Extrai os argumentos da função (a consulta de busca)
+	var args struct {
+		Query    string            `json:"query"`
+		Category string            `json:"category"`
+		Metadata map[string]string `json:"metadata"`
+		Filter   string            `json:"filter"`
+	}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return nil, fmt.Errorf("erro ao processar argumentos: %w", err)
+	}
+	expression, err := domain.ParseSearchFilter(args.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := searchFilterFrom(ctx).Merge(domain.SearchFilter{Category: args.Category, Metadata: args.Metadata}).Merge(expression)
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Executa a busca real na base
+	docs, err := s.retrieve(ctx, args.Query, filter)
+	if err != nil {
+		return nil, fmt.Errorf("erro na busca: %w", err)
+	}
+	if len(docs) == 0 {
+		return &domain.ToolResult{Content: "[]"}, nil
+	}
+
+	// Reordena os documentos para que os mais relevantes venham primeiro
+	if s.reranker != nil {
+		reranked, err := s.reranker.Rerank(ctx, args.Query, docs)
+		if err != nil {
+			log.Printf("Aviso no rerank, mantendo a ordem da busca: %v", err)
+		} else {
+			docs = reranked
+		}
+	}
+	// Rebaixa os documentos frequentes em respostas mal avaliadas
+	if s.config.FeedbackRanking && s.feedback != nil {
+		docs = s.applyFeedbackPriors(ctx, docs)
+	}
+	// Evita que os primeiros documentos sejam chunks quase iguais
+	if s.config.MMRLambda != nil {
+		docs = diversify(docs, *s.config.MMRLambda)
+	}
+	// Acrescenta aos chunks as frases vizinhas do documento original
+	if s.config.SentenceWindow > 0 {
+		docs = s.expandSentenceWindows(ctx, docs)
+	}
+	// Resume as fontes que excedem o orçamento de contexto; as fontes da
+	// resposta continuam com o conteúdo original
+	sources := docs
+	if s.config.SourceSummarization {
+		docs = s.summarizeSources(ctx, args.Query, docs)
+	}
+	// Descarta os menos relevantes que não cabem no orçamento de contexto
+	docs = s.fitDocuments(ctx, docs)
+	if len(docs) == 0 {
+		return &domain.ToolResult{Content: "[]"}, nil
+	}
+
+	// Cada documento recebe o número com que o agente deve citá-lo
+	content, err := json.Marshal(citeDocuments(ctx, docs))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao converter para JSON: %w", err)
+	}
+	return &domain.ToolResult{Content: string(content), Sources: sources[:len(docs)]}, nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/alextavella/agentic-rag/internal/chunking"
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// expandSentenceWindows acrescenta a cada chunk encontrado as frases vizinhas
+// do documento original (RAGConfig.SentenceWindow antes e depois), para que
+// chunks pequenos não cheguem ao agente sem o contexto em volta. Os chunks de
+// cada documento são buscados uma única vez; falhas mantêm o chunk como está.
+func (s *RAGServiceImpl) expandSentenceWindows(ctx context.Context, docs []domain.Document) []domain.Document {
+	siblings := map[string][]domain.Document{}
+	expanded := make([]domain.Document, len(docs))
+	for i, doc := range docs {
+		if doc.ParentID == "" {
+			expanded[i] = doc
+			continue
+		}
+		chunks, ok := siblings[doc.ParentID]
+		if !ok {
+			var err error
+			if chunks, err = s.docRepo.FindByParentID(ctx, doc.ParentID); err != nil {
+				log.Printf("Aviso ao buscar os chunks de %s para expandir o contexto: %v", doc.ParentID, err)
+			}
+			siblings[doc.ParentID] = chunks
+		}
+		expanded[i] = chunking.ExpandWindow(doc, chunks, s.config.SentenceWindow)
+	}
+	return expanded
+}
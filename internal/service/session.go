@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/language"
+)
+
+// sessionRateWindow é a janela do limite de perguntas por sessão
+const sessionRateWindow = time.Minute
+
+// sessionQueriesCounter é o contador das perguntas da sessão na janela
+const sessionQueriesCounter = "queries"
+
+// loadSession carrega o estado da sessão e aplica as preferências guardadas
+// aos campos que a pergunta não informa. Retorna nil sem armazenamento de
+// sessões ou sem sessão na pergunta, e uma sessão nova quando ela não
+// existe. A sessão de outro usuário é tratada como inexistente: a pergunta
+// passa a não ter sessão e começa uma nova, sem usar nem alterar a dele.
+// Falhas ao consultar o armazenamento não impedem a pergunta e são apenas
+// registradas.
+func (s *RAGServiceImpl) loadSession(ctx context.Context, req *domain.RAGRequest) *domain.Session {
+	if s.sessions == nil || req.SessionID == "" {
+		return nil
+	}
+
+	sess, err := s.sessions.Get(ctx, req.SessionID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrSessionNotFound) {
+			log.Printf("Aviso ao carregar a sessão %s: %v", req.SessionID, err)
+		}
+		return &domain.Session{ID: req.SessionID}
+	}
+	if !sess.AccessibleTo(req.UserID) {
+		log.Printf("Aviso: sessão %s pertence a outro usuário, iniciando uma nova", req.SessionID)
+		req.SessionID = ""
+		return nil
+	}
+
+	prefs := sess.Preferences
+	if req.Language == "" {
+		req.Language = prefs.Language
+	}
+	if req.Style == "" {
+		req.Style = prefs.Style
+	}
+	// Um modelo que deixou de ser permitido é esquecido
+	if req.Model == "" && slices.Contains(s.config.AllowedModels, prefs.Model) {
+		req.Model = prefs.Model
+	}
+	return sess
+}
+
+// checkSessionLimit conta a pergunta no limite de RAGConfig.SessionRateLimit
+// perguntas por minuto da sessão. Falhas ao consultar o contador não
+// bloqueiam a pergunta e são apenas registradas.
+func (s *RAGServiceImpl) checkSessionLimit(ctx context.Context, sess *domain.Session) error {
+	if sess == nil || s.config.SessionRateLimit <= 0 {
+		return nil
+	}
+
+	count, err := s.sessions.Increment(ctx, sess.ID, sessionQueriesCounter, sessionRateWindow)
+	if err != nil {
+		log.Printf("Aviso ao verificar limite de perguntas da sessão %s: %v", sess.ID, err)
+		return nil
+	}
+	if count > int64(s.config.SessionRateLimit) {
+		return &domain.RateLimitError{RetryAfter: sessionRateWindow}
+	}
+	return nil
+}
+
+// saveSession guarda a conversa em uso, o usuário e as preferências da
+// pergunta respondida, para as próximas perguntas da sessão. Perguntas sem
+// sessão criam uma com o ID da conversa iniciada. Falhas ao salvar não
+// impedem a resposta e são apenas registradas.
+func (s *RAGServiceImpl) saveSession(ctx context.Context, sess *domain.Session, req domain.RAGRequest, conv *domain.Conversation) {
+	if s.sessions == nil {
+		return
+	}
+	if sess == nil {
+		if conv == nil {
+			return
+		}
+		sess = &domain.Session{ID: conv.SessionID}
+	}
+
+	if conv != nil {
+		sess.ConversationID = conv.SessionID
+	}
+	if req.UserID != "" {
+		sess.UserID = req.UserID
+	}
+	sess.Preferences = domain.SessionPreferences{Language: req.Language, Style: req.Style, Model: req.Model}
+	// "auto" volta a detectar o idioma de cada pergunta
+	if strings.EqualFold(sess.Preferences.Language, language.Auto) {
+		sess.Preferences.Language = ""
+	}
+	if err := s.sessions.Save(ctx, sess); err != nil {
+		log.Printf("Aviso ao salvar a sessão %s: %v", sess.ID, err)
+	}
+}
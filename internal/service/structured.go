@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/llmjson"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// structuredAttempts é o número de chamadas feitas para obter uma resposta
+// estruturada válida; a partir da segunda, o LLM recebe o erro de validação
+const structuredAttempts = 2
+
+// responseSchemaURL identifica o schema da pergunta no compilador
+const responseSchemaURL = "mem:///response_schema.json"
+
+// compileResponseSchema valida o schema pedido em RAGRequest.ResponseSchema.
+// A raiz deve descrever um objeto, como exigem os provedores, e referências
+// a schemas externos não são carregadas.
+func compileResponseSchema(schema map[string]any) (*jsonschema.Schema, error) {
+	if schema["type"] != "object" {
+		return nil, &domain.ValidationError{Field: "response_schema", Message: `a raiz deve ter "type": "object"`}
+	}
+
+	doc, err := toJSONValue(schema)
+	if err != nil {
+		return nil, &domain.ValidationError{Field: "response_schema", Message: err.Error()}
+	}
+	compiler := jsonschema.NewCompiler()
+	compiler.UseLoader(jsonschema.SchemeURLLoader{})
+	if err := compiler.AddResource(responseSchemaURL, doc); err != nil {
+		return nil, &domain.ValidationError{Field: "response_schema", Message: err.Error()}
+	}
+	compiled, err := compiler.Compile(responseSchemaURL)
+	if err != nil {
+		return nil, &domain.ValidationError{Field: "response_schema", Message: err.Error()}
+	}
+	return compiled, nil
+}
+
+// structure pede ao LLM a resposta no formato do schema e a valida. Quando a
+// validação falha, tenta de novo informando o erro; se nenhuma tentativa for
+// válida, falha com domain.ErrStructuredAnswer.
+func (s *RAGServiceImpl) structure(ctx context.Context, query string, resp *domain.RAGResponse, schema map[string]any, compiled *jsonschema.Schema) (json.RawMessage, error) {
+	encoded, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]any{"Query": query, "Answer": resp.Answer, "Schema": string(encoded)}
+	ctx = domain.WithResponseSchema(ctx, schema)
+
+	var invalid error
+	for range structuredAttempts {
+		prompt, err := s.prompts.Render(prompts.Structured, data)
+		if err != nil {
+			return nil, err
+		}
+		msg, err := s.llm.GenerateResponse(ctx, []domain.Message{{Role: domain.RoleUser, Content: prompt}}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao gerar a resposta estruturada: %w", err)
+		}
+
+		answer := llmjson.Extract(msg.Content)
+		if invalid = validateJSON(answer, compiled); invalid == nil {
+			return json.RawMessage(answer), nil
+		}
+		data["Previous"] = answer
+		data["Error"] = invalid.Error()
+	}
+	return nil, fmt.Errorf("%w: %v", domain.ErrStructuredAnswer, invalid)
+}
+
+// validateJSON verifica se o texto é um JSON válido para o schema
+func validateJSON(text string, compiled *jsonschema.Schema) error {
+	value, err := jsonschema.UnmarshalJSON(strings.NewReader(text))
+	if err != nil {
+		return fmt.Errorf("JSON inválido: %w", err)
+	}
+	return compiled.Validate(value)
+}
+
+// toJSONValue converte o schema para os tipos esperados pelo validador
+func toJSONValue(v any) (any, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(encoded))
+}
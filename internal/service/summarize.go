@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/prompts"
+)
+
+// minSummaryTokens é o menor resumo pedido por fonte; com menos que isso o
+// resumo perde o conteúdo, e é melhor deixar o orçamento cortar as últimas
+const minSummaryTokens = 32
+
+// sourceSummariesKey é a chave, no contexto, do registro dos resumos da pergunta
+type sourceSummariesKey struct{}
+
+// sourceSummaries registra se alguma busca da pergunta entregou ao agente
+// fontes resumidas
+type sourceSummaries struct {
+	used atomic.Bool
+}
+
+// withSourceSummaries guarda no contexto o registro dos resumos da pergunta
+func withSourceSummaries(ctx context.Context) (context.Context, *sourceSummaries) {
+	summaries := &sourceSummaries{}
+	return context.WithValue(ctx, sourceSummariesKey{}, summaries), summaries
+}
+
+// documentTokens conta os tokens que o documento ocupa no resultado da busca
+func (s *RAGServiceImpl) documentTokens(doc domain.Document) int {
+	data, _ := json.Marshal(doc)
+	return s.tokenizer.Count(string(data))
+}
+
+// summarizeSources resume, com o modelo de RAGConfig.SummaryModel, o conteúdo
+// das fontes que não cabem no orçamento de contexto da busca, para que o
+// agente receba todas elas em vez de apenas as primeiras. Cada fonte recebe
+// uma parte igual do orçamento, e as que já cabem na sua parte são mantidas.
+// Os resumos são focados em query e feitos em paralelo; falhas mantêm o
+// conteúdo original, que fitDocuments corta depois se preciso.
+func (s *RAGServiceImpl) summarizeSources(ctx context.Context, query string, docs []domain.Document) []domain.Document {
+	budget, ok := ctx.Value(documentBudgetKey{}).(int)
+	if !ok || len(docs) == 0 {
+		return docs
+	}
+
+	tokens := make([]int, len(docs))
+	total := 0
+	for i, doc := range docs {
+		tokens[i] = s.documentTokens(doc)
+		total += tokens[i]
+	}
+	if total <= budget {
+		return docs
+	}
+
+	share := budget / len(docs)
+	summarized := make([]domain.Document, len(docs))
+	copy(summarized, docs)
+	var wg sync.WaitGroup
+	var count atomic.Int32
+	for i, doc := range docs {
+		if tokens[i] <= share {
+			continue
+		}
+		// O resumo substitui apenas o conteúdo; o restante do documento
+		// continua ocupando o mesmo espaço
+		overhead := tokens[i] - s.tokenizer.Count(doc.Content)
+		limit := max(share-overhead, minSummaryTokens)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			summary, err := s.summarizeSource(ctx, query, doc, limit)
+			if err != nil {
+				log.Printf("Aviso ao resumir a fonte %q, mantendo o conteúdo original: %v", doc.Title, err)
+				return
+			}
+			summarized[i].Content = summary
+			count.Add(1)
+		}()
+	}
+	wg.Wait()
+
+	if count.Load() == 0 {
+		return docs
+	}
+	log.Printf("Orçamento de contexto: resumidas %d de %d fontes (%d tokens, %d disponíveis)", count.Load(), len(docs), total, budget)
+	if summaries, ok := ctx.Value(sourceSummariesKey{}).(*sourceSummaries); ok {
+		summaries.used.Store(true)
+	}
+	return summarized
+}
+
+// summarizeSource pede ao LLM um resumo do documento com até limit tokens
+func (s *RAGServiceImpl) summarizeSource(ctx context.Context, query string, doc domain.Document, limit int) (string, error) {
+	prompt, err := s.prompts.Render(prompts.Summary, map[string]any{
+		// Cerca de três quartos de palavra por token
+		"Words":   max(limit*3/4, 1),
+		"Query":   query,
+		"Title":   doc.Title,
+		"Content": doc.Content,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	ctx = domain.WithGeneration(ctx, domain.Generation{Model: s.config.SummaryModel, MaxTokens: limit})
+	msg, err := s.llm.GenerateResponse(ctx, []domain.Message{{Role: domain.RoleUser, Content: prompt}}, nil)
+	if err != nil {
+		return "", err
+	}
+	summary := strings.TrimSpace(msg.Content)
+	if summary == "" {
+		return "", fmt.Errorf("resumo vazio")
+	}
+	return summary, nil
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer cria os spans das etapas do fluxo do agente
+var tracer = otel.Tracer("github.com/alextavella/agentic-rag/internal/service")
+
+// endSpan registra o erro no span, quando houver, e o encerra
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
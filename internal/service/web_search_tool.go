@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// webSearchToolName é o nome da ferramenta de busca na web exposta ao agente
+const webSearchToolName = "web_search"
+
+// CreateWebSearchTool define a ferramenta de busca na web, reservada às
+// perguntas que a base não responde
+func CreateWebSearchTool() domain.Tool {
+	return domain.Tool{
+		Name: webSearchToolName,
+		Description: "Search the public web. Use it only when " + searchToolName +
+			" returns no relevant documents; results are external to the knowledge base",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "Text to search on the web",
+				},
+			},
+			"required": []string{"query"},
+		},
+	}
+}
+
+// webSearchTool retorna o nome da ferramenta de busca na web, ou vazio se
+// ela não estiver registrada
+func (s *RAGServiceImpl) webSearchTool() string {
+	if !s.tools.Has(webSearchToolName) {
+		return ""
+	}
+	return webSearchToolName
+}
+
+// handleWebSearch executa a ferramenta de busca na web. As páginas
+// encontradas são devolvidas ao agente como documentos com a origem web nos
+// metadados, numerados junto com os da base, e marcadas como externas nas
+// fontes da resposta.
+func (s *RAGServiceImpl) handleWebSearch(ctx context.Context, arguments string) (*domain.ToolResult, error) {
+	var args struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return nil, fmt.Errorf("erro ao processar argumentos: %w", err)
+	}
+
+	results, err := s.webSearcher.Search(ctx, args.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]domain.Document, 0, len(results))
+	for _, r := range results {
+		docs = append(docs, domain.Document{
+			Title:    r.Title,
+			Content:  r.Snippet,
+			Link:     r.URL,
+			Metadata: map[string]string{domain.MetadataOrigin: domain.OriginWeb},
+		})
+	}
+	// Descarta os menos relevantes que não cabem no orçamento de contexto
+	docs = s.fitDocuments(ctx, docs)
+	if len(docs) == 0 {
+		return &domain.ToolResult{Content: "[]"}, nil
+	}
+
+	content, err := json.Marshal(citeDocuments(ctx, docs))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao converter para JSON: %w", err)
+	}
+	return &domain.ToolResult{Content: string(content), Sources: docs}, nil
+}
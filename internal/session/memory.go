@@ -0,0 +1,134 @@
+package session
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// sweepInterval é o intervalo mínimo entre as limpezas das sessões expiradas
+const sweepInterval = time.Minute
+
+// MemoryStore implementa domain.SessionStore na memória do processo. Com
+// várias instâncias, cada uma tem as próprias sessões; use o RedisStore.
+type MemoryStore struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	sessions  map[string]memorySession
+	counters  map[string]memoryCounter
+	lastSweep time.Time
+}
+
+// memorySession é uma sessão guardada, com o instante em que expira
+type memorySession struct {
+	session   domain.Session
+	expiresAt time.Time
+}
+
+// memoryCounter é um contador de sessão, com o fim da sua janela
+type memoryCounter struct {
+	value     int64
+	expiresAt time.Time
+}
+
+// NewMemoryStore cria um armazenamento em memória que descarta as sessões
+// sem uso há mais de ttl
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		ttl:       ttl,
+		sessions:  make(map[string]memorySession),
+		counters:  make(map[string]memoryCounter),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get busca o estado da sessão
+func (m *MemoryStore) Get(ctx context.Context, id string) (*domain.Session, error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(now)
+
+	entry, ok := m.sessions[sessionKey(ctx, id)]
+	if !ok || !now.Before(entry.expiresAt) {
+		return nil, domain.ErrSessionNotFound
+	}
+	session := entry.session
+	return &session, nil
+}
+
+// Save cria ou substitui o estado da sessão, renovando a expiração
+func (m *MemoryStore) Save(ctx context.Context, session *domain.Session) error {
+	now := time.Now()
+	session.UpdatedAt = now
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(now)
+
+	m.sessions[sessionKey(ctx, session.ID)] = memorySession{session: *session, expiresAt: now.Add(m.ttl)}
+	return nil
+}
+
+// Delete remove o estado e os contadores da sessão
+func (m *MemoryStore) Delete(ctx context.Context, id string) error {
+	key := sessionKey(ctx, id)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, key)
+	for counter := range m.counters {
+		if strings.HasPrefix(counter, key+":") {
+			delete(m.counters, counter)
+		}
+	}
+	return nil
+}
+
+// Increment soma um ao contador da sessão na janela atual
+func (m *MemoryStore) Increment(ctx context.Context, id, counter string, window time.Duration) (int64, error) {
+	now := time.Now()
+	key := sessionKey(ctx, id) + ":" + counter
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(now)
+
+	c, ok := m.counters[key]
+	if !ok || !now.Before(c.expiresAt) {
+		c = memoryCounter{expiresAt: now.Add(window)}
+	}
+	c.value++
+	m.counters[key] = c
+	return c.value, nil
+}
+
+// Close não faz nada: o armazenamento não mantém conexões
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+// sweep descarta as sessões e os contadores expirados, para que sessões
+// abandonadas não ocupem memória
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, entry := range m.sessions {
+		if !now.Before(entry.expiresAt) {
+			delete(m.sessions, key)
+		}
+	}
+	for key, c := range m.counters {
+		if !now.Before(c.expiresAt) {
+			delete(m.counters, key)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix é o prefixo das chaves das sessões no Redis
+const keyPrefix = "rag:session:"
+
+// globEscaper escapa os caracteres especiais dos padrões do SCAN
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// increment soma um ao contador e define a expiração no primeiro incremento,
+// de forma atômica, para que a janela comece no primeiro uso
+var increment = redis.NewScript(`
+local value = redis.call('INCR', KEYS[1])
+if value == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return value
+`)
+
+// RedisStore implementa domain.SessionStore no Redis, compartilhando as
+// sessões entre todas as instâncias da aplicação. Cada sessão é um JSON que
+// expira depois do TTL sem ser salvo; os contadores ficam em chaves próprias.
+type RedisStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisStore conecta ao Redis e cria o armazenamento das sessões
+func NewRedisStore(ctx context.Context, url string, ttl time.Duration) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar REDIS_URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("erro ao conectar ao Redis: %w", err)
+	}
+
+	return &RedisStore{client: client, ttl: ttl}, nil
+}
+
+// Get busca o estado da sessão
+func (r *RedisStore) Get(ctx context.Context, id string) (*domain.Session, error) {
+	data, err := r.client.Get(ctx, keyPrefix+sessionKey(ctx, id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, domain.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar sessão: %w", err)
+	}
+
+	var session domain.Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar sessão: %w", err)
+	}
+	return &session, nil
+}
+
+// Save cria ou substitui o estado da sessão, renovando a expiração
+func (r *RedisStore) Save(ctx context.Context, session *domain.Session) error {
+	session.UpdatedAt = time.Now()
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar sessão: %w", err)
+	}
+	if err := r.client.Set(ctx, keyPrefix+sessionKey(ctx, session.ID), data, r.ttl).Err(); err != nil {
+		return fmt.Errorf("erro ao salvar sessão: %w", err)
+	}
+	return nil
+}
+
+// Delete remove o estado e os contadores da sessão
+func (r *RedisStore) Delete(ctx context.Context, id string) error {
+	key := keyPrefix + sessionKey(ctx, id)
+	keys := []string{key}
+	iter := r.client.Scan(ctx, 0, globEscaper.Replace(key)+":*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("erro ao buscar contadores da sessão: %w", err)
+	}
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("erro ao remover sessão: %w", err)
+	}
+	return nil
+}
+
+// Increment soma um ao contador da sessão na janela atual
+func (r *RedisStore) Increment(ctx context.Context, id, counter string, window time.Duration) (int64, error) {
+	key := keyPrefix + sessionKey(ctx, id) + ":" + counter
+	value, err := increment.Run(ctx, r.client, []string{key}, window.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao incrementar contador da sessão: %w", err)
+	}
+	return value, nil
+}
+
+// Close fecha a conexão com o Redis
+func (r *RedisStore) Close() error {
+	return r.client.Close()
+}
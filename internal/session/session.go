@@ -0,0 +1,77 @@
+// Package session guarda o estado das sessões (conversa em uso, preferências
+// de resposta e contadores) na memória do processo ou no Redis, que o
+// compartilha entre as instâncias atrás de um balanceador de carga.
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Armazenamentos disponíveis para as sessões
+const (
+	BackendMemory = "memory" // Sessões na memória do processo
+	BackendRedis  = "redis"  // Sessões no Redis, compartilhadas entre instâncias
+)
+
+// DefaultTTL é o tempo padrão que uma sessão sem uso é mantida
+const DefaultTTL = 24 * time.Hour
+
+// Config contém as configurações do armazenamento das sessões
+type Config struct {
+	// Backend é "memory", "redis" ou vazio para desabilitar as sessões
+	Backend string
+	// RedisURL é o endereço do Redis usado com o backend "redis"
+	RedisURL string
+	// TTL é o tempo que uma sessão sem uso é mantida (padrão: 24h)
+	TTL time.Duration
+}
+
+// ConfigFromEnv lê a configuração das sessões a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Backend:  os.Getenv("SESSION_STORE"),
+		RedisURL: os.Getenv("REDIS_URL"),
+	}
+	cfg.TTL, _ = time.ParseDuration(os.Getenv("SESSION_TTL"))
+	return cfg
+}
+
+// Store é um domain.SessionStore que mantém recursos abertos
+type Store interface {
+	domain.SessionStore
+	// Close libera as conexões usadas pelo armazenamento
+	Close() error
+}
+
+// New cria o armazenamento configurado. Retorna nil quando as sessões estão
+// desabilitadas.
+func New(ctx context.Context, cfg Config) (Store, error) {
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	switch cfg.Backend {
+	case "":
+		return nil, nil
+	case BackendMemory:
+		return NewMemoryStore(ttl), nil
+	case BackendRedis:
+		if cfg.RedisURL == "" {
+			return nil, fmt.Errorf("SESSION_STORE=redis requer REDIS_URL")
+		}
+		return NewRedisStore(ctx, cfg.RedisURL, ttl)
+	default:
+		return nil, fmt.Errorf("armazenamento de sessões desconhecido: %q (use memory ou redis)", cfg.Backend)
+	}
+}
+
+// sessionKey identifica a sessão no tenant do contexto
+func sessionKey(ctx context.Context, id string) string {
+	return "t=" + domain.TenantFromContext(ctx) + ":" + id
+}
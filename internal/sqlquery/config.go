@@ -0,0 +1,99 @@
+package sqlquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Valores padrão da ferramenta de SQL
+const (
+	defaultMaxRows = 100
+	defaultTimeout = 5 * time.Second
+)
+
+// Database é um banco PostgreSQL que o agente pode consultar
+type Database struct {
+	Name        string   `json:"name"`        // Nome usado pelo agente para escolher o banco
+	URL         string   `json:"url"`         // String de conexão; use um usuário apenas com SELECT nas tabelas
+	Description string   `json:"description"` // O que o banco guarda, mostrado ao agente
+	Tables      []string `json:"tables"`      // Tabelas permitidas, como "public.orders" ou "orders" (schema public)
+	// Role é o papel assumido em cada consulta (SET LOCAL ROLE), para que o
+	// próprio banco restrinja as tabelas quando o usuário da url tem mais
+	// permissões. Opcional.
+	Role string `json:"role,omitempty"`
+}
+
+// Config contém as configurações da ferramenta de SQL
+type Config struct {
+	Databases []Database
+	MaxRows   int           // Linhas devolvidas por consulta (padrão: 100)
+	Timeout   time.Duration // Tempo máximo de cada consulta (padrão: 5s)
+}
+
+// ConfigFromEnv lê a configuração da ferramenta a partir das variáveis de
+// ambiente. Os bancos vêm do arquivo JSON em SQL_TOOL_DATABASES_FILE; sem
+// ele, a ferramenta fica desabilitada.
+func ConfigFromEnv() (Config, error) {
+	maxRows, _ := strconv.Atoi(os.Getenv("SQL_TOOL_MAX_ROWS"))
+	timeout, _ := time.ParseDuration(os.Getenv("SQL_TOOL_TIMEOUT"))
+	cfg := Config{MaxRows: maxRows, Timeout: timeout}
+
+	path := os.Getenv("SQL_TOOL_DATABASES_FILE")
+	if path == "" {
+		return cfg, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("erro ao ler bancos da ferramenta de SQL: %w", err)
+	}
+	if err := json.Unmarshal(raw, &cfg.Databases); err != nil {
+		return cfg, fmt.Errorf("erro ao interpretar bancos da ferramenta de SQL: %w", err)
+	}
+	return cfg, nil
+}
+
+// validate verifica os bancos e normaliza os nomes das tabelas para
+// schema.tabela, em minúsculas
+func (c *Config) validate() error {
+	seen := map[string]bool{}
+	for i := range c.Databases {
+		db := &c.Databases[i]
+		if db.Name == "" || db.URL == "" {
+			return fmt.Errorf("banco %d sem nome ou url", i+1)
+		}
+		if seen[db.Name] {
+			return fmt.Errorf("banco %q repetido", db.Name)
+		}
+		seen[db.Name] = true
+		if len(db.Tables) == 0 {
+			return fmt.Errorf("banco %q sem tabelas permitidas", db.Name)
+		}
+		for j, table := range db.Tables {
+			db.Tables[j] = qualifiedName("", table)
+		}
+	}
+	if c.MaxRows <= 0 {
+		c.MaxRows = defaultMaxRows
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	return nil
+}
+
+// qualifiedName retorna o nome da tabela no formato schema.tabela, em
+// minúsculas; sem schema, usa o informado ou, se vazio, public
+func qualifiedName(schema, table string) string {
+	table = strings.ToLower(strings.TrimSpace(table))
+	if strings.Contains(table, ".") {
+		return table
+	}
+	if schema == "" {
+		schema = "public"
+	}
+	return strings.ToLower(schema) + "." + table
+}
@@ -0,0 +1,393 @@
+// Package sqlquery oferece ao agente uma ferramenta que consulta bancos
+// PostgreSQL configurados, para perguntas que combinam os documentos com
+// dados atualizados. As consultas rodam em transações somente leitura, com
+// limite de tempo e de linhas, e só podem ler as tabelas permitidas.
+//
+// A verificação do plano recusa tabelas fora da lista e as funções que leem
+// dados por conta própria, mas não enxerga o corpo de funções criadas no
+// banco. A garantia completa vem das permissões: conecte com um usuário (ou
+// informe em Database.Role um papel) que tenha apenas SELECT nas tabelas
+// permitidas.
+package sqlquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ToolName é o nome da ferramenta de SQL exposta ao agente
+const ToolName = "sql_query"
+
+// database é um banco configurado, com a conexão e o esquema das tabelas
+type database struct {
+	Database
+	pool   *pgxpool.Pool
+	schema string // Colunas das tabelas permitidas, mostradas ao agente
+}
+
+// Tool executa as consultas do agente nos bancos configurados
+type Tool struct {
+	databases map[string]*database
+	order     []string // Ordem da configuração, usada na descrição da ferramenta
+	maxRows   int
+	timeout   time.Duration
+}
+
+// New conecta aos bancos configurados e lê as colunas das tabelas
+// permitidas. Retorna nil quando não há bancos configurados.
+func New(ctx context.Context, cfg Config) (*Tool, error) {
+	if len(cfg.Databases) == 0 {
+		return nil, nil
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	t := &Tool{databases: map[string]*database{}, maxRows: cfg.MaxRows, timeout: cfg.Timeout}
+	for _, db := range cfg.Databases {
+		pool, err := pgxpool.New(ctx, db.URL)
+		if err != nil {
+			t.Close()
+			return nil, fmt.Errorf("erro ao conectar ao banco %q: %w", db.Name, err)
+		}
+		entry := &database{Database: db, pool: pool}
+		t.databases[db.Name] = entry
+		t.order = append(t.order, db.Name)
+
+		if entry.schema, err = describeTables(ctx, pool, db.Tables); err != nil {
+			t.Close()
+			return nil, fmt.Errorf("erro ao ler as tabelas do banco %q: %w", db.Name, err)
+		}
+		if err := t.warnExtraTables(ctx, entry); err != nil {
+			t.Close()
+			return nil, fmt.Errorf("erro ao verificar as permissões no banco %q: %w", db.Name, err)
+		}
+	}
+	return t, nil
+}
+
+// warnExtraTables registra no log as tabelas fora da lista que o usuário (ou
+// o papel configurado) pode ler. Nesse caso, a lista depende apenas da
+// verificação do plano, que não alcança o corpo das funções do banco.
+func (t *Tool) warnExtraTables(ctx context.Context, db *database) error {
+	tx, err := t.begin(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(context.Background())
+
+	rows, err := tx.Query(ctx, `
+		SELECT lower(n.nspname || '.' || c.relname)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'v', 'm', 'p', 'f')
+			AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+			AND n.nspname NOT LIKE 'pg_toast%'
+			AND has_table_privilege(c.oid, 'SELECT')
+			AND NOT lower(n.nspname || '.' || c.relname) = ANY($1)
+		ORDER BY 1
+		LIMIT 5`, db.Tables)
+	if err != nil {
+		return err
+	}
+	extra, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+	if len(extra) > 0 {
+		log.Printf("Aviso: o usuário do banco %q da ferramenta de SQL pode ler tabelas fora da lista (%s); "+
+			"use um usuário ou papel apenas com SELECT nas tabelas permitidas", db.Name, strings.Join(extra, ", "))
+	}
+	return nil
+}
+
+// begin inicia uma transação somente leitura, descartada pelo chamador, no
+// papel configurado para o banco
+func (t *Tool) begin(ctx context.Context, db *database) (pgx.Tx, error) {
+	tx, err := db.pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	if db.Role != "" {
+		if _, err := tx.Exec(ctx, "SET LOCAL ROLE "+pgx.Identifier{db.Role}.Sanitize()); err != nil {
+			tx.Rollback(context.Background())
+			return nil, fmt.Errorf("erro ao assumir o papel %q: %w", db.Role, err)
+		}
+	}
+	return tx, nil
+}
+
+// Close encerra as conexões com os bancos
+func (t *Tool) Close() {
+	for _, db := range t.databases {
+		db.pool.Close()
+	}
+}
+
+// Definition define a ferramenta, com os bancos e as colunas das tabelas
+// que o agente pode consultar
+func (t *Tool) Definition() domain.Tool {
+	var description strings.Builder
+	description.WriteString("Run a read-only PostgreSQL SELECT query against a live database and return the rows. " +
+		"Use it for current figures that the documents do not have. Only the tables below can be read; " +
+		fmt.Sprintf("at most %d rows are returned, so aggregate or filter in the query.\n", t.maxRows))
+	for _, name := range t.order {
+		db := t.databases[name]
+		fmt.Fprintf(&description, "Database %s", name)
+		if db.Description != "" {
+			fmt.Fprintf(&description, " (%s)", db.Description)
+		}
+		description.WriteString(":\n")
+		description.WriteString(db.schema)
+	}
+
+	return domain.Tool{
+		Name:        ToolName,
+		Description: description.String(),
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"database": map[string]any{
+					"type":        "string",
+					"enum":        t.order,
+					"description": "Database to query",
+				},
+				"query": map[string]any{
+					"type":        "string",
+					"description": "A single SELECT statement",
+				},
+			},
+			"required": []string{"database", "query"},
+		},
+	}
+}
+
+// queryResult é o resultado devolvido ao agente
+type queryResult struct {
+	Columns   []string `json:"columns"`
+	Rows      [][]any  `json:"rows"`
+	Truncated bool     `json:"truncated,omitempty"` // Havia mais linhas que o limite
+}
+
+// Handle executa a consulta pedida pelo agente e devolve as linhas em JSON
+func (t *Tool) Handle(ctx context.Context, arguments string) (*domain.ToolResult, error) {
+	var args struct {
+		Database string `json:"database"`
+		Query    string `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return nil, fmt.Errorf("erro ao processar argumentos: %w", err)
+	}
+	db, ok := t.databases[args.Database]
+	if !ok {
+		return nil, fmt.Errorf("banco desconhecido: %q", args.Database)
+	}
+
+	result, err := t.query(ctx, db, args.Query)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao converter para JSON: %w", err)
+	}
+	return &domain.ToolResult{Content: string(content)}, nil
+}
+
+// query executa a consulta em uma transação somente leitura, descartada ao
+// final, depois de verificar no plano que ela só lê tabelas permitidas
+func (t *Tool) query(ctx context.Context, db *database, query string) (*queryResult, error) {
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	if !isSelect(query) {
+		return nil, fmt.Errorf("apenas consultas SELECT são permitidas")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	tx, err := t.begin(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+
+	// O limite também vale no servidor, caso a conexão não seja interrompida
+	if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", t.timeout.Milliseconds())); err != nil {
+		return nil, fmt.Errorf("erro ao configurar timeout: %w", err)
+	}
+	if err := checkTables(ctx, tx, query, db.Tables); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro na consulta: %w", err)
+	}
+	defer rows.Close()
+
+	result := &queryResult{Rows: [][]any{}}
+	for _, field := range rows.FieldDescriptions() {
+		result.Columns = append(result.Columns, field.Name)
+	}
+	for rows.Next() {
+		if len(result.Rows) == t.maxRows {
+			result.Truncated = true
+			break
+		}
+		values, err := rows.Values()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler linha: %w", err)
+		}
+		for i, value := range values {
+			values[i] = jsonValue(value)
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro na consulta: %w", err)
+	}
+	return result, nil
+}
+
+// isSelect indica se o texto começa por SELECT ou WITH. Outros comandos, e
+// mais de um comando no mesmo texto, falhariam na transação somente leitura
+// e no protocolo usado pelo pgx; a verificação antecipa um erro mais claro ao
+// agente.
+func isSelect(query string) bool {
+	fields := strings.Fields(strings.ToLower(query))
+	if len(fields) == 0 {
+		return false
+	}
+	return fields[0] == "select" || fields[0] == "with"
+}
+
+// deniedFunctions são as funções que leem tabelas, arquivos ou outros bancos
+// por conta própria, fora dos nós do plano: as que executam SQL recebido em
+// texto (query_to_xml, ts_stat...), as de arquivos do servidor, os large
+// objects e o dblink
+var deniedFunctions = regexp.MustCompile(`(?i)\b(\w*_to_xml\w*|ts_stat|ts_rewrite|dblink\w*|lo_\w+|pg_read_\w+|pg_ls_\w+|pg_stat_file|pg_file_\w+)\s*\(`)
+
+// checkTables obtém o plano da consulta e verifica se todas as tabelas lidas
+// estão entre as permitidas. Views aparecem no plano como as tabelas de
+// origem, que precisam estar na lista. Funções no FROM e chamadas a
+// deniedFunctions, em qualquer expressão do plano, são recusadas.
+func checkTables(ctx context.Context, tx pgx.Tx, query string, allowed []string) error {
+	var raw []byte
+	if err := tx.QueryRow(ctx, "EXPLAIN (VERBOSE, FORMAT JSON) "+query).Scan(&raw); err != nil {
+		return fmt.Errorf("erro na consulta: %w", err)
+	}
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		return fmt.Errorf("erro ao interpretar plano da consulta: %w", err)
+	}
+
+	for _, p := range plans {
+		if node := p.Plan.functionScan(); node != "" {
+			return fmt.Errorf("funções no FROM não são permitidas: %s", node)
+		}
+		for _, table := range p.Plan.relations() {
+			if !slices.Contains(allowed, table) {
+				return fmt.Errorf("tabela não permitida: %s", table)
+			}
+		}
+	}
+	// No modo VERBOSE, as expressões de cada nó vêm já interpretadas, sem
+	// comentários nem aspas em volta dos nomes das funções
+	if name := deniedFunctions.FindStringSubmatch(string(raw)); name != nil {
+		return fmt.Errorf("função não permitida: %s", name[1])
+	}
+	return nil
+}
+
+// planNode é um nó do plano de execução em JSON
+type planNode struct {
+	NodeType string     `json:"Node Type"`
+	Relation string     `json:"Relation Name"`
+	Schema   string     `json:"Schema"`
+	Plans    []planNode `json:"Plans"`
+}
+
+// relations retorna as tabelas lidas pelo nó e pelos seus filhos
+func (n planNode) relations() []string {
+	var tables []string
+	if n.Relation != "" {
+		tables = append(tables, qualifiedName(n.Schema, n.Relation))
+	}
+	for _, child := range n.Plans {
+		tables = append(tables, child.relations()...)
+	}
+	return tables
+}
+
+// functionScan retorna o tipo do primeiro nó, entre este e os seus filhos,
+// que lê as linhas de uma função, ou vazio quando não há nenhum
+func (n planNode) functionScan() string {
+	if n.NodeType == "Function Scan" || n.NodeType == "Table Function Scan" {
+		return n.NodeType
+	}
+	for _, child := range n.Plans {
+		if node := child.functionScan(); node != "" {
+			return node
+		}
+	}
+	return ""
+}
+
+// describeTables lê as colunas das tabelas, no formato
+// "- schema.tabela(coluna tipo, ...)", uma por linha
+func describeTables(ctx context.Context, pool *pgxpool.Pool, tables []string) (string, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT lower(table_schema || '.' || table_name), column_name, data_type
+		FROM information_schema.columns
+		WHERE lower(table_schema || '.' || table_name) = ANY($1)
+		ORDER BY table_schema, table_name, ordinal_position`, tables)
+	if err != nil {
+		return "", err
+	}
+	columns, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) ([3]string, error) {
+		var c [3]string
+		err := row.Scan(&c[0], &c[1], &c[2])
+		return c, err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	byTable := map[string][]string{}
+	for _, c := range columns {
+		byTable[c[0]] = append(byTable[c[0]], c[1]+" "+c[2])
+	}
+	var schema strings.Builder
+	for _, table := range tables {
+		cols, ok := byTable[table]
+		if !ok {
+			return "", fmt.Errorf("tabela %s não encontrada", table)
+		}
+		fmt.Fprintf(&schema, "- %s(%s)\n", table, strings.Join(cols, ", "))
+	}
+	return schema.String(), nil
+}
+
+// jsonValue converte os valores sem representação JSON adequada, como UUIDs
+// lidos em arrays de bytes
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case [16]byte:
+		return fmt.Sprintf("%x-%x-%x-%x-%x", v[0:4], v[4:6], v[6:8], v[8:10], v[10:16])
+	case []byte:
+		return string(v)
+	}
+	return value
+}
@@ -0,0 +1,132 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/ingest"
+	"github.com/alextavella/agentic-rag/internal/loader"
+)
+
+// Espera entre as tentativas de gravar um lote que falhou
+const (
+	initialRetryBackoff = time.Second
+	maxRetryBackoff     = time.Minute
+)
+
+// Consumer lê as mensagens da fonte em lotes e grava os documentos com o
+// ingester. As mensagens só são confirmadas depois que o lote é gravado,
+// então cada documento é gravado pelo menos uma vez: um lote interrompido é
+// entregue de novo e os documentos já gravados são ignorados pelo hash de
+// conteúdo.
+type Consumer struct {
+	source        Source
+	ingester      *ingest.Ingester // Deve retornar as falhas de gravação (ingest.WithStrictBatches)
+	batchSize     int
+	flushInterval time.Duration
+}
+
+// NewConsumer cria o consumidor com o tamanho de lote e a espera de cfg
+func NewConsumer(source Source, ingester *ingest.Ingester, cfg Config) *Consumer {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	return &Consumer{source: source, ingester: ingester, batchSize: cfg.BatchSize, flushInterval: cfg.FlushInterval}
+}
+
+// Run consome as mensagens até o cancelamento do contexto. Os documentos vão
+// para o tenant do campo tenant_id da mensagem ou, sem ele, o do contexto.
+// Mensagens inválidas são registradas no log e confirmadas, para não
+// bloquear as seguintes.
+func (c *Consumer) Run(ctx context.Context) error {
+	for {
+		messages, err := c.source.Fetch(ctx, c.batchSize, c.flushInterval)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			continue
+		}
+
+		if err := c.store(ctx, messages); err != nil {
+			// Interrompido antes de gravar: as mensagens serão entregues de novo
+			return nil
+		}
+		if err := c.source.Ack(ctx, messages); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// store grava os documentos das mensagens, separados por tenant, repetindo
+// as gravações que falham até o sucesso ou o cancelamento do contexto
+func (c *Consumer) store(ctx context.Context, messages []Message) error {
+	var tenants []string
+	byTenant := make(map[string][]domain.Document)
+	for _, msg := range messages {
+		doc, err := decodeDocument(msg.Value)
+		if err != nil {
+			log.Printf("Mensagem %s ignorada: %v", msg.ID, err)
+			continue
+		}
+		tenant := doc.TenantID
+		if tenant == "" {
+			tenant = domain.TenantFromContext(ctx)
+		}
+		if _, ok := byTenant[tenant]; !ok {
+			tenants = append(tenants, tenant)
+		}
+		byTenant[tenant] = append(byTenant[tenant], doc)
+	}
+
+	for _, tenant := range tenants {
+		docs := byTenant[tenant]
+		tenantCtx := domain.WithTenant(ctx, tenant)
+		for wait := initialRetryBackoff; ; wait = min(wait*2, maxRetryBackoff) {
+			chunks, err := c.ingester.Ingest(tenantCtx, docs)
+			if err == nil {
+				log.Printf("Lote gravado: %d documentos, %d chunks", len(docs), chunks)
+				break
+			}
+			log.Printf("Erro ao gravar lote de %d documentos, repetindo em %s: %v", len(docs), wait, err)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// decodeDocument lê o documento da mensagem, com os campos e as validações
+// das linhas da importação em JSONL
+func decodeDocument(value []byte) (domain.Document, error) {
+	doc, err := loader.NewJSONLReader(bytes.NewReader(value)).Next()
+	if errors.Is(err, io.EOF) {
+		return doc, errors.New("mensagem vazia")
+	}
+	if err != nil {
+		// O número de linha não faz sentido em uma mensagem
+		var recordErr *loader.RecordError
+		if errors.As(err, &recordErr) {
+			err = recordErr.Err
+		}
+		return doc, err
+	}
+	return doc, domain.ValidateTenantID(doc.TenantID)
+}
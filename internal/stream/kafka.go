@@ -0,0 +1,78 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaSource lê um tópico do Kafka em um grupo de consumidores. As
+// posições são gravadas no grupo apenas em Ack; um grupo novo começa pelas
+// mensagens mais antigas do tópico.
+type kafkaSource struct {
+	reader *kafka.Reader
+}
+
+// newKafkaSource cria o leitor do tópico
+func newKafkaSource(cfg Config) *kafkaSource {
+	return &kafkaSource{reader: kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     cfg.KafkaBrokers,
+		Topic:       cfg.KafkaTopic,
+		GroupID:     cfg.KafkaGroupID,
+		StartOffset: kafka.FirstOffset,
+		MaxBytes:    10 << 20,
+	})}
+}
+
+// Fetch implementa Source
+func (s *kafkaSource) Fetch(ctx context.Context, max int, wait time.Duration) ([]Message, error) {
+	// A primeira mensagem pode demorar; as seguintes completam o lote até o prazo
+	first, err := s.reader.FetchMessage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler do Kafka: %w", err)
+	}
+	messages := []Message{kafkaMessage(first)}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	for len(messages) < max {
+		msg, err := s.reader.FetchMessage(fetchCtx)
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler do Kafka: %w", err)
+		}
+		messages = append(messages, kafkaMessage(msg))
+	}
+	return messages, nil
+}
+
+// Ack implementa Source, gravando no grupo a posição das mensagens
+func (s *kafkaSource) Ack(ctx context.Context, messages []Message) error {
+	raw := make([]kafka.Message, len(messages))
+	for i, msg := range messages {
+		raw[i] = msg.raw.(kafka.Message)
+	}
+	if err := s.reader.CommitMessages(ctx, raw...); err != nil {
+		return fmt.Errorf("erro ao confirmar mensagens no Kafka: %w", err)
+	}
+	return nil
+}
+
+// Close implementa Source
+func (s *kafkaSource) Close() error {
+	return s.reader.Close()
+}
+
+// kafkaMessage converte a mensagem do Kafka
+func kafkaMessage(msg kafka.Message) Message {
+	return Message{
+		Value: msg.Value,
+		ID:    fmt.Sprintf("%s/%d@%d", msg.Topic, msg.Partition, msg.Offset),
+		raw:   msg,
+	}
+}
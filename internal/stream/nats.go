@@ -0,0 +1,92 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// natsAckWait é o prazo para confirmar as mensagens entregues, depois do
+// qual o JetStream as entrega de novo; cobre a geração dos embeddings e as
+// novas tentativas de gravação de um lote
+const natsAckWait = 5 * time.Minute
+
+// natsSource lê um subject de um stream do JetStream com um consumidor
+// durável de confirmação explícita. O stream deve existir; o consumidor é
+// criado ou atualizado ao conectar.
+type natsSource struct {
+	conn     *nats.Conn
+	consumer jetstream.Consumer
+}
+
+// newNATSSource conecta ao servidor e prepara o consumidor durável
+func newNATSSource(ctx context.Context, cfg Config) (*natsSource, error) {
+	conn, err := nats.Connect(cfg.NATSURL, nats.Name("agentic-rag"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao NATS: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("erro ao acessar o JetStream: %w", err)
+	}
+	stream, err := js.Stream(ctx, cfg.NATSStream)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("erro ao acessar o stream %s: %w", cfg.NATSStream, err)
+	}
+	consumer, err := stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:       cfg.NATSDurable,
+		FilterSubject: cfg.NATSSubject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       natsAckWait,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("erro ao criar o consumidor %s: %w", cfg.NATSDurable, err)
+	}
+	return &natsSource{conn: conn, consumer: consumer}, nil
+}
+
+// Fetch implementa Source
+func (s *natsSource) Fetch(ctx context.Context, max int, wait time.Duration) ([]Message, error) {
+	// O JetStream não aceita o contexto junto do prazo; o cancelamento é
+	// percebido no fim da espera
+	batch, err := s.consumer.Fetch(max, jetstream.FetchMaxWait(wait))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler do NATS: %w", err)
+	}
+
+	var messages []Message
+	for msg := range batch.Messages() {
+		id := msg.Subject()
+		if meta, err := msg.Metadata(); err == nil {
+			id = fmt.Sprintf("%s@%d", msg.Subject(), meta.Sequence.Stream)
+		}
+		messages = append(messages, Message{Value: msg.Data(), ID: id, raw: msg})
+	}
+	if err := batch.Error(); err != nil && !errors.Is(err, nats.ErrTimeout) {
+		return nil, fmt.Errorf("erro ao ler do NATS: %w", err)
+	}
+	return messages, nil
+}
+
+// Ack implementa Source, confirmando cada mensagem e esperando a resposta do
+// servidor
+func (s *natsSource) Ack(ctx context.Context, messages []Message) error {
+	for _, msg := range messages {
+		if err := msg.raw.(jetstream.Msg).DoubleAck(ctx); err != nil {
+			return fmt.Errorf("erro ao confirmar mensagem %s no NATS: %w", msg.ID, err)
+		}
+	}
+	return nil
+}
+
+// Close implementa Source
+func (s *natsSource) Close() error {
+	return s.conn.Drain()
+}
@@ -0,0 +1,109 @@
+// Package stream consome documentos publicados por outros serviços em um
+// tópico do Kafka ou em um subject do NATS JetStream e os grava na base
+package stream
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Drivers disponíveis
+const (
+	DriverKafka = "kafka"
+	DriverNATS  = "nats"
+)
+
+// Valores padrão do consumidor
+const (
+	DefaultGroup         = "agentic-rag"
+	DefaultBatchSize     = 100
+	DefaultFlushInterval = 5 * time.Second
+)
+
+// Config contém as configurações do consumidor
+type Config struct {
+	Driver string // kafka ou nats; vazio desabilita o consumidor
+
+	KafkaBrokers []string
+	KafkaTopic   string
+	KafkaGroupID string // Grupo de consumidores que guarda as posições lidas (padrão: agentic-rag)
+
+	NATSURL     string
+	NATSStream  string // Stream do JetStream que guarda o subject
+	NATSSubject string
+	NATSDurable string // Consumidor durável que guarda as mensagens confirmadas (padrão: agentic-rag)
+
+	BatchSize     int           // Mensagens gravadas por lote (padrão: 100)
+	FlushInterval time.Duration // Espera máxima para completar um lote (padrão: 5s)
+}
+
+// ConfigFromEnv lê a configuração do consumidor a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Driver:       strings.ToLower(os.Getenv("STREAM_DRIVER")),
+		KafkaTopic:   os.Getenv("KAFKA_TOPIC"),
+		KafkaGroupID: os.Getenv("KAFKA_GROUP_ID"),
+		NATSURL:      os.Getenv("NATS_URL"),
+		NATSStream:   os.Getenv("NATS_STREAM"),
+		NATSSubject:  os.Getenv("NATS_SUBJECT"),
+		NATSDurable:  os.Getenv("NATS_DURABLE"),
+	}
+	for _, broker := range strings.Split(os.Getenv("KAFKA_BROKERS"), ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			cfg.KafkaBrokers = append(cfg.KafkaBrokers, broker)
+		}
+	}
+	cfg.BatchSize, _ = strconv.Atoi(os.Getenv("STREAM_BATCH_SIZE"))
+	cfg.FlushInterval, _ = time.ParseDuration(os.Getenv("STREAM_FLUSH_INTERVAL"))
+	return cfg
+}
+
+// Message é uma mensagem recebida, com o documento em JSON
+type Message struct {
+	Value []byte
+	ID    string // Identifica a mensagem nos logs (partição e offset, ou sequência)
+	raw   any    // Mensagem original, usada na confirmação
+}
+
+// Source entrega as mensagens de um tópico ou subject. As mensagens não
+// confirmadas com Ack são entregues de novo depois de reiniciar o consumidor.
+type Source interface {
+	// Fetch retorna até max mensagens, completando o lote por no máximo
+	// wait; pode retornar uma lista vazia quando não há mensagens no prazo
+	Fetch(ctx context.Context, max int, wait time.Duration) ([]Message, error)
+	// Ack confirma as mensagens processadas
+	Ack(ctx context.Context, messages []Message) error
+	// Close encerra a conexão
+	Close() error
+}
+
+// New conecta à fonte configurada. Retorna nil quando o consumidor está
+// desabilitado.
+func New(ctx context.Context, cfg Config) (Source, error) {
+	switch cfg.Driver {
+	case "":
+		return nil, nil
+	case DriverKafka:
+		if len(cfg.KafkaBrokers) == 0 || cfg.KafkaTopic == "" {
+			return nil, fmt.Errorf("KAFKA_BROKERS e KAFKA_TOPIC são obrigatórias")
+		}
+		if cfg.KafkaGroupID == "" {
+			cfg.KafkaGroupID = DefaultGroup
+		}
+		return newKafkaSource(cfg), nil
+	case DriverNATS:
+		if cfg.NATSURL == "" || cfg.NATSStream == "" || cfg.NATSSubject == "" {
+			return nil, fmt.Errorf("NATS_URL, NATS_STREAM e NATS_SUBJECT são obrigatórias")
+		}
+		if cfg.NATSDurable == "" {
+			cfg.NATSDurable = DefaultGroup
+		}
+		return newNATSSource(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("driver de stream desconhecido: %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,404 @@
+// Package telegram atende um bot do Telegram: as mensagens de cada chat são
+// respondidas pelo agente do RAG, com uma sessão por chat e as citações
+// como links, e os administradores podem indexar páginas com /ingest
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/ingest"
+	"github.com/alextavella/agentic-rag/internal/loader"
+)
+
+// Valores padrão do bot
+const (
+	defaultAPIURL     = "https://api.telegram.org"
+	defaultTimeout    = 2 * time.Minute
+	defaultIngestTime = 5 * time.Minute
+	pollTimeout       = 50 // Segundos de espera de cada getUpdates (long polling)
+	typingInterval    = 4 * time.Second
+	maxMessageSize    = 4096 // Limite de caracteres de uma mensagem do Telegram
+)
+
+// citationPattern reconhece os marcadores de citação da resposta, como [1]
+// ou [1, 3]
+var citationPattern = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// tagPattern reconhece as tags HTML da resposta formatada
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// Config contém as configurações do bot
+type Config struct {
+	Token    string
+	APIURL   string
+	AdminIDs []int64       // Usuários que podem usar /ingest
+	Timeout  time.Duration // Tempo máximo de cada pergunta (padrão: 2m)
+}
+
+// ConfigFromEnv lê a configuração do bot a partir das variáveis de ambiente
+func ConfigFromEnv() (Config, error) {
+	timeout, _ := time.ParseDuration(os.Getenv("TELEGRAM_TIMEOUT"))
+	cfg := Config{
+		Token:   os.Getenv("TELEGRAM_BOT_TOKEN"),
+		APIURL:  os.Getenv("TELEGRAM_API_URL"),
+		Timeout: timeout,
+	}
+	for _, field := range strings.Split(os.Getenv("TELEGRAM_ADMIN_IDS"), ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return cfg, fmt.Errorf("TELEGRAM_ADMIN_IDS inválido: %q", field)
+		}
+		cfg.AdminIDs = append(cfg.AdminIDs, id)
+	}
+	return cfg, nil
+}
+
+// Bot responde as mensagens do Telegram com o serviço RAG
+type Bot struct {
+	svc        domain.RAGService
+	ingester   *ingest.Ingester // Opcional: habilita o /ingest
+	config     Config
+	httpClient *http.Client
+	chats      sync.Map // ID do chat -> *sync.Mutex; as mensagens de um chat são respondidas em ordem
+}
+
+// New cria o bot. Sem ingester, o /ingest fica indisponível.
+func New(svc domain.RAGService, ingester *ingest.Ingester, cfg Config) (*Bot, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("TELEGRAM_BOT_TOKEN não definida")
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = defaultAPIURL
+	}
+	cfg.APIURL = strings.TrimRight(cfg.APIURL, "/")
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	// O long polling mantém a requisição aberta por pollTimeout segundos
+	client := &http.Client{Timeout: (pollTimeout + 10) * time.Second}
+	return &Bot{svc: svc, ingester: ingester, config: cfg, httpClient: client}, nil
+}
+
+// update é a parte usada de uma atualização recebida do Telegram
+type update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *message `json:"message"`
+}
+
+// message é uma mensagem recebida
+type message struct {
+	Text string `json:"text"`
+	Chat struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	From *struct {
+		ID int64 `json:"id"`
+	} `json:"from"`
+}
+
+// Run recebe as mensagens por long polling até o cancelamento do contexto,
+// quando espera as respostas em andamento terminarem
+func (b *Bot) Run(ctx context.Context) error {
+	// Valida o token antes de começar a receber mensagens
+	var me struct {
+		Username string `json:"username"`
+	}
+	if err := b.call(ctx, "getMe", map[string]any{}, &me); err != nil {
+		return err
+	}
+	log.Printf("Recebendo mensagens como @%s", me.Username)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// As respostas em andamento continuam depois do cancelamento
+	handleCtx := context.WithoutCancel(ctx)
+	var offset int64
+	for {
+		var updates []update
+		err := b.call(ctx, "getUpdates", map[string]any{
+			"offset":          offset,
+			"timeout":         pollTimeout,
+			"allowed_updates": []string{"message"},
+		}, &updates)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			log.Printf("Erro ao receber mensagens do Telegram: %v", err)
+			select {
+			case <-time.After(5 * time.Second):
+			case <-ctx.Done():
+				return nil
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			if u.Message == nil || u.Message.Text == "" {
+				continue
+			}
+			msg := u.Message
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				lock, _ := b.chats.LoadOrStore(msg.Chat.ID, &sync.Mutex{})
+				lock.(*sync.Mutex).Lock()
+				defer lock.(*sync.Mutex).Unlock()
+				b.handle(handleCtx, msg)
+			}()
+		}
+	}
+}
+
+// handle responde uma mensagem: comandos ou perguntas
+func (b *Bot) handle(ctx context.Context, msg *message) {
+	command, args, _ := strings.Cut(strings.TrimSpace(msg.Text), " ")
+	// Em grupos, os comandos podem vir com o nome do bot, como /ingest@meu_bot
+	command, _, _ = strings.Cut(command, "@")
+
+	switch command {
+	case "/start", "/help":
+		b.send(ctx, msg.Chat.ID, "Envie uma pergunta e eu respondo com os documentos da base, citando as fontes.")
+	case "/ingest":
+		b.ingest(ctx, msg, strings.TrimSpace(args))
+	default:
+		if strings.HasPrefix(command, "/") {
+			b.send(ctx, msg.Chat.ID, "Comando desconhecido.")
+			return
+		}
+		b.answer(ctx, msg)
+	}
+}
+
+// answer responde a pergunta com a sessão do chat, mostrando que o bot está
+// digitando enquanto a resposta é gerada
+func (b *Bot) answer(ctx context.Context, msg *message) {
+	ctx, cancel := context.WithTimeout(ctx, b.config.Timeout)
+	defer cancel()
+
+	stopTyping := b.typing(ctx, msg.Chat.ID)
+	req := domain.RAGRequest{
+		Query:     msg.Text,
+		SessionID: "telegram-" + strconv.FormatInt(msg.Chat.ID, 10),
+	}
+	if msg.From != nil {
+		req.UserID = "telegram:" + strconv.FormatInt(msg.From.ID, 10)
+	}
+	resp, err := b.svc.ProcessQuery(ctx, req)
+	stopTyping()
+
+	if err != nil {
+		log.Printf("Erro ao responder pergunta do Telegram: %v", err)
+		b.send(context.WithoutCancel(ctx), msg.Chat.ID, html.EscapeString(errorMessage(err)))
+		return
+	}
+	b.send(ctx, msg.Chat.ID, formatAnswer(resp))
+}
+
+// ingest indexa a página informada, se o autor for administrador
+func (b *Bot) ingest(ctx context.Context, msg *message, target string) {
+	if msg.From == nil || !b.isAdmin(msg.From.ID) {
+		b.send(ctx, msg.Chat.ID, "Apenas administradores podem indexar páginas.")
+		return
+	}
+	if b.ingester == nil {
+		b.send(ctx, msg.Chat.ID, "A indexação não está disponível.")
+		return
+	}
+	if target == "" {
+		b.send(ctx, msg.Chat.ID, "Uso: /ingest &lt;url&gt;")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultIngestTime)
+	defer cancel()
+	stopTyping := b.typing(ctx, msg.Chat.ID)
+	defer stopTyping()
+
+	doc, err := loader.NewHTMLLoader(nil).LoadURL(ctx, target)
+	if err != nil {
+		b.send(ctx, msg.Chat.ID, html.EscapeString("Erro ao baixar a página: "+err.Error()))
+		return
+	}
+	chunks, err := b.ingester.Ingest(ctx, []domain.Document{*doc})
+	if err != nil {
+		log.Printf("Erro ao indexar %s pelo Telegram: %v", target, err)
+		b.send(ctx, msg.Chat.ID, html.EscapeString("Erro ao indexar a página: "+err.Error()))
+		return
+	}
+	log.Printf("Página indexada pelo Telegram: %s (%s), %d chunks", doc.Title, doc.Link, chunks)
+	if chunks == 0 {
+		b.send(ctx, msg.Chat.ID, html.EscapeString(fmt.Sprintf("%s já está indexada, sem alterações.", doc.Title)))
+		return
+	}
+	b.send(ctx, msg.Chat.ID, html.EscapeString(fmt.Sprintf("%s indexada (%d chunks).", doc.Title, chunks)))
+}
+
+// isAdmin indica se o usuário pode usar os comandos de administração
+func (b *Bot) isAdmin(userID int64) bool {
+	for _, id := range b.config.AdminIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// typing envia a ação "digitando" a cada typingInterval, até a função
+// retornada ser chamada
+func (b *Bot) typing(ctx context.Context, chatID int64) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(typingInterval)
+		defer ticker.Stop()
+		for {
+			b.call(ctx, "sendChatAction", map[string]any{"chat_id": chatID, "action": "typing"}, nil)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return cancel
+}
+
+// send envia uma mensagem em HTML ao chat
+func (b *Bot) send(ctx context.Context, chatID int64, text string) {
+	err := b.call(ctx, "sendMessage", map[string]any{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	}, nil)
+	if err != nil {
+		log.Printf("Aviso ao enviar mensagem ao Telegram: %v", err)
+	}
+}
+
+// apiResponse é o envelope das respostas da API do Telegram
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	Description string          `json:"description"`
+}
+
+// call chama um método da API do Telegram e decodifica o resultado em out
+func (b *Bot) call(ctx context.Context, method string, params, out any) error {
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.config.APIURL+"/bot"+b.config.Token+"/"+method, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		// O erro repete o endereço, que contém o token do bot
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("erro na chamada %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var result apiResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 16<<20)).Decode(&result); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta de %s: %w", method, err)
+	}
+	if !result.OK {
+		return fmt.Errorf("erro na chamada %s (status %d): %s", method, resp.StatusCode, result.Description)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(result.Result, out)
+}
+
+// formatAnswer converte a resposta para HTML, com os marcadores de citação,
+// como [1], transformados em links para as fontes citadas
+func formatAnswer(resp *domain.RAGResponse) string {
+	links := map[int]string{}
+	for _, c := range resp.Citations {
+		if c.Link != "" {
+			links[c.Marker] = c.Link
+		}
+	}
+
+	text := html.EscapeString(resp.Answer)
+	text = citationPattern.ReplaceAllStringFunc(text, func(marker string) string {
+		var parts []string
+		for _, field := range strings.Split(strings.Trim(marker, "[]"), ",") {
+			field = strings.TrimSpace(field)
+			ref, _ := strconv.Atoi(field)
+			if link, ok := links[ref]; ok {
+				parts = append(parts, fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(link), field))
+			} else {
+				parts = append(parts, field)
+			}
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	})
+	return truncate(text)
+}
+
+// truncate corta o texto para caber em uma mensagem
+func truncate(text string) string {
+	if utf8.RuneCountInString(text) <= maxMessageSize {
+		return text
+	}
+	// O corte pode quebrar um link; sem ele, o texto é enviado sem formatação
+	plain := tagPattern.ReplaceAllString(text, "")
+	runes := []rune(plain)
+	if len(runes) <= maxMessageSize {
+		return plain
+	}
+	return string(runes[:maxMessageSize-1]) + "…"
+}
+
+// errorMessage é a mensagem exibida ao usuário quando a pergunta falha
+func errorMessage(err error) string {
+	var timeout *domain.TimeoutResult
+	var validationErr *domain.ValidationError
+	var rateLimitErr *domain.RateLimitError
+	switch {
+	case errors.As(err, &timeout):
+		if timeout.PartialAnswer != "" {
+			return timeout.PartialAnswer + "\n\n(resposta interrompida pelo limite de tempo)"
+		}
+		return "A pergunta excedeu o tempo limite."
+	case errors.As(err, &validationErr), errors.As(err, &rateLimitErr),
+		errors.Is(err, domain.ErrPromptTooLarge), errors.Is(err, domain.ErrContentBlocked):
+		return err.Error()
+	case errors.Is(err, domain.ErrLLMUnavailable), errors.Is(err, domain.ErrLLMQuotaExceeded):
+		return domain.ErrLLMUnavailable.Error()
+	default:
+		return "Não foi possível responder à pergunta."
+	}
+}
@@ -0,0 +1,68 @@
+// Package tokenizer conta os tokens de textos enviados aos LLMs, usando as
+// codificações BPE do tiktoken (as mesmas dos modelos da OpenAI).
+package tokenizer
+
+import (
+	"log"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktoken_loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+// DefaultEncoding é a codificação usada para modelos que o tiktoken não
+// conhece (Claude, modelos do Ollama). A contagem é uma aproximação, mas
+// bem mais próxima que a estimativa por caracteres.
+const DefaultEncoding = "cl100k_base"
+
+// charsPerToken é a média aproximada de caracteres por token do Estimator
+const charsPerToken = 4
+
+// Tokenizer conta os tokens de um texto
+type Tokenizer interface {
+	Count(text string) int
+}
+
+// Estimator estima os tokens pelo número de caracteres, sem codificar o
+// texto. É usado quando nenhum tokenizer é configurado.
+type Estimator struct{}
+
+// Count estima a quantidade de tokens do texto
+func (Estimator) Count(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// loadOnce configura o carregamento das codificações embutidas no binário,
+// em vez de baixá-las na primeira contagem
+var loadOnce sync.Once
+
+// New cria o tokenizer do modelo informado. Modelos desconhecidos usam
+// DefaultEncoding; se nem ela puder ser carregada, a contagem passa a ser
+// estimada pelo Estimator.
+func New(model string) Tokenizer {
+	loadOnce.Do(func() {
+		tiktoken.SetBpeLoader(tiktoken_loader.NewOfflineLoader())
+	})
+
+	enc, err := tiktoken.EncodingForModel(model)
+	if err != nil {
+		enc, err = tiktoken.GetEncoding(DefaultEncoding)
+	}
+	if err != nil {
+		log.Printf("Aviso: tokenizer indisponível para o modelo %q, estimando tokens por caracteres: %v", model, err)
+		return Estimator{}
+	}
+	return &tiktokenTokenizer{enc: enc}
+}
+
+// tiktokenTokenizer conta tokens com uma codificação do tiktoken
+type tiktokenTokenizer struct {
+	enc *tiktoken.Tiktoken
+}
+
+// Count retorna a quantidade de tokens do texto. Tokens especiais no texto
+// são contados como texto comum.
+func (t *tiktokenTokenizer) Count(text string) int {
+	return len(t.enc.EncodeOrdinary(text))
+}
@@ -0,0 +1,72 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+)
+
+// ContextWindows associa o nome (ou prefixo do nome) de cada modelo ao
+// tamanho da sua janela de contexto, em tokens
+type ContextWindows map[string]int
+
+// DefaultContextWindows retorna as janelas de contexto dos modelos mais usados
+func DefaultContextWindows() ContextWindows {
+	return ContextWindows{
+		"gpt-3.5-turbo": 16385,
+		"gpt-4":         8192,
+		"gpt-4-turbo":   128000,
+		"gpt-4-0125":    128000,
+		"gpt-4-1106":    128000,
+		"gpt-4o":        128000,
+		"gpt-4.1":       1047576,
+		"claude":        200000,
+		"llama3":        8192,
+		"llama3.1":      128000,
+		"llama3.2":      128000,
+		"mistral":       32768,
+	}
+}
+
+// ContextWindowsFromEnv retorna as janelas padrão, sobrescritas pelas do
+// arquivo JSON indicado em LLM_CONTEXT_WINDOWS_FILE (no formato {"modelo": 32768})
+func ContextWindowsFromEnv() (ContextWindows, error) {
+	windows := DefaultContextWindows()
+
+	path := os.Getenv("LLM_CONTEXT_WINDOWS_FILE")
+	if path == "" {
+		return windows, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler janelas de contexto: %w", err)
+	}
+	var custom ContextWindows
+	if err := json.Unmarshal(raw, &custom); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar janelas de contexto: %w", err)
+	}
+	maps.Copy(windows, custom)
+	return windows, nil
+}
+
+// Lookup busca a janela do modelo pelo nome exato ou, se não houver, pelo
+// maior prefixo cadastrado (por exemplo, "gpt-4o-2024-08-06" usa a de "gpt-4o")
+func (w ContextWindows) Lookup(model string) (int, bool) {
+	if tokens, ok := w[model]; ok {
+		return tokens, true
+	}
+
+	var best string
+	for name := range w {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return 0, false
+	}
+	return w[best], true
+}
@@ -0,0 +1,224 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"math"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// CalculatorToolName é o nome da calculadora exposta ao agente
+const CalculatorToolName = "calculator"
+
+// maxExpressionLength limita o tamanho das expressões avaliadas
+const maxExpressionLength = 1000
+
+// calculatorFunctions são as funções aceitas nas expressões
+var calculatorFunctions = map[string]func(args []float64) (float64, error){
+	"abs":   unary(math.Abs),
+	"sqrt":  unary(math.Sqrt),
+	"floor": unary(math.Floor),
+	"ceil":  unary(math.Ceil),
+	"round": unary(math.Round),
+	"exp":   unary(math.Exp),
+	"ln":    unary(math.Log),
+	"log10": unary(math.Log10),
+	"pow": func(args []float64) (float64, error) {
+		if len(args) != 2 {
+			return 0, fmt.Errorf("pow espera 2 argumentos")
+		}
+		return math.Pow(args[0], args[1]), nil
+	},
+	"min": variadic(math.Min),
+	"max": variadic(math.Max),
+}
+
+// calculatorConstants são as constantes aceitas nas expressões
+var calculatorConstants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// CalculatorTool define a calculadora, que avalia expressões aritméticas sem
+// executar código
+func CalculatorTool() domain.Tool {
+	return domain.Tool{
+		Name:        CalculatorToolName,
+		Description: "Evaluate an arithmetic expression exactly, instead of calculating mentally. Supports + - * / %, parentheses, pi, e and the functions abs, sqrt, floor, ceil, round, exp, ln, log10, pow(x, y), min and max",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"expression": map[string]any{
+					"type":        "string",
+					"description": "Expression to evaluate, like (1250.5 - 980) / 980 * 100",
+				},
+			},
+			"required": []string{"expression"},
+		},
+	}
+}
+
+// HandleCalculator executa a calculadora e devolve o resultado em JSON
+func HandleCalculator(_ context.Context, arguments string) (*domain.ToolResult, error) {
+	var args struct {
+		Expression string `json:"expression"`
+	}
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return nil, fmt.Errorf("erro ao processar argumentos: %w", err)
+	}
+
+	result, err := Evaluate(args.Expression)
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(map[string]any{"expression": args.Expression, "result": result})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao converter para JSON: %w", err)
+	}
+	return &domain.ToolResult{Content: string(content)}, nil
+}
+
+// Evaluate avalia uma expressão aritmética. A expressão é lida com o parser
+// de Go, mas apenas números, operadores aritméticos, parênteses e as
+// funções e constantes da calculadora são aceitos.
+func Evaluate(expression string) (float64, error) {
+	if len(expression) > maxExpressionLength {
+		return 0, fmt.Errorf("expressão maior que %d caracteres", maxExpressionLength)
+	}
+	expr, err := parser.ParseExpr(expression)
+	if err != nil {
+		return 0, fmt.Errorf("expressão inválida: %w", err)
+	}
+	result, err := evaluate(expr)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, fmt.Errorf("resultado indefinido: %s", expression)
+	}
+	return result, nil
+}
+
+// evaluate avalia um nó da expressão
+func evaluate(node ast.Expr) (float64, error) {
+	switch n := node.(type) {
+	case *ast.BasicLit:
+		if n.Kind != token.INT && n.Kind != token.FLOAT {
+			return 0, fmt.Errorf("valor não numérico: %s", n.Value)
+		}
+		value, _ := constant.Float64Val(constant.ToFloat(constant.MakeFromLiteral(n.Value, n.Kind, 0)))
+		return value, nil
+	case *ast.Ident:
+		if value, ok := calculatorConstants[n.Name]; ok {
+			return value, nil
+		}
+		return 0, fmt.Errorf("constante desconhecida: %s", n.Name)
+	case *ast.ParenExpr:
+		return evaluate(n.X)
+	case *ast.UnaryExpr:
+		x, err := evaluate(n.X)
+		if err != nil {
+			return 0, err
+		}
+		switch n.Op {
+		case token.ADD:
+			return x, nil
+		case token.SUB:
+			return -x, nil
+		}
+		return 0, fmt.Errorf("operador não suportado: %s", n.Op)
+	case *ast.BinaryExpr:
+		return evaluateBinary(n)
+	case *ast.CallExpr:
+		return evaluateCall(n)
+	default:
+		return 0, fmt.Errorf("expressão não suportada")
+	}
+}
+
+// evaluateBinary avalia uma operação entre dois operandos
+func evaluateBinary(n *ast.BinaryExpr) (float64, error) {
+	x, err := evaluate(n.X)
+	if err != nil {
+		return 0, err
+	}
+	y, err := evaluate(n.Y)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.Op {
+	case token.ADD:
+		return x + y, nil
+	case token.SUB:
+		return x - y, nil
+	case token.MUL:
+		return x * y, nil
+	case token.QUO:
+		if y == 0 {
+			return 0, fmt.Errorf("divisão por zero")
+		}
+		return x / y, nil
+	case token.REM:
+		if y == 0 {
+			return 0, fmt.Errorf("divisão por zero")
+		}
+		return math.Mod(x, y), nil
+	case token.XOR:
+		// Na precedência de Go, ^ tem a mesma prioridade da soma
+		return 0, fmt.Errorf("use pow(x, y) para potências")
+	}
+	return 0, fmt.Errorf("operador não suportado: %s", n.Op)
+}
+
+// evaluateCall avalia a chamada de uma função da calculadora
+func evaluateCall(n *ast.CallExpr) (float64, error) {
+	name, ok := n.Fun.(*ast.Ident)
+	if !ok {
+		return 0, fmt.Errorf("função não suportada")
+	}
+	fn, ok := calculatorFunctions[name.Name]
+	if !ok {
+		return 0, fmt.Errorf("função desconhecida: %s", name.Name)
+	}
+
+	args := make([]float64, 0, len(n.Args))
+	for _, arg := range n.Args {
+		value, err := evaluate(arg)
+		if err != nil {
+			return 0, err
+		}
+		args = append(args, value)
+	}
+	return fn(args)
+}
+
+// unary adapta uma função de um argumento
+func unary(fn func(float64) float64) func(args []float64) (float64, error) {
+	return func(args []float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("a função espera 1 argumento")
+		}
+		return fn(args[0]), nil
+	}
+}
+
+// variadic adapta uma função de dois argumentos para um ou mais argumentos
+func variadic(fn func(a, b float64) float64) func(args []float64) (float64, error) {
+	return func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("a função espera ao menos 1 argumento")
+		}
+		result := args[0]
+		for _, arg := range args[1:] {
+			result = fn(result, arg)
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       float64
+	}{
+		{expression: "1 + 2 * 3", want: 7},
+		{expression: "(1 + 2) * 3", want: 9},
+		{expression: "7 / 2", want: 3.5},
+		{expression: "7 % 4", want: 3},
+		{expression: "-(2 - 5)", want: 3},
+		{expression: "+4", want: 4},
+		{expression: "(1250.5 - 980) / 980 * 100", want: (1250.5 - 980) / 980 * 100},
+		{expression: "pow(2, 10)", want: 1024},
+		{expression: "sqrt(16) + abs(-2)", want: 6},
+		{expression: "min(3, 1, 2) + max(4)", want: 5},
+		{expression: "round(2.5) + floor(1.9) + ceil(1.1)", want: 6},
+		{expression: "ln(e) + log10(1000)", want: 4},
+		{expression: "2 * pi", want: 2 * math.Pi},
+		{expression: "1e3", want: 1000},
+	}
+	for _, tt := range tests {
+		got, err := Evaluate(tt.expression)
+		if err != nil {
+			t.Errorf("Evaluate(%q): %v", tt.expression, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Evaluate(%q) = %v, esperado %v", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{name: "sintaxe inválida", expression: "1 +"},
+		{name: "divisão por zero", expression: "1 / 0"},
+		{name: "resto por zero", expression: "1 % (2 - 2)"},
+		{name: "potência com ^", expression: "2 ^ 3"},
+		{name: "resultado indefinido", expression: "sqrt(-1)"},
+		{name: "texto", expression: `"1" + 2`},
+		{name: "constante desconhecida", expression: "x + 1"},
+		{name: "função desconhecida", expression: "os.Exit(1)"},
+		{name: "função fora da calculadora", expression: "print(1)"},
+		{name: "aridade", expression: "pow(2)"},
+		{name: "função sem argumentos", expression: "max()"},
+		{name: "operador não aritmético", expression: "1 << 2"},
+		{name: "expressão longa", expression: strings.Repeat("1+", maxExpressionLength) + "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Evaluate(tt.expression); err == nil {
+				t.Errorf("Evaluate(%q) = %v, esperado um erro", tt.expression, got)
+			}
+		})
+	}
+}
+
+func TestHandleCalculator(t *testing.T) {
+	result, err := HandleCalculator(context.Background(), `{"expression": "2 + 2"}`)
+	if err != nil {
+		t.Fatalf("HandleCalculator: %v", err)
+	}
+	if want := `{"expression":"2 + 2","result":4}`; result.Content != want {
+		t.Errorf("HandleCalculator = %s, esperado %s", result.Content, want)
+	}
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// CodeToolName é o nome da ferramenta de execução de código exposta ao agente
+const CodeToolName = "run_code"
+
+// CodeTool define a ferramenta que executa programas com o runner, nas
+// linguagens que ele aceita
+func CodeTool(runner domain.CodeRunner) domain.Tool {
+	return domain.Tool{
+		Name:        CodeToolName,
+		Description: "Run a short self-contained program and return its stdout and stderr. Use it for computations over retrieved data that are too complex for the calculator, like statistics or date arithmetic. The program has no network or file access and must print its result",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"language": map[string]any{
+					"type":        "string",
+					"enum":        runner.Languages(),
+					"description": "Programming language of the code",
+				},
+				"code": map[string]any{
+					"type":        "string",
+					"description": "Complete program source; Go programs must be a single main package using only the standard library",
+				},
+			},
+			"required": []string{"language", "code"},
+		},
+	}
+}
+
+// NewCodeHandler cria o handler que executa os programas com o runner e
+// devolve o resultado em JSON
+func NewCodeHandler(runner domain.CodeRunner) domain.ToolHandler {
+	return func(ctx context.Context, arguments string) (*domain.ToolResult, error) {
+		var args struct {
+			Language string `json:"language"`
+			Code     string `json:"code"`
+		}
+		if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+			return nil, fmt.Errorf("erro ao processar argumentos: %w", err)
+		}
+		if !slices.Contains(runner.Languages(), args.Language) {
+			return nil, fmt.Errorf("linguagem não suportada: %q", args.Language)
+		}
+
+		result, err := runner.Run(ctx, args.Language, args.Code)
+		if err != nil {
+			return nil, err
+		}
+		content, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao converter para JSON: %w", err)
+		}
+		return &domain.ToolResult{Content: string(content)}, nil
+	}
+}
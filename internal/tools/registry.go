@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// registeredTool associa a definição da ferramenta ao seu handler
+type registeredTool struct {
+	tool    domain.Tool
+	handler domain.ToolHandler
+}
+
+// Registry guarda as ferramentas disponíveis para o agente e despacha as
+// chamadas feitas pelo LLM para o handler correspondente
+type Registry struct {
+	mu    sync.RWMutex
+	tools map[string]registeredTool
+	order []string // Ordem de registro, usada ao listar as ferramentas
+}
+
+// NewRegistry cria um registro de ferramentas vazio
+func NewRegistry() *Registry {
+	return &Registry{tools: map[string]registeredTool{}}
+}
+
+// Register adiciona uma ferramenta ao registro. Retorna erro se já existir
+// uma ferramenta com o mesmo nome.
+func (r *Registry) Register(tool domain.Tool, handler domain.ToolHandler) error {
+	if tool.Name == "" {
+		return fmt.Errorf("ferramenta sem nome")
+	}
+	if handler == nil {
+		return fmt.Errorf("ferramenta %q sem handler", tool.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tools[tool.Name]; ok {
+		return fmt.Errorf("ferramenta %q já registrada", tool.Name)
+	}
+	r.tools[tool.Name] = registeredTool{tool: tool, handler: handler}
+	r.order = append(r.order, tool.Name)
+	return nil
+}
+
+// Has indica se existe uma ferramenta registrada com o nome informado
+func (r *Registry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.tools[name]
+	return ok
+}
+
+// Tools retorna as definições das ferramentas, na ordem de registro
+func (r *Registry) Tools() []domain.Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]domain.Tool, 0, len(r.order))
+	for _, name := range r.order {
+		tools = append(tools, r.tools[name].tool)
+	}
+	return tools
+}
+
+// Execute executa a chamada de ferramenta feita pelo LLM
+func (r *Registry) Execute(ctx context.Context, call domain.ToolCall) (*domain.ToolResult, error) {
+	r.mu.RLock()
+	registered, ok := r.tools[call.Name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("ferramenta desconhecida: %s", call.Name)
+	}
+
+	return registered.handler(ctx, call.Arguments)
+}
+
+// RegisterBuiltins registra as ferramentas embutidas: a calculadora e, se
+// runner não for nil, a execução de código
+func RegisterBuiltins(r *Registry, runner domain.CodeRunner) error {
+	if err := r.Register(CalculatorTool(), HandleCalculator); err != nil {
+		return err
+	}
+	if runner == nil {
+		return nil
+	}
+	return r.Register(CodeTool(runner), NewCodeHandler(runner))
+}
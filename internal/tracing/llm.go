@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// LLMClient decora um domain.LLMClient criando um span por chamada
+type LLMClient struct {
+	next domain.LLMClient
+}
+
+// NewLLMClient instrumenta o cliente de LLM informado
+func NewLLMClient(next domain.LLMClient) *LLMClient {
+	return &LLMClient{next: next}
+}
+
+// GenerateResponse implementa domain.LLMClient
+func (c *LLMClient) GenerateResponse(ctx context.Context, messages []domain.Message, tools []domain.Tool) (*domain.Message, error) {
+	ctx, span := start(ctx, "llm.generate",
+		attribute.Int("llm.messages", len(messages)),
+		attribute.Int("llm.tools", len(tools)),
+	)
+	msg, err := c.next.GenerateResponse(ctx, messages, tools)
+	if err == nil {
+		span.SetAttributes(attribute.Int("llm.tool_calls", len(msg.ToolCalls)))
+	}
+	end(span, err)
+	return msg, err
+}
+
+// GenerateResponseStream implementa domain.LLMClient. O span é encerrado no
+// fim do streaming, repassando os pedaços recebidos sem alterá-los.
+func (c *LLMClient) GenerateResponseStream(ctx context.Context, messages []domain.Message, tools []domain.Tool) (<-chan domain.LLMChunk, error) {
+	ctx, span := start(ctx, "llm.stream",
+		attribute.Int("llm.messages", len(messages)),
+		attribute.Int("llm.tools", len(tools)),
+	)
+	chunks, err := c.next.GenerateResponseStream(ctx, messages, tools)
+	if err != nil {
+		end(span, err)
+		return nil, err
+	}
+
+	out := make(chan domain.LLMChunk)
+	go func() {
+		defer close(out)
+		var streamErr error
+		abandoned := false // Quem consome desistiu: o canal original é apenas esvaziado
+		for chunk := range chunks {
+			if chunk.Err != nil && streamErr == nil {
+				streamErr = chunk.Err
+			}
+			if chunk.Done {
+				span.SetAttributes(attribute.Int("llm.tool_calls", len(chunk.ToolCalls)))
+			}
+			if abandoned {
+				continue
+			}
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				abandoned = true
+				if streamErr == nil {
+					streamErr = ctx.Err()
+				}
+			}
+		}
+		end(span, streamErr)
+	}()
+	return out, nil
+}
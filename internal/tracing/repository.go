@@ -0,0 +1,163 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DocumentRepository decora um domain.DocumentRepository criando um span por
+// operação no banco
+type DocumentRepository struct {
+	next domain.DocumentRepository
+}
+
+// NewDocumentRepository instrumenta o repositório informado
+func NewDocumentRepository(next domain.DocumentRepository) *DocumentRepository {
+	return &DocumentRepository{next: next}
+}
+
+// SearchDocuments implementa domain.DocumentRepository
+func (r *DocumentRepository) SearchDocuments(ctx context.Context, query string, filter domain.SearchFilter) ([]domain.Document, error) {
+	ctx, span := start(ctx, "db.search_text", attribute.String("db.category", filter.Category), attribute.StringSlice("db.tags", filter.Tags))
+	docs, err := r.next.SearchDocuments(ctx, query, filter)
+	span.SetAttributes(attribute.Int("db.results", len(docs)))
+	end(span, err)
+	return docs, err
+}
+
+// SearchByVector implementa domain.DocumentRepository
+func (r *DocumentRepository) SearchByVector(ctx context.Context, vector []float32, filter domain.SearchFilter) ([]domain.Document, error) {
+	ctx, span := start(ctx, "db.search_vector",
+		attribute.Int("db.vector_size", len(vector)),
+		attribute.String("db.category", filter.Category),
+		attribute.StringSlice("db.tags", filter.Tags),
+	)
+	docs, err := r.next.SearchByVector(ctx, vector, filter)
+	span.SetAttributes(attribute.Int("db.results", len(docs)))
+	end(span, err)
+	return docs, err
+}
+
+// SearchWithFacets implementa domain.DocumentRepository
+func (r *DocumentRepository) SearchWithFacets(ctx context.Context, query string, filter domain.SearchFilter) (*domain.FacetedSearch, error) {
+	ctx, span := start(ctx, "db.search_facets", attribute.String("db.category", filter.Category), attribute.StringSlice("db.tags", filter.Tags))
+	search, err := r.next.SearchWithFacets(ctx, query, filter)
+	if search != nil {
+		span.SetAttributes(attribute.Int("db.results", len(search.Documents)), attribute.Int("db.total", search.Total))
+	}
+	end(span, err)
+	return search, err
+}
+
+// FindByID implementa domain.DocumentRepository
+func (r *DocumentRepository) FindByID(ctx context.Context, id string) (*domain.Document, error) {
+	ctx, span := start(ctx, "db.find_by_id", attribute.String("db.document_id", id))
+	doc, err := r.next.FindByID(ctx, id)
+	// Documento inexistente é um resultado esperado, não uma falha do banco
+	if errors.Is(err, domain.ErrDocumentNotFound) {
+		span.SetAttributes(attribute.Bool("db.found", false))
+		end(span, nil)
+	} else {
+		end(span, err)
+	}
+	return doc, err
+}
+
+// FindByParentID implementa domain.DocumentRepository
+func (r *DocumentRepository) FindByParentID(ctx context.Context, parentID string) ([]domain.Document, error) {
+	ctx, span := start(ctx, "db.find_by_parent_id", attribute.String("db.parent_id", parentID))
+	docs, err := r.next.FindByParentID(ctx, parentID)
+	span.SetAttributes(attribute.Int("db.results", len(docs)))
+	end(span, err)
+	return docs, err
+}
+
+// FindByContentHash implementa domain.DocumentRepository
+func (r *DocumentRepository) FindByContentHash(ctx context.Context, hash string) (*domain.Document, error) {
+	ctx, span := start(ctx, "db.find_by_content_hash")
+	doc, err := r.next.FindByContentHash(ctx, hash)
+	if errors.Is(err, domain.ErrDocumentNotFound) {
+		span.SetAttributes(attribute.Bool("db.found", false))
+		end(span, nil)
+	} else {
+		end(span, err)
+	}
+	return doc, err
+}
+
+// List implementa domain.DocumentRepository
+func (r *DocumentRepository) List(ctx context.Context, filter domain.DocumentFilter, cursor string, limit int) ([]domain.Document, string, error) {
+	ctx, span := start(ctx, "db.list", attribute.String("db.category", filter.Category), attribute.Int("db.limit", limit))
+	docs, next, err := r.next.List(ctx, filter, cursor, limit)
+	span.SetAttributes(attribute.Int("db.results", len(docs)))
+	end(span, err)
+	return docs, next, err
+}
+
+// InsertDocument implementa domain.DocumentRepository
+func (r *DocumentRepository) InsertDocument(ctx context.Context, doc *domain.Document) error {
+	ctx, span := start(ctx, "db.insert")
+	err := r.next.InsertDocument(ctx, doc)
+	end(span, err)
+	return err
+}
+
+// InsertMany implementa domain.DocumentRepository
+func (r *DocumentRepository) InsertMany(ctx context.Context, docs []*domain.Document) error {
+	ctx, span := start(ctx, "db.insert_many", attribute.Int("db.documents", len(docs)))
+	err := r.next.InsertMany(ctx, docs)
+	end(span, err)
+	return err
+}
+
+// Update implementa domain.DocumentRepository
+func (r *DocumentRepository) Update(ctx context.Context, doc *domain.Document) error {
+	ctx, span := start(ctx, "db.update", attribute.String("db.document_id", doc.ID))
+	err := r.next.Update(ctx, doc)
+	end(span, err)
+	return err
+}
+
+// GetVersionHistory implementa domain.DocumentRepository
+func (r *DocumentRepository) GetVersionHistory(ctx context.Context, id string) ([]domain.DocumentVersion, error) {
+	ctx, span := start(ctx, "db.get_version_history", attribute.String("db.document_id", id))
+	versions, err := r.next.GetVersionHistory(ctx, id)
+	span.SetAttributes(attribute.Int("db.results", len(versions)))
+	end(span, err)
+	return versions, err
+}
+
+// SoftDelete implementa domain.DocumentRepository
+func (r *DocumentRepository) SoftDelete(ctx context.Context, id string) error {
+	ctx, span := start(ctx, "db.soft_delete", attribute.String("db.document_id", id))
+	err := r.next.SoftDelete(ctx, id)
+	end(span, err)
+	return err
+}
+
+// SetEmbedding implementa domain.DocumentRepository
+func (r *DocumentRepository) SetEmbedding(ctx context.Context, id string, embedding []float32) error {
+	ctx, span := start(ctx, "db.set_embedding", attribute.String("db.document_id", id))
+	err := r.next.SetEmbedding(ctx, id, embedding)
+	end(span, err)
+	return err
+}
+
+// Restore implementa domain.DocumentRepository
+func (r *DocumentRepository) Restore(ctx context.Context, id string) error {
+	ctx, span := start(ctx, "db.restore", attribute.String("db.document_id", id))
+	err := r.next.Restore(ctx, id)
+	end(span, err)
+	return err
+}
+
+// HealthCheck implementa domain.DocumentRepository
+func (r *DocumentRepository) HealthCheck(ctx context.Context) error {
+	ctx, span := start(ctx, "db.health_check")
+	err := r.next.HealthCheck(ctx)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultServiceName é o nome do serviço nos traces quando não configurado
+const defaultServiceName = "agentic-rag"
+
+// tracer cria os spans das operações instrumentadas neste pacote
+var tracer = otel.Tracer("github.com/alextavella/agentic-rag/internal/tracing")
+
+// Config contém as configurações de exportação dos traces
+type Config struct {
+	// Endpoint é o endereço do coletor OTLP/HTTP; vazio desabilita a exportação
+	Endpoint    string
+	ServiceName string
+}
+
+// ConfigFromEnv lê a configuração de tracing a partir das variáveis de
+// ambiente padrão do OpenTelemetry
+func ConfigFromEnv() Config {
+	return Config{
+		Endpoint:    os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		ServiceName: os.Getenv("OTEL_SERVICE_NAME"),
+	}
+}
+
+// Setup registra o provedor global de traces e a propagação do contexto
+// (W3C Trace Context e Baggage). Retorna a função que envia os spans
+// pendentes e encerra o provedor. Sem Endpoint, apenas a propagação é
+// configurada e os spans não são exportados.
+func Setup(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// O exportador lê o endpoint e os cabeçalhos das variáveis OTEL_EXPORTER_OTLP_*
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar exportador de traces: %w", err)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// end registra o erro no span, quando houver, e o encerra
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// start inicia um span das operações instrumentadas
+func start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Handler instrumenta o servidor HTTP: extrai o contexto de trace das
+// requisições recebidas e cria um span por requisição, nomeado pelo método
+// e caminho
+func Handler(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http.server",
+		otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+	)
+}
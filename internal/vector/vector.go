@@ -0,0 +1,23 @@
+// Package vector reúne as operações sobre os embeddings usadas pela busca,
+// pelo cache semântico, pela diversificação dos resultados e pela avaliação
+package vector
+
+import "math"
+
+// Cosine calcula a similaridade de cosseno entre dois vetores. Vetores
+// nulos ou de dimensões diferentes têm similaridade zero.
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
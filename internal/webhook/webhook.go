@@ -0,0 +1,283 @@
+// Package webhook entrega os eventos da base e das perguntas (domain.Event)
+// aos endpoints configurados, em requisições POST assinadas com HMAC-SHA256
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+	"github.com/alextavella/agentic-rag/internal/resilience"
+)
+
+// Valores padrão do dispatcher
+const (
+	DefaultMaxAttempts = 5
+	DefaultTimeout     = 10 * time.Second
+	queueSize          = 1000 // Entregas pendentes; acima disso, os eventos são descartados
+	workers            = 4
+	drainTimeout       = 30 * time.Second // Espera pelas entregas pendentes em Close
+)
+
+// Cabeçalhos das requisições de entrega
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderID        = "X-Webhook-ID"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Config contém as configurações dos webhooks
+type Config struct {
+	URLs []string // Endpoints que recebem os eventos; vazio desabilita os webhooks
+	// Secret assina as entregas; sem ele, as requisições vão sem assinatura
+	Secret string
+	// Events restringe os tipos de evento entregues; vazio entrega todos
+	Events      []string
+	MaxAttempts int           // Tentativas de cada entrega, incluindo a primeira (padrão: 5)
+	Timeout     time.Duration // Tempo máximo de cada tentativa (padrão: 10s)
+}
+
+// ConfigFromEnv lê a configuração dos webhooks a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	cfg := Config{
+		URLs:   splitList(os.Getenv("WEBHOOK_URLS")),
+		Secret: os.Getenv("WEBHOOK_SECRET"),
+		Events: splitList(os.Getenv("WEBHOOK_EVENTS")),
+	}
+	cfg.MaxAttempts, _ = strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	cfg.Timeout, _ = time.ParseDuration(os.Getenv("WEBHOOK_TIMEOUT"))
+	return cfg
+}
+
+// splitList separa uma lista de valores separados por vírgula, ignorando os vazios
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// delivery é a entrega de um evento a um endpoint
+type delivery struct {
+	endpoint string
+	event    domain.Event
+	body     []byte
+}
+
+// Dispatcher implementa domain.EventPublisher: os eventos são enfileirados e
+// entregues em segundo plano, com novas tentativas e backoff exponencial
+// enquanto o endpoint responde 429, 5xx ou está inacessível
+type Dispatcher struct {
+	config     Config
+	events     map[string]bool // Tipos entregues; nil entrega todos
+	policy     resilience.RetryPolicy
+	httpClient *http.Client
+
+	queue  chan delivery
+	ctx    context.Context // Cancelado quando Close desiste de esperar as entregas
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// New cria o dispatcher e inicia as entregas. Retorna nil quando nenhum
+// endpoint está configurado.
+func New(cfg Config) (*Dispatcher, error) {
+	if len(cfg.URLs) == 0 {
+		return nil, nil
+	}
+	for _, endpoint := range cfg.URLs {
+		target, err := url.Parse(endpoint)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return nil, fmt.Errorf("url de webhook inválida: %q", endpoint)
+		}
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	d := &Dispatcher{
+		config: cfg,
+		policy: resilience.RetryPolicy{
+			MaxAttempts:    cfg.MaxAttempts,
+			InitialBackoff: time.Second,
+			MaxBackoff:     time.Minute,
+		},
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		queue:      make(chan delivery, queueSize),
+	}
+	if len(cfg.Events) > 0 {
+		d.events = make(map[string]bool)
+		for _, eventType := range cfg.Events {
+			switch eventType {
+			case domain.EventDocumentIngested, domain.EventDocumentFailed, domain.EventQueryAnswered:
+				d.events[eventType] = true
+			default:
+				return nil, fmt.Errorf("evento de webhook desconhecido: %q", eventType)
+			}
+		}
+	}
+
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	for range workers {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for item := range d.queue {
+				d.deliver(item)
+			}
+		}()
+	}
+	return d, nil
+}
+
+// Publish enfileira o evento para cada endpoint, no tenant do contexto. Com
+// a fila cheia ou o dispatcher fechado, o evento é descartado.
+func (d *Dispatcher) Publish(ctx context.Context, eventType string, data any) {
+	if d.events != nil && !d.events[eventType] {
+		return
+	}
+
+	event := domain.Event{
+		ID:       rand.Text(),
+		Type:     eventType,
+		Time:     time.Now().UTC(),
+		TenantID: domain.TenantFromContext(ctx),
+		Data:     data,
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Erro ao codificar evento %s: %v", eventType, err)
+		return
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return
+	}
+	for _, endpoint := range d.config.URLs {
+		select {
+		case d.queue <- delivery{endpoint: endpoint, event: event, body: body}:
+		default:
+			log.Printf("Aviso: fila de webhooks cheia, evento %s %s descartado", eventType, event.ID)
+		}
+	}
+}
+
+// Close para de aceitar eventos e espera as entregas pendentes terminarem,
+// por até 30 segundos; depois disso, as que restam são descartadas
+func (d *Dispatcher) Close() error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(drainTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-timer.C:
+		d.cancel()
+		<-done
+		return fmt.Errorf("entregas de webhook pendentes descartadas após %s", drainTimeout)
+	}
+}
+
+// deliver entrega o evento ao endpoint, repetindo as falhas temporárias
+func (d *Dispatcher) deliver(item delivery) {
+	target, _ := url.Parse(item.endpoint)
+	name := "webhook " + target.Host
+	_, err := resilience.Retry(d.ctx, d.policy, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, d.post(ctx, item)
+	})
+	if err != nil {
+		log.Printf("Erro ao entregar evento %s %s a %s: %v", item.event.Type, item.event.ID, target.Host, err)
+	}
+}
+
+// post envia o evento em uma tentativa. Respostas 429 e 5xx e falhas de
+// conexão são temporárias; as demais respostas fora de 2xx encerram a entrega.
+func (d *Dispatcher) post(ctx context.Context, item delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, item.endpoint, bytes.NewReader(item.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, item.event.Type)
+	req.Header.Set(HeaderID, item.event.ID)
+	if d.config.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(d.config.Secret, time.Now(), item.body))
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// O erro repete o endereço, que pode conter credenciais
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return &domain.TemporaryError{Err: err}
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		seconds, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return &domain.TemporaryError{
+			Err:        fmt.Errorf("endpoint respondeu %d", resp.StatusCode),
+			RetryAfter: time.Duration(seconds) * time.Second,
+		}
+	default:
+		return fmt.Errorf("endpoint respondeu %d", resp.StatusCode)
+	}
+}
+
+// Sign retorna a assinatura de X-Webhook-Signature, no formato
+// "t=<unix>,v1=<hex>": o HMAC-SHA256, com o segredo, de "<unix>.<corpo>".
+// O receptor recalcula o HMAC com o mesmo segredo e recusa as assinaturas
+// antigas, para evitar que uma entrega capturada seja reenviada.
+func Sign(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
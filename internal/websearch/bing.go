@@ -0,0 +1,63 @@
+package websearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// defaultBingURL é o endereço padrão da Bing Web Search API
+const defaultBingURL = "https://api.bing.microsoft.com/v7.0"
+
+// BingClient busca páginas com a Bing Web Search API
+type BingClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	results    int
+}
+
+// NewBingClient cria um cliente da Bing Web Search API
+func NewBingClient(cfg Config) *BingClient {
+	return &BingClient{
+		httpClient: http.DefaultClient,
+		baseURL:    baseURLOr(cfg.BaseURL, defaultBingURL),
+		apiKey:     cfg.APIKey,
+		results:    cfg.Results,
+	}
+}
+
+// bingResponse é a resposta de /search
+type bingResponse struct {
+	WebPages struct {
+		Value []struct {
+			Name    string `json:"name"`
+			URL     string `json:"url"`
+			Snippet string `json:"snippet"`
+		} `json:"value"`
+	} `json:"webPages"`
+}
+
+// Search retorna as páginas encontradas pelo Bing
+func (c *BingClient) Search(ctx context.Context, query string) ([]domain.WebResult, error) {
+	params := url.Values{"q": {query}, "count": {strconv.Itoa(c.results)}}
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição de busca na web: %w", err)
+	}
+	req.Header.Set("Ocp-Apim-Subscription-Key", c.apiKey)
+
+	var resp bingResponse
+	if err := getJSON(ctx, c.httpClient, req, &resp); err != nil {
+		return nil, err
+	}
+	results := make([]domain.WebResult, 0, len(resp.WebPages.Value))
+	for _, page := range resp.WebPages.Value {
+		results = append(results, domain.WebResult{Title: page.Name, URL: page.URL, Snippet: page.Snippet})
+	}
+	return results, nil
+}
@@ -0,0 +1,77 @@
+package websearch
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// defaultBraveURL é o endereço padrão da Brave Search API
+const defaultBraveURL = "https://api.search.brave.com/res/v1"
+
+// tagPattern reconhece as tags de destaque que a Brave inclui nos trechos
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// BraveClient busca páginas com a Brave Search API
+type BraveClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	results    int
+}
+
+// NewBraveClient cria um cliente da Brave Search API
+func NewBraveClient(cfg Config) *BraveClient {
+	return &BraveClient{
+		httpClient: http.DefaultClient,
+		baseURL:    baseURLOr(cfg.BaseURL, defaultBraveURL),
+		apiKey:     cfg.APIKey,
+		results:    cfg.Results,
+	}
+}
+
+// braveResponse é a resposta de /web/search
+type braveResponse struct {
+	Web struct {
+		Results []struct {
+			Title       string `json:"title"`
+			URL         string `json:"url"`
+			Description string `json:"description"`
+		} `json:"results"`
+	} `json:"web"`
+}
+
+// Search retorna as páginas encontradas pela Brave
+func (c *BraveClient) Search(ctx context.Context, query string) ([]domain.WebResult, error) {
+	params := url.Values{"q": {query}, "count": {strconv.Itoa(c.results)}}
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/web/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição de busca na web: %w", err)
+	}
+	req.Header.Set("X-Subscription-Token", c.apiKey)
+
+	var resp braveResponse
+	if err := getJSON(ctx, c.httpClient, req, &resp); err != nil {
+		return nil, err
+	}
+	results := make([]domain.WebResult, 0, len(resp.Web.Results))
+	for _, r := range resp.Web.Results {
+		results = append(results, domain.WebResult{
+			Title:   stripTags(r.Title),
+			URL:     r.URL,
+			Snippet: stripTags(r.Description),
+		})
+	}
+	return results, nil
+}
+
+// stripTags remove as tags HTML do texto e decodifica as entidades
+func stripTags(text string) string {
+	return html.UnescapeString(tagPattern.ReplaceAllString(text, ""))
+}
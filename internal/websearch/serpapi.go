@@ -0,0 +1,69 @@
+package websearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// defaultSerpAPIURL é o endereço padrão da SerpAPI
+const defaultSerpAPIURL = "https://serpapi.com"
+
+// SerpAPIClient busca páginas no Google por meio da SerpAPI
+type SerpAPIClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	results    int
+}
+
+// NewSerpAPIClient cria um cliente da SerpAPI
+func NewSerpAPIClient(cfg Config) *SerpAPIClient {
+	return &SerpAPIClient{
+		httpClient: http.DefaultClient,
+		baseURL:    baseURLOr(cfg.BaseURL, defaultSerpAPIURL),
+		apiKey:     cfg.APIKey,
+		results:    cfg.Results,
+	}
+}
+
+// serpAPIResponse é a resposta de /search.json
+type serpAPIResponse struct {
+	OrganicResults []struct {
+		Title   string `json:"title"`
+		Link    string `json:"link"`
+		Snippet string `json:"snippet"`
+	} `json:"organic_results"`
+}
+
+// Search retorna os resultados orgânicos do Google
+func (c *SerpAPIClient) Search(ctx context.Context, query string) ([]domain.WebResult, error) {
+	params := url.Values{
+		"engine":  {"google"},
+		"q":       {query},
+		"num":     {strconv.Itoa(c.results)},
+		"api_key": {c.apiKey},
+	}
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/search.json?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição de busca na web: %w", err)
+	}
+
+	var resp serpAPIResponse
+	if err := getJSON(ctx, c.httpClient, req, &resp); err != nil {
+		return nil, err
+	}
+	// A SerpAPI pode devolver mais resultados que o pedido
+	results := make([]domain.WebResult, 0, len(resp.OrganicResults))
+	for _, r := range resp.OrganicResults {
+		if len(results) == c.results {
+			break
+		}
+		results = append(results, domain.WebResult{Title: r.Title, URL: r.Link, Snippet: r.Snippet})
+	}
+	return results, nil
+}
@@ -0,0 +1,108 @@
+// Package websearch busca páginas na web com um provedor externo (Brave,
+// Bing ou SerpAPI), para que o agente responda perguntas fora da base
+package websearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/alextavella/agentic-rag/internal/domain"
+)
+
+// Provedores de busca disponíveis
+const (
+	ProviderNone    = ""        // Sem busca na web
+	ProviderBrave   = "brave"   // Brave Search API
+	ProviderBing    = "bing"    // Bing Web Search API
+	ProviderSerpAPI = "serpapi" // SerpAPI (resultados do Google)
+)
+
+// defaultResults é o número padrão de páginas por busca
+const defaultResults = 5
+
+// Config contém as configurações da busca na web
+type Config struct {
+	Provider string
+	APIKey   string
+	BaseURL  string // Endereço da API (padrão: o do provedor)
+	Results  int    // Páginas por busca (padrão: 5)
+}
+
+// ConfigFromEnv lê a configuração da busca na web a partir das variáveis de ambiente
+func ConfigFromEnv() Config {
+	results, _ := strconv.Atoi(os.Getenv("WEB_SEARCH_RESULTS"))
+	return Config{
+		Provider: os.Getenv("WEB_SEARCH_PROVIDER"),
+		APIKey:   os.Getenv("WEB_SEARCH_API_KEY"),
+		BaseURL:  os.Getenv("WEB_SEARCH_URL"),
+		Results:  results,
+	}
+}
+
+// New cria o cliente do provedor configurado. Retorna nil quando a busca na
+// web está desabilitada.
+func New(cfg Config) (domain.WebSearcher, error) {
+	if cfg.Provider == ProviderNone {
+		return nil, nil
+	}
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("WEB_SEARCH_API_KEY não definida")
+	}
+	if cfg.Results <= 0 {
+		cfg.Results = defaultResults
+	}
+
+	switch cfg.Provider {
+	case ProviderBrave:
+		return NewBraveClient(cfg), nil
+	case ProviderBing:
+		return NewBingClient(cfg), nil
+	case ProviderSerpAPI:
+		return NewSerpAPIClient(cfg), nil
+	default:
+		return nil, fmt.Errorf("provedor de busca na web desconhecido: %q", cfg.Provider)
+	}
+}
+
+// getJSON faz a requisição ao provedor e decodifica a resposta em out
+func getJSON(ctx context.Context, client *http.Client, req *http.Request, out any) error {
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// O erro de url.Error repete o endereço, que pode conter a chave da API
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("erro na busca na web: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return fmt.Errorf("erro na busca na web (status %d): %s", resp.StatusCode, raw)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta da busca na web: %w", err)
+	}
+	return nil
+}
+
+// baseURLOr retorna o endereço configurado, sem a barra final, ou o padrão
+// do provedor
+func baseURLOr(configured, fallback string) string {
+	if configured = strings.TrimRight(configured, "/"); configured != "" {
+		return configured
+	}
+	return fallback
+}